@@ -9,8 +9,11 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/clawinfra/evoclaw/internal/types"
 )
 
 const (
@@ -19,6 +22,7 @@ const (
 	colorYellow = "\033[33m"
 	colorBlue   = "\033[34m"
 	colorGray   = "\033[90m"
+	colorDim    = "\033[2m"
 )
 
 type ChatRequest struct {
@@ -32,6 +36,8 @@ type ChatResponse struct {
 	Message   string `json:"message"`
 	Model     string `json:"model"`
 	Timestamp string `json:"timestamp"`
+	// Token counts, cost and latency of the answer
+	types.Usage
 }
 
 type Agent struct {
@@ -68,26 +74,19 @@ func main() {
 	} else if len(agents) == 1 {
 		selectedAgent = agents[0].ID
 	} else {
-		fmt.Println("Available agents:")
-		for i, a := range agents {
-			fmt.Printf("  %d. %s%s%s (%s%s%s)\n",
-				i+1,
-				colorGreen, a.ID, colorReset,
-				colorGray, a.Status, colorReset,
-			)
-		}
+		printAgents(agents)
 		fmt.Print("\nSelect agent (1-", len(agents), "): ")
-		var choice int
+		var choice string
 		_, _ = fmt.Scanln(&choice)
-		if choice < 1 || choice > len(agents) {
+		selectedAgent, err = selectAgent(agents, choice)
+		if err != nil {
 			fmt.Println("Invalid choice")
 			os.Exit(1)
 		}
-		selectedAgent = agents[choice-1].ID
 	}
 
 	fmt.Printf("%s✓ Connected to %s%s%s\n", colorGreen, colorBlue, selectedAgent, colorReset)
-	fmt.Printf("%sType 'exit' or 'quit' to exit%s\n\n", colorGray, colorReset)
+	fmt.Printf("%sType '/switch [agent]' to change agent, 'exit' or 'quit' to exit%s\n\n", colorGray, colorReset)
 
 	// Chat loop
 	scanner := bufio.NewScanner(os.Stdin)
@@ -107,6 +106,30 @@ func main() {
 			break
 		}
 
+		if isSwitchCommand(message) {
+			choice := strings.TrimSpace(strings.TrimPrefix(message, "/switch"))
+			if latest, err := loadAgents(*apiURL); err == nil {
+				agents = latest
+			}
+			if choice == "" {
+				// Re-prompt the selection, as at startup
+				printAgents(agents)
+				fmt.Print("\nSelect agent (1-", len(agents), "): ")
+				if !scanner.Scan() {
+					break
+				}
+				choice = scanner.Text()
+			}
+			next, err := selectAgent(agents, choice)
+			if err != nil {
+				fmt.Printf("%s✗ %v — still talking to %s%s\n\n", colorYellow, err, selectedAgent, colorReset)
+				continue
+			}
+			selectedAgent = next
+			fmt.Printf("%s✓ Connected to %s%s%s\n\n", colorGreen, colorBlue, selectedAgent, colorReset)
+			continue
+		}
+
 		// Send message
 		resp, err := sendMessage(*apiURL, selectedAgent, message)
 		if err != nil {
@@ -115,8 +138,50 @@ func main() {
 		}
 
 		// Display response
-		fmt.Printf("%s%s%s > %s\n\n", colorGreen, selectedAgent, colorReset, resp.Message)
+		fmt.Printf("%s%s%s > %s\n", colorGreen, selectedAgent, colorReset, resp.Message)
+		if resp.Usage != (types.Usage{}) {
+			fmt.Printf("%s%s%s\n", colorDim, resp.Usage, colorReset)
+		}
+		fmt.Println()
+	}
+}
+
+// printAgents lists agents with the 1-based numbers accepted by selectAgent.
+func printAgents(agents []Agent) {
+	fmt.Println("Available agents:")
+	for i, a := range agents {
+		fmt.Printf("  %d. %s%s%s (%s%s%s)\n",
+			i+1,
+			colorGreen, a.ID, colorReset,
+			colorGray, a.Status, colorReset,
+		)
+	}
+}
+
+// isSwitchCommand reports whether a chat line is "/switch" or "/switch <agent>".
+func isSwitchCommand(message string) bool {
+	return message == "/switch" || strings.HasPrefix(message, "/switch ")
+}
+
+// selectAgent resolves a selection to an agent ID. choice is either the
+// 1-based position printed by printAgents or an agent ID.
+func selectAgent(agents []Agent, choice string) (string, error) {
+	choice = strings.TrimSpace(choice)
+	if choice == "" {
+		return "", fmt.Errorf("no agent selected")
+	}
+	for _, a := range agents {
+		if a.ID == choice {
+			return a.ID, nil
+		}
+	}
+	if n, err := strconv.Atoi(choice); err == nil {
+		if n < 1 || n > len(agents) {
+			return "", fmt.Errorf("choice %d out of range 1-%d", n, len(agents))
+		}
+		return agents[n-1].ID, nil
 	}
+	return "", fmt.Errorf("unknown agent %q", choice)
 }
 
 func loadAgents(apiURL string) ([]Agent, error) {
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/types"
+)
+
+func TestSelectAgent(t *testing.T) {
+	agents := []Agent{{ID: "alpha"}, {ID: "beta"}, {ID: "2"}}
+	tests := []struct {
+		choice  string
+		want    string
+		wantErr bool
+	}{
+		{"1", "alpha", false},
+		{" 2 ", "2", false}, // an ID match wins over the position
+		{"beta", "beta", false},
+		{"3", "2", false},
+		{"0", "", true},
+		{"4", "", true},
+		{"gamma", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := selectAgent(agents, tt.choice)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("selectAgent(%q) error = %v, wantErr %v", tt.choice, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("selectAgent(%q) = %q, want %q", tt.choice, got, tt.want)
+		}
+	}
+}
+
+func TestIsSwitchCommand(t *testing.T) {
+	for msg, want := range map[string]bool{
+		"/switch":        true,
+		"/switch beta":   true,
+		"/switchboard":   false,
+		"switch beta":    false,
+		"please /switch": false,
+	} {
+		if got := isSwitchCommand(msg); got != want {
+			t.Errorf("isSwitchCommand(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
+
+func TestSendMessageDecodesUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"agent_id":"alpha","model":"m","tokens_input":100,"tokens_output":50,"cost_usd":0.002,"latency_ms":830}`))
+	}))
+	defer srv.Close()
+
+	resp, err := sendMessage(srv.URL, "alpha", "hi")
+	if err != nil {
+		t.Fatalf("sendMessage: %v", err)
+	}
+	want := types.Usage{TokensInput: 100, TokensOutput: 50, CostUSD: 0.002, LatencyMs: 830}
+	if resp.Usage != want {
+		t.Errorf("usage = %+v, want %+v", resp.Usage, want)
+	}
+}
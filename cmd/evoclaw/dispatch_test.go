@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestRun_DispatchesSubcommands(t *testing.T) {
+	tests := []struct {
+		name       string
+		argv       []string
+		wantCmd    string
+		wantArgs   []string
+		wantConfig string
+	}{
+		{"chain", []string{"evoclaw", "chain", "status", "bsc"}, "chain", []string{"status", "bsc"}, "evoclaw.json"},
+		{"memory", []string{"evoclaw", "memory", "retrieve", "--query", "x"}, "memory", []string{"retrieve", "--query", "x"}, "evoclaw.json"},
+		{"chain with config", []string{"evoclaw", "--config", "c.yaml", "chain", "list"}, "chain", []string{"list"}, "c.yaml"},
+		{"memory with config", []string{"evoclaw", "-config", "m.json", "memory", "status"}, "memory", []string{"status"}, "m.json"},
+		{"schedule", []string{"evoclaw", "schedule", "list"}, "schedule", []string{"list"}, "evoclaw.json"},
+		{"router", []string{"evoclaw", "router"}, "router", []string{}, "evoclaw.json"},
+		{"governance", []string{"evoclaw", "governance", "status"}, "governance", []string{"status"}, "evoclaw.json"},
+		{"validate", []string{"evoclaw", "validate", "x.toml"}, "validate", []string{"x.toml"}, "evoclaw.json"},
+		{"doctor", []string{"evoclaw", "doctor", "--timeout", "1s"}, "doctor", []string{"--timeout", "1s"}, "evoclaw.json"},
+		{"init", []string{"evoclaw", "init", "--dir", "/tmp"}, "init", []string{"--dir", "/tmp"}, "evoclaw.json"},
+		{"migrate", []string{"evoclaw", "migrate", "openclaw"}, "migrate", []string{"openclaw"}, "evoclaw.json"},
+		{"gateway", []string{"evoclaw", "gateway", "status"}, "gateway", []string{"status"}, "evoclaw.json"},
+	}
+
+	origArgs := os.Args
+	origSubcommands := subcommands
+	defer func() {
+		os.Args = origArgs
+		subcommands = origSubcommands
+	}()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCmd, gotConfig string
+			var gotArgs []string
+			subcommands = make(map[string]subcommandHandler, len(origSubcommands))
+			for name := range origSubcommands {
+				name := name
+				subcommands[name] = func(args []string, configPath string) int {
+					gotCmd, gotArgs, gotConfig = name, args, configPath
+					return 42
+				}
+			}
+
+			os.Args = tt.argv
+			if code := run(); code != 42 {
+				t.Fatalf("run() = %d, want handler exit code 42", code)
+			}
+			if gotCmd != tt.wantCmd {
+				t.Errorf("routed to %q, want %q", gotCmd, tt.wantCmd)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("args = %q, want %q", gotArgs, tt.wantArgs)
+			}
+			if gotConfig != tt.wantConfig {
+				t.Errorf("configPath = %q, want %q", gotConfig, tt.wantConfig)
+			}
+		})
+	}
+}
+
+func TestRun_ChainAndMemoryWithoutArgsShowHelp(t *testing.T) {
+	orig := os.Args
+	defer func() { os.Args = orig }()
+
+	for _, cmd := range []string{"chain", "memory"} {
+		os.Args = []string{"evoclaw", cmd}
+		if code := run(); code != 1 {
+			t.Errorf("run(%s) = %d, want 1 (usage)", cmd, code)
+		}
+		os.Args = []string{"evoclaw", cmd, "help"}
+		if code := run(); code != 0 {
+			t.Errorf("run(%s help) = %d, want 0", cmd, code)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/cloudsync"
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// defaultDoctorTimeout bounds each doctor check, so one hanging subsystem
+// cannot block the whole report.
+const defaultDoctorTimeout = 5 * time.Second
+
+// checkStatus is the outcome of a doctor check, ordered by severity.
+type checkStatus int
+
+const (
+	checkPass checkStatus = iota
+	checkSkip             // subsystem not configured
+	checkWarn
+	checkFail
+)
+
+func (s checkStatus) String() string {
+	switch s {
+	case checkPass:
+		return "PASS"
+	case checkSkip:
+		return "SKIP"
+	case checkWarn:
+		return "WARN"
+	default:
+		return "FAIL"
+	}
+}
+
+// doctorCheck is a single diagnostic. Run must honour ctx, which carries
+// the per-check timeout.
+type doctorCheck struct {
+	Name string
+	Run  func(ctx context.Context) (checkStatus, string)
+}
+
+type doctorResult struct {
+	Name   string
+	Status checkStatus
+	Detail string
+}
+
+// runDoctorCommand checks that the config is valid and that every
+// subsystem it points at is reachable, printing a pass/warn/fail report.
+// It returns a non-zero exit code if any check fails.
+//
+// Usage: evoclaw doctor [--timeout 5s] [--no-color] [config-file]
+func runDoctorCommand(args []string, configPath string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", defaultDoctorTimeout, "Timeout for each check")
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	fs.Usage = func() {
+		fmt.Println("Usage: evoclaw doctor [--timeout 5s] [--no-color] [config-file]")
+		fmt.Println()
+		fmt.Println("Diagnose config, providers, MQTT, Turso, data dir and chain RPCs. Exits non-zero on failure.")
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	if fs.NArg() > 0 {
+		configPath = fs.Arg(0)
+	}
+
+	fmt.Printf("Diagnosing %s\n\n", configPath)
+
+	var results []doctorResult
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		results = append(results, doctorResult{Name: "config", Status: checkFail, Detail: err.Error()})
+	} else {
+		results = append(results, checkConfig(cfg))
+		results = append(results, runDoctorChecks(context.Background(), doctorChecks(cfg), *timeout)...)
+	}
+
+	color := !*noColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+	overall := printDoctorReport(os.Stdout, results, color)
+	if overall == checkFail {
+		return 1
+	}
+	return 0
+}
+
+// checkConfig reports the result of config validation.
+func checkConfig(cfg *config.Config) doctorResult {
+	r := doctorResult{Name: "config", Status: checkPass, Detail: "valid"}
+	if err := cfg.Validate(); err != nil {
+		r.Status = checkFail
+		var verrs config.ValidationErrors
+		if errors.As(err, &verrs) {
+			msgs := make([]string, len(verrs))
+			for i, e := range verrs {
+				msgs[i] = e.Field + ": " + e.Message
+			}
+			r.Detail = strings.Join(msgs, "; ")
+		} else {
+			r.Detail = err.Error()
+		}
+	}
+	return r
+}
+
+// doctorChecks builds the connectivity checks for cfg, in report order.
+func doctorChecks(cfg *config.Config) []doctorCheck {
+	var checks []doctorCheck
+
+	names := make([]string, 0, len(cfg.Models.Providers))
+	for name := range cfg.Models.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		name, prov := name, cfg.Models.Providers[name]
+		checks = append(checks, doctorCheck{
+			Name: "provider " + name,
+			Run:  func(ctx context.Context) (checkStatus, string) { return pingProvider(ctx, name, prov) },
+		})
+	}
+	if len(names) == 0 {
+		checks = append(checks, doctorCheck{Name: "providers", Run: skipCheck("none configured")})
+	}
+
+	checks = append(checks,
+		doctorCheck{Name: "mqtt", Run: func(ctx context.Context) (checkStatus, string) { return checkMQTT(ctx, cfg.MQTT) }},
+		doctorCheck{Name: "turso", Run: func(ctx context.Context) (checkStatus, string) { return checkTurso(ctx, cfg.CloudSync) }},
+		doctorCheck{Name: "data dir", Run: func(context.Context) (checkStatus, string) { return checkDataDir(cfg.Server.DataDir) }},
+	)
+
+	chains := enabledChains(cfg)
+	chainNames := make([]string, 0, len(chains))
+	for name := range chains {
+		chainNames = append(chainNames, name)
+	}
+	sort.Strings(chainNames)
+	for _, name := range chainNames {
+		chain := chains[name]
+		checks = append(checks, doctorCheck{
+			Name: "chain " + name,
+			Run:  func(ctx context.Context) (checkStatus, string) { return pingChainRPC(ctx, chain) },
+		})
+	}
+	if len(chainNames) == 0 {
+		checks = append(checks, doctorCheck{Name: "chains", Run: skipCheck("none enabled")})
+	}
+	return checks
+}
+
+func skipCheck(detail string) func(context.Context) (checkStatus, string) {
+	return func(context.Context) (checkStatus, string) { return checkSkip, detail }
+}
+
+// runDoctorChecks runs checks concurrently, each under its own timeout, and
+// returns the results in the order of checks. A check that overruns its
+// timeout fails without waiting for it to return.
+func runDoctorChecks(ctx context.Context, checks []doctorCheck, timeout time.Duration) []doctorResult {
+	results := make([]doctorResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c doctorCheck) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			type outcome struct {
+				status checkStatus
+				detail string
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				s, d := c.Run(ctx)
+				done <- outcome{s, d}
+			}()
+
+			select {
+			case o := <-done:
+				results[i] = doctorResult{Name: c.Name, Status: o.status, Detail: o.detail}
+			case <-ctx.Done():
+				results[i] = doctorResult{Name: c.Name, Status: checkFail, Detail: fmt.Sprintf("timed out after %s", timeout)}
+			}
+		}(i, c)
+	}
+	wg.Wait()
+	return results
+}
+
+// printDoctorReport writes one line per result followed by the overall
+// status, which it returns.
+func printDoctorReport(w io.Writer, results []doctorResult, color bool) checkStatus {
+	paint := func(s checkStatus, text string) string {
+		if !color {
+			return text
+		}
+		codes := map[checkStatus]string{checkPass: "\033[32m", checkSkip: "\033[90m", checkWarn: "\033[33m", checkFail: "\033[31m"}
+		return codes[s] + text + "\033[0m"
+	}
+
+	overall := checkPass
+	var warns, fails int
+	for _, r := range results {
+		switch r.Status {
+		case checkWarn:
+			warns++
+		case checkFail:
+			fails++
+		}
+		if r.Status > overall {
+			overall = r.Status
+		}
+		fmt.Fprintf(w, "  %s  %-20s %s\n", paint(r.Status, fmt.Sprintf("%-4s", r.Status)), r.Name, r.Detail)
+	}
+	if overall == checkSkip {
+		overall = checkPass
+	}
+	fmt.Fprintf(w, "\n%s (%d checks, %d warnings, %d failures)\n", paint(overall, overall.String()), len(results), warns, fails)
+	return overall
+}
+
+// isTerminal reports whether f is a character device, i.e. a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// pingProvider lists the provider's models, which is free and exercises
+// both reachability and credentials.
+func pingProvider(ctx context.Context, name string, prov config.ProviderConfig) (checkStatus, string) {
+	var url string
+	header := http.Header{}
+	switch providerTypeFor(name, prov) {
+	case "anthropic":
+		url = strings.TrimRight(withDefault(prov.BaseURL, "https://api.anthropic.com"), "/") + "/v1/models"
+		header.Set("x-api-key", prov.APIKey)
+		header.Set("anthropic-version", "2023-06-01")
+	case "ollama":
+		url = strings.TrimRight(withDefault(prov.BaseURL, "http://localhost:11434"), "/") + "/api/tags"
+	default:
+		url = strings.TrimRight(withDefault(prov.BaseURL, "https://api.openai.com/v1"), "/") + "/models"
+		if prov.APIKey != "" {
+			header.Set("Authorization", "Bearer "+prov.APIKey)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return checkFail, err.Error()
+	}
+	req.Header = header
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return checkFail, fmt.Sprintf("unreachable: %v", err)
+	}
+	_ = resp.Body.Close()
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return checkFail, fmt.Sprintf("credentials rejected (HTTP %d)", resp.StatusCode)
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return checkPass, fmt.Sprintf("reachable (%s)", elapsed)
+	default:
+		return checkWarn, fmt.Sprintf("reachable but returned HTTP %d (%s)", resp.StatusCode, elapsed)
+	}
+}
+
+func withDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// checkMQTT opens a TCP connection to the broker.
+func checkMQTT(ctx context.Context, cfg config.MQTTConfig) (checkStatus, string) {
+	if cfg.Port <= 0 {
+		return checkSkip, "disabled"
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return checkFail, fmt.Sprintf("broker %s unreachable: %v", addr, err)
+	}
+	_ = conn.Close()
+	return checkPass, "broker " + addr + " reachable"
+}
+
+// checkTurso runs a trivial query against the cloud sync database.
+func checkTurso(ctx context.Context, cfg config.CloudSyncConfig) (checkStatus, string) {
+	if !cfg.Enabled {
+		return checkSkip, "cloud sync disabled"
+	}
+	if cfg.DatabaseURL == "" || cfg.AuthToken == "" {
+		return checkFail, "databaseUrl and authToken are required"
+	}
+	client := cloudsync.NewClient(cfg.DatabaseURL, cfg.AuthToken, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if _, err := client.Query(ctx, "SELECT 1"); err != nil {
+		return checkFail, fmt.Sprintf("query failed: %v", err)
+	}
+	return checkPass, "reachable"
+}
+
+// checkDataDir creates the data directory if needed and writes a probe
+// file to it.
+func checkDataDir(dir string) (checkStatus, string) {
+	if dir == "" {
+		return checkFail, "server.dataDir is not set"
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return checkFail, fmt.Sprintf("create %s: %v", dir, err)
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return checkFail, fmt.Sprintf("%s not writable: %v", dir, err)
+	}
+	name := f.Name()
+	_, werr := f.WriteString("ok")
+	cerr := f.Close()
+	_ = os.Remove(name)
+	if werr != nil || cerr != nil {
+		return checkFail, fmt.Sprintf("%s not writable: %v", dir, errors.Join(werr, cerr))
+	}
+	return checkPass, dir + " writable"
+}
+
+// enabledChains returns the enabled chains with an RPC URL, including the
+// deprecated onchain block.
+func enabledChains(cfg *config.Config) map[string]config.ChainConfig {
+	chains := make(map[string]config.ChainConfig)
+	for name, c := range cfg.Chains {
+		if c.Enabled && c.RPCURL != "" {
+			chains[name] = c
+		}
+	}
+	if oc := cfg.OnChain; oc.Enabled && oc.RPCURL != "" {
+		if _, ok := chains["onchain"]; !ok {
+			chains["onchain"] = config.ChainConfig{Enabled: true, Type: "evm", RPCURL: oc.RPCURL, ChainID: oc.ChainID}
+		}
+	}
+	return chains
+}
+
+// chainHealthMethods is the cheap JSON-RPC call used to probe each chain
+// type. Other types are only checked for an HTTP response.
+var chainHealthMethods = map[string]string{
+	"evm":       "eth_chainId",
+	"solana":    "getHealth",
+	"substrate": "system_health",
+}
+
+// pingChainRPC makes a health call against the chain's RPC endpoint. For
+// EVM chains it also checks the node is on the configured chain ID.
+func pingChainRPC(ctx context.Context, chain config.ChainConfig) (checkStatus, string) {
+	method, ok := chainHealthMethods[chain.Type]
+	if !ok {
+		method = "health"
+	}
+	body, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": []any{}})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, chain.RPCURL, bytes.NewReader(body))
+	if err != nil {
+		return checkFail, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return checkFail, fmt.Sprintf("RPC unreachable: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if !ok {
+		if resp.StatusCode >= 500 {
+			return checkWarn, fmt.Sprintf("RPC returned HTTP %d", resp.StatusCode)
+		}
+		return checkPass, "RPC reachable"
+	}
+	if resp.StatusCode != http.StatusOK {
+		return checkWarn, fmt.Sprintf("RPC returned HTTP %d", resp.StatusCode)
+	}
+
+	var rpc struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpc); err != nil {
+		return checkWarn, fmt.Sprintf("RPC returned invalid JSON: %v", err)
+	}
+	if rpc.Error != nil {
+		return checkWarn, fmt.Sprintf("%s: %s", method, rpc.Error.Message)
+	}
+	if chain.Type == "evm" && chain.ChainID != 0 {
+		var hexID string
+		_ = json.Unmarshal(rpc.Result, &hexID)
+		id, err := strconv.ParseInt(strings.TrimPrefix(hexID, "0x"), 16, 64)
+		if err != nil || id != chain.ChainID {
+			return checkWarn, fmt.Sprintf("RPC reports chain ID %q, config expects %d", hexID, chain.ChainID)
+		}
+	}
+	return checkPass, "RPC reachable"
+}
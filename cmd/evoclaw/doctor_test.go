@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/cloudsync"
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// healthyDoctorConfig points every subsystem at a local mock: an
+// OpenAI-compatible provider, a TCP listener standing in for the MQTT
+// broker, a Turso pipeline endpoint and an EVM JSON-RPC node.
+func healthyDoctorConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	t.Cleanup(provider.Close)
+
+	broker, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = broker.Close() })
+	go func() {
+		for {
+			conn, err := broker.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	turso := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(cloudsync.PipelineResponse{Results: []cloudsync.BatchResult{
+			{Type: "ok", Response: &cloudsync.QueryResponse{Columns: []string{"1"}, Rows: [][]interface{}{{1}}}},
+		}})
+	}))
+	t.Cleanup(turso.Close)
+
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x61"}`))
+	}))
+	t.Cleanup(rpc.Close)
+
+	host, port, _ := net.SplitHostPort(broker.Addr().String())
+	mqttPort, _ := strconv.Atoi(port)
+
+	cfg := config.DefaultConfig()
+	cfg.Server.DataDir = filepath.Join(t.TempDir(), "data")
+	cfg.MQTT = config.MQTTConfig{Host: host, Port: mqttPort}
+	cfg.Models.Providers = map[string]config.ProviderConfig{
+		"local": {BaseURL: provider.URL + "/v1", APIKey: "sk-test", Models: []config.Model{{ID: "m1"}}},
+	}
+	cfg.CloudSync = config.CloudSyncConfig{Enabled: true, DatabaseURL: turso.URL, AuthToken: "token"}
+	cfg.Chains = map[string]config.ChainConfig{
+		"bsc-testnet": {Enabled: true, Type: "evm", RPCURL: rpc.URL, ChainID: 97},
+	}
+	return cfg
+}
+
+func doctorReport(t *testing.T, cfg *config.Config, timeout time.Duration) ([]doctorResult, checkStatus) {
+	t.Helper()
+	results := append([]doctorResult{checkConfig(cfg)}, runDoctorChecks(context.Background(), doctorChecks(cfg), timeout)...)
+	var buf bytes.Buffer
+	overall := printDoctorReport(&buf, results, false)
+	t.Log("\n" + buf.String())
+	return results, overall
+}
+
+func resultFor(t *testing.T, results []doctorResult, name string) doctorResult {
+	t.Helper()
+	for _, r := range results {
+		if r.Name == name {
+			return r
+		}
+	}
+	t.Fatalf("no result for %q", name)
+	return doctorResult{}
+}
+
+func TestDoctor_AllHealthy(t *testing.T) {
+	results, overall := doctorReport(t, healthyDoctorConfig(t), 5*time.Second)
+	if overall != checkPass {
+		t.Errorf("overall = %s, want PASS", overall)
+	}
+	for _, name := range []string{"config", "provider local", "mqtt", "turso", "data dir", "chain bsc-testnet"} {
+		if r := resultFor(t, results, name); r.Status != checkPass {
+			t.Errorf("%s = %s (%s), want PASS", name, r.Status, r.Detail)
+		}
+	}
+}
+
+func TestDoctor_Failures(t *testing.T) {
+	tests := []struct {
+		name   string
+		check  string
+		mutate func(*config.Config)
+		want   checkStatus
+	}{
+		{"bad api key", "provider local", func(c *config.Config) {
+			p := c.Models.Providers["local"]
+			p.APIKey = "wrong"
+			c.Models.Providers["local"] = p
+		}, checkFail},
+		{"broker down", "mqtt", func(c *config.Config) { c.MQTT.Port = closedPort(t) }, checkFail},
+		{"turso down", "turso", func(c *config.Config) {
+			c.CloudSync.DatabaseURL = "http://127.0.0.1:" + strconv.Itoa(closedPort(t))
+		}, checkFail},
+		{"data dir not writable", "data dir", func(c *config.Config) {
+			file := filepath.Join(t.TempDir(), "file")
+			if err := os.WriteFile(file, nil, 0600); err != nil {
+				t.Fatal(err)
+			}
+			c.Server.DataDir = filepath.Join(file, "data")
+		}, checkFail},
+		{"wrong chain", "chain bsc-testnet", func(c *config.Config) {
+			ch := c.Chains["bsc-testnet"]
+			ch.ChainID = 56
+			c.Chains["bsc-testnet"] = ch
+		}, checkWarn},
+		{"invalid config", "config", func(c *config.Config) { c.Server.Port = 70000 }, checkFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := healthyDoctorConfig(t)
+			tt.mutate(cfg)
+			results, overall := doctorReport(t, cfg, 5*time.Second)
+			if r := resultFor(t, results, tt.check); r.Status != tt.want {
+				t.Errorf("%s = %s (%s), want %s", tt.check, r.Status, r.Detail, tt.want)
+			}
+			if overall != tt.want {
+				t.Errorf("overall = %s, want %s", overall, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoctor_DisabledSubsystemsSkip(t *testing.T) {
+	cfg := healthyDoctorConfig(t)
+	cfg.MQTT.Port = 0
+	cfg.CloudSync.Enabled = false
+	cfg.Chains = nil
+
+	results, overall := doctorReport(t, cfg, 5*time.Second)
+	for _, name := range []string{"mqtt", "turso", "chains"} {
+		if r := resultFor(t, results, name); r.Status != checkSkip {
+			t.Errorf("%s = %s, want SKIP", name, r.Status)
+		}
+	}
+	if overall != checkPass {
+		t.Errorf("overall = %s, want PASS", overall)
+	}
+}
+
+func TestRunDoctorChecks_TimeoutDoesNotBlockReport(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	checks := []doctorCheck{
+		{Name: "hangs", Run: func(context.Context) (checkStatus, string) {
+			<-block // ignores its context
+			return checkPass, ""
+		}},
+		{Name: "fast", Run: func(context.Context) (checkStatus, string) { return checkPass, "ok" }},
+	}
+
+	start := time.Now()
+	results := runDoctorChecks(context.Background(), checks, 50*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("report took %s, want the hung check cut off", elapsed)
+	}
+	if results[0].Status != checkFail || !strings.Contains(results[0].Detail, "timed out") {
+		t.Errorf("hung check = %+v, want timeout failure", results[0])
+	}
+	if results[1].Status != checkPass {
+		t.Errorf("fast check = %+v, want PASS", results[1])
+	}
+}
+
+func TestRunDoctorCommand_ExitCode(t *testing.T) {
+	cfg := healthyDoctorConfig(t)
+	path := filepath.Join(t.TempDir(), "evoclaw.json")
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if code := runDoctorCommand([]string{"--no-color", path}, "evoclaw.json"); code != 0 {
+		t.Errorf("healthy: exit %d, want 0", code)
+	}
+
+	cfg.MQTT.Port = closedPort(t)
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if code := runDoctorCommand([]string{"--no-color", path}, "evoclaw.json"); code != 1 {
+		t.Errorf("broker down: exit %d, want 1", code)
+	}
+
+	if code := runDoctorCommand([]string{filepath.Join(t.TempDir(), "missing.json")}, "evoclaw.json"); code != 1 {
+		t.Errorf("missing config: exit %d, want 1", code)
+	}
+}
+
+// closedPort returns a local port with nothing listening on it.
+func closedPort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+	return port
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+const (
+	defaultLogMaxSizeMB  = 100
+	defaultLogMaxBackups = 3
+)
+
+// logLevel is the level of the logger built by newLogger. It is a LevelVar
+// so that a config reload can change it without rebuilding the logger.
+var logLevel = new(slog.LevelVar)
+
+// applyLogLevel sets the logger's level from the (possibly reloaded)
+// server.logLevel.
+func applyLogLevel(cfg *config.Config) {
+	config.RLock()
+	level := cfg.Server.LogLevel
+	config.RUnlock()
+	logLevel.Set(parseLogLevel(level))
+}
+
+// newLogger builds the application logger from server config: text or JSON
+// output, to stdout or to a size-rotated file. The returned closer releases
+// the log file and is nil when logging to stdout.
+func newLogger(cfg config.ServerConfig) (*slog.Logger, io.Closer, error) {
+	var out io.Writer = os.Stdout
+	var closer io.Closer
+
+	if cfg.LogFile != "" {
+		maxSize := cfg.LogMaxSizeMB
+		if maxSize <= 0 {
+			maxSize = defaultLogMaxSizeMB
+		}
+		backups := cfg.LogMaxBackups
+		if backups <= 0 {
+			backups = defaultLogMaxBackups
+		}
+		f, err := openRotatingFile(cfg.LogFile, int64(maxSize)<<20, backups)
+		if err != nil {
+			return nil, nil, err
+		}
+		out, closer = f, f
+	}
+
+	logLevel.Set(parseLogLevel(cfg.LogLevel))
+	opts := &slog.HandlerOptions{Level: logLevel}
+	var handler slog.Handler
+	if cfg.LogFormat == "json" {
+		handler = slog.NewJSONHandler(out, opts)
+	} else {
+		handler = slog.NewTextHandler(out, opts)
+	}
+	return slog.New(handler), closer, nil
+}
+
+// rotatingFile is an io.WriteCloser that renames the file to path.1 (shifting
+// older backups up to path.N) once a write would take it past maxSize.
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts backups, moves the current file to path.1 and reopens path.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("close log file: %w", err)
+	}
+	r.file = nil
+
+	_ = os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
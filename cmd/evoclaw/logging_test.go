@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func TestNewLogger_HandlerType(t *testing.T) {
+	tests := []struct {
+		format string
+		json   bool
+	}{
+		{"", false},
+		{"text", false},
+		{"json", true},
+	}
+	for _, tt := range tests {
+		logger, closer, err := newLogger(config.ServerConfig{LogFormat: tt.format})
+		if err != nil {
+			t.Fatalf("newLogger(%q) error: %v", tt.format, err)
+		}
+		if closer != nil {
+			t.Errorf("newLogger(%q) returned a closer for stdout", tt.format)
+		}
+		_, isJSON := logger.Handler().(*slog.JSONHandler)
+		_, isText := logger.Handler().(*slog.TextHandler)
+		if isJSON != tt.json || isText == tt.json {
+			t.Errorf("newLogger(%q) handler = %T", tt.format, logger.Handler())
+		}
+	}
+}
+
+func TestNewLogger_LevelFromConfig(t *testing.T) {
+	logger, _, err := newLogger(config.ServerConfig{LogLevel: "warn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logger.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("info should be disabled at warn level")
+	}
+	if !logger.Enabled(context.Background(), slog.LevelWarn) {
+		t.Error("warn should be enabled at warn level")
+	}
+}
+
+func TestNewLogger_WritesJSONToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "evoclaw.log")
+	logger, closer, err := newLogger(config.ServerConfig{LogFormat: "json", LogFile: path})
+	if err != nil {
+		t.Fatalf("newLogger error: %v", err)
+	}
+	logger.Info("hello", "agent", "a1")
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("log line is not JSON: %q", data)
+	}
+	if entry["msg"] != "hello" || entry["agent"] != "a1" {
+		t.Errorf("entry = %v", entry)
+	}
+}
+
+func TestRotatingFile_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := openRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"first-1\n", "second\n", "third-\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	read := func(p string) string {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("read %s: %v", p, err)
+		}
+		return string(data)
+	}
+	if got := read(path); got != "fourth\n" {
+		t.Errorf("current = %q", got)
+	}
+	if got := read(path + ".1"); got != "third-\n" {
+		t.Errorf("backup 1 = %q", got)
+	}
+	if got := read(path + ".2"); got != "second\n" {
+		t.Errorf("backup 2 = %q", got)
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("expected at most 2 backups")
+	}
+}
+
+func TestRotatingFile_WriteAfterClose(t *testing.T) {
+	f, err := openRotatingFile(filepath.Join(t.TempDir(), "app.log"), 1<<20, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+	if _, err := f.Write([]byte("x")); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("Write after Close err = %v", err)
+	}
+}
+
+func TestApplyLogLevel_AfterReload(t *testing.T) {
+	logger, _, err := newLogger(config.ServerConfig{LogLevel: "info"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("debug should be disabled at info level")
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Server.LogLevel = "debug"
+	applyLogLevel(cfg)
+	t.Cleanup(func() { logLevel.Set(slog.LevelInfo) })
+
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("debug should be enabled after applying the reloaded level")
+	}
+}
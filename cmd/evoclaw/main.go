@@ -6,11 +6,14 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/fs"
 	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/clawinfra/evoclaw/internal/agents"
 	"github.com/clawinfra/evoclaw/internal/api"
@@ -22,6 +25,8 @@ import (
 	"github.com/clawinfra/evoclaw/internal/onchain"
 	"github.com/clawinfra/evoclaw/internal/orchestrator"
 	"github.com/clawinfra/evoclaw/internal/skills"
+	"github.com/clawinfra/evoclaw/internal/storage"
+	"github.com/clawinfra/evoclaw/internal/tracing"
 )
 
 //go:embed web
@@ -36,18 +41,51 @@ var (
 type App struct {
 	Config        *config.Config
 	Logger        *slog.Logger
+	logCloser     io.Closer
 	Registry      *agents.Registry
 	MemoryStore   *agents.MemoryStore
 	Router        *models.Router
 	EvoEngine     *evolution.Engine
+	Storage       storage.Store
 	ChainRegistry *onchain.ChainRegistry
 	Orchestrator  *orchestrator.Orchestrator
 	SkillRegistry *skills.Registry
+	SkillWatcher  *skills.Watcher
 	APIServer     *api.Server
 	apiContext    context.Context
 	apiCancel     context.CancelFunc
 }
 
+// subcommandHandler runs a subcommand with the arguments that follow it.
+type subcommandHandler func(args []string, configPath string) int
+
+// subcommands routes each subcommand to its handler. Handlers receive only
+// the arguments after the subcommand name. It is a variable so tests can
+// substitute handlers and assert routing.
+var subcommands = map[string]subcommandHandler{
+	"memory":     cli.MemoryCommand,
+	"schedule":   cli.ScheduleCommand,
+	"router":     cli.RouterCommand,
+	"governance": cli.GovernanceCommand,
+	"chain":      cli.ChainCommand,
+	"agent":      cli.AgentCommand,
+	"validate":   runValidateCommand,
+	"doctor":     runDoctorCommand,
+	"init": func(args []string, _ string) int {
+		return cli.InitCommand(args)
+	},
+	"migrate": func(args []string, _ string) int {
+		return cli.MigrateCommand(args)
+	},
+	"gateway": func(args []string, _ string) int {
+		if err := runGatewayCommand(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	},
+}
+
 func main() {
 	os.Exit(run())
 }
@@ -117,36 +155,12 @@ func run() int {
 		case "version", "--version", "-v":
 			printVersion()
 			return 0
-		case "memory":
-			// Memory system operations
-			return cli.MemoryCommand(os.Args[subCmdIdx+1:], configPath)
-		case "schedule":
-			// Scheduler operations
-			return cli.ScheduleCommand(os.Args[subCmdIdx+1:], configPath)
-		case "router":
-			// Intelligent router operations
-			return cli.RouterCommand(os.Args[subCmdIdx+1:], configPath)
-		case "governance":
-			// Self-governance protocol operations
-			return cli.GovernanceCommand(os.Args[subCmdIdx+1:], configPath)
-		case "chain":
-			// Chain operations
-			return cli.ChainCommand(os.Args[subCmdIdx+1:], configPath)
-		case "init":
-			return cli.InitCommand(os.Args[subCmdIdx+1:])
-		case "migrate":
-			// Migration tools
-			return cli.MigrateCommand(os.Args[subCmdIdx+1:])
-		case "gateway":
-			// Gateway daemon management
-			if err := runGatewayCommand(os.Args[subCmdIdx+1:]); err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				return 1
-			}
-			return 0
 		case "start":
 			// Explicit start subcommand — falls through to normal server start below
 		default:
+			if handler, ok := subcommands[subCmd]; ok {
+				return handler(os.Args[subCmdIdx+1:], configPath)
+			}
 			fmt.Fprintf(os.Stderr, "error: unknown command %q\n\n", subCmd)
 			cli.PrintHelp(os.Args[0])
 			return 1
@@ -222,7 +236,7 @@ func printVersion() {
 func setup(configPath string) (*App, error) {
 	app := &App{}
 
-	// Setup logger (initially at Info level)
+	// Bootstrap logger until the config is loaded
 	app.Logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}))
@@ -239,17 +253,27 @@ func setup(configPath string) (*App, error) {
 	}
 	app.Config = cfg
 
-	// Recreate logger with config's log level
-	logLevel := parseLogLevel(cfg.Server.LogLevel)
-	app.Logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: logLevel,
-	}))
+	// Build the configured logger (level, format, optional rotated file)
+	logger, logCloser, err := newLogger(cfg.Server)
+	if err != nil {
+		return nil, fmt.Errorf("create logger: %w", err)
+	}
+	app.Logger, app.logCloser = logger, logCloser
+	app.Logger.Debug("config loaded", "config", cfg.Redacted())
+	if cfg.Server.Offline {
+		app.Logger.Info("offline mode: network features disabled", "disabled", cfg.OfflineDisabled())
+	}
 
-	// Create agent registry
-	registry, err := agents.NewRegistry(cfg.Server.DataDir, app.Logger)
+	// Open persistence backend (file by default, sqlite via server.storage)
+	store, err := storage.Open(cfg.Server.Storage, cfg.Server.DataDir)
 	if err != nil {
-		return nil, fmt.Errorf("create registry: %w", err)
+		return nil, fmt.Errorf("open storage: %w", err)
 	}
+	app.Storage = store
+
+	// Create agent registry
+	registry := agents.NewRegistryWithStore(store, app.Logger)
+	registry.SetAgentDataDirs(cfg.Server.AgentDataDirs)
 	app.Registry = registry
 
 	// Load existing agents
@@ -267,6 +291,14 @@ func setup(configPath string) (*App, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create memory store: %w", err)
 	}
+	if cfg.Server.AgentDataDirs {
+		memoryStore.SetAgentDataDirs(cfg.Server.DataDir)
+	}
+	conv := cfg.Memory.Conversation
+	memoryStore.SetEvictionPolicy(agents.EvictionPolicy{
+		Retention:    time.Duration(conv.RetentionDays) * 24 * time.Hour,
+		MaxSizeBytes: int64(conv.MaxSizeKb) * 1024,
+	})
 	app.MemoryStore = memoryStore
 
 	// Create model router
@@ -277,12 +309,21 @@ func setup(configPath string) (*App, error) {
 		return nil, fmt.Errorf("register providers: %w", err)
 	}
 
-	// Create evolution engine if enabled
+	// Create the evolution engine even when evolution is disabled, so that
+	// enabling it with a config PATCH takes effect without a restart
+	app.EvoEngine = evolution.NewEngineWithStore(store, app.Logger)
+	app.EvoEngine.SetAgentDataDirs(cfg.Server.AgentDataDirs)
+	app.EvoEngine.SetRequireSignedGenomes(cfg.Evolution.RequireSignedGenomes)
+	app.EvoEngine.SetOwnerPublicKey(cfg.Evolution.OwnerPublicKey)
+	app.EvoEngine.SetThrashPolicy(evolution.ThrashPolicyFromConfig(cfg.Evolution))
+	if cfg.Evolution.Seed != 0 {
+		app.EvoEngine.SetSeed(cfg.Evolution.Seed)
+	}
 	if cfg.Evolution.Enabled {
-		app.EvoEngine = evolution.NewEngine(cfg.Server.DataDir, app.Logger)
 		app.Logger.Info("evolution engine enabled",
 			"evalInterval", cfg.Evolution.EvalIntervalSec,
 			"minSamples", cfg.Evolution.MinSamplesForEval,
+			"requireSignedGenomes", cfg.Evolution.RequireSignedGenomes,
 		)
 	}
 
@@ -294,6 +335,41 @@ func setup(configPath string) (*App, error) {
 
 	// Create orchestrator
 	app.Orchestrator = orchestrator.New(cfg, app.Logger)
+	app.Orchestrator.SetStorage(store)
+	SetActiveOrchestrator(app.Orchestrator)
+
+	// Local action log, independent of on-chain reporting
+	actionLog, err := orchestrator.NewActionLog(filepath.Join(cfg.Server.DataDir, "actions"))
+	if err != nil {
+		return nil, fmt.Errorf("create action log: %w", err)
+	}
+	if cfg.Server.AgentDataDirs {
+		if err := actionLog.SetAgentDataDirs(cfg.Server.DataDir); err != nil {
+			app.Logger.Warn("failed to move action logs to agent data dirs", "error", err)
+		}
+	}
+	app.Orchestrator.SetActionLog(actionLog)
+
+	// Dead-letter log for messages and responses that could not be routed
+	deadLetters, err := orchestrator.NewDeadLetterLog(filepath.Join(cfg.Server.DataDir, "deadletter.jsonl"), orchestrator.DefaultDeadLetterSize)
+	if err != nil {
+		return nil, fmt.Errorf("create dead-letter log: %w", err)
+	}
+	app.Orchestrator.SetDeadLetterLog(deadLetters)
+
+	// Optional OpenTelemetry tracing
+	if cfg.Tracing.Enabled {
+		endpoint := cfg.Tracing.Endpoint
+		if endpoint == "" {
+			endpoint = "http://localhost:4318"
+		}
+		service := cfg.Tracing.ServiceName
+		if service == "" {
+			service = "evoclaw"
+		}
+		app.Orchestrator.SetTracer(tracing.New(tracing.NewOTLPExporter(endpoint, service, app.Logger)))
+		app.Logger.Info("tracing enabled", "endpoint", endpoint, "service", service)
+	}
 
 	// Wire evolution engine
 	if app.EvoEngine != nil {
@@ -308,15 +384,16 @@ func setup(configPath string) (*App, error) {
 	if err != nil {
 		app.Logger.Warn("failed to load skills", "error", err)
 	} else {
-		for _, s := range loadedSkills {
-			if regErr := app.SkillRegistry.Register(s); regErr != nil {
-				app.Logger.Warn("failed to register skill", "name", s.Manifest.Name, "error", regErr)
-			}
+		if regErr := app.SkillRegistry.RegisterAll(loadedSkills); regErr != nil {
+			app.Logger.Error("skills rejected", "error", regErr)
 		}
 		if count := app.SkillRegistry.SkillCount(); count > 0 {
 			app.Logger.Info("skills loaded", "count", count)
 		}
 	}
+	if cfg.Server.SkillHotReload {
+		app.SkillWatcher = skills.NewWatcher(skillLoader, app.SkillRegistry, app.Logger)
+	}
 
 	// Register channels
 	if err := registerChannels(app.Orchestrator, cfg, app.Logger); err != nil {
@@ -336,12 +413,32 @@ func setup(configPath string) (*App, error) {
 		app.Logger,
 	)
 
+	// Feedback posted to the API feeds behavioral evolution
+	if app.EvoEngine != nil {
+		app.APIServer.SetEvolution(app.EvoEngine)
+	}
+
+	// Serve HTTPS when a certificate is configured
+	if cfg.Server.TLSCert != "" {
+		if err := app.APIServer.SetTLS(cfg.Server.TLSCert, cfg.Server.TLSKey, cfg.Server.HTTPRedirectPort); err != nil {
+			return nil, fmt.Errorf("api tls: %w", err)
+		}
+		SetActiveAPIServer(app.APIServer)
+	}
+
+	// PATCH /api/config writes the config file and hot-reloads it
+	app.APIServer.SetConfigFile(configPath, func(*config.ReloadResult) {
+		applyLogLevel(cfg)
+	})
+
 	// Embed web dashboard assets
 	webFS, err := fs.Sub(webContent, "web")
 	if err != nil {
 		app.Logger.Warn("web dashboard assets not available", "error", err)
 	} else {
 		app.APIServer.SetWebFS(webFS)
+	}
+	if app.APIServer.DashboardAvailable() {
 		app.Logger.Info("web dashboard embedded")
 	}
 
@@ -483,31 +580,44 @@ func registerProviders(router *models.Router, cfg *config.Config, logger *slog.L
 	for providerName, provCfg := range cfg.Models.Providers {
 		logger.Info("initializing provider", "name", providerName, "models", len(provCfg.Models))
 
-		// Detect provider type from name or baseUrl
-		providerType := providerName
-		if strings.Contains(provCfg.BaseURL, "/anthropic") || strings.HasPrefix(providerName, "anthropic") {
-			providerType = "anthropic"
-		}
-
-		switch providerType {
+		var p orchestrator.ModelProvider
+		switch providerTypeFor(providerName, provCfg) {
 		case "anthropic":
-			p := models.NewAnthropicProvider(provCfg)
-			p.SetName(providerName)
-			router.RegisterProvider(p)
+			ap := models.NewAnthropicProvider(provCfg)
+			ap.SetName(providerName)
+			p = ap
 		case "ollama":
-			router.RegisterProvider(models.NewOllamaProvider(provCfg))
+			p = models.NewOllamaProvider(provCfg)
 		case "openai":
-			router.RegisterProvider(models.NewOpenAIProvider("openai", provCfg))
+			p = models.NewOpenAIProvider("openai", provCfg)
 		case "openrouter":
-			router.RegisterProvider(models.NewOpenAIProvider("openrouter", provCfg))
+			p = models.NewOpenAIProvider("openrouter", provCfg)
 		default:
 			// Assume OpenAI-compatible
-			router.RegisterProvider(models.NewOpenAIProvider(providerName, provCfg))
+			p = models.NewOpenAIProvider(providerName, provCfg)
+		}
+		router.RegisterProvider(models.WithLimits(p, provCfg))
+
+		if provCfg.DiscoverModels {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := router.RefreshModels(ctx, providerName); err != nil {
+				logger.Warn("model discovery failed, using configured models",
+					"provider", providerName, "error", err)
+			}
+			cancel()
 		}
 	}
 	return nil
 }
 
+// providerTypeFor detects the provider implementation from its name or baseUrl
+func providerTypeFor(name string, provCfg config.ProviderConfig) string {
+	if strings.Contains(provCfg.BaseURL, "/anthropic") || strings.HasPrefix(name, "anthropic") {
+		return "anthropic"
+	}
+	return name
+}
+
 // registerChannels registers communication channels to orchestrator
 func registerChannels(orch *orchestrator.Orchestrator, cfg *config.Config, logger *slog.Logger) error {
 	// Telegram
@@ -517,19 +627,61 @@ func registerChannels(orch *orchestrator.Orchestrator, cfg *config.Config, logge
 		orch.RegisterChannel(telegram)
 	}
 
+	// Webhook
+	if wh := cfg.Channels.Webhook; wh != nil && wh.Enabled {
+		logger.Info("enabling webhook channel", "listen", wh.Listen, "outbound", wh.OutboundURL != "")
+		webhook := channels.NewWebhook(channels.WebhookOptions{
+			Listen:        wh.Listen,
+			Path:          wh.Path,
+			ContentPath:   wh.ContentPath,
+			SenderPath:    wh.SenderPath,
+			InboundSecret: wh.InboundSecret,
+			OutboundURL:   wh.OutboundURL,
+			Secret:        wh.Secret,
+		}, logger)
+		orch.RegisterChannel(webhook)
+	}
+
 	// MQTT
 	if cfg.MQTT.Port > 0 {
 		logger.Info("enabling mqtt channel",
 			"host", cfg.MQTT.Host,
 			"port", cfg.MQTT.Port,
+			"topicPrefix", cfg.MQTT.TopicPrefix,
+			"tls", cfg.MQTT.TLS != nil,
 		)
-		mqtt := channels.NewMQTT(
-			cfg.MQTT.Host,
-			cfg.MQTT.Port,
-			cfg.MQTT.Username,
-			cfg.MQTT.Password,
-			logger,
-		)
+		var mqtt *channels.MQTTChannel
+		if t := cfg.MQTT.TLS; t != nil {
+			tlsCfg, err := channels.LoadMQTTTLSConfig(t.CACert, t.ClientCert, t.ClientKey, t.InsecureSkipVerify)
+			if err != nil {
+				return fmt.Errorf("mqtt tls: %w", err)
+			}
+			if t.InsecureSkipVerify {
+				logger.Warn("mqtt tls certificate verification disabled")
+			}
+			mqtt = channels.NewMQTTWithTLS(
+				cfg.MQTT.Host,
+				cfg.MQTT.Port,
+				cfg.MQTT.Username,
+				cfg.MQTT.Password,
+				tlsCfg,
+				logger,
+			)
+		} else {
+			mqtt = channels.NewMQTT(
+				cfg.MQTT.Host,
+				cfg.MQTT.Port,
+				cfg.MQTT.Username,
+				cfg.MQTT.Password,
+				logger,
+			)
+		}
+		if cfg.MQTT.TopicPrefix != "" {
+			mqtt.SetTopicPrefix(cfg.MQTT.TopicPrefix)
+		}
+		if cfg.MQTT.EdgeAgentTTLHours > 0 {
+			mqtt.SetEdgeAgentTTL(time.Duration(cfg.MQTT.EdgeAgentTTLHours) * time.Hour)
+		}
 		orch.RegisterChannel(mqtt)
 	}
 
@@ -564,9 +716,50 @@ func startServices(app *App) error {
 		}
 	}()
 
+	// Evict stale conversation memory in background
+	if app.Config != nil && app.MemoryStore != nil {
+		startMemoryEviction(app)
+	}
+
+	// Reload skills on change in background
+	if app.SkillWatcher != nil {
+		go app.SkillWatcher.Run(app.apiContext)
+	}
+
+	// Keep discovered model lists current
+	if app.Config != nil && app.Router != nil {
+		startModelRefresh(app)
+	}
+
 	return nil
 }
 
+// startModelRefresh periodically re-lists models for providers with
+// discoverModels set, until the API context is cancelled.
+func startModelRefresh(app *App) {
+	for name, provCfg := range app.Config.Models.Providers {
+		if !provCfg.DiscoverModels {
+			continue
+		}
+		interval := time.Duration(provCfg.ModelRefreshSec) * time.Second
+		app.Router.StartModelRefresh(app.apiContext, name, interval)
+	}
+}
+
+// startMemoryEviction runs conversation memory eviction until the API
+// context is cancelled, if a retention or size limit is configured.
+func startMemoryEviction(app *App) {
+	conv := app.Config.Memory.Conversation
+	if conv.RetentionDays <= 0 && conv.MaxSizeKb <= 0 {
+		return
+	}
+	interval := time.Duration(conv.EvictionIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	go app.MemoryStore.RunEviction(app.apiContext, interval)
+}
+
 // printBanner displays the startup banner
 func printBanner(app *App) {
 	fmt.Println()
@@ -578,7 +771,11 @@ func printBanner(app *App) {
 	fmt.Println("  ╚═══════════════════════════════════════╝")
 	fmt.Println()
 	fmt.Printf("  🌐 API: http://localhost:%d\n", app.Config.Server.Port)
-	fmt.Printf("  📊 Dashboard: http://localhost:%d\n", app.Config.Server.Port)
+	if app.APIServer != nil && app.APIServer.DashboardAvailable() {
+		fmt.Printf("  📊 Dashboard: http://localhost:%d\n", app.Config.Server.Port)
+	} else {
+		fmt.Println("  📊 Dashboard: unavailable (web assets not embedded)")
+	}
 	fmt.Printf("  🤖 Agents: %d loaded\n", len(app.Registry.List()))
 	fmt.Printf("  🧠 Models: %d available\n", len(app.Router.ListModels()))
 	fmt.Println()
@@ -607,7 +804,13 @@ func waitForShutdown(app *App) error {
 		app.apiCancel()
 	}
 
-	// Graceful shutdown
+	// Stop orchestrator: in-flight messages finish and their responses are
+	// delivered before its subsystems shut down
+	if err := app.Orchestrator.Stop(); err != nil {
+		return fmt.Errorf("stop orchestrator: %w", err)
+	}
+
+	// Save state once nothing is still updating it
 	app.Logger.Info("saving state...")
 	if err := app.Registry.SaveAll(); err != nil {
 		app.Logger.Error("failed to save agents", "error", err)
@@ -616,11 +819,23 @@ func waitForShutdown(app *App) error {
 		app.Logger.Error("failed to save memory", "error", err)
 	}
 
-	// Stop orchestrator
-	if err := app.Orchestrator.Stop(); err != nil {
-		return fmt.Errorf("stop orchestrator: %w", err)
+	// Flush buffered trace spans
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := app.Orchestrator.Tracer().Shutdown(flushCtx); err != nil {
+		app.Logger.Warn("failed to flush traces", "error", err)
+	}
+	cancel()
+
+	// Close persistence backend (flushes the sqlite database)
+	if closer, ok := app.Storage.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			app.Logger.Error("failed to close storage", "error", err)
+		}
 	}
 
 	app.Logger.Info("EvoClaw stopped")
+	if app.logCloser != nil {
+		_ = app.logCloser.Close()
+	}
 	return nil
 }
@@ -2,11 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/clawinfra/evoclaw/internal/agents"
@@ -14,6 +18,7 @@ import (
 	"github.com/clawinfra/evoclaw/internal/models"
 	"github.com/clawinfra/evoclaw/internal/onchain"
 	"github.com/clawinfra/evoclaw/internal/orchestrator"
+	"github.com/clawinfra/evoclaw/internal/storage"
 )
 
 // --- printVersion ---
@@ -295,6 +300,42 @@ func TestRegisterChannels_MQTT(t *testing.T) {
 	}
 }
 
+func TestRegisterChannels_MQTTTLSBadCert(t *testing.T) {
+	logger := slog.Default()
+	cfg := config.DefaultConfig()
+	cfg.MQTT.Host = "localhost"
+	cfg.MQTT.Port = 8883
+	cfg.MQTT.TLS = &config.MQTTTLSConfig{CACert: filepath.Join(t.TempDir(), "missing-ca.pem")}
+	orch := orchestrator.New(cfg, logger)
+	if err := registerChannels(orch, cfg, logger); err == nil {
+		t.Fatal("expected error for a missing CA certificate")
+	}
+}
+
+func TestRegisterChannels_Webhook(t *testing.T) {
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("X-EvoClaw-Signature")
+	}))
+	defer srv.Close()
+
+	logger := slog.Default()
+	cfg := config.DefaultConfig()
+	cfg.Channels.Webhook = &config.WebhookConfig{Enabled: true, OutboundURL: srv.URL, Secret: "s3cret"}
+	orch := orchestrator.New(cfg, logger)
+	if err := registerChannels(orch, cfg, logger); err != nil {
+		t.Fatal(err)
+	}
+
+	// The webhook accepts broadcasts once an outbound URL is configured.
+	if err := orch.Broadcast(context.Background(), "hello"); err != nil {
+		t.Fatalf("Broadcast: %v", err)
+	}
+	if sig := <-received; !strings.HasPrefix(sig, "sha256=") {
+		t.Errorf("signature header = %q", sig)
+	}
+}
+
 // --- registerProvidersToOrchestrator ---
 
 func TestRegisterProvidersToOrchestrator(t *testing.T) {
@@ -631,6 +672,62 @@ func TestSetup_Valid(t *testing.T) {
 	}
 }
 
+func TestSetup_SQLiteStorage(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "evoclaw.json")
+	cfg := config.DefaultConfig()
+	cfg.Server.DataDir = dir
+	cfg.Server.Storage = "sqlite"
+	cfg.Agents = []config.AgentDef{{ID: "edge-1", Name: "Edge", Type: "monitor", Model: "test/model"}}
+	_ = cfg.Save(cfgPath)
+
+	app, err := setup(cfgPath)
+	if err != nil {
+		t.Fatalf("setup() error: %v", err)
+	}
+	sq, ok := app.Storage.(*storage.SQLiteStore)
+	if !ok {
+		t.Fatalf("Storage = %T, want *storage.SQLiteStore", app.Storage)
+	}
+	defer sq.Close()
+
+	if app.Orchestrator.GetStorage() != app.Storage {
+		t.Error("orchestrator should share the selected storage")
+	}
+	if _, err := sq.Get("agents", "edge-1"); err != nil {
+		t.Errorf("agent should be persisted in sqlite: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "agents", "edge-1.json")); !os.IsNotExist(err) {
+		t.Error("no agent JSON file should be written with sqlite storage")
+	}
+}
+
+func TestSetup_SkillHotReload(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "evoclaw.json")
+	cfg := config.DefaultConfig()
+	cfg.Server.DataDir = dir
+	_ = cfg.Save(cfgPath)
+
+	app, err := setup(cfgPath)
+	if err != nil {
+		t.Fatalf("setup() error: %v", err)
+	}
+	if app.SkillWatcher != nil {
+		t.Error("skill watcher should be off by default")
+	}
+
+	cfg.Server.SkillHotReload = true
+	_ = cfg.Save(cfgPath)
+	app, err = setup(cfgPath)
+	if err != nil {
+		t.Fatalf("setup() error: %v", err)
+	}
+	if app.SkillWatcher == nil {
+		t.Error("skill watcher should be created when skillHotReload is set")
+	}
+}
+
 func TestSetup_InvalidConfig(t *testing.T) {
 	dir := t.TempDir()
 	cfgPath := filepath.Join(dir, "evoclaw.json")
@@ -7,12 +7,14 @@ import (
 	"os"
 	"syscall"
 
+	"github.com/clawinfra/evoclaw/internal/api"
 	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
 )
 
 // getShutdownSignals returns the signals to listen for on Unix systems
 func getShutdownSignals() []os.Signal {
-	return []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR1}
+	return []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2}
 }
 
 // handlePlatformSignal handles platform-specific signals, returns true if should continue loop
@@ -25,6 +27,9 @@ func handlePlatformSignal(sig os.Signal, logger *slog.Logger) bool {
 	case syscall.SIGUSR1:
 		logger.Info("update signal received - self-update not yet implemented")
 		return true // continue loop
+	case syscall.SIGUSR2:
+		toggleSafeMode(logger)
+		return true // continue loop
 	}
 	return false // don't continue, proceed to shutdown
 }
@@ -40,12 +45,45 @@ func SetActiveConfig(cfg *config.Config, path string) {
 	activeConfigPath = path
 }
 
+// activeOrchestrator receives SIGUSR2 safe-mode toggles.
+var activeOrchestrator *orchestrator.Orchestrator
+
+// SetActiveOrchestrator sets the orchestrator SIGUSR2 toggles safe mode on.
+func SetActiveOrchestrator(o *orchestrator.Orchestrator) {
+	activeOrchestrator = o
+}
+
+// activeAPIServer has its TLS certificate reloaded on SIGHUP.
+var activeAPIServer *api.Server
+
+// SetActiveAPIServer sets the API server whose certificate SIGHUP reloads.
+func SetActiveAPIServer(s *api.Server) {
+	activeAPIServer = s
+}
+
+func toggleSafeMode(logger *slog.Logger) {
+	if activeOrchestrator == nil {
+		logger.Error("safe mode toggle: no active orchestrator set")
+		return
+	}
+	on := !activeOrchestrator.SafeMode()
+	logger.Info("SIGUSR2 received, toggling safe mode", "enabled", on)
+	activeOrchestrator.SetSafeMode(on)
+}
+
 func reloadConfig(logger *slog.Logger) {
 	if activeConfig == nil || activeConfigPath == "" {
 		logger.Error("config reload: no active config set")
 		return
 	}
 
+	// Renewed certificates are picked up even if the config is unchanged
+	if activeAPIServer != nil {
+		if err := activeAPIServer.ReloadTLSCert(); err != nil {
+			logger.Error("TLS certificate reload failed", "error", err)
+		}
+	}
+
 	result, err := activeConfig.Reload(activeConfigPath)
 	if err != nil {
 		logger.Error("config reload failed", "error", err)
@@ -53,4 +91,5 @@ func reloadConfig(logger *slog.Logger) {
 	}
 
 	result.LogResult(logger)
+	applyLogLevel(activeConfig)
 }
@@ -7,7 +7,9 @@ import (
 	"os"
 	"syscall"
 
+	"github.com/clawinfra/evoclaw/internal/api"
 	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
 )
 
 // getShutdownSignals returns the signals to listen for on Windows
@@ -23,3 +25,9 @@ func handlePlatformSignal(sig os.Signal, logger *slog.Logger) bool {
 
 // SetActiveConfig is a no-op on Windows (SIGHUP not supported).
 func SetActiveConfig(cfg *config.Config, path string) {}
+
+// SetActiveOrchestrator is a no-op on Windows (SIGUSR2 not supported).
+func SetActiveOrchestrator(o *orchestrator.Orchestrator) {}
+
+// SetActiveAPIServer is a no-op on Windows (SIGHUP not supported).
+func SetActiveAPIServer(s *api.Server) {}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/models"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// providersRequiringKey lists provider types that cannot work without an API key.
+var providersRequiringKey = map[string]bool{
+	"anthropic":  true,
+	"openai":     true,
+	"openrouter": true,
+}
+
+// runValidateCommand loads a config file, validates it and constructs (but
+// does not start) its providers and channels. It prints a pass/fail report
+// and returns a non-zero exit code on failure, for use in CI pipelines.
+//
+// Usage: evoclaw validate [config-file]
+func runValidateCommand(args []string, configPath string) int {
+	for _, arg := range args {
+		if arg == "help" || arg == "--help" || arg == "-h" {
+			fmt.Println("Usage: evoclaw validate [config-file]")
+			fmt.Println()
+			fmt.Println("Check a config file without starting EvoClaw. Exits non-zero on failure.")
+			return 0
+		}
+	}
+	if len(args) > 0 {
+		configPath = args[0]
+	}
+
+	fmt.Printf("Validating %s\n\n", configPath)
+	failed := false
+	pass := func(format string, a ...any) { fmt.Printf("  ✓ "+format+"\n", a...) }
+	fail := func(format string, a ...any) {
+		failed = true
+		fmt.Printf("  ✗ "+format+"\n", a...)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fail("load config: %v", err)
+		fmt.Println("\nFAIL")
+		return 1
+	}
+	pass("config parsed")
+
+	if err := cfg.Validate(); err != nil {
+		var verrs config.ValidationErrors
+		if errors.As(err, &verrs) {
+			for _, e := range verrs {
+				fail("%s: %s", e.Field, e.Message)
+			}
+		} else {
+			fail("%v", err)
+		}
+	} else {
+		pass("config fields valid")
+	}
+
+	// Construct providers and channels with a silent logger to surface
+	// misconfiguration that only shows up at wiring time.
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	names := make([]string, 0, len(cfg.Models.Providers))
+	for name := range cfg.Models.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prov := cfg.Models.Providers[name]
+		providerType := providerTypeFor(name, prov)
+		if providersRequiringKey[providerType] && prov.APIKey == "" {
+			fail("provider %s: apiKey is required for %s providers", name, providerType)
+		}
+		if len(prov.Models) == 0 {
+			fail("provider %s: no models configured", name)
+		}
+	}
+	router := models.NewRouter(logger)
+	if err := registerProviders(router, cfg, logger); err != nil {
+		fail("construct providers: %v", err)
+	} else {
+		pass("%d provider(s) constructed, %d model(s) available", len(cfg.Models.Providers), len(router.ListModels()))
+	}
+
+	orch := orchestrator.New(cfg, logger)
+	if err := registerChannels(orch, cfg, logger); err != nil {
+		fail("construct channels: %v", err)
+	} else {
+		pass("channels constructed")
+	}
+
+	if failed {
+		fmt.Println("\nFAIL")
+		return 1
+	}
+	fmt.Println("\nPASS")
+	return 0
+}
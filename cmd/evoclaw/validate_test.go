@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func TestRunValidateCommand_Good(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "evoclaw.yaml")
+	cfg := config.DefaultConfig()
+	cfg.Server.DataDir = filepath.Join(dir, "data")
+	cfg.Models.Providers = map[string]config.ProviderConfig{
+		"ollama": {BaseURL: "http://localhost:11434", Models: []config.Model{{ID: "llama3"}}},
+	}
+	cfg.Agents = []config.AgentDef{{ID: "a1", Model: "ollama/llama3"}}
+	if err := cfg.Save(cfgPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := runValidateCommand(nil, cfgPath); code != 0 {
+		t.Errorf("runValidateCommand() = %d, want 0", code)
+	}
+}
+
+func TestRunValidateCommand_InvalidFields(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "evoclaw.json")
+	cfg := config.DefaultConfig()
+	cfg.Server.DataDir = filepath.Join(dir, "data")
+	cfg.Server.Port = 70000
+	cfg.Agents = []config.AgentDef{{ID: "dup"}, {ID: "dup"}}
+	if err := cfg.Save(cfgPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := runValidateCommand(nil, cfgPath); code != 1 {
+		t.Errorf("runValidateCommand() = %d, want 1", code)
+	}
+}
+
+func TestRunValidateCommand_MissingAPIKey(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "evoclaw.json")
+	cfg := config.DefaultConfig()
+	cfg.Server.DataDir = filepath.Join(dir, "data")
+	cfg.Models.Providers = map[string]config.ProviderConfig{
+		"anthropic": {Models: []config.Model{{ID: "claude-sonnet"}}},
+	}
+	if err := cfg.Save(cfgPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := runValidateCommand(nil, cfgPath); code != 1 {
+		t.Errorf("runValidateCommand() = %d, want 1", code)
+	}
+}
+
+func TestRunValidateCommand_Unparseable(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "evoclaw.json")
+	_ = os.WriteFile(cfgPath, []byte("{not json"), 0644)
+
+	if code := runValidateCommand(nil, cfgPath); code != 1 {
+		t.Errorf("runValidateCommand() = %d, want 1", code)
+	}
+	if code := runValidateCommand(nil, filepath.Join(dir, "missing.json")); code != 1 {
+		t.Errorf("runValidateCommand(missing) = %d, want 1", code)
+	}
+}
+
+func TestRun_ValidateSubcmd(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "evoclaw.json")
+	cfg := config.DefaultConfig()
+	cfg.Server.DataDir = filepath.Join(dir, "data")
+	_ = cfg.Save(cfgPath)
+	orig := os.Args
+	defer func() { os.Args = orig }()
+
+	os.Args = []string{"evoclaw", "--config", cfgPath, "validate"}
+	if code := run(); code != 0 {
+		t.Errorf("run() = %d, want 0", code)
+	}
+
+	os.Args = []string{"evoclaw", "validate", filepath.Join(dir, "missing.toml")}
+	if code := run(); code != 1 {
+		t.Errorf("run() = %d, want 1", code)
+	}
+}
@@ -10,6 +10,7 @@
 // The TUI provides:
 //   - Split-pane layout: agent sidebar + chat panel + input
 //   - Real-time agent status (online/idle/evolving, message count, cost)
+//   - /metrics pane with live agent metrics and a fitness sparkline
 //   - Full chat with any registered agent
 //   - Works over SSH, tmux, screen — no GUI needed
 package main
@@ -25,6 +26,7 @@ import (
 
 	"github.com/clawinfra/evoclaw/internal/channels"
 	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
 	"github.com/clawinfra/evoclaw/internal/models"
 	"github.com/clawinfra/evoclaw/internal/orchestrator"
 )
@@ -58,8 +60,16 @@ func main() {
 	// Register model providers from config
 	registerProviders(orch, cfg, logger)
 
+	// Evolution engine feeds the fitness chart in the metrics pane
+	if cfg.Evolution.Enabled {
+		engine := evolution.NewEngine(cfg.Server.DataDir, logger)
+		engine.SetAgentDataDirs(cfg.Server.AgentDataDirs)
+		orch.SetEvolutionEngine(engine)
+	}
+
 	// Create TUI channel — pass the orchestrator's ListAgents for sidebar updates
 	tuiCh := channels.NewTUI(logger, orch.ListAgents)
+	tuiCh.SetFitnessSource(orch.FitnessHistory)
 	orch.RegisterChannel(tuiCh)
 
 	// Start orchestrator (which starts the TUI channel)
@@ -83,16 +93,18 @@ func main() {
 // registerProviders sets up LLM providers from config
 func registerProviders(orch *orchestrator.Orchestrator, cfg *config.Config, logger *slog.Logger) {
 	for name, provCfg := range cfg.Models.Providers {
+		var p orchestrator.ModelProvider
 		switch name {
 		case "anthropic":
-			orch.RegisterProvider(models.NewAnthropicProvider(provCfg))
+			p = models.NewAnthropicProvider(provCfg)
 		case "openai":
-			orch.RegisterProvider(models.NewOpenAIProvider(name, provCfg))
+			p = models.NewOpenAIProvider(name, provCfg)
 		case "ollama":
-			orch.RegisterProvider(models.NewOllamaProvider(provCfg))
+			p = models.NewOllamaProvider(provCfg)
 		default:
 			// Try as OpenAI-compatible (covers z.ai, nvidia-nim, etc.)
-			orch.RegisterProvider(models.NewOpenAIProvider(name, provCfg))
+			p = models.NewOpenAIProvider(name, provCfg)
 		}
+		orch.RegisterProvider(models.WithLimits(p, provCfg))
 	}
 }
@@ -1,12 +1,14 @@
 use std::time::Duration;
+use tokio::sync::mpsc;
 use tracing::{error, info, warn};
 
+use crate::cancel::Cancellations;
 use crate::config::Config;
 use crate::evolution::EvolutionTracker;
 use crate::llm::LLMClient;
 use crate::metrics::Metrics;
 use crate::monitor::Monitor;
-use crate::mqtt::{parse_command, MqttClient};
+use crate::mqtt::{parse_command, AgentCommand, MqttClient};
 use crate::paper::PaperTrader;
 use crate::risk::RiskManager;
 use crate::skills::registry::SkillRegistry;
@@ -28,6 +30,7 @@ pub struct EdgeAgent {
     pub paper_trader: Option<PaperTrader>,
     pub risk_manager: Option<RiskManager>,
     pub skill_registry: SkillRegistry,
+    pub cancellations: Cancellations,
 }
 
 impl EdgeAgent {
@@ -119,6 +122,7 @@ impl EdgeAgent {
             paper_trader,
             risk_manager,
             skill_registry,
+            cancellations: Cancellations::new(),
         };
 
         Ok((agent, eventloop))
@@ -166,7 +170,7 @@ impl EdgeAgent {
     /// Main event loop
     pub async fn run(
         mut self,
-        mut eventloop: rumqttc::EventLoop,
+        eventloop: rumqttc::EventLoop,
     ) -> Result<(), Box<dyn std::error::Error>> {
         self.subscribe().await?;
         self.advertise_capabilities().await?;
@@ -177,28 +181,15 @@ impl EdgeAgent {
         // Skill tick timer (check every 5 seconds)
         let mut skill_tick_interval = tokio::time::interval(Duration::from_secs(5));
 
+        // Read MQTT on its own task so a cancel can arrive while a command
+        // is still being handled.
+        let (cmd_tx, mut cmd_rx) = mpsc::channel(64);
+        tokio::spawn(read_commands(eventloop, cmd_tx, self.cancellations.clone()));
+
         loop {
             tokio::select! {
-                // Process MQTT events
-                event = eventloop.poll() => {
-                    match event {
-                        Ok(rumqttc::Event::Incoming(rumqttc::Packet::Publish(publish))) => {
-                            let topic = publish.topic.clone();
-                            match parse_command(&publish.payload) {
-                                Ok(cmd) => self.handle_command(cmd).await,
-                                Err(e) => warn!(topic = %topic, error = %e, "failed to parse command"),
-                            }
-                        }
-                        Ok(rumqttc::Event::Incoming(rumqttc::Packet::ConnAck(_))) => {
-                            info!("connected to MQTT broker");
-                        }
-                        Err(e) => {
-                            error!(error = %e, "MQTT error, reconnecting...");
-                            tokio::time::sleep(Duration::from_secs(5)).await;
-                        }
-                        _ => {}
-                    }
-                }
+                // Handle commands in arrival order
+                Some(cmd) = cmd_rx.recv() => self.handle_command(cmd).await,
                 // Send heartbeat
                 _ = heartbeat_interval.tick() => {
                     if let Err(e) = self.heartbeat().await {
@@ -220,6 +211,41 @@ impl EdgeAgent {
     }
 }
 
+/// Poll the MQTT event loop and forward parsed commands to the agent. A
+/// `cancel` is also recorded in `cancellations` straight away, before it
+/// queues behind the command it cancels.
+async fn read_commands(
+    mut eventloop: rumqttc::EventLoop,
+    commands: mpsc::Sender<AgentCommand>,
+    cancellations: Cancellations,
+) {
+    loop {
+        match eventloop.poll().await {
+            Ok(rumqttc::Event::Incoming(rumqttc::Packet::Publish(publish))) => {
+                match parse_command(&publish.payload) {
+                    Ok(cmd) => {
+                        if cmd.command == "cancel" {
+                            cancellations.cancel(&cmd.request_id);
+                        }
+                        if commands.send(cmd).await.is_err() {
+                            return;
+                        }
+                    }
+                    Err(e) => warn!(topic = %publish.topic, error = %e, "failed to parse command"),
+                }
+            }
+            Ok(rumqttc::Event::Incoming(rumqttc::Packet::ConnAck(_))) => {
+                info!("connected to MQTT broker");
+            }
+            Err(e) => {
+                error!(error = %e, "MQTT error, reconnecting...");
+                tokio::time::sleep(Duration::from_secs(5)).await;
+            }
+            _ => {}
+        }
+    }
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
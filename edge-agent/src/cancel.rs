@@ -0,0 +1,91 @@
+use std::collections::HashSet;
+use std::sync::{Arc, Mutex};
+
+use tokio::sync::Notify;
+
+/// Request IDs the orchestrator has cancelled.
+///
+/// The MQTT reader task records a `cancel` command here as soon as it
+/// arrives, so a prompt that is still running can notice it and stop.
+#[derive(Clone, Default)]
+pub struct Cancellations {
+    ids: Arc<Mutex<HashSet<String>>>,
+    notify: Arc<Notify>,
+}
+
+impl Cancellations {
+    pub fn new() -> Self {
+        Self::default()
+    }
+
+    /// Mark a request as cancelled and wake anything waiting on it.
+    pub fn cancel(&self, request_id: &str) {
+        self.ids.lock().unwrap().insert(request_id.to_string());
+        self.notify.notify_waiters();
+    }
+
+    /// Whether the request has been cancelled.
+    pub fn is_cancelled(&self, request_id: &str) -> bool {
+        self.ids.lock().unwrap().contains(request_id)
+    }
+
+    /// Forget a request once it is no longer in flight.
+    pub fn clear(&self, request_id: &str) {
+        self.ids.lock().unwrap().remove(request_id);
+    }
+
+    /// Resolve once the request is cancelled.
+    pub async fn cancelled(&self, request_id: &str) {
+        loop {
+            let notified = self.notify.notified();
+            tokio::pin!(notified);
+            notified.as_mut().enable();
+            if self.is_cancelled(request_id) {
+                return;
+            }
+            notified.await;
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use std::time::Duration;
+
+    #[test]
+    fn test_cancel_and_clear() {
+        let c = Cancellations::new();
+        assert!(!c.is_cancelled("req-1"));
+        c.cancel("req-1");
+        assert!(c.is_cancelled("req-1"));
+        assert!(!c.is_cancelled("req-2"));
+        c.clear("req-1");
+        assert!(!c.is_cancelled("req-1"));
+    }
+
+    #[tokio::test]
+    async fn test_cancelled_wakes_waiter() {
+        let c = Cancellations::new();
+        let waiter = c.clone();
+        let handle = tokio::spawn(async move { waiter.cancelled("req-1").await });
+
+        tokio::time::sleep(Duration::from_millis(10)).await;
+        c.cancel("req-2");
+        c.cancel("req-1");
+
+        tokio::time::timeout(Duration::from_secs(1), handle)
+            .await
+            .expect("waiter not woken")
+            .unwrap();
+    }
+
+    #[tokio::test]
+    async fn test_cancelled_returns_when_already_cancelled() {
+        let c = Cancellations::new();
+        c.cancel("req-1");
+        tokio::time::timeout(Duration::from_secs(1), c.cancelled("req-1"))
+            .await
+            .expect("already-cancelled request should resolve at once");
+    }
+}
@@ -35,6 +35,7 @@ impl EdgeAgent {
             "risk" => self.handle_risk(&cmd).await,
             "skill" => self.handle_skill(&cmd).await,
             "shutdown" => self.handle_shutdown(&cmd).await,
+            "cancel" => self.handle_cancel(&cmd).await,
             _ => {
                 warn!(command = %cmd.command, "unknown command");
                 Err(format!("unknown command: {}", cmd.command).into())
@@ -827,6 +828,15 @@ impl EdgeAgent {
         std::process::exit(0);
     }
 
+    // The reader task flagged the request as soon as the cancel arrived, so
+    // a prompt running for it has already stopped. Commands are handled in
+    // order, so that prompt is finished by now and the flag can go.
+    async fn handle_cancel(&self, cmd: &AgentCommand) -> CommandResult {
+        info!(request_id = %cmd.request_id, "request cancelled by orchestrator");
+        self.cancellations.clear(&cmd.request_id);
+        Ok(serde_json::json!({"cancelled": cmd.request_id}))
+    }
+
     // Handle prompt command from orchestrator with tool execution support
     // This runs the LLM locally on the edge agent and executes tool calls
     async fn handle_prompt(&mut self, cmd: &AgentCommand) -> CommandResult {
@@ -870,6 +880,7 @@ impl EdgeAgent {
         };
 
         let start = std::time::Instant::now();
+        let cancellations = self.cancellations.clone();
 
         // Build system prompt with tool definitions if tools are enabled
         let full_system_prompt = if enable_tools {
@@ -903,11 +914,14 @@ impl EdgeAgent {
                 .unwrap_or_else(|| "You are a helpful assistant.".to_string())
         };
 
-        // First LLM call
-        match llm_client
-            .complete(prompt, Some(&full_system_prompt), max_tokens)
-            .await
-        {
+        // First LLM call, abandoned if the orchestrator cancels the request
+        let first = tokio::select! {
+            r = llm_client.complete(prompt, Some(&full_system_prompt), max_tokens) => r,
+            _ = cancellations.cancelled(&cmd.request_id) => {
+                return Err(cancelled_error(&cmd.request_id));
+            }
+        };
+        match first {
             Ok(response) => {
                 let mut final_content = response.content.clone();
                 let mut tool_results: Vec<serde_json::Value> = Vec::new();
@@ -916,6 +930,9 @@ impl EdgeAgent {
 
                 // Check for tool calls in the response
                 if enable_tools && response.content.contains("TOOL_CALL:") {
+                    if cancellations.is_cancelled(&cmd.request_id) {
+                        return Err(cancelled_error(&cmd.request_id));
+                    }
                     info!("LLM requested tool execution");
 
                     // Extract and execute tool calls
@@ -960,10 +977,13 @@ impl EdgeAgent {
                             tool_results_str
                         );
 
-                        match llm_client
-                            .complete(&followup_prompt, Some(&full_system_prompt), max_tokens)
-                            .await
-                        {
+                        let followup = tokio::select! {
+                            r = llm_client.complete(&followup_prompt, Some(&full_system_prompt), max_tokens) => r,
+                            _ = cancellations.cancelled(&cmd.request_id) => {
+                                return Err(cancelled_error(&cmd.request_id));
+                            }
+                        };
+                        match followup {
                             Ok(followup_response) => {
                                 final_content = followup_response.content;
                                 total_input_tokens += followup_response.input_tokens;
@@ -1030,6 +1050,10 @@ impl EdgeAgent {
     }
 }
 
+fn cancelled_error(request_id: &str) -> Box<dyn std::error::Error> {
+    format!("request {} cancelled", request_id).into()
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -1583,6 +1607,23 @@ mod tests {
         assert_eq!(agent.metrics.actions_total, 1);
     }
 
+    #[tokio::test]
+    async fn test_handle_cancel_clears_request() {
+        let config = create_test_agent_config("trader");
+        let (mut agent, _) = EdgeAgent::new(config).await.unwrap();
+        agent.cancellations.cancel("req-prompt");
+
+        let cmd = AgentCommand {
+            command: "cancel".to_string(),
+            payload: serde_json::json!({}),
+            request_id: "req-prompt".to_string(),
+        };
+        agent.handle_command(cmd).await;
+
+        assert!(!agent.cancellations.is_cancelled("req-prompt"));
+        assert_eq!(agent.metrics.actions_success, 1);
+    }
+
     #[tokio::test]
     async fn test_handle_evolution_no_action() {
         let config = create_test_agent_config("trader");
@@ -45,12 +45,20 @@ pub struct RiskConfig {
     pub consecutive_loss_limit: u32,
 }
 
+/// Topic namespace used when `topic_prefix` is not set, matching the
+/// orchestrator's default.
+pub const DEFAULT_TOPIC_PREFIX: &str = "evoclaw";
+
 #[derive(Debug, Clone, Serialize, Deserialize)]
 pub struct MqttConfig {
     pub broker: String,
     pub port: u16,
     #[serde(default = "default_keep_alive")]
     pub keep_alive_secs: u64,
+    /// Namespace for every topic; must match the orchestrator's
+    /// `mqtt.topicPrefix` when brokers are shared.
+    #[serde(default = "default_topic_prefix")]
+    pub topic_prefix: String,
 }
 
 #[derive(Debug, Clone, Serialize, Deserialize)]
@@ -171,6 +179,10 @@ fn default_keep_alive() -> u64 {
     30
 }
 
+fn default_topic_prefix() -> String {
+    DEFAULT_TOPIC_PREFIX.to_string()
+}
+
 impl Default for RiskConfig {
     fn default() -> Self {
         Self {
@@ -224,6 +236,7 @@ impl Config {
                 broker: "localhost".to_string(),
                 port: 1883,
                 keep_alive_secs: 30,
+                topic_prefix: default_topic_prefix(),
             },
             orchestrator: OrchestratorConfig {
                 url: "http://localhost:8420".to_string(),
@@ -366,6 +379,29 @@ url = "http://localhost:8420"
         let config = Config::from_file(temp_file.path()).unwrap();
         assert_eq!(config.agent_id, "minimal_agent");
         assert_eq!(config.mqtt.keep_alive_secs, 30); // Default value
+        assert_eq!(config.mqtt.topic_prefix, "evoclaw");
+    }
+
+    #[test]
+    fn test_config_topic_prefix() {
+        let toml_content = r#"
+agent_id = "tenant_agent"
+agent_type = "monitor"
+
+[mqtt]
+broker = "localhost"
+port = 1883
+topic_prefix = "tenant-a/evoclaw"
+
+[orchestrator]
+url = "http://localhost:8420"
+        "#;
+
+        let mut temp_file = NamedTempFile::new().unwrap();
+        temp_file.write_all(toml_content.as_bytes()).unwrap();
+
+        let config = Config::from_file(temp_file.path()).unwrap();
+        assert_eq!(config.mqtt.topic_prefix, "tenant-a/evoclaw");
     }
 
     #[test]
@@ -5,7 +5,7 @@ use std::path::{Path, PathBuf};
 use serde::{Deserialize, Serialize};
 use tracing::info;
 
-use crate::config::{Config, MonitorConfig, MqttConfig, OrchestratorConfig};
+use crate::config::{Config, MonitorConfig, MqttConfig, OrchestratorConfig, DEFAULT_TOPIC_PREFIX};
 
 /// Options for the `join` command
 #[derive(Debug, Clone)]
@@ -231,6 +231,7 @@ pub fn build_config(
         broker: mqtt_broker.to_string(),
         port: mqtt_port,
         keep_alive_secs: 30,
+        topic_prefix: DEFAULT_TOPIC_PREFIX.to_string(),
     };
     config.orchestrator = OrchestratorConfig {
         url: format!("http://{}:{}", hub, orchestrator_port),
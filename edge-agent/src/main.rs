@@ -1,4 +1,5 @@
 mod agent;
+mod cancel;
 mod commands;
 mod config;
 mod evolution;
@@ -3,7 +3,7 @@ use serde::{Deserialize, Serialize};
 use std::time::Duration;
 use tracing::info;
 
-use crate::config::MqttConfig;
+use crate::config::{MqttConfig, DEFAULT_TOPIC_PREFIX};
 
 /// Message from orchestrator to agent
 #[derive(Debug, Clone, Serialize, Deserialize)]
@@ -27,6 +27,7 @@ pub struct MqttClient {
     client: AsyncClient,
     agent_id: String,
     agent_type: String,
+    topic_prefix: String,
 }
 
 impl MqttClient {
@@ -42,40 +43,50 @@ impl MqttClient {
 
         let (client, eventloop) = AsyncClient::new(mqttoptions, 100);
 
+        let mut topic_prefix = config.topic_prefix.trim_matches('/').to_string();
+        if topic_prefix.is_empty() {
+            topic_prefix = DEFAULT_TOPIC_PREFIX.to_string();
+        }
+
         Ok((
             Self {
                 client,
                 agent_id,
                 agent_type,
+                topic_prefix,
             },
             eventloop,
         ))
     }
 
+    /// Full topic for a path under the configured prefix
+    pub fn topic(&self, path: &str) -> String {
+        format!("{}/{}", self.topic_prefix, path)
+    }
+
+    /// Topic for a path under this agent's namespace
+    fn agent_topic(&self, path: &str) -> String {
+        self.topic(&format!("agents/{}/{}", self.agent_id, path))
+    }
+
     /// Subscribe to relevant MQTT topics
     pub async fn subscribe(&self) -> Result<(), Box<dyn std::error::Error>> {
         // Subscribe to commands for this agent
         self.client
-            .subscribe(
-                format!("evoclaw/agents/{}/commands", self.agent_id),
-                QoS::AtLeastOnce,
-            )
+            .subscribe(self.agent_topic("commands"), QoS::AtLeastOnce)
             .await?;
 
         // Subscribe to broadcast commands
         self.client
-            .subscribe("evoclaw/broadcast".to_string(), QoS::AtLeastOnce)
+            .subscribe(self.topic("broadcast"), QoS::AtLeastOnce)
             .await?;
 
         // Subscribe to strategy updates from evolution engine
         self.client
-            .subscribe(
-                format!("evoclaw/agents/{}/strategy", self.agent_id),
-                QoS::AtLeastOnce,
-            )
+            .subscribe(self.agent_topic("strategy"), QoS::AtLeastOnce)
             .await?;
 
-        info!(agent_id = %self.agent_id, "subscribed to MQTT topics");
+        info!(agent_id = %self.agent_id, prefix = %self.topic_prefix, "subscribed to MQTT topics");
         Ok(())
     }
 
@@ -98,7 +109,7 @@ impl MqttClient {
         let payload = serde_json::to_vec(&report)?;
         self.client
             .publish(
-                format!("evoclaw/agents/{}/reports", self.agent_id),
+                self.agent_topic("reports"),
                 QoS::AtLeastOnce,
                 false,
                 payload,
@@ -122,7 +133,7 @@ impl MqttClient {
 
         self.client
             .publish(
-                format!("evoclaw/agents/{}/capabilities", self.agent_id),
+                self.agent_topic("capabilities"),
                 QoS::AtLeastOnce,
                 true, // retained — orchestrator gets this on reconnect automatically
                 payload,
@@ -256,6 +267,7 @@ mod tests {
             broker: "localhost".to_string(),
             port: 1883,
             keep_alive_secs: 30,
+            topic_prefix: DEFAULT_TOPIC_PREFIX.to_string(),
         };
 
         let result = MqttClient::new(&config, "test_agent".to_string(), "trader".to_string());
@@ -267,15 +279,32 @@ mod tests {
 
     #[test]
     fn test_mqtt_topics_format() {
-        // Test that topic formats are consistent
-        let agent_id = "agent123";
-        let command_topic = format!("evoclaw/agents/{}/commands", agent_id);
-        let report_topic = format!("evoclaw/agents/{}/reports", agent_id);
-        let strategy_topic = format!("evoclaw/agents/{}/strategy", agent_id);
-
-        assert_eq!(command_topic, "evoclaw/agents/agent123/commands");
-        assert_eq!(report_topic, "evoclaw/agents/agent123/reports");
-        assert_eq!(strategy_topic, "evoclaw/agents/agent123/strategy");
+        let mut config = MqttConfig {
+            broker: "localhost".to_string(),
+            port: 1883,
+            keep_alive_secs: 30,
+            topic_prefix: DEFAULT_TOPIC_PREFIX.to_string(),
+        };
+        let (client, _eventloop) =
+            MqttClient::new(&config, "agent123".to_string(), "trader".to_string()).unwrap();
+        assert_eq!(
+            client.agent_topic("commands"),
+            "evoclaw/agents/agent123/commands"
+        );
+        assert_eq!(
+            client.agent_topic("reports"),
+            "evoclaw/agents/agent123/reports"
+        );
+        assert_eq!(client.topic("broadcast"), "evoclaw/broadcast");
+
+        config.topic_prefix = "/tenant-a/evoclaw/".to_string();
+        let (client, _eventloop) =
+            MqttClient::new(&config, "agent123".to_string(), "trader".to_string()).unwrap();
+        assert_eq!(
+            client.agent_topic("strategy"),
+            "tenant-a/evoclaw/agents/agent123/strategy"
+        );
+        assert_eq!(client.topic("broadcast"), "tenant-a/evoclaw/broadcast");
     }
 
     #[test]
@@ -343,6 +372,7 @@ mod tests {
                     broker: "broker1".to_string(),
                     port: 1883,
                     keep_alive_secs: 30,
+                    topic_prefix: DEFAULT_TOPIC_PREFIX.to_string(),
                 },
                 1883,
             ),
@@ -351,6 +381,7 @@ mod tests {
                     broker: "broker2".to_string(),
                     port: 8883,
                     keep_alive_secs: 30,
+                    topic_prefix: DEFAULT_TOPIC_PREFIX.to_string(),
                 },
                 8883,
             ),
@@ -359,6 +390,7 @@ mod tests {
                     broker: "broker3".to_string(),
                     port: 9001,
                     keep_alive_secs: 30,
+                    topic_prefix: DEFAULT_TOPIC_PREFIX.to_string(),
                 },
                 9001,
             ),
@@ -10,6 +10,7 @@
 // Prerequisites:
 //   - MQTT broker (Mosquitto) running on localhost:1883
 //   - Set MQTT_BROKER and MQTT_PORT env vars to override defaults
+//   - Set MQTT_TOPIC_PREFIX to run under a topic prefix other than "evoclaw"
 //
 // Run with: go test -v -tags=integration -timeout=60s ./...
 package integration
@@ -61,15 +62,15 @@ type AgentStatus struct {
 }
 
 // ──────────────────────────────────────────────
-// MQTT topic constants (must match both codebases)
+// MQTT topics (must match both codebases), under mqtt.topicPrefix
 // ──────────────────────────────────────────────
 
-const (
-	commandsTopicFmt = "evoclaw/agents/%s/commands"
-	reportsTopicFmt  = "evoclaw/agents/%s/reports"
-	statusTopicFmt   = "evoclaw/agents/%s/status"
-	strategyTopicFmt = "evoclaw/agents/%s/strategy"
-	broadcastTopic   = "evoclaw/broadcast"
+var (
+	commandsTopicFmt = mqttTopicPrefix() + "/agents/%s/commands"
+	reportsTopicFmt  = mqttTopicPrefix() + "/agents/%s/reports"
+	statusTopicFmt   = mqttTopicPrefix() + "/agents/%s/status"
+	strategyTopicFmt = mqttTopicPrefix() + "/agents/%s/strategy"
+	broadcastTopic   = mqttTopicPrefix() + "/broadcast"
 )
 
 // ──────────────────────────────────────────────
@@ -83,6 +84,13 @@ func mqttBroker() string {
 	return "localhost"
 }
 
+func mqttTopicPrefix() string {
+	if p := os.Getenv("MQTT_TOPIC_PREFIX"); p != "" {
+		return p
+	}
+	return "evoclaw"
+}
+
 func mqttPort() int {
 	if p := os.Getenv("MQTT_PORT"); p != "" {
 		port, err := strconv.Atoi(p)
@@ -274,7 +282,7 @@ func TestHeartbeat(t *testing.T) {
 
 	// Orchestrator subscribes to agent status (wildcard)
 	statusCh := make(chan []byte, 5)
-	statusPattern := "evoclaw/agents/+/status"
+	statusPattern := fmt.Sprintf(statusTopicFmt, "+")
 	token := orchClient.Subscribe(statusPattern, 1, func(_ mqtt.Client, msg mqtt.Message) {
 		data := make([]byte, len(msg.Payload()))
 		copy(data, msg.Payload())
@@ -469,7 +477,7 @@ func TestMetricsReporting(t *testing.T) {
 
 	// Orchestrator subscribes to agent reports (wildcard)
 	reportCh := make(chan []byte, 5)
-	reportPattern := "evoclaw/agents/+/reports"
+	reportPattern := fmt.Sprintf(reportsTopicFmt, "+")
 	token := orchClient.Subscribe(reportPattern, 1, func(_ mqtt.Client, msg mqtt.Message) {
 		data := make([]byte, len(msg.Payload()))
 		copy(data, msg.Payload())
@@ -835,7 +843,7 @@ func TestTopicWildcards(t *testing.T) {
 	receivedTopics := make(map[string]bool)
 	var mu sync.Mutex
 
-	token := orchClient.Subscribe("evoclaw/agents/+/reports", 1, func(_ mqtt.Client, msg mqtt.Message) {
+	token := orchClient.Subscribe(fmt.Sprintf(reportsTopicFmt, "+"), 1, func(_ mqtt.Client, msg mqtt.Message) {
 		mu.Lock()
 		receivedTopics[msg.Topic()] = true
 		mu.Unlock()
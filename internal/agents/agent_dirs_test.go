@@ -0,0 +1,144 @@
+package agents
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+func TestAgentDataDirs_IsolatesAndDeletes(t *testing.T) {
+	dataDir := t.TempDir()
+	store, err := storage.NewFileStore(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRegistryWithStore(store, testLogger())
+	r.SetAgentDataDirs(true)
+	mem, err := NewMemoryStore(dataDir, testLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem.SetAgentDataDirs(dataDir)
+	engine := evolution.NewEngineWithStore(store, testLogger())
+	engine.SetAgentDataDirs(true)
+
+	for _, id := range []string{"a1", "a2"} {
+		if _, err := r.Create(config.AgentDef{ID: id, Type: "monitor"}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		mem.Get(id).Add("user", "hello from "+id)
+		if err := mem.Save(id); err != nil {
+			t.Fatalf("Save memory: %v", err)
+		}
+		engine.SetStrategy(id, &evolution.Strategy{Temperature: 0.5, Params: map[string]float64{}})
+	}
+
+	agentFiles := func(id string) []string {
+		var files []string
+		_ = filepath.WalkDir(filepath.Join(dataDir, "agents", id), func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				rel, _ := filepath.Rel(dataDir, path)
+				files = append(files, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+		return files
+	}
+	for _, id := range []string{"a1", "a2"} {
+		want := map[string]bool{
+			"agents/" + id + "/agent.json":              true,
+			"agents/" + id + "/memory.json":             true,
+			"agents/" + id + "/evolution/strategy.json": true,
+			"agents/" + id + "/evolution/lineage.json":  true,
+		}
+		files := agentFiles(id)
+		if len(files) != len(want) {
+			t.Errorf("%s files = %v", id, files)
+		}
+		for _, f := range files {
+			if !want[f] {
+				t.Errorf("%s: unexpected file %s", id, f)
+			}
+		}
+	}
+	for _, shared := range []string{"agents/a1.json", "memory/a1.json", "evolution/a1.json"} {
+		if _, err := os.Stat(filepath.Join(dataDir, shared)); !os.IsNotExist(err) {
+			t.Errorf("%s written to the shared layout", shared)
+		}
+	}
+
+	if err := r.Delete("a1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	mem.Delete("a1")
+	if _, err := os.Stat(filepath.Join(dataDir, "agents", "a1")); !os.IsNotExist(err) {
+		t.Errorf("a1 data dir still present: %v", agentFiles("a1"))
+	}
+	if files := agentFiles("a2"); len(files) != 4 {
+		t.Errorf("a2 files after deleting a1 = %v", files)
+	}
+
+	r2 := NewRegistryWithStore(store, testLogger())
+	r2.SetAgentDataDirs(true)
+	if err := r2.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := r2.List(); len(got) != 1 || got[0].ID != "a2" {
+		t.Errorf("reloaded agents = %d, want only a2", len(got))
+	}
+	if msgs := mem.Get("a2").Messages; len(msgs) != 1 {
+		t.Errorf("a2 memory = %v", msgs)
+	}
+}
+
+func TestAgentDataDirs_MovesFlatRecords(t *testing.T) {
+	store := storage.NewMemoryStore()
+	r := NewRegistryWithStore(store, testLogger())
+	if _, err := r.Create(config.AgentDef{ID: "a1", Type: "monitor"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r2 := NewRegistryWithStore(store, testLogger())
+	r2.SetAgentDataDirs(true)
+	if err := r2.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, err := r2.Get("a1"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if keys, _ := store.List(agentsNamespace); len(keys) != 0 {
+		t.Errorf("flat records left behind: %v", keys)
+	}
+	if _, err := store.Get(storage.AgentNamespace("a1"), agentRecordKey); err != nil {
+		t.Errorf("record not moved: %v", err)
+	}
+}
+
+func TestAgentDataDirs_MovesFlatMemory(t *testing.T) {
+	dataDir := t.TempDir()
+	mem, err := NewMemoryStore(dataDir, testLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem.Get("a1").Add("user", "remember me")
+	if err := mem.Save("a1"); err != nil {
+		t.Fatal(err)
+	}
+
+	mem2, err := NewMemoryStore(dataDir, testLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem2.SetAgentDataDirs(dataDir)
+
+	if _, err := os.Stat(filepath.Join(dataDir, "memory", "a1.json")); !os.IsNotExist(err) {
+		t.Errorf("shared memory file left behind: %v", err)
+	}
+	if msgs := mem2.Get("a1").Messages; len(msgs) != 1 || msgs[0].Content != "remember me" {
+		t.Errorf("memory not carried over: %v", msgs)
+	}
+}
@@ -6,11 +6,14 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 	"unicode/utf8"
 
 	"github.com/clawinfra/evoclaw/internal/orchestrator"
+	"github.com/clawinfra/evoclaw/internal/storage"
 )
 
 const (
@@ -37,6 +40,11 @@ type MemoryStore struct {
 	logger  *slog.Logger
 	mu      sync.RWMutex
 	cache   map[string]*ConversationMemory
+	policy  EvictionPolicy
+
+	// agentsDir, when set, holds each agent's memory at
+	// agentsDir/<id>/memory.json instead of dataDir/<id>.json.
+	agentsDir string
 }
 
 // ConversationMemory stores chat history for an agent with bounded growth.
@@ -48,7 +56,14 @@ type ConversationMemory struct {
 	TokenLimit     int                        `json:"token_limit"`
 	CompactionCount int                       `json:"compaction_count"`
 	LastAccessed   time.Time                  `json:"last_accessed"`
+	// Pinned memories are core memory and are never evicted.
+	Pinned bool `json:"pinned,omitempty"`
 	mu             sync.RWMutex
+
+	// dirty is set when the conversation changed since it was last saved
+	// or loaded; size is its encoded size at that point.
+	dirty bool
+	size  int64
 }
 
 // NewMemoryStore creates a new memory store.
@@ -65,6 +80,43 @@ func NewMemoryStore(dataDir string, logger *slog.Logger) (*MemoryStore, error) {
 	}, nil
 }
 
+// SetAgentDataDirs stores each agent's memory in its per-agent data
+// directory, dataDir/agents/<id>/memory.json, where deleting the agent
+// removes it. Memory saved in the shared memory directory is moved there,
+// unless the agent's directory already has memory of its own. Call it
+// before the store is used.
+func (m *MemoryStore) SetAgentDataDirs(dataDir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	flat, err := m.memoryFiles()
+	m.agentsDir = filepath.Join(dataDir, storage.AgentsNamespace)
+	if err != nil {
+		m.logger.Error("failed to list memory to move", "error", err)
+		return
+	}
+	for key := range flat {
+		m.migrateMemory(key)
+	}
+}
+
+// migrateMemory moves key's memory file from the shared memory directory
+// into its per-agent data directory. Caller must hold m.mu.
+func (m *MemoryStore) migrateMemory(key string) {
+	from := filepath.Join(m.dataDir, key+".json")
+	to := m.memoryPath(key)
+	if _, err := os.Stat(to); err == nil {
+		m.logger.Warn("memory already in agent data dir, leaving shared copy", "agent", key, "path", from)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0750); err != nil {
+		m.logger.Error("failed to move memory", "agent", key, "error", err)
+		return
+	}
+	if err := os.Rename(from, to); err != nil {
+		m.logger.Error("failed to move memory", "agent", key, "error", err)
+	}
+}
+
 // Get retrieves or creates conversation memory for an agent.
 func (m *MemoryStore) Get(agentID string) *ConversationMemory {
 	m.mu.RLock()
@@ -116,6 +168,7 @@ func (c *ConversationMemory) Add(role, content string) {
 	c.TotalTokens += estimateTokens(content)
 	c.compact()
 	c.LastAccessed = time.Now()
+	c.dirty = true
 }
 
 // GetMessages returns a copy of all messages.
@@ -154,6 +207,7 @@ func (c *ConversationMemory) Clear() {
 	c.TotalTokens = 0
 	c.CompactionCount = 0
 	c.LastAccessed = time.Now()
+	c.dirty = true
 }
 
 // compact enforces MaxMessages and TokenLimit using a head+tail strategy.
@@ -235,19 +289,29 @@ func (c *ConversationMemory) recalculateTokens() {
 	c.TotalTokens = total
 }
 
-// Save persists the conversation memory to disk.
+// Save persists the conversation memory to disk, then applies the
+// eviction policy if one is set. Saving does not count as an access.
 func (m *MemoryStore) Save(agentID string) error {
-	mem := m.Get(agentID)
+	m.mu.RLock()
+	mem, ok := m.cache[agentID]
+	m.mu.RUnlock()
+	if !ok {
+		mem = m.Get(agentID)
+	}
 	if mem == nil {
 		return fmt.Errorf("no memory for agent: %s", agentID)
 	}
-	return m.saveMemory(agentID, mem)
+	if err := m.saveMemory(agentID, mem); err != nil {
+		return err
+	}
+	m.Evict()
+	return nil
 }
 
 // saveMemory is an internal helper that avoids the Get→lock deadlock path.
 func (m *MemoryStore) saveMemory(agentID string, mem *ConversationMemory) error {
-	mem.mu.RLock()
-	defer mem.mu.RUnlock()
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
 
 	data, err := json.MarshalIndent(mem, "", "  ")
 	if err != nil {
@@ -255,24 +319,38 @@ func (m *MemoryStore) saveMemory(agentID string, mem *ConversationMemory) error
 	}
 
 	path := m.memoryPath(agentID)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("create memory dir: %w", err)
+	}
 	if err := os.WriteFile(path, data, 0640); err != nil {
 		return fmt.Errorf("write memory file: %w", err)
 	}
+	mem.dirty = false
+	mem.size = int64(len(data))
 
 	m.logger.Debug("memory saved", "agent", agentID, "messages", len(mem.Messages))
 	return nil
 }
 
-// SaveAll flushes all cached memories to disk.
+// SaveAll flushes cached memories changed since they were last saved.
 func (m *MemoryStore) SaveAll() error {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
+	cached := make(map[string]*ConversationMemory, len(m.cache))
+	for agentID, mem := range m.cache {
+		mem.mu.RLock()
+		if mem.dirty {
+			cached[agentID] = mem
+		}
+		mem.mu.RUnlock()
+	}
+	m.mu.RUnlock()
 
-	for agentID := range m.cache {
-		if err := m.Save(agentID); err != nil {
+	for agentID, mem := range cached {
+		if err := m.saveMemory(agentID, mem); err != nil {
 			m.logger.Error("failed to save memory", "agent", agentID, "error", err)
 		}
 	}
+	m.Evict()
 	return nil
 }
 
@@ -302,15 +380,103 @@ func (m *MemoryStore) loadFromDisk(agentID string) *ConversationMemory {
 	}
 
 	mem.LastAccessed = time.Now()
+	mem.size = int64(len(data))
 	m.logger.Info("memory loaded", "agent", agentID, "messages", len(mem.Messages))
 	return &mem
 }
 
 // memoryPath returns the file path for an agent's memory.
 func (m *MemoryStore) memoryPath(agentID string) string {
+	if m.agentsDir != "" {
+		return filepath.Join(m.agentsDir, agentID, agentMemoryFile)
+	}
 	return filepath.Join(m.dataDir, agentID+".json")
 }
 
+// agentMemoryFile is the memory file name in a per-agent data directory.
+const agentMemoryFile = "memory.json"
+
+// memoryFiles returns the memory file on disk for every agent, keyed by
+// agent ID.
+func (m *MemoryStore) memoryFiles() (map[string]os.FileInfo, error) {
+	files := make(map[string]os.FileInfo)
+	if m.agentsDir != "" {
+		dirs, err := os.ReadDir(m.agentsDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, d := range dirs {
+			if !d.IsDir() {
+				continue
+			}
+			if info, err := os.Stat(filepath.Join(m.agentsDir, d.Name(), agentMemoryFile)); err == nil {
+				files[d.Name()] = info
+			}
+		}
+		return files, nil
+	}
+
+	entries, err := os.ReadDir(m.dataDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files[strings.TrimSuffix(entry.Name(), ".json")] = info
+		}
+	}
+	return files, nil
+}
+
+// ConversationKeys returns the memory keys holding agentID's
+// conversations: the agent ID itself and "<agentID>:<conversation>" keys.
+func (m *MemoryStore) ConversationKeys(agentID string) ([]string, error) {
+	files, err := m.memoryFiles()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	m.mu.RLock()
+	for key := range m.cache {
+		seen[key] = true
+	}
+	m.mu.RUnlock()
+	for key := range files {
+		seen[key] = true
+	}
+
+	var keys []string
+	for key := range seen {
+		if key == agentID || strings.HasPrefix(key, agentID+":") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Restore replaces the conversation stored under key with messages and
+// saves it.
+func (m *MemoryStore) Restore(key string, messages []orchestrator.ChatMessage) error {
+	mem := m.Get(key)
+	mem.mu.Lock()
+	mem.Messages = append(make([]orchestrator.ChatMessage, 0, len(messages)), messages...)
+	mem.recalculateTokens()
+	mem.dirty = true
+	mem.mu.Unlock()
+	return m.saveMemory(key, mem)
+}
+
+// Delete drops an agent's memory from the cache and disk.
+func (m *MemoryStore) Delete(agentID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removeLocked(agentID)
+}
+
 // Cleanup evicts unused in-memory entries and removes stale files.
 func (m *MemoryStore) Cleanup(maxAgeHours int) error {
 	m.mu.Lock()
@@ -332,21 +498,14 @@ func (m *MemoryStore) Cleanup(maxAgeHours int) error {
 		}
 	}
 
-	entries, err := os.ReadDir(m.dataDir)
+	files, err := m.memoryFiles()
 	if err != nil {
 		return fmt.Errorf("read memory dir: %w", err)
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
-			continue
-		}
-		info, err := entry.Info()
-		if err != nil {
-			continue
-		}
+	for agentID, info := range files {
 		if info.ModTime().Before(threshold) {
-			path := filepath.Join(m.dataDir, entry.Name())
+			path := m.memoryPath(agentID)
 			if err := os.Remove(path); err != nil {
 				m.logger.Error("failed to delete old memory file", "path", path, "error", err)
 			} else {
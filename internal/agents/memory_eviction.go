@@ -0,0 +1,230 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// EvictionPolicy bounds the conversation memory kept by a MemoryStore.
+// A zero field disables that limit. Pinned memories are never evicted.
+type EvictionPolicy struct {
+	// Retention evicts memories not accessed for this long.
+	Retention time.Duration
+	// MaxSizeBytes caps the total serialised size of all memories. Once it
+	// is exceeded, the oldest messages of the least recently used memories
+	// are dropped first; a memory left with no messages is removed.
+	MaxSizeBytes int64
+}
+
+// enabled reports whether the policy imposes any limit.
+func (p EvictionPolicy) enabled() bool {
+	return p.Retention > 0 || p.MaxSizeBytes > 0
+}
+
+// SetEvictionPolicy configures retention and size limits. Eviction then runs
+// whenever memory is saved and on each tick of RunEviction.
+func (m *MemoryStore) SetEvictionPolicy(p EvictionPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policy = p
+}
+
+// Pin marks an agent's memory as core memory that eviction never removes.
+func (m *MemoryStore) Pin(agentID string, pinned bool) {
+	mem := m.Get(agentID)
+	mem.mu.Lock()
+	mem.Pinned = pinned
+	mem.dirty = true
+	mem.mu.Unlock()
+}
+
+// RunEviction applies the eviction policy every interval until ctx is done.
+func (m *MemoryStore) RunEviction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Evict()
+		}
+	}
+}
+
+// memoryEntry describes one agent's memory, cached or on disk, for eviction.
+type memoryEntry struct {
+	agentID    string
+	size       int64
+	lastAccess time.Time
+	pinned     bool
+}
+
+// Evict applies the eviction policy to the cache and to disk, and returns
+// the IDs of the agents whose memory was removed or trimmed.
+func (m *MemoryStore) Evict() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.policy.enabled() {
+		return nil
+	}
+
+	entries := m.memoryEntriesLocked()
+	var evicted []string
+
+	// Expire entries past retention
+	if m.policy.Retention > 0 {
+		threshold := time.Now().Add(-m.policy.Retention)
+		kept := entries[:0]
+		for _, e := range entries {
+			if !e.pinned && e.lastAccess.Before(threshold) {
+				m.removeLocked(e.agentID)
+				evicted = append(evicted, e.agentID)
+				continue
+			}
+			kept = append(kept, e)
+		}
+		entries = kept
+	}
+
+	// Drop the oldest messages of the least recently used entries until
+	// under the size cap
+	if m.policy.MaxSizeBytes > 0 {
+		var total int64
+		for _, e := range entries {
+			total += e.size
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].lastAccess.Before(entries[j].lastAccess)
+		})
+		for _, e := range entries {
+			if total <= m.policy.MaxSizeBytes {
+				break
+			}
+			if e.pinned {
+				continue
+			}
+			total -= m.trimLocked(e, total-m.policy.MaxSizeBytes)
+			evicted = append(evicted, e.agentID)
+		}
+	}
+
+	if len(evicted) > 0 {
+		m.logger.Info("conversation memory evicted", "agents", evicted)
+	}
+	return evicted
+}
+
+// memoryEntriesLocked lists every memory known to the store, preferring the
+// cached copy over the file on disk. Caller must hold m.mu.
+func (m *MemoryStore) memoryEntriesLocked() []memoryEntry {
+	var entries []memoryEntry
+	seen := make(map[string]bool, len(m.cache))
+
+	for agentID, mem := range m.cache {
+		mem.mu.RLock()
+		entry := memoryEntry{agentID: agentID, size: mem.size, lastAccess: mem.LastAccessed, pinned: mem.Pinned}
+		if mem.dirty || mem.size == 0 {
+			// Only memory changed since its last save is encoded again
+			if data, err := json.MarshalIndent(mem, "", "  "); err == nil {
+				entry.size = int64(len(data))
+			}
+		}
+		mem.mu.RUnlock()
+		entries = append(entries, entry)
+		seen[agentID] = true
+	}
+
+	files, err := m.memoryFiles()
+	if err != nil {
+		m.logger.Error("failed to read memory dir", "error", err)
+		return entries
+	}
+	for agentID, info := range files {
+		if seen[agentID] {
+			continue
+		}
+		entries = append(entries, memoryEntry{
+			agentID:    agentID,
+			size:       info.Size(),
+			lastAccess: info.ModTime(),
+			pinned:     m.pinnedOnDisk(agentID),
+		})
+	}
+	return entries
+}
+
+// pinnedOnDisk reads the pinned flag from an uncached memory file.
+func (m *MemoryStore) pinnedOnDisk(agentID string) bool {
+	data, err := os.ReadFile(m.memoryPath(agentID))
+	if err != nil {
+		return false
+	}
+	var header struct {
+		Pinned bool `json:"pinned"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return false
+	}
+	return header.Pinned
+}
+
+// trimLocked drops e's oldest messages until about excess bytes are freed
+// and saves what is left, removing the memory once no messages remain. It
+// returns the bytes freed. Caller must hold m.mu.
+func (m *MemoryStore) trimLocked(e memoryEntry, excess int64) int64 {
+	mem, cached := m.cache[e.agentID]
+	if !cached {
+		if mem = m.loadFromDisk(e.agentID); mem == nil {
+			m.removeLocked(e.agentID)
+			return e.size
+		}
+	}
+
+	mem.mu.Lock()
+	var freed int64
+	n := 0
+	for n < len(mem.Messages) && freed < excess {
+		if data, err := json.MarshalIndent(mem.Messages[n], "    ", "  "); err == nil {
+			freed += int64(len(data))
+		}
+		n++
+	}
+	if n == len(mem.Messages) {
+		mem.mu.Unlock()
+		m.removeLocked(e.agentID)
+		return e.size
+	}
+	mem.Messages = append([]orchestrator.ChatMessage(nil), mem.Messages[n:]...)
+	mem.recalculateTokens()
+	mem.dirty = true
+	mem.mu.Unlock()
+
+	if err := m.saveMemory(e.agentID, mem); err != nil {
+		m.logger.Error("failed to save trimmed memory", "agent", e.agentID, "error", err)
+		return 0
+	}
+	if !cached {
+		// Trimming is not an access: keep the file's age for the next pass
+		_ = os.Chtimes(m.memoryPath(e.agentID), e.lastAccess, e.lastAccess)
+	}
+	mem.mu.RLock()
+	defer mem.mu.RUnlock()
+	return e.size - mem.size
+}
+
+// removeLocked drops an agent's memory from the cache and disk.
+// Caller must hold m.mu.
+func (m *MemoryStore) removeLocked(agentID string) {
+	delete(m.cache, agentID)
+	if err := os.Remove(m.memoryPath(agentID)); err != nil && !os.IsNotExist(err) {
+		m.logger.Error("failed to delete memory file", "agent", agentID, "error", err)
+	}
+}
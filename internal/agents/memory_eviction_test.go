@@ -0,0 +1,265 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// seedMemory creates and saves an agent memory last accessed at the given time.
+func seedMemory(t *testing.T, m *MemoryStore, agentID string, lastAccessed time.Time) {
+	t.Helper()
+	mem := m.Get(agentID)
+	mem.Add("user", strings.Repeat("x", 200))
+	mem.mu.Lock()
+	mem.LastAccessed = lastAccessed
+	mem.mu.Unlock()
+	if err := m.Save(agentID); err != nil {
+		t.Fatalf("Save(%s): %v", agentID, err)
+	}
+}
+
+func memoryExists(m *MemoryStore, agentID string) bool {
+	m.mu.RLock()
+	_, cached := m.cache[agentID]
+	m.mu.RUnlock()
+	_, err := os.Stat(m.memoryPath(agentID))
+	return cached || err == nil
+}
+
+func totalMemorySize(t *testing.T, m *MemoryStore) int64 {
+	t.Helper()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var total int64
+	for _, e := range m.memoryEntriesLocked() {
+		total += e.size
+	}
+	return total
+}
+
+func TestEvictNoPolicy(t *testing.T) {
+	m := newTestMemoryStore(t)
+	seedMemory(t, m, "a", time.Now().Add(-365*24*time.Hour))
+
+	if evicted := m.Evict(); len(evicted) != 0 {
+		t.Errorf("expected nothing evicted without a policy, got %v", evicted)
+	}
+	if !memoryExists(m, "a") {
+		t.Error("memory should survive without a policy")
+	}
+}
+
+func TestEvictSizeCapLRU(t *testing.T) {
+	m := newTestMemoryStore(t)
+	base := time.Now().Add(-time.Hour)
+	for i, id := range []string{"oldest", "older", "newer", "newest"} {
+		seedMemory(t, m, id, base.Add(time.Duration(i)*time.Minute))
+	}
+
+	// Cap at roughly two entries' worth
+	perEntry := totalMemorySize(t, m) / 4
+	m.SetEvictionPolicy(EvictionPolicy{MaxSizeBytes: perEntry*2 + perEntry/2})
+
+	evicted := m.Evict()
+	if len(evicted) != 2 || evicted[0] != "oldest" || evicted[1] != "older" {
+		t.Fatalf("expected [oldest older] evicted, got %v", evicted)
+	}
+	for _, id := range []string{"oldest", "older"} {
+		if memoryExists(m, id) {
+			t.Errorf("%s should have been evicted from cache and disk", id)
+		}
+	}
+	for _, id := range []string{"newer", "newest"} {
+		if !memoryExists(m, id) {
+			t.Errorf("%s should have survived", id)
+		}
+	}
+}
+
+func TestEvictSizeCapKeepsPinned(t *testing.T) {
+	m := newTestMemoryStore(t)
+	base := time.Now().Add(-time.Hour)
+	for i, id := range []string{"core", "warm1", "warm2", "warm3"} {
+		seedMemory(t, m, id, base.Add(time.Duration(i)*time.Minute))
+	}
+	m.Pin("core", true)
+	if err := m.Save("core"); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	perEntry := totalMemorySize(t, m) / 4
+	m.SetEvictionPolicy(EvictionPolicy{MaxSizeBytes: perEntry*2 + perEntry/2})
+
+	evicted := m.Evict()
+	if len(evicted) != 2 || evicted[0] != "warm1" || evicted[1] != "warm2" {
+		t.Fatalf("expected [warm1 warm2] evicted, got %v", evicted)
+	}
+	if !memoryExists(m, "core") {
+		t.Error("pinned memory should never be evicted")
+	}
+	if !memoryExists(m, "warm3") {
+		t.Error("most recent warm memory should survive")
+	}
+}
+
+func TestEvictPinnedOnDisk(t *testing.T) {
+	m := newTestMemoryStore(t)
+	seedMemory(t, m, "core", time.Now().Add(-48*time.Hour))
+	m.Pin("core", true)
+	if err := m.Save("core"); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Reopen so the memory is only known from disk
+	m2, err := NewMemoryStore(filepath.Dir(m.dataDir), m.logger)
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	m2.SetEvictionPolicy(EvictionPolicy{Retention: time.Nanosecond, MaxSizeBytes: 1})
+
+	if evicted := m2.Evict(); len(evicted) != 0 {
+		t.Errorf("expected pinned disk memory to survive, got %v evicted", evicted)
+	}
+	if !m2.Get("core").Pinned {
+		t.Error("pinned flag should persist across reloads")
+	}
+}
+
+func TestEvictRetention(t *testing.T) {
+	m := newTestMemoryStore(t)
+	seedMemory(t, m, "stale", time.Now().Add(-10*24*time.Hour))
+	seedMemory(t, m, "fresh", time.Now())
+	seedMemory(t, m, "pinned", time.Now().Add(-10*24*time.Hour))
+	m.Pin("pinned", true)
+
+	m.SetEvictionPolicy(EvictionPolicy{Retention: 7 * 24 * time.Hour})
+
+	evicted := m.Evict()
+	if len(evicted) != 1 || evicted[0] != "stale" {
+		t.Fatalf("expected [stale] evicted, got %v", evicted)
+	}
+	if !memoryExists(m, "fresh") || !memoryExists(m, "pinned") {
+		t.Error("fresh and pinned memories should survive retention")
+	}
+}
+
+func TestSaveEvictsPastCap(t *testing.T) {
+	m := newTestMemoryStore(t)
+	seedMemory(t, m, "first", time.Now().Add(-time.Hour))
+	m.SetEvictionPolicy(EvictionPolicy{MaxSizeBytes: totalMemorySize(t, m) + 10})
+
+	seedMemory(t, m, "second", time.Now())
+
+	if memoryExists(m, "first") {
+		t.Error("saving past the size cap should evict the oldest memory")
+	}
+	if !memoryExists(m, "second") {
+		t.Error("newly saved memory should survive")
+	}
+}
+
+func TestRunEviction(t *testing.T) {
+	m := newTestMemoryStore(t)
+	seedMemory(t, m, "stale", time.Now().Add(-time.Hour))
+	m.SetEvictionPolicy(EvictionPolicy{Retention: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.RunEviction(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for memoryExists(m, "stale") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if memoryExists(m, "stale") {
+		t.Error("ticker should have evicted stale memory")
+	}
+}
+
+func TestEvictSizeCapTrimsOldestMessages(t *testing.T) {
+	m := newTestMemoryStore(t)
+	mem := m.Get("chatty")
+	for i := 0; i < 10; i++ {
+		mem.Add("user", fmt.Sprintf("message %d %s", i, strings.Repeat("x", 200)))
+	}
+	if err := m.Save("chatty"); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	m.SetEvictionPolicy(EvictionPolicy{MaxSizeBytes: totalMemorySize(t, m) / 2})
+	if evicted := m.Evict(); len(evicted) != 1 || evicted[0] != "chatty" {
+		t.Fatalf("expected [chatty] trimmed, got %v", evicted)
+	}
+
+	msgs := m.Get("chatty").GetMessages()
+	if len(msgs) == 0 || len(msgs) >= 10 {
+		t.Fatalf("expected some but not all messages kept, got %d", len(msgs))
+	}
+	if !strings.HasPrefix(msgs[len(msgs)-1].Content, "message 9 ") {
+		t.Errorf("newest message should be kept, last is %q", msgs[len(msgs)-1].Content[:10])
+	}
+	if size := totalMemorySize(t, m); size > m.policy.MaxSizeBytes {
+		t.Errorf("size %d still over cap %d", size, m.policy.MaxSizeBytes)
+	}
+}
+
+func TestEvictTrimsUncachedMemory(t *testing.T) {
+	m := newTestMemoryStore(t)
+	mem := m.Get("disk")
+	for i := 0; i < 10; i++ {
+		mem.Add("user", strings.Repeat("y", 200))
+	}
+	if err := m.Save("disk"); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	m2, err := NewMemoryStore(filepath.Dir(m.dataDir), m.logger)
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	m2.SetEvictionPolicy(EvictionPolicy{MaxSizeBytes: totalMemorySize(t, m2) / 2})
+	m2.Evict()
+
+	if n := len(m2.Get("disk").GetMessages()); n == 0 || n >= 10 {
+		t.Errorf("expected the file trimmed to some messages, got %d", n)
+	}
+}
+
+func TestSaveAllSkipsUnchanged(t *testing.T) {
+	m := newTestMemoryStore(t)
+	m.Get("a").Add("user", "hello")
+	if err := m.SaveAll(); err != nil {
+		t.Fatalf("SaveAll: %v", err)
+	}
+	path := m.memoryPath("a")
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.SaveAll(); err != nil {
+		t.Fatalf("SaveAll: %v", err)
+	}
+	if info, _ := os.Stat(path); !info.ModTime().Equal(old) {
+		t.Error("unchanged memory was written again")
+	}
+
+	m.Get("a").Add("user", "again")
+	if err := m.SaveAll(); err != nil {
+		t.Fatalf("SaveAll: %v", err)
+	}
+	if info, _ := os.Stat(path); info.ModTime().Equal(old) {
+		t.Error("changed memory was not saved")
+	}
+}
@@ -2,6 +2,7 @@ package agents
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -10,14 +11,27 @@ import (
 	"time"
 
 	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/storage"
 )
 
+// agentsNamespace is the storage namespace holding agent records.
+const agentsNamespace = storage.AgentsNamespace
+
+// agentRecordKey is the key of an agent's record in its own namespace when
+// per-agent data directories are enabled.
+const agentRecordKey = "agent"
+
 // Registry manages all agents and their state
 type Registry struct {
 	agents  map[string]*Agent
-	dataDir string
+	store   storage.Store
+	dataDir string // agents dir when backed by the default file store
 	logger  *slog.Logger
 	mu      sync.RWMutex
+
+	// agentDirs stores each record in the agent's own namespace
+	// (agents/<id>/agent) instead of agents/<id>.
+	agentDirs bool
 }
 
 // Agent represents a running agent
@@ -63,18 +77,46 @@ type SkillData struct {
 	Reports    []map[string]interface{} `json:"recent_reports,omitempty"`
 }
 
-// NewRegistry creates a new agent registry
+// NewRegistry creates a new agent registry persisted to files under dataDir
 func NewRegistry(dataDir string, logger *slog.Logger) (*Registry, error) {
-	agentsDir := filepath.Join(dataDir, "agents")
+	agentsDir := filepath.Join(dataDir, agentsNamespace)
 	if err := os.MkdirAll(agentsDir, 0750); err != nil {
 		return nil, fmt.Errorf("create agents dir: %w", err)
 	}
+	store, err := storage.NewFileStore(dataDir)
+	if err != nil {
+		return nil, err
+	}
 
+	r := NewRegistryWithStore(store, logger)
+	r.dataDir = agentsDir
+	return r, nil
+}
+
+// NewRegistryWithStore creates a new agent registry persisted to store
+func NewRegistryWithStore(store storage.Store, logger *slog.Logger) *Registry {
 	return &Registry{
-		agents:  make(map[string]*Agent),
-		dataDir: agentsDir,
-		logger:  logger.With("component", "registry"),
-	}, nil
+		agents: make(map[string]*Agent),
+		store:  store,
+		logger: logger.With("component", "registry"),
+	}
+}
+
+// SetAgentDataDirs switches the registry to per-agent data directories:
+// each record is stored at agents/<id>/agent, and Delete removes the whole
+// agents/<id> namespace with everything other components stored there. Call
+// it before Load; records in the flat layout are moved on load.
+func (r *Registry) SetAgentDataDirs(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.agentDirs = enabled
+}
+
+// AgentDataDirs reports whether per-agent data directories are enabled.
+func (r *Registry) AgentDataDirs() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.agentDirs
 }
 
 // Create adds a new agent to the registry
@@ -108,6 +150,32 @@ func (r *Registry) Create(def config.AgentDef) (*Agent, error) {
 	return agent, nil
 }
 
+// Restore adds an agent record saved elsewhere, such as one imported from
+// another device, keeping its definition, counters and metrics. An
+// existing agent with the same ID is only replaced if replace is set.
+func (r *Registry) Restore(agent *Agent, replace bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.agents[agent.ID]; exists && !replace {
+		return fmt.Errorf("agent already exists: %s", agent.ID)
+	}
+
+	restored := agent.GetSnapshot()
+	restored.Def.ID = restored.ID
+	restored.Status = "idle"
+	if restored.Metrics.Custom == nil {
+		restored.Metrics.Custom = make(map[string]float64)
+	}
+	if err := r.save(&restored); err != nil {
+		return fmt.Errorf("save agent: %w", err)
+	}
+	r.agents[restored.ID] = &restored
+
+	r.logger.Info("agent restored", "id", restored.ID, "type", restored.Def.Type)
+	return nil
+}
+
 // Get retrieves an agent by ID
 func (r *Registry) Get(id string) (*Agent, error) {
 	r.mu.RLock()
@@ -167,10 +235,14 @@ func (r *Registry) Delete(id string) error {
 
 	delete(r.agents, id)
 
-	// Delete from disk
-	path := r.agentPath(id)
-	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
-		r.logger.Error("failed to delete agent file", "id", id, "error", err)
+	// Delete from storage
+	if err := r.store.Delete(agentsNamespace, id); err != nil {
+		r.logger.Error("failed to delete agent record", "id", id, "error", err)
+	}
+	if r.agentDirs {
+		if err := r.store.DeleteNamespace(storage.AgentNamespace(id)); err != nil {
+			r.logger.Error("failed to delete agent data", "id", id, "error", err)
+		}
 	}
 
 	r.logger.Info("agent deleted", "id", id, "type", agent.Def.Type)
@@ -284,45 +356,70 @@ func (r *Registry) CheckHealth(timeoutSec int) []string {
 	return unhealthy
 }
 
-// Load restores agents from disk
+// Load restores agents from storage
 func (r *Registry) Load() error {
-	entries, err := os.ReadDir(r.dataDir)
+	ids, err := r.store.List(agentsNamespace)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil // No agents yet
+		return fmt.Errorf("list agents: %w", err)
+	}
+	for _, id := range ids {
+		agent := r.loadRecord(id, agentsNamespace, id)
+		if agent != nil && r.agentDirs {
+			r.migrateRecord(agent)
 		}
-		return fmt.Errorf("read agents dir: %w", err)
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
-			continue
-		}
+	if !r.agentDirs {
+		return nil
+	}
+	dirs, err := r.store.Namespaces(agentsNamespace)
+	if err != nil {
+		return fmt.Errorf("list agent data dirs: %w", err)
+	}
+	for _, id := range dirs {
+		r.loadRecord(id, storage.AgentNamespace(id), agentRecordKey)
+	}
+	return nil
+}
 
-		path := filepath.Join(r.dataDir, entry.Name())
-		data, err := os.ReadFile(path)
-		if err != nil {
-			r.logger.Error("failed to read agent file", "path", path, "error", err)
-			continue
+// loadRecord reads one agent record and adds it to the registry. Missing
+// or unreadable records are logged and skipped.
+func (r *Registry) loadRecord(id, namespace, key string) *Agent {
+	data, err := r.store.Get(namespace, key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			r.logger.Error("failed to read agent record", "id", id, "error", err)
 		}
+		return nil
+	}
 
-		var agent Agent
-		if err := json.Unmarshal(data, &agent); err != nil {
-			r.logger.Error("failed to parse agent file", "path", path, "error", err)
-			continue
-		}
+	var agent Agent
+	if err := json.Unmarshal(data, &agent); err != nil {
+		r.logger.Error("failed to parse agent record", "id", id, "error", err)
+		return nil
+	}
 
-		r.mu.Lock()
-		r.agents[agent.ID] = &agent
-		r.mu.Unlock()
+	r.mu.Lock()
+	r.agents[agent.ID] = &agent
+	r.mu.Unlock()
 
-		r.logger.Info("agent loaded", "id", agent.ID, "type", agent.Def.Type)
-	}
+	r.logger.Info("agent loaded", "id", agent.ID, "type", agent.Def.Type)
+	return &agent
+}
 
-	return nil
+// migrateRecord moves a record from the flat layout into the agent's own
+// namespace.
+func (r *Registry) migrateRecord(agent *Agent) {
+	if err := r.save(agent); err != nil {
+		r.logger.Error("failed to move agent record", "id", agent.ID, "error", err)
+		return
+	}
+	if err := r.store.Delete(agentsNamespace, agent.ID); err != nil {
+		r.logger.Error("failed to remove old agent record", "id", agent.ID, "error", err)
+	}
 }
 
-// SaveAll persists all agents to disk
+// SaveAll persists all agents to storage
 func (r *Registry) SaveAll() error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -336,7 +433,7 @@ func (r *Registry) SaveAll() error {
 	return nil
 }
 
-// save writes an agent to disk
+// save writes an agent to storage
 func (r *Registry) save(agent *Agent) error {
 	agent.mu.RLock()
 	defer agent.mu.RUnlock()
@@ -346,15 +443,18 @@ func (r *Registry) save(agent *Agent) error {
 		return fmt.Errorf("marshal agent: %w", err)
 	}
 
-	path := r.agentPath(agent.ID)
-	if err := os.WriteFile(path, data, 0640); err != nil {
-		return fmt.Errorf("write agent file: %w", err)
+	namespace, key := agentsNamespace, agent.ID
+	if r.agentDirs {
+		namespace, key = storage.AgentNamespace(agent.ID), agentRecordKey
+	}
+	if err := r.store.Put(namespace, key, data); err != nil {
+		return fmt.Errorf("write agent record: %w", err)
 	}
 
 	return nil
 }
 
-// agentPath returns the file path for an agent
+// agentPath returns the file path for an agent in the default file store
 func (r *Registry) agentPath(id string) string {
 	return filepath.Join(r.dataDir, id+".json")
 }
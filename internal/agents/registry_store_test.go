@@ -0,0 +1,56 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+func TestRegistryWithStore_RoundTrip(t *testing.T) {
+	store := storage.NewMemoryStore()
+	r := NewRegistryWithStore(store, testLogger())
+
+	if _, err := r.Create(config.AgentDef{ID: "a1", Name: "One", Type: "monitor"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := r.Create(config.AgentDef{ID: "a2", Name: "Two", Type: "trader"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := r.Delete("a2"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	keys, _ := store.List(agentsNamespace)
+	if len(keys) != 1 || keys[0] != "a1" {
+		t.Fatalf("stored keys = %v, want [a1]", keys)
+	}
+
+	r2 := NewRegistryWithStore(store, testLogger())
+	if err := r2.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	a, err := r2.Get("a1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if a.Def.Name != "One" {
+		t.Errorf("Name = %q, want One", a.Def.Name)
+	}
+	if _, err := r2.Get("a2"); err == nil {
+		t.Error("deleted agent should not be reloaded")
+	}
+}
+
+func TestRegistryWithStore_SkipsCorruptRecords(t *testing.T) {
+	store := storage.NewMemoryStore()
+	_ = store.Put(agentsNamespace, "bad", []byte("not json"))
+
+	r := NewRegistryWithStore(store, testLogger())
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(r.List()) != 0 {
+		t.Errorf("expected corrupt record to be skipped, got %d agents", len(r.List()))
+	}
+}
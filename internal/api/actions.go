@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// handleAgentActions handles GET /api/agents/{id}/actions?limit=50, returning
+// the agent's local action log newest first.
+func (s *Server) handleAgentActions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agentID := extractAgentIDForFirewall(r.URL.Path, "/actions")
+	if agentID == "" {
+		http.Error(w, "agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	if s.orch == nil || s.orch.ActionLog() == nil {
+		http.Error(w, "action log not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	actions, err := s.orch.ActionLog().Recent(agentID, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.respondJSON(w, map[string]interface{}{
+		"agent_id": agentID,
+		"actions":  actions,
+	})
+}
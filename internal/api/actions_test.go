@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/agents"
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/models"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+func TestHandleAgentActions(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	registry, _ := agents.NewRegistry(tmpDir, logger)
+	memory, _ := agents.NewMemoryStore(tmpDir, logger)
+	orch := orchestrator.New(&config.Config{}, logger)
+	s := NewServer(8420, orch, registry, memory, models.NewRouter(logger), logger)
+
+	// No action log configured yet
+	w := httptest.NewRecorder()
+	s.handleAgentActions(w, httptest.NewRequest("GET", "/api/agents/a1/actions", nil))
+	if w.Code != 503 {
+		t.Errorf("without log: status = %d, want 503", w.Code)
+	}
+
+	log, err := orchestrator.NewActionLog(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	orch.SetActionLog(log)
+	for _, desc := range []string{"first", "second", "third"} {
+		if err := log.Append(orchestrator.ActionRecord{AgentID: "a1", Type: "chat", Description: desc, Success: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	s.handleAgentActions(w, httptest.NewRequest("GET", "/api/agents/a1/actions?limit=2", nil))
+	if w.Code != 200 {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		AgentID string                      `json:"agent_id"`
+		Actions []orchestrator.ActionRecord `json:"actions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.AgentID != "a1" || len(resp.Actions) != 2 {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if resp.Actions[0].Description != "third" || resp.Actions[1].Description != "second" {
+		t.Errorf("actions = %+v, want newest first", resp.Actions)
+	}
+
+	for _, tc := range []struct {
+		method, path string
+		want         int
+	}{
+		{"POST", "/api/agents/a1/actions", 405},
+		{"GET", "/api/agents/a1/actions?limit=0", 400},
+		{"GET", "/api/agents/a1/actions?limit=abc", 400},
+		{"GET", "/api/agents//actions", 400},
+	} {
+		w := httptest.NewRecorder()
+		s.handleAgentActions(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.want {
+			t.Errorf("%s %s: status = %d, want %d", tc.method, tc.path, w.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/agents"
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/models"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+func newLifecycleTestServer(t *testing.T) (*Server, string) {
+	tmpDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	registry, _ := agents.NewRegistry(tmpDir, logger)
+	memory, _ := agents.NewMemoryStore(tmpDir, logger)
+	router := models.NewRouter(logger)
+	orch := orchestrator.New(config.DefaultConfig(), logger)
+
+	return NewServer(8420, orch, registry, memory, router, logger), tmpDir
+}
+
+func TestHandleAgentsCreate(t *testing.T) {
+	s, dataDir := newLifecycleTestServer(t)
+
+	body := `{"id":"runtime-1","name":"Runtime","type":"monitor","model":"ollama/llama3"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/agents", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	s.handleAgents(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := s.registry.Get("runtime-1"); err != nil {
+		t.Errorf("expected agent in registry: %v", err)
+	}
+	if s.orch.GetAgentInfo("runtime-1") == nil {
+		t.Error("expected agent in orchestrator")
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "agents", "runtime-1.json")); err != nil {
+		t.Errorf("expected agent to be persisted: %v", err)
+	}
+}
+
+func TestHandleAgentsCreateDuplicate(t *testing.T) {
+	s, _ := newLifecycleTestServer(t)
+
+	body := `{"id":"dup","type":"monitor"}`
+	for i, want := range []int{http.StatusCreated, http.StatusConflict} {
+		req := httptest.NewRequest(http.MethodPost, "/api/agents", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		s.handleAgents(w, req)
+		if w.Code != want {
+			t.Errorf("request %d: expected %d, got %d", i, want, w.Code)
+		}
+	}
+	if n := len(s.registry.List()); n != 1 {
+		t.Errorf("expected 1 agent, got %d", n)
+	}
+}
+
+func TestHandleAgentsCreateFromTemplate(t *testing.T) {
+	s, _ := newLifecycleTestServer(t)
+
+	// No body: the template's own ID is used.
+	req := httptest.NewRequest(http.MethodPost, "/api/agents?template=support", nil)
+	w := httptest.NewRecorder()
+	s.handleAgents(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := s.registry.Get("support"); err != nil {
+		t.Errorf("expected templated agent in registry: %v", err)
+	}
+
+	// Body fields override the template.
+	body := `{"id":"desk-1","model":"ollama/llama3"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/agents?template=trader", bytes.NewBufferString(body))
+	w = httptest.NewRecorder()
+	s.handleAgents(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	agent, err := s.registry.Get("desk-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agent.Def.Type != "trader" || agent.Def.Model != "ollama/llama3" || agent.Def.Genome == nil {
+		t.Errorf("unexpected def: %+v", agent.Def)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/agents?template=pirate", nil)
+	w = httptest.NewRecorder()
+	s.handleAgents(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown template: expected 400, got %d", w.Code)
+	}
+}
+
+func TestHandleAgentsCreateInvalid(t *testing.T) {
+	s, _ := newLifecycleTestServer(t)
+
+	for _, body := range []string{"not json", `{"name":"no id"}`, `{"id":"a/b"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/agents", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		s.handleAgents(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("body %q: expected 400, got %d", body, w.Code)
+		}
+	}
+}
+
+func TestHandleAgentDelete(t *testing.T) {
+	s, dataDir := newLifecycleTestServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/agents", bytes.NewBufferString(`{"id":"doomed"}`))
+	w := httptest.NewRecorder()
+	s.handleAgents(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/agents/doomed", nil)
+	w = httptest.NewRecorder()
+	s.handleAgentDetail(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := s.registry.Get("doomed"); err == nil {
+		t.Error("expected agent removed from registry")
+	}
+	if s.orch.GetAgentInfo("doomed") != nil {
+		t.Error("expected agent removed from orchestrator")
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "agents", "doomed.json")); !os.IsNotExist(err) {
+		t.Errorf("expected agent file removed, got %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/agents/doomed", nil)
+	w = httptest.NewRecorder()
+	s.handleAgentDetail(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("second delete: expected 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// Limits for POST /api/chat/batch.
+const (
+	maxBatchBodyBytes       = 8 << 20
+	maxBatchItems           = 100
+	maxBatchConcurrency     = 8
+	defaultBatchConcurrency = 4
+)
+
+// BatchChatRequest is the JSON body for POST /api/chat/batch. Each prompt
+// is sent to the agent on its own, without conversation history, and
+// nothing is written to chat memory.
+type BatchChatRequest struct {
+	AgentID string   `json:"agent_id"`
+	Prompts []string `json:"prompts"`
+	// Concurrency is how many prompts run at once (default 4, max 8).
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// validate checks the fields of a decoded batch request. The error
+// message is safe to return to the client.
+func (req *BatchChatRequest) validate() error {
+	switch {
+	case req.AgentID == "":
+		return fmt.Errorf("agent_id is required")
+	case utf8.RuneCountInString(req.AgentID) > maxChatIDChars:
+		return fmt.Errorf("agent_id is longer than %d characters", maxChatIDChars)
+	case len(req.Prompts) == 0:
+		return fmt.Errorf("prompts is required")
+	case len(req.Prompts) > maxBatchItems:
+		return fmt.Errorf("batch has %d prompts, the limit is %d", len(req.Prompts), maxBatchItems)
+	case req.Concurrency < 0:
+		return fmt.Errorf("concurrency must not be negative")
+	}
+	for i, p := range req.Prompts {
+		if p == "" {
+			return fmt.Errorf("prompts[%d] is empty", i)
+		}
+		if utf8.RuneCountInString(p) > maxChatMessageChars {
+			return fmt.Errorf("prompts[%d] is longer than %d characters", i, maxChatMessageChars)
+		}
+	}
+	return nil
+}
+
+// BatchChatResult is the answer to one prompt of a batch. Error is set
+// instead of the answer fields when the prompt failed.
+type BatchChatResult struct {
+	Index        int      `json:"index"`
+	Response     string   `json:"response,omitempty"`
+	Model        string   `json:"model,omitempty"`
+	ElapsedMs    int64    `json:"elapsed_ms"`
+	LatencyMs    int64    `json:"latency_ms"`
+	TokensInput  int      `json:"tokens_input"`
+	TokensOutput int      `json:"tokens_output"`
+	CostUSD      float64  `json:"cost_usd"`
+	Cached       bool     `json:"cached,omitempty"`
+	Violations   []string `json:"violations,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// BatchChatSummary aggregates the results of a batch. Latencies are over
+// the prompts that succeeded.
+type BatchChatSummary struct {
+	Count         int     `json:"count"`
+	Failed        int     `json:"failed"`
+	ElapsedMs     int64   `json:"elapsed_ms"`
+	TokensInput   int     `json:"tokens_input"`
+	TokensOutput  int     `json:"tokens_output"`
+	CostUSD       float64 `json:"cost_usd"`
+	MeanLatencyMs int64   `json:"mean_latency_ms"`
+	MaxLatencyMs  int64   `json:"max_latency_ms"`
+}
+
+// BatchChatResponseJSON is the JSON response for POST /api/chat/batch.
+// Results are in the order of the request's prompts.
+type BatchChatResponseJSON struct {
+	AgentID     string            `json:"agent_id"`
+	Concurrency int               `json:"concurrency"`
+	Results     []BatchChatResult `json:"results"`
+	Summary     BatchChatSummary  `json:"summary"`
+}
+
+// handleChatBatch handles POST /api/chat/batch — answers a list of prompts
+// with bounded concurrency, for evaluating an agent against a test set.
+func (s *Server) handleChatBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodyBytes)
+	var req BatchChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			WriteError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := s.registry.Get(req.AgentID); err != nil {
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("agent %q not found", req.AgentID))
+		return
+	}
+
+	concurrency := req.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	if concurrency > maxBatchConcurrency {
+		concurrency = maxBatchConcurrency
+	}
+
+	start := time.Now()
+	results := make([]BatchChatResult, len(req.Prompts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, prompt := range req.Prompts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, prompt string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.runBatchPrompt(r, req.AgentID, i, prompt)
+		}(i, prompt)
+	}
+	wg.Wait()
+
+	s.respondJSON(w, BatchChatResponseJSON{
+		AgentID:     req.AgentID,
+		Concurrency: concurrency,
+		Results:     results,
+		Summary:     summarizeBatch(results, time.Since(start)),
+	})
+}
+
+// runBatchPrompt answers the batch prompt at index i.
+func (s *Server) runBatchPrompt(r *http.Request, agentID string, i int, prompt string) BatchChatResult {
+	result := BatchChatResult{Index: i}
+	if err := r.Context().Err(); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	resp, err := s.orch.ChatSync(r.Context(), orchestrator.ChatSyncRequest{
+		AgentID: agentID,
+		UserID:  "batch",
+		Message: prompt,
+	})
+	if err != nil {
+		s.logger.Warn("batch chat prompt failed", "agent", agentID, "index", i, "error", err)
+		result.Error = err.Error()
+		return result
+	}
+	result.Response = resp.Response
+	result.Model = resp.Model
+	result.ElapsedMs = resp.ElapsedMs
+	result.LatencyMs = resp.LatencyMs
+	result.TokensInput = resp.TokensInput
+	result.TokensOutput = resp.TokensOutput
+	result.CostUSD = resp.CostUSD
+	result.Cached = resp.Cached
+	result.Violations = resp.Violations
+	return result
+}
+
+// summarizeBatch totals results; elapsed is the wall time of the batch.
+func summarizeBatch(results []BatchChatResult, elapsed time.Duration) BatchChatSummary {
+	sum := BatchChatSummary{Count: len(results), ElapsedMs: elapsed.Milliseconds()}
+	var latency int64
+	for _, res := range results {
+		if res.Error != "" {
+			sum.Failed++
+			continue
+		}
+		sum.TokensInput += res.TokensInput
+		sum.TokensOutput += res.TokensOutput
+		sum.CostUSD += res.CostUSD
+		latency += res.LatencyMs
+		if res.LatencyMs > sum.MaxLatencyMs {
+			sum.MaxLatencyMs = res.LatencyMs
+		}
+	}
+	if ok := sum.Count - sum.Failed; ok > 0 {
+		sum.MeanLatencyMs = latency / int64(ok)
+	}
+	return sum
+}
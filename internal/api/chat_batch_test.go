@@ -0,0 +1,151 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// echoProvider echoes the prompt back after a delay that shrinks with each
+// call, so later prompts tend to finish first, and tracks how many calls
+// run at once.
+type echoProvider struct {
+	mu       sync.Mutex
+	calls    int
+	inFlight int
+	peak     int
+}
+
+func (p *echoProvider) Name() string { return "test-provider" }
+
+func (p *echoProvider) Models() []config.Model {
+	return []config.Model{{ID: "model-1", Name: "Test Model", ContextWindow: 4096}}
+}
+
+func (p *echoProvider) Chat(ctx context.Context, req orchestrator.ChatRequest) (*orchestrator.ChatResponse, error) {
+	p.mu.Lock()
+	p.calls++
+	p.inFlight++
+	if p.inFlight > p.peak {
+		p.peak = p.inFlight
+	}
+	delay := time.Duration(20-p.calls%10) * time.Millisecond
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.inFlight--
+		p.mu.Unlock()
+	}()
+
+	time.Sleep(delay)
+	last := req.Messages[len(req.Messages)-1].Content
+	return &orchestrator.ChatResponse{Content: "echo: " + last, Model: req.Model, TokensInput: 10, TokensOutput: 5}, nil
+}
+
+func postBatch(t *testing.T, s *Server, body BatchChatRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	data, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	s.handleChatBatch(w, httptest.NewRequest(http.MethodPost, "/api/chat/batch", bytes.NewReader(data)))
+	return w
+}
+
+func TestHandleChatBatch(t *testing.T) {
+	s := newTestChatServer(t)
+	provider := &echoProvider{}
+	s.orch.RegisterProvider(provider)
+
+	prompts := []string{"p0", "p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8", "p9"}
+	w := postBatch(t, s, BatchChatRequest{AgentID: "test-agent", Prompts: prompts, Concurrency: 3})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp BatchChatResponseJSON
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(resp.Results) != len(prompts) {
+		t.Fatalf("got %d results, want %d", len(resp.Results), len(prompts))
+	}
+	for i, res := range resp.Results {
+		if res.Index != i || res.Response != "echo: "+prompts[i] || res.Error != "" {
+			t.Errorf("result %d = %+v, want the answer to %q", i, res, prompts[i])
+		}
+	}
+	if resp.Concurrency != 3 {
+		t.Errorf("concurrency = %d, want 3", resp.Concurrency)
+	}
+	if provider.peak > 3 {
+		t.Errorf("%d prompts ran at once, want at most 3", provider.peak)
+	}
+	if provider.calls != len(prompts) {
+		t.Errorf("provider called %d times, want %d", provider.calls, len(prompts))
+	}
+	if sum := resp.Summary; sum.Count != 10 || sum.Failed != 0 || sum.TokensInput != 100 || sum.TokensOutput != 50 {
+		t.Errorf("unexpected summary %+v", sum)
+	}
+
+	// Batch prompts don't touch chat memory
+	if n := len(s.memory.Get("test-agent").GetRecentMessages(10)); n != 0 {
+		t.Errorf("batch wrote %d messages to chat memory", n)
+	}
+}
+
+func TestHandleChatBatch_ConcurrencyCapped(t *testing.T) {
+	s := newTestChatServer(t)
+	provider := &echoProvider{}
+	s.orch.RegisterProvider(provider)
+
+	prompts := make([]string, 20)
+	for i := range prompts {
+		prompts[i] = "prompt"
+	}
+	w := postBatch(t, s, BatchChatRequest{AgentID: "test-agent", Prompts: prompts, Concurrency: 50})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if provider.peak > maxBatchConcurrency {
+		t.Errorf("%d prompts ran at once, want at most %d", provider.peak, maxBatchConcurrency)
+	}
+}
+
+func TestHandleChatBatch_Validation(t *testing.T) {
+	s := newTestChatServer(t)
+
+	tooMany := make([]string, maxBatchItems+1)
+	for i := range tooMany {
+		tooMany[i] = "x"
+	}
+	for name, tt := range map[string]struct {
+		body BatchChatRequest
+		want int
+	}{
+		"no agent":      {BatchChatRequest{Prompts: []string{"hi"}}, http.StatusBadRequest},
+		"no prompts":    {BatchChatRequest{AgentID: "test-agent"}, http.StatusBadRequest},
+		"empty prompt":  {BatchChatRequest{AgentID: "test-agent", Prompts: []string{"hi", ""}}, http.StatusBadRequest},
+		"too many":      {BatchChatRequest{AgentID: "test-agent", Prompts: tooMany}, http.StatusBadRequest},
+		"unknown agent": {BatchChatRequest{AgentID: "ghost", Prompts: []string{"hi"}}, http.StatusNotFound},
+	} {
+		if w := postBatch(t, s, tt.body); w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d: %s", name, w.Code, tt.want, w.Body.String())
+		}
+	}
+}
+
+func TestHandleChatBatch_MethodNotAllowed(t *testing.T) {
+	s := newTestChatServer(t)
+	w := httptest.NewRecorder()
+	s.handleChatBatch(w, httptest.NewRequest(http.MethodGet, "/api/chat/batch", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405", w.Code)
+	}
+}
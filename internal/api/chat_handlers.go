@@ -2,19 +2,55 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"time"
+	"unicode/utf8"
 
 	"github.com/clawinfra/evoclaw/internal/orchestrator"
 )
 
+// Limits for POST /api/chat. The body limit is server.maxChatBodyBytes.
+const (
+	defaultMaxChatBodyBytes = 1 << 20
+	maxChatMessageChars     = 32000
+	maxChatIDChars          = 128
+)
+
 // ChatRequest is the JSON body for POST /api/chat
 type ChatRequest struct {
 	AgentID        string `json:"agent_id"`
 	Message        string `json:"message"`
 	ConversationID string `json:"conversation_id,omitempty"`
+	// Agent is accepted as an alias for AgentID (sent by the web terminal
+	// and the TUI).
+	Agent string `json:"agent,omitempty"`
+}
+
+// validate checks the fields of a decoded chat request, folding Agent
+// into AgentID. The error message is safe to return to the client.
+func (req *ChatRequest) validate() error {
+	if req.AgentID == "" {
+		req.AgentID = req.Agent
+	} else if req.Agent != "" && req.Agent != req.AgentID {
+		return fmt.Errorf("agent and agent_id disagree")
+	}
+
+	switch {
+	case req.AgentID == "":
+		return fmt.Errorf("agent_id is required")
+	case utf8.RuneCountInString(req.AgentID) > maxChatIDChars:
+		return fmt.Errorf("agent_id is longer than %d characters", maxChatIDChars)
+	case req.Message == "":
+		return fmt.Errorf("message is required")
+	case utf8.RuneCountInString(req.Message) > maxChatMessageChars:
+		return fmt.Errorf("message is longer than %d characters", maxChatMessageChars)
+	case utf8.RuneCountInString(req.ConversationID) > maxChatIDChars:
+		return fmt.Errorf("conversation_id is longer than %d characters", maxChatIDChars)
+	}
+	return nil
 }
 
 // ChatResponseJSON is the JSON response for POST /api/chat
@@ -26,6 +62,17 @@ type ChatResponseJSON struct {
 	TokensInput  int    `json:"tokens_input"`
 	TokensOutput int    `json:"tokens_output"`
 	Timestamp    string `json:"timestamp"`
+	// CostUSD is what the answer was charged; LatencyMs is the part of
+	// ElapsedMs spent waiting for the model.
+	CostUSD   float64 `json:"cost_usd"`
+	LatencyMs int64   `json:"latency_ms"`
+	// Violations lists the genome's forbidden patterns the response matched.
+	Violations []string `json:"violations,omitempty"`
+	// Cached is set when the answer came from the response cache.
+	Cached bool `json:"cached,omitempty"`
+	// Reasoning is the model's thinking, only with models.reasoning "store".
+	Reasoning       string `json:"reasoning,omitempty"`
+	ReasoningTokens int    `json:"reasoning_tokens,omitempty"`
 }
 
 // ChatHistoryEntry represents a single chat message in history
@@ -42,28 +89,8 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req ChatRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if req.Message == "" {
-		http.Error(w, "message is required", http.StatusBadRequest)
-		return
-	}
-
-	if req.AgentID == "" {
-		agents := s.registry.List()
-		if len(agents) == 0 {
-			http.Error(w, "no agents available", http.StatusServiceUnavailable)
-			return
-		}
-		req.AgentID = agents[0].ID
-	}
-
-	if _, err := s.registry.Get(req.AgentID); err != nil {
-		http.Error(w, "agent not found", http.StatusNotFound)
+	req, ok := s.readChatRequest(w, r)
+	if !ok {
 		return
 	}
 
@@ -73,20 +100,12 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 	}
 
 	mem := s.memory.Get(convKey)
-	history := mem.GetRecentMessages(20)
-
-	chatReq := orchestrator.ChatSyncRequest{
-		AgentID:        req.AgentID,
-		UserID:         "dashboard",
-		Message:        req.Message,
-		ConversationID: req.ConversationID,
-		History:        history,
-	}
+	chatReq := s.chatSyncRequest(req)
 
 	resp, err := s.orch.ChatSync(r.Context(), chatReq)
 	if err != nil {
 		s.logger.Error("chat sync error", "agent", req.AgentID, "error", err)
-		http.Error(w, fmt.Sprintf("chat error: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("chat error: %v", err), chatErrorStatus(err))
 		return
 	}
 
@@ -104,9 +123,142 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 		TokensInput:  resp.TokensInput,
 		TokensOutput: resp.TokensOutput,
 		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+		CostUSD:      resp.CostUSD,
+		LatencyMs:    resp.LatencyMs,
+		Violations:   resp.Violations,
+		Cached:       resp.Cached,
+
+		Reasoning:       resp.Reasoning,
+		ReasoningTokens: resp.ReasoningTokens,
+	})
+}
+
+// readChatRequest decodes and validates a chat request body and checks the
+// agent exists. On failure it writes the error response and returns false.
+func (s *Server) readChatRequest(w http.ResponseWriter, r *http.Request) (ChatRequest, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxChatBodyBytes())
+	var req ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			WriteError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+			return req, false
+		}
+		WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return req, false
+	}
+
+	if err := req.validate(); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return req, false
+	}
+
+	if _, err := s.registry.Get(req.AgentID); err != nil {
+		if len(s.registry.List()) == 0 {
+			WriteError(w, http.StatusServiceUnavailable, "no agents available")
+			return req, false
+		}
+		WriteError(w, http.StatusNotFound, fmt.Sprintf("agent %q not found", req.AgentID))
+		return req, false
+	}
+	return req, true
+}
+
+// chatSyncRequest builds the orchestrator request for req, with the last
+// 20 turns of its conversation as history.
+func (s *Server) chatSyncRequest(req ChatRequest) orchestrator.ChatSyncRequest {
+	convKey := req.AgentID
+	if req.ConversationID != "" {
+		convKey = req.AgentID + ":" + req.ConversationID
+	}
+	return orchestrator.ChatSyncRequest{
+		AgentID:        req.AgentID,
+		UserID:         "dashboard",
+		Message:        req.Message,
+		ConversationID: req.ConversationID,
+		History:        s.memory.Get(convKey).GetRecentMessages(20),
+	}
+}
+
+// ChatPreviewJSON is the JSON response for POST /api/chat/preview
+type ChatPreviewJSON struct {
+	AgentID      string                     `json:"agent_id"`
+	Model        string                     `json:"model"`
+	Provider     string                     `json:"provider"`
+	SystemPrompt string                     `json:"system_prompt"`
+	Messages     []orchestrator.ChatMessage `json:"messages"`
+	MaxTokens    int                        `json:"max_tokens,omitempty"`
+	Temperature  float64                    `json:"temperature,omitempty"`
+	TopP         float64                    `json:"top_p,omitempty"`
+	// RequestModel is the model ID as sent to the provider, without the
+	// provider prefix.
+	RequestModel string `json:"request_model"`
+}
+
+// handleChatPreview handles POST /api/chat/preview — takes the same body
+// as POST /api/chat and returns the provider request it would send,
+// without calling the model.
+func (s *Server) handleChatPreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, ok := s.readChatRequest(w, r)
+	if !ok {
+		return
+	}
+
+	preview, err := s.orch.PreviewChat(r.Context(), s.chatSyncRequest(req))
+	if err != nil {
+		WriteError(w, chatErrorStatus(err), err.Error())
+		return
+	}
+
+	s.respondJSON(w, ChatPreviewJSON{
+		AgentID:      preview.AgentID,
+		Model:        preview.Model,
+		Provider:     preview.Provider,
+		SystemPrompt: preview.Request.SystemPrompt,
+		Messages:     preview.Request.Messages,
+		MaxTokens:    preview.Request.MaxTokens,
+		Temperature:  preview.Request.Temperature,
+		TopP:         preview.Request.TopP,
+		RequestModel: preview.Request.Model,
 	})
 }
 
+// maxChatBodyBytes returns server.maxChatBodyBytes or the default.
+func (s *Server) maxChatBodyBytes() int64 {
+	if s.orch != nil {
+		if cfg := s.orch.GetConfig(); cfg != nil && cfg.Server.MaxChatBodyBytes > 0 {
+			return cfg.Server.MaxChatBodyBytes
+		}
+	}
+	return defaultMaxChatBodyBytes
+}
+
+// chatErrorStatus maps a ChatSync error to an HTTP status.
+func chatErrorStatus(err error) int {
+	var failure *orchestrator.ErrProviderFailure
+	switch {
+	case errors.Is(err, orchestrator.ErrAgentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, orchestrator.ErrAgentPaused):
+		return http.StatusConflict
+	case errors.Is(err, orchestrator.ErrNoProvider),
+		errors.Is(err, orchestrator.ErrPinnedModelUnhealthy):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, orchestrator.ErrEdgeTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, orchestrator.ErrCostBudgetExceeded):
+		return http.StatusPaymentRequired
+	case errors.As(err, &failure):
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
 // handleChatHistory handles GET /api/chat/history?agent_id=...&limit=50
 func (s *Server) handleChatHistory(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -226,6 +378,8 @@ func (s *Server) handleChatStream(w http.ResponseWriter, r *http.Request) {
 		"elapsed_ms":    resp.ElapsedMs,
 		"tokens_input":  resp.TokensInput,
 		"tokens_output": resp.TokensOutput,
+		"cost_usd":      resp.CostUSD,
+		"latency_ms":    resp.LatencyMs,
 		"timestamp":     time.Now().UTC().Format(time.RFC3339),
 	})
 
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/clawinfra/evoclaw/internal/agents"
@@ -130,6 +133,43 @@ func TestHandleChat_Success(t *testing.T) {
 	}
 }
 
+func TestHandleChat_ReportsUsage(t *testing.T) {
+	s := newTestChatServer(t)
+	// $3 in / $15 out per million tokens
+	s.orch.GetConfig().Models.Providers = map[string]config.ProviderConfig{
+		"test-provider": {Models: []config.Model{{ID: "model-1", CostInput: 3, CostOutput: 15}}},
+	}
+
+	jsonBody, _ := json.Marshal(ChatRequest{AgentID: "test-agent", Message: "Hello!"})
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", bytes.NewReader(jsonBody))
+	w := httptest.NewRecorder()
+	s.handleChat(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	for _, field := range []string{"tokens_input", "tokens_output", "cost_usd", "latency_ms"} {
+		if _, ok := raw[field]; !ok {
+			t.Errorf("response is missing %q: %s", field, w.Body.String())
+		}
+	}
+
+	if raw["tokens_input"] != 100.0 || raw["tokens_output"] != 50.0 {
+		t.Errorf("tokens = %v in / %v out, want 100 / 50", raw["tokens_input"], raw["tokens_output"])
+	}
+	// 100*3/1e6 + 50*15/1e6
+	if cost, _ := raw["cost_usd"].(float64); cost < 0.00104 || cost > 0.00106 {
+		t.Errorf("cost_usd = %v, want 0.00105", raw["cost_usd"])
+	}
+	if latency, elapsed := raw["latency_ms"].(float64), raw["elapsed_ms"].(float64); latency < 0 || latency > elapsed {
+		t.Errorf("latency_ms = %v, want between 0 and elapsed_ms %v", latency, elapsed)
+	}
+}
+
 func TestHandleChat_MethodNotAllowed(t *testing.T) {
 	s := newTestChatServer(t)
 
@@ -197,23 +237,115 @@ func TestHandleChat_AgentNotFound(t *testing.T) {
 	}
 }
 
-func TestHandleChat_DefaultAgent(t *testing.T) {
+func TestHandleChat_AgentAlias(t *testing.T) {
 	s := newTestChatServer(t)
 
-	body := ChatRequest{
-		AgentID: "", // empty = use first agent
-		Message: "Hello!",
+	// The web terminal and TUI send "agent" rather than "agent_id".
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{"agent":"test-agent","message":"Hello!","from":"web-terminal"}`))
+	w := httptest.NewRecorder()
+	s.handleChat(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %s", w.Code, w.Body.String())
 	}
-	jsonBody, _ := json.Marshal(body)
+}
 
-	req := httptest.NewRequest(http.MethodPost, "/api/chat", bytes.NewReader(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
+func TestHandleChatPreview(t *testing.T) {
+	s := newTestChatServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/chat/preview", strings.NewReader(`{"agent_id":"test-agent","message":"Hello!"}`))
 	w := httptest.NewRecorder()
+	s.handleChatPreview(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
 
-	s.handleChat(w, req)
+	var preview ChatPreviewJSON
+	if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if preview.Model != "test-provider/model-1" || preview.Provider != "test-provider" || preview.RequestModel != "model-1" {
+		t.Errorf("preview = %+v", preview)
+	}
+	if !strings.Contains(preview.SystemPrompt, "You are a test agent") {
+		t.Errorf("system_prompt = %q", preview.SystemPrompt)
+	}
+	if n := len(preview.Messages); n == 0 || preview.Messages[n-1].Content != "Hello!" {
+		t.Errorf("messages = %+v, want the message last", preview.Messages)
+	}
+
+	// A preview is not a turn of the conversation.
+	if n := len(s.memory.Get("test-agent").GetRecentMessages(20)); n != 0 {
+		t.Errorf("conversation has %d messages after a preview, want 0", n)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/chat/preview", nil)
+	w = httptest.NewRecorder()
+	s.handleChatPreview(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want 405", w.Code)
+	}
+}
+
+func TestHandleChat_Validation(t *testing.T) {
+	s := newTestChatServer(t)
 
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{"missing agent", `{"message":"hi"}`, http.StatusBadRequest, "agent_id is required"},
+		{"missing message", `{"agent_id":"test-agent"}`, http.StatusBadRequest, "message is required"},
+		{"conflicting agent", `{"agent_id":"test-agent","agent":"other","message":"hi"}`, http.StatusBadRequest, "agent and agent_id disagree"},
+		{"long agent id", `{"agent_id":"` + strings.Repeat("a", maxChatIDChars+1) + `","message":"hi"}`, http.StatusBadRequest, "agent_id is longer than"},
+		{"long message", `{"agent_id":"test-agent","message":"` + strings.Repeat("x", maxChatMessageChars+1) + `"}`, http.StatusBadRequest, "message is longer than"},
+		{"long conversation id", `{"agent_id":"test-agent","message":"hi","conversation_id":"` + strings.Repeat("c", maxChatIDChars+1) + `"}`, http.StatusBadRequest, "conversation_id is longer than"},
+		{"wrong type", `{"agent_id":"test-agent","message":42}`, http.StatusBadRequest, "invalid request body"},
+		{"unknown agent", `{"agent_id":"ghost","message":"hi"}`, http.StatusNotFound, `agent "ghost" not found`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.handleChat(w, httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			var resp struct {
+				Error string `json:"error"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("error body is not JSON: %v", err)
+			}
+			if !strings.Contains(resp.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to contain %q", resp.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestHandleChat_OversizedBody(t *testing.T) {
+	s := newTestChatServer(t)
+	s.orch.GetConfig().Server.MaxChatBodyBytes = 256
+
+	body := `{"agent_id":"test-agent","message":"` + strings.Repeat("x", 1024) + `"}`
+	w := httptest.NewRecorder()
+	s.handleChat(w, httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(body)))
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "request body exceeds 256 bytes") {
+		t.Errorf("body = %s", w.Body.String())
+	}
+
+	// A body under the limit still goes through.
+	w = httptest.NewRecorder()
+	s.handleChat(w, httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{"agent_id":"test-agent","message":"hi"}`)))
 	if w.Code != http.StatusOK {
-		t.Errorf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		t.Errorf("small body: status = %d: %s", w.Code, w.Body.String())
 	}
 }
 
@@ -339,3 +471,23 @@ func TestHandleChatStream_AgentNotFound(t *testing.T) {
 		t.Errorf("expected status 404, got %d", w.Code)
 	}
 }
+
+func TestChatErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("%w: ghost", orchestrator.ErrAgentNotFound), http.StatusNotFound},
+		{fmt.Errorf("%w: busy", orchestrator.ErrAgentPaused), http.StatusConflict},
+		{fmt.Errorf("%w: x/y", orchestrator.ErrNoProvider), http.StatusServiceUnavailable},
+		{fmt.Errorf("%w: a1", orchestrator.ErrPinnedModelUnhealthy), http.StatusServiceUnavailable},
+		{fmt.Errorf("%w from pi", orchestrator.ErrEdgeTimeout), http.StatusGatewayTimeout},
+		{&orchestrator.ErrProviderFailure{Provider: "p", Model: "p/m", Err: errors.New("boom")}, http.StatusBadGateway},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := chatErrorStatus(tt.err); got != tt.want {
+			t.Errorf("chatErrorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
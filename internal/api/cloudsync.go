@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/clawinfra/evoclaw/internal/cloudsync"
+)
+
+// handleCloudSyncStatus handles GET /api/cloudsync/status — whether Turso is
+// reachable, how many writes are queued and when the last sync succeeded.
+// Without cloud sync configured it reports {"enabled": false, ...}.
+func (s *Server) handleCloudSyncStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := cloudsync.Status{}
+	if s.orch != nil {
+		if st := s.orch.CloudSyncStatus(); st != nil {
+			status = *st
+		}
+	}
+	s.respondJSON(w, status)
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/cloudsync"
+)
+
+func TestHandleCloudSyncStatus_Unconfigured(t *testing.T) {
+	s := newTestChatServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cloudsync/status", nil)
+	w := httptest.NewRecorder()
+	s.handleCloudSyncStatus(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	var st cloudsync.Status
+	if err := json.NewDecoder(w.Body).Decode(&st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Enabled || st.Connected || st.QueueDepth != 0 {
+		t.Errorf("status = %+v, want zero value", st)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/cloudsync/status", nil)
+	w = httptest.NewRecorder()
+	s.handleCloudSyncStatus(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: status %d, want 405", w.Code)
+	}
+}
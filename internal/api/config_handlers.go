@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// maxConfigPatchBytes bounds a PATCH /api/config body.
+const maxConfigPatchBytes = 64 << 10
+
+// SetConfigFile enables PATCH /api/config, which writes changes to the
+// config file at path and reloads it. onReload, if set, is called with the
+// result of every successful update so the caller can act on it (e.g.
+// apply a new log level).
+func (s *Server) SetConfigFile(path string, onReload func(*config.ReloadResult)) {
+	s.configPath = path
+	s.onConfigReload = onReload
+}
+
+// runningConfig returns the orchestrator's config, or nil if there is none.
+func (s *Server) runningConfig() *config.Config {
+	if s.orch == nil {
+		return nil
+	}
+	return s.orch.GetConfig()
+}
+
+// handleConfig returns the running config with secrets redacted, or
+// updates the settings listed by config.PatchableKeys.
+// GET, PATCH /api/config
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := s.runningConfig()
+	if cfg == nil {
+		http.Error(w, "config not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		config.RLock()
+		redacted := cfg.Redacted()
+		config.RUnlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(redacted)
+	case http.MethodPatch:
+		s.patchConfig(w, r, cfg)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// patchConfig applies a flat {"<json path>": value} body, e.g.
+// {"models.routing.simple": "ollama/llama3"}.
+func (s *Server) patchConfig(w http.ResponseWriter, r *http.Request, cfg *config.Config) {
+	if s.configPath == "" {
+		http.Error(w, "config updates not available: no config file", http.StatusServiceUnavailable)
+		return
+	}
+
+	var updates map[string]json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxConfigPatchBytes)).Decode(&updates); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(updates) == 0 {
+		http.Error(w, "no settings to update", http.StatusBadRequest)
+		return
+	}
+
+	result, err := cfg.Patch(s.configPath, updates)
+	if err != nil {
+		var (
+			verrs   config.ValidationErrors
+			typeErr *json.UnmarshalTypeError
+			syntErr *json.SyntaxError
+		)
+		switch {
+		case errors.Is(err, config.ErrNotPatchable):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":     err.Error(),
+				"patchable": config.PatchableKeys(),
+			})
+		case errors.As(err, &verrs), errors.As(err, &typeErr), errors.As(err, &syntErr):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			s.logger.Error("config update failed", "error", err)
+			http.Error(w, "config update failed", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	result.LogResult(s.logger)
+	if s.onConfigReload != nil {
+		s.onConfigReload(result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"changed": nonNil(result.Changed),
+		"applied": nonNil(result.Applied),
+		"skipped": nonNil(result.Skipped),
+	})
+}
+
+// nonNil keeps empty lists as [] rather than null in responses.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/agents"
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/models"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+const (
+	testProviderKey  = "sk-test-provider-secret"
+	testMQTTPassword = "mqtt-test-password"
+)
+
+// newConfigTestServer returns a server whose orchestrator runs a config
+// holding secrets, saved to a config file that PATCH /api/config updates.
+func newConfigTestServer(t *testing.T) (*Server, *config.Config, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	cfg := config.DefaultConfig()
+	cfg.MQTT.Password = testMQTTPassword
+	cfg.Models.Providers = map[string]config.ProviderConfig{
+		"openai": {BaseURL: "https://api.openai.com/v1", APIKey: testProviderKey},
+	}
+	path := filepath.Join(tmpDir, "evoclaw.json")
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	registry, _ := agents.NewRegistry(tmpDir, logger)
+	memory, _ := agents.NewMemoryStore(tmpDir, logger)
+	router := models.NewRouter(logger)
+	orch := orchestrator.New(cfg, logger)
+
+	s := NewServer(8420, orch, registry, memory, router, logger)
+	s.SetConfigFile(path, nil)
+	return s, cfg, path
+}
+
+func patchConfigRequest(t *testing.T, s *Server, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPatch, "/api/config", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	s.handleConfig(w, req)
+	return w
+}
+
+func TestHandleConfigGetRedactsSecrets(t *testing.T) {
+	s, _, _ := newConfigTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+	w := httptest.NewRecorder()
+	s.handleConfig(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	for _, secret := range []string{testProviderKey, testMQTTPassword} {
+		if strings.Contains(body, secret) {
+			t.Errorf("response leaks secret %q", secret)
+		}
+	}
+
+	var got config.Config
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.Models.Providers["openai"].APIKey != config.RedactedValue {
+		t.Errorf("provider apiKey = %q, want redacted", got.Models.Providers["openai"].APIKey)
+	}
+	if got.MQTT.Password != config.RedactedValue {
+		t.Errorf("mqtt password = %q, want redacted", got.MQTT.Password)
+	}
+	if got.Models.Providers["openai"].BaseURL != "https://api.openai.com/v1" {
+		t.Errorf("non-secret baseUrl = %q, want it unchanged", got.Models.Providers["openai"].BaseURL)
+	}
+}
+
+func TestHandleConfigPatchWhitelistedKey(t *testing.T) {
+	s, cfg, path := newConfigTestServer(t)
+
+	var reloaded *config.ReloadResult
+	s.SetConfigFile(path, func(r *config.ReloadResult) { reloaded = r })
+
+	w := patchConfigRequest(t, s, `{"models.routing.simple": "openai/gpt-4o-mini", "evolution.enabled": false}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if cfg.Models.Routing.Simple != "openai/gpt-4o-mini" {
+		t.Errorf("running routing.simple = %q, want openai/gpt-4o-mini", cfg.Models.Routing.Simple)
+	}
+	if cfg.Evolution.Enabled {
+		t.Error("expected running evolution to be disabled")
+	}
+	if reloaded == nil {
+		t.Error("expected reload callback to be called")
+	}
+
+	saved, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("reload saved config: %v", err)
+	}
+	if saved.Models.Routing.Simple != "openai/gpt-4o-mini" {
+		t.Errorf("saved routing.simple = %q, want openai/gpt-4o-mini", saved.Models.Routing.Simple)
+	}
+	if saved.Models.Providers["openai"].APIKey != testProviderKey {
+		t.Error("expected saved config to keep the real provider key")
+	}
+}
+
+func TestHandleConfigPatchRejectsOtherKeys(t *testing.T) {
+	s, cfg, path := newConfigTestServer(t)
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	port := cfg.Server.Port
+
+	w := patchConfigRequest(t, s, `{"server.port": 9999, "models.routing.simple": "openai/gpt-4o-mini"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "server.port") {
+		t.Errorf("expected error to name the rejected key, got %s", w.Body.String())
+	}
+
+	if cfg.Server.Port != port {
+		t.Errorf("running port changed to %d", cfg.Server.Port)
+	}
+	if cfg.Models.Routing.Simple == "openai/gpt-4o-mini" {
+		t.Error("expected whitelisted key in a rejected patch not to be applied")
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("expected config file to be unchanged")
+	}
+}
+
+func TestHandleConfigPatchInvalidValue(t *testing.T) {
+	s, _, _ := newConfigTestServer(t)
+
+	w := patchConfigRequest(t, s, `{"models.defaults.temperature": "hot"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for wrong type, got %d", w.Code)
+	}
+
+	w = patchConfigRequest(t, s, `not json`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for bad body, got %d", w.Code)
+	}
+}
+
+func TestHandleConfigUnavailable(t *testing.T) {
+	s := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+	w := httptest.NewRecorder()
+	s.handleConfig(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a running config, got %d", w.Code)
+	}
+
+	s2, _, _ := newConfigTestServer(t)
+	s2.SetConfigFile("", nil)
+	w = patchConfigRequest(t, s2, `{"server.logLevel": "debug"}`)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a config file, got %d", w.Code)
+	}
+}
@@ -173,6 +173,45 @@ func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleEventStream streams orchestrator events (edge agent presence and
+// similar notifications) as Server-Sent Events.
+func (s *Server) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.orch == nil || s.orch.Events() == nil {
+		http.Error(w, "event bus not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := s.orch.Events().Subscribe(32)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-events:
+			s.sendSSE(w, flusher, ev)
+		}
+	}
+}
+
 // sendSSE writes a Server-Sent Event
 func (s *Server) sendSSE(w http.ResponseWriter, flusher http.Flusher, data interface{}) {
 	jsonData, err := json.Marshal(data)
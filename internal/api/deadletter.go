@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// handleDeadLetters handles GET /api/deadletter?limit=50&reason=no_agent,
+// returning dropped messages and responses newest first.
+func (s *Server) handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	if s.orch == nil || s.orch.DeadLetterLog() == nil {
+		http.Error(w, "dead-letter log not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	entries := s.orch.DeadLetterLog().Recent(r.URL.Query().Get("reason"), limit)
+	s.respondJSON(w, map[string]interface{}{
+		"count":   len(entries),
+		"entries": entries,
+	})
+}
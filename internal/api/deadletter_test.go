@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/agents"
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/models"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+func TestHandleDeadLetters(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	registry, _ := agents.NewRegistry(tmpDir, logger)
+	memory, _ := agents.NewMemoryStore(tmpDir, logger)
+	orch := orchestrator.New(&config.Config{}, logger)
+	s := NewServer(8420, orch, registry, memory, models.NewRouter(logger), logger)
+
+	w := httptest.NewRecorder()
+	s.handleDeadLetters(w, httptest.NewRequest("GET", "/api/deadletter", nil))
+	if w.Code != 503 {
+		t.Errorf("without log: status = %d, want 503", w.Code)
+	}
+
+	dl, err := orchestrator.NewDeadLetterLog("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orch.SetDeadLetterLog(dl)
+	for _, e := range []orchestrator.DeadLetter{
+		{Reason: orchestrator.DeadLetterNoAgent, MessageID: "m1"},
+		{Reason: orchestrator.DeadLetterUnknownChannel, MessageID: "r1"},
+		{Reason: orchestrator.DeadLetterNoAgent, MessageID: "m2"},
+	} {
+		_ = dl.Add(e)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleDeadLetters(w, httptest.NewRequest("GET", "/api/deadletter?reason=no_agent", nil))
+	if w.Code != 200 {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Count   int                       `json:"count"`
+		Entries []orchestrator.DeadLetter `json:"entries"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 2 || resp.Entries[0].MessageID != "m2" || resp.Entries[1].MessageID != "m1" {
+		t.Errorf("resp = %+v, want no_agent entries newest first", resp)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleDeadLetters(w, httptest.NewRequest("GET", "/api/deadletter?limit=1", nil))
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Count != 1 || resp.Entries[0].MessageID != "m2" {
+		t.Errorf("limited resp = %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleDeadLetters(w, httptest.NewRequest("GET", "/api/deadletter?limit=x", nil))
+	if w.Code != 400 {
+		t.Errorf("bad limit: status = %d, want 400", w.Code)
+	}
+	w = httptest.NewRecorder()
+	s.handleDeadLetters(w, httptest.NewRequest("POST", "/api/deadletter", nil))
+	if w.Code != 405 {
+		t.Errorf("POST: status = %d, want 405", w.Code)
+	}
+}
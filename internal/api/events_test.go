@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/agents"
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/models"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+func TestHandleEventStream(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	registry, _ := agents.NewRegistry(tmpDir, logger)
+	memory, _ := agents.NewMemoryStore(tmpDir, logger)
+	orch := orchestrator.New(&config.Config{}, logger)
+	s := NewServer(8420, orch, registry, memory, models.NewRouter(logger), logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest("GET", "/api/events", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		s.handleEventStream(w, req)
+		close(done)
+	}()
+
+	// Keep publishing until the handler has subscribed and the stream ends.
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				orch.Events().Publish(orchestrator.Event{Type: orchestrator.EventAgentOffline, AgentID: "edge-1"})
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("event stream did not close")
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, `"type":"agent.offline"`) || !strings.Contains(body, `"agent_id":"edge-1"`) {
+		t.Errorf("body = %q, want an agent.offline event", body)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestHandleEventStreamUnavailable(t *testing.T) {
+	s := newTestServer(t)
+	w := httptest.NewRecorder()
+	s.handleEventStream(w, httptest.NewRequest("GET", "/api/events", nil))
+	if w.Code != 503 {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// handleEvolutionRoutes dispatches /api/evolution/{id} and
+// /api/evolution/{id}/trigger.
+func (s *Server) handleEvolutionRoutes(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/evolution/"), "/")
+	agentID, action, _ := strings.Cut(path, "/")
+	if agentID == "" {
+		http.Error(w, "agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	switch action {
+	case "":
+		s.handleEvolutionStatus(w, r, agentID)
+	case "trigger":
+		s.handleEvolutionTrigger(w, r, agentID)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+// handleEvolutionStatus handles GET /api/evolution/{id} — the agent's current
+// strategy, per-skill fitness, divergence and last evolution event.
+func (s *Server) handleEvolutionStatus(w http.ResponseWriter, r *http.Request, agentID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.orch == nil {
+		http.Error(w, "orchestrator not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	status, err := s.orch.EvolutionStatus(agentID)
+	switch {
+	case errors.Is(err, orchestrator.ErrEvolutionDisabled):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	s.respondJSON(w, status)
+}
+
+// handleEvolutionTrigger handles POST /api/evolution/{id}/trigger — runs an
+// evaluation and mutation cycle now. The agent needs
+// evolution.minSamplesForEval actions unless ?force=true.
+func (s *Server) handleEvolutionTrigger(w http.ResponseWriter, r *http.Request, agentID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.rejectInSafeMode(w) {
+		return
+	}
+	if s.orch == nil {
+		http.Error(w, "orchestrator not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	force := false
+	if v := r.URL.Query().Get("force"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "force must be true or false", http.StatusBadRequest)
+			return
+		}
+		force = b
+	}
+
+	if _, err := s.orch.EvolutionStatus(agentID); err != nil {
+		if errors.Is(err, orchestrator.ErrEvolutionDisabled) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		} else {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		}
+		return
+	}
+
+	strategy, err := s.orch.TriggerEvolution(agentID, force)
+	if err != nil {
+		// Too few samples, a cool-off, the firewall or a missing strategy:
+		// the cycle was refused rather than failed.
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	s.logger.Info("evolution triggered", "agent", agentID, "force", force)
+	s.respondJSON(w, map[string]interface{}{
+		"agent_id": agentID,
+		"forced":   force,
+		"strategy": strategy,
+	})
+}
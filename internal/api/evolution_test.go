@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/evolution"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+func newTestEvolutionServer(t *testing.T) (*Server, *evolution.Engine) {
+	t.Helper()
+	s := newTestChatServer(t)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	eng := evolution.NewEngine(t.TempDir(), logger)
+	eng.SetStrategy("test-agent", &evolution.Strategy{
+		ID:          "test-agent-v1",
+		Version:     1,
+		Temperature: 0.7,
+		Params:      map[string]float64{"threshold": 0.5},
+	})
+	s.orch.SetEvolutionEngine(eng)
+	s.orch.GetConfig().Evolution.MinSamplesForEval = 5
+	return s, eng
+}
+
+func TestEvolutionStatus(t *testing.T) {
+	s, _ := newTestEvolutionServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleEvolutionRoutes(w, httptest.NewRequest(http.MethodGet, "/api/evolution/test-agent", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		AgentID  string             `json:"agent_id"`
+		Strategy evolution.Strategy `json:"strategy"`
+		Skills   map[string]float64 `json:"skill_fitness"`
+		Samples  int64              `json:"samples"`
+		Min      int                `json:"min_samples"`
+		Last     *json.RawMessage   `json:"last_event"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.AgentID != "test-agent" || resp.Strategy.Version != 1 || resp.Min != 5 {
+		t.Errorf("response = %+v", resp)
+	}
+	if resp.Skills == nil || resp.Last != nil {
+		t.Errorf("skills = %v, last event = %v; want empty skills and no event yet", resp.Skills, resp.Last)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleEvolutionRoutes(w, httptest.NewRequest(http.MethodGet, "/api/evolution/ghost", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown agent: status = %d, want 404", w.Code)
+	}
+}
+
+func TestEvolutionTrigger_RespectsMinSamples(t *testing.T) {
+	s, eng := newTestEvolutionServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleEvolutionRoutes(w, httptest.NewRequest(http.MethodPost, "/api/evolution/test-agent/trigger", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409 with no samples: %s", w.Code, w.Body.String())
+	}
+	if v := eng.GetStrategy("test-agent").(*evolution.Strategy).Version; v != 1 {
+		t.Errorf("strategy version = %d, want unchanged", v)
+	}
+}
+
+func TestEvolutionTrigger_ForcedProducesNewVersion(t *testing.T) {
+	s, eng := newTestEvolutionServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleEvolutionRoutes(w, httptest.NewRequest(http.MethodPost, "/api/evolution/test-agent/trigger?force=true", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Forced   bool               `json:"forced"`
+		Strategy evolution.Strategy `json:"strategy"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.Forced || resp.Strategy.Version != 2 {
+		t.Errorf("response = %+v, want forced version 2", resp)
+	}
+	if v := eng.GetStrategy("test-agent").(*evolution.Strategy).Version; v != 2 {
+		t.Errorf("engine strategy version = %d, want 2", v)
+	}
+
+	status, err := s.orch.EvolutionStatus("test-agent")
+	if err != nil {
+		t.Fatalf("EvolutionStatus: %v", err)
+	}
+	if status.LastEvent == nil || status.LastEvent.Type != orchestrator.EvolutionMutation || !status.LastEvent.Manual {
+		t.Errorf("last event = %+v, want a manual mutation", status.LastEvent)
+	}
+	if status.Divergence != 2 {
+		t.Errorf("divergence = %g, want 2", status.Divergence)
+	}
+}
+
+func TestEvolutionTrigger_SafeMode(t *testing.T) {
+	s, eng := newTestEvolutionServer(t)
+	s.orch.SetSafeMode(true)
+
+	w := httptest.NewRecorder()
+	s.handleEvolutionRoutes(w, httptest.NewRequest(http.MethodPost, "/api/evolution/test-agent/trigger?force=true", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 in safe mode: %s", w.Code, w.Body.String())
+	}
+	if v := eng.GetStrategy("test-agent").(*evolution.Strategy).Version; v != 1 {
+		t.Errorf("strategy version = %d, want unchanged", v)
+	}
+
+	// Reads keep working.
+	w = httptest.NewRecorder()
+	s.handleEvolutionRoutes(w, httptest.NewRequest(http.MethodGet, "/api/evolution/test-agent", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status read in safe mode = %d, want 200", w.Code)
+	}
+}
+
+func TestEvolutionRoutes_Errors(t *testing.T) {
+	s := newTestChatServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleEvolutionRoutes(w, httptest.NewRequest(http.MethodGet, "/api/evolution/test-agent", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("no engine: status = %d, want 503", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleEvolutionRoutes(w, httptest.NewRequest(http.MethodGet, "/api/evolution/test-agent/trigger", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET trigger: status = %d, want 405", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleEvolutionRoutes(w, httptest.NewRequest(http.MethodPost, "/api/evolution/test-agent/trigger?force=maybe", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("bad force: status = %d, want 400", w.Code)
+	}
+}
@@ -40,6 +40,9 @@ func (s *Server) handleFirewallRollback(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	if s.rejectInSafeMode(w) {
+		return
+	}
 
 	agentID := extractAgentIDForFirewall(r.URL.Path, "/firewall/rollback")
 	if agentID == "" {
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/clawinfra/evoclaw/internal/cloudsync"
+)
+
+// FleetSource reads the cloud-synced fleet. *cloudsync.Manager implements it.
+type FleetSource interface {
+	Fleet(ctx context.Context) (*cloudsync.Fleet, error)
+}
+
+// SetFleetSource sets where GET /api/fleet reads from. Without one it uses
+// the orchestrator's cloud sync once that is enabled.
+func (s *Server) SetFleetSource(src FleetSource) {
+	s.fleet = src
+}
+
+// fleetSource returns the fleet to report on, or nil when cloud sync is off.
+func (s *Server) fleetSource() FleetSource {
+	if s.fleet != nil {
+		return s.fleet
+	}
+	if s.orch == nil {
+		return nil
+	}
+	if cs := s.orch.GetCloudSync(); cs != nil && cs.IsEnabled() {
+		return cs
+	}
+	return nil
+}
+
+// handleFleet handles GET /api/fleet — every cloud-synced device with its
+// agents' latest fitness and metrics, including devices run by other
+// orchestrators. It responds 503 when cloud sync is not enabled.
+func (s *Server) handleFleet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	src := s.fleetSource()
+	if src == nil {
+		http.Error(w, "cloud sync not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	fleet, err := src.Fleet(r.Context())
+	if errors.Is(err, cloudsync.ErrDisabled) {
+		http.Error(w, "cloud sync not enabled", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		s.logger.Error("failed to read fleet", "error", err)
+		http.Error(w, "failed to read fleet", http.StatusBadGateway)
+		return
+	}
+
+	s.respondJSON(w, fleet)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/cloudsync"
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// newMockTurso answers every query with rows.
+func newMockTurso(t *testing.T, rows [][]interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(cloudsync.PipelineResponse{Results: []cloudsync.BatchResult{
+			{Type: "ok", Response: &cloudsync.QueryResponse{Rows: rows}},
+		}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandleFleet_AggregatesDevices(t *testing.T) {
+	turso := newMockTurso(t, [][]interface{}{
+		{"dev-a", "Kitchen Pi", "hub", float64(300), float64(290), "active", float64(100),
+			"agent-1", "Trader", "gpt-4", "active", 0.9, `{"successRate":1}`, float64(280)},
+		{"dev-b", "Phone", "phone", float64(200), float64(190), "active", float64(150),
+			"agent-2", "Helper", "gpt-4o", "active", 0.5, nil, float64(180)},
+		{"dev-c", "Laptop", "hub", float64(100), float64(90), "offline", float64(50),
+			"agent-3", "Coder", "llama3", "active", nil, nil, nil},
+	})
+	mgr, err := cloudsync.NewManager(config.CloudSyncConfig{Enabled: true, DatabaseURL: turso.URL, AuthToken: "token"}, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestChatServer(t)
+	s.SetFleetSource(mgr)
+
+	w := httptest.NewRecorder()
+	s.handleFleet(w, httptest.NewRequest(http.MethodGet, "/api/fleet", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	var fleet cloudsync.Fleet
+	if err := json.NewDecoder(w.Body).Decode(&fleet); err != nil {
+		t.Fatal(err)
+	}
+	if fleet.DeviceCount != 3 || fleet.AgentCount != 3 || fleet.AvgFitness != 0.7 {
+		t.Errorf("fleet totals = %d devices, %d agents, %v avg fitness", fleet.DeviceCount, fleet.AgentCount, fleet.AvgFitness)
+	}
+	for i, want := range []string{"agent-1", "agent-2", "agent-3"} {
+		if got := fleet.Devices[i].Agents; len(got) != 1 || got[0].AgentID != want {
+			t.Errorf("device %d agents = %+v, want %s", i, got, want)
+		}
+	}
+	if fleet.Devices[2].Agents[0].Fitness != nil {
+		t.Error("unevaluated agent should have no fitness")
+	}
+}
+
+func TestHandleFleet_CloudSyncDisabled(t *testing.T) {
+	s := newTestChatServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleFleet(w, httptest.NewRequest(http.MethodGet, "/api/fleet", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status %d, want 503", w.Code)
+	}
+
+	disabled, _ := cloudsync.NewManager(config.CloudSyncConfig{}, slog.Default())
+	s.SetFleetSource(disabled)
+	w = httptest.NewRecorder()
+	s.handleFleet(w, httptest.NewRequest(http.MethodGet, "/api/fleet", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("disabled manager: status %d, want 503", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleFleet(w, httptest.NewRequest(http.MethodPost, "/api/fleet", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: status %d, want 405", w.Code)
+	}
+}
@@ -1,9 +1,12 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/clawinfra/evoclaw/internal/config"
@@ -91,6 +94,9 @@ func (s *Server) handleUpdateGenome(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	if s.rejectInSafeMode(w) {
+		return
+	}
 
 	// Extract agent ID from path
 	path := strings.TrimPrefix(r.URL.Path, "/api/agents/")
@@ -200,6 +206,9 @@ func (s *Server) handleUpdateSkillParams(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	if s.rejectInSafeMode(w) {
+		return
+	}
 
 	// Extract agent ID and skill name from path
 	path := strings.TrimPrefix(r.URL.Path, "/api/agents/")
@@ -275,6 +284,9 @@ func (s *Server) handleConstraintRoutes(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	if s.rejectInSafeMode(w) {
+		return
+	}
 
 	// Extract agent ID from path: /api/agents/{id}/genome/constraints
 	path := strings.TrimPrefix(r.URL.Path, "/api/agents/")
@@ -301,8 +313,20 @@ func (s *Server) handleConstraintRoutes(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Only the pinned owner key may sign constraints: a key supplied with
+	// the request would let anyone sign their own.
+	ownerKey := s.ownerPublicKey()
+	if len(ownerKey) == 0 {
+		http.Error(w, "no owner public key configured (evolution.ownerPublicKey)", http.StatusForbidden)
+		return
+	}
+	if !bytes.Equal(req.PublicKey, ownerKey) {
+		http.Error(w, "public key does not match the owner public key", http.StatusForbidden)
+		return
+	}
+
 	// Verify signature
-	ok, err := security.VerifyConstraints(req.Constraints, req.Signature, req.PublicKey)
+	ok, err := security.VerifyConstraints(req.Constraints, req.Signature, ownerKey)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("signature verification error: %v", err), http.StatusForbidden)
 		return
@@ -332,6 +356,17 @@ func (s *Server) handleConstraintRoutes(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// ownerPublicKey returns the pinned key genome constraints must be signed with.
+func (s *Server) ownerPublicKey() []byte {
+	if s.orch == nil {
+		return nil
+	}
+	if cfg := s.orch.GetConfig(); cfg != nil {
+		return cfg.Evolution.OwnerPublicKey
+	}
+	return nil
+}
+
 // ================================
 // Layer 3: Behavioral Evolution API
 // ================================
@@ -388,15 +423,7 @@ func (s *Server) handleSubmitFeedback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate feedback type
-	validTypes := map[string]bool{
-		"approval":   true,
-		"correction": true,
-		"engagement": true,
-		"dismissal":  true,
-		"completion": true,
-	}
-
-	if !validTypes[feedback.Type] {
+	if !evolution.ValidFeedbackType(feedback.Type) {
 		http.Error(w, "invalid feedback type", http.StatusBadRequest)
 		return
 	}
@@ -520,3 +547,203 @@ func (s *Server) handleGetBehaviorHistory(w http.ResponseWriter, r *http.Request
 		"history":  history,
 	})
 }
+
+// ================================
+// Genome Versioning API
+// ================================
+
+// handleGenomeHistory lists stored genome versions for an agent
+// GET /api/agents/{id}/genome/history
+func (s *Server) handleGenomeHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agentID := extractAgentIDForFirewall(r.URL.Path, "/genome/history")
+	if agentID == "" {
+		http.Error(w, "agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	eng := s.getEvolutionEngine()
+	if eng == nil {
+		http.Error(w, "evolution engine not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	history, err := eng.GenomeHistory(agentID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if history == nil {
+		history = []evolution.GenomeVersionInfo{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"agent_id": agentID,
+		"versions": history,
+	})
+}
+
+// handleGenomeDiff returns the field-level changes between two genome versions
+// GET /api/agents/{id}/genome/diff?from={v1}&to={v2}
+func (s *Server) handleGenomeDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agentID := extractAgentIDForFirewall(r.URL.Path, "/genome/diff")
+	if agentID == "" {
+		http.Error(w, "agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	from, err1 := strconv.Atoi(r.URL.Query().Get("from"))
+	to, err2 := strconv.Atoi(r.URL.Query().Get("to"))
+	if err1 != nil || err2 != nil {
+		http.Error(w, "from and to must be version numbers", http.StatusBadRequest)
+		return
+	}
+
+	eng := s.getEvolutionEngine()
+	if eng == nil {
+		http.Error(w, "evolution engine not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	changes, err := eng.GenomeDiff(agentID, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"agent_id": agentID,
+		"from":     from,
+		"to":       to,
+		"changes":  changes,
+	})
+}
+
+// handleGenomeRollback restores a stored genome version after re-verifying
+// its constraints
+// POST /api/agents/{id}/genome/rollback
+func (s *Server) handleGenomeRollback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.rejectInSafeMode(w) {
+		return
+	}
+
+	agentID := extractAgentIDForFirewall(r.URL.Path, "/genome/rollback")
+	if agentID == "" {
+		http.Error(w, "agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Version int `json:"version"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Version < 1 {
+		http.Error(w, "version must be positive", http.StatusBadRequest)
+		return
+	}
+
+	eng := s.getEvolutionEngine()
+	if eng == nil {
+		http.Error(w, "evolution engine not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	if _, err := eng.GetGenomeVersion(agentID, req.Version); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	genome, err := eng.RollbackGenome(agentID, req.Version)
+	if err != nil {
+		if errors.Is(err, security.ErrInvalidSignature) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Keep the registry copy in step with the engine
+	if agent, err := s.registry.Get(agentID); err == nil && agent != nil {
+		agent.Def.Genome = genome
+	}
+
+	s.logger.Info("genome rolled back via API", "agent", agentID, "version", req.Version)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":   "success",
+		"agent_id": agentID,
+		"version":  req.Version,
+		"genome":   genome,
+	})
+}
+
+// handleFitnessHistory returns the fitness recorded at each evaluation
+// GET /api/agents/{id}/fitness/history
+func (s *Server) handleFitnessHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agentID := extractAgentIDForFirewall(r.URL.Path, "/fitness/history")
+	if agentID == "" {
+		http.Error(w, "agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	eng := s.getEvolutionEngine()
+	if eng == nil {
+		http.Error(w, "evolution engine not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"agent_id": agentID,
+		"samples":  eng.FitnessHistory(agentID),
+	})
+}
+
+// handleLineage returns the agent's strategy lineage as nodes and edges
+// GET /api/agents/{id}/lineage
+func (s *Server) handleLineage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agentID := extractAgentIDForFirewall(r.URL.Path, "/lineage")
+	if agentID == "" {
+		http.Error(w, "agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	eng := s.getEvolutionEngine()
+	if eng == nil {
+		http.Error(w, "evolution engine not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(eng.Lineage(agentID))
+}
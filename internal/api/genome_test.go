@@ -7,6 +7,9 @@ import (
 	"testing"
 
 	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+	"github.com/clawinfra/evoclaw/internal/security"
 )
 
 func TestHandleUpdateGenome(t *testing.T) {
@@ -251,6 +254,38 @@ func TestHandleFeedbackRoutes(t *testing.T) {
 	}
 }
 
+func TestHandleFeedbackRoutes_RecordsBehaviorHistory(t *testing.T) {
+	s, agent := newTestServerWithAgent(t)
+	eng := evolution.NewEngine(t.TempDir(), s.logger)
+	s.SetEvolution(eng)
+
+	post := func(payload map[string]interface{}) int {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest("POST", "/api/agents/"+agent.ID+"/feedback", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		s.handleFeedbackRoutes(w, req)
+		return w.Code
+	}
+
+	if code := post(map[string]interface{}{"type": "approval", "score": 1.0, "context": "thumbs up"}); code != 200 {
+		t.Fatalf("POST feedback status = %d, want 200", code)
+	}
+	if code := post(map[string]interface{}{"type": "like", "score": 1.0}); code != 400 {
+		t.Errorf("invalid type status = %d, want 400", code)
+	}
+
+	history, err := eng.GetBehaviorHistory(agent.ID)
+	if err != nil {
+		t.Fatalf("GetBehaviorHistory: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("history has %d entries, want 1", len(history))
+	}
+	if history[0].Type != "approval" || history[0].Score != 1.0 || history[0].Context != "thumbs up" {
+		t.Errorf("history[0] = %+v", history[0])
+	}
+}
+
 func TestHandleBehaviorRoutes(t *testing.T) {
 	s, agent := newTestServerWithAgent(t)
 
@@ -306,3 +341,40 @@ func TestHandleDashboardV3(t *testing.T) {
 }
 
 // newTestServerWithAgent is defined in comprehensive_test.go
+
+func TestHandleConstraintRoutes_PinnedOwnerKey(t *testing.T) {
+	s, agent := newTestServerWithAgent(t)
+	cfg := &config.Config{}
+	s.orch = orchestrator.New(cfg, s.logger)
+
+	pub, priv, err := security.GenerateOwnerKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, otherPriv, err := security.GenerateOwnerKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	put := func(pub []byte, priv []byte) int {
+		c := config.GenomeConstraints{MaxLossUSD: 500}
+		sig, err := security.SignConstraints(c, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := json.Marshal(map[string]interface{}{"constraints": c, "signature": sig, "public_key": pub})
+		w := httptest.NewRecorder()
+		s.handleConstraintRoutes(w, httptest.NewRequest("PUT", "/api/agents/"+agent.ID+"/genome/constraints", bytes.NewBuffer(body)))
+		return w.Code
+	}
+
+	if code := put(pub, priv); code != 403 {
+		t.Errorf("without a pinned key: status = %d, want 403", code)
+	}
+	cfg.Evolution.OwnerPublicKey = pub
+	if code := put(otherPub, otherPriv); code != 403 {
+		t.Errorf("signed with another key: status = %d, want 403", code)
+	}
+	if code := put(pub, priv); code != 200 {
+		t.Errorf("signed with the owner key: status = %d, want 200", code)
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
+)
+
+func newGenomeVersionServer(t *testing.T) (*Server, *evolution.Engine) {
+	s := newTestServer(t)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	eng := evolution.NewEngine(t.TempDir(), logger)
+	s.SetEvolution(eng)
+
+	g := &config.Genome{
+		Skills: map[string]config.SkillGenome{
+			"trading": {Enabled: true, Params: map[string]interface{}{"threshold": 50.0}},
+		},
+	}
+	if err := eng.UpdateGenome("agent-1", g); err != nil {
+		t.Fatalf("UpdateGenome: %v", err)
+	}
+	if err := eng.MutateSkill("agent-1", "trading", 1.0); err != nil {
+		t.Fatalf("MutateSkill: %v", err)
+	}
+	return s, eng
+}
+
+func TestHandleGenomeHistory(t *testing.T) {
+	s, _ := newGenomeVersionServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleGenomeHistory(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-1/genome/history", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Versions []evolution.GenomeVersionInfo `json:"versions"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Versions) != 2 {
+		t.Errorf("expected 2 versions, got %d", len(resp.Versions))
+	}
+}
+
+func TestHandleGenomeDiff(t *testing.T) {
+	s, _ := newGenomeVersionServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleGenomeDiff(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-1/genome/diff?from=1&to=2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "skills.trading.params.threshold") {
+		t.Errorf("expected threshold change in body: %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	s.handleGenomeDiff(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-1/genome/diff?from=a", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for bad versions, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleGenomeDiff(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-1/genome/diff?from=1&to=7", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown version, got %d", w.Code)
+	}
+}
+
+func TestHandleGenomeRollback(t *testing.T) {
+	s, eng := newGenomeVersionServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleGenomeRollback(w, httptest.NewRequest(http.MethodPost, "/api/agents/agent-1/genome/rollback", strings.NewReader(`{"version":1}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	g, err := eng.GetGenome("agent-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Skills["trading"].Params["threshold"]; got != 50.0 {
+		t.Errorf("expected threshold 50 after rollback, got %v", got)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleGenomeRollback(w, httptest.NewRequest(http.MethodPost, "/api/agents/agent-1/genome/rollback", strings.NewReader(`{"version":42}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown version, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleGenomeRollback(w, httptest.NewRequest(http.MethodPost, "/api/agents/agent-1/genome/rollback", strings.NewReader(`{}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for missing version, got %d", w.Code)
+	}
+}
+
+func TestHandleGenomeVersioning_NoEngine(t *testing.T) {
+	s := newTestServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleGenomeHistory(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-1/genome/history", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleGenomeRollback(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-1/genome/rollback", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleFitnessHistory(t *testing.T) {
+	s, eng := newGenomeVersionServer(t)
+	eng.SetStrategy("agent-1", &evolution.Strategy{ID: "s1"})
+	eng.Evaluate("agent-1", map[string]float64{"successRate": 1.0})
+	eng.Evaluate("agent-1", map[string]float64{"successRate": 0.5})
+
+	w := httptest.NewRecorder()
+	s.handleFitnessHistory(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-1/fitness/history", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		AgentID string                    `json:"agent_id"`
+		Samples []evolution.FitnessSample `json:"samples"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.AgentID != "agent-1" || len(resp.Samples) != 2 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	// No evaluations yet returns an empty list, not null.
+	w = httptest.NewRecorder()
+	s.handleFitnessHistory(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-2/fitness/history", nil))
+	if !strings.Contains(w.Body.String(), `"samples":[]`) {
+		t.Errorf("expected empty samples, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	newTestServer(t).handleFitnessHistory(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-1/fitness/history", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without engine, got %d", w.Code)
+	}
+}
+
+func TestHandleLineage(t *testing.T) {
+	s, eng := newGenomeVersionServer(t)
+	eng.SetStrategy("agent-1", &evolution.Strategy{ID: "s1", Version: 1})
+	if _, err := eng.Mutate("agent-1", 0.1); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	s.handleLineage(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-1/lineage", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp evolution.Lineage
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.AgentID != "agent-1" || len(resp.Nodes) != 2 || len(resp.Edges) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if e := resp.Edges[0]; e.Type != evolution.LineageMutation || e.To != resp.Current {
+		t.Errorf("unexpected edge: %+v", e)
+	}
+
+	w = httptest.NewRecorder()
+	newTestServer(t).handleLineage(w, httptest.NewRequest(http.MethodGet, "/api/agents/agent-1/lineage", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without engine, got %d", w.Code)
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// handleHealthz is the liveness probe: it answers 200 as long as the process
+// is serving HTTP, regardless of subsystem state.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"time":   time.Now().UTC(),
+	})
+}
+
+// handleReadyz is the readiness probe: it answers 200 when every subsystem
+// reported by the orchestrator is ready and 503 otherwise, with a per-subsystem
+// breakdown in both cases.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var checks map[string]orchestrator.SubsystemStatus
+	if s.orch != nil {
+		checks = s.orch.Readiness()
+	} else {
+		checks = map[string]orchestrator.SubsystemStatus{
+			"orchestrator": {Detail: "not configured"},
+		}
+	}
+
+	ready := true
+	for _, c := range checks {
+		if !c.Ready {
+			ready = false
+			break
+		}
+	}
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not_ready", http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, map[string]interface{}{
+		"status": status,
+		"checks": checks,
+	})
+}
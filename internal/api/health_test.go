@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/agents"
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/models"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+type readyzBody struct {
+	Status string                                  `json:"status"`
+	Checks map[string]orchestrator.SubsystemStatus `json:"checks"`
+}
+
+func getReadyz(t *testing.T, s *Server) (int, readyzBody) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	s.handleReadyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var body readyzBody
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode readyz: %v", err)
+	}
+	return w.Code, body
+}
+
+func TestHandleHealthz(t *testing.T) {
+	s := newTestServer(t)
+	w := httptest.NewRecorder()
+	s.handleHealthz(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var body map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["status"] != "ok" {
+		t.Errorf("status = %v, want ok", body["status"])
+	}
+}
+
+func TestHandleHealthz_MethodNotAllowed(t *testing.T) {
+	s := newTestServer(t)
+	w := httptest.NewRecorder()
+	s.handleHealthz(w, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405", w.Code)
+	}
+}
+
+func TestHandleReadyz_NoOrchestrator(t *testing.T) {
+	s := newTestServerV2(t)
+	code, body := getReadyz(t, s)
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", code)
+	}
+	if body.Status != "not_ready" {
+		t.Errorf("status = %q, want not_ready", body.Status)
+	}
+}
+
+func TestHandleReadyz_ChannelsStarted(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(nil, &slog.HandlerOptions{Level: slog.LevelError}))
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Models.Health.PersistPath = dir + "/health.json"
+	orch := orchestrator.New(cfg, logger)
+	orch.RegisterProvider(&mockProvider{name: "mock", models: []config.Model{{ID: "m1"}}})
+
+	reg, _ := agents.NewRegistry(dir, logger)
+	mem, _ := agents.NewMemoryStore(dir, logger)
+	s := NewServer(0, orch, reg, mem, models.NewRouter(logger), logger)
+
+	code, body := getReadyz(t, s)
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("before start: status = %d, want 503", code)
+	}
+	if body.Checks["channels"].Ready {
+		t.Errorf("before start: channels reported ready: %+v", body.Checks["channels"])
+	}
+	if !body.Checks["providers"].Ready {
+		t.Errorf("before start: providers should be ready: %+v", body.Checks["providers"])
+	}
+
+	if err := orch.Start(); err != nil {
+		t.Fatalf("start orchestrator: %v", err)
+	}
+	t.Cleanup(func() { _ = orch.Stop() })
+
+	code, body = getReadyz(t, s)
+	if code != http.StatusOK {
+		t.Errorf("after start: status = %d, want 200 (checks %+v)", code, body.Checks)
+	}
+	if body.Status != "ready" {
+		t.Errorf("after start: status = %q, want ready", body.Status)
+	}
+}
+
+func TestHealthzUnauthenticated(t *testing.T) {
+	s := newTestServer(t)
+	s.jwtSecret = []byte("secret")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	srv := httptest.NewServer(s.jwtAuthWrapper(mux))
+	defer srv.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/healthz", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 without a token", resp.StatusCode)
+	}
+}
@@ -22,3 +22,13 @@ func WriteError(w http.ResponseWriter, status int, message string) {
 		"error": message,
 	})
 }
+
+// rejectInSafeMode answers 503 and returns true when the orchestrator is in
+// safe mode. Handlers that change genomes or strategies call it first.
+func (s *Server) rejectInSafeMode(w http.ResponseWriter) bool {
+	if s.orch == nil || !s.orch.SafeMode() {
+		return false
+	}
+	http.Error(w, "safe mode is on: evolution and genome changes are disabled", http.StatusServiceUnavailable)
+	return true
+}
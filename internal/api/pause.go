@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// handleAgentPause handles POST /api/agents/{id}/pause — the agent stops
+// taking messages until it is resumed.
+func (s *Server) handleAgentPause(w http.ResponseWriter, r *http.Request) {
+	s.setAgentPaused(w, r, "/pause", true)
+}
+
+// handleAgentResume handles POST /api/agents/{id}/resume.
+func (s *Server) handleAgentResume(w http.ResponseWriter, r *http.Request) {
+	s.setAgentPaused(w, r, "/resume", false)
+}
+
+func (s *Server) setAgentPaused(w http.ResponseWriter, r *http.Request, suffix string, paused bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agentID := extractAgentIDForFirewall(r.URL.Path, suffix)
+	if agentID == "" {
+		http.Error(w, "agent ID required", http.StatusBadRequest)
+		return
+	}
+	if s.orch == nil {
+		http.Error(w, "orchestrator not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var err error
+	status := "paused"
+	if paused {
+		err = s.orch.PauseAgent(agentID)
+	} else {
+		err = s.orch.ResumeAgent(agentID)
+		status = "idle"
+	}
+	if errors.Is(err, orchestrator.ErrAgentNotFound) {
+		http.Error(w, "agent not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Keep the registry's status, which the agent list shows, in step.
+	_ = s.registry.UpdateStatus(agentID, status)
+
+	s.respondJSON(w, map[string]interface{}{
+		"agent_id": agentID,
+		"paused":   paused,
+		"status":   status,
+	})
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleAgentPauseResume(t *testing.T) {
+	s := newTestChatServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/agents/test-agent/pause", nil)
+	w := httptest.NewRecorder()
+	s.handleAgentPause(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("pause: status %d: %s", w.Code, w.Body.String())
+	}
+	if a, _ := s.registry.Get("test-agent"); a.GetSnapshot().Status != "paused" {
+		t.Errorf("registry status = %q, want paused", a.GetSnapshot().Status)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{"agent_id":"test-agent","message":"Hello!"}`))
+	w = httptest.NewRecorder()
+	s.handleChat(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("chat with paused agent: status %d, want 409", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/agents/test-agent/resume", nil)
+	w = httptest.NewRecorder()
+	s.handleAgentResume(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("resume: status %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{"agent_id":"test-agent","message":"Hello!"}`))
+	w = httptest.NewRecorder()
+	s.handleChat(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("chat after resume: status %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleAgentPause_Errors(t *testing.T) {
+	s := newTestChatServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/agents/test-agent/pause", nil)
+	w := httptest.NewRecorder()
+	s.handleAgentPause(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status %d, want 405", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/agents/ghost/pause", nil)
+	w = httptest.NewRecorder()
+	s.handleAgentPause(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown agent: status %d, want 404", w.Code)
+	}
+}
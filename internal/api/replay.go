@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// ReplayRequest is the JSON body for POST /api/agents/{id}/replay. Either
+// Messages or ConversationID selects the conversation; with neither, the
+// agent's default dashboard conversation is replayed.
+type ReplayRequest struct {
+	ConversationID string                     `json:"conversation_id,omitempty"`
+	Messages       []orchestrator.ChatMessage `json:"messages,omitempty"`
+	Model          string                     `json:"model"`
+}
+
+// ReplayResponse is the JSON response for POST /api/agents/{id}/replay.
+// Original holds the reply recorded after the replayed user message, if any.
+type ReplayResponse struct {
+	AgentID      string  `json:"agent_id"`
+	Model        string  `json:"model"`
+	Response     string  `json:"response"`
+	Original     string  `json:"original,omitempty"`
+	ElapsedMs    int64   `json:"elapsed_ms"`
+	TokensInput  int     `json:"tokens_input"`
+	TokensOutput int     `json:"tokens_output"`
+	CostUSD      float64 `json:"cost_usd"`
+	Timestamp    string  `json:"timestamp"`
+}
+
+// handleAgentReplay handles POST /api/agents/{id}/replay — re-runs the last
+// user turn of a past conversation against another model. Nothing is saved
+// to conversation memory, metrics or evolution, but the call is subject to
+// the agent's cost budget and its cost is charged.
+func (s *Server) handleAgentReplay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agentID := extractAgentIDForFirewall(r.URL.Path, "/replay")
+	if agentID == "" {
+		http.Error(w, "agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req ReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Model == "" {
+		http.Error(w, "model is required", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.registry.Get(agentID); err != nil {
+		http.Error(w, "agent not found", http.StatusNotFound)
+		return
+	}
+
+	messages := req.Messages
+	if len(messages) == 0 {
+		convKey := agentID
+		if req.ConversationID != "" {
+			convKey = agentID + ":" + req.ConversationID
+		}
+		messages = s.memory.Get(convKey).GetMessages()
+	}
+
+	history, message, original, ok := splitReplay(messages)
+	if !ok {
+		http.Error(w, "conversation has no user message to replay", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.orch.ChatSync(r.Context(), orchestrator.ChatSyncRequest{
+		AgentID:        agentID,
+		UserID:         "replay",
+		Message:        message,
+		ConversationID: req.ConversationID,
+		History:        history,
+		Model:          req.Model,
+		Replay:         true,
+	})
+	if err != nil {
+		s.logger.Error("chat replay error", "agent", agentID, "model", req.Model, "error", err)
+		status := chatErrorStatus(err)
+		if errors.Is(err, orchestrator.ErrNoProvider) {
+			status = http.StatusBadRequest // the caller picked the model
+		}
+		http.Error(w, fmt.Sprintf("replay error: %v", err), status)
+		return
+	}
+
+	s.respondJSON(w, ReplayResponse{
+		AgentID:      resp.AgentID,
+		Model:        resp.Model,
+		Response:     resp.Response,
+		Original:     original,
+		ElapsedMs:    resp.ElapsedMs,
+		TokensInput:  resp.TokensInput,
+		TokensOutput: resp.TokensOutput,
+		CostUSD:      resp.CostUSD,
+		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// splitReplay splits a conversation at its last user message. Messages
+// before it become history and the assistant replies after it are returned
+// as the original answer. ok is false when there is no user message.
+func splitReplay(messages []orchestrator.ChatMessage) (history []orchestrator.ChatMessage, message, original string, ok bool) {
+	last := -1
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		return nil, "", "", false
+	}
+
+	var replies []string
+	for _, m := range messages[last+1:] {
+		if m.Role == "assistant" {
+			replies = append(replies, m.Content)
+		}
+	}
+	return messages[:last], messages[last].Content, strings.Join(replies, "\n\n"), true
+}
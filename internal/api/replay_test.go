@@ -0,0 +1,224 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/agents"
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/models"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// replayProvider answers every request with a fixed reply and records the
+// last request it saw.
+type replayProvider struct {
+	name  string
+	reply string
+	last  orchestrator.ChatRequest
+}
+
+func (p *replayProvider) Name() string { return p.name }
+
+func (p *replayProvider) Models() []config.Model {
+	return []config.Model{{ID: "m", Name: p.name}}
+}
+
+func (p *replayProvider) Chat(ctx context.Context, req orchestrator.ChatRequest) (*orchestrator.ChatResponse, error) {
+	p.last = req
+	return &orchestrator.ChatResponse{Content: p.reply, Model: req.Model, TokensInput: 200, TokensOutput: 100}, nil
+}
+
+func newTestReplayServer(t *testing.T) (*Server, *orchestrator.Orchestrator, *replayProvider, *replayProvider) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8420, DataDir: tmpDir},
+		Agents: []config.AgentDef{
+			{ID: "replay-agent", Name: "Replay", Type: "monitor", Model: "alpha/m"},
+		},
+		Models: config.ModelsConfig{
+			Providers: map[string]config.ProviderConfig{
+				"alpha": {Models: []config.Model{{ID: "m", CostInput: 1, CostOutput: 2}}},
+				"beta":  {Models: []config.Model{{ID: "m", CostInput: 10, CostOutput: 20}}},
+			},
+			Routing: config.ModelRouting{Simple: "alpha/m", Complex: "alpha/m", Critical: "alpha/m"},
+		},
+	}
+
+	registry, _ := agents.NewRegistry(tmpDir, logger)
+	memory, _ := agents.NewMemoryStore(tmpDir, logger)
+
+	orch := orchestrator.New(cfg, logger)
+	alpha := &replayProvider{name: "alpha", reply: "alpha says buy"}
+	beta := &replayProvider{name: "beta", reply: "beta says hold"}
+	orch.RegisterProvider(alpha)
+	orch.RegisterProvider(beta)
+	orch.RegisterChannel(&mockChanForChat{msgs: make(chan orchestrator.Message, 1)})
+	if err := orch.Start(); err != nil {
+		t.Fatalf("failed to start orchestrator: %v", err)
+	}
+	t.Cleanup(func() { orch.Stop() })
+
+	_, _ = registry.Create(cfg.Agents[0])
+
+	s := NewServer(8420, orch, registry, memory, models.NewRouter(logger), logger)
+	return s, orch, alpha, beta
+}
+
+func doReplay(t *testing.T, s *Server, agentID string, body ReplayRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/api/agents/"+agentID+"/replay", bytes.NewReader(data))
+	w := httptest.NewRecorder()
+	s.handleAgentReplay(w, req)
+	return w
+}
+
+func TestHandleAgentReplay_ComparesModels(t *testing.T) {
+	s, orch, alpha, beta := newTestReplayServer(t)
+
+	// Canned conversation recorded in memory under a conversation ID.
+	mem := s.memory.Get("replay-agent:c1")
+	mem.Add("user", "What is BTC doing?")
+	mem.Add("assistant", "Trending up.")
+	mem.Add("user", "Should I buy?")
+	mem.Add("assistant", "Yes, buy.")
+	before := len(mem.GetMessages())
+
+	results := make(map[string]ReplayResponse)
+	for _, model := range []string{"alpha/m", "beta/m"} {
+		w := doReplay(t, s, "replay-agent", ReplayRequest{ConversationID: "c1", Model: model})
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", model, w.Code, w.Body.String())
+		}
+		var resp ReplayResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		results[model] = resp
+	}
+
+	a, b := results["alpha/m"], results["beta/m"]
+	if a.Response != "alpha says buy" || b.Response != "beta says hold" {
+		t.Fatalf("responses = %q, %q", a.Response, b.Response)
+	}
+	if a.Model != "alpha/m" || b.Model != "beta/m" {
+		t.Errorf("models = %q, %q", a.Model, b.Model)
+	}
+	if a.Original != "Yes, buy." {
+		t.Errorf("original = %q, want recorded reply", a.Original)
+	}
+	if a.TokensInput != 200 || a.TokensOutput != 100 {
+		t.Errorf("tokens = %d/%d", a.TokensInput, a.TokensOutput)
+	}
+	// alpha: (200*1 + 100*2)/1e6, beta: (200*10 + 100*20)/1e6
+	if a.CostUSD != 0.0004 || b.CostUSD != 0.004 {
+		t.Errorf("costs = %g, %g", a.CostUSD, b.CostUSD)
+	}
+
+	// Both models saw the same replayed turn: one prior exchange as history.
+	for _, p := range []*replayProvider{alpha, beta} {
+		msgs := p.last.Messages
+		if len(msgs) != 3 || msgs[2].Content != "Should I buy?" || msgs[0].Content != "What is BTC doing?" {
+			t.Errorf("%s got messages %+v", p.name, msgs)
+		}
+	}
+
+	// Nothing was persisted, but both replays were charged.
+	if got := len(mem.GetMessages()); got != before {
+		t.Errorf("memory has %d messages, want %d", got, before)
+	}
+	agent := orch.GetAgentInfo("replay-agent")
+	if agent.MessageCount != 0 || agent.Metrics.TotalActions != 0 {
+		t.Errorf("agent state changed: count=%d actions=%d", agent.MessageCount, agent.Metrics.TotalActions)
+	}
+	if got := agent.Metrics.CostUSD; got < 0.0043 || got > 0.0045 {
+		t.Errorf("CostUSD = %g, want the replays' 0.0044", got)
+	}
+}
+
+func TestHandleAgentReplay_CostBudget(t *testing.T) {
+	s, orch, _, _ := newTestReplayServer(t)
+	orch.GetConfig().Server.GlobalBudgetUSD = 0.004
+	msgs := []orchestrator.ChatMessage{{Role: "user", Content: "status?"}}
+
+	// beta/m costs $0.004 a call: the first replay spends the budget.
+	if w := doReplay(t, s, "replay-agent", ReplayRequest{Model: "beta/m", Messages: msgs}); w.Code != http.StatusOK {
+		t.Fatalf("first replay: status = %d: %s", w.Code, w.Body.String())
+	}
+	w := doReplay(t, s, "replay-agent", ReplayRequest{Model: "beta/m", Messages: msgs})
+	if w.Code != http.StatusPaymentRequired || !strings.Contains(w.Body.String(), "cost budget exceeded") {
+		t.Errorf("second replay: status = %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleAgentReplay_InlineMessages(t *testing.T) {
+	s, _, _, beta := newTestReplayServer(t)
+
+	w := doReplay(t, s, "replay-agent", ReplayRequest{
+		Model: "beta/m",
+		Messages: []orchestrator.ChatMessage{
+			{Role: "user", Content: "hi"},
+			{Role: "assistant", Content: "hello"},
+			{Role: "user", Content: "status?"},
+		},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ReplayResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Response != "beta says hold" || resp.Original != "" {
+		t.Errorf("resp = %+v", resp)
+	}
+	if n := len(beta.last.Messages); n != 3 {
+		t.Errorf("provider got %d messages, want 3", n)
+	}
+	if got := len(s.memory.Get("replay-agent").GetMessages()); got != 0 {
+		t.Errorf("default conversation has %d messages, want 0", got)
+	}
+}
+
+func TestHandleAgentReplay_Errors(t *testing.T) {
+	s, _, _, _ := newTestReplayServer(t)
+
+	tests := []struct {
+		name    string
+		method  string
+		agentID string
+		body    ReplayRequest
+		want    int
+		wantMsg string
+	}{
+		{"method", http.MethodGet, "replay-agent", ReplayRequest{}, http.StatusMethodNotAllowed, "method not allowed"},
+		{"no model", http.MethodPost, "replay-agent", ReplayRequest{Messages: []orchestrator.ChatMessage{{Role: "user", Content: "x"}}}, http.StatusBadRequest, "model is required"},
+		{"unknown agent", http.MethodPost, "ghost", ReplayRequest{Model: "alpha/m"}, http.StatusNotFound, "agent not found"},
+		{"empty conversation", http.MethodPost, "replay-agent", ReplayRequest{Model: "alpha/m"}, http.StatusBadRequest, "no user message"},
+		{"no user turn", http.MethodPost, "replay-agent", ReplayRequest{Model: "alpha/m", Messages: []orchestrator.ChatMessage{{Role: "assistant", Content: "x"}}}, http.StatusBadRequest, "no user message"},
+		{"unknown model", http.MethodPost, "replay-agent", ReplayRequest{Model: "gamma/m", Messages: []orchestrator.ChatMessage{{Role: "user", Content: "x"}}}, http.StatusBadRequest, "no provider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(tt.method, "/api/agents/"+tt.agentID+"/replay", bytes.NewReader(data))
+			w := httptest.NewRecorder()
+			s.handleAgentReplay(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantMsg) {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.wantMsg)
+			}
+		})
+	}
+}
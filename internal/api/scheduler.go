@@ -49,6 +49,14 @@ func (s *Server) handleSchedulerJobRoutes(w http.ResponseWriter, r *http.Request
 		s.handleSchedulerRunJob(w, r)
 		return
 	}
+	if strings.HasSuffix(path, "/status") {
+		s.handleSchedulerJobStatus(w, r)
+		return
+	}
+	if strings.HasSuffix(path, "/enable") || strings.HasSuffix(path, "/disable") {
+		s.handleSchedulerToggleJob(w, r)
+		return
+	}
 	
 	// Otherwise route based on method
 	switch r.Method {
@@ -79,12 +87,55 @@ func (s *Server) handleSchedulerListJobs(w http.ResponseWriter, r *http.Request)
 	}
 
 	jobs := sched.ListJobs()
+	entries := make([]schedulerJobEntry, 0, len(jobs))
+	for _, job := range jobs {
+		entry := schedulerJobEntry{Job: job}
+		if status, err := sched.JobStatus(job.ID); err == nil {
+			entry.LastRun = status.LastRun
+		}
+		entries = append(entries, entry)
+	}
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"jobs":  jobs,
-		"count": len(jobs),
+		"jobs":  entries,
+		"count": len(entries),
 	})
 }
 
+// schedulerJobEntry is a job as listed by the API, with its last-run outcome.
+type schedulerJobEntry struct {
+	*scheduler.Job
+	LastRun *scheduler.JobRun `json:"lastRun,omitempty"`
+}
+
+// handleSchedulerJobStatus returns a job's recent run history
+// GET /api/scheduler/jobs/{id}/status
+func (s *Server) handleSchedulerJobStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/scheduler/jobs/"), "/status")
+
+	sched := s.orch.GetScheduler()
+	if sched == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "Scheduler not available",
+		})
+		return
+	}
+
+	status, err := sched.JobStatus(jobID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
 // handleSchedulerGetJob returns a specific job
 func (s *Server) handleSchedulerGetJob(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -180,8 +231,7 @@ func (s *Server) handleSchedulerUpdateJob(w http.ResponseWriter, r *http.Request
 	}
 
 	// Get current job
-	job, err := sched.GetJob(jobID)
-	if err != nil {
+	if _, err := sched.GetJob(jobID); err != nil {
 		writeJSON(w, http.StatusNotFound, map[string]string{
 			"error": err.Error(),
 		})
@@ -190,21 +240,72 @@ func (s *Server) handleSchedulerUpdateJob(w http.ResponseWriter, r *http.Request
 
 	// Update enabled status
 	if update.Enabled != nil {
-		job.Enabled = *update.Enabled
-		if err := sched.UpdateJob(job); err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{
+		setEnabled := sched.DisableJob
+		if *update.Enabled {
+			setEnabled = sched.EnableJob
+		}
+		if err := setEnabled(jobID); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
 				"error": err.Error(),
 			})
 			return
 		}
 	}
 
+	job, _ := sched.GetJob(jobID)
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"message": "Job updated",
 		"job":     job,
 	})
 }
 
+// handleSchedulerToggleJob enables or disables a job without restarting
+// POST /api/scheduler/jobs/{id}/enable
+// POST /api/scheduler/jobs/{id}/disable
+func (s *Server) handleSchedulerToggleJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/scheduler/jobs/")
+	enable := strings.HasSuffix(path, "/enable")
+	jobID := strings.TrimSuffix(strings.TrimSuffix(path, "/enable"), "/disable")
+
+	sched := s.orch.GetScheduler()
+	if sched == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "Scheduler not available",
+		})
+		return
+	}
+
+	if _, err := sched.GetJob(jobID); err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	setEnabled := sched.DisableJob
+	if enable {
+		setEnabled = sched.EnableJob
+	}
+	if err := setEnabled(jobID); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Job updated",
+		"job_id":  jobID,
+		"enabled": enable,
+	})
+}
+
 // handleSchedulerAddJob adds a new job
 func (s *Server) handleSchedulerAddJob(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
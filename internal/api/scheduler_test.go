@@ -604,3 +604,100 @@ func TestGenerateMessageID(t *testing.T) {
 		t.Log("warning: two consecutive IDs are the same (possible if very fast)")
 	}
 }
+
+// --- job run history ---
+
+func TestHandleSchedulerJobs_IncludesLastRun(t *testing.T) {
+	s, sched := newTestServerWithScheduler(t)
+	job := seedJob(t, sched)
+	if err := sched.RunJobNow(job.ID); err != nil {
+		t.Fatalf("RunJobNow: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	s.handleSchedulerJobs(w, httptest.NewRequest(http.MethodGet, "/api/scheduler/jobs", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	var resp struct {
+		Jobs []struct {
+			ID      string            `json:"id"`
+			LastRun *scheduler.JobRun `json:"lastRun"`
+		} `json:"jobs"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	for _, j := range resp.Jobs {
+		if j.ID == job.ID {
+			if j.LastRun == nil || !j.LastRun.Success {
+				t.Errorf("expected successful lastRun, got %+v", j.LastRun)
+			}
+			return
+		}
+	}
+	t.Errorf("job %s not listed", job.ID)
+}
+
+func TestHandleSchedulerJobStatus(t *testing.T) {
+	s, sched := newTestServerWithScheduler(t)
+	job := seedJob(t, sched)
+	_ = sched.RunJobNow(job.ID)
+
+	w := httptest.NewRecorder()
+	s.handleSchedulerJobRoutes(w, httptest.NewRequest(http.MethodGet, "/api/scheduler/jobs/"+job.ID+"/status", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var st scheduler.JobStatus
+	if err := json.NewDecoder(w.Body).Decode(&st); err != nil {
+		t.Fatal(err)
+	}
+	if st.RunCount != 1 || len(st.Runs) != 1 {
+		t.Errorf("expected one recorded run, got %+v", st)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleSchedulerJobRoutes(w, httptest.NewRequest(http.MethodGet, "/api/scheduler/jobs/missing/status", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+// --- enable / disable ---
+
+func TestHandleSchedulerToggleJob(t *testing.T) {
+	s, sched := newTestServerWithScheduler(t)
+	job := seedJob(t, sched)
+
+	w := httptest.NewRecorder()
+	s.handleSchedulerJobRoutes(w, httptest.NewRequest(http.MethodPost, "/api/scheduler/jobs/"+job.ID+"/disable", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("disable status = %d: %s", w.Code, w.Body.String())
+	}
+	if got, _ := sched.GetJob(job.ID); got.Enabled {
+		t.Error("expected job disabled")
+	}
+
+	w = httptest.NewRecorder()
+	s.handleSchedulerJobRoutes(w, httptest.NewRequest(http.MethodPost, "/api/scheduler/jobs/"+job.ID+"/enable", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("enable status = %d: %s", w.Code, w.Body.String())
+	}
+	if got, _ := sched.GetJob(job.ID); !got.Enabled {
+		t.Error("expected job enabled")
+	}
+
+	w = httptest.NewRecorder()
+	s.handleSchedulerJobRoutes(w, httptest.NewRequest(http.MethodPost, "/api/scheduler/jobs/missing/enable", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown job status = %d, want 404", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleSchedulerJobRoutes(w, httptest.NewRequest(http.MethodGet, "/api/scheduler/jobs/"+job.ID+"/enable", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want 405", w.Code)
+	}
+}
@@ -3,7 +3,9 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log/slog"
 	"net/http"
@@ -37,6 +39,20 @@ type Server struct {
 	wsTimeout   time.Duration         // timeout for WS chat responses (default 30 s)
 	cloudMgr    *cloud.Manager        // E2B cloud sandbox manager
 	saasSvc     *saas.Service         // Multi-tenant SaaS service
+
+	// fleet, when set, is read by GET /api/fleet instead of the
+	// orchestrator's cloud sync.
+	fleet FleetSource
+
+	// configPath is the config file PATCH /api/config writes; empty
+	// disables updates. onConfigReload is told about each update.
+	configPath     string
+	onConfigReload func(*config.ReloadResult)
+
+	// tlsCerts, when set by SetTLS, serves HTTPS; redirectPort optionally
+	// redirects plain HTTP to it.
+	tlsCerts     *certReloader
+	redirectPort int
 }
 
 // NewServer creates a new API server
@@ -97,20 +113,32 @@ func (s *Server) Start(ctx context.Context) error {
 
 	// Terminal web UI
 	mux.HandleFunc("/terminal", s.handleTerminalPage)
+
+	// Liveness and readiness probes (unauthenticated, outside /api/)
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	mux.HandleFunc("/metrics", s.handlePrometheusMetrics)
 	
 	// Register API routes (protected by auth middleware applied at handler level)
 	mux.HandleFunc("/api/status", s.handleStatus)
 	mux.HandleFunc("/api/chat", s.handleChat)
 	mux.HandleFunc("/api/chat/stream", s.handleChatStream)
+	mux.HandleFunc("/api/chat/preview", s.handleChatPreview)
+	mux.HandleFunc("/api/chat/batch", s.handleChatBatch)
 	mux.HandleFunc("/api/agents", s.handleAgents)
 	mux.HandleFunc("/api/agents/", s.handleAgentDetail)
 	mux.HandleFunc("/api/models", s.handleModels)
 	mux.HandleFunc("/api/costs", s.handleCosts)
 	mux.HandleFunc("/api/dashboard", s.handleDashboard)
 	mux.HandleFunc("/api/logs/stream", s.handleLogStream)
+	mux.HandleFunc("/api/events", s.handleEventStream)
 	mux.HandleFunc("/api/memory/stats", s.handleMemoryStats)
 	mux.HandleFunc("/api/memory/tree", s.handleMemoryTree)
 	mux.HandleFunc("/api/memory/retrieve", s.handleMemoryRetrieve)
+	mux.HandleFunc("/api/cloudsync/status", s.handleCloudSyncStatus)
+	mux.HandleFunc("/api/fleet", s.handleFleet)
+	mux.HandleFunc("/api/tools/metrics", s.handleToolMetrics)
+	mux.HandleFunc("/api/config", s.handleConfig)
 	
 	// Scheduler API routes
 	mux.HandleFunc("/api/scheduler/status", s.handleSchedulerStatus)
@@ -122,6 +150,17 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.HandleFunc("/api/agents/{id}/genome/skills/{skill}", s.handleSkillRoutes)
 	mux.HandleFunc("/api/agents/{id}/genome/skills/{skill}/params", s.handleUpdateSkillParams)
 	mux.HandleFunc("/api/agents/{id}/genome/constraints", s.handleConstraintRoutes)
+	mux.HandleFunc("/api/agents/{id}/genome/history", s.handleGenomeHistory)
+	mux.HandleFunc("/api/agents/{id}/genome/diff", s.handleGenomeDiff)
+	mux.HandleFunc("/api/agents/{id}/genome/rollback", s.handleGenomeRollback)
+	mux.HandleFunc("/api/agents/{id}/fitness/history", s.handleFitnessHistory)
+	mux.HandleFunc("/api/agents/{id}/lineage", s.handleLineage)
+	mux.HandleFunc("/api/agents/{id}/actions", s.handleAgentActions)
+	mux.HandleFunc("/api/agents/{id}/replay", s.handleAgentReplay)
+	mux.HandleFunc("/api/agents/{id}/pause", s.handleAgentPause)
+	mux.HandleFunc("/api/agents/{id}/resume", s.handleAgentResume)
+	mux.HandleFunc("/api/deadletter", s.handleDeadLetters)
+	mux.HandleFunc("/api/evolution/", s.handleEvolutionRoutes)
 	
 	// Layer 3: Behavioral Evolution API routes
 	mux.HandleFunc("/api/agents/{id}/feedback", s.handleFeedbackRoutes)
@@ -139,12 +178,8 @@ func (s *Server) Start(ctx context.Context) error {
 	// SaaS API routes
 	s.registerSaaSRoutes(mux)
 
-	// Serve embedded web dashboard
-	if s.webFS != nil {
-		fileServer := http.FileServer(http.FS(s.webFS))
-		mux.Handle("/", fileServer)
-		s.logger.Info("web dashboard enabled at /")
-	}
+	// Serve embedded web dashboard, or a placeholder without it
+	mux.Handle("/", s.webHandler())
 
 	// Apply JWT auth middleware to all routes (skips /api/auth/token via path check)
 	authedHandler := s.jwtAuthWrapper(mux)
@@ -155,26 +190,52 @@ func (s *Server) Start(ctx context.Context) error {
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 0, // 0 = no write timeout (required for long-lived WS connections)
 		IdleTimeout:  60 * time.Second,
+		TLSConfig:    s.tlsConfig(),
 	}
 
-	s.logger.Info("API server starting", "port", s.port)
+	s.logger.Info("API server starting", "port", s.port, "tls", s.tlsCerts != nil)
 
 	// Run server in goroutine
-	errCh := make(chan error, 1)
+	errCh := make(chan error, 2)
 	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if s.tlsCerts != nil {
+			// The certificate comes from TLSConfig.GetCertificate
+			err = s.httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = s.httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			errCh <- err
 		}
 	}()
 
+	var redirect *http.Server
+	if s.tlsCerts != nil && s.redirectPort > 0 {
+		redirect = s.newRedirectServer()
+		s.logger.Info("redirecting HTTP to HTTPS", "port", s.redirectPort)
+		go func() {
+			if err := redirect.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errCh <- fmt.Errorf("http redirect: %w", err)
+			}
+		}()
+	}
+
 	// Wait for shutdown or error
 	select {
 	case <-ctx.Done():
 		s.logger.Info("shutting down API server")
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
+		if redirect != nil {
+			_ = redirect.Shutdown(shutdownCtx)
+		}
 		return s.httpServer.Shutdown(shutdownCtx)
 	case err := <-errCh:
+		if redirect != nil {
+			_ = redirect.Close()
+		}
+		_ = s.httpServer.Close()
 		return err
 	}
 }
@@ -330,11 +391,19 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 		"memory":     memStats,
 		"total_cost": totalCost,
 	}
+	if s.orch != nil {
+		status["queues"] = s.orch.QueueStats()
+		status["safe_mode"] = s.orch.SafeMode()
+		status["goroutine_panics"] = s.orch.PanicStats()
+		if rc := s.orch.ResponseCacheStats(); rc != nil {
+			status["response_cache"] = rc
+		}
+	}
 
 	s.respondJSON(w, status)
 }
 
-// handleAgents handles agent listing
+// handleAgents handles agent listing and creation
 func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -345,11 +414,98 @@ func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
 		}
 		s.respondJSON(w, snapshots)
 
+	case http.MethodPost:
+		s.handleAgentCreate(w, r)
+
 	default:
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
+// handleAgentCreate creates an agent at runtime from an AgentDef body.
+// With ?template=<name> the body is optional and its fields override the
+// built-in template. The agent is persisted by the registry and starts
+// receiving messages immediately.
+func (s *Server) handleAgentCreate(w http.ResponseWriter, r *http.Request) {
+	template := r.URL.Query().Get("template")
+
+	var def config.AgentDef
+	if err := json.NewDecoder(r.Body).Decode(&def); err != nil && !(template != "" && errors.Is(err, io.EOF)) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if template != "" {
+		var err error
+		if def, err = config.AgentFromTemplate(template, def); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if def.ID == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	if strings.Contains(def.ID, "/") {
+		http.Error(w, "id must not contain '/'", http.StatusBadRequest)
+		return
+	}
+
+	agent, err := s.registry.Create(def)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if s.orch != nil {
+		if err := s.orch.AddAgent(def); err != nil {
+			// Keep registry and orchestrator consistent
+			_ = s.registry.Delete(def.ID)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+	}
+
+	s.logger.Info("agent created via API", "id", def.ID, "type", def.Type)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(agent.GetSnapshot())
+}
+
+// handleAgentDelete removes an agent from the registry and the orchestrator.
+func (s *Server) handleAgentDelete(w http.ResponseWriter, agentID string) {
+	if err := s.registry.Delete(agentID); err != nil {
+		http.Error(w, "agent not found", http.StatusNotFound)
+		return
+	}
+	// With per-agent data directories the agent's conversation memory and
+	// action log go with it. The registry has already removed the directory
+	// under the file store; this also covers other storage backends.
+	if s.registry.AgentDataDirs() {
+		if s.memory != nil {
+			s.memory.Delete(agentID)
+		}
+		if s.orch != nil && s.orch.ActionLog() != nil {
+			if err := s.orch.ActionLog().Delete(agentID); err != nil {
+				s.logger.Warn("failed to delete action log", "agent", agentID, "error", err)
+			}
+		}
+	}
+
+	// Agents registered only with the registry (e.g. self-registered edge
+	// agents) are not tracked by the orchestrator, so not-found is fine here.
+	if s.orch != nil {
+		_ = s.orch.RemoveAgent(agentID)
+	}
+
+	s.logger.Info("agent deleted via API", "id", agentID)
+
+	s.respondJSON(w, map[string]interface{}{
+		"message":  "agent deleted",
+		"agent_id": agentID,
+	})
+}
+
 // handleAgentDetail handles individual agent operations
 func (s *Server) handleAgentDetail(w http.ResponseWriter, r *http.Request) {
 	// Extract agent ID from path: /api/agents/{id}/{action}
@@ -394,6 +550,8 @@ func (s *Server) handleAgentDetail(w http.ResponseWriter, r *http.Request) {
 	case action == "" && r.Method == http.MethodPatch:
 		// Update agent settings
 		s.handleAgentUpdate(w, r, agentID, agent)
+	case action == "" && r.Method == http.MethodDelete:
+		s.handleAgentDelete(w, agentID)
 	default:
 		http.Error(w, "invalid action or method", http.StatusBadRequest)
 	}
@@ -470,6 +628,9 @@ func (s *Server) handleAgentMetrics(w http.ResponseWriter, agent *agents.Agent)
 
 // handleAgentEvolve triggers evolution for an agent
 func (s *Server) handleAgentEvolve(w http.ResponseWriter, agent *agents.Agent) {
+	if s.rejectInSafeMode(w) {
+		return
+	}
 	// TODO: Trigger evolution engine
 	s.respondJSON(w, map[string]interface{}{
 		"message":  "evolution triggered",
@@ -652,7 +652,7 @@ func TestHandleCosts_MethodNotAllowed(t *testing.T) {
 func TestHandleAgents_MethodNotAllowed(t *testing.T) {
 	s := newTestServer(t)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/agents", nil)
+	req := httptest.NewRequest(http.MethodPut, "/api/agents", nil)
 	w := httptest.NewRecorder()
 
 	s.handleAgents(w, req)
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// certReloader serves a certificate and key pair that can be reloaded from
+// disk without restarting the server.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload reads the pair from disk. On error the previous pair stays in use.
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS certificate: %w", err)
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// SetTLS serves the API over HTTPS with the PEM certificate and key in
+// certFile and keyFile. With redirectPort > 0, Start also listens for plain
+// HTTP on that port and redirects every request to HTTPS. Call it before
+// Start.
+func (s *Server) SetTLS(certFile, keyFile string, redirectPort int) error {
+	certs, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	s.tlsCerts = certs
+	s.redirectPort = redirectPort
+	return nil
+}
+
+// ReloadTLSCert re-reads the TLS certificate and key, e.g. after they were
+// renewed. New connections use the new pair; on error the old one is kept.
+// It does nothing when the server does not use TLS.
+func (s *Server) ReloadTLSCert() error {
+	if s.tlsCerts == nil {
+		return nil
+	}
+	if err := s.tlsCerts.reload(); err != nil {
+		return err
+	}
+	s.logger.Info("TLS certificate reloaded", "cert", s.tlsCerts.certFile)
+	return nil
+}
+
+// tlsConfig returns the server's TLS configuration, or nil without TLS.
+func (s *Server) tlsConfig() *tls.Config {
+	if s.tlsCerts == nil {
+		return nil
+	}
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: s.tlsCerts.getCertificate,
+	}
+}
+
+// newRedirectServer returns the plain HTTP server that sends clients to the
+// HTTPS port.
+func (s *Server) newRedirectServer() *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", s.redirectPort),
+		Handler:           http.HandlerFunc(s.redirectToHTTPS),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
+
+// redirectToHTTPS permanently redirects a request to the same URL on the
+// HTTPS port.
+func (s *Server) redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if s.port != 443 {
+		host = net.JoinHostPort(host, strconv.Itoa(s.port))
+	}
+	target := "https://" + host + r.URL.RequestURI()
+	http.Redirect(w, r, target, http.StatusPermanentRedirect)
+}
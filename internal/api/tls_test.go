@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCert writes a self-signed certificate for 127.0.0.1 and
+// its key as PEM files in dir and returns their paths and the certificate.
+func writeSelfSignedCert(t *testing.T, dir string, serial int64) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	certFile = filepath.Join(dir, "server.pem")
+	keyFile = filepath.Join(dir, "server-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, cert
+}
+
+// freeTCPPort returns a port that was free a moment ago.
+func freeTCPPort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestServerServesHTTPS(t *testing.T) {
+	certFile, keyFile, cert := writeSelfSignedCert(t, t.TempDir(), 1)
+
+	s := newTestServerV2(t)
+	s.port = freeTCPPort(t)
+	if err := s.SetTLS(certFile, keyFile, 0); err != nil {
+		t.Fatalf("SetTLS: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Start(ctx) }()
+	defer func() {
+		cancel()
+		select {
+		case <-errCh:
+		case <-time.After(5 * time.Second):
+			t.Error("timeout waiting for server shutdown")
+		}
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	client := &http.Client{
+		Timeout:   2 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}},
+	}
+	url := fmt.Sprintf("https://127.0.0.1:%d/healthz", s.port)
+
+	var resp *http.Response
+	var err error
+	for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if resp, err = client.Get(url); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatalf("HTTPS request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 || resp.TLS.PeerCertificates[0].SerialNumber.Int64() != 1 {
+		t.Error("expected the response to be served with the configured certificate")
+	}
+}
+
+func TestSetTLSInvalidCert(t *testing.T) {
+	s := newTestServerV2(t)
+	dir := t.TempDir()
+	if err := s.SetTLS(filepath.Join(dir, "missing.pem"), filepath.Join(dir, "missing-key.pem"), 0); err == nil {
+		t.Error("expected error for missing certificate files")
+	}
+	if s.tlsConfig() != nil {
+		t.Error("expected TLS to stay off after a failed SetTLS")
+	}
+}
+
+func TestReloadTLSCert(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeSelfSignedCert(t, dir, 1)
+
+	s := newTestServerV2(t)
+	if err := s.ReloadTLSCert(); err != nil {
+		t.Errorf("ReloadTLSCert without TLS: %v", err)
+	}
+	if err := s.SetTLS(certFile, keyFile, 0); err != nil {
+		t.Fatalf("SetTLS: %v", err)
+	}
+
+	serial := func() int64 {
+		c, err := s.tlsConfig().GetCertificate(nil)
+		if err != nil {
+			t.Fatalf("GetCertificate: %v", err)
+		}
+		leaf, err := x509.ParseCertificate(c.Certificate[0])
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		return leaf.SerialNumber.Int64()
+	}
+
+	// A renewed certificate on disk is served after a reload
+	writeSelfSignedCert(t, dir, 2)
+	if err := s.ReloadTLSCert(); err != nil {
+		t.Fatalf("ReloadTLSCert: %v", err)
+	}
+	if got := serial(); got != 2 {
+		t.Errorf("serial after reload = %d, want 2", got)
+	}
+
+	// A broken file keeps the previous certificate
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ReloadTLSCert(); err == nil {
+		t.Error("expected error reloading an invalid certificate")
+	}
+	if got := serial(); got != 2 {
+		t.Errorf("serial after failed reload = %d, want 2", got)
+	}
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	s := newTestServerV2(t)
+	s.port = 8443
+
+	req := httptest.NewRequest(http.MethodPost, "http://evoclaw.local:8080/api/chat?x=1", nil)
+	w := httptest.NewRecorder()
+	s.redirectToHTTPS(w, req)
+
+	if w.Code != http.StatusPermanentRedirect {
+		t.Errorf("status = %d, want 308", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "https://evoclaw.local:8443/api/chat?x=1" {
+		t.Errorf("Location = %q", loc)
+	}
+
+	s.port = 443
+	req = httptest.NewRequest(http.MethodGet, "http://evoclaw.local/", nil)
+	w = httptest.NewRecorder()
+	s.redirectToHTTPS(w, req)
+	if loc := w.Header().Get("Location"); loc != "https://evoclaw.local/" {
+		t.Errorf("Location on 443 = %q", loc)
+	}
+}
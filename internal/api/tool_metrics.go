@@ -0,0 +1,74 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// handleToolMetrics handles GET /api/tools/metrics — invocation count,
+// average latency and error rate for every tool executed since startup.
+func (s *Server) handleToolMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tools := []orchestrator.ToolStats{}
+	if s.orch != nil {
+		if stats := s.orch.ToolStats(); stats != nil {
+			tools = stats
+		}
+	}
+	s.respondJSON(w, map[string]interface{}{"tools": tools})
+}
+
+// handlePrometheusMetrics handles GET /metrics in the Prometheus text
+// exposition format. Like /healthz it sits outside /api/ and is not
+// behind JWT auth, so scrapers need no token.
+func (s *Server) handlePrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var tools []orchestrator.ToolStats
+	var panics map[string]int64
+	if s.orch != nil {
+		tools = s.orch.ToolStats()
+		panics = s.orch.PanicStats()
+	}
+
+	var b strings.Builder
+	writeToolMetric := func(name, typ, help string, value func(orchestrator.ToolStats) string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+		for _, t := range tools {
+			fmt.Fprintf(&b, "%s{tool=%q} %s\n", name, t.Tool, value(t))
+		}
+	}
+	writeToolMetric("evoclaw_tool_invocations_total", "counter", "Tool executions since startup.",
+		func(t orchestrator.ToolStats) string { return fmt.Sprint(t.Invocations) })
+	writeToolMetric("evoclaw_tool_errors_total", "counter", "Tool executions that failed.",
+		func(t orchestrator.ToolStats) string { return fmt.Sprint(t.Errors) })
+	writeToolMetric("evoclaw_tool_timeouts_total", "counter", "Tool executions that timed out.",
+		func(t orchestrator.ToolStats) string { return fmt.Sprint(t.Timeouts) })
+	writeToolMetric("evoclaw_tool_duration_seconds_total", "counter", "Total time spent executing each tool.",
+		func(t orchestrator.ToolStats) string { return fmt.Sprint(float64(t.TotalLatencyMs) / 1000) })
+
+	names := make([]string, 0, len(panics))
+	for name := range panics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b.WriteString("# HELP evoclaw_goroutine_panics_total Panics recovered in background goroutines since startup.\n")
+	b.WriteString("# TYPE evoclaw_goroutine_panics_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "evoclaw_goroutine_panics_total{goroutine=%q} %d\n", name, panics[name])
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
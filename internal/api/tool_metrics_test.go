@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleToolMetrics_Empty(t *testing.T) {
+	s := newTestChatServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tools/metrics", nil)
+	w := httptest.NewRecorder()
+	s.handleToolMetrics(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Tools []json.RawMessage `json:"tools"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Tools == nil || len(body.Tools) != 0 {
+		t.Errorf("tools = %v, want empty list", body.Tools)
+	}
+}
+
+func TestHandlePrometheusMetrics(t *testing.T) {
+	s := newTestChatServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+	s.handlePrometheusMetrics(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("content type = %q", ct)
+	}
+	for _, want := range []string{
+		"# TYPE evoclaw_tool_invocations_total counter",
+		"# TYPE evoclaw_tool_errors_total counter",
+		"# TYPE evoclaw_tool_duration_seconds_total counter",
+		"# TYPE evoclaw_goroutine_panics_total counter",
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, w.Body.String())
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/metrics", nil)
+	w = httptest.NewRecorder()
+	s.handlePrometheusMetrics(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: status %d, want 405", w.Code)
+	}
+}
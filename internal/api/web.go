@@ -0,0 +1,55 @@
+package api
+
+import (
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// dashboardUnavailablePage is served in place of the dashboard when the
+// binary was built without its web assets.
+const dashboardUnavailablePage = `<!DOCTYPE html>
+<html>
+<head><title>EvoClaw Dashboard</title></head>
+<body>
+	<h1>EvoClaw Dashboard unavailable</h1>
+	<p>This build of EvoClaw does not include the web dashboard assets.
+	Rebuild from a checkout that has <code>cmd/evoclaw/web/</code> to
+	enable it.</p>
+	<p>The API is still served: see <a href="/api/status">/api/status</a>
+	and <a href="/healthz">/healthz</a>.</p>
+</body>
+</html>
+`
+
+// DashboardAvailable reports whether the web dashboard is served, that is
+// whether web assets with an index.html were set with SetWebFS.
+func (s *Server) DashboardAvailable() bool {
+	if s.webFS == nil {
+		return false
+	}
+	_, err := fs.Stat(s.webFS, "index.html")
+	return err == nil
+}
+
+// webHandler serves the dashboard from the web assets, or a 503 page
+// explaining they are missing.
+func (s *Server) webHandler() http.Handler {
+	if s.DashboardAvailable() {
+		s.logger.Info("web dashboard enabled at /")
+		return http.FileServer(http.FS(s.webFS))
+	}
+	s.logger.Warn("web dashboard assets missing; serving a placeholder at /")
+	return http.HandlerFunc(s.handleDashboardUnavailable)
+}
+
+func (s *Server) handleDashboardUnavailable(w http.ResponseWriter, r *http.Request) {
+	// Unknown API routes are still plain 404s
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = w.Write([]byte(dashboardUnavailablePage)) // Ignore write errors (client disconnect)
+}
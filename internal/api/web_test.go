@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWebHandler_NoAssets(t *testing.T) {
+	for name, setup := range map[string]func(*Server){
+		"no web fs":     func(*Server) {},
+		"no index.html": func(s *Server) { s.SetWebFS(fstest.MapFS{"app.js": {Data: []byte("")}}) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := newTestServer(t)
+			setup(s)
+			if s.DashboardAvailable() {
+				t.Fatal("expected the dashboard to be unavailable")
+			}
+			h := s.webHandler()
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != http.StatusServiceUnavailable {
+				t.Errorf("status = %d, want 503", w.Code)
+			}
+			if !strings.Contains(w.Header().Get("Content-Type"), "text/html") || !strings.Contains(w.Body.String(), "Dashboard unavailable") {
+				t.Errorf("unexpected fallback page %q", w.Body.String())
+			}
+
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/nope", nil))
+			if w.Code != http.StatusNotFound {
+				t.Errorf("unknown API route status = %d, want 404", w.Code)
+			}
+		})
+	}
+}
+
+func TestWebHandler_ServesAssets(t *testing.T) {
+	s := newTestServer(t)
+	s.SetWebFS(fstest.MapFS{"index.html": {Data: []byte("<h1>dashboard</h1>")}})
+	if !s.DashboardAvailable() {
+		t.Fatal("expected the dashboard to be available")
+	}
+
+	w := httptest.NewRecorder()
+	s.webHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "dashboard") {
+		t.Errorf("got %d %q, want the index page", w.Code, w.Body.String())
+	}
+}
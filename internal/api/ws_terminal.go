@@ -209,6 +209,11 @@ func (s *Server) handleWSChat(ctx context.Context, conn *websocket.Conn, req *WS
 		})
 
 	case <-chatCtx.Done():
+		// Nobody is waiting for the answer any more: stop the agent
+		// working on it, including an edge agent it was forwarded to.
+		if s.orch != nil {
+			s.orch.CancelMessage(msgID)
+		}
 		s.wsSendResponse(ctx, conn, WSResponse{
 			Type:      "error",
 			RequestID: req.RequestID,
@@ -2,8 +2,11 @@ package channels
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/clawinfra/evoclaw/internal/types"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
@@ -132,4 +135,19 @@ func TestMQTT_BroadcastPublishError(t *testing.T) {
 	}
 }
 
+// Test SendPromptAndWait reports a silent edge agent as ErrEdgeTimeout
+func TestMQTT_SendPromptAndWaitTimeout(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+	m.client = &MockMQTTClient{IsConnectedVal: true}
+	m.edgeAgents["pi"] = &EdgeAgentInfo{AgentID: "pi", LastSeen: time.Now()}
+
+	_, err := m.SendPromptAndWait(context.Background(), "pi", "hello", "", 20*time.Millisecond)
+	if !errors.Is(err, ErrEdgeTimeout) {
+		t.Fatalf("err = %v, want ErrEdgeTimeout", err)
+	}
+	if !strings.Contains(err.Error(), "pi") {
+		t.Errorf("err = %q, want the agent ID in it", err)
+	}
+}
+
 // The Telegram tests require proper HTTP mocking which is already done in telegram_comprehensive_test.go
@@ -5,25 +5,37 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/clawinfra/evoclaw/internal/tracing"
 	"github.com/clawinfra/evoclaw/internal/types"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 )
 
+// DefaultTopicPrefix is the namespace all MQTT topics live under unless
+// SetTopicPrefix is called.
+const DefaultTopicPrefix = "evoclaw"
+
 const (
-	// MQTT topics for agent communication
-	commandsTopic      = "evoclaw/agents/%s/commands"      // orchestrator → agent
-	reportsTopic       = "evoclaw/agents/%s/reports"       // agent → orchestrator
-	broadcastTopic     = "evoclaw/broadcast"               // orchestrator → all agents
-	statusTopic        = "evoclaw/agents/%s/status"        // agent heartbeats
-	capabilitiesTopic  = "evoclaw/agents/%s/capabilities"  // agent capability advertisement (retained)
+	// MQTT topics for agent communication, relative to the topic prefix
+	commandsTopic           = "agents/%s/commands"     // orchestrator → agent
+	reportsTopic            = "agents/%s/reports"      // agent → orchestrator
+	broadcastTopic          = "broadcast"              // orchestrator → all agents
+	statusTopic             = "agents/%s/status"       // agent heartbeats
+	capabilitiesTopic       = "agents/%s/capabilities" // agent capability advertisement (retained)
+	orchestratorStatusTopic = "orchestrator/status"    // orchestrator liveness (retained, "online"/"offline")
 )
 
+// ErrEdgeTimeout is returned by SendPromptAndWait when the edge agent does
+// not answer within the timeout.
+var ErrEdgeTimeout = errors.New("timeout waiting for response")
+
 // EdgeAgentCommand represents the message format expected by Rust edge agents
 type EdgeAgentCommand struct {
 	Command   string                 `json:"command"`   // "message", "ping", "status", etc.
@@ -40,13 +52,25 @@ type EdgeAgentInfo struct {
 	CPU          float64
 	MemoryMB     float64
 	Capabilities string    // one-liner capability summary, published on startup
+	// Structured capabilities, when the agent advertised a list
+	CapabilitySpecs []EdgeCapability
+
+	offline bool // set by the presence sweeper after a missed heartbeat window
 }
 
 // PendingRequest tracks a request waiting for response
 type PendingRequest struct {
 	RequestID string
+	AgentID   string
 	Response  chan *EdgeAgentResponse
 	CreatedAt time.Time
+	// Deadline is when the janitor gives up on the request and fails its
+	// waiter. Zero means never.
+	Deadline time.Time
+	// owned is set while a SendPromptAndWait call waits on Response; that
+	// call times the request out and removes it, so the janitor leaves it
+	// alone.
+	owned bool
 }
 
 // EdgeAgentResponse represents a response from an edge agent
@@ -73,18 +97,39 @@ type MQTTChannel struct {
 	client    MQTTClient
 	ctx       context.Context
 	cancel    context.CancelFunc
-	wg        sync.WaitGroup
+	// wg tracks running Paho callbacks and background goroutines; Stop
+	// waits for it before closing inbox. handlersMu guards stopped and
+	// every wg.Add made by a callback, so none can start once Stop has.
+	wg         sync.WaitGroup
+	handlersMu sync.Mutex
+	stopped    bool
 	// Factory function for creating MQTT client
 	clientFactory func(opts *mqtt.ClientOptions) MQTTClient
 	// Result callback for tool execution results
 	resultCallback func(requestID string, result map[string]interface{})
+	chunkCallback  func(requestID string, seq int, content string)
 	resultMu       sync.RWMutex
+	// Streamed answers being reassembled, by request ID
+	chunks   map[string]*chunkBuffer
+	chunksMu sync.Mutex
 	// Edge agent tracking
 	edgeAgents   map[string]*EdgeAgentInfo
 	edgeAgentsMu sync.RWMutex
 	// Pending requests waiting for responses
 	pendingRequests   map[string]*PendingRequest
 	pendingRequestsMu sync.RWMutex
+	// Broadcast commands waiting for acks, by request ID
+	broadcastAcks   map[string]chan<- BroadcastAck
+	broadcastAcksMu sync.RWMutex
+	// Presence events for edge agents going online/offline
+	presenceCallback func(PresenceEvent)
+	presenceTimeout  time.Duration // 0 = defaultPresenceTimeout
+	edgeAgentTTL     time.Duration // 0 = defaultEdgeAgentTTL
+	presenceMu       sync.RWMutex
+
+	// topicPrefix namespaces every topic so deployments sharing a broker
+	// don't see each other's traffic ("" = DefaultTopicPrefix).
+	topicPrefix string
 }
 
 // NewMQTT creates a new MQTT channel adapter
@@ -103,6 +148,8 @@ func NewMQTT(broker string, port int, username, password string, logger *slog.Lo
 		resultCallback:  nil, // Will be set by orchestrator
 		edgeAgents:      make(map[string]*EdgeAgentInfo),
 		pendingRequests: make(map[string]*PendingRequest),
+		broadcastAcks:   make(map[string]chan<- BroadcastAck),
+		chunks:          make(map[string]*chunkBuffer),
 	}
 }
 
@@ -120,6 +167,8 @@ func NewMQTTWithClient(broker string, port int, username, password string, logge
 		resultCallback:  nil, // Will be set by orchestrator
 		edgeAgents:      make(map[string]*EdgeAgentInfo),
 		pendingRequests: make(map[string]*PendingRequest),
+		broadcastAcks:   make(map[string]chan<- BroadcastAck),
+		chunks:          make(map[string]*chunkBuffer),
 	}
 }
 
@@ -141,10 +190,32 @@ func NewMQTTWithTLS(broker string, port int, username, password string, tlsConfi
 		resultCallback:  nil,
 		edgeAgents:      make(map[string]*EdgeAgentInfo),
 		pendingRequests: make(map[string]*PendingRequest),
+		broadcastAcks:   make(map[string]chan<- BroadcastAck),
+		chunks:          make(map[string]*chunkBuffer),
 	}
 	return ch
 }
 
+// SetTopicPrefix sets the namespace all topics are published and
+// subscribed under, e.g. "tenant-a/evoclaw". Edge agents must use the same
+// prefix. Call it before Start.
+func (m *MQTTChannel) SetTopicPrefix(prefix string) {
+	m.topicPrefix = strings.Trim(prefix, "/")
+}
+
+// prefix returns the topic prefix in use.
+func (m *MQTTChannel) prefix() string {
+	if m.topicPrefix == "" {
+		return DefaultTopicPrefix
+	}
+	return m.topicPrefix
+}
+
+// topic returns the full topic for one of the relative topic formats.
+func (m *MQTTChannel) topic(format string, args ...any) string {
+	return m.prefix() + "/" + fmt.Sprintf(format, args...)
+}
+
 // NewMQTTTLSConfig builds a *tls.Config for mutual TLS from raw PEM bytes.
 //   - caCert:     PEM-encoded CA certificate (used to verify the broker)
 //   - clientCert: PEM-encoded client certificate (may be nil for server-only TLS)
@@ -175,6 +246,35 @@ func NewMQTTTLSConfig(caCert, clientCert, clientKey []byte) (*tls.Config, error)
 	return tlsCfg, nil
 }
 
+// LoadMQTTTLSConfig builds a *tls.Config from PEM files: an optional CA
+// certificate to verify the broker (system roots otherwise) and an optional
+// client certificate and key for mutual TLS. insecureSkipVerify disables
+// broker certificate verification and is meant for development only.
+func LoadMQTTTLSConfig(caFile, certFile, keyFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	var caCert, clientCert, clientKey []byte
+	var err error
+	if caFile != "" {
+		if caCert, err = os.ReadFile(caFile); err != nil {
+			return nil, fmt.Errorf("read CA certificate: %w", err)
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		if clientCert, err = os.ReadFile(certFile); err != nil {
+			return nil, fmt.Errorf("read client certificate: %w", err)
+		}
+		if clientKey, err = os.ReadFile(keyFile); err != nil {
+			return nil, fmt.Errorf("read client key: %w", err)
+		}
+	}
+
+	tlsCfg, err := NewMQTTTLSConfig(caCert, clientCert, clientKey)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg.InsecureSkipVerify = insecureSkipVerify
+	return tlsCfg, nil
+}
+
 func (m *MQTTChannel) Name() string {
 	return "mqtt"
 }
@@ -208,6 +308,10 @@ func (m *MQTTChannel) Start(ctx context.Context) error {
 	opts.SetAutoReconnect(true)
 	opts.SetMaxReconnectInterval(30 * time.Second)
 
+	// Last will: the broker marks the orchestrator offline if we vanish
+	// without a clean disconnect, so edge agents stop waiting on commands.
+	opts.SetWill(m.topic(orchestratorStatusTopic), "offline", 1, true)
+
 	// Connection lost handler
 	opts.SetConnectionLostHandler(func(c mqtt.Client, err error) {
 		m.logger.Warn("mqtt connection lost", "error", err)
@@ -219,6 +323,9 @@ func (m *MQTTChannel) Start(ctx context.Context) error {
 		if err := m.subscribe(); err != nil {
 			m.logger.Error("failed to subscribe", "error", err)
 		}
+		if err := m.publishOrchestratorStatus("online"); err != nil {
+			m.logger.Error("failed to publish orchestrator status", "error", err)
+		}
 	})
 
 	m.client = m.clientFactory(opts)
@@ -233,11 +340,26 @@ func (m *MQTTChannel) Start(ctx context.Context) error {
 		return fmt.Errorf("connect to mqtt: %w", err)
 	}
 
+	m.wg.Add(1)
+	go m.runJanitor()
+
 	m.logger.Info("mqtt channel started")
 	return nil
 }
 
+// Stop shuts the channel down: new callbacks are refused and in-flight
+// ones stop forwarding, the client disconnects, and inbox is closed only
+// after every callback that could still send to it has returned. Calling
+// Stop again is a no-op.
 func (m *MQTTChannel) Stop() error {
+	m.handlersMu.Lock()
+	if m.stopped {
+		m.handlersMu.Unlock()
+		return nil
+	}
+	m.stopped = true
+	m.handlersMu.Unlock()
+
 	m.logger.Info("stopping mqtt channel")
 
 	if m.cancel != nil {
@@ -245,6 +367,10 @@ func (m *MQTTChannel) Stop() error {
 	}
 
 	if m.client != nil && m.client.IsConnected() {
+		// A clean disconnect suppresses the will, so announce it ourselves.
+		if err := m.publishOrchestratorStatus("offline"); err != nil {
+			m.logger.Warn("failed to publish orchestrator status", "error", err)
+		}
 		m.client.Disconnect(250)
 	}
 
@@ -253,13 +379,26 @@ func (m *MQTTChannel) Stop() error {
 	return nil
 }
 
+// enterHandler registers a running Paho callback with wg. It returns false
+// once Stop has begun, in which case the callback must return without
+// touching inbox; otherwise the callback must call wg.Done when finished.
+func (m *MQTTChannel) enterHandler() bool {
+	m.handlersMu.Lock()
+	defer m.handlersMu.Unlock()
+	if m.stopped {
+		return false
+	}
+	m.wg.Add(1)
+	return true
+}
+
 func (m *MQTTChannel) Send(ctx context.Context, msg types.Response) error {
 	if !m.client.IsConnected() {
 		return fmt.Errorf("mqtt not connected")
 	}
 
 	// Determine the topic based on the recipient
-	topic := fmt.Sprintf(commandsTopic, msg.To)
+	topic := m.topic(commandsTopic, msg.To)
 
 	// Convert orchestrator Response to edge agent command format
 	// Generate request_id from metadata or create a new one
@@ -296,6 +435,11 @@ func (m *MQTTChannel) Send(ctx context.Context, msg types.Response) error {
 		}
 	}
 
+	// Continue the caller's trace on the edge agent
+	if tp := tracing.Traceparent(ctx); tp != "" {
+		cmd.Payload[tracing.HeaderName] = tp
+	}
+
 	// Serialize message to edge agent format
 	payload, err := json.Marshal(cmd)
 	if err != nil {
@@ -315,6 +459,25 @@ func (m *MQTTChannel) Send(ctx context.Context, msg types.Response) error {
 	return nil
 }
 
+// publishOrchestratorStatus publishes the retained orchestrator liveness
+// status that edge agents watch.
+func (m *MQTTChannel) publishOrchestratorStatus(status string) error {
+	topic := m.topic(orchestratorStatusTopic)
+	token := m.client.Publish(topic, 1, true, status)
+	if !token.WaitTimeout(5 * time.Second) {
+		return fmt.Errorf("publish %s: timeout", topic)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("publish %s: %w", topic, err)
+	}
+	return nil
+}
+
+// IsConnected reports whether the channel currently holds a broker connection.
+func (m *MQTTChannel) IsConnected() bool {
+	return m.client != nil && m.client.IsConnected()
+}
+
 func (m *MQTTChannel) Receive() <-chan types.Message {
 	return m.inbox
 }
@@ -322,7 +485,7 @@ func (m *MQTTChannel) Receive() <-chan types.Message {
 // subscribe to relevant MQTT topics
 func (m *MQTTChannel) subscribe() error {
 	// Subscribe to all agent reports (wildcard)
-	reportPattern := "evoclaw/agents/+/reports"
+	reportPattern := m.topic(reportsTopic, "+")
 	token := m.client.Subscribe(reportPattern, 1, m.handleMessage)
 	if !token.WaitTimeout(5 * time.Second) {
 		return fmt.Errorf("subscribe timeout")
@@ -333,7 +496,7 @@ func (m *MQTTChannel) subscribe() error {
 	m.logger.Info("subscribed", "topic", reportPattern)
 
 	// Subscribe to all agent status updates
-	statusPattern := "evoclaw/agents/+/status"
+	statusPattern := m.topic(statusTopic, "+")
 	token = m.client.Subscribe(statusPattern, 1, m.handleStatus)
 	if !token.WaitTimeout(5 * time.Second) {
 		return fmt.Errorf("subscribe timeout")
@@ -344,7 +507,7 @@ func (m *MQTTChannel) subscribe() error {
 	m.logger.Info("subscribed", "topic", statusPattern)
 
 	// Subscribe to agent capability advertisements (retained messages — delivered immediately on connect)
-	capPattern := "evoclaw/agents/+/capabilities"
+	capPattern := m.topic(capabilitiesTopic, "+")
 	token = m.client.Subscribe(capPattern, 1, m.handleCapabilities)
 	if !token.WaitTimeout(5 * time.Second) {
 		return fmt.Errorf("subscribe timeout")
@@ -358,36 +521,47 @@ func (m *MQTTChannel) subscribe() error {
 }
 
 // handleCapabilities processes capability advertisement messages from edge agents.
-// Edge agents publish a retained message on startup describing what they can do.
+// Edge agents publish a retained message on startup describing what they can do,
+// either as a one-line summary or as a list of structured capabilities.
 func (m *MQTTChannel) handleCapabilities(client mqtt.Client, mqttMsg mqtt.Message) {
-	var payload struct {
-		AgentID      string `json:"agent_id"`
-		Capabilities string `json:"capabilities"` // one-liner summary
+	if !m.enterHandler() {
+		return
 	}
-	if err := json.Unmarshal(mqttMsg.Payload(), &payload); err != nil {
+	defer m.wg.Done()
+
+	adv, skipped, err := parseCapabilityAdvertisement(mqttMsg.Payload())
+	if err != nil {
 		m.logger.Warn("failed to parse capabilities message", "error", err)
 		return
 	}
-	if payload.AgentID == "" || payload.Capabilities == "" {
+	if adv.AgentID == "" || adv.Summary == "" {
 		return
 	}
+	if len(skipped) > 0 {
+		m.logger.Warn("ignoring invalid edge agent capabilities", "agent", adv.AgentID, "skipped", skipped)
+	}
 
+	update := func(info *EdgeAgentInfo) {
+		info.Capabilities = adv.Summary
+		info.CapabilitySpecs = adv.Structured
+	}
+
+	// Capabilities are retained, so an update for a known agent is not a
+	// sign of life; only a first sighting marks the agent online.
 	m.edgeAgentsMu.Lock()
-	if existing, ok := m.edgeAgents[payload.AgentID]; ok {
-		existing.Capabilities = payload.Capabilities
-	} else {
-		m.edgeAgents[payload.AgentID] = &EdgeAgentInfo{
-			AgentID:      payload.AgentID,
-			Status:       "online",
-			LastSeen:     time.Now(),
-			Capabilities: payload.Capabilities,
-		}
+	existing, known := m.edgeAgents[adv.AgentID]
+	if known {
+		update(existing)
 	}
 	m.edgeAgentsMu.Unlock()
+	if !known {
+		m.touchEdgeAgent(adv.AgentID, update)
+	}
 
 	m.logger.Info("edge agent capabilities registered",
-		"agent", payload.AgentID,
-		"capabilities", payload.Capabilities,
+		"agent", adv.AgentID,
+		"capabilities", adv.Summary,
+		"structured", len(adv.Structured),
 	)
 }
 
@@ -408,7 +582,7 @@ func (m *MQTTChannel) GetOnlineAgentsWithCapabilities() map[string]string {
 
 	result := make(map[string]string)
 	for id, info := range m.edgeAgents {
-		if time.Since(info.LastSeen) < 2*time.Minute {
+		if !info.offline && time.Since(info.LastSeen) < m.heartbeatTimeout() {
 			result[id] = info.Capabilities
 		}
 	}
@@ -419,28 +593,33 @@ func (m *MQTTChannel) GetOnlineAgentsWithCapabilities() map[string]string {
 type AgentReport struct {
 	AgentID    string                 `json:"agent_id"`
 	AgentType  string                 `json:"agent_type"`
-	ReportType string                 `json:"report_type"` // "result", "error", "heartbeat", "metric"
+	ReportType string                 `json:"report_type"` // "result", "error", "ack", "heartbeat", "metric"
 	Payload    map[string]interface{} `json:"payload"`
 	Timestamp  int64                  `json:"timestamp"`
 }
 
 // handleMessage processes incoming messages from agents
 func (m *MQTTChannel) handleMessage(client mqtt.Client, mqttMsg mqtt.Message) {
-	m.wg.Add(1)
+	if !m.enterHandler() {
+		return
+	}
 	defer m.wg.Done()
 
-	m.logger.Info("incoming message", "channel", "mqtt", "from", extractAgentID(mqttMsg.Topic()), "length", len(mqttMsg.Payload()))
-
 	// Extract agent ID from topic for heartbeat tracking
-	topic := mqttMsg.Topic()
-	var agentIDFromTopic string
-	if parts := strings.Split(topic, "/"); len(parts) >= 3 {
-		agentIDFromTopic = parts[2]
-	}
+	agentIDFromTopic := m.topicAgentID(mqttMsg.Topic())
+	m.logger.Info("incoming message", "channel", "mqtt", "from", agentIDFromTopic, "length", len(mqttMsg.Payload()))
 
 	// Try to parse as AgentReport first (new edge agent format)
 	var report AgentReport
 	if err := json.Unmarshal(mqttMsg.Payload(), &report); err == nil {
+		// Acks for a broadcast command are collected by its sender
+		switch report.ReportType {
+		case "ack", "result", "error":
+			if m.deliverBroadcastAck(report, agentIDFromTopic) {
+				return
+			}
+		}
+
 		// Handle different report types
 		switch report.ReportType {
 		case "result":
@@ -459,6 +638,18 @@ func (m *MQTTChannel) handleMessage(client mqtt.Client, mqttMsg mqtt.Message) {
 			)
 			m.handleEdgeAgentResult(report)
 			return
+		case "chunk":
+			// Part of a streamed answer
+			m.handleEdgeAgentChunk(report)
+			return
+		case "complete":
+			// End of a streamed answer
+			m.logger.Debug("complete report detected",
+				"agent", report.AgentID,
+				"request_id", report.Payload["request_id"],
+			)
+			m.handleEdgeAgentComplete(report)
+			return
 		case "heartbeat":
 			// Heartbeat - update tracking state and return
 			m.logger.Debug("heartbeat received", "agent", report.AgentID)
@@ -467,17 +658,7 @@ func (m *MQTTChannel) handleMessage(client mqtt.Client, mqttMsg mqtt.Message) {
 				agentID = agentIDFromTopic
 			}
 			if agentID != "" {
-				m.edgeAgentsMu.Lock()
-				if existing, ok := m.edgeAgents[agentID]; ok {
-					existing.LastSeen = time.Now()
-				} else {
-					m.edgeAgents[agentID] = &EdgeAgentInfo{
-						AgentID:  agentID,
-						Status:   "online",
-						LastSeen: time.Now(),
-					}
-				}
-				m.edgeAgentsMu.Unlock()
+				m.touchEdgeAgent(agentID, nil)
 			}
 			return
 		}
@@ -539,6 +720,11 @@ func (m *MQTTChannel) handleMessage(client mqtt.Client, mqttMsg mqtt.Message) {
 	}
 	msg.Metadata["mqtt_topic"] = mqttMsg.Topic()
 
+	// Stop cancels the context before waiting for handlers; don't forward
+	// anything once it has.
+	if m.ctx.Err() != nil {
+		return
+	}
 	select {
 	case m.inbox <- msg:
 		m.logger.Debug("message queued", "from", msg.From, "length", len(msg.Content))
@@ -552,6 +738,8 @@ func (m *MQTTChannel) handleMessage(client mqtt.Client, mqttMsg mqtt.Message) {
 // handleEdgeAgentResult processes result/error reports from edge agents
 func (m *MQTTChannel) handleEdgeAgentResult(report AgentReport) {
 	requestID, _ := report.Payload["request_id"].(string)
+	// A result or error ends the request, streamed or not
+	m.takeChunks(requestID)
 
 	m.resultMu.RLock()
 	callback := m.resultCallback
@@ -575,33 +763,23 @@ func (m *MQTTChannel) handleEdgeAgentResult(report AgentReport) {
 	)
 }
 
-// extractAgentID extracts agent ID from topic like "evoclaw/agents/alex-eye/reports"
-func extractAgentID(topic string) string {
-	parts := []byte(topic)
-	// Find the third slash
-	slashCount := 0
-	start := 0
-	end := len(parts)
-	for i, b := range parts {
-		if b == '/' {
-			slashCount++
-			if slashCount == 2 {
-				start = i + 1
-			} else if slashCount == 3 {
-				end = i
-				break
-			}
-		}
+// topicAgentID extracts the agent ID from a topic like
+// "<prefix>/agents/alex-eye/reports", or returns "" if topic is not an
+// agent topic under the channel's prefix.
+func (m *MQTTChannel) topicAgentID(topic string) string {
+	rest, ok := strings.CutPrefix(topic, m.prefix()+"/agents/")
+	if !ok {
+		return ""
 	}
-	if start > 0 && end > start {
-		return string(parts[start:end])
-	}
-	return "unknown"
+	agentID, _, _ := strings.Cut(rest, "/")
+	return agentID
 }
 
 // handleStatus processes agent heartbeat/status updates
 func (m *MQTTChannel) handleStatus(client mqtt.Client, mqttMsg mqtt.Message) {
-	m.wg.Add(1)
+	if !m.enterHandler() {
+		return
+	}
 	defer m.wg.Done()
 
 	m.logger.Debug("agent status update", "topic", mqttMsg.Topic())
@@ -621,16 +799,12 @@ func (m *MQTTChannel) handleStatus(client mqtt.Client, mqttMsg mqtt.Message) {
 	}
 
 	// Update edge agent registry
-	m.edgeAgentsMu.Lock()
-	m.edgeAgents[status.AgentID] = &EdgeAgentInfo{
-		AgentID:  status.AgentID,
-		Status:   status.Status,
-		LastSeen: time.Now(),
-		Uptime:   status.Uptime,
-		CPU:      status.CPU,
-		MemoryMB: status.Memory,
-	}
-	m.edgeAgentsMu.Unlock()
+	m.touchEdgeAgent(status.AgentID, func(info *EdgeAgentInfo) {
+		info.Status = status.Status
+		info.Uptime = status.Uptime
+		info.CPU = status.CPU
+		info.MemoryMB = status.Memory
+	})
 
 	m.logger.Debug("agent status updated",
 		"agent", status.AgentID,
@@ -653,7 +827,7 @@ func (m *MQTTChannel) Broadcast(ctx context.Context, content string) error {
 		return fmt.Errorf("marshal broadcast: %w", err)
 	}
 
-	token := m.client.Publish(broadcastTopic, 1, false, payload)
+	token := m.client.Publish(m.topic(broadcastTopic), 1, false, payload)
 	if !token.WaitTimeout(5 * time.Second) {
 		return fmt.Errorf("broadcast timeout")
 	}
@@ -709,8 +883,8 @@ func (m *MQTTChannel) IsEdgeAgentOnline(agentID string) bool {
 		return false
 	}
 
-	// Consider online if seen within last 2 minutes
-	return time.Since(info.LastSeen) < 2*time.Minute
+	// Consider online if seen within the presence timeout
+	return !info.offline && time.Since(info.LastSeen) < m.heartbeatTimeout()
 }
 
 // GetEdgeAgentInfo returns info about an edge agent
@@ -733,7 +907,7 @@ func (m *MQTTChannel) GetOnlineEdgeAgents() []string {
 
 	var online []string
 	for id, info := range m.edgeAgents {
-		if time.Since(info.LastSeen) < 2*time.Minute {
+		if !info.offline && time.Since(info.LastSeen) < m.heartbeatTimeout() {
 			online = append(online, id)
 		}
 	}
@@ -760,10 +934,14 @@ func (m *MQTTChannel) SendPromptAndWait(ctx context.Context, agentID, prompt, sy
 
 	// Register pending request
 	m.pendingRequestsMu.Lock()
+	now := time.Now()
 	m.pendingRequests[requestID] = &PendingRequest{
 		RequestID: requestID,
+		AgentID:   agentID,
 		Response:  respChan,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
+		Deadline:  now.Add(timeout),
+		owned:     true,
 	}
 	m.pendingRequestsMu.Unlock()
 
@@ -785,8 +963,12 @@ func (m *MQTTChannel) SendPromptAndWait(ctx context.Context, agentID, prompt, sy
 		},
 	}
 
+	if tp := tracing.Traceparent(ctx); tp != "" {
+		cmd.Payload[tracing.HeaderName] = tp
+	}
+
 	// Serialize and publish
-	topic := fmt.Sprintf(commandsTopic, agentID)
+	topic := m.topic(commandsTopic, agentID)
 	payload, err := json.Marshal(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("marshal command: %w", err)
@@ -814,7 +996,7 @@ func (m *MQTTChannel) SendPromptAndWait(ctx context.Context, agentID, prompt, sy
 	case resp := <-respChan:
 		return resp, nil
 	case <-timeoutCtx.Done():
-		return nil, fmt.Errorf("timeout waiting for response from %s", agentID)
+		return nil, fmt.Errorf("%w from %s", ErrEdgeTimeout, agentID)
 	}
 }
 
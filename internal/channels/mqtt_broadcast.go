@@ -0,0 +1,101 @@
+package channels
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BroadcastAck is an edge agent's reply to a broadcast command, sent as an
+// "ack", "result" or "error" report carrying the command's request_id.
+type BroadcastAck struct {
+	AgentID    string
+	RequestID  string
+	Status     string // "ok" or "error"
+	Error      string
+	ReceivedAt time.Time
+}
+
+// BroadcastCommand publishes cmd on the broadcast topic so every edge agent
+// receives it. Replies carrying cmd.RequestID are delivered to acks until
+// the returned stop function is called; replies that find acks full are
+// dropped, so size it for the expected number of agents.
+func (m *MQTTChannel) BroadcastCommand(cmd EdgeAgentCommand, acks chan<- BroadcastAck) (stop func(), err error) {
+	if !m.client.IsConnected() {
+		return nil, fmt.Errorf("mqtt not connected")
+	}
+	if cmd.RequestID == "" {
+		return nil, fmt.Errorf("broadcast command needs a request ID")
+	}
+
+	payload, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("marshal command: %w", err)
+	}
+
+	// Register before publishing so a fast agent's ack is not missed.
+	m.broadcastAcksMu.Lock()
+	m.broadcastAcks[cmd.RequestID] = acks
+	m.broadcastAcksMu.Unlock()
+	stop = func() {
+		m.broadcastAcksMu.Lock()
+		delete(m.broadcastAcks, cmd.RequestID)
+		m.broadcastAcksMu.Unlock()
+	}
+
+	token := m.client.Publish(m.topic(broadcastTopic), 1, false, payload)
+	if !token.WaitTimeout(5 * time.Second) {
+		stop()
+		return nil, fmt.Errorf("broadcast timeout")
+	}
+	if err := token.Error(); err != nil {
+		stop()
+		return nil, fmt.Errorf("broadcast: %w", err)
+	}
+
+	m.logger.Info("broadcast command sent", "command", cmd.Command, "request_id", cmd.RequestID)
+	return stop, nil
+}
+
+// deliverBroadcastAck routes a report answering a pending broadcast command
+// to its waiter. It reports whether the report was consumed.
+func (m *MQTTChannel) deliverBroadcastAck(report AgentReport, agentIDFromTopic string) bool {
+	requestID, _ := report.Payload["request_id"].(string)
+	if requestID == "" {
+		return false
+	}
+
+	m.broadcastAcksMu.RLock()
+	acks, ok := m.broadcastAcks[requestID]
+	m.broadcastAcksMu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	ack := BroadcastAck{
+		AgentID:    report.AgentID,
+		RequestID:  requestID,
+		Status:     "ok",
+		ReceivedAt: time.Now(),
+	}
+	if ack.AgentID == "" {
+		ack.AgentID = agentIDFromTopic
+	}
+	if status, ok := report.Payload["status"].(string); ok && status != "" {
+		ack.Status = status
+	}
+	if errMsg, ok := report.Payload["error"].(string); ok && errMsg != "" {
+		ack.Error = errMsg
+		ack.Status = "error"
+	}
+	if report.ReportType == "error" {
+		ack.Status = "error"
+	}
+
+	select {
+	case acks <- ack:
+	default:
+		m.logger.Warn("broadcast ack dropped, receiver full", "request_id", requestID, "agent", ack.AgentID)
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package channels
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EdgeCapability is one action an edge agent advertises, with the JSON
+// Schema its params must satisfy.
+type EdgeCapability struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"input_schema,omitempty"`
+}
+
+// capabilityAdvertisement is a message on the capabilities topic. The
+// capabilities field is either a one-line summary (older agents) or a list
+// of EdgeCapability; for a list the summary is built from the names.
+type capabilityAdvertisement struct {
+	AgentID    string
+	Summary    string
+	Structured []EdgeCapability
+}
+
+// parseCapabilityAdvertisement decodes a capabilities message. Structured
+// entries without a name, with a duplicate name or with an input schema that
+// does not describe an object are dropped and reported in skipped.
+func parseCapabilityAdvertisement(data []byte) (adv capabilityAdvertisement, skipped []string, err error) {
+	var raw struct {
+		AgentID      string          `json:"agent_id"`
+		Capabilities json.RawMessage `json:"capabilities"`
+		Summary      string          `json:"summary"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return adv, nil, err
+	}
+	adv.AgentID = raw.AgentID
+
+	trimmed := strings.TrimSpace(string(raw.Capabilities))
+	switch {
+	case trimmed == "" || trimmed == "null":
+	case strings.HasPrefix(trimmed, "["):
+		var list []EdgeCapability
+		if err := json.Unmarshal(raw.Capabilities, &list); err != nil {
+			return adv, nil, fmt.Errorf("capabilities list: %w", err)
+		}
+		seen := make(map[string]bool, len(list))
+		for i, c := range list {
+			c.Name = strings.TrimSpace(c.Name)
+			switch {
+			case c.Name == "":
+				skipped = append(skipped, fmt.Sprintf("#%d: missing name", i))
+				continue
+			case seen[c.Name]:
+				skipped = append(skipped, fmt.Sprintf("%s: duplicate name", c.Name))
+				continue
+			}
+			if t, ok := c.InputSchema["type"]; ok && t != "object" {
+				skipped = append(skipped, fmt.Sprintf("%s: input_schema type must be object", c.Name))
+				continue
+			}
+			seen[c.Name] = true
+			adv.Structured = append(adv.Structured, c)
+		}
+		sort.Slice(adv.Structured, func(i, j int) bool { return adv.Structured[i].Name < adv.Structured[j].Name })
+	default:
+		if err := json.Unmarshal(raw.Capabilities, &adv.Summary); err != nil {
+			return adv, nil, fmt.Errorf("capabilities must be a string or a list: %w", err)
+		}
+	}
+
+	if raw.Summary != "" {
+		adv.Summary = raw.Summary
+	} else if adv.Summary == "" && len(adv.Structured) > 0 {
+		names := make([]string, len(adv.Structured))
+		for i, c := range adv.Structured {
+			names[i] = c.Name
+		}
+		adv.Summary = strings.Join(names, ", ")
+	}
+	return adv, skipped, nil
+}
+
+// GetOnlineAgentCapabilitySpecs returns the structured capabilities of each
+// online edge agent that advertised any.
+func (m *MQTTChannel) GetOnlineAgentCapabilitySpecs() map[string][]EdgeCapability {
+	m.edgeAgentsMu.RLock()
+	defer m.edgeAgentsMu.RUnlock()
+
+	result := make(map[string][]EdgeCapability)
+	for id, info := range m.edgeAgents {
+		if len(info.CapabilitySpecs) > 0 && !info.offline && time.Since(info.LastSeen) < m.heartbeatTimeout() {
+			result[id] = append([]EdgeCapability(nil), info.CapabilitySpecs...)
+		}
+	}
+	return result
+}
+
+// GetEdgeAgentCapabilitySpecs returns the structured capabilities an edge
+// agent advertised, or nil if it only sent a summary.
+func (m *MQTTChannel) GetEdgeAgentCapabilitySpecs(agentID string) []EdgeCapability {
+	m.edgeAgentsMu.RLock()
+	defer m.edgeAgentsMu.RUnlock()
+	if info, ok := m.edgeAgents[agentID]; ok {
+		return append([]EdgeCapability(nil), info.CapabilitySpecs...)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package channels
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseStructuredCapabilities(t *testing.T) {
+	payload := []byte(`{
+		"agent_id": "pi",
+		"capabilities": [
+			{"name": "snapshot", "description": "Take a camera picture",
+			 "input_schema": {"type": "object", "properties": {"resolution": {"type": "string", "enum": ["640x480", "1280x720"]}}}},
+			{"name": "read_gpio", "description": "Read a GPIO pin",
+			 "input_schema": {"type": "object", "properties": {"pin": {"type": "integer"}}, "required": ["pin"]}},
+			{"name": ""},
+			{"name": "snapshot"},
+			{"name": "blink", "input_schema": {"type": "string"}}
+		]
+	}`)
+
+	adv, skipped, err := parseCapabilityAdvertisement(payload)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if adv.AgentID != "pi" || adv.Summary != "read_gpio, snapshot" {
+		t.Errorf("agent = %q, summary = %q", adv.AgentID, adv.Summary)
+	}
+	if len(adv.Structured) != 2 || adv.Structured[0].Name != "read_gpio" || adv.Structured[1].Name != "snapshot" {
+		t.Fatalf("structured = %+v, want read_gpio and snapshot", adv.Structured)
+	}
+	if req := adv.Structured[0].InputSchema["required"].([]interface{}); len(req) != 1 || req[0] != "pin" {
+		t.Errorf("read_gpio schema lost its required list: %v", adv.Structured[0].InputSchema)
+	}
+	if len(skipped) != 3 {
+		t.Errorf("skipped = %v, want the nameless, duplicate and non-object entries", skipped)
+	}
+
+	// The old one-line form still works and has no structured entries.
+	adv, _, err = parseCapabilityAdvertisement([]byte(`{"agent_id":"pi","capabilities":"gpio, camera"}`))
+	if err != nil || adv.Summary != "gpio, camera" || adv.Structured != nil {
+		t.Errorf("summary form: adv = %+v, err = %v", adv, err)
+	}
+
+	if _, _, err := parseCapabilityAdvertisement([]byte(`{"agent_id":"pi","capabilities":42}`)); err == nil {
+		t.Error("expected an error for a numeric capabilities field")
+	}
+}
+
+func TestHandleStructuredCapabilities(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+
+	caps, _ := json.Marshal(map[string]interface{}{
+		"agent_id": "pi",
+		"summary":  "camera node",
+		"capabilities": []EdgeCapability{
+			{Name: "snapshot", Description: "Take a camera picture"},
+		},
+	})
+	m.handleCapabilities(nil, &MockMQTTMessage{topic: "evoclaw/agents/pi/capabilities", payload: caps})
+
+	info := m.GetEdgeAgentInfo("pi")
+	if info == nil || info.Capabilities != "camera node" {
+		t.Fatalf("info = %+v, want summary %q", info, "camera node")
+	}
+	specs := m.GetOnlineAgentCapabilitySpecs()["pi"]
+	if len(specs) != 1 || specs[0].Name != "snapshot" {
+		t.Errorf("online specs = %+v", specs)
+	}
+
+	// A later summary-only advertisement clears the structured list.
+	caps, _ = json.Marshal(map[string]string{"agent_id": "pi", "capabilities": "camera"})
+	m.handleCapabilities(nil, &MockMQTTMessage{topic: "evoclaw/agents/pi/capabilities", payload: caps})
+	if specs := m.GetEdgeAgentCapabilitySpecs("pi"); len(specs) != 0 {
+		t.Errorf("specs = %+v after summary-only update, want none", specs)
+	}
+}
@@ -0,0 +1,150 @@
+package channels
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// chunkBufferTTL is how long a partial answer is kept without new chunks
+// before it is dropped, so an agent that never completes doesn't leak.
+const chunkBufferTTL = 5 * time.Minute
+
+// chunkBuffer reassembles the "chunk" reports of one prompt request.
+// Sequence numbers start at 0.
+type chunkBuffer struct {
+	agentID string
+	next    int // next sequence number expected
+	content strings.Builder
+	pending map[int]string // chunks that arrived ahead of next
+	updated time.Time
+}
+
+// SetChunkCallback sets the callback that receives streamed chunks of an
+// edge agent's answer, in order and without duplicates, as they arrive.
+// The reassembled answer is still delivered through the result callback
+// when the agent sends its "complete" report.
+func (m *MQTTChannel) SetChunkCallback(cb func(requestID string, seq int, content string)) {
+	m.resultMu.Lock()
+	defer m.resultMu.Unlock()
+	m.chunkCallback = cb
+}
+
+// handleEdgeAgentChunk buffers one "chunk" report. MQTT may redeliver or
+// reorder reports, so duplicates are dropped and early chunks are held
+// until the gap before them is filled.
+func (m *MQTTChannel) handleEdgeAgentChunk(report AgentReport) {
+	requestID, _ := report.Payload["request_id"].(string)
+	seqF, ok := report.Payload["seq"].(float64)
+	if requestID == "" || !ok || seqF < 0 {
+		m.logger.Warn("dropping malformed chunk report",
+			"agent", report.AgentID,
+			"request_id", requestID,
+		)
+		return
+	}
+	seq := int(seqF)
+	content, _ := report.Payload["content"].(string)
+
+	type chunk struct {
+		seq     int
+		content string
+	}
+	var ready []chunk
+
+	m.chunksMu.Lock()
+	now := time.Now()
+	for id, b := range m.chunks {
+		if now.Sub(b.updated) > chunkBufferTTL {
+			m.logger.Warn("dropping incomplete edge agent answer", "request_id", id, "agent", b.agentID)
+			delete(m.chunks, id)
+		}
+	}
+	b := m.chunks[requestID]
+	if b == nil {
+		b = &chunkBuffer{agentID: report.AgentID, pending: make(map[int]string)}
+		m.chunks[requestID] = b
+	}
+	b.updated = now
+	if _, dup := b.pending[seq]; seq >= b.next && !dup {
+		b.pending[seq] = content
+		for {
+			c, ok := b.pending[b.next]
+			if !ok {
+				break
+			}
+			delete(b.pending, b.next)
+			b.content.WriteString(c)
+			ready = append(ready, chunk{b.next, c})
+			b.next++
+		}
+	}
+	m.chunksMu.Unlock()
+
+	if len(ready) == 0 {
+		return
+	}
+	m.resultMu.RLock()
+	callback := m.chunkCallback
+	m.resultMu.RUnlock()
+	if callback == nil {
+		return
+	}
+	for _, c := range ready {
+		callback(requestID, c.seq, c.content)
+	}
+}
+
+// handleEdgeAgentComplete finishes a streamed answer: the buffered chunks
+// become the result's content, unless the "complete" report carries the
+// full content itself, and the result is delivered like a "result" report.
+func (m *MQTTChannel) handleEdgeAgentComplete(report AgentReport) {
+	requestID, _ := report.Payload["request_id"].(string)
+	content, missing := m.takeChunks(requestID)
+
+	payload := make(map[string]interface{}, len(report.Payload)+1)
+	for k, v := range report.Payload {
+		payload[k] = v
+	}
+	if c, _ := payload["content"].(string); c == "" {
+		payload["content"] = content
+	}
+	if _, ok := payload["status"]; !ok {
+		payload["status"] = "success"
+	}
+	if missing > 0 {
+		m.logger.Warn("edge agent answer completed with missing chunks",
+			"request_id", requestID,
+			"agent", report.AgentID,
+			"missing", missing,
+		)
+	}
+
+	report.Payload = payload
+	m.handleEdgeAgentResult(report)
+}
+
+// takeChunks removes the buffer for requestID and returns its content.
+// Chunks still waiting behind a gap are appended in sequence order;
+// missing is the number of chunks that never arrived.
+func (m *MQTTChannel) takeChunks(requestID string) (content string, missing int) {
+	m.chunksMu.Lock()
+	b := m.chunks[requestID]
+	delete(m.chunks, requestID)
+	m.chunksMu.Unlock()
+	if b == nil {
+		return "", 0
+	}
+
+	seqs := make([]int, 0, len(b.pending))
+	for seq := range b.pending {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+	for _, seq := range seqs {
+		missing += seq - b.next
+		b.content.WriteString(b.pending[seq])
+		b.next = seq + 1
+	}
+	return b.content.String(), missing
+}
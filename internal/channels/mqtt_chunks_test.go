@@ -0,0 +1,106 @@
+package channels
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// sendReport delivers an edge agent report with the given type and payload.
+func sendReport(t *testing.T, m *MQTTChannel, reportType string, payload map[string]interface{}) {
+	t.Helper()
+	data, err := json.Marshal(AgentReport{AgentID: "pi", ReportType: reportType, Payload: payload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.handleMessage(nil, &MockMQTTMessage{topic: "evoclaw/agents/pi/reports", payload: data})
+}
+
+func TestMQTTChunksReassembled(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+
+	var streamed []string
+	m.SetChunkCallback(func(requestID string, seq int, content string) {
+		if requestID != "prompt-1" || seq != len(streamed) {
+			t.Errorf("chunk %q seq %d out of order after %v", requestID, seq, streamed)
+		}
+		streamed = append(streamed, content)
+	})
+	var results []map[string]interface{}
+	m.SetResultCallback(func(requestID string, result map[string]interface{}) {
+		if requestID != "prompt-1" {
+			t.Errorf("result for %q, want prompt-1", requestID)
+		}
+		results = append(results, result)
+	})
+
+	for seq, content := range []string{"The answer ", "is ", "42."} {
+		sendReport(t, m, "chunk", map[string]interface{}{"request_id": "prompt-1", "seq": seq, "content": content})
+	}
+	if len(results) != 0 {
+		t.Fatal("expected no result before the complete report")
+	}
+	sendReport(t, m, "complete", map[string]interface{}{
+		"request_id": "prompt-1",
+		"metadata":   map[string]interface{}{"output_tokens": 5},
+	})
+
+	if len(streamed) != 3 {
+		t.Errorf("streamed %d chunks, want 3", len(streamed))
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if got := results[0]["content"]; got != "The answer is 42." {
+		t.Errorf("content = %q, want %q", got, "The answer is 42.")
+	}
+	if got := results[0]["status"]; got != "success" {
+		t.Errorf("status = %v, want success", got)
+	}
+	if _, ok := results[0]["metadata"]; !ok {
+		t.Error("expected the complete report's metadata to be kept")
+	}
+	if len(m.chunks) != 0 {
+		t.Errorf("expected the chunk buffer to be released, %d left", len(m.chunks))
+	}
+}
+
+func TestMQTTChunksOutOfOrderAndDuplicate(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+
+	var streamed []int
+	m.SetChunkCallback(func(_ string, seq int, _ string) { streamed = append(streamed, seq) })
+	var content interface{}
+	m.SetResultCallback(func(_ string, result map[string]interface{}) { content = result["content"] })
+
+	for _, c := range []struct {
+		seq  int
+		text string
+	}{{2, "c"}, {0, "a"}, {0, "a"}, {1, "b"}, {2, "c"}, {4, "e"}} {
+		sendReport(t, m, "chunk", map[string]interface{}{"request_id": "prompt-2", "seq": c.seq, "content": c.text})
+	}
+	if len(streamed) != 3 || streamed[0] != 0 || streamed[1] != 1 || streamed[2] != 2 {
+		t.Errorf("streamed seqs = %v, want [0 1 2]", streamed)
+	}
+
+	// Chunk 3 never arrives; what was received is still delivered
+	sendReport(t, m, "complete", map[string]interface{}{"request_id": "prompt-2"})
+	if content != "abce" {
+		t.Errorf("content = %q, want abce", content)
+	}
+}
+
+func TestMQTTChunksDroppedOnError(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+	var result map[string]interface{}
+	m.SetResultCallback(func(_ string, r map[string]interface{}) { result = r })
+
+	sendReport(t, m, "chunk", map[string]interface{}{"request_id": "prompt-3", "seq": 0, "content": "partial"})
+	sendReport(t, m, "error", map[string]interface{}{"request_id": "prompt-3", "status": "error", "error": "model crashed"})
+
+	if result["status"] != "error" || result["content"] != nil {
+		t.Errorf("unexpected result %v", result)
+	}
+	if len(m.chunks) != 0 {
+		t.Error("expected the chunk buffer to be released on error")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -134,6 +135,56 @@ func TestMQTTStart_Success(t *testing.T) {
 	}
 }
 
+func TestMQTTStart_LastWillAndOnlineStatus(t *testing.T) {
+	type publish struct {
+		topic    string
+		retained bool
+		payload  interface{}
+	}
+	var published []publish
+	mockClient := &MockMQTTClient{
+		PublishFunc: func(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+			published = append(published, publish{topic, retained, payload})
+			return &MockMQTTToken{}
+		},
+	}
+
+	var opts *mqtt.ClientOptions
+	mqttChan := NewMQTTWithClient("localhost", 1883, "", "", testLogger(),
+		func(o *mqtt.ClientOptions) MQTTClient {
+			opts = o
+			return mockClient
+		},
+	)
+	if err := mqttChan.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	if !opts.WillEnabled || opts.WillTopic != "evoclaw/orchestrator/status" {
+		t.Errorf("will = %v on %q, want enabled on evoclaw/orchestrator/status", opts.WillEnabled, opts.WillTopic)
+	}
+	if string(opts.WillPayload) != "offline" || !opts.WillRetained {
+		t.Errorf("will payload = %q retained = %v, want retained \"offline\"", opts.WillPayload, opts.WillRetained)
+	}
+
+	// The broker invokes OnConnect after each (re)connect.
+	opts.OnConnect(nil)
+	if len(published) != 1 {
+		t.Fatalf("published %d messages on connect, want 1", len(published))
+	}
+	if p := published[0]; p.topic != "evoclaw/orchestrator/status" || !p.retained || p.payload != "online" {
+		t.Errorf("on connect published %+v, want retained \"online\" status", p)
+	}
+
+	// A clean stop announces offline itself, since the will is not sent.
+	if err := mqttChan.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if len(published) != 2 || published[1].payload != "offline" || !published[1].retained {
+		t.Errorf("on stop published %+v, want retained \"offline\" status", published)
+	}
+}
+
 func TestMQTTStart_ConnectionFailed(t *testing.T) {
 	mockClient := &MockMQTTClient{
 		ConnectFunc: func() mqtt.Token {
@@ -593,6 +644,64 @@ func TestMQTTStop_WhenNotConnected(t *testing.T) {
 	}
 }
 
+func TestMQTTStop_ConcurrentDelivery(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		mqttChan := NewMQTTWithClient("localhost", 1883, "", "", testLogger(),
+			func(opts *mqtt.ClientOptions) MQTTClient { return &MockMQTTClient{} },
+		)
+		if err := mqttChan.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+
+		// Drain the inbox like the orchestrator does until Stop closes it.
+		drained := make(chan struct{})
+		go func() {
+			defer close(drained)
+			for range mqttChan.Receive() {
+			}
+		}()
+
+		payload, _ := json.Marshal(map[string]interface{}{"agent_id": "edge-1", "content": "reading", "sent_at": 1})
+		var wg sync.WaitGroup
+		panics := make(chan interface{}, 8)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						panics <- r
+					}
+				}()
+				// Paho keeps invoking callbacks from its own goroutines while
+				// Stop runs, and can still do so after it returns.
+				for j := 0; j < 200; j++ {
+					mqttChan.handleMessage(nil, &MockMQTTMessage{topic: "evoclaw/agents/edge-1/reports", payload: payload})
+					mqttChan.handleStatus(nil, &MockMQTTMessage{topic: "evoclaw/agents/edge-1/status", payload: []byte(`{"agent_id":"edge-1","status":"online"}`)})
+				}
+			}()
+		}
+
+		if err := mqttChan.Stop(); err != nil {
+			t.Fatalf("Stop failed: %v", err)
+		}
+		wg.Wait()
+		close(panics)
+		for r := range panics {
+			t.Fatalf("round %d: handler panicked during Stop: %v", round, r)
+		}
+
+		select {
+		case <-drained:
+		case <-time.After(time.Second):
+			t.Fatal("inbox was not closed by Stop")
+		}
+		if err := mqttChan.Stop(); err != nil {
+			t.Fatalf("second Stop failed: %v", err)
+		}
+	}
+}
+
 // These tests exercise the DefaultMQTTClient wrapper methods
 // They don't connect to a real MQTT broker but verify the interface works
 
@@ -0,0 +1,121 @@
+package channels
+
+import (
+	"time"
+)
+
+// defaultEdgeAgentTTL is how long an edge agent may go unseen before it is
+// forgotten entirely. It goes offline much earlier, after the presence
+// timeout; this only bounds how long its entry is kept.
+const defaultEdgeAgentTTL = 24 * time.Hour
+
+// errPendingExpired is the error sent on an expired pending request's
+// Response channel.
+const errPendingExpired = "request expired before the edge agent responded"
+
+// SetEdgeAgentTTL overrides how long an edge agent may go without a
+// heartbeat before its entry is evicted (mqtt.edgeAgentTtlHours). Zero
+// restores the default.
+func (m *MQTTChannel) SetEdgeAgentTTL(d time.Duration) {
+	m.presenceMu.Lock()
+	defer m.presenceMu.Unlock()
+	m.edgeAgentTTL = d
+}
+
+// evictionTTL returns the configured edge agent TTL or the default. It is
+// never shorter than the presence timeout, so agents go offline first.
+func (m *MQTTChannel) evictionTTL() time.Duration {
+	m.presenceMu.RLock()
+	ttl := m.edgeAgentTTL
+	m.presenceMu.RUnlock()
+	if ttl <= 0 {
+		ttl = defaultEdgeAgentTTL
+	}
+	if timeout := m.heartbeatTimeout(); ttl < timeout {
+		ttl = timeout
+	}
+	return ttl
+}
+
+// evictStaleAgents removes edge agents not seen within the TTL. An agent
+// still marked online is reported offline as it goes.
+func (m *MQTTChannel) evictStaleAgents(now time.Time) {
+	ttl := m.evictionTTL()
+
+	var events []PresenceEvent
+	m.edgeAgentsMu.Lock()
+	for id, info := range m.edgeAgents {
+		if now.Sub(info.LastSeen) < ttl {
+			continue
+		}
+		delete(m.edgeAgents, id)
+		if !info.offline {
+			events = append(events, PresenceEvent{AgentID: id, Capabilities: info.Capabilities, LastSeen: info.LastSeen})
+		}
+		m.logger.Info("edge agent evicted", "agent", id, "last_seen", info.LastSeen)
+	}
+	m.edgeAgentsMu.Unlock()
+
+	for _, ev := range events {
+		m.emitPresence(ev)
+	}
+}
+
+// expirePendingRequests removes pending requests past their deadline that
+// no SendPromptAndWait call owns, and fails each one's Response channel, so
+// a response that never comes doesn't leave the request behind. Owned
+// requests are timed out and removed by their waiter.
+func (m *MQTTChannel) expirePendingRequests(now time.Time) {
+	var expired []*PendingRequest
+	m.pendingRequestsMu.Lock()
+	for id, req := range m.pendingRequests {
+		if req.owned || req.Deadline.IsZero() || now.Before(req.Deadline) {
+			continue
+		}
+		delete(m.pendingRequests, id)
+		expired = append(expired, req)
+	}
+	m.pendingRequestsMu.Unlock()
+
+	for _, req := range expired {
+		m.logger.Warn("pending request expired",
+			"request_id", req.RequestID,
+			"agent", req.AgentID,
+			"age", now.Sub(req.CreatedAt),
+		)
+		select {
+		case req.Response <- &EdgeAgentResponse{
+			AgentID:   req.AgentID,
+			RequestID: req.RequestID,
+			Status:    "error",
+			Error:     errPendingExpired,
+		}:
+		default:
+			// The waiter already has a response it hasn't read
+		}
+	}
+}
+
+// runJanitor marks silent edge agents offline, evicts long-gone ones and
+// expires abandoned pending requests until the channel stops.
+func (m *MQTTChannel) runJanitor() {
+	defer m.wg.Done()
+
+	interval := m.heartbeatTimeout() / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.sweepPresence(now)
+			m.evictStaleAgents(now)
+			m.expirePendingRequests(now)
+		}
+	}
+}
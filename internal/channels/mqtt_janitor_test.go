@@ -0,0 +1,161 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJanitorEvictsStaleEdgeAgents(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+	m.SetPresenceTimeout(time.Minute)
+	m.SetEdgeAgentTTL(time.Hour)
+	rec := &presenceRecorder{}
+	m.SetPresenceCallback(rec.record)
+
+	heartbeat(t, m, "gone")
+	heartbeat(t, m, "quiet")
+	heartbeat(t, m, "fresh")
+	now := time.Now()
+	m.edgeAgentsMu.Lock()
+	m.edgeAgents["gone"].LastSeen = now.Add(-2 * time.Hour)
+	m.edgeAgents["quiet"].LastSeen = now.Add(-10 * time.Minute)
+	m.edgeAgentsMu.Unlock()
+
+	m.evictStaleAgents(now)
+
+	if m.GetEdgeAgentInfo("gone") != nil {
+		t.Error("agent unseen past the TTL should be evicted")
+	}
+	if m.GetEdgeAgentInfo("quiet") == nil || m.GetEdgeAgentInfo("fresh") == nil {
+		t.Error("agents seen within the TTL should be kept")
+	}
+
+	// The evicted agent was never swept offline, so eviction reports it
+	events := rec.get()
+	last := events[len(events)-1]
+	if last.AgentID != "gone" || last.Online {
+		t.Errorf("last event = %+v, want gone offline", last)
+	}
+
+	// Agents already reported offline are evicted without another event
+	later := now.Add(2 * time.Hour)
+	m.sweepPresence(later)
+	before := len(rec.get())
+	m.evictStaleAgents(later)
+	if m.GetEdgeAgentInfo("quiet") != nil || m.GetEdgeAgentInfo("fresh") != nil {
+		t.Error("agents should be evicted once past the TTL")
+	}
+	if got := len(rec.get()); got != before {
+		t.Errorf("evicting offline agents emitted %d more events, want none", got-before)
+	}
+}
+
+func TestEvictionTTLNotShorterThanPresenceTimeout(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+	m.SetPresenceTimeout(time.Hour)
+	m.SetEdgeAgentTTL(time.Minute)
+	if got := m.evictionTTL(); got != time.Hour {
+		t.Errorf("evictionTTL = %v, want the presence timeout", got)
+	}
+	m.SetEdgeAgentTTL(0)
+	if got := m.evictionTTL(); got != defaultEdgeAgentTTL {
+		t.Errorf("evictionTTL = %v, want default %v", got, defaultEdgeAgentTTL)
+	}
+}
+
+func TestJanitorExpiresPendingRequests(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+	now := time.Now()
+
+	stale := make(chan *EdgeAgentResponse, 1)
+	live := make(chan *EdgeAgentResponse, 1)
+	m.pendingRequests["stale"] = &PendingRequest{RequestID: "stale", AgentID: "edge-1", Response: stale, CreatedAt: now.Add(-2 * time.Minute), Deadline: now.Add(-time.Minute)}
+	m.pendingRequests["live"] = &PendingRequest{RequestID: "live", AgentID: "edge-1", Response: live, CreatedAt: now, Deadline: now.Add(time.Minute)}
+	m.pendingRequests["forever"] = &PendingRequest{RequestID: "forever", Response: make(chan *EdgeAgentResponse, 1), CreatedAt: now.Add(-time.Hour)}
+	m.pendingRequests["owned"] = &PendingRequest{RequestID: "owned", Response: make(chan *EdgeAgentResponse, 1), CreatedAt: now.Add(-2 * time.Minute), Deadline: now.Add(-time.Minute), owned: true}
+
+	m.expirePendingRequests(now)
+
+	select {
+	case resp := <-stale:
+		if resp.Status != "error" || resp.RequestID != "stale" || resp.Error != errPendingExpired {
+			t.Errorf("expired response = %+v", resp)
+		}
+	default:
+		t.Fatal("waiter of the expired request was not signalled")
+	}
+	select {
+	case resp := <-live:
+		t.Errorf("live request was signalled: %+v", resp)
+	default:
+	}
+
+	if _, ok := m.pendingRequests["stale"]; ok {
+		t.Error("expired request not removed")
+	}
+	if _, ok := m.pendingRequests["live"]; !ok {
+		t.Error("request within its deadline removed")
+	}
+	if _, ok := m.pendingRequests["forever"]; !ok {
+		t.Error("request without a deadline removed")
+	}
+	if _, ok := m.pendingRequests["owned"]; !ok {
+		t.Error("request owned by a waiter removed; its waiter times it out")
+	}
+
+	// A late response for the expired request finds nothing to deliver to
+	if m.handleEdgeAgentResponse(map[string]interface{}{"request_id": "stale", "content": "late"}) {
+		t.Error("late response delivered to an expired request")
+	}
+}
+
+func TestSendPromptAndWaitNotExpiredByJanitor(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+	m.client = &MockMQTTClient{IsConnectedVal: true}
+	m.edgeAgents["edge-1"] = &EdgeAgentInfo{AgentID: "edge-1", LastSeen: time.Now()}
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := m.SendPromptAndWait(context.Background(), "edge-1", "hello", "", 200*time.Millisecond)
+		errc <- err
+	}()
+
+	// Once the request is registered, run the janitor as if its deadline
+	// passed: the waiter owns it, so it is left for the waiter to time out
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		m.pendingRequestsMu.RLock()
+		n := len(m.pendingRequests)
+		m.pendingRequestsMu.RUnlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("request never registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	m.expirePendingRequests(time.Now().Add(2 * time.Hour))
+	m.pendingRequestsMu.RLock()
+	n := len(m.pendingRequests)
+	m.pendingRequestsMu.RUnlock()
+	if n != 1 {
+		t.Fatal("janitor removed a request its waiter still owns")
+	}
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, ErrEdgeTimeout) {
+			t.Errorf("err = %v, want ErrEdgeTimeout", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiter did not time out")
+	}
+	m.pendingRequestsMu.RLock()
+	defer m.pendingRequestsMu.RUnlock()
+	if len(m.pendingRequests) != 0 {
+		t.Error("waiter did not remove its request")
+	}
+}
@@ -0,0 +1,100 @@
+package channels
+
+import (
+	"time"
+)
+
+// defaultPresenceTimeout is how long an edge agent may stay silent before it
+// is considered offline.
+const defaultPresenceTimeout = 2 * time.Minute
+
+// PresenceEvent reports an edge agent coming online or going offline.
+type PresenceEvent struct {
+	AgentID      string
+	Online       bool
+	Capabilities string
+	LastSeen     time.Time
+}
+
+// SetPresenceCallback registers cb to be called whenever an edge agent is
+// first seen, comes back after going offline, or misses its heartbeat window.
+// The callback runs without the channel's locks held.
+func (m *MQTTChannel) SetPresenceCallback(cb func(PresenceEvent)) {
+	m.presenceMu.Lock()
+	defer m.presenceMu.Unlock()
+	m.presenceCallback = cb
+}
+
+// SetPresenceTimeout overrides how long an edge agent may go without a
+// heartbeat before it is reported offline. Zero restores the default.
+func (m *MQTTChannel) SetPresenceTimeout(d time.Duration) {
+	m.presenceMu.Lock()
+	defer m.presenceMu.Unlock()
+	m.presenceTimeout = d
+}
+
+// heartbeatTimeout returns the configured presence timeout or the default.
+func (m *MQTTChannel) heartbeatTimeout() time.Duration {
+	m.presenceMu.RLock()
+	defer m.presenceMu.RUnlock()
+	if m.presenceTimeout > 0 {
+		return m.presenceTimeout
+	}
+	return defaultPresenceTimeout
+}
+
+// touchEdgeAgent records activity from agentID, applies update to its entry
+// and emits an online event if the agent is new or was offline.
+func (m *MQTTChannel) touchEdgeAgent(agentID string, update func(*EdgeAgentInfo)) {
+	m.edgeAgentsMu.Lock()
+	info, ok := m.edgeAgents[agentID]
+	if !ok {
+		info = &EdgeAgentInfo{AgentID: agentID, Status: "online"}
+		m.edgeAgents[agentID] = info
+	}
+	cameOnline := !ok || info.offline
+	info.LastSeen = time.Now()
+	info.offline = false
+	if update != nil {
+		update(info)
+	}
+	ev := PresenceEvent{AgentID: agentID, Online: true, Capabilities: info.Capabilities, LastSeen: info.LastSeen}
+	m.edgeAgentsMu.Unlock()
+
+	if cameOnline {
+		m.logger.Info("edge agent online", "agent", agentID)
+		m.emitPresence(ev)
+	}
+}
+
+// sweepPresence marks agents whose last heartbeat is older than the presence
+// timeout as offline and emits an offline event for each.
+func (m *MQTTChannel) sweepPresence(now time.Time) {
+	timeout := m.heartbeatTimeout()
+
+	var events []PresenceEvent
+	m.edgeAgentsMu.Lock()
+	for id, info := range m.edgeAgents {
+		if info.offline || now.Sub(info.LastSeen) < timeout {
+			continue
+		}
+		info.offline = true
+		info.Status = "offline"
+		events = append(events, PresenceEvent{AgentID: id, Capabilities: info.Capabilities, LastSeen: info.LastSeen})
+	}
+	m.edgeAgentsMu.Unlock()
+
+	for _, ev := range events {
+		m.logger.Warn("edge agent offline", "agent", ev.AgentID, "last_seen", ev.LastSeen)
+		m.emitPresence(ev)
+	}
+}
+
+func (m *MQTTChannel) emitPresence(ev PresenceEvent) {
+	m.presenceMu.RLock()
+	cb := m.presenceCallback
+	m.presenceMu.RUnlock()
+	if cb != nil {
+		cb(ev)
+	}
+}
@@ -0,0 +1,95 @@
+package channels
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+// presenceRecorder collects presence events delivered to the callback.
+type presenceRecorder struct {
+	mu     sync.Mutex
+	events []PresenceEvent
+}
+
+func (r *presenceRecorder) record(ev PresenceEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+}
+
+func (r *presenceRecorder) get() []PresenceEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]PresenceEvent(nil), r.events...)
+}
+
+func heartbeat(t *testing.T, m *MQTTChannel, agentID string) {
+	t.Helper()
+	payload, _ := json.Marshal(AgentReport{AgentID: agentID, ReportType: "heartbeat"})
+	m.handleMessage(nil, &MockMQTTMessage{topic: "evoclaw/agents/" + agentID + "/reports", payload: payload})
+}
+
+func TestPresenceOfflineAfterMissedHeartbeat(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+	m.SetPresenceTimeout(time.Minute)
+	rec := &presenceRecorder{}
+	m.SetPresenceCallback(rec.record)
+
+	heartbeat(t, m, "edge-1")
+	events := rec.get()
+	if len(events) != 1 || !events[0].Online || events[0].AgentID != "edge-1" {
+		t.Fatalf("after first heartbeat: events = %+v, want one online event", events)
+	}
+
+	// A second heartbeat inside the window is not a new presence event.
+	heartbeat(t, m, "edge-1")
+	m.sweepPresence(time.Now().Add(30 * time.Second))
+	if n := len(rec.get()); n != 1 {
+		t.Fatalf("events = %d within heartbeat window, want 1", n)
+	}
+
+	// Simulate the agent going silent past the timeout.
+	m.sweepPresence(time.Now().Add(2 * time.Minute))
+	events = rec.get()
+	if len(events) != 2 || events[1].Online || events[1].AgentID != "edge-1" {
+		t.Fatalf("after missed window: events = %+v, want an offline event", events)
+	}
+	if m.IsEdgeAgentOnline("edge-1") {
+		t.Error("agent should not be reported online")
+	}
+
+	// Offline is reported once, not on every sweep.
+	m.sweepPresence(time.Now().Add(3 * time.Minute))
+	if n := len(rec.get()); n != 2 {
+		t.Errorf("events = %d after repeated sweep, want 2", n)
+	}
+
+	// The agent reappearing emits a fresh online event.
+	heartbeat(t, m, "edge-1")
+	events = rec.get()
+	if len(events) != 3 || !events[2].Online {
+		t.Errorf("after reconnect: events = %+v, want a third online event", events)
+	}
+}
+
+func TestPresenceStatusKeepsCapabilities(t *testing.T) {
+	m := NewMQTT("localhost", 1883, "", "", testLogger())
+	rec := &presenceRecorder{}
+	m.SetPresenceCallback(rec.record)
+
+	caps, _ := json.Marshal(map[string]string{"agent_id": "pi", "capabilities": "gpio, camera"})
+	m.handleCapabilities(nil, &MockMQTTMessage{topic: "evoclaw/agents/pi/capabilities", payload: caps})
+	status, _ := json.Marshal(map[string]interface{}{"agent_id": "pi", "status": "busy"})
+	m.handleStatus(nil, &MockMQTTMessage{topic: "evoclaw/agents/pi/status", payload: status})
+
+	events := rec.get()
+	if len(events) != 1 || events[0].Capabilities != "gpio, camera" {
+		t.Fatalf("events = %+v, want one online event with capabilities", events)
+	}
+	info := m.GetEdgeAgentInfo("pi")
+	if info.Status != "busy" || info.Capabilities != "gpio, camera" {
+		t.Errorf("info = %+v, want status busy with capabilities kept", info)
+	}
+}
@@ -0,0 +1,134 @@
+package channels
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// writeTestCert writes a self-signed certificate and its key as PEM files
+// in dir and returns their paths.
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "evoclaw-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+// startCapturingOptions starts ch against a mock client and returns the
+// options it built for Paho.
+func startCapturingOptions(t *testing.T, ch *MQTTChannel) *mqtt.ClientOptions {
+	t.Helper()
+	var opts *mqtt.ClientOptions
+	ch.clientFactory = func(o *mqtt.ClientOptions) MQTTClient {
+		opts = o
+		return &MockMQTTClient{}
+	}
+	if err := ch.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = ch.Stop() })
+	return opts
+}
+
+func TestMQTTStart_TLSOptions(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+	tlsCfg, err := LoadMQTTTLSConfig(certFile, certFile, keyFile, true)
+	if err != nil {
+		t.Fatalf("LoadMQTTTLSConfig: %v", err)
+	}
+	if tlsCfg.RootCAs == nil {
+		t.Error("expected CA certificate in RootCAs")
+	}
+	if len(tlsCfg.Certificates) != 1 {
+		t.Errorf("expected 1 client certificate, got %d", len(tlsCfg.Certificates))
+	}
+
+	ch := NewMQTTWithTLS("broker.example.com", 8883, "user", "pass", tlsCfg, testLogger())
+	opts := startCapturingOptions(t, ch)
+
+	if opts.TLSConfig != tlsCfg {
+		t.Error("expected the TLS config to be set on the client options")
+	}
+	if !opts.TLSConfig.InsecureSkipVerify {
+		t.Error("expected insecureSkipVerify to be carried through")
+	}
+	if len(opts.Servers) != 1 || opts.Servers[0].Scheme != "tls" || opts.Servers[0].Host != "broker.example.com:8883" {
+		t.Errorf("servers = %v, want tls://broker.example.com:8883", opts.Servers)
+	}
+}
+
+func TestMQTTStart_PlainTCPWithoutTLS(t *testing.T) {
+	ch := NewMQTT("localhost", 1883, "", "", testLogger())
+	opts := startCapturingOptions(t, ch)
+
+	if len(opts.Servers) != 1 || opts.Servers[0].Scheme != "tcp" {
+		t.Errorf("servers = %v, want tcp://localhost:1883", opts.Servers)
+	}
+}
+
+func TestLoadMQTTTLSConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCert(t, dir)
+
+	// System roots, no client certificate
+	tlsCfg, err := LoadMQTTTLSConfig("", "", "", false)
+	if err != nil {
+		t.Fatalf("LoadMQTTTLSConfig with no files: %v", err)
+	}
+	if tlsCfg.RootCAs != nil || len(tlsCfg.Certificates) != 0 || tlsCfg.InsecureSkipVerify {
+		t.Errorf("expected defaults, got %+v", tlsCfg)
+	}
+
+	if _, err := LoadMQTTTLSConfig(filepath.Join(dir, "missing.pem"), "", "", false); err == nil {
+		t.Error("expected error for missing CA file")
+	}
+	if _, err := LoadMQTTTLSConfig(keyFile, "", "", false); err == nil {
+		t.Error("expected error for a CA file with no certificate")
+	}
+	if _, err := LoadMQTTTLSConfig("", certFile, filepath.Join(dir, "missing.pem"), false); err == nil {
+		t.Error("expected error for missing client key")
+	}
+	if _, err := LoadMQTTTLSConfig("", keyFile, certFile, false); err == nil {
+		t.Error("expected error for a mismatched client cert/key")
+	}
+}
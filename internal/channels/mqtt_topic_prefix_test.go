@@ -0,0 +1,202 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/types"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// fakeBroker routes publishes to matching subscriptions, like a broker
+// shared by several clients.
+type fakeBroker struct {
+	mu        sync.Mutex
+	subs      []fakeSubscription
+	published []string
+}
+
+type fakeSubscription struct {
+	filter   string
+	callback mqtt.MessageHandler
+}
+
+func (b *fakeBroker) publish(topic string, payload []byte) {
+	b.mu.Lock()
+	b.published = append(b.published, topic)
+	var matched []mqtt.MessageHandler
+	for _, s := range b.subs {
+		if topicMatches(s.filter, topic) {
+			matched = append(matched, s.callback)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, cb := range matched {
+		cb(nil, &MockMQTTMessage{topic: topic, payload: payload})
+	}
+}
+
+func (b *fakeBroker) publishedTopics() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.published...)
+}
+
+// topicMatches reports whether topic matches an MQTT filter with + and #.
+func topicMatches(filter, topic string) bool {
+	f, t := strings.Split(filter, "/"), strings.Split(topic, "/")
+	for i, part := range f {
+		if part == "#" {
+			return true
+		}
+		if i >= len(t) || (part != "+" && part != t[i]) {
+			return false
+		}
+	}
+	return len(f) == len(t)
+}
+
+// fakeBrokerClient is one client connection to a fakeBroker.
+type fakeBrokerClient struct {
+	broker    *fakeBroker
+	opts      *mqtt.ClientOptions
+	connected bool
+}
+
+func (c *fakeBrokerClient) Connect() mqtt.Token {
+	c.connected = true
+	if c.opts.OnConnect != nil {
+		c.opts.OnConnect(nil)
+	}
+	return &MockMQTTToken{}
+}
+
+func (c *fakeBrokerClient) Disconnect(uint) { c.connected = false }
+
+func (c *fakeBrokerClient) Publish(topic string, _ byte, _ bool, payload interface{}) mqtt.Token {
+	var data []byte
+	switch p := payload.(type) {
+	case []byte:
+		data = p
+	case string:
+		data = []byte(p)
+	}
+	c.broker.publish(topic, data)
+	return &MockMQTTToken{}
+}
+
+func (c *fakeBrokerClient) Subscribe(filter string, _ byte, callback mqtt.MessageHandler) mqtt.Token {
+	c.broker.mu.Lock()
+	defer c.broker.mu.Unlock()
+	c.broker.subs = append(c.broker.subs, fakeSubscription{filter: filter, callback: callback})
+	return &MockMQTTToken{}
+}
+
+func (c *fakeBrokerClient) IsConnected() bool { return c.connected }
+
+// startPrefixedChannel starts an MQTT channel on broker under prefix.
+func startPrefixedChannel(t *testing.T, broker *fakeBroker, prefix string) *MQTTChannel {
+	t.Helper()
+	ch := NewMQTTWithClient("localhost", 1883, "", "", testLogger(), func(opts *mqtt.ClientOptions) MQTTClient {
+		return &fakeBrokerClient{broker: broker, opts: opts}
+	})
+	ch.SetTopicPrefix(prefix)
+	if err := ch.Start(context.Background()); err != nil {
+		t.Fatalf("Start(%s): %v", prefix, err)
+	}
+	t.Cleanup(func() { _ = ch.Stop() })
+	return ch
+}
+
+func TestMQTTTopicPrefix_CommandsAndReports(t *testing.T) {
+	broker := &fakeBroker{}
+	ch := startPrefixedChannel(t, broker, "tenant-a/evoclaw")
+
+	err := ch.Send(context.Background(), types.Response{To: "pi", Content: "hello"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	topics := broker.publishedTopics()
+	for _, want := range []string{"tenant-a/evoclaw/orchestrator/status", "tenant-a/evoclaw/agents/pi/commands"} {
+		found := false
+		for _, topic := range topics {
+			found = found || topic == want
+		}
+		if !found {
+			t.Errorf("expected publish to %s, got %v", want, topics)
+		}
+	}
+
+	report, _ := json.Marshal(map[string]interface{}{"agent_id": "pi", "content": "done"})
+	broker.publish("tenant-a/evoclaw/agents/pi/reports", report)
+
+	select {
+	case msg := <-ch.Receive():
+		if msg.From != "pi" || msg.Content != "done" {
+			t.Errorf("unexpected message %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected report on prefixed topic to be delivered")
+	}
+
+	// A heartbeat without agent_id is attributed by its topic
+	broker.publish("tenant-a/evoclaw/agents/pi-2/reports", []byte(`{"report_type":"heartbeat"}`))
+	if ch.GetEdgeAgentInfo("pi-2") == nil {
+		t.Error("expected agent ID to be parsed from the prefixed topic")
+	}
+}
+
+func TestMQTTTopicPrefix_NoCrossDelivery(t *testing.T) {
+	broker := &fakeBroker{}
+	a := startPrefixedChannel(t, broker, "tenant-a")
+	b := startPrefixedChannel(t, broker, "tenant-b")
+
+	report, _ := json.Marshal(map[string]interface{}{"agent_id": "pi", "content": "for a"})
+	broker.publish("tenant-a/agents/pi/reports", report)
+
+	select {
+	case msg := <-a.Receive():
+		if msg.Content != "for a" {
+			t.Errorf("tenant-a got %q", msg.Content)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected tenant-a to receive its report")
+	}
+	select {
+	case msg := <-b.Receive():
+		t.Fatalf("tenant-b received tenant-a's report: %+v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The default prefix is a namespace of its own too
+	broker.publish("evoclaw/agents/pi/reports", report)
+	select {
+	case msg := <-a.Receive():
+		t.Fatalf("tenant-a received a report under the default prefix: %+v", msg)
+	case msg := <-b.Receive():
+		t.Fatalf("tenant-b received a report under the default prefix: %+v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMQTTTopicPrefix_Default(t *testing.T) {
+	ch := NewMQTT("localhost", 1883, "", "", testLogger())
+	if got := ch.topic(commandsTopic, "pi"); got != "evoclaw/agents/pi/commands" {
+		t.Errorf("default commands topic = %q", got)
+	}
+	ch.SetTopicPrefix("/tenant/")
+	if got := ch.topic(broadcastTopic); got != "tenant/broadcast" {
+		t.Errorf("broadcast topic = %q, want tenant/broadcast", got)
+	}
+	if got := ch.topicAgentID("tenant/agents/pi/status"); got != "pi" {
+		t.Errorf("topicAgentID = %q, want pi", got)
+	}
+	if got := ch.topicAgentID("evoclaw/agents/pi/status"); got != "" {
+		t.Errorf("topicAgentID for another prefix = %q, want empty", got)
+	}
+}
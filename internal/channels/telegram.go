@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/clawinfra/evoclaw/internal/config"
 	"github.com/clawinfra/evoclaw/internal/types"
 )
 
@@ -32,6 +33,18 @@ type TelegramChannel struct {
 	wg       sync.WaitGroup
 	client   HTTPClient
 	offset   int64 // for long polling
+
+	// Sent replies by "chatID:messageID" → agent, for reaction feedback.
+	sentMu           sync.Mutex
+	sentAgents       map[string]string
+	sentOrder        []string
+	feedbackCallback func(Feedback)
+
+	// Replies being streamed by "chatID:replyTo", edited at most once per
+	// chunkInterval.
+	streamMu      sync.Mutex
+	streams       map[string]*telegramStream
+	chunkInterval time.Duration
 }
 
 // NewTelegram creates a new Telegram channel adapter
@@ -50,6 +63,8 @@ func NewTelegramWithClient(botToken string, logger *slog.Logger, client HTTPClie
 		logger:   logger.With("channel", "telegram"),
 		inbox:    make(chan types.Message, 100),
 		client:   client,
+
+		chunkInterval: telegramChunkInterval,
 	}
 }
 
@@ -91,6 +106,7 @@ func (t *TelegramChannel) Stop() error {
 //   - Inline keyboard buttons
 //   - Edit existing messages (EditMessageID > 0)
 //   - Reply-to (ReplyToID > 0)
+//   - Completing a reply streamed with SendChunk
 func (t *TelegramChannel) Send(ctx context.Context, msg types.Response) error {
 	content := msg.Content
 
@@ -127,13 +143,24 @@ func (t *TelegramChannel) Send(ctx context.Context, msg types.Response) error {
 	}
 
 	// --- Plain text (with optional inline keyboard) ---
-	return t.sendMessage(ctx, msg.To, content, msg.ReplyTo, msg.ReplyToID, msg.Buttons)
+	if len(msg.Buttons) == 0 {
+		if done, err := t.finishStream(ctx, msg); done {
+			return err
+		}
+	}
+	messageID, err := t.sendMessage(ctx, msg.To, content, msg.ReplyTo, msg.ReplyToID, msg.Buttons)
+	if err != nil {
+		return err
+	}
+	t.rememberSent(msg.To, messageID, msg.AgentID)
+	return nil
 }
 
 // sendMessage sends a plain-text message with optional inline keyboard.
 // replyTo is the legacy string reply (from types.Response.ReplyTo);
 // replyToID is the int64 version (from types.Response.ReplyToID).
-func (t *TelegramChannel) sendMessage(ctx context.Context, chatID, text, replyTo string, replyToID int64, buttons [][]types.Button) error {
+// It returns the ID of the sent message, or 0 if the API did not report one.
+func (t *TelegramChannel) sendMessage(ctx context.Context, chatID, text, replyTo string, replyToID int64, buttons [][]types.Button) (int64, error) {
 	// Build JSON body for richer payloads (inline keyboard / parse_mode)
 	body := map[string]interface{}{
 		"chat_id": chatID,
@@ -153,7 +180,7 @@ func (t *TelegramChannel) sendMessage(ctx context.Context, chatID, text, replyTo
 		body["reply_markup"] = keyboard
 	}
 
-	return t.postJSON(ctx, "sendMessage", body)
+	return t.postJSONMessage(ctx, "sendMessage", body)
 }
 
 // sendPhoto sends a photo by URL
@@ -204,31 +231,45 @@ func (t *TelegramChannel) editMessage(ctx context.Context, chatID string, messag
 
 // postJSON posts a JSON body to a Telegram Bot API method
 func (t *TelegramChannel) postJSON(ctx context.Context, method string, body map[string]interface{}) error {
+	_, err := t.postJSONMessage(ctx, method, body)
+	return err
+}
+
+// postJSONMessage is postJSON for methods that return a Message, also
+// returning its message_id (0 if absent).
+func (t *TelegramChannel) postJSONMessage(ctx context.Context, method string, body map[string]interface{}) (int64, error) {
 	data, err := json.Marshal(body)
 	if err != nil {
-		return fmt.Errorf("marshal request: %w", err)
+		return 0, fmt.Errorf("marshal request: %w", err)
 	}
 
 	apiURL := fmt.Sprintf("%s%s/%s", telegramAPIURL, t.botToken, method)
 	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(data))
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return 0, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("%s: %w", method, err)
+		return 0, config.RedactError(fmt.Errorf("%s: %w", method, err), t.botToken)
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
 	if resp.StatusCode != http.StatusOK {
 		b, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("telegram api error (%s): %s (status %d)", method, b, resp.StatusCode)
+		return 0, fmt.Errorf("telegram api error (%s): %s (status %d)", method, b, resp.StatusCode)
 	}
 
+	var result struct {
+		Result struct {
+			MessageID int64 `json:"message_id"`
+		} `json:"result"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+
 	t.logger.Debug("api call succeeded", "method", method, "to", body["chat_id"])
-	return nil
+	return result.Result.MessageID, nil
 }
 
 // buildInlineKeyboard converts [][]types.Button into Telegram inline_keyboard JSON structure
@@ -268,7 +309,7 @@ func (t *TelegramChannel) verifyToken() error {
 
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("get bot info: %w", err)
+		return config.RedactError(fmt.Errorf("get bot info: %w", err), t.botToken)
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
@@ -327,8 +368,8 @@ func (t *TelegramChannel) pollOnce() error {
 	params := url.Values{}
 	params.Set("offset", strconv.FormatInt(t.offset, 10))
 	params.Set("timeout", strconv.Itoa(pollTimeout))
-	// Include callback_query and message updates
-	params.Set("allowed_updates", `["message","callback_query"]`)
+	// Include message, callback_query and reaction updates
+	params.Set("allowed_updates", `["message","callback_query","message_reaction"]`)
 
 	apiURL := fmt.Sprintf("%s%s/getUpdates?%s", telegramAPIURL, t.botToken, params.Encode())
 
@@ -339,7 +380,7 @@ func (t *TelegramChannel) pollOnce() error {
 
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("get updates: %w", err)
+		return config.RedactError(fmt.Errorf("get updates: %w", err), t.botToken)
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
@@ -368,6 +409,11 @@ func (t *TelegramChannel) pollOnce() error {
 			t.offset = int64(update.UpdateID) + 1
 		}
 
+		if update.MessageReaction != nil {
+			t.handleReaction(update.MessageReaction)
+			continue
+		}
+
 		// Only process messages that have text (skip stickers, photos without caption, etc.)
 		if update.Message == nil || update.Message.Text == "" {
 			continue
@@ -436,6 +482,8 @@ func (t *TelegramChannel) buildMessage(tm *TelegramMessage) types.Message {
 type TelegramUpdate struct {
 	UpdateID int              `json:"update_id"`
 	Message  *TelegramMessage `json:"message,omitempty"`
+
+	MessageReaction *TelegramMessageReaction `json:"message_reaction,omitempty"`
 }
 
 // TelegramMessage represents a message from Telegram
@@ -0,0 +1,104 @@
+package channels
+
+import (
+	"fmt"
+)
+
+// maxTrackedReplies bounds how many sent messages are remembered for
+// attributing reactions to the agent that wrote them.
+const maxTrackedReplies = 1000
+
+// Feedback is a user reaction to an agent's reply, reported by channels that
+// support reactions. Type and Score follow the evolution engine's behavioral
+// feedback ("approval" with +1 or -1 for thumbs up/down).
+type Feedback struct {
+	AgentID string
+	Type    string
+	Score   float64
+	Context string
+}
+
+// reactionFeedback maps reaction emoji to feedback scores.
+var reactionFeedback = map[string]float64{
+	"👍": 1.0,
+	"👎": -1.0,
+}
+
+// TelegramReaction is a reaction type from the Bot API.
+type TelegramReaction struct {
+	Type  string `json:"type"` // "emoji", "custom_emoji", "paid"
+	Emoji string `json:"emoji,omitempty"`
+}
+
+// TelegramMessageReaction is a change to a user's reactions on a message.
+type TelegramMessageReaction struct {
+	Chat        TelegramChat       `json:"chat"`
+	MessageID   int64              `json:"message_id"`
+	User        *TelegramUser      `json:"user,omitempty"`
+	Date        int                `json:"date"`
+	OldReaction []TelegramReaction `json:"old_reaction"`
+	NewReaction []TelegramReaction `json:"new_reaction"`
+}
+
+// SetFeedbackCallback registers cb to receive 👍/👎 reactions on agent
+// replies. The callback runs on the polling goroutine.
+func (t *TelegramChannel) SetFeedbackCallback(cb func(Feedback)) {
+	t.sentMu.Lock()
+	defer t.sentMu.Unlock()
+	t.feedbackCallback = cb
+}
+
+// rememberSent records which agent wrote a sent message, evicting the oldest
+// entry once maxTrackedReplies is reached.
+func (t *TelegramChannel) rememberSent(chatID string, messageID int64, agentID string) {
+	if messageID <= 0 || agentID == "" {
+		return
+	}
+	key := fmt.Sprintf("%s:%d", chatID, messageID)
+
+	t.sentMu.Lock()
+	defer t.sentMu.Unlock()
+	if t.sentAgents == nil {
+		t.sentAgents = make(map[string]string)
+	}
+	if _, ok := t.sentAgents[key]; !ok {
+		t.sentOrder = append(t.sentOrder, key)
+	}
+	t.sentAgents[key] = agentID
+	if len(t.sentOrder) > maxTrackedReplies {
+		delete(t.sentAgents, t.sentOrder[0])
+		t.sentOrder = t.sentOrder[1:]
+	}
+}
+
+// handleReaction turns a newly added 👍/👎 on an agent reply into feedback.
+// Reactions on unknown messages and removed reactions are ignored.
+func (t *TelegramChannel) handleReaction(r *TelegramMessageReaction) {
+	key := fmt.Sprintf("%d:%d", r.Chat.ID, r.MessageID)
+
+	t.sentMu.Lock()
+	agentID := t.sentAgents[key]
+	cb := t.feedbackCallback
+	t.sentMu.Unlock()
+	if agentID == "" || cb == nil {
+		return
+	}
+
+	old := make(map[string]bool, len(r.OldReaction))
+	for _, re := range r.OldReaction {
+		old[re.Emoji] = true
+	}
+	for _, re := range r.NewReaction {
+		score, ok := reactionFeedback[re.Emoji]
+		if !ok || re.Type != "emoji" || old[re.Emoji] {
+			continue
+		}
+		t.logger.Info("reaction feedback", "agent", agentID, "emoji", re.Emoji, "message_id", r.MessageID)
+		cb(Feedback{
+			AgentID: agentID,
+			Type:    "approval",
+			Score:   score,
+			Context: fmt.Sprintf("telegram reaction %s on message %d", re.Emoji, r.MessageID),
+		})
+	}
+}
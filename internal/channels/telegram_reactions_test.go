@@ -0,0 +1,72 @@
+package channels
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/types"
+)
+
+func TestTelegramReaction_FeedsBack(t *testing.T) {
+	updates := `{"ok":true,"result":[
+		{"update_id":1,"message_reaction":{"chat":{"id":222,"type":"private"},"message_id":77,"date":0,
+			"old_reaction":[],"new_reaction":[{"type":"emoji","emoji":"👍"}]}},
+		{"update_id":2,"message_reaction":{"chat":{"id":222,"type":"private"},"message_id":77,"date":0,
+			"old_reaction":[{"type":"emoji","emoji":"👍"}],"new_reaction":[{"type":"emoji","emoji":"👍"},{"type":"emoji","emoji":"👎"}]}},
+		{"update_id":3,"message_reaction":{"chat":{"id":222,"type":"private"},"message_id":99,"date":0,
+			"old_reaction":[],"new_reaction":[{"type":"emoji","emoji":"👎"}]}}
+	]}`
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			body := `{"ok":true,"result":{"message_id":77}}`
+			if strings.Contains(req.URL.Path, "/getUpdates") {
+				if !strings.Contains(req.URL.RawQuery, "message_reaction") {
+					t.Errorf("getUpdates should request message_reaction updates: %s", req.URL.RawQuery)
+				}
+				body = updates
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+		},
+	}
+
+	tg := NewTelegramWithClient("test-token", testLogger(), mockClient)
+	tg.ctx = context.Background()
+
+	var got []Feedback
+	tg.SetFeedbackCallback(func(fb Feedback) { got = append(got, fb) })
+
+	if err := tg.Send(context.Background(), types.Response{AgentID: "agent-1", To: "222", Content: "hello"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := tg.pollOnce(); err != nil {
+		t.Fatalf("pollOnce: %v", err)
+	}
+
+	// 👍 added, then 👎 added alongside the existing 👍; message 99 is not
+	// an agent reply and is ignored.
+	if len(got) != 2 {
+		t.Fatalf("got %d feedback events, want 2: %+v", len(got), got)
+	}
+	if got[0].AgentID != "agent-1" || got[0].Type != "approval" || got[0].Score != 1.0 {
+		t.Errorf("first feedback = %+v", got[0])
+	}
+	if got[1].Score != -1.0 {
+		t.Errorf("second feedback score = %v, want -1", got[1].Score)
+	}
+}
+
+func TestTelegramRememberSent_Bounded(t *testing.T) {
+	tg := NewTelegramWithClient("test-token", testLogger(), &MockHTTPClient{})
+	for i := int64(1); i <= maxTrackedReplies+10; i++ {
+		tg.rememberSent("1", i, "a")
+	}
+	if len(tg.sentAgents) != maxTrackedReplies || len(tg.sentOrder) != maxTrackedReplies {
+		t.Errorf("tracked %d/%d, want %d", len(tg.sentAgents), len(tg.sentOrder), maxTrackedReplies)
+	}
+	if _, ok := tg.sentAgents["1:1"]; ok {
+		t.Error("oldest entry should have been evicted")
+	}
+}
@@ -0,0 +1,113 @@
+package channels
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/types"
+)
+
+const (
+	// telegramChunkInterval is the least time between edits of a streamed
+	// reply. Telegram rate-limits edits, so chunks arriving faster are
+	// gathered into the next edit or the final answer.
+	telegramChunkInterval = time.Second
+	// maxStreamAge drops a streamed reply whose final answer never came.
+	maxStreamAge = 10 * time.Minute
+)
+
+// telegramStream is a reply being shown while it is produced: one message
+// edited as chunks arrive, then replaced by the final answer.
+type telegramStream struct {
+	mu        sync.Mutex
+	messageID int64
+	text      string // all chunks so far
+	shown     string // text of the last send or edit
+	lastEdit  time.Time
+	started   time.Time
+}
+
+// streamKey identifies the reply to an incoming message.
+func streamKey(msg types.Response) string {
+	return msg.To + ":" + msg.ReplyTo
+}
+
+// SendChunk shows the next part of an answer that is still being produced.
+// The first chunk is sent as a reply to msg.ReplyTo and later ones edit it,
+// at most once per telegramChunkInterval. Send then puts the complete
+// answer in the same message instead of sending a new one.
+func (t *TelegramChannel) SendChunk(ctx context.Context, msg types.Response, seq int) error {
+	key := streamKey(msg)
+
+	t.streamMu.Lock()
+	if t.streams == nil {
+		t.streams = make(map[string]*telegramStream)
+	}
+	s := t.streams[key]
+	if s == nil || seq == 0 {
+		t.pruneStreamsLocked()
+		s = &telegramStream{started: time.Now()}
+		t.streams[key] = s
+	}
+	t.streamMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.text += msg.Content
+
+	if s.messageID == 0 {
+		if s.text == "" {
+			return nil
+		}
+		messageID, err := t.sendMessage(ctx, msg.To, s.text, msg.ReplyTo, msg.ReplyToID, nil)
+		if err != nil {
+			return err
+		}
+		s.messageID, s.shown, s.lastEdit = messageID, s.text, time.Now()
+		return nil
+	}
+	if s.text == s.shown || time.Since(s.lastEdit) < t.chunkInterval {
+		return nil
+	}
+	if err := t.editMessage(ctx, msg.To, s.messageID, s.text); err != nil {
+		return err
+	}
+	s.shown, s.lastEdit = s.text, time.Now()
+	return nil
+}
+
+// finishStream puts the final answer into the streamed reply to the same
+// message, if there is one. It reports whether it did.
+func (t *TelegramChannel) finishStream(ctx context.Context, msg types.Response) (bool, error) {
+	t.streamMu.Lock()
+	s := t.streams[streamKey(msg)]
+	delete(t.streams, streamKey(msg))
+	t.streamMu.Unlock()
+	if s == nil {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.messageID == 0 {
+		return false, nil
+	}
+	if msg.Content != s.shown {
+		if err := t.editMessage(ctx, msg.To, s.messageID, msg.Content); err != nil {
+			return true, err
+		}
+	}
+	t.rememberSent(msg.To, s.messageID, msg.AgentID)
+	return true, nil
+}
+
+// pruneStreamsLocked forgets streams older than maxStreamAge. Caller must
+// hold t.streamMu.
+func (t *TelegramChannel) pruneStreamsLocked() {
+	for key, s := range t.streams {
+		if time.Since(s.started) > maxStreamAge {
+			delete(t.streams, key)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/types"
+)
+
+// telegramCall is one Bot API request seen by recordingTelegram.
+type telegramCall struct {
+	method string
+	body   map[string]interface{}
+}
+
+func recordingTelegram(t *testing.T) (*TelegramChannel, func() []telegramCall) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []telegramCall
+	client := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			mu.Lock()
+			calls = append(calls, telegramCall{method: req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:], body: body})
+			mu.Unlock()
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"ok":true,"result":{"message_id":41}}`))}, nil
+		},
+	}
+	tg := NewTelegramWithClient("test-token", testLogger(), client)
+	return tg, func() []telegramCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]telegramCall(nil), calls...)
+	}
+}
+
+func TestTelegramSendChunk_EditsOneReply(t *testing.T) {
+	tg, calls := recordingTelegram(t)
+	tg.chunkInterval = 0
+	ctx := context.Background()
+	chunk := types.Response{AgentID: "agent-1", To: "222", ReplyTo: "9"}
+
+	for seq, c := range []string{"one ", "two ", "three"} {
+		chunk.Content = c
+		if err := tg.SendChunk(ctx, chunk, seq); err != nil {
+			t.Fatalf("SendChunk %d: %v", seq, err)
+		}
+	}
+	final := chunk
+	final.Content = "one two three!"
+	if err := tg.Send(ctx, final); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	got := calls()
+	want := []struct{ method, text string }{
+		{"sendMessage", "one "},
+		{"editMessageText", "one two "},
+		{"editMessageText", "one two three"},
+		{"editMessageText", "one two three!"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("calls = %+v, want %d", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].method != w.method || got[i].body["text"] != w.text {
+			t.Errorf("call %d = %s %q, want %s %q", i, got[i].method, got[i].body["text"], w.method, w.text)
+		}
+	}
+	if got[0].body["reply_to_message_id"] != "9" {
+		t.Errorf("first chunk not sent as a reply: %+v", got[0].body)
+	}
+	if got[3].body["message_id"] != float64(41) {
+		t.Errorf("final answer edited message %v, want 41", got[3].body["message_id"])
+	}
+	if tg.sentAgents["222:41"] != "agent-1" {
+		t.Error("streamed reply not tracked for reaction feedback")
+	}
+	if len(tg.streams) != 0 {
+		t.Error("stream not forgotten after the final answer")
+	}
+}
+
+func TestTelegramSendChunk_ThrottlesEdits(t *testing.T) {
+	tg, calls := recordingTelegram(t)
+	tg.chunkInterval = time.Hour
+	ctx := context.Background()
+	chunk := types.Response{To: "222", ReplyTo: "9"}
+
+	for seq, c := range []string{"a", "b", "c"} {
+		chunk.Content = c
+		if err := tg.SendChunk(ctx, chunk, seq); err != nil {
+			t.Fatalf("SendChunk %d: %v", seq, err)
+		}
+	}
+	if n := len(calls()); n != 1 {
+		t.Fatalf("%d calls before the final answer, want only the first send", n)
+	}
+
+	final := chunk
+	final.Content = "abc"
+	if err := tg.Send(ctx, final); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	got := calls()
+	if len(got) != 2 || got[1].method != "editMessageText" || got[1].body["text"] != "abc" {
+		t.Errorf("calls = %+v, want the final answer as an edit", got)
+	}
+}
+
+func TestTelegramSend_WithoutStreamSendsNewMessage(t *testing.T) {
+	tg, calls := recordingTelegram(t)
+	if err := tg.Send(context.Background(), types.Response{To: "222", ReplyTo: "9", Content: "hi"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := calls(); len(got) != 1 || got[0].method != "sendMessage" {
+		t.Errorf("calls = %+v, want one sendMessage", got)
+	}
+}
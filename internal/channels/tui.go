@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -30,6 +32,9 @@ type TUIChannel struct {
 	cancel   context.CancelFunc
 	program  *tea.Program
 	agentsFn func() []types.AgentInfo // callback to get live agent state
+
+	// fitnessFn returns an agent's fitness history for the metrics pane.
+	fitnessFn func(agentID string) []float64
 }
 
 // NewTUI creates a new terminal UI channel.
@@ -43,6 +48,12 @@ func NewTUI(logger *slog.Logger, agentsFn func() []types.AgentInfo) *TUIChannel
 	}
 }
 
+// SetFitnessSource sets the callback the metrics pane uses to chart an
+// agent's fitness over time. Without it the chart is left empty.
+func (t *TUIChannel) SetFitnessSource(fn func(agentID string) []float64) {
+	t.fitnessFn = fn
+}
+
 func (t *TUIChannel) Name() string { return "tui" }
 
 func (t *TUIChannel) Start(ctx context.Context) error {
@@ -75,22 +86,31 @@ func (t *TUIChannel) Stop() error {
 }
 
 func (t *TUIChannel) Send(_ context.Context, msg types.Response) error {
-	// Push response text into the TUI's chat viewport
-	t.outbox <- msg.Content
+	// Push response text into the TUI's chat viewport. The outbox is only a
+	// bounded backlog; never block the orchestrator when it is full.
+	select {
+	case t.outbox <- msg.Content:
+	default:
+	}
 	if t.program != nil {
-		t.program.Send(agentResponseMsg{content: msg.Content, agentID: msg.AgentID})
+		t.program.Send(agentResponseMsg{content: msg.Content, agentID: msg.AgentID, usage: msg.Usage})
 	}
 	return nil
 }
 
+// AcceptsBroadcast reports that the TUI displays orchestrator broadcasts.
+func (t *TUIChannel) AcceptsBroadcast() bool { return true }
+
 func (t *TUIChannel) Receive() <-chan types.Message { return t.inbox }
 
-// sendUserMessage is called from the TUI model when the user presses Enter
-func (t *TUIChannel) sendUserMessage(text string) {
+// sendUserMessage is called from the TUI model when the user presses Enter.
+// to names the target agent; empty leaves routing to the orchestrator.
+func (t *TUIChannel) sendUserMessage(text, to string) {
 	t.inbox <- types.Message{
 		ID:        fmt.Sprintf("tui-%d", time.Now().UnixNano()),
 		Channel:   "tui",
 		From:      "user",
+		To:        to,
 		Content:   text,
 		Timestamp: time.Now(),
 	}
@@ -103,6 +123,7 @@ func (t *TUIChannel) sendUserMessage(text string) {
 type agentResponseMsg struct {
 	content string
 	agentID string
+	usage   *types.Usage
 }
 
 type tickMsg struct{}
@@ -160,6 +181,10 @@ var (
 	chatText = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#E5E7EB"))
 
+	usageStyle = lipgloss.NewStyle().
+			Foreground(mutedColor).
+			Faint(true)
+
 	// Header/footer
 	headerStyle = lipgloss.NewStyle().
 			Bold(true).
@@ -173,6 +198,24 @@ var (
 	statusOnline = lipgloss.NewStyle().
 			Foreground(successColor).
 			Bold(true)
+
+	// Metrics pane
+	metricsBorder = lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(warnColor)
+
+	sparkStyle = lipgloss.NewStyle().
+			Foreground(successColor)
+)
+
+// metricsPaneHeight is the number of rows the metrics pane takes from the
+// chat viewport, borders included.
+const metricsPaneHeight = 6
+
+// Commands handled by the TUI itself rather than sent to an agent.
+const (
+	metricsCommand = "/metrics" // toggle the metrics pane
+	switchCommand  = "/switch"  // "/switch" cycles agents, "/switch <id>" picks one
 )
 
 // ─────────────────────────────────────────────────────
@@ -187,6 +230,14 @@ type tuiModel struct {
 	width    int
 	height   int
 	ready    bool
+
+	// activeAgent is the agent messages are addressed to. Empty until the
+	// user switches, leaving routing to the orchestrator.
+	activeAgent string
+
+	// Metrics pane state, refreshed on every tick while visible.
+	showMetrics bool
+	fitness     []float64
 }
 
 type chatEntry struct {
@@ -194,6 +245,8 @@ type chatEntry struct {
 	content string
 	time    time.Time
 	isUser  bool
+	isNote  bool         // status line from the TUI itself
+	usage   *types.Usage // shown dimmed after an agent's answer
 }
 
 func newTUIModel(ch *TUIChannel) tuiModel {
@@ -234,11 +287,34 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
+		case "ctrl+n", "ctrl+p":
+			step := 1
+			if msg.String() == "ctrl+p" {
+				step = -1
+			}
+			m.switchAgent(nextAgent(m.agentIDs(), m.activeAgent, step))
+			return m, nil
 		case "enter":
 			text := strings.TrimSpace(m.input.Value())
 			if text == "" {
 				return m, nil
 			}
+			if text == metricsCommand {
+				m.showMetrics = !m.showMetrics
+				m.input.Reset()
+				m.refreshMetrics()
+				m.resize()
+				return m, nil
+			}
+			if text == switchCommand || strings.HasPrefix(text, switchCommand+" ") {
+				target := strings.TrimSpace(strings.TrimPrefix(text, switchCommand))
+				if target == "" {
+					target = nextAgent(m.agentIDs(), m.activeAgent, 1)
+				}
+				m.input.Reset()
+				m.switchAgent(target)
+				return m, nil
+			}
 
 			// Add to chat
 			m.messages = append(m.messages, chatEntry{
@@ -249,7 +325,7 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 
 			// Send to orchestrator via channel
-			m.channel.sendUserMessage(text)
+			m.channel.sendUserMessage(text, m.activeAgent)
 
 			// Clear input
 			m.input.Reset()
@@ -267,34 +343,21 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			content: msg.content,
 			time:    time.Now(),
 			isUser:  false,
+			usage:   msg.usage,
 		})
 		m.chat.SetContent(m.renderChat())
 		m.chat.GotoBottom()
 		return m, nil
 
 	case tickMsg:
-		// Refresh sidebar (agent status updates)
+		// Refresh sidebar (agent status updates) and the metrics pane
+		m.refreshMetrics()
 		cmds = append(cmds, tickCmd())
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-
-		sidebarW := 30
-		chatW := m.width - sidebarW - 3 // 3 for borders/gap
-		chatH := m.height - 8            // header + input + footer
-
-		if !m.ready {
-			m.chat = viewport.New(chatW, chatH)
-			m.chat.SetContent(m.renderChat())
-			m.ready = true
-		} else {
-			m.chat.Width = chatW
-			m.chat.Height = chatH
-			m.chat.SetContent(m.renderChat())
-		}
-
-		m.input.SetWidth(chatW - 2)
+		m.resize()
 	}
 
 	// Update sub-components
@@ -314,8 +377,12 @@ func (m tuiModel) View() string {
 	}
 
 	// Header
+	target := m.activeAgent
+	if target == "" {
+		target = "auto"
+	}
 	header := headerStyle.Width(m.width).Render(
-		"  🧬 EvoClaw Terminal  " + statusOnline.Render("● ONLINE"),
+		"  🧬 EvoClaw Terminal  " + statusOnline.Render("● ONLINE") + "  → " + target,
 	)
 
 	// Sidebar
@@ -327,18 +394,152 @@ func (m tuiModel) View() string {
 
 	// Compose right pane
 	rightPane := lipgloss.JoinVertical(lipgloss.Left, chatArea, inputArea)
+	if m.showMetrics {
+		metricsArea := metricsBorder.Width(m.width - 33).Render(m.renderMetrics())
+		rightPane = lipgloss.JoinVertical(lipgloss.Left, chatArea, metricsArea, inputArea)
+	}
 
 	// Compose main body
 	body := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, " ", rightPane)
 
 	// Footer
 	footer := footerStyle.Render(
-		"  Enter: send │ Ctrl+C: quit │ ↑↓: scroll chat",
+		"  Enter: send │ Ctrl+N/P, /switch: change agent │ /metrics: toggle metrics │ Ctrl+C: quit │ ↑↓: scroll chat",
 	)
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, body, footer)
 }
 
+// resize lays out the chat viewport and input for the current window size,
+// leaving room for the metrics pane when it is shown.
+func (m *tuiModel) resize() {
+	if m.width == 0 || m.height == 0 {
+		return
+	}
+
+	sidebarW := 30
+	chatW := m.width - sidebarW - 3 // 3 for borders/gap
+	chatH := m.height - 8            // header + input + footer
+	if m.showMetrics {
+		chatH -= metricsPaneHeight
+	}
+	if chatH < 1 {
+		chatH = 1
+	}
+
+	if !m.ready {
+		m.chat = viewport.New(chatW, chatH)
+		m.ready = true
+	} else {
+		m.chat.Width = chatW
+		m.chat.Height = chatH
+	}
+	m.chat.SetContent(m.renderChat())
+	m.input.SetWidth(chatW - 2)
+}
+
+// refreshMetrics picks the agent shown in the metrics pane and reloads its
+// fitness history. It does nothing while the pane is hidden.
+func (m *tuiModel) refreshMetrics() {
+	if !m.showMetrics {
+		return
+	}
+
+	m.fitness = nil
+	agent, ok := m.metricsAgent()
+	if !ok {
+		return
+	}
+	if m.channel.fitnessFn != nil {
+		m.fitness = m.channel.fitnessFn(agent.ID)
+	}
+}
+
+// metricsAgent returns the agent shown in the metrics pane: the active agent
+// if it is still registered, otherwise the first agent by ID.
+func (m tuiModel) metricsAgent() (types.AgentInfo, bool) {
+	if m.channel.agentsFn == nil {
+		return types.AgentInfo{}, false
+	}
+	agents := m.channel.agentsFn()
+	if len(agents) == 0 {
+		return types.AgentInfo{}, false
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].ID < agents[j].ID })
+	for _, a := range agents {
+		if a.ID == m.activeAgent {
+			return a, true
+		}
+	}
+	return agents[0], true
+}
+
+// agentIDs returns the registered agent IDs in sorted order.
+func (m tuiModel) agentIDs() []string {
+	if m.channel.agentsFn == nil {
+		return nil
+	}
+	agents := m.channel.agentsFn()
+	ids := make([]string, len(agents))
+	for i, a := range agents {
+		ids[i] = a.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// switchAgent makes id the target of subsequent messages and notes the
+// change in the chat log. Unknown IDs are reported and leave the target as is.
+func (m *tuiModel) switchAgent(id string) {
+	note := ""
+	switch {
+	case id == "":
+		note = "No agents registered"
+	case !containsString(m.agentIDs(), id):
+		note = fmt.Sprintf("Unknown agent %q", id)
+	case id == m.activeAgent:
+		note = "Already talking to " + id
+	default:
+		m.activeAgent = id
+		note = "Switched to " + id
+		m.refreshMetrics()
+	}
+
+	m.messages = append(m.messages, chatEntry{content: note, time: time.Now(), isNote: true})
+	if m.ready {
+		m.chat.SetContent(m.renderChat())
+		m.chat.GotoBottom()
+	}
+}
+
+// nextAgent returns the agent step places after current in ids, wrapping
+// around. When current is not in ids the first (step > 0) or last agent is
+// returned; an empty ids yields "".
+func nextAgent(ids []string, current string, step int) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	for i, id := range ids {
+		if id == current {
+			n := len(ids)
+			return ids[((i+step)%n+n)%n]
+		}
+	}
+	if step < 0 {
+		return ids[len(ids)-1]
+	}
+	return ids[0]
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 // ─────────────────────────────────────────────────────
 // Rendering helpers
 // ─────────────────────────────────────────────────────
@@ -372,6 +573,9 @@ func (m tuiModel) renderSidebar() string {
 		}
 
 		name := agentLabel.Render(a.ID)
+		if a.ID == m.activeAgent {
+			name = agentLabel.Bold(true).Render(a.ID + " ◂")
+		}
 		fmt.Fprintf(&sb, "  %s %s\n", indicator, name)
 
 		// Metrics
@@ -446,7 +650,9 @@ func (m tuiModel) renderChat() string {
 		ts := entry.time.Format("15:04")
 		timeStr := lipgloss.NewStyle().Foreground(mutedColor).Render(ts)
 
-		if entry.isUser {
+		if entry.isNote {
+			fmt.Fprintf(&sb, "%s %s\n", timeStr, lipgloss.NewStyle().Foreground(mutedColor).Italic(true).Render("— "+entry.content))
+		} else if entry.isUser {
 			sender := userMsg.Render("[You]")
 			fmt.Fprintf(&sb, "%s %s %s\n", timeStr, sender, chatText.Render(entry.content))
 		} else {
@@ -454,6 +660,10 @@ func (m tuiModel) renderChat() string {
 			// Word-wrap long responses
 			content := entry.content
 			fmt.Fprintf(&sb, "%s %s\n%s\n", timeStr, sender, chatText.Render(content))
+			if entry.usage != nil {
+				sb.WriteString(usageStyle.Render(entry.usage.String()))
+				sb.WriteString("\n")
+			}
 		}
 		sb.WriteString("\n")
 	}
@@ -461,6 +671,80 @@ func (m tuiModel) renderChat() string {
 	return sb.String()
 }
 
+func (m tuiModel) renderMetrics() string {
+	agent, ok := m.metricsAgent()
+	if !ok {
+		return agentOffline.Render("  No agents registered")
+	}
+
+	met := agent.Metrics
+	successRate := 0.0
+	if met.TotalActions > 0 {
+		successRate = float64(met.SuccessfulActions) / float64(met.TotalActions) * 100
+	}
+
+	var sb strings.Builder
+	sb.WriteString(sidebarTitle.UnsetMarginBottom().Render("  Metrics · " + agent.ID))
+	sb.WriteString("\n")
+	sb.WriteString(metricStyle.Render(fmt.Sprintf("msgs: %d   tokens: %d   cost: $%.3f",
+		agent.MessageCount, met.TokensUsed, met.CostUSD)))
+	sb.WriteString("\n")
+	sb.WriteString(metricStyle.Render(fmt.Sprintf("avg latency: %.0fms   success: %.1f%%",
+		met.AvgResponseMs, successRate)))
+	sb.WriteString("\n")
+
+	// Leave room for the label and latest value around the chart.
+	chartW := m.width - 33 - 30
+	if len(m.fitness) == 0 {
+		sb.WriteString(metricStyle.Render("fitness: no evaluations yet"))
+	} else {
+		latest := m.fitness[len(m.fitness)-1]
+		sb.WriteString(metricStyle.Render("fitness: "))
+		sb.WriteString(sparkStyle.Render(sparkline(m.fitness, chartW)))
+		sb.WriteString(metricStyle.Render(fmt.Sprintf(" %.3f", latest)))
+	}
+	return sb.String()
+}
+
+// sparkBlocks are the bar glyphs used by sparkline, lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as a row of block characters scaled between the
+// series minimum and maximum. Only the last width values are drawn. A flat
+// series (including a single point) is drawn at mid height; an empty series
+// or non-positive width yields "". NaN values are drawn as spaces.
+func sparkline(values []float64, width int) string {
+	if len(values) == 0 || width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	top := len(sparkBlocks) - 1
+	out := make([]rune, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v):
+			out[i] = ' '
+		case hi == lo:
+			out[i] = sparkBlocks[top/2]
+		default:
+			out[i] = sparkBlocks[int(math.Round((v-lo)/(hi-lo)*float64(top)))]
+		}
+	}
+	return string(out)
+}
+
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
 		return fmt.Sprintf("%ds", int(d.Seconds()))
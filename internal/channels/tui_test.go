@@ -3,9 +3,13 @@ package channels
 import (
 	"context"
 	"log/slog"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/clawinfra/evoclaw/internal/types"
 )
 
@@ -51,10 +55,29 @@ func TestTUISendWithoutProgram(t *testing.T) {
 	}
 }
 
+func TestTUISendDoesNotBlockWhenBacklogFull(t *testing.T) {
+	ch := NewTUI(slog.Default(), nil)
+	if !ch.AcceptsBroadcast() {
+		t.Error("TUI should accept broadcasts")
+	}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < cap(ch.outbox)+10; i++ {
+			_ = ch.Send(context.Background(), types.Response{Content: "nudge"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Send blocked with a full outbox")
+	}
+}
+
 func TestTUISendUserMessage(t *testing.T) {
 	logger := slog.Default()
 	ch := NewTUI(logger, nil)
-	ch.sendUserMessage("test message")
+	ch.sendUserMessage("test message", "")
 	select {
 	case msg := <-ch.inbox:
 		if msg.Content != "test message" {
@@ -205,6 +228,27 @@ func TestTUIRenderChat(t *testing.T) {
 	}
 }
 
+func TestTUIRenderChatUsage(t *testing.T) {
+	m := newTUIModel(NewTUI(slog.Default(), nil))
+	m.messages = []chatEntry{
+		{sender: "agent-1", content: "Hi there!", time: time.Now(),
+			usage: &types.Usage{TokensInput: 120, TokensOutput: 48, CostUSD: 0.0012, LatencyMs: 830}},
+		{sender: "agent-2", content: "Hello", time: time.Now()},
+	}
+	chat := m.renderChat()
+	if !strings.Contains(chat, "120 in · 48 out · $0.0012 · 830ms") {
+		t.Errorf("expected usage line after the answer, got:\n%s", chat)
+	}
+	if strings.Count(chat, " in · ") != 1 {
+		t.Error("expected no usage line for an answer without usage")
+	}
+
+	free := types.Usage{TokensInput: 10, TokensOutput: 5, LatencyMs: 1500}
+	if got := free.String(); got != "10 in · 5 out · 1.5s" {
+		t.Errorf("free model usage = %q", got)
+	}
+}
+
 func TestTUIRenderSidebarLargeTokens(t *testing.T) {
 	logger := slog.Default()
 	agents := []types.AgentInfo{
@@ -233,3 +277,170 @@ func TestTickCmd(t *testing.T) {
 		t.Error("tickCmd() should return a non-nil Cmd")
 	}
 }
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{"empty", nil, 10, ""},
+		{"zero width", []float64{1, 2}, 0, ""},
+		{"single point", []float64{0.42}, 10, "▄"},
+		{"flat", []float64{0.5, 0.5, 0.5}, 10, "▄▄▄"},
+		{"ascending", []float64{0, 1, 2, 3, 4, 5, 6, 7}, 10, "▁▂▃▄▅▆▇█"},
+		{"min and max", []float64{0.9, 0.1, 0.5}, 10, "█▁▅"},
+		{"truncated to width", []float64{100, 0, 1, 2}, 3, "▁▅█"},
+		{"nan gap", []float64{0, math.NaN(), 1}, 10, "▁ █"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTUIMetricsToggle(t *testing.T) {
+	ch := NewTUI(slog.Default(), func() []types.AgentInfo {
+		return []types.AgentInfo{
+			{ID: "beta", MessageCount: 1},
+			{ID: "alpha", MessageCount: 4, Metrics: types.AgentMetrics{
+				TotalActions: 4, SuccessfulActions: 3, TokensUsed: 1200, CostUSD: 0.02, AvgResponseMs: 350,
+			}},
+		}
+	})
+	var asked string
+	ch.SetFitnessSource(func(agentID string) []float64 {
+		asked = agentID
+		return []float64{0.2, 0.4, 0.8}
+	})
+
+	var model tea.Model = newTUIModel(ch)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	chatH := model.(tuiModel).chat.Height
+
+	m := model.(tuiModel)
+	m.input.SetValue("/metrics")
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(tuiModel)
+
+	if !m.showMetrics {
+		t.Fatal("expected /metrics to show the metrics pane")
+	}
+	if m.input.Value() != "" {
+		t.Errorf("input not cleared: %q", m.input.Value())
+	}
+	if m.chat.Height != chatH-metricsPaneHeight {
+		t.Errorf("chat height = %d, want %d", m.chat.Height, chatH-metricsPaneHeight)
+	}
+	if asked != "alpha" {
+		t.Errorf("fitness asked for %q, want alpha", asked)
+	}
+	select {
+	case msg := <-ch.inbox:
+		t.Errorf("/metrics should not be sent to agents, got %q", msg.Content)
+	default:
+	}
+
+	view := m.renderMetrics()
+	for _, want := range []string{"alpha", "tokens: 1200", "success: 75.0%", "▁▃█", "0.800"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("metrics pane missing %q:\n%s", want, view)
+		}
+	}
+
+	m.input.SetValue("/metrics")
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(tuiModel)
+	if m.showMetrics || m.chat.Height != chatH {
+		t.Errorf("expected pane hidden and chat height restored, got show=%v height=%d", m.showMetrics, m.chat.Height)
+	}
+}
+
+func TestNextAgent(t *testing.T) {
+	ids := []string{"alpha", "beta", "gamma"}
+	tests := []struct {
+		name    string
+		ids     []string
+		current string
+		step    int
+		want    string
+	}{
+		{"no agents", nil, "", 1, ""},
+		{"unset forward", ids, "", 1, "alpha"},
+		{"unset backward", ids, "", -1, "gamma"},
+		{"forward", ids, "alpha", 1, "beta"},
+		{"forward wraps", ids, "gamma", 1, "alpha"},
+		{"backward wraps", ids, "alpha", -1, "gamma"},
+		{"removed agent", ids, "delta", 1, "alpha"},
+		{"single agent", []string{"solo"}, "solo", 1, "solo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextAgent(tt.ids, tt.current, tt.step); got != tt.want {
+				t.Errorf("nextAgent(%v, %q, %d) = %q, want %q", tt.ids, tt.current, tt.step, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTUISwitchAgent(t *testing.T) {
+	ch := NewTUI(slog.Default(), func() []types.AgentInfo {
+		return []types.AgentInfo{{ID: "beta"}, {ID: "alpha"}}
+	})
+	var model tea.Model = newTUIModel(ch)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	send := func(text string) tuiModel {
+		m := model.(tuiModel)
+		m.input.SetValue(text)
+		model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		return model.(tuiModel)
+	}
+
+	// Messages go unaddressed until an agent is chosen.
+	m := send("hello")
+	if msg := <-ch.inbox; msg.To != "" {
+		t.Errorf("To = %q before switching, want empty", msg.To)
+	}
+
+	m = send("/switch beta")
+	if m.activeAgent != "beta" {
+		t.Fatalf("activeAgent = %q, want beta", m.activeAgent)
+	}
+	if !strings.Contains(m.View(), "→ beta") {
+		t.Error("header does not show the active agent")
+	}
+
+	m = send("/switch nobody")
+	if m.activeAgent != "beta" {
+		t.Errorf("unknown agent changed activeAgent to %q", m.activeAgent)
+	}
+	if last := m.messages[len(m.messages)-1]; !last.isNote || !strings.Contains(last.content, "nobody") {
+		t.Errorf("expected a note about the unknown agent, got %+v", last)
+	}
+
+	// Bare /switch and ctrl+n cycle in ID order.
+	m = send("/switch")
+	if m.activeAgent != "alpha" {
+		t.Errorf("after /switch activeAgent = %q, want alpha", m.activeAgent)
+	}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if got := model.(tuiModel).activeAgent; got != "beta" {
+		t.Errorf("after ctrl+n activeAgent = %q, want beta", got)
+	}
+
+	select {
+	case msg := <-ch.inbox:
+		t.Errorf("switch commands should not reach agents, got %q", msg.Content)
+	default:
+	}
+
+	send("hi beta")
+	if msg := <-ch.inbox; msg.To != "beta" {
+		t.Errorf("To = %q, want beta", msg.To)
+	}
+}
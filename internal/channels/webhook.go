@@ -0,0 +1,327 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/types"
+)
+
+const (
+	// webhookSignatureHeader carries "sha256=<hex HMAC>" of the body.
+	webhookSignatureHeader = "X-EvoClaw-Signature"
+	// githubSignatureHeader is accepted on inbound requests so GitHub
+	// webhooks can be verified without a proxy.
+	githubSignatureHeader = "X-Hub-Signature-256"
+
+	maxWebhookBody = 1 << 20 // 1 MiB
+)
+
+// WebhookOptions configures a WebhookChannel.
+type WebhookOptions struct {
+	// Listen is the inbound address, e.g. ":8421". Empty disables inbound.
+	Listen string
+	// Path is the inbound URL path. Defaults to "/webhook".
+	Path string
+	// ContentPath and SenderPath are dotted JSON paths (e.g.
+	// "alert.summary" or "commits.0.author.name") into the POSTed payload.
+	// They default to "content" and "from".
+	ContentPath string
+	SenderPath  string
+	// InboundSecret, when set, requires a valid HMAC-SHA256 signature on
+	// inbound requests.
+	InboundSecret string
+	// OutboundURL receives responses as JSON POSTs. Empty disables outbound.
+	OutboundURL string
+	// Secret, when set, signs outbound requests with HMAC-SHA256.
+	Secret string
+}
+
+// WebhookChannel implements the Channel interface for generic HTTP
+// webhooks: external systems POST JSON to trigger agents, and responses
+// are POSTed to a configured URL.
+type WebhookChannel struct {
+	opts   WebhookOptions
+	logger *slog.Logger
+	inbox  chan types.Message
+	client HTTPClient
+	server *http.Server
+	wg     sync.WaitGroup
+}
+
+// webhookPayload is the JSON body POSTed to the outbound URL.
+type webhookPayload struct {
+	AgentID   string            `json:"agent_id"`
+	Content   string            `json:"content"`
+	To        string            `json:"to,omitempty"`
+	ReplyTo   string            `json:"reply_to,omitempty"`
+	MessageID string            `json:"message_id,omitempty"`
+	Model     string            `json:"model,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	SentAt    int64             `json:"sent_at"`
+}
+
+// NewWebhook creates a webhook channel adapter.
+func NewWebhook(opts WebhookOptions, logger *slog.Logger) *WebhookChannel {
+	return NewWebhookWithClient(opts, logger, &DefaultHTTPClient{
+		client: &http.Client{Timeout: 15 * time.Second},
+	})
+}
+
+// NewWebhookWithClient creates a webhook channel with a custom HTTP client (for testing)
+func NewWebhookWithClient(opts WebhookOptions, logger *slog.Logger, client HTTPClient) *WebhookChannel {
+	if opts.Path == "" {
+		opts.Path = "/webhook"
+	}
+	if opts.ContentPath == "" {
+		opts.ContentPath = "content"
+	}
+	if opts.SenderPath == "" {
+		opts.SenderPath = "from"
+	}
+	return &WebhookChannel{
+		opts:   opts,
+		logger: logger.With("channel", "webhook"),
+		inbox:  make(chan types.Message, 100),
+		client: client,
+	}
+}
+
+func (w *WebhookChannel) Name() string {
+	return "webhook"
+}
+
+// Start begins listening for inbound webhooks when an address is configured.
+func (w *WebhookChannel) Start(ctx context.Context) error {
+	if w.opts.Listen == "" {
+		w.logger.Info("webhook channel started", "inbound", false, "outbound", w.opts.OutboundURL != "")
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", w.opts.Listen)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", w.opts.Listen, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(w.opts.Path, w)
+	w.server = &http.Server{
+		Handler:      mux,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		if err := w.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			w.logger.Error("webhook server error", "error", err)
+		}
+	}()
+
+	w.logger.Info("webhook channel started", "listen", ln.Addr().String(), "path", w.opts.Path)
+	return nil
+}
+
+func (w *WebhookChannel) Stop() error {
+	w.logger.Info("stopping webhook channel")
+	if w.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := w.server.Shutdown(ctx); err != nil {
+			w.logger.Warn("webhook server shutdown", "error", err)
+		}
+	}
+	w.wg.Wait()
+	close(w.inbox)
+	return nil
+}
+
+func (w *WebhookChannel) Receive() <-chan types.Message {
+	return w.inbox
+}
+
+// AcceptsBroadcast reports whether orchestrator broadcasts can be delivered,
+// i.e. whether an outbound URL is configured.
+func (w *WebhookChannel) AcceptsBroadcast() bool {
+	return w.opts.OutboundURL != ""
+}
+
+// ServeHTTP converts a POSTed JSON payload into a message for the
+// orchestrator. The optional ?agent= query parameter targets an agent.
+func (w *WebhookChannel) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody+1))
+	if err != nil {
+		http.Error(rw, "read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxWebhookBody {
+		http.Error(rw, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if w.opts.InboundSecret != "" {
+		sig := r.Header.Get(webhookSignatureHeader)
+		if sig == "" {
+			sig = r.Header.Get(githubSignatureHeader)
+		}
+		if !validSignature(w.opts.InboundSecret, body, sig) {
+			http.Error(rw, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	msg, err := w.parseInbound(body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	msg.To = r.URL.Query().Get("agent")
+
+	select {
+	case w.inbox <- msg:
+	default:
+		http.Error(rw, "inbox full", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.logger.Info("incoming message", "channel", "webhook", "from", msg.From, "length", len(msg.Content))
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(rw).Encode(map[string]string{"id": msg.ID})
+}
+
+// parseInbound maps a JSON payload to a message using the configured paths.
+func (w *WebhookChannel) parseInbound(body []byte) (types.Message, error) {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return types.Message{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	content, ok := lookupJSONPath(doc, w.opts.ContentPath)
+	if !ok || strings.TrimSpace(content) == "" {
+		return types.Message{}, fmt.Errorf("no content at %q", w.opts.ContentPath)
+	}
+	sender, ok := lookupJSONPath(doc, w.opts.SenderPath)
+	if !ok || sender == "" {
+		sender = "webhook"
+	}
+
+	return types.Message{
+		ID:        fmt.Sprintf("webhook-%d", time.Now().UnixNano()),
+		Channel:   "webhook",
+		From:      sender,
+		Content:   content,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// Send POSTs the response to the outbound URL, signing it when a secret is set.
+func (w *WebhookChannel) Send(ctx context.Context, msg types.Response) error {
+	if w.opts.OutboundURL == "" {
+		return fmt.Errorf("webhook: no outbound URL configured")
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		AgentID:   msg.AgentID,
+		Content:   msg.Content,
+		To:        msg.To,
+		ReplyTo:   msg.ReplyTo,
+		MessageID: msg.MessageID,
+		Model:     msg.Model,
+		Metadata:  msg.Metadata,
+		SentAt:    time.Now().Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opts.OutboundURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.opts.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, signPayload(w.opts.Secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// signPayload returns "sha256=<hex>" of the HMAC-SHA256 of body.
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// validSignature checks a "sha256=<hex>" signature in constant time.
+func validSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(signPayload(secret, body)), []byte(signature))
+}
+
+// lookupJSONPath walks a decoded JSON document along a dotted path. Numeric
+// segments index into arrays. Non-string leaves are returned as JSON.
+func lookupJSONPath(doc interface{}, path string) (string, bool) {
+	cur := doc
+	for _, seg := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			v, ok := node[seg]
+			if !ok {
+				return "", false
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			cur = node[i]
+		default:
+			return "", false
+		}
+	}
+
+	switch v := cur.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(raw), true
+	}
+}
@@ -0,0 +1,153 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/types"
+)
+
+func TestWebhookInboundMapping(t *testing.T) {
+	w := NewWebhook(WebhookOptions{
+		ContentPath: "alert.summary",
+		SenderPath:  "alert.labels.0",
+	}, testLogger())
+
+	body := `{"alert":{"summary":"disk 95% full on db-1","labels":["prometheus","critical"]}}`
+	rec := httptest.NewRecorder()
+	w.ServeHTTP(rec, httptest.NewRequest("POST", "/webhook?agent=ops", strings.NewReader(body)))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	select {
+	case msg := <-w.Receive():
+		if msg.Content != "disk 95% full on db-1" {
+			t.Errorf("Content = %q", msg.Content)
+		}
+		if msg.From != "prometheus" || msg.To != "ops" || msg.Channel != "webhook" {
+			t.Errorf("From = %q, To = %q, Channel = %q", msg.From, msg.To, msg.Channel)
+		}
+	default:
+		t.Fatal("no message delivered to inbox")
+	}
+}
+
+func TestWebhookInboundDefaultsAndErrors(t *testing.T) {
+	w := NewWebhook(WebhookOptions{}, testLogger())
+
+	rec := httptest.NewRecorder()
+	w.ServeHTTP(rec, httptest.NewRequest("POST", "/webhook", strings.NewReader(`{"content":"ping"}`)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if msg := <-w.Receive(); msg.Content != "ping" || msg.From != "webhook" {
+		t.Errorf("msg = %+v, want content ping from webhook", msg)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   int
+	}{
+		{"wrong method", "GET", "", http.StatusMethodNotAllowed},
+		{"invalid json", "POST", "{", http.StatusBadRequest},
+		{"missing content", "POST", `{"text":"hi"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			w.ServeHTTP(rec, httptest.NewRequest(tt.method, "/webhook", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookInboundSignature(t *testing.T) {
+	w := NewWebhook(WebhookOptions{InboundSecret: "topsecret"}, testLogger())
+	body := []byte(`{"content":"deploy finished"}`)
+
+	rec := httptest.NewRecorder()
+	w.ServeHTTP(rec, httptest.NewRequest("POST", "/webhook", bytes.NewReader(body)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned: status = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(body))
+	req.Header.Set("X-Hub-Signature-256", signPayload("topsecret", body))
+	rec = httptest.NewRecorder()
+	w.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("signed: status = %d, want 202", rec.Code)
+	}
+}
+
+func TestWebhookOutboundSigned(t *testing.T) {
+	type delivery struct {
+		sig  string
+		body []byte
+	}
+	got := make(chan delivery, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- delivery{sig: r.Header.Get("X-EvoClaw-Signature"), body: body}
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(WebhookOptions{OutboundURL: srv.URL, Secret: "shh"}, testLogger())
+	err := w.Send(context.Background(), types.Response{AgentID: "ops", Content: "rolled back", ReplyTo: "webhook-1"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	select {
+	case d := <-got:
+		if d.sig != signPayload("shh", d.body) {
+			t.Errorf("signature %q does not match body", d.sig)
+		}
+		var payload webhookPayload
+		if err := json.Unmarshal(d.body, &payload); err != nil {
+			t.Fatalf("decode payload: %v", err)
+		}
+		if payload.AgentID != "ops" || payload.Content != "rolled back" || payload.ReplyTo != "webhook-1" {
+			t.Errorf("payload = %+v", payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no outbound delivery")
+	}
+}
+
+func TestWebhookOutboundErrors(t *testing.T) {
+	if err := NewWebhook(WebhookOptions{}, testLogger()).Send(context.Background(), types.Response{}); err == nil {
+		t.Error("expected error without outbound URL")
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		http.Error(rw, "nope", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	w := NewWebhook(WebhookOptions{OutboundURL: srv.URL}, testLogger())
+	if err := w.Send(context.Background(), types.Response{Content: "x"}); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("err = %v, want 502 error", err)
+	}
+}
+
+func TestWebhookStartStop(t *testing.T) {
+	w := NewWebhook(WebhookOptions{Listen: "127.0.0.1:0"}, testLogger())
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+}
@@ -3,10 +3,14 @@ package clawchain
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -31,6 +35,23 @@ type Proxy struct {
 
 	mu        sync.RWMutex
 	callbacks []EventCallback
+
+	// nonces tracks recent vote and registration submissions by nonce,
+	// so a resubmission returns the first one's result.
+	nonceMu sync.Mutex
+	nonces  map[string]*submission
+}
+
+// nonceTTL is how long a completed submission's nonce is remembered.
+const nonceTTL = 10 * time.Minute
+
+// submission is a vote or registration sent under a nonce. done is
+// closed once result and err are set.
+type submission struct {
+	done        chan struct{}
+	result      interface{}
+	err         error
+	completedAt time.Time
 }
 
 // New creates a new ClawChain RPC proxy.
@@ -39,6 +60,7 @@ func New(cfg ProxyConfig, logger *slog.Logger) *Proxy {
 		cfg:    cfg,
 		logger: logger,
 		caller: &httpCaller{timeout: time.Duration(cfg.RequestTimeoutSec) * time.Second},
+		nonces: make(map[string]*submission),
 	}
 }
 
@@ -48,19 +70,40 @@ func NewWithCaller(cfg ProxyConfig, logger *slog.Logger, caller RPCCaller) *Prox
 		cfg:    cfg,
 		logger: logger,
 		caller: caller,
+		nonces: make(map[string]*submission),
 	}
 }
 
 // ── Public RPC methods ────────────────────────────────────────────────
 
-// RegisterAgent registers an agent DID on-chain.
+// RegisterAgent registers an agent DID on-chain. Transport failures are
+// retried under a fresh nonce; see RegisterAgentWithNonce.
 func (p *Proxy) RegisterAgent(ctx context.Context, did string, metadata map[string]string) (*RegisterResult, error) {
+	return p.RegisterAgentWithNonce(ctx, NewNonce(), did, metadata)
+}
+
+// RegisterAgentWithNonce registers an agent DID on-chain under a
+// caller-chosen nonce. Repeating a recent call with the same nonce returns
+// the first call's result without resubmitting. After a transport failure
+// the registration is only resent if clawchain_getAgentInfo shows the DID
+// is still unregistered, so the agent is registered at most once.
+func (p *Proxy) RegisterAgentWithNonce(ctx context.Context, nonce, did string, metadata map[string]string) (*RegisterResult, error) {
 	if did == "" {
 		return nil, fmt.Errorf("did must not be empty")
 	}
+	if nonce == "" {
+		return nil, fmt.Errorf("nonce must not be empty")
+	}
 
 	params := []interface{}{did, metadata}
-	result, err := p.call(ctx, "clawchain_registerAgent", params)
+	registered := func(ctx context.Context) (interface{}, bool) {
+		info, err := p.GetAgentInfo(ctx, did)
+		if err != nil || info.DID != did {
+			return nil, false
+		}
+		return map[string]interface{}{"did": did}, true
+	}
+	result, err := p.submit(ctx, "clawchain_registerAgent", nonce, params, registered)
 	if err != nil {
 		return nil, fmt.Errorf("register agent: %w", err)
 	}
@@ -123,17 +166,31 @@ func (p *Proxy) GetBalance(ctx context.Context, did string) (*BalanceInfo, error
 	return &bal, nil
 }
 
-// Vote casts a governance vote for the given agent.
+// Vote casts a governance vote for the given agent. Transport failures
+// are retried under a fresh nonce; see VoteWithNonce.
 func (p *Proxy) Vote(ctx context.Context, did string, proposalID uint64, vote string) (*VoteResult, error) {
+	return p.VoteWithNonce(ctx, NewNonce(), did, proposalID, vote)
+}
+
+// VoteWithNonce casts a governance vote under a caller-chosen nonce.
+// Repeating a recent call with the same nonce returns the first call's
+// result without resubmitting. The node has no per-voter vote record to
+// check, so after a transport failure the vote is only resent if the
+// request never reached the node (the connection could not be opened);
+// otherwise the error is returned rather than risk counting it twice.
+func (p *Proxy) VoteWithNonce(ctx context.Context, nonce, did string, proposalID uint64, vote string) (*VoteResult, error) {
 	if did == "" {
 		return nil, fmt.Errorf("did must not be empty")
 	}
 	if vote != "for" && vote != "against" {
 		return nil, fmt.Errorf("vote must be 'for' or 'against', got %q", vote)
 	}
+	if nonce == "" {
+		return nil, fmt.Errorf("nonce must not be empty")
+	}
 
 	params := []interface{}{did, proposalID, vote}
-	result, err := p.call(ctx, "clawchain_vote", params)
+	result, err := p.submit(ctx, "clawchain_vote", nonce, params, nil)
 	if err != nil {
 		return nil, fmt.Errorf("vote: %w", err)
 	}
@@ -249,6 +306,120 @@ func (p *Proxy) dispatchEvent(event ChainEvent) {
 	}
 }
 
+// ── Idempotent submissions ────────────────────────────────────────────
+
+// NewNonce returns a random idempotency key for VoteWithNonce and
+// RegisterAgentWithNonce.
+func NewNonce() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand doesn't fail on supported platforms
+		panic(fmt.Sprintf("clawchain: generate nonce: %v", err))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// landedFunc reports whether a submission whose response was lost has
+// been applied on-chain, and if so the result to return for it.
+type landedFunc func(ctx context.Context) (interface{}, bool)
+
+// submit sends a state-changing call. The nonce is only used here, not
+// sent to the node, which does not deduplicate: a nonce seen within
+// nonceTTL isn't sent again and the caller gets the earlier submission's
+// result, waiting for it if it is still in flight. A failed submission is
+// forgotten so it can be retried. landed, if not nil, is consulted before
+// resending after a transport failure; see callWithRetry.
+func (p *Proxy) submit(ctx context.Context, method, nonce string, params []interface{}, landed landedFunc) (interface{}, error) {
+	p.nonceMu.Lock()
+	p.pruneNoncesLocked(time.Now())
+	if s, ok := p.nonces[nonce]; ok {
+		p.nonceMu.Unlock()
+		p.logger.Debug("duplicate submission, reusing result",
+			"method", method,
+			"nonce", nonce,
+		)
+		select {
+		case <-s.done:
+			return s.result, s.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	s := &submission{done: make(chan struct{})}
+	p.nonces[nonce] = s
+	p.nonceMu.Unlock()
+
+	s.result, s.err = p.callWithRetry(ctx, method, params, landed)
+	s.completedAt = time.Now()
+	if s.err != nil {
+		p.nonceMu.Lock()
+		delete(p.nonces, nonce)
+		p.nonceMu.Unlock()
+	}
+	close(s.done)
+
+	return s.result, s.err
+}
+
+// pruneNoncesLocked forgets submissions completed more than nonceTTL
+// ago. Caller must hold p.nonceMu.
+func (p *Proxy) pruneNoncesLocked(now time.Time) {
+	for nonce, s := range p.nonces {
+		select {
+		case <-s.done:
+			if now.Sub(s.completedAt) > nonceTTL {
+				delete(p.nonces, nonce)
+			}
+		default:
+		}
+	}
+}
+
+// callWithRetry makes an RPC call, retrying transport failures up to
+// cfg.SubmitRetries times with doubling backoff. Errors returned by the
+// node are not retried. A failure after the request may have reached the
+// node is only retried if landed reports the call was not applied; with
+// no landed check it is returned as is.
+func (p *Proxy) callWithRetry(ctx context.Context, method string, params interface{}, landed landedFunc) (interface{}, error) {
+	backoff := time.Duration(p.cfg.SubmitRetryBackoffMs) * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		result, err := p.call(ctx, method, params)
+		var rpcErr *RPCError
+		if err == nil || errors.As(err, &rpcErr) || attempt >= p.cfg.SubmitRetries {
+			return result, err
+		}
+		if !neverSent(err) {
+			if landed == nil {
+				return nil, err
+			}
+			if result, ok := landed(ctx); ok {
+				p.logger.Info("RPC submission applied despite transport failure", "method", method)
+				return result, nil
+			}
+		}
+
+		p.logger.Warn("RPC submission failed, retrying",
+			"method", method,
+			"attempt", attempt+1,
+			"backoff", backoff,
+			"error", err,
+		)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w (giving up: %v)", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// neverSent reports whether err shows the request never left this host:
+// the connection to the node could not be opened.
+func neverSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
 // ── Internal RPC plumbing ─────────────────────────────────────────────
 
 func (p *Proxy) call(ctx context.Context, method string, params interface{}) (interface{}, error) {
@@ -281,7 +452,7 @@ func (p *Proxy) call(ctx context.Context, method string, params interface{}) (in
 			"code", resp.Error.Code,
 			"message", resp.Error.Message,
 		)
-		return nil, fmt.Errorf("RPC error %d: %s", resp.Error.Code, resp.Error.Message)
+		return nil, &RPCError{Code: resp.Error.Code, Message: resp.Error.Message}
 	}
 
 	return resp.Result, nil
@@ -3,8 +3,10 @@ package clawchain
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
 	"sync"
 	"sync/atomic"
@@ -89,6 +91,7 @@ func newTestLogger() *slog.Logger {
 
 func newTestProxy(caller RPCCaller) *Proxy {
 	cfg := DefaultProxyConfig()
+	cfg.SubmitRetryBackoffMs = 1
 	return NewWithCaller(cfg, newTestLogger(), caller)
 }
 
@@ -991,3 +994,227 @@ func TestConcurrentRequests(t *testing.T) {
 		t.Errorf("expected 20 calls, got %d", caller.CallCount())
 	}
 }
+
+// ── Idempotent submission tests ─────────────────────────────────────
+
+// flakyNode simulates a node that, like the real one, does no
+// deduplication of its own. Its first failures submissions fail in
+// transit, in the way set by mode.
+type flakyNode struct {
+	mu       sync.Mutex
+	failures int
+	mode     failureMode
+	calls    int // submissions received, including failed ones
+	lookups  int // clawchain_getAgentInfo calls
+	applied  []map[string]interface{}
+	params   [][]interface{}
+}
+
+type failureMode int
+
+const (
+	// lostResponse applies the submission but loses the response, the
+	// worst case for a retry.
+	lostResponse failureMode = iota
+	// lostRequest fails before the node sees the request.
+	lostRequest
+	// unreachable fails to connect at all.
+	unreachable
+)
+
+func newFlakyNode(failures int, mode failureMode) *flakyNode {
+	return &flakyNode{failures: failures, mode: mode}
+}
+
+func (n *flakyNode) Call(_ context.Context, _ string, req SubstrateRPCRequest) (*SubstrateRPCResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	params := req.Params.([]interface{})
+
+	if req.Method == "clawchain_getAgentInfo" {
+		n.lookups++
+		for _, a := range n.applied {
+			if _, ok := a["vote"]; !ok && a["did"] == params[0] {
+				return &SubstrateRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: map[string]interface{}{"did": params[0]}}, nil
+			}
+		}
+		return &SubstrateRPCResponse{JSONRPC: "2.0", ID: req.ID, Error: &SubstrateRPCErr{Code: -32001, Message: "agent not found"}}, nil
+	}
+
+	n.calls++
+	n.params = append(n.params, params)
+	failing := n.failures > 0
+	if failing {
+		n.failures--
+		switch n.mode {
+		case unreachable:
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		case lostRequest:
+			return nil, fmt.Errorf("request timeout")
+		}
+	}
+
+	result := map[string]interface{}{
+		"tx_hash": fmt.Sprintf("0xtx%d", len(n.applied)+1),
+		"did":     params[0],
+	}
+	if req.Method == "clawchain_vote" {
+		result["proposal_id"] = params[1]
+		result["vote"] = params[2]
+	}
+	n.applied = append(n.applied, result)
+	if failing {
+		return nil, fmt.Errorf("connection reset by peer")
+	}
+	return &SubstrateRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result}, nil
+}
+
+func TestVote_RetriesWhenNodeUnreachable(t *testing.T) {
+	node := newFlakyNode(2, unreachable)
+	p := newTestProxy(node)
+
+	result, err := p.Vote(context.Background(), "did:claw:voter", 7, "for")
+	if err != nil {
+		t.Fatalf("vote should succeed after retrying: %v", err)
+	}
+	if result.TxHash != "0xtx1" {
+		t.Errorf("tx_hash = %q, want 0xtx1", result.TxHash)
+	}
+	if node.calls != 3 || len(node.applied) != 1 {
+		t.Errorf("calls = %d, applied = %d; want 3 calls, 1 vote", node.calls, len(node.applied))
+	}
+	if got := len(node.params[0]); got != 3 {
+		t.Errorf("vote sent with %d params, want did, proposal and vote only", got)
+	}
+}
+
+func TestVote_NotResentAfterLostResponse(t *testing.T) {
+	node := newFlakyNode(1, lostResponse)
+	p := newTestProxy(node)
+
+	if _, err := p.Vote(context.Background(), "did:claw:voter", 7, "for"); err == nil {
+		t.Fatal("expected the transport error")
+	}
+	if node.calls != 1 || len(node.applied) != 1 {
+		t.Errorf("calls = %d, applied = %d; a vote that may have landed must not be resent", node.calls, len(node.applied))
+	}
+}
+
+func TestRegisterAgent_ChecksChainBeforeResending(t *testing.T) {
+	node := newFlakyNode(1, lostResponse)
+	p := newTestProxy(node)
+
+	result, err := p.RegisterAgent(context.Background(), "did:claw:new", map[string]string{"type": "sensor"})
+	if err != nil {
+		t.Fatalf("register should succeed once the chain shows it: %v", err)
+	}
+	if result.DID != "did:claw:new" {
+		t.Errorf("result = %+v", result)
+	}
+	if node.calls != 1 || node.lookups != 1 || len(node.applied) != 1 {
+		t.Errorf("calls = %d, lookups = %d, applied = %d; want 1 call, 1 lookup, 1 registration",
+			node.calls, node.lookups, len(node.applied))
+	}
+	if got := len(node.params[0]); got != 2 {
+		t.Errorf("registration sent with %d params, want did and metadata only", got)
+	}
+}
+
+func TestRegisterAgent_ResendsWhenNotOnChain(t *testing.T) {
+	node := newFlakyNode(1, lostRequest)
+	p := newTestProxy(node)
+
+	result, err := p.RegisterAgent(context.Background(), "did:claw:new", nil)
+	if err != nil {
+		t.Fatalf("register should succeed after retrying: %v", err)
+	}
+	if result.TxHash != "0xtx1" {
+		t.Errorf("tx_hash = %q, want 0xtx1", result.TxHash)
+	}
+	if node.calls != 2 || node.lookups != 1 || len(node.applied) != 1 {
+		t.Errorf("calls = %d, lookups = %d, applied = %d; want 2 calls, 1 lookup, 1 registration",
+			node.calls, node.lookups, len(node.applied))
+	}
+}
+
+func TestVoteWithNonce_DuplicateNotResubmitted(t *testing.T) {
+	node := newFlakyNode(0, lostResponse)
+	p := newTestProxy(node)
+	ctx := context.Background()
+
+	first, err := p.VoteWithNonce(ctx, "nonce-1", "did:claw:voter", 7, "for")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := p.VoteWithNonce(ctx, "nonce-1", "did:claw:voter", 7, "for")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.calls != 1 {
+		t.Errorf("node called %d times, want 1", node.calls)
+	}
+	if second.TxHash != first.TxHash {
+		t.Errorf("duplicate got tx %q, want %q", second.TxHash, first.TxHash)
+	}
+
+	if _, err := p.VoteWithNonce(ctx, "nonce-2", "did:claw:voter", 7, "for"); err != nil {
+		t.Fatal(err)
+	}
+	if node.calls != 2 {
+		t.Errorf("a new nonce should be submitted: %d calls, want 2", node.calls)
+	}
+}
+
+func TestVoteWithNonce_ConcurrentDuplicates(t *testing.T) {
+	node := newFlakyNode(0, lostResponse)
+	p := newTestProxy(node)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.VoteWithNonce(context.Background(), "same", "did:claw:voter", 7, "for"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if node.calls != 1 {
+		t.Errorf("node called %d times, want 1", node.calls)
+	}
+}
+
+func TestVote_RetriesExhausted(t *testing.T) {
+	retries := DefaultProxyConfig().SubmitRetries
+	node := newFlakyNode(retries+1, unreachable)
+	p := newTestProxy(node)
+
+	if _, err := p.VoteWithNonce(context.Background(), "n", "did:claw:voter", 7, "for"); err == nil {
+		t.Fatal("expected error")
+	}
+	if node.calls != retries+1 {
+		t.Errorf("calls = %d, want %d", node.calls, retries+1)
+	}
+
+	// The failed nonce is forgotten, so resubmitting it reaches the node
+	result, err := p.VoteWithNonce(context.Background(), "n", "did:claw:voter", 7, "for")
+	if err != nil || result.TxHash != "0xtx1" {
+		t.Errorf("resubmit = %+v, %v", result, err)
+	}
+}
+
+func TestVote_RPCErrorNotRetried(t *testing.T) {
+	caller := newMockCaller()
+	caller.SetError("clawchain_vote", -32000, "proposal expired")
+	p := newTestProxy(caller)
+
+	_, err := p.Vote(context.Background(), "did:claw:voter", 99, "for")
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32000 {
+		t.Fatalf("err = %v, want RPCError -32000", err)
+	}
+	if caller.CallCount() != 1 {
+		t.Errorf("calls = %d, want 1", caller.CallCount())
+	}
+}
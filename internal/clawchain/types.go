@@ -1,6 +1,9 @@
 package clawchain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // AgentInfo represents on-chain agent information.
 type AgentInfo struct {
@@ -76,6 +79,17 @@ type SubstrateRPCErr struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// RPCError is an error response from the node. Unlike a transport
+// failure, it means the node received and rejected the request.
+type RPCError struct {
+	Code    int
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
 // MQTTRPCRequest is the envelope edge agents send via MQTT.
 type MQTTRPCRequest struct {
 	RequestID string      `json:"request_id"`
@@ -106,13 +120,23 @@ type ProxyConfig struct {
 	WebSocketURL string `json:"websocket_url"`
 	// RequestTimeoutSec is the timeout for individual RPC calls.
 	RequestTimeoutSec int `json:"request_timeout_sec"`
+	// SubmitRetries is how many times a vote or registration is resent
+	// after a transport failure. A registration is only resent while the
+	// agent is not yet on-chain, and a vote only if the node was never
+	// reached, so neither is applied twice. 0 disables retries.
+	SubmitRetries int `json:"submit_retries"`
+	// SubmitRetryBackoffMs is the wait before the first resend; it
+	// doubles after each one.
+	SubmitRetryBackoffMs int `json:"submit_retry_backoff_ms"`
 }
 
 // DefaultProxyConfig returns sensible defaults for local development.
 func DefaultProxyConfig() ProxyConfig {
 	return ProxyConfig{
-		NodeURL:           "http://localhost:9933",
-		WebSocketURL:      "ws://localhost:9944",
-		RequestTimeoutSec: 15,
+		NodeURL:              "http://localhost:9933",
+		WebSocketURL:         "ws://localhost:9944",
+		RequestTimeoutSec:    15,
+		SubmitRetries:        3,
+		SubmitRetryBackoffMs: 250,
 	}
 }
@@ -0,0 +1,369 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/agents"
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+// agentBundleFormat is the version of the bundle format written by
+// 'agent export'. Import accepts bundles up to this version.
+const agentBundleFormat = 1
+
+// AgentBundle is a portable copy of an agent, written by 'agent export'
+// and read by 'agent import' to move the agent to another device.
+type AgentBundle struct {
+	Format     int       `json:"format"`
+	ExportedAt time.Time `json:"exported_at"`
+	// Agent is the registry record: definition, counters and metrics.
+	Agent    agents.Agent        `json:"agent"`
+	Strategy *evolution.Strategy `json:"strategy,omitempty"`
+	Genome   *config.Genome      `json:"genome,omitempty"`
+	// Memory holds the agent's conversations by memory key: the agent ID
+	// for its default conversation, "<id>:<conversation>" for the others.
+	Memory map[string][]orchestrator.ChatMessage `json:"memory,omitempty"`
+}
+
+// agentImportOptions controls how 'agent import' handles an existing
+// agent.
+type agentImportOptions struct {
+	// As imports the agent under this ID instead of its own.
+	As string
+	// Replace overwrites an existing agent with the same ID.
+	Replace bool
+}
+
+// agentStores is the persisted agent state of a deployment, opened
+// without starting it.
+type agentStores struct {
+	cfg      *config.Config
+	store    storage.Store
+	registry *agents.Registry
+	engine   *evolution.Engine
+	memory   *agents.MemoryStore
+}
+
+// openAgentStores opens the agent registry, evolution state and
+// conversation memory under cfg's data directory.
+func openAgentStores(cfg *config.Config) (*agentStores, error) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	store, err := storage.Open(cfg.Server.Storage, cfg.Server.DataDir)
+	if err != nil {
+		return nil, fmt.Errorf("open storage: %w", err)
+	}
+	registry := agents.NewRegistryWithStore(store, logger)
+	registry.SetAgentDataDirs(cfg.Server.AgentDataDirs)
+	if err := registry.Load(); err != nil {
+		return nil, fmt.Errorf("load agents: %w", err)
+	}
+	memory, err := agents.NewMemoryStore(cfg.Server.DataDir, logger)
+	if err != nil {
+		return nil, fmt.Errorf("open memory: %w", err)
+	}
+	if cfg.Server.AgentDataDirs {
+		memory.SetAgentDataDirs(cfg.Server.DataDir)
+	}
+	engine := evolution.NewEngineWithStore(store, logger)
+	engine.SetAgentDataDirs(cfg.Server.AgentDataDirs)
+	engine.SetRequireSignedGenomes(cfg.Evolution.RequireSignedGenomes)
+	engine.SetOwnerPublicKey(cfg.Evolution.OwnerPublicKey)
+
+	return &agentStores{cfg: cfg, store: store, registry: registry, engine: engine, memory: memory}, nil
+}
+
+// close releases the storage backend.
+func (st *agentStores) close() {
+	if c, ok := st.store.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
+
+// configAgent returns the agent with id from the config, if any.
+func (st *agentStores) configAgent(id string) (config.AgentDef, bool) {
+	for _, def := range st.cfg.Agents {
+		if def.ID == id {
+			return def, true
+		}
+	}
+	return config.AgentDef{}, false
+}
+
+// exportAgent bundles everything persisted for the agent with id.
+func exportAgent(st *agentStores, id string) (*AgentBundle, error) {
+	b := &AgentBundle{Format: agentBundleFormat, ExportedAt: time.Now().UTC()}
+
+	if a, err := st.registry.Get(id); err == nil {
+		b.Agent = a.GetSnapshot()
+	} else if def, ok := st.configAgent(id); ok {
+		// Configured but never started: there is only the definition
+		b.Agent = agents.Agent{ID: id, Def: def}
+	} else {
+		return nil, fmt.Errorf("agent %q not found", id)
+	}
+
+	if s, ok := st.engine.GetStrategy(id).(*evolution.Strategy); ok && s != nil {
+		b.Strategy = s
+	}
+	g, err := st.engine.GetGenome(id)
+	switch {
+	case err == nil:
+		b.Genome = g
+	case !errors.Is(err, storage.ErrNotFound):
+		return nil, fmt.Errorf("read genome: %w", err)
+	}
+
+	keys, err := st.memory.ConversationKeys(id)
+	if err != nil {
+		return nil, fmt.Errorf("list conversations: %w", err)
+	}
+	for _, key := range keys {
+		msgs := st.memory.Get(key).GetMessages()
+		if len(msgs) == 0 {
+			continue
+		}
+		if b.Memory == nil {
+			b.Memory = make(map[string][]orchestrator.ChatMessage)
+		}
+		b.Memory[key] = msgs
+	}
+	return b, nil
+}
+
+// importAgent recreates the agent in b, adds it to the config and saves
+// the config to configPath. It returns the imported agent's ID and
+// warnings about things the agent needs that this deployment lacks.
+func importAgent(st *agentStores, configPath string, b *AgentBundle, opts agentImportOptions) (string, []string, error) {
+	if b.Format < 1 || b.Format > agentBundleFormat {
+		return "", nil, fmt.Errorf("unsupported bundle format %d (this version reads formats 1-%d); upgrade evoclaw to import it", b.Format, agentBundleFormat)
+	}
+	oldID := b.Agent.ID
+	id := oldID
+	if opts.As != "" {
+		id = opts.As
+	}
+	if id == "" {
+		return "", nil, fmt.Errorf("bundle has no agent id")
+	}
+	if strings.Contains(id, "/") {
+		return "", nil, fmt.Errorf("agent id %q must not contain '/'", id)
+	}
+
+	_, notRegistered := st.registry.Get(id)
+	_, inConfig := st.configAgent(id)
+	if (notRegistered == nil || inConfig) && !opts.Replace {
+		return "", nil, fmt.Errorf("agent %q already exists; use --force to replace it or --as to import it under another ID", id)
+	}
+
+	agent := b.Agent.GetSnapshot()
+	agent.ID = id
+	agent.Def.ID = id
+	if err := st.registry.Restore(&agent, opts.Replace); err != nil {
+		return "", nil, err
+	}
+
+	if b.Strategy != nil {
+		s := *b.Strategy
+		st.engine.SetStrategy(id, &s)
+	}
+	if b.Genome != nil {
+		if err := st.engine.UpdateGenome(id, b.Genome); err != nil {
+			return "", nil, fmt.Errorf("import genome: %w", err)
+		}
+	}
+
+	keys := make([]string, 0, len(b.Memory))
+	for key := range b.Memory {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		newKey := key
+		if key == oldID {
+			newKey = id
+		} else if conv, ok := strings.CutPrefix(key, oldID+":"); ok {
+			newKey = id + ":" + conv
+		} else {
+			continue
+		}
+		if err := st.memory.Restore(newKey, b.Memory[key]); err != nil {
+			return "", nil, fmt.Errorf("import conversation %s: %w", key, err)
+		}
+	}
+
+	replaced := false
+	for i, def := range st.cfg.Agents {
+		if def.ID == id {
+			st.cfg.Agents[i] = agent.Def
+			replaced = true
+		}
+	}
+	if !replaced {
+		st.cfg.Agents = append(st.cfg.Agents, agent.Def)
+	}
+	if err := st.cfg.Save(configPath); err != nil {
+		return "", nil, fmt.Errorf("save config: %w", err)
+	}
+
+	return id, importWarnings(st.cfg, agent.Def), nil
+}
+
+// importWarnings lists what an imported agent uses that cfg doesn't
+// provide.
+func importWarnings(cfg *config.Config, def config.AgentDef) []string {
+	var warnings []string
+	for _, model := range []string{def.Model, def.Shadow.Model} {
+		provider, _, ok := strings.Cut(model, "/")
+		if !ok || def.Remote {
+			continue
+		}
+		if _, configured := cfg.Models.Providers[provider]; !configured {
+			warnings = append(warnings, fmt.Sprintf("model %s: provider %q is not configured on this device", model, provider))
+		}
+	}
+	return warnings
+}
+
+// AgentCommand handles the 'evoclaw agent' subcommands
+func AgentCommand(args []string, configPath string) int {
+	if len(args) == 0 {
+		printAgentHelp()
+		return 1
+	}
+
+	switch args[0] {
+	case "export":
+		return agentExport(args[1:], configPath)
+	case "import":
+		return agentImport(args[1:], configPath)
+	case "help", "--help", "-h":
+		printAgentHelp()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown agent subcommand: %s\n", args[0])
+		printAgentHelp()
+		return 1
+	}
+}
+
+func printAgentHelp() {
+	fmt.Println(`Usage: evoclaw agent <subcommand> [options]
+
+Move agents between devices. Run these while EvoClaw is stopped; an
+imported agent starts with the next start.
+
+Subcommands:
+  export <agent-id> <file>   Write the agent (definition, genome, strategy,
+                             conversation memory and metrics) to a bundle
+  import <file>              Recreate an agent from a bundle and add it to
+                             the config
+    --as <agent-id>          Import under another ID
+    --force                  Replace an agent with the same ID
+
+Examples:
+  evoclaw agent export trader-1 trader-1.evoagent.json
+  evoclaw agent import trader-1.evoagent.json
+  evoclaw agent import trader-1.evoagent.json --as trader-2`)
+}
+
+func agentExport(args []string, configPath string) int {
+	positional, _ := splitArgs(args)
+	if len(positional) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: evoclaw agent export <agent-id> <file>")
+		return 1
+	}
+	id, path := positional[0], positional[1]
+
+	cfg, err := loadConfigFromFile(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	st, err := openAgentStores(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer st.close()
+
+	bundle, err := exportAgent(st, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding bundle: %v\n", err)
+		return 1
+	}
+	// Conversations may hold private data
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing bundle: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Exported agent %s to %s\n", id, path)
+	fmt.Printf("   Genome: %t, strategy: %t, conversations: %d\n", bundle.Genome != nil, bundle.Strategy != nil, len(bundle.Memory))
+	return 0
+}
+
+func agentImport(args []string, configPath string) int {
+	positional, flagArgs := splitArgs(args, "force")
+	fs := flag.NewFlagSet("agent import", flag.ContinueOnError)
+	as := fs.String("as", "", "Import the agent under this ID")
+	force := fs.Bool("force", false, "Replace an agent with the same ID")
+	if err := fs.Parse(flagArgs); err != nil {
+		return 1
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: evoclaw agent import <file> [--as <agent-id>] [--force]")
+		return 1
+	}
+
+	data, err := os.ReadFile(positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading bundle: %v\n", err)
+		return 1
+	}
+	var bundle AgentBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s is not an agent bundle: %v\n", positional[0], err)
+		return 1
+	}
+
+	cfg, err := loadConfigFromFile(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	st, err := openAgentStores(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer st.close()
+
+	id, warnings, err := importAgent(st, configPath, &bundle, agentImportOptions{As: *as, Replace: *force})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("✅ Imported agent %s\n", id)
+	for _, w := range warnings {
+		fmt.Printf("⚠️  %s\n", w)
+	}
+	return 0
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
+)
+
+// newAgentDevice writes a config with its own data directory, as if on
+// a separate device, and returns the config path.
+func newAgentDevice(t *testing.T, agentDefs ...config.AgentDef) string {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Server.DataDir = filepath.Join(dir, "data")
+	cfg.Agents = agentDefs
+	path := filepath.Join(dir, "evoclaw.json")
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	return path
+}
+
+func openTestAgentStores(t *testing.T, configPath string) *agentStores {
+	t.Helper()
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	st, err := openAgentStores(cfg)
+	if err != nil {
+		t.Fatalf("open stores: %v", err)
+	}
+	t.Cleanup(st.close)
+	return st
+}
+
+// seedAgent gives the agent with id a record, metrics, strategy, genome
+// and two conversations on the device at configPath.
+func seedAgent(t *testing.T, configPath, id string) {
+	t.Helper()
+	st := openTestAgentStores(t, configPath)
+	def, _ := st.configAgent(id)
+	if _, err := st.registry.Create(def); err != nil {
+		t.Fatalf("create agent: %v", err)
+	}
+	for _, ok := range []bool{true, true, false} {
+		if err := st.registry.UpdateMetrics(id, 120, 0.01, 300, ok); err != nil {
+			t.Fatalf("update metrics: %v", err)
+		}
+	}
+	st.engine.SetStrategy(id, &evolution.Strategy{
+		ID:          id + "-v3",
+		Version:     3,
+		Temperature: 0.4,
+		MaxTokens:   800,
+		Params:      map[string]float64{"riskTolerance": 0.25},
+		Fitness:     0.82,
+		EvalCount:   12,
+	})
+	genome := &config.Genome{
+		Identity: config.GenomeIdentity{Name: "Trader", Persona: "careful"},
+		Behavior: config.GenomeBehavior{RiskTolerance: 0.25, Verbosity: 0.5, Autonomy: 0.7},
+	}
+	if err := st.engine.UpdateGenome(id, genome); err != nil {
+		t.Fatalf("update genome: %v", err)
+	}
+	st.memory.Get(id).Add("user", "hello")
+	st.memory.Get(id).Add("assistant", "hi there")
+	st.memory.Get(id+":research").Add("user", "summarise the market")
+	if err := st.memory.SaveAll(); err != nil {
+		t.Fatalf("save memory: %v", err)
+	}
+}
+
+func exportTestAgent(t *testing.T, configPath, id string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), id+".evoagent.json")
+	if code := AgentCommand([]string{"export", id, path}, configPath); code != 0 {
+		t.Fatalf("export exit code = %d", code)
+	}
+	return path
+}
+
+func TestAgentExportImportRoundTrip(t *testing.T) {
+	def := config.AgentDef{ID: "trader-1", Name: "Trader", Type: "trader", Model: "anthropic/claude-sonnet"}
+	src := newAgentDevice(t, def)
+	seedAgent(t, src, "trader-1")
+	bundlePath := exportTestAgent(t, src, "trader-1")
+
+	dst := newAgentDevice(t)
+	if code := AgentCommand([]string{"import", bundlePath}, dst); code != 0 {
+		t.Fatalf("import exit code = %d", code)
+	}
+
+	want := openTestAgentStores(t, src)
+	got := openTestAgentStores(t, dst)
+
+	if d, ok := got.configAgent("trader-1"); !ok || d.Model != def.Model || d.Type != def.Type {
+		t.Errorf("config agent = %+v, %v; want %+v", d, ok, def)
+	}
+
+	wantAgent, _ := want.registry.Get("trader-1")
+	gotAgent, err := got.registry.Get("trader-1")
+	if err != nil {
+		t.Fatalf("imported agent not registered: %v", err)
+	}
+	wm, gm := wantAgent.GetSnapshot().Metrics, gotAgent.GetSnapshot().Metrics
+	if gm.TotalActions != wm.TotalActions || gm.SuccessfulActions != wm.SuccessfulActions ||
+		gm.FailedActions != wm.FailedActions || gm.TokensUsed != wm.TokensUsed || gm.CostUSD != wm.CostUSD {
+		t.Errorf("metrics = %+v, want %+v", gm, wm)
+	}
+
+	ws := want.engine.GetStrategy("trader-1").(*evolution.Strategy)
+	gs, ok := got.engine.GetStrategy("trader-1").(*evolution.Strategy)
+	if !ok || gs == nil {
+		t.Fatal("imported strategy missing")
+	}
+	if gs.Version != ws.Version || gs.Fitness != ws.Fitness || gs.EvalCount != ws.EvalCount ||
+		gs.Temperature != ws.Temperature || gs.Params["riskTolerance"] != ws.Params["riskTolerance"] {
+		t.Errorf("strategy = %+v, want %+v", gs, ws)
+	}
+
+	wg, _ := want.engine.GetGenome("trader-1")
+	gg, err := got.engine.GetGenome("trader-1")
+	if err != nil {
+		t.Fatalf("imported genome missing: %v", err)
+	}
+	if gg.Identity != wg.Identity || gg.Behavior.RiskTolerance != wg.Behavior.RiskTolerance ||
+		gg.Behavior.Autonomy != wg.Behavior.Autonomy {
+		t.Errorf("genome = %+v, want %+v", gg, wg)
+	}
+
+	for _, key := range []string{"trader-1", "trader-1:research"} {
+		wmsgs, gmsgs := want.memory.Get(key).GetMessages(), got.memory.Get(key).GetMessages()
+		if len(gmsgs) != len(wmsgs) {
+			t.Fatalf("%s: %d messages, want %d", key, len(gmsgs), len(wmsgs))
+		}
+		for i := range wmsgs {
+			if gmsgs[i].Role != wmsgs[i].Role || gmsgs[i].Content != wmsgs[i].Content {
+				t.Errorf("%s[%d] = %+v, want %+v", key, i, gmsgs[i], wmsgs[i])
+			}
+		}
+	}
+}
+
+func TestAgentImportConflict(t *testing.T) {
+	def := config.AgentDef{ID: "trader-1", Name: "Trader", Type: "trader", Model: "anthropic/claude-sonnet"}
+	src := newAgentDevice(t, def)
+	seedAgent(t, src, "trader-1")
+	bundlePath := exportTestAgent(t, src, "trader-1")
+
+	dst := newAgentDevice(t, config.AgentDef{ID: "trader-1", Name: "Other", Type: "monitor", Model: "openai/gpt-4o"})
+	if code := AgentCommand([]string{"import", bundlePath}, dst); code == 0 {
+		t.Fatal("import over an existing agent should fail without --force")
+	}
+	cfg, _ := config.Load(dst)
+	if cfg.Agents[0].Name != "Other" {
+		t.Errorf("existing agent changed by a failed import: %+v", cfg.Agents[0])
+	}
+
+	if code := AgentCommand([]string{"import", bundlePath, "--as", "trader-2"}, dst); code != 0 {
+		t.Fatalf("import --as exit code = %d", code)
+	}
+	st := openTestAgentStores(t, dst)
+	if _, ok := st.configAgent("trader-2"); !ok {
+		t.Error("trader-2 not added to config")
+	}
+	if a, err := st.registry.Get("trader-2"); err != nil || a.Def.ID != "trader-2" {
+		t.Errorf("trader-2 registry record = %v, %v", a, err)
+	}
+	if msgs := st.memory.Get("trader-2:research").GetMessages(); len(msgs) != 1 {
+		t.Errorf("renamed conversation has %d messages, want 1", len(msgs))
+	}
+	st.close()
+
+	if code := AgentCommand([]string{"import", "--force", bundlePath}, dst); code != 0 {
+		t.Fatalf("import --force exit code = %d", code)
+	}
+	cfg, _ = config.Load(dst)
+	if len(cfg.Agents) != 2 || cfg.Agents[0].Name != "Trader" {
+		t.Errorf("agents after --force = %+v", cfg.Agents)
+	}
+}
+
+func TestAgentImportRejectsNewerFormat(t *testing.T) {
+	dst := newAgentDevice(t)
+	st := openTestAgentStores(t, dst)
+
+	for _, format := range []int{0, agentBundleFormat + 1} {
+		b := &AgentBundle{Format: format}
+		b.Agent.ID = "trader-1"
+		_, _, err := importAgent(st, dst, b, agentImportOptions{})
+		if err == nil || !strings.Contains(err.Error(), "bundle format") {
+			t.Errorf("format %d: err = %v, want unsupported format", format, err)
+		}
+	}
+}
+
+func TestAgentExportBundle(t *testing.T) {
+	src := newAgentDevice(t, config.AgentDef{ID: "trader-1", Type: "trader", Model: "anthropic/claude-sonnet"})
+	seedAgent(t, src, "trader-1")
+	path := exportTestAgent(t, src, "trader-1")
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("bundle mode = %o, want 600", perm)
+	}
+	data, _ := os.ReadFile(path)
+	var b AgentBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		t.Fatalf("bundle is not JSON: %v", err)
+	}
+	if b.Format != agentBundleFormat || b.Agent.ID != "trader-1" || b.Genome == nil || b.Strategy == nil || len(b.Memory) != 2 {
+		t.Errorf("bundle = format %d, agent %q, genome %v, strategy %v, %d conversations",
+			b.Format, b.Agent.ID, b.Genome != nil, b.Strategy != nil, len(b.Memory))
+	}
+
+	if code := AgentCommand([]string{"export", "missing", path}, src); code == 0 {
+		t.Error("exporting an unknown agent should fail")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,6 +13,68 @@ import (
 	"github.com/clawinfra/evoclaw/internal/onchain"
 )
 
+// ChainInfo describes a configured chain in the --json output of the
+// chain subcommands.
+type ChainInfo struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Name     string `json:"name,omitempty"`
+	RPC      string `json:"rpc"`
+	ChainID  int64  `json:"chain_id,omitempty"`
+	Wallet   string `json:"wallet,omitempty"`
+	Explorer string `json:"explorer,omitempty"`
+	Enabled  bool   `json:"enabled"`
+	// Health is nil when the chain is disabled or the check was skipped.
+	Health *ChainHealth `json:"health,omitempty"`
+}
+
+// ChainHealth is the result of a live connectivity check.
+type ChainHealth struct {
+	Connected   bool   `json:"connected"`
+	BlockHeight uint64 `json:"block_height,omitempty"`
+	LatencyMs   int64  `json:"latency_ms,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ChainListResult is the --json output of 'chain list'.
+type ChainListResult struct {
+	Chains []ChainInfo `json:"chains"`
+}
+
+// ChainRemoveResult is the --json output of 'chain remove'.
+type ChainRemoveResult struct {
+	Removed string `json:"removed"`
+}
+
+func newChainInfo(id string, c config.ChainConfig) ChainInfo {
+	return ChainInfo{
+		ID:       id,
+		Type:     c.Type,
+		Name:     c.Name,
+		RPC:      c.RPCURL,
+		ChainID:  c.ChainID,
+		Wallet:   c.Wallet,
+		Explorer: c.Explorer,
+		Enabled:  c.Enabled,
+	}
+}
+
+// checkChainHealth runs a live connectivity check against the chain.
+func checkChainHealth(ctx context.Context, id string, c config.ChainConfig) *ChainHealth {
+	hr := onchain.CheckHealth(ctx, onchain.ChainConfig{
+		ID:   id,
+		Type: c.Type,
+		Name: c.Name,
+		RPC:  c.RPCURL,
+	})
+	return &ChainHealth{
+		Connected:   hr.Connected,
+		BlockHeight: hr.BlockHeight,
+		LatencyMs:   hr.Latency.Milliseconds(),
+		Error:       hr.Error,
+	}
+}
+
 // ChainCommand handles the 'evoclaw chain' subcommands
 func ChainCommand(args []string, configPath string) int {
 	if len(args) == 0 {
@@ -50,6 +113,8 @@ Subcommands:
   remove <chain-id>   Remove a chain configuration
   status <chain-id>   Show detailed status (block height, latency, etc.)
 
+Every subcommand accepts --json to print its result as JSON.
+
 Examples:
   # Add BSC testnet (preset with minimal flags)
   evoclaw chain add bsc-testnet --wallet 0x2331...
@@ -76,26 +141,11 @@ Supported presets:
 }
 
 func chainAdd(args []string, configPath string) int {
-	// Find chain-id (first non-flag argument) and extract it
 	var chainIDStr string
-	var parsedArgs []string
-	
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if strings.HasPrefix(arg, "-") {
-			// This is a flag, keep it
-			parsedArgs = append(parsedArgs, arg)
-			// If next arg doesn't start with -, it's the flag value
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-				i++
-				parsedArgs = append(parsedArgs, args[i])
-			}
-		} else {
-			// First non-flag arg is chain-id
-			if chainIDStr == "" {
-				chainIDStr = arg
-			}
-		}
+	positional, parsedArgs := splitArgs(args, "json")
+	if len(positional) > 0 {
+		// First non-flag arg is chain-id
+		chainIDStr = positional[0]
 	}
 
 	if chainIDStr == "" {
@@ -111,6 +161,7 @@ func chainAdd(args []string, configPath string) int {
 	wallet := fs.String("wallet", "", "Wallet address")
 	chainID := fs.Int64("chain-id", 0, "EVM chain ID (auto-detected for presets)")
 	explorer := fs.String("explorer", "", "Block explorer URL")
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
 
 	if err := fs.Parse(parsedArgs); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
@@ -194,6 +245,10 @@ func chainAdd(args []string, configPath string) int {
 		return 1
 	}
 
+	if *asJSON {
+		return printJSON(newChainInfo(chainIDStr, chainCfg))
+	}
+
 	// Success message
 	fmt.Printf("✅ Chain added: %s\n", chainIDStr)
 	if isPreset {
@@ -215,6 +270,7 @@ func chainAdd(args []string, configPath string) int {
 func chainList(args []string, configPath string) int {
 	fs := flag.NewFlagSet("chain list", flag.ContinueOnError)
 	noCheck := fs.Bool("no-check", false, "Skip live connectivity check")
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
 	if err := fs.Parse(args); err != nil {
 		return 1
 	}
@@ -229,6 +285,26 @@ func chainList(args []string, configPath string) int {
 	// Migrate old config if needed
 	cfg.MigrateOnChainConfig()
 
+	ids := make([]string, 0, len(cfg.Chains))
+	for chainID := range cfg.Chains {
+		ids = append(ids, chainID)
+	}
+	sort.Strings(ids)
+
+	ctx := context.Background()
+	result := ChainListResult{Chains: make([]ChainInfo, 0, len(ids))}
+	for _, chainID := range ids {
+		chainCfg := cfg.Chains[chainID]
+		info := newChainInfo(chainID, chainCfg)
+		if chainCfg.Enabled && !*noCheck {
+			info.Health = checkChainHealth(ctx, chainID, chainCfg)
+		}
+		result.Chains = append(result.Chains, info)
+	}
+	if *asJSON {
+		return printJSON(result)
+	}
+
 	if len(cfg.Chains) == 0 {
 		fmt.Println("No chains configured.")
 		fmt.Println("Add a chain with: evoclaw chain add <chain-id>")
@@ -240,36 +316,28 @@ func chainList(args []string, configPath string) int {
 	fmt.Printf("  %-20s %-12s %-30s %s\n", "CHAIN ID", "TYPE", "NAME", "STATUS")
 	fmt.Printf("  %-20s %-12s %-30s %s\n", strings.Repeat("-", 20), strings.Repeat("-", 12), strings.Repeat("-", 30), strings.Repeat("-", 16))
 
-	ctx := context.Background()
-	for chainID, chainCfg := range cfg.Chains {
-		if !chainCfg.Enabled {
-			fmt.Printf("  %-20s %-12s %-30s %s\n", chainID, strings.ToUpper(chainCfg.Type), chainCfg.Name, "❌ disabled")
+	for _, info := range result.Chains {
+		if !info.Enabled {
+			fmt.Printf("  %-20s %-12s %-30s %s\n", info.ID, strings.ToUpper(info.Type), info.Name, "❌ disabled")
 			continue
 		}
 
 		connStatus := "⏭  skipped"
-		if !*noCheck {
-			occ := onchain.ChainConfig{
-				ID:   chainID,
-				Type: chainCfg.Type,
-				Name: chainCfg.Name,
-				RPC:  chainCfg.RPCURL,
-			}
-			hr := onchain.CheckHealth(ctx, occ)
-			if hr.Connected {
-				connStatus = fmt.Sprintf("✅ connected (block %d)", hr.BlockHeight)
+		if h := info.Health; h != nil {
+			if h.Connected {
+				connStatus = fmt.Sprintf("✅ connected (block %d)", h.BlockHeight)
 			} else {
-				connStatus = fmt.Sprintf("🔴 disconnected: %s", hr.Error)
+				connStatus = fmt.Sprintf("🔴 disconnected: %s", h.Error)
 			}
 		}
 
-		typeInfo := strings.ToUpper(chainCfg.Type)
-		name := chainCfg.Name
+		typeInfo := strings.ToUpper(info.Type)
+		name := info.Name
 		if name == "" {
-			name = chainID
+			name = info.ID
 		}
 
-		fmt.Printf("  %-20s %-12s %-30s %s\n", chainID, typeInfo, name, connStatus)
+		fmt.Printf("  %-20s %-12s %-30s %s\n", info.ID, typeInfo, name, connStatus)
 	}
 
 	fmt.Println()
@@ -279,12 +347,18 @@ func chainList(args []string, configPath string) int {
 }
 
 func chainStatus(args []string, configPath string) int {
-	if len(args) < 1 {
+	positional, flagArgs := splitArgs(args, "json")
+	if len(positional) < 1 {
 		fmt.Fprintln(os.Stderr, "Error: chain-id required")
 		fmt.Fprintln(os.Stderr, "Usage: evoclaw chain status <chain-id>")
 		return 1
 	}
-	chainID := args[0]
+	chainID := positional[0]
+	fs := flag.NewFlagSet("chain status", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
+	if err := fs.Parse(flagArgs); err != nil {
+		return 1
+	}
 
 	cfg, err := config.Load(configPath)
 	if err != nil {
@@ -298,6 +372,12 @@ func chainStatus(args []string, configPath string) int {
 		return 1
 	}
 
+	if *asJSON {
+		info := newChainInfo(chainID, chainCfg)
+		info.Health = checkChainHealth(context.Background(), chainID, chainCfg)
+		return printJSON(info)
+	}
+
 	occ := onchain.ChainConfig{
 		ID:   chainID,
 		Type: chainCfg.Type,
@@ -328,13 +408,18 @@ func chainStatus(args []string, configPath string) int {
 }
 
 func chainRemove(args []string, configPath string) int {
-	if len(args) < 1 {
+	positional, flagArgs := splitArgs(args, "json")
+	if len(positional) < 1 {
 		fmt.Fprintln(os.Stderr, "Error: chain-id required")
 		fmt.Fprintln(os.Stderr, "Usage: evoclaw chain remove <chain-id>")
 		return 1
 	}
-
-	chainID := args[0]
+	chainID := positional[0]
+	fs := flag.NewFlagSet("chain remove", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
+	if err := fs.Parse(flagArgs); err != nil {
+		return 1
+	}
 
 	// Load config
 	cfg, err := config.Load(configPath)
@@ -355,6 +440,9 @@ func chainRemove(args []string, configPath string) int {
 		return 1
 	}
 
+	if *asJSON {
+		return printJSON(ChainRemoveResult{Removed: chainID})
+	}
 	fmt.Printf("✅ Chain removed: %s\n", chainID)
 	return 0
 }
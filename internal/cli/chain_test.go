@@ -1,6 +1,10 @@
 package cli
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -342,3 +346,119 @@ func TestChainListNoCheck(t *testing.T) {
 		t.Errorf("Expected exit code 0, got %d", exitCode)
 	}
 }
+
+// captureStdout returns what fn writes to stdout, and its exit code.
+func captureStdout(t *testing.T, fn func() int) ([]byte, int) {
+	t.Helper()
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	code := fn()
+	os.Stdout = oldStdout
+	_ = w.Close()
+	out := <-done
+	_ = r.Close()
+	return out, code
+}
+
+func TestChainCommandJSON(t *testing.T) {
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x2a"}`))
+	}))
+	defer rpc.Close()
+
+	cfgPath := filepath.Join(t.TempDir(), "evoclaw.json")
+	cfg := config.DefaultConfig()
+	cfg.AddChain("zeta", config.ChainConfig{Enabled: false, Type: "solana", RPCURL: "https://zeta.example.com"})
+	if err := cfg.Save(cfgPath); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	out, code := captureStdout(t, func() int {
+		return ChainCommand([]string{"add", "--json", "local", "--type", "evm", "--rpc", rpc.URL, "--chain-id", "31337"}, cfgPath)
+	})
+	if code != 0 {
+		t.Fatalf("add: exit code %d", code)
+	}
+	var added ChainInfo
+	if err := json.Unmarshal(out, &added); err != nil {
+		t.Fatalf("add: invalid JSON %q: %v", out, err)
+	}
+	if added.ID != "local" || added.ChainID != 31337 || added.RPC != rpc.URL || !added.Enabled {
+		t.Errorf("add: got %+v", added)
+	}
+
+	out, code = captureStdout(t, func() int { return ChainCommand([]string{"list", "--json"}, cfgPath) })
+	if code != 0 {
+		t.Fatalf("list: exit code %d", code)
+	}
+	var list ChainListResult
+	if err := json.Unmarshal(out, &list); err != nil {
+		t.Fatalf("list: invalid JSON %q: %v", out, err)
+	}
+	var ids []string
+	for _, c := range list.Chains {
+		ids = append(ids, c.ID)
+		if c.ID == "local" && (c.Health == nil || !c.Health.Connected || c.Health.BlockHeight != 42) {
+			t.Errorf("list: local health = %+v, want connected at block 42", c.Health)
+		}
+		if c.ID == "zeta" && c.Health != nil {
+			t.Error("list: a disabled chain should not be checked")
+		}
+	}
+	if strings.Join(ids, ",") != "local,zeta" {
+		t.Errorf("list: chains %v, want [local zeta] sorted by ID", ids)
+	}
+
+	out, code = captureStdout(t, func() int { return ChainCommand([]string{"status", "local", "--json"}, cfgPath) })
+	if code != 0 {
+		t.Fatalf("status: exit code %d", code)
+	}
+	var status ChainInfo
+	if err := json.Unmarshal(out, &status); err != nil {
+		t.Fatalf("status: invalid JSON %q: %v", out, err)
+	}
+	if status.Health == nil || !status.Health.Connected {
+		t.Errorf("status: health = %+v", status.Health)
+	}
+
+	out, code = captureStdout(t, func() int { return ChainCommand([]string{"remove", "local", "--json"}, cfgPath) })
+	if code != 0 {
+		t.Fatalf("remove: exit code %d", code)
+	}
+	var removed ChainRemoveResult
+	if err := json.Unmarshal(out, &removed); err != nil || removed.Removed != "local" {
+		t.Errorf("remove: output %q: %v", out, err)
+	}
+}
+
+func TestChainListJSONEmpty(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "evoclaw.json")
+	if err := config.DefaultConfig().Save(cfgPath); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	out, code := captureStdout(t, func() int { return ChainCommand([]string{"list", "--json"}, cfgPath) })
+	if code != 0 {
+		t.Fatalf("exit code %d", code)
+	}
+	if strings.TrimSpace(string(out)) != `{
+  "chains": []
+}` {
+		t.Errorf("output = %q, want an empty chains list", out)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	pos, flags := splitArgs([]string{"--json", "bsc", "--wallet", "0x1", "extra", "--rpc=https://x"}, "json")
+	if strings.Join(pos, " ") != "bsc extra" || strings.Join(flags, " ") != "--json --wallet 0x1 --rpc=https://x" {
+		t.Errorf("got %v %v", pos, flags)
+	}
+}
@@ -40,6 +40,33 @@ example agent definitions, and recommended directory structure.`,
 			"evoclaw init --dir /opt/evoclaw",
 		},
 	},
+	{
+		Name:  "validate",
+		Args:  "[config-file]",
+		Short: "Check a config file without starting EvoClaw",
+		Long: `Load and validate a config file, then construct (but do not start)
+its providers and channels. Prints a pass/fail report and exits non-zero
+on failure, which makes it suitable for CI and deploy pipelines.`,
+		Examples: []string{
+			"evoclaw validate",
+			"evoclaw validate /etc/evoclaw/evoclaw.yaml",
+			"evoclaw --config deploy/evoclaw.toml validate",
+		},
+	},
+	{
+		Name:  "doctor",
+		Args:  "[--timeout 5s] [--no-color] [config-file]",
+		Short: "Diagnose a deployment's config and connectivity",
+		Long: `Validate the config, then check that every provider, the MQTT broker,
+Turso, the data directory and each enabled chain RPC is reachable.
+Checks run in parallel, each bounded by --timeout. Prints a colored
+pass/warn/fail report and exits non-zero if any check fails.`,
+		Examples: []string{
+			"evoclaw doctor",
+			"evoclaw doctor --timeout 10s /etc/evoclaw/evoclaw.yaml",
+			"evoclaw --config deploy/evoclaw.toml doctor --no-color",
+		},
+	},
 	{
 		Name:  "memory",
 		Args:  "<store|retrieve|consolidate|status>",
@@ -129,6 +156,23 @@ Subcommands:
 			"evoclaw chain balance --chain bsc --address 0xABC...",
 		},
 	},
+	{
+		Name:  "agent",
+		Args:  "<export|import>",
+		Short: "Move an agent between devices",
+		Long: `Export an agent's definition, genome, strategy, conversation memory
+and metrics to a bundle file, and import it on another device. Run
+while EvoClaw is stopped.
+
+Subcommands:
+  export  Write an agent to a bundle
+  import  Recreate an agent from a bundle (--as <id>, --force)`,
+		Examples: []string{
+			"evoclaw agent export trader-1 trader-1.evoagent.json",
+			"evoclaw agent import trader-1.evoagent.json",
+			"evoclaw agent import trader-1.evoagent.json --as trader-2",
+		},
+	},
 	{
 		Name:  "gateway",
 		Args:  "<start|stop|restart|status>",
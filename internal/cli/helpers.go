@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/clawinfra/evoclaw/internal/config"
 )
@@ -24,3 +26,42 @@ func getLogger() *slog.Logger {
 		Level: slog.LevelInfo,
 	}))
 }
+
+// printJSON writes v to stdout as indented JSON, for --json output.
+func printJSON(v interface{}) int {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding output: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// splitArgs separates positional arguments from flags in args, so flags
+// may come before, between or after them. Flags named in boolFlags take
+// no value; any other flag not written as --name=value takes the next
+// argument as its value.
+func splitArgs(args []string, boolFlags ...string) (positional, flags []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+			continue
+		}
+		flags = append(flags, arg)
+		name := strings.TrimLeft(arg, "-")
+		takesValue := !strings.Contains(name, "=")
+		for _, b := range boolFlags {
+			if name == b {
+				takesValue = false
+			}
+		}
+		// If next arg doesn't start with -, it's the flag value
+		if takesValue && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+	return positional, flags
+}
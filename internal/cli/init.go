@@ -19,6 +19,7 @@ func InitCommand(args []string) int {
 	agentName := fs.String("name", "", "Agent name")
 	skipChain := fs.Bool("skip-chain", false, "Skip ClawChain registration")
 	outputPath := fs.String("output", "evoclaw.json", "Output config file path")
+	template := fs.String("template", "", "Agent template: "+strings.Join(config.AgentTemplateNames(), ", "))
 
 	fs.Usage = func() {
 		fmt.Println(`Usage: evoclaw init [options]
@@ -35,6 +36,9 @@ Examples:
   # Scripted setup
   evoclaw init --non-interactive --provider anthropic --key sk-ant-... --name my-agent
 
+  # Start from a built-in agent template
+  evoclaw init --template trader
+
   # Skip chain registration
   evoclaw init --skip-chain`)
 	}
@@ -43,6 +47,13 @@ Examples:
 		return 1
 	}
 
+	if *template != "" {
+		if _, err := config.AgentFromTemplate(*template, config.AgentDef{}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
 	// Check if config already exists
 	if _, err := os.Stat(*outputPath); err == nil {
 		fmt.Printf("⚠️  Config file %s already exists. Overwrite? [y/N]: ", *outputPath)
@@ -76,6 +87,13 @@ Examples:
 		}
 	}
 
+	if *template != "" {
+		if err := applyTemplate(cfg, *template); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
 	if err := cfg.Save(*outputPath); err != nil {
 		fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
 		return 1
@@ -320,3 +338,22 @@ func buildConfig(provider, apiKey, agentName string, enableTelegram, enableMQTT
 
 	return cfg
 }
+
+// applyTemplate replaces the config's first agent with the named template,
+// keeping the agent's ID, name and model.
+func applyTemplate(cfg *config.Config, name string) error {
+	var base config.AgentDef
+	if len(cfg.Agents) > 0 {
+		base = config.AgentDef{ID: cfg.Agents[0].ID, Name: cfg.Agents[0].Name, Model: cfg.Agents[0].Model}
+	}
+	def, err := config.AgentFromTemplate(name, base)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Agents) == 0 {
+		cfg.Agents = []config.AgentDef{def}
+	} else {
+		cfg.Agents[0] = def
+	}
+	return nil
+}
@@ -76,3 +76,21 @@ func TestBuildConfigWithoutMQTT(t *testing.T) {
 		t.Errorf("MQTT port = %d, want 0 when disabled", cfg.MQTT.Port)
 	}
 }
+
+func TestApplyTemplate(t *testing.T) {
+	cfg := buildConfig("ollama", "", "my-trader", false, false, true)
+	if err := applyTemplate(cfg, "trader"); err != nil {
+		t.Fatalf("applyTemplate: %v", err)
+	}
+	agent := cfg.Agents[0]
+	if agent.Type != "trader" || agent.Name != "my-trader" || agent.Model != "ollama/llama3.2:3b" || agent.Genome == nil {
+		t.Errorf("unexpected agent: %+v", agent)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("templated config invalid: %v", err)
+	}
+
+	if err := applyTemplate(cfg, "nope"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
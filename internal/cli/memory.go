@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -11,6 +10,25 @@ import (
 	"github.com/clawinfra/evoclaw/internal/memory"
 )
 
+// MemoryConsolidateResult is the --json output of 'memory consolidate'.
+type MemoryConsolidateResult struct {
+	Mode       string `json:"mode"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// MemoryStoreResult is the --json output of 'memory store'.
+type MemoryStoreResult struct {
+	ID       string `json:"id"`
+	Category string `json:"category"`
+	Tier     string `json:"tier"`
+}
+
+// MemoryRetrieveResult is the --json output of 'memory retrieve'.
+type MemoryRetrieveResult struct {
+	Query   string                `json:"query"`
+	Results []*memory.MemoryEntry `json:"results"`
+}
+
 // MemoryCommand handles the 'evoclaw memory' subcommands
 func MemoryCommand(args []string, configPath string) int {
 	if len(args) == 0 {
@@ -51,6 +69,8 @@ Subcommands:
   retrieve --query "search term"              Search memory
   status                                      Show memory system status
 
+Every subcommand accepts --json to print its result as JSON.
+
 Examples:
   # Quick consolidation (warm eviction)
   evoclaw memory consolidate --mode quick
@@ -69,6 +89,7 @@ Examples:
 func memoryConsolidate(args []string, configPath string) int {
 	fs := flag.NewFlagSet("consolidate", flag.ExitOnError)
 	mode := fs.String("mode", "quick", "Consolidation mode: quick, daily, monthly")
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		return 1
@@ -86,19 +107,25 @@ func memoryConsolidate(args []string, configPath string) int {
 
 	switch *mode {
 	case "quick":
-		fmt.Println("Running quick consolidation (warm eviction)...")
+		if !*asJSON {
+			fmt.Println("Running quick consolidation (warm eviction)...")
+		}
 		if consolidator := mgr.GetConsolidator(); consolidator != nil {
 			consolidator.TriggerWarmEviction(ctx)
 		}
 
 	case "daily":
-		fmt.Println("Running daily consolidation (tree pruning)...")
+		if !*asJSON {
+			fmt.Println("Running daily consolidation (tree pruning)...")
+		}
 		if consolidator := mgr.GetConsolidator(); consolidator != nil {
 			consolidator.TriggerTreePrune()
 		}
 
 	case "monthly":
-		fmt.Println("Running monthly consolidation (cold cleanup + tree rebuild)...")
+		if !*asJSON {
+			fmt.Println("Running monthly consolidation (cold cleanup + tree rebuild)...")
+		}
 		if consolidator := mgr.GetConsolidator(); consolidator != nil {
 			consolidator.TriggerColdCleanup(ctx)
 			// Tree rebuild not yet fully implemented
@@ -109,7 +136,11 @@ func memoryConsolidate(args []string, configPath string) int {
 		return 1
 	}
 
-	fmt.Printf("✓ Consolidation complete (%s)\n", time.Since(start).Round(time.Millisecond))
+	elapsed := time.Since(start)
+	if *asJSON {
+		return printJSON(MemoryConsolidateResult{Mode: *mode, DurationMs: elapsed.Milliseconds()})
+	}
+	fmt.Printf("✓ Consolidation complete (%s)\n", elapsed.Round(time.Millisecond))
 	return 0
 }
 
@@ -117,6 +148,7 @@ func memoryStore(args []string, configPath string) int {
 	fs := flag.NewFlagSet("store", flag.ExitOnError)
 	text := fs.String("text", "", "Memory text (required)")
 	category := fs.String("category", "", "Memory category (required)")
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		return 1
@@ -149,6 +181,9 @@ func memoryStore(args []string, configPath string) int {
 		return 1
 	}
 
+	if *asJSON {
+		return printJSON(MemoryStoreResult{ID: entry.ID, Category: entry.Category, Tier: entry.Tier})
+	}
 	fmt.Printf("✓ Stored memory in category: %s\n", *category)
 	return 0
 }
@@ -157,6 +192,7 @@ func memoryRetrieve(args []string, configPath string) int {
 	fs := flag.NewFlagSet("retrieve", flag.ExitOnError)
 	query := fs.String("query", "", "Search query (required)")
 	limit := fs.Int("limit", 5, "Maximum results")
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		return 1
@@ -182,6 +218,12 @@ func memoryRetrieve(args []string, configPath string) int {
 		return 1
 	}
 
+	if *asJSON {
+		if results == nil {
+			results = []*memory.MemoryEntry{}
+		}
+		return printJSON(MemoryRetrieveResult{Query: *query, Results: results})
+	}
 	if len(results) == 0 {
 		fmt.Println("No results found")
 		return 0
@@ -200,6 +242,15 @@ func memoryRetrieve(args []string, configPath string) int {
 }
 
 func memoryStatus(args []string, configPath string) int {
+	// The status is always printed as JSON; --json is accepted so every
+	// subcommand takes it.
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	_ = fs.Bool("json", false, "Print the result as JSON")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
 	mgr, err := getMemoryManager(configPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -215,15 +266,7 @@ func memoryStatus(args []string, configPath string) int {
 		return 1
 	}
 
-	// Pretty print JSON
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	if err := enc.Encode(status); err != nil {
-		fmt.Fprintf(os.Stderr, "Error encoding status: %v\n", err)
-		return 1
-	}
-
-	return 0
+	return printJSON(status)
 }
 
 func getMemoryManager(configPath string) (*memory.Manager, error) {
@@ -244,6 +287,7 @@ func getMemoryManager(configPath string) (*memory.Manager, error) {
 	memCfg.TreeMaxNodes = cfg.Memory.Tree.MaxNodes
 	memCfg.TreeMaxDepth = cfg.Memory.Tree.MaxDepth
 	memCfg.WarmMaxKB = cfg.Memory.Warm.MaxSizeKb
+	memCfg.WarmForceConsolidateKB = cfg.Memory.Warm.ForceConsolidateKb
 	memCfg.HalfLifeDays = cfg.Memory.Scoring.HalfLifeDays
 
 	// Get agent ID from first agent in config
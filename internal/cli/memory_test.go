@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/memory"
+)
+
+func TestMemoryResultsJSON(t *testing.T) {
+	for name, tt := range map[string]struct {
+		result interface{}
+		fields []string
+	}{
+		"consolidate": {MemoryConsolidateResult{Mode: "quick", DurationMs: 12}, []string{"mode", "duration_ms"}},
+		"store":       {MemoryStoreResult{ID: "cli-1", Category: "prefs", Tier: "warm"}, []string{"id", "category", "tier"}},
+		"retrieve": {MemoryRetrieveResult{Query: "dark", Results: []*memory.MemoryEntry{
+			{ID: "cli-1", Text: "User prefers dark mode", Category: "prefs", Tier: "warm", CreatedAt: time.Unix(0, 0)},
+		}}, []string{"query", "results"}},
+	} {
+		out, code := captureStdout(t, func() int { return printJSON(tt.result) })
+		if code != 0 {
+			t.Fatalf("%s: exit code %d", name, code)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal(out, &got); err != nil {
+			t.Fatalf("%s: invalid JSON %q: %v", name, out, err)
+		}
+		for _, f := range tt.fields {
+			if _, ok := got[f]; !ok {
+				t.Errorf("%s: output %s is missing %q", name, out, f)
+			}
+		}
+	}
+}
+
+func TestMemoryCommandAcceptsJSONFlag(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "evoclaw.json")
+	if err := config.DefaultConfig().Save(cfgPath); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() {
+		os.Stderr = oldStderr
+		_ = r.Close()
+	}()
+
+	// Memory is disabled in the default config, so every subcommand fails
+	// after parsing its flags, without printing anything to stdout.
+	for _, args := range [][]string{
+		{"consolidate", "--json", "--mode", "daily"},
+		{"store", "--json", "--text", "fact", "--category", "c"},
+		{"retrieve", "--json", "--query", "fact"},
+		{"status", "--json"},
+	} {
+		out, code := captureStdout(t, func() int { return MemoryCommand(args, cfgPath) })
+		if code != 1 || len(out) != 0 {
+			t.Errorf("%s: exit code %d, stdout %q", strings.Join(args, " "), code, out)
+		}
+	}
+	_ = w.Close()
+}
@@ -0,0 +1,25 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeOnlyMovesWhenTold(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	if !f.Now().Equal(start) {
+		t.Fatalf("Now = %v, want %v", f.Now(), start)
+	}
+
+	f.Advance(90 * time.Minute)
+	if got := Since(f, start); got != 90*time.Minute {
+		t.Errorf("Since = %v, want 90m", got)
+	}
+
+	later := start.Add(48 * time.Hour)
+	f.Set(later)
+	if !f.Now().Equal(later) {
+		t.Errorf("Now after Set = %v, want %v", f.Now(), later)
+	}
+}
@@ -11,6 +11,7 @@ package cloudsync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -18,6 +19,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrPaused is returned by sync operations while the manager is paused.
+var ErrPaused = errors.New("cloud sync paused")
+
+// ErrDisabled is returned by reads and syncs when cloud sync is not enabled.
+var ErrDisabled = errors.New("cloud sync disabled")
+
 // Manager is the main interface for cloud sync operations
 type Manager struct {
 	client   *Client
@@ -68,6 +75,12 @@ func NewManager(cfg config.CloudSyncConfig, logger *slog.Logger) (*Manager, erro
 	if cfg.MaxOfflineQueueSize <= 0 {
 		cfg.MaxOfflineQueueSize = 1000
 	}
+	if cfg.SnapshotIntervalSeconds == 0 {
+		cfg.SnapshotIntervalSeconds = 900
+	}
+	if cfg.SyncDebounceMs <= 0 {
+		cfg.SyncDebounceMs = 2000
+	}
 
 	client := NewClient(cfg.DatabaseURL, cfg.AuthToken, logger)
 	
@@ -81,6 +94,8 @@ func NewManager(cfg config.CloudSyncConfig, logger *slog.Logger) (*Manager, erro
 		FullSyncIntervalHours:    cfg.FullSyncIntervalHours,
 		FullSyncRequireWiFi:      cfg.FullSyncRequireWiFi,
 		MaxOfflineQueueSize:      cfg.MaxOfflineQueueSize,
+		SnapshotIntervalSeconds:  max(cfg.SnapshotIntervalSeconds, 0),
+		SyncDebounceMs:           cfg.SyncDebounceMs,
 	}
 
 	engine := NewSyncEngine(client, syncConfig, logger)
@@ -95,12 +110,18 @@ func NewManager(cfg config.CloudSyncConfig, logger *slog.Logger) (*Manager, erro
 	}, nil
 }
 
-// InitSchema creates all database tables
+// InitSchema creates all database tables. If Turso cannot be reached the
+// error is returned, and the engine starts disconnected and retries when it
+// reconnects; writes are queued meanwhile.
 func (m *Manager) InitSchema(ctx context.Context) error {
 	if !m.config.Enabled {
 		return nil
 	}
-	return m.client.InitSchema(ctx)
+	if err := m.client.InitSchema(ctx); err != nil {
+		m.engine.markSchemaPending(err)
+		return err
+	}
+	return nil
 }
 
 // Start begins background sync operations
@@ -112,6 +133,24 @@ func (m *Manager) Start(ctx context.Context) error {
 	return m.engine.Start(ctx)
 }
 
+// SetPaused suspends or resumes all writes to the cloud: sync calls fail
+// with ErrPaused and background heartbeats and queue replay are skipped.
+// Reads and restores are unaffected.
+func (m *Manager) SetPaused(paused bool) {
+	if m.engine != nil {
+		m.engine.SetPaused(paused)
+	}
+}
+
+// Status reports whether Turso is reachable, how many writes are queued
+// and when the last request succeeded.
+func (m *Manager) Status() Status {
+	if !m.config.Enabled || m.engine == nil {
+		return Status{}
+	}
+	return m.engine.Status()
+}
+
 // Stop gracefully shuts down sync operations
 func (m *Manager) Stop() error {
 	if !m.config.Enabled {
@@ -125,14 +164,42 @@ func (m *Manager) SyncCritical(ctx context.Context, memory *AgentMemory) error {
 	if !m.config.Enabled {
 		return nil
 	}
+	if m.engine.paused.Load() {
+		return ErrPaused
+	}
 	return m.engine.CriticalSync(ctx, memory)
 }
 
+// QueueCritical schedules a critical sync after a conversation. An agent's
+// conversations within the debounce window (cloudSync.syncDebounceMs) are
+// coalesced into one write of the latest state.
+func (m *Manager) QueueCritical(memory *AgentMemory) error {
+	if !m.config.Enabled {
+		return nil
+	}
+	if m.engine.paused.Load() {
+		return ErrPaused
+	}
+	m.engine.QueueCritical(memory)
+	return nil
+}
+
+// SetSnapshotSource sets where the periodic snapshot sync
+// (cloudSync.snapshotIntervalSeconds) reads every agent's state from.
+func (m *Manager) SetSnapshotSource(src SnapshotSource) {
+	if m.engine != nil {
+		m.engine.SetSnapshotSource(src)
+	}
+}
+
 // SyncWarm syncs recent events (hourly)
 func (m *Manager) SyncWarm(ctx context.Context, snapshot *MemorySnapshot) error {
 	if !m.config.Enabled {
 		return nil
 	}
+	if m.engine.paused.Load() {
+		return ErrPaused
+	}
 	return m.engine.WarmSync(ctx, snapshot)
 }
 
@@ -141,13 +208,16 @@ func (m *Manager) SyncFull(ctx context.Context, snapshot *MemorySnapshot) error
 	if !m.config.Enabled {
 		return nil
 	}
+	if m.engine.paused.Load() {
+		return ErrPaused
+	}
 	return m.engine.FullSync(ctx, snapshot)
 }
 
 // RestoreAgent pulls full agent state from cloud
 func (m *Manager) RestoreAgent(ctx context.Context, agentID string) (*AgentMemory, error) {
 	if !m.config.Enabled {
-		return nil, fmt.Errorf("cloud sync disabled")
+		return nil, ErrDisabled
 	}
 	return m.recovery.RestoreAgent(ctx, agentID)
 }
@@ -155,7 +225,7 @@ func (m *Manager) RestoreAgent(ctx context.Context, agentID string) (*AgentMemor
 // RestoreToDevice pairs a new device with an existing agent
 func (m *Manager) RestoreToDevice(ctx context.Context, agentID, deviceID, deviceKey string) (*AgentMemory, error) {
 	if !m.config.Enabled {
-		return nil, fmt.Errorf("cloud sync disabled")
+		return nil, ErrDisabled
 	}
 	return m.recovery.RestoreToDevice(ctx, agentID, deviceID, deviceKey)
 }
@@ -163,7 +233,7 @@ func (m *Manager) RestoreToDevice(ctx context.Context, agentID, deviceID, device
 // GetWarmMemory retrieves recent conversations
 func (m *Manager) GetWarmMemory(ctx context.Context, agentID string, limit int) ([]WarmMemoryEntry, error) {
 	if !m.config.Enabled {
-		return nil, fmt.Errorf("cloud sync disabled")
+		return nil, ErrDisabled
 	}
 	return m.recovery.GetWarmMemory(ctx, agentID, limit)
 }
@@ -171,7 +241,7 @@ func (m *Manager) GetWarmMemory(ctx context.Context, agentID string, limit int)
 // GetEvolutionHistory retrieves evolution log
 func (m *Manager) GetEvolutionHistory(ctx context.Context, agentID string, limit int) ([]EvolutionEntry, error) {
 	if !m.config.Enabled {
-		return nil, fmt.Errorf("cloud sync disabled")
+		return nil, ErrDisabled
 	}
 	return m.recovery.GetEvolutionHistory(ctx, agentID, limit)
 }
@@ -179,7 +249,7 @@ func (m *Manager) GetEvolutionHistory(ctx context.Context, agentID string, limit
 // GetActionHistory retrieves action log
 func (m *Manager) GetActionHistory(ctx context.Context, agentID string, limit int) ([]ActionEntry, error) {
 	if !m.config.Enabled {
-		return nil, fmt.Errorf("cloud sync disabled")
+		return nil, ErrDisabled
 	}
 	return m.recovery.GetActionHistory(ctx, agentID, limit)
 }
@@ -187,7 +257,7 @@ func (m *Manager) GetActionHistory(ctx context.Context, agentID string, limit in
 // MarkDeviceStolen marks a device as stolen
 func (m *Manager) MarkDeviceStolen(ctx context.Context, deviceID string) error {
 	if !m.config.Enabled {
-		return fmt.Errorf("cloud sync disabled")
+		return ErrDisabled
 	}
 	return m.recovery.MarkDeviceStolen(ctx, deviceID)
 }
@@ -195,11 +265,20 @@ func (m *Manager) MarkDeviceStolen(ctx context.Context, deviceID string) error {
 // ListDevices returns all devices for an agent
 func (m *Manager) ListDevices(ctx context.Context, agentID string) ([]DeviceInfo, error) {
 	if !m.config.Enabled {
-		return nil, fmt.Errorf("cloud sync disabled")
+		return nil, ErrDisabled
 	}
 	return m.recovery.ListDevices(ctx, agentID)
 }
 
+// Fleet reads every device in the cloud-sync database with its agents'
+// latest fitness and metrics.
+func (m *Manager) Fleet(ctx context.Context) (*Fleet, error) {
+	if !m.config.Enabled {
+		return nil, ErrDisabled
+	}
+	return m.recovery.Fleet(ctx)
+}
+
 // CleanupExpiredMemory deletes warm memory past expiration
 func (m *Manager) CleanupExpiredMemory(ctx context.Context) (int64, error) {
 	if !m.config.Enabled {
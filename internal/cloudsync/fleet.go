@@ -0,0 +1,134 @@
+package cloudsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Fleet is every device in the cloud-sync database with the agents synced
+// from it, so one orchestrator can report on devices it doesn't run.
+type Fleet struct {
+	Devices     []FleetDevice `json:"devices"`
+	DeviceCount int           `json:"device_count"`
+	AgentCount  int           `json:"agent_count"`
+	// AvgFitness averages the latest fitness of agents that have been
+	// evaluated; zero when none have.
+	AvgFitness float64 `json:"avg_fitness"`
+}
+
+// FleetDevice is a registered device and the agents synced from it.
+type FleetDevice struct {
+	DeviceInfo
+	Agents []FleetAgent `json:"agents"`
+}
+
+// FleetAgent is an agent with its latest evolution log entry. Fitness is
+// nil and Metrics empty until the agent has been evaluated.
+type FleetAgent struct {
+	AgentID     string                 `json:"agent_id"`
+	Name        string                 `json:"name"`
+	Model       string                 `json:"model"`
+	Status      string                 `json:"status"`
+	Fitness     *float64               `json:"fitness,omitempty"`
+	Metrics     map[string]interface{} `json:"metrics,omitempty"`
+	EvaluatedAt int64                  `json:"evaluated_at,omitempty"`
+}
+
+// fleetQuery lists devices with their agents (matched by device key) and
+// each agent's most recent evolution log entry.
+const fleetQuery = `SELECT d.device_id, d.device_name, d.device_type, d.last_heartbeat, d.last_sync, d.status, d.created_at,
+       a.agent_id, a.name, a.model, a.status,
+       e.fitness_score, e.metrics, e.timestamp
+FROM devices d
+LEFT JOIN agents a ON a.device_key = d.device_key
+LEFT JOIN evolution_log e ON e.id = (
+    SELECT id FROM evolution_log
+    WHERE agent_id = a.agent_id
+    ORDER BY timestamp DESC
+    LIMIT 1
+)
+ORDER BY d.last_heartbeat DESC, d.device_id, a.agent_id`
+
+// Fleet reads every device and its agents' latest fitness and metrics.
+func (r *RecoveryManager) Fleet(ctx context.Context) (*Fleet, error) {
+	resp, err := r.client.Query(ctx, fleetQuery)
+	if err != nil {
+		return nil, fmt.Errorf("query fleet: %w", err)
+	}
+
+	fleet := &Fleet{Devices: []FleetDevice{}}
+	index := make(map[string]int) // device ID -> position in fleet.Devices
+	var fitnessSum float64
+	var evaluated int
+	for _, row := range resp.Rows {
+		if len(row) < 14 {
+			return nil, fmt.Errorf("query fleet: expected 14 columns, got %d", len(row))
+		}
+		deviceID := safeString(row[0])
+		i, ok := index[deviceID]
+		if !ok {
+			i = len(fleet.Devices)
+			index[deviceID] = i
+			fleet.Devices = append(fleet.Devices, FleetDevice{
+				DeviceInfo: DeviceInfo{
+					DeviceID:      deviceID,
+					DeviceName:    safeString(row[1]),
+					DeviceType:    safeString(row[2]),
+					LastHeartbeat: safeInt64(row[3]),
+					LastSync:      safeInt64(row[4]),
+					Status:        safeString(row[5]),
+					CreatedAt:     safeInt64(row[6]),
+				},
+				Agents: []FleetAgent{},
+			})
+		}
+
+		if row[7] == nil {
+			continue // device with no synced agents
+		}
+		agent := FleetAgent{
+			AgentID:     safeString(row[7]),
+			Name:        safeString(row[8]),
+			Model:       safeString(row[9]),
+			Status:      safeString(row[10]),
+			EvaluatedAt: safeInt64(row[13]),
+		}
+		if f, ok := safeFloat(row[11]); ok {
+			agent.Fitness = &f
+			fitnessSum += f
+			evaluated++
+		}
+		if m := safeString(row[12]); m != "" {
+			_ = json.Unmarshal([]byte(m), &agent.Metrics)
+		}
+		fleet.Devices[i].Agents = append(fleet.Devices[i].Agents, agent)
+		fleet.AgentCount++
+	}
+
+	fleet.DeviceCount = len(fleet.Devices)
+	if evaluated > 0 {
+		fleet.AvgFitness = fitnessSum / float64(evaluated)
+	}
+	return fleet, nil
+}
+
+// safeFloat converts a numeric column, which Turso may return as a number
+// or a string, reporting false for NULL or anything unparsable.
+func safeFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// safeInt64 converts an integer column, returning 0 for NULL.
+func safeInt64(v interface{}) int64 {
+	f, _ := safeFloat(v)
+	return int64(f)
+}
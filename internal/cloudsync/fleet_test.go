@@ -0,0 +1,85 @@
+package cloudsync
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fleetRows is what Turso returns for fleetQuery: two devices with one
+// agent each (one never evaluated), a second agent on the first device, and
+// a device with no agents yet.
+var fleetRows = [][]interface{}{
+	{"dev-a", "Kitchen Pi", "hub", float64(300), float64(290), "active", float64(100),
+		"agent-1", "Trader", "gpt-4", "active", 0.8, `{"successRate":0.9}`, float64(280)},
+	{"dev-a", "Kitchen Pi", "hub", float64(300), float64(290), "active", float64(100),
+		"agent-2", "Monitor", "llama3", "active", "0.6", nil, "270"},
+	{"dev-b", "Phone", "phone", float64(200), nil, "offline", float64(150),
+		"agent-3", "Helper", "gpt-4o", "active", nil, nil, nil},
+	{"dev-c", nil, nil, nil, nil, "active", float64(180),
+		nil, nil, nil, nil, nil, nil, nil},
+}
+
+// newFleetTurso serves rows for any query, checking it is the fleet query.
+func newFleetTurso(t *testing.T, rows [][]interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req PipelineRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if len(req.Requests) != 1 || !strings.Contains(req.Requests[0].Statement.SQL, "FROM devices d") {
+			t.Errorf("unexpected query: %+v", req.Requests)
+		}
+		_ = json.NewEncoder(w).Encode(PipelineResponse{Results: []BatchResult{
+			{Type: "ok", Response: &QueryResponse{Rows: rows}},
+		}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFleetGroupsAgentsByDevice(t *testing.T) {
+	server := newFleetTurso(t, fleetRows)
+	recovery := NewRecoveryManager(NewClient(server.URL, "token", slog.Default()), slog.Default())
+
+	fleet, err := recovery.Fleet(context.Background())
+	if err != nil {
+		t.Fatalf("Fleet: %v", err)
+	}
+	if fleet.DeviceCount != 3 || fleet.AgentCount != 3 {
+		t.Fatalf("counts = %d devices, %d agents; want 3, 3", fleet.DeviceCount, fleet.AgentCount)
+	}
+	if fleet.AvgFitness < 0.699 || fleet.AvgFitness > 0.701 {
+		t.Errorf("avg fitness = %v, want 0.7 over the evaluated agents", fleet.AvgFitness)
+	}
+
+	kitchen := fleet.Devices[0]
+	if kitchen.DeviceID != "dev-a" || kitchen.LastHeartbeat != 300 || len(kitchen.Agents) != 2 {
+		t.Fatalf("first device = %+v", kitchen)
+	}
+	trader := kitchen.Agents[0]
+	if trader.Fitness == nil || *trader.Fitness != 0.8 || trader.Metrics["successRate"] != 0.9 || trader.EvaluatedAt != 280 {
+		t.Errorf("trader = %+v", trader)
+	}
+	if monitor := kitchen.Agents[1]; monitor.Fitness == nil || *monitor.Fitness != 0.6 || monitor.EvaluatedAt != 270 {
+		t.Errorf("string-typed numbers not parsed: %+v", monitor)
+	}
+	if helper := fleet.Devices[1].Agents[0]; helper.Fitness != nil || helper.Metrics != nil {
+		t.Errorf("unevaluated agent = %+v", helper)
+	}
+	if empty := fleet.Devices[2]; len(empty.Agents) != 0 || empty.Agents == nil {
+		t.Errorf("device without agents = %+v, want empty agent list", empty)
+	}
+}
+
+func TestFleetDisabled(t *testing.T) {
+	m := &Manager{}
+	if _, err := m.Fleet(context.Background()); err != ErrDisabled {
+		t.Errorf("err = %v, want ErrDisabled", err)
+	}
+}
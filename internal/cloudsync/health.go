@@ -0,0 +1,177 @@
+package cloudsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOffline is returned (wrapped) by sync operations queued because Turso
+// was unreachable at the time.
+var ErrOffline = errors.New("cloud sync offline")
+
+// Reconnect backoff: the first probe after a failure is made after
+// reconnectMinDelay, doubling up to reconnectMaxDelay while Turso stays
+// unreachable. While connected, the queue is checked every
+// reconnectMinDelay.
+const (
+	reconnectMinDelay = 5 * time.Second
+	reconnectMaxDelay = 5 * time.Minute
+)
+
+// Status describes the cloud sync connection for GET /api/cloudsync/status.
+type Status struct {
+	Enabled   bool `json:"enabled"`
+	Connected bool `json:"connected"`
+	Paused    bool `json:"paused"`
+	// QueueDepth is the number of writes waiting in the offline queue.
+	QueueDepth int `json:"queue_depth"`
+	// LastSuccess is when Turso last accepted a request.
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	// ConsecutiveFailures counts failed requests since the last success.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+}
+
+// connHealth tracks whether Turso is reachable. The HTTP API has no
+// long-lived connection, so "connected" means the last request succeeded;
+// the zero value is connected, so writes are attempted until one fails.
+type connHealth struct {
+	mu          sync.RWMutex
+	offline     bool
+	lastSuccess time.Time
+	lastError   string
+	lastErrorAt time.Time
+	failures    int
+}
+
+func (h *connHealth) isConnected() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return !h.offline
+}
+
+// recordResult updates the connection health after a Turso request. A
+// statement rejected by Turso still shows it is reachable.
+func (s *SyncEngine) recordResult(err error) {
+	if isRejected(err) {
+		err = nil
+	}
+	h := &s.health
+	h.mu.Lock()
+	wasConnected := !h.offline
+	if err == nil {
+		h.offline = false
+		h.lastSuccess = time.Now()
+		h.failures = 0
+	} else {
+		h.offline = true
+		h.lastError = err.Error()
+		h.lastErrorAt = time.Now()
+		h.failures++
+	}
+	h.mu.Unlock()
+
+	switch {
+	case wasConnected && err != nil:
+		s.logger.Warn("cloud sync connection lost", "error", err)
+	case !wasConnected && err == nil:
+		s.logger.Info("cloud sync connection restored", "queued", s.offlineQueue.Size())
+	}
+}
+
+// isRejected reports whether err is Turso refusing a statement, as opposed
+// to Turso being unreachable. Retrying a rejected write will not help.
+func isRejected(err error) bool {
+	var pe *PipelineError
+	return errors.As(err, &pe)
+}
+
+// reconnect probes Turso, first creating the schema if that failed at
+// startup, then re-registering the device.
+func (s *SyncEngine) reconnect(ctx context.Context) error {
+	if s.schemaPending.Load() {
+		err := s.client.InitSchema(ctx)
+		s.recordResult(err)
+		if err != nil {
+			return err
+		}
+		s.schemaPending.Store(false)
+	}
+	return s.registerDevice(ctx)
+}
+
+// markSchemaPending records that the schema could not be created, so the
+// engine starts disconnected and creates it on reconnect.
+func (s *SyncEngine) markSchemaPending(err error) {
+	s.schemaPending.Store(true)
+	s.recordResult(err)
+}
+
+// Status reports the connection health and offline queue depth.
+func (s *SyncEngine) Status() Status {
+	h := &s.health
+	h.mu.RLock()
+	st := Status{
+		Enabled:             s.config.Enabled,
+		Connected:           !h.offline,
+		LastError:           h.lastError,
+		ConsecutiveFailures: h.failures,
+	}
+	if !h.lastSuccess.IsZero() {
+		t := h.lastSuccess
+		st.LastSuccess = &t
+	}
+	if !h.lastErrorAt.IsZero() {
+		t := h.lastErrorAt
+		st.LastErrorAt = &t
+	}
+	h.mu.RUnlock()
+
+	st.Paused = s.paused.Load()
+	st.QueueDepth = s.offlineQueue.Size()
+	return st
+}
+
+// reconnectLoop re-establishes the connection after failures and feeds the
+// offline queue back to Turso once it is reachable. While disconnected it
+// probes with a heartbeat, backing off exponentially between attempts.
+func (s *SyncEngine) reconnectLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	delay := s.reconnectMin
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		delay = s.reconnectStep(ctx, delay)
+		timer.Reset(delay)
+	}
+}
+
+// reconnectStep runs one round of reconnectLoop and returns the delay until
+// the next.
+func (s *SyncEngine) reconnectStep(ctx context.Context, delay time.Duration) time.Duration {
+	if s.paused.Load() {
+		return s.reconnectMin
+	}
+	if !s.health.isConnected() {
+		if err := s.reconnect(ctx); err != nil {
+			return min(delay*2, s.reconnectMax)
+		}
+	}
+	if s.offlineQueue.Size() > 0 {
+		s.processOfflineQueue(ctx)
+	}
+	return s.reconnectMin
+}
@@ -0,0 +1,261 @@
+package cloudsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyTurso is a mock Turso server that returns 500 while failing is set
+// and records the statements of every request it accepts.
+type flakyTurso struct {
+	failing  atomic.Bool
+	requests atomic.Int32
+
+	mu       sync.Mutex
+	accepted [][]string // SQL of each accepted pipeline
+}
+
+func newFlakyTurso(t *testing.T) (*flakyTurso, *Client) {
+	t.Helper()
+	ft := &flakyTurso{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ft.requests.Add(1)
+		if ft.failing.Load() {
+			http.Error(w, "unavailable", http.StatusInternalServerError)
+			return
+		}
+		var req PipelineRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		sqls := make([]string, len(req.Requests))
+		results := make([]BatchResult, len(req.Requests))
+		for i, br := range req.Requests {
+			sqls[i] = br.Statement.SQL
+			results[i] = BatchResult{Type: "ok"}
+		}
+		ft.mu.Lock()
+		ft.accepted = append(ft.accepted, sqls)
+		ft.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(PipelineResponse{Results: results})
+	}))
+	t.Cleanup(server.Close)
+	return ft, NewClient(server.URL, "test-token", slog.Default())
+}
+
+// acceptedWrites counts accepted statements whose SQL starts with prefix.
+func (ft *flakyTurso) acceptedWrites(prefix string) int {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	n := 0
+	for _, sqls := range ft.accepted {
+		for _, sql := range sqls {
+			if strings.HasPrefix(strings.TrimSpace(sql), prefix) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func testHealthConfig() SyncConfig {
+	return SyncConfig{
+		Enabled:                  true,
+		DeviceID:                 "test-device",
+		DeviceKey:                "test-key",
+		HeartbeatIntervalSeconds: 60,
+		CriticalSyncEnabled:      true,
+		WarmSyncIntervalMinutes:  60,
+		FullSyncIntervalHours:    24,
+		MaxOfflineQueueSize:      100,
+	}
+}
+
+func TestSyncEngine_QueuesWhileOfflineAndFlushesOnReconnect(t *testing.T) {
+	ft, client := newFlakyTurso(t)
+	engine := NewSyncEngine(client, testHealthConfig(), slog.Default())
+	ctx := context.Background()
+
+	ft.failing.Store(true)
+	if err := engine.CriticalSync(ctx, &AgentMemory{AgentID: "agent-1"}); err == nil {
+		t.Fatal("expected CriticalSync to fail while Turso is down")
+	}
+	st := engine.Status()
+	if st.Connected || st.QueueDepth != 1 || st.ConsecutiveFailures != 1 || st.LastError == "" {
+		t.Fatalf("status after failure = %+v", st)
+	}
+
+	// Once offline, writes are queued without hitting Turso.
+	before := ft.requests.Load()
+	err := engine.CriticalSync(ctx, &AgentMemory{AgentID: "agent-2"})
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("err = %v, want ErrOffline", err)
+	}
+	if ft.requests.Load() != before {
+		t.Error("offline write reached Turso")
+	}
+
+	// Still down: the probe fails and the delay backs off.
+	if d := engine.reconnectStep(ctx, engine.reconnectMin); d != 2*engine.reconnectMin {
+		t.Errorf("delay after failed probe = %v, want %v", d, 2*engine.reconnectMin)
+	}
+	if got := engine.Status().QueueDepth; got != 2 {
+		t.Fatalf("queue depth = %d, want 2", got)
+	}
+
+	ft.failing.Store(false)
+	if d := engine.reconnectStep(ctx, 2*engine.reconnectMin); d != engine.reconnectMin {
+		t.Errorf("delay after reconnect = %v, want %v", d, engine.reconnectMin)
+	}
+
+	st = engine.Status()
+	if !st.Connected || st.QueueDepth != 0 || st.ConsecutiveFailures != 0 || st.LastSuccess == nil {
+		t.Errorf("status after reconnect = %+v", st)
+	}
+	if n := ft.acceptedWrites("INSERT INTO agents"); n != 2 {
+		t.Errorf("flushed agent writes = %d, want 2", n)
+	}
+}
+
+func TestSyncEngine_ReconnectLoopFlushesQueue(t *testing.T) {
+	ft, client := newFlakyTurso(t)
+	engine := NewSyncEngine(client, testHealthConfig(), slog.Default())
+	engine.reconnectMin = 10 * time.Millisecond
+
+	ft.failing.Store(true)
+	ctx := context.Background()
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = engine.Stop() }()
+
+	_ = engine.CriticalSync(ctx, &AgentMemory{AgentID: "agent-1"})
+	if engine.Status().QueueDepth != 1 {
+		t.Fatalf("status = %+v, want one queued write", engine.Status())
+	}
+
+	ft.failing.Store(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for engine.Status().QueueDepth > 0 || !engine.Status().Connected {
+		if time.Now().After(deadline) {
+			t.Fatalf("queue not flushed: %+v", engine.Status())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if n := ft.acceptedWrites("INSERT INTO agents"); n != 1 {
+		t.Errorf("flushed agent writes = %d, want 1", n)
+	}
+}
+
+func TestSyncEngine_ReconnectCreatesPendingSchema(t *testing.T) {
+	ft, client := newFlakyTurso(t)
+	engine := NewSyncEngine(client, testHealthConfig(), slog.Default())
+	ctx := context.Background()
+
+	engine.markSchemaPending(errors.New("dial tcp: connection refused"))
+	if engine.Status().Connected {
+		t.Fatal("engine connected with schema pending")
+	}
+
+	engine.reconnectStep(ctx, engine.reconnectMin)
+	if !engine.Status().Connected || engine.schemaPending.Load() {
+		t.Errorf("status = %+v, schema pending = %v", engine.Status(), engine.schemaPending.Load())
+	}
+	if ft.acceptedWrites("CREATE TABLE") == 0 {
+		t.Error("schema not created on reconnect")
+	}
+}
+
+func TestSyncEngine_RejectedStatementKeepsConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(PipelineResponse{Results: []BatchResult{
+			{Type: "error", Error: &PipelineError{Message: "constraint failed"}},
+		}})
+	}))
+	defer server.Close()
+
+	engine := NewSyncEngine(NewClient(server.URL, "test-token", slog.Default()), testHealthConfig(), slog.Default())
+	ctx := context.Background()
+	if err := engine.CriticalSync(ctx, &AgentMemory{AgentID: "agent-1"}); err == nil {
+		t.Fatal("expected rejected CriticalSync to fail")
+	}
+	if st := engine.Status(); !st.Connected || st.QueueDepth != 0 {
+		t.Errorf("status = %+v, want connected with nothing queued", st)
+	}
+
+	// A write queued during an outage that Turso then rejects is dropped.
+	engine.offlineQueue.Enqueue(&SyncOperation{Type: "critical", Data: &AgentMemory{AgentID: "agent-1"}})
+	engine.processOfflineQueue(ctx)
+	if got := engine.Status().QueueDepth; got != 0 {
+		t.Errorf("queue depth = %d, want rejected write dropped", got)
+	}
+}
+
+var agentIDPattern = regexp.MustCompile(`agent-\d+`)
+
+func TestSyncEngine_ConcurrentReplayKeepsOrder(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req PipelineRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		results := make([]BatchResult, len(req.Requests))
+		for i, br := range req.Requests {
+			results[i] = BatchResult{Type: "ok"}
+			stmt, _ := json.Marshal(br.Statement)
+			if id := agentIDPattern.FindString(string(stmt)); id != "" {
+				mu.Lock()
+				order = append(order, id)
+				mu.Unlock()
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		_ = json.NewEncoder(w).Encode(PipelineResponse{Results: results})
+	}))
+	defer server.Close()
+
+	engine := NewSyncEngine(NewClient(server.URL, "test-token", slog.Default()), testHealthConfig(), slog.Default())
+	for i := 0; i < 10; i++ {
+		engine.offlineQueue.Enqueue(&SyncOperation{Type: "critical", Data: &AgentMemory{AgentID: fmt.Sprintf("agent-%d", i)}})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			engine.processOfflineQueue(context.Background())
+		}()
+	}
+	wg.Wait()
+	engine.processOfflineQueue(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	seen := make(map[string]bool)
+	var first []string
+	for _, id := range order {
+		if !seen[id] {
+			seen[id] = true
+			first = append(first, id)
+		}
+	}
+	if len(first) != 10 {
+		t.Fatalf("applied %d agents, want 10: %v", len(first), order)
+	}
+	for i, id := range first {
+		if id != fmt.Sprintf("agent-%d", i) {
+			t.Fatalf("queued operations applied out of order: %v", first)
+		}
+	}
+}
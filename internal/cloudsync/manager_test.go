@@ -2,6 +2,7 @@ package cloudsync
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"testing"
 
@@ -192,3 +193,27 @@ func TestOfflineQueueDefaultMaxSize(t *testing.T) {
 		t.Errorf("expected default maxSize 1000, got %d", q2.maxSize)
 	}
 }
+
+func TestManagerPausedRejectsWrites(t *testing.T) {
+	cfg := config.CloudSyncConfig{Enabled: true, DatabaseURL: "http://127.0.0.1:1", AuthToken: "token"}
+	m, err := NewManager(cfg, slog.Default())
+	if err != nil {
+		t.Fatalf("NewManager() error: %v", err)
+	}
+	m.SetPaused(true)
+
+	ctx := context.Background()
+	if err := m.SyncCritical(ctx, &AgentMemory{AgentID: "a1"}); !errors.Is(err, ErrPaused) {
+		t.Errorf("SyncCritical() = %v, want ErrPaused", err)
+	}
+	if err := m.SyncWarm(ctx, &MemorySnapshot{AgentID: "a1"}); !errors.Is(err, ErrPaused) {
+		t.Errorf("SyncWarm() = %v, want ErrPaused", err)
+	}
+	if err := m.SyncFull(ctx, &MemorySnapshot{AgentID: "a1"}); !errors.Is(err, ErrPaused) {
+		t.Errorf("SyncFull() = %v, want ErrPaused", err)
+	}
+
+	// Pausing a disabled manager is harmless.
+	disabled, _ := NewManager(config.CloudSyncConfig{}, slog.Default())
+	disabled.SetPaused(true)
+}
@@ -58,6 +58,18 @@ func (q *OfflineQueue) Dequeue() *SyncOperation {
 	return op
 }
 
+// Requeue puts an operation back at the front of the queue, ahead of newer
+// ones, after a failed replay.
+func (q *OfflineQueue) Requeue(op *SyncOperation) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.queue) >= q.maxSize {
+		q.evictOldest()
+	}
+	q.queue = append([]*SyncOperation{op}, q.queue...)
+}
+
 // Size returns the current queue size
 func (q *OfflineQueue) Size() int {
 	q.mu.Lock()
@@ -0,0 +1,158 @@
+package cloudsync
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// snapshotSyncTimeout bounds each debounced or periodic critical sync.
+const snapshotSyncTimeout = 10 * time.Second
+
+// SnapshotSource returns the current state of every agent for the periodic
+// snapshot sync.
+type SnapshotSource func() []*AgentMemory
+
+// snapshotState holds the debounced critical syncs waiting to be written
+// and when each agent was last synced.
+type snapshotState struct {
+	mu         sync.Mutex
+	pending    map[string]*AgentMemory
+	timers     map[string]*time.Timer
+	lastSynced map[string]time.Time
+	source     SnapshotSource
+}
+
+// SetSnapshotSource sets where the periodic snapshot sync reads agent state
+// from. Without one the periodic sync does nothing.
+func (s *SyncEngine) SetSnapshotSource(src SnapshotSource) {
+	s.snapshots.mu.Lock()
+	defer s.snapshots.mu.Unlock()
+	s.snapshots.source = src
+}
+
+// QueueCritical schedules a critical sync of memory once the debounce
+// window has passed. Further calls for the same agent inside the window
+// replace the pending state, so a burst of conversations costs one write of
+// the latest state.
+func (s *SyncEngine) QueueCritical(memory *AgentMemory) {
+	if !s.config.CriticalSyncEnabled {
+		return
+	}
+	st := &s.snapshots
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.pending == nil {
+		st.pending = make(map[string]*AgentMemory)
+		st.timers = make(map[string]*time.Timer)
+	}
+	_, waiting := st.pending[memory.AgentID]
+	st.pending[memory.AgentID] = memory
+	if waiting {
+		return
+	}
+	agentID := memory.AgentID
+	st.timers[agentID] = time.AfterFunc(s.syncDebounce, func() { s.flushCritical(agentID) })
+}
+
+// flushCritical writes the pending critical sync for an agent, if any.
+func (s *SyncEngine) flushCritical(agentID string) {
+	st := &s.snapshots
+	st.mu.Lock()
+	memory := st.pending[agentID]
+	delete(st.pending, agentID)
+	delete(st.timers, agentID)
+	st.mu.Unlock()
+
+	if memory == nil {
+		return
+	}
+	if s.paused.Load() {
+		s.logger.Debug("cloud sync paused, dropping debounced sync", "agent_id", agentID)
+		return
+	}
+	s.syncSnapshot(memory)
+}
+
+// flushAllCritical writes every pending critical sync now, for shutdown.
+func (s *SyncEngine) flushAllCritical() {
+	st := &s.snapshots
+	st.mu.Lock()
+	ids := make([]string, 0, len(st.timers))
+	for id, timer := range st.timers {
+		// A timer that already fired is flushing the agent itself.
+		if timer.Stop() {
+			ids = append(ids, id)
+		}
+	}
+	st.mu.Unlock()
+
+	for _, id := range ids {
+		s.flushCritical(id)
+	}
+}
+
+// syncSnapshot runs a critical sync and records when it succeeded.
+func (s *SyncEngine) syncSnapshot(memory *AgentMemory) {
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotSyncTimeout)
+	defer cancel()
+
+	if err := s.CriticalSync(ctx, memory); err != nil {
+		s.logger.Debug("cloud sync critical failed (non-fatal)", "agent_id", memory.AgentID, "error", err)
+		return
+	}
+	s.snapshots.mu.Lock()
+	if s.snapshots.lastSynced == nil {
+		s.snapshots.lastSynced = make(map[string]time.Time)
+	}
+	s.snapshots.lastSynced[memory.AgentID] = time.Now()
+	s.snapshots.mu.Unlock()
+}
+
+// syncSnapshots writes the current state of every agent, so idle agents
+// still reach the cloud. Agents synced after a conversation within the last
+// half interval are skipped.
+func (s *SyncEngine) syncSnapshots() {
+	st := &s.snapshots
+	st.mu.Lock()
+	src := st.source
+	st.mu.Unlock()
+	if src == nil {
+		return
+	}
+
+	cutoff := time.Now().Add(-s.snapshotInterval / 2)
+	for _, memory := range src() {
+		st.mu.Lock()
+		_, pending := st.pending[memory.AgentID]
+		recent := st.lastSynced[memory.AgentID].After(cutoff)
+		st.mu.Unlock()
+		if pending || recent {
+			continue
+		}
+		s.syncSnapshot(memory)
+	}
+}
+
+// snapshotLoop runs the periodic snapshot sync.
+func (s *SyncEngine) snapshotLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.paused.Load() {
+				continue
+			}
+			s.syncSnapshots()
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package cloudsync
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+const agentUpsert = "INSERT INTO agents"
+
+func waitForWrites(t *testing.T, ft *flakyTurso, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for ft.acceptedWrites(agentUpsert) < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("agent writes = %d, want %d", ft.acceptedWrites(agentUpsert), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestQueueCritical_CoalescesRapidConversations(t *testing.T) {
+	ft, client := newFlakyTurso(t)
+	engine := NewSyncEngine(client, testHealthConfig(), slog.Default())
+	engine.syncDebounce = 50 * time.Millisecond
+
+	var last *AgentMemory
+	for i := 0; i < 5; i++ {
+		last = &AgentMemory{AgentID: "agent-1", CoreMemory: map[string]interface{}{"n": i}}
+		engine.QueueCritical(last)
+	}
+	engine.QueueCritical(&AgentMemory{AgentID: "agent-2"})
+
+	engine.snapshots.mu.Lock()
+	pending := engine.snapshots.pending["agent-1"]
+	engine.snapshots.mu.Unlock()
+	if pending != last {
+		t.Error("pending sync is not the latest state")
+	}
+
+	waitForWrites(t, ft, 2)
+	time.Sleep(100 * time.Millisecond)
+	if got := ft.acceptedWrites(agentUpsert); got != 2 {
+		t.Errorf("agent writes = %d, want one per agent", got)
+	}
+
+	// A conversation after the window starts a new one.
+	engine.QueueCritical(&AgentMemory{AgentID: "agent-1"})
+	waitForWrites(t, ft, 3)
+}
+
+func TestSnapshotLoop_SyncsIdleAgents(t *testing.T) {
+	ft, client := newFlakyTurso(t)
+	cfg := testHealthConfig()
+	engine := NewSyncEngine(client, cfg, slog.Default())
+	engine.snapshotInterval = 50 * time.Millisecond
+	engine.SetSnapshotSource(func() []*AgentMemory {
+		return []*AgentMemory{{AgentID: "idle-agent"}}
+	})
+
+	if err := engine.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = engine.Stop() }()
+
+	// The agent never has a conversation but is synced on every tick.
+	waitForWrites(t, ft, 2)
+}
+
+func TestSyncSnapshots_SkipsRecentlySyncedAgents(t *testing.T) {
+	ft, client := newFlakyTurso(t)
+	engine := NewSyncEngine(client, testHealthConfig(), slog.Default())
+	engine.snapshotInterval = time.Hour
+	engine.SetSnapshotSource(func() []*AgentMemory {
+		return []*AgentMemory{{AgentID: "busy"}, {AgentID: "idle"}}
+	})
+
+	engine.syncSnapshot(&AgentMemory{AgentID: "busy"})
+	engine.syncSnapshots()
+	if got := ft.acceptedWrites(agentUpsert); got != 2 {
+		t.Errorf("agent writes = %d, want 2 (busy once, idle once)", got)
+	}
+}
+
+func TestStop_FlushesPendingCriticalSyncs(t *testing.T) {
+	ft, client := newFlakyTurso(t)
+	engine := NewSyncEngine(client, testHealthConfig(), slog.Default())
+	engine.syncDebounce = time.Hour
+	if err := engine.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	engine.QueueCritical(&AgentMemory{AgentID: "agent-1"})
+	if err := engine.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if got := ft.acceptedWrites(agentUpsert); got != 1 {
+		t.Errorf("agent writes after Stop = %d, want 1", got)
+	}
+}
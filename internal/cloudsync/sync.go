@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,6 +22,29 @@ type SyncEngine struct {
 	wg           sync.WaitGroup
 	mu           sync.RWMutex
 	running      bool
+	// paused suspends heartbeats and offline queue replay (safe mode)
+	paused atomic.Bool
+	// replayMu lets one goroutine at a time replay the offline queue, so
+	// queued operations are applied once and in order
+	replayMu sync.Mutex
+	// health tracks whether Turso is reachable; see Status
+	health connHealth
+	// schemaPending is set when InitSchema failed; reconnect retries it
+	schemaPending atomic.Bool
+	// reconnect backoff bounds (reconnectMinDelay/reconnectMaxDelay unless
+	// overridden in tests)
+	reconnectMin time.Duration
+	reconnectMax time.Duration
+	// debounced critical syncs and the periodic snapshot; see snapshots.go
+	snapshots        snapshotState
+	syncDebounce     time.Duration
+	snapshotInterval time.Duration
+}
+
+// SetPaused suspends or resumes background writes. Queued operations are
+// kept and replayed after resuming.
+func (s *SyncEngine) SetPaused(paused bool) {
+	s.paused.Store(paused)
 }
 
 // SyncConfig holds cloud sync configuration
@@ -34,6 +58,12 @@ type SyncConfig struct {
 	FullSyncIntervalHours    int
 	FullSyncRequireWiFi      bool
 	MaxOfflineQueueSize      int
+	// SnapshotIntervalSeconds is how often every agent's state is synced,
+	// whether or not it has had conversations (0 = no periodic snapshot).
+	SnapshotIntervalSeconds int
+	// SyncDebounceMs is how long QueueCritical waits to coalesce an
+	// agent's conversations into one write.
+	SyncDebounceMs int
 }
 
 // AgentMemory represents an agent's complete memory state
@@ -101,6 +131,11 @@ func NewSyncEngine(client *Client, config SyncConfig, logger *slog.Logger) *Sync
 		logger:       logger,
 		offlineQueue: NewOfflineQueue(config.MaxOfflineQueueSize),
 		stopCh:       make(chan struct{}),
+		reconnectMin: reconnectMinDelay,
+		reconnectMax: reconnectMaxDelay,
+
+		syncDebounce:     time.Duration(config.SyncDebounceMs) * time.Millisecond,
+		snapshotInterval: time.Duration(config.SnapshotIntervalSeconds) * time.Second,
 	}
 }
 
@@ -126,16 +161,22 @@ func (s *SyncEngine) Start(ctx context.Context) error {
 	}
 
 	// Start background goroutines
-	s.wg.Add(3)
+	s.wg.Add(4)
 	go s.heartbeatLoop(ctx)
 	go s.warmSyncLoop(ctx)
 	go s.fullSyncLoop(ctx)
+	go s.reconnectLoop(ctx)
+	if s.snapshotInterval > 0 {
+		s.wg.Add(1)
+		go s.snapshotLoop(ctx)
+	}
 
 	s.logger.Info("cloud sync engine started",
 		"device_id", s.config.DeviceID,
 		"heartbeat_interval", s.config.HeartbeatIntervalSeconds,
 		"warm_sync_interval", s.config.WarmSyncIntervalMinutes,
-		"full_sync_interval", s.config.FullSyncIntervalHours)
+		"full_sync_interval", s.config.FullSyncIntervalHours,
+		"snapshot_interval", s.snapshotInterval)
 
 	return nil
 }
@@ -150,6 +191,7 @@ func (s *SyncEngine) Stop() error {
 	}
 
 	s.logger.Info("stopping cloud sync engine")
+	s.flushAllCritical()
 	close(s.stopCh)
 	s.wg.Wait()
 	s.running = false
@@ -162,7 +204,84 @@ func (s *SyncEngine) CriticalSync(ctx context.Context, memory *AgentMemory) erro
 	if !s.config.CriticalSyncEnabled {
 		return nil
 	}
+	return s.submit(ctx, &SyncOperation{
+		Type:      "critical",
+		AgentID:   memory.AgentID,
+		Data:      memory,
+		Timestamp: currentTimestamp(),
+	})
+}
+
+// WarmSync syncs recent events (hourly)
+func (s *SyncEngine) WarmSync(ctx context.Context, snapshot *MemorySnapshot) error {
+	if len(snapshot.WarmMemory) == 0 {
+		s.logger.Debug("no warm memory to sync")
+		return nil
+	}
+	return s.submit(ctx, &SyncOperation{
+		Type:      "warm",
+		AgentID:   snapshot.AgentID,
+		Data:      snapshot,
+		Timestamp: currentTimestamp(),
+	})
+}
+
+// FullSync performs a complete backup (daily)
+func (s *SyncEngine) FullSync(ctx context.Context, snapshot *MemorySnapshot) error {
+	return s.submit(ctx, &SyncOperation{
+		Type:      "full",
+		AgentID:   snapshot.AgentID,
+		Data:      snapshot,
+		Timestamp: currentTimestamp(),
+	})
+}
+
+// submit writes op to the cloud, or queues it for the reconnect loop to
+// replay when Turso is unreachable. Writes Turso rejects are not queued.
+func (s *SyncEngine) submit(ctx context.Context, op *SyncOperation) error {
+	if !s.health.isConnected() {
+		s.offlineQueue.Enqueue(op)
+		return fmt.Errorf("%s sync skipped (queued for retry): %w", op.Type, ErrOffline)
+	}
+	err := s.apply(ctx, op)
+	if isRejected(err) {
+		return fmt.Errorf("%s sync rejected: %w", op.Type, err)
+	}
+	if err != nil {
+		s.offlineQueue.Enqueue(op)
+		return fmt.Errorf("%s sync failed (queued for retry): %w", op.Type, err)
+	}
+	return nil
+}
+
+// apply performs a queued or fresh sync operation.
+func (s *SyncEngine) apply(ctx context.Context, op *SyncOperation) error {
+	switch op.Type {
+	case "critical":
+		if memory, ok := op.Data.(*AgentMemory); ok {
+			return s.criticalSync(ctx, memory)
+		}
+	case "warm":
+		if snapshot, ok := op.Data.(*MemorySnapshot); ok {
+			return s.warmSync(ctx, snapshot)
+		}
+	case "full":
+		if snapshot, ok := op.Data.(*MemorySnapshot); ok {
+			return s.fullSync(ctx, snapshot)
+		}
+	}
+	return fmt.Errorf("unknown sync operation %q", op.Type)
+}
 
+// exec runs statements against Turso and records the outcome in the
+// connection health.
+func (s *SyncEngine) exec(ctx context.Context, statements []Statement) error {
+	err := s.client.BatchExecute(ctx, statements)
+	s.recordResult(err)
+	return err
+}
+
+func (s *SyncEngine) criticalSync(ctx context.Context, memory *AgentMemory) error {
 	s.logger.Debug("critical sync started", "agent_id", memory.AgentID)
 
 	// Prepare statements
@@ -246,30 +365,17 @@ func (s *SyncEngine) CriticalSync(ctx context.Context, memory *AgentMemory) erro
 	}
 
 	// Execute with retry
-	if err := s.client.BatchExecute(ctx, statements); err != nil {
-		// Queue for offline retry
-		s.offlineQueue.Enqueue(&SyncOperation{
-			Type:      "critical",
-			AgentID:   memory.AgentID,
-			Data:      memory,
-			Timestamp: now,
-		})
-		return fmt.Errorf("critical sync failed (queued for retry): %w", err)
+	if err := s.exec(ctx, statements); err != nil {
+		return err
 	}
 
 	s.logger.Info("critical sync completed", "agent_id", memory.AgentID)
 	return nil
 }
 
-// WarmSync syncs recent events (hourly)
-func (s *SyncEngine) WarmSync(ctx context.Context, snapshot *MemorySnapshot) error {
+func (s *SyncEngine) warmSync(ctx context.Context, snapshot *MemorySnapshot) error {
 	s.logger.Debug("warm sync started", "agent_id", snapshot.AgentID)
 
-	if len(snapshot.WarmMemory) == 0 {
-		s.logger.Debug("no warm memory to sync")
-		return nil
-	}
-
 	now := currentTimestamp()
 	expiresAt := now + (30 * 24 * 3600) // 30 days from now
 
@@ -320,14 +426,8 @@ func (s *SyncEngine) WarmSync(ctx context.Context, snapshot *MemorySnapshot) err
 		},
 	})
 
-	if err := s.client.BatchExecute(ctx, statements); err != nil {
-		s.offlineQueue.Enqueue(&SyncOperation{
-			Type:      "warm",
-			AgentID:   snapshot.AgentID,
-			Data:      snapshot,
-			Timestamp: now,
-		})
-		return fmt.Errorf("warm sync failed (queued for retry): %w", err)
+	if err := s.exec(ctx, statements); err != nil {
+		return err
 	}
 
 	s.logger.Info("warm sync completed",
@@ -336,8 +436,7 @@ func (s *SyncEngine) WarmSync(ctx context.Context, snapshot *MemorySnapshot) err
 	return nil
 }
 
-// FullSync performs a complete backup (daily)
-func (s *SyncEngine) FullSync(ctx context.Context, snapshot *MemorySnapshot) error {
+func (s *SyncEngine) fullSync(ctx context.Context, snapshot *MemorySnapshot) error {
 	s.logger.Debug("full sync started", "agent_id", snapshot.AgentID)
 
 	now := currentTimestamp()
@@ -403,14 +502,8 @@ func (s *SyncEngine) FullSync(ctx context.Context, snapshot *MemorySnapshot) err
 		},
 	})
 
-	if err := s.client.BatchExecute(ctx, statements); err != nil {
-		s.offlineQueue.Enqueue(&SyncOperation{
-			Type:      "full",
-			AgentID:   snapshot.AgentID,
-			Data:      snapshot,
-			Timestamp: now,
-		})
-		return fmt.Errorf("full sync failed (queued for retry): %w", err)
+	if err := s.exec(ctx, statements); err != nil {
+		return err
 	}
 
 	s.logger.Info("full sync completed",
@@ -424,7 +517,7 @@ func (s *SyncEngine) FullSync(ctx context.Context, snapshot *MemorySnapshot) err
 func (s *SyncEngine) registerDevice(ctx context.Context) error {
 	now := currentTimestamp()
 
-	return s.client.Execute(ctx,
+	err := s.client.Execute(ctx,
 		`INSERT INTO devices (device_id, agent_id, device_key, device_name, device_type, last_heartbeat, created_at)
 		 VALUES (?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(device_id) DO UPDATE SET
@@ -437,6 +530,8 @@ func (s *SyncEngine) registerDevice(ctx context.Context) error {
 		now,
 		now,
 	)
+	s.recordResult(err)
+	return err
 }
 
 // heartbeatLoop sends periodic heartbeats
@@ -453,6 +548,9 @@ func (s *SyncEngine) heartbeatLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			if s.paused.Load() {
+				continue
+			}
 			if err := s.sendHeartbeat(ctx); err != nil {
 				s.logger.Warn("heartbeat failed", "error", err)
 			}
@@ -474,6 +572,9 @@ func (s *SyncEngine) warmSyncLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			if s.paused.Load() {
+				continue
+			}
 			s.processOfflineQueue(ctx)
 		}
 	}
@@ -502,15 +603,26 @@ func (s *SyncEngine) fullSyncLoop(ctx context.Context) {
 // sendHeartbeat updates device heartbeat timestamp
 func (s *SyncEngine) sendHeartbeat(ctx context.Context) error {
 	now := currentTimestamp()
-	return s.client.Execute(ctx,
+	err := s.client.Execute(ctx,
 		"UPDATE devices SET last_heartbeat = ? WHERE device_id = ?",
 		now,
 		s.config.DeviceID,
 	)
+	s.recordResult(err)
+	return err
 }
 
-// processOfflineQueue attempts to sync queued operations
+// processOfflineQueue replays queued operations in order, stopping at the
+// first failure; the failed operation goes back to the front of the queue.
+// Operations Turso rejects outright are dropped rather than retried forever.
+// The warm sync and reconnect loops both call it; a call made while another
+// replay is running returns at once and leaves the queue to that replay.
 func (s *SyncEngine) processOfflineQueue(ctx context.Context) {
+	if !s.replayMu.TryLock() {
+		return
+	}
+	defer s.replayMu.Unlock()
+
 	for {
 		op := s.offlineQueue.Dequeue()
 		if op == nil {
@@ -521,25 +633,16 @@ func (s *SyncEngine) processOfflineQueue(ctx context.Context) {
 			"type", op.Type,
 			"agent_id", op.AgentID)
 
-		var err error
-		switch op.Type {
-		case "critical":
-			if memory, ok := op.Data.(*AgentMemory); ok {
-				err = s.CriticalSync(ctx, memory)
-			}
-		case "warm":
-			if snapshot, ok := op.Data.(*MemorySnapshot); ok {
-				err = s.WarmSync(ctx, snapshot)
-			}
-		case "full":
-			if snapshot, ok := op.Data.(*MemorySnapshot); ok {
-				err = s.FullSync(ctx, snapshot)
-			}
+		err := s.apply(ctx, op)
+		if isRejected(err) {
+			s.logger.Error("queued sync operation rejected, dropping it",
+				"type", op.Type,
+				"agent_id", op.AgentID,
+				"error", err)
+			continue
 		}
-
 		if err != nil {
-			// Re-queue if still failing
-			s.offlineQueue.Enqueue(op)
+			s.offlineQueue.Requeue(op)
 			s.logger.Warn("queued sync operation failed again",
 				"type", op.Type,
 				"agent_id", op.AgentID,
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Capability is something an agent can do, matched against the
+// permissions of the tools it may use. In JSON it is a name
+// ("filesystem"), a name and version ("filesystem@2.1"), or an object
+// with name, version and an optional JSON schema for the parameters the
+// capability takes.
+type Capability struct {
+	Name string `json:"name"`
+	// Version is "MAJOR" or "MAJOR.MINOR" ("" = any). See
+	// CompatibleVersion.
+	Version string                 `json:"version,omitempty"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+// ParseCapability parses "name" or "name@version".
+func ParseCapability(s string) Capability {
+	name, version, _ := strings.Cut(strings.TrimSpace(s), "@")
+	return Capability{Name: name, Version: version}
+}
+
+// String returns the capability as "name" or "name@version".
+func (c Capability) String() string {
+	if c.Version == "" {
+		return c.Name
+	}
+	return c.Name + "@" + c.Version
+}
+
+// MarshalJSON writes the short string form unless Params is set, so
+// configs keep the shape they were written in.
+func (c Capability) MarshalJSON() ([]byte, error) {
+	if c.Params == nil {
+		return json.Marshal(c.String())
+	}
+	type plain Capability
+	return json.Marshal(plain(c))
+}
+
+// UnmarshalJSON accepts the string and object forms.
+func (c *Capability) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*c = ParseCapability(s)
+		return nil
+	}
+	type plain Capability
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+// Satisfies reports whether c meets a tool's requirement, given as
+// "name" or "name@version".
+func (c Capability) Satisfies(requirement string) bool {
+	req := ParseCapability(requirement)
+	return c.Name == req.Name && CompatibleVersion(c.Version, req.Version)
+}
+
+// Capabilities is an agent's capability list.
+type Capabilities []Capability
+
+// NewCapabilities builds a list from "name" or "name@version" strings.
+func NewCapabilities(specs ...string) Capabilities {
+	caps := make(Capabilities, len(specs))
+	for i, s := range specs {
+		caps[i] = ParseCapability(s)
+	}
+	return caps
+}
+
+// Strings returns each capability in its "name@version" form.
+func (cs Capabilities) Strings() []string {
+	out := make([]string, len(cs))
+	for i, c := range cs {
+		out[i] = c.String()
+	}
+	return out
+}
+
+// Satisfies reports whether any capability meets requirement.
+func (cs Capabilities) Satisfies(requirement string) bool {
+	for _, c := range cs {
+		if c.Satisfies(requirement) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompatibleVersion reports whether a capability at version have meets a
+// requirement for version want: the major versions must match and have's
+// minor version must be at least want's. An empty version on either side
+// matches anything.
+func CompatibleVersion(have, want string) bool {
+	if have == "" || want == "" {
+		return true
+	}
+	hMajor, hMinor, ok1 := parseVersion(have)
+	wMajor, wMinor, ok2 := parseVersion(want)
+	if !ok1 || !ok2 {
+		return have == want
+	}
+	return hMajor == wMajor && hMinor >= wMinor
+}
+
+// parseVersion parses "MAJOR" or "MAJOR.MINOR"; anything after the minor
+// version is ignored.
+func parseVersion(v string) (major, minor int, ok bool) {
+	parts := strings.SplitN(v, ".", 3)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil || major < 0 {
+		return 0, 0, false
+	}
+	if len(parts) > 1 {
+		if minor, err = strconv.Atoi(parts[1]); err != nil || minor < 0 {
+			return 0, 0, false
+		}
+	}
+	return major, minor, true
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCapabilitiesJSON(t *testing.T) {
+	data := []byte(`["filesystem", "payments@1.2", {"name": "search", "version": "2", "params": {"type": "object"}}]`)
+	var caps Capabilities
+	if err := json.Unmarshal(data, &caps); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(caps) != 3 {
+		t.Fatalf("got %d capabilities, want 3", len(caps))
+	}
+	if caps[0].Name != "filesystem" || caps[0].Version != "" || caps[1].Name != "payments" || caps[1].Version != "1.2" {
+		t.Errorf("string forms parsed as %+v, %+v", caps[0], caps[1])
+	}
+	if caps[2].Name != "search" || caps[2].Version != "2" || caps[2].Params["type"] != "object" {
+		t.Errorf("object form parsed as %+v", caps[2])
+	}
+
+	out, err := json.Marshal(caps)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `["filesystem","payments@1.2",{"name":"search","version":"2","params":{"type":"object"}}]`
+	if string(out) != want {
+		t.Errorf("marshal = %s, want %s", out, want)
+	}
+}
+
+func TestCompatibleVersion(t *testing.T) {
+	for _, tt := range []struct {
+		have, want string
+		ok         bool
+	}{
+		{"", "2", true},
+		{"2", "", true},
+		{"2", "2", true},
+		{"2.3", "2.1", true},
+		{"2.1", "2.3", false},
+		{"3", "2", false},
+		{"1.9", "2", false},
+		{"beta", "beta", true},
+		{"beta", "2", false},
+	} {
+		if got := CompatibleVersion(tt.have, tt.want); got != tt.ok {
+			t.Errorf("CompatibleVersion(%q, %q) = %v, want %v", tt.have, tt.want, got, tt.ok)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -45,11 +44,49 @@ type Config struct {
 	// Auto-update configuration
 	Updates *UpdatesConfig `json:"updates,omitempty"`
 
+	// Agentic tool loop limits
+	ToolLoop ToolLoopConfig `json:"toolLoop,omitempty"`
+
+	// OpenTelemetry tracing
+	Tracing TracingConfig `json:"tracing,omitempty"`
+
 	// Agent definitions
 	Agents []AgentDef `json:"agents"`
 
 	// E2B cloud sandbox settings
 	Cloud CloudConfig `json:"cloud,omitempty"`
+
+	// Content moderation for inbound messages and outbound responses
+	Moderation ModerationConfig `json:"moderation,omitempty"`
+
+	// Ordered rewrites applied to agent responses before they are sent
+	PostProcess []PostProcessStep `json:"postProcess,omitempty"`
+
+	// Webhook notifications for orchestrator events
+	Notifications NotificationsConfig `json:"notifications,omitempty"`
+
+	// offlineDisabled lists the settings server.offline forced off.
+	offlineDisabled []string
+}
+
+// ToolLoopConfig bounds how long an agent may keep calling tools for one
+// message. Zero values keep the built-in defaults (10 iterations, no
+// wall-clock limit).
+type ToolLoopConfig struct {
+	MaxIterations int `json:"maxIterations,omitempty"`
+	BudgetSec     int `json:"budgetSec,omitempty"`
+	// StrictCapabilities refuses to start when an agent capability has no
+	// backing tool or a tool needs a version the agent lacks, instead of
+	// logging a warning.
+	StrictCapabilities bool `json:"strictCapabilities,omitempty"`
+}
+
+// TracingConfig enables OpenTelemetry tracing. Spans are exported to an
+// OTLP/HTTP collector at Endpoint (default http://localhost:4318).
+type TracingConfig struct {
+	Enabled     bool   `json:"enabled"`
+	Endpoint    string `json:"endpoint,omitempty"`
+	ServiceName string `json:"serviceName,omitempty"`
 }
 
 type CloudConfig struct {
@@ -67,6 +104,69 @@ type ServerConfig struct {
 	Port     int    `json:"port"`
 	DataDir  string `json:"dataDir"`
 	LogLevel string `json:"logLevel"`
+	// LogFormat is "text" (default) or "json"
+	LogFormat string `json:"logFormat,omitempty"`
+	// LogFile, when set, sends logs to this file instead of stdout. It is
+	// rotated once it exceeds LogMaxSizeMB, keeping LogMaxBackups old files.
+	LogFile       string `json:"logFile,omitempty"`
+	LogMaxSizeMB  int    `json:"logMaxSizeMb,omitempty"`
+	LogMaxBackups int    `json:"logMaxBackups,omitempty"`
+	// Storage selects the persistence backend: "file" (default) or "sqlite"
+	Storage string `json:"storage,omitempty"`
+	// SkillHotReload watches the skills directory and re-registers skills
+	// when their files change
+	SkillHotReload bool `json:"skillHotReload,omitempty"`
+	// GlobalBudgetUSD caps LLM spend across all agents per BudgetPeriod
+	// (0 = unlimited). Agents get their own cap via AgentDef.CostBudgetUSD.
+	GlobalBudgetUSD float64 `json:"globalBudgetUsd,omitempty"`
+	// BudgetPeriod is "daily" (default) or "monthly"; budgets reset at the
+	// period boundary (UTC).
+	BudgetPeriod string `json:"budgetPeriod,omitempty"`
+	// QueuePolicy decides what happens when the inbox or outbox is full:
+	// "block" (default) waits up to QueueTimeoutMs, "drop-new" drops the
+	// incoming item and "drop-oldest" evicts the oldest queued item.
+	// Dropped items go to the dead-letter log.
+	QueuePolicy    string `json:"queuePolicy,omitempty"`
+	QueueTimeoutMs int    `json:"queueTimeoutMs,omitempty"`
+	// OutboxWorkers is how many goroutines deliver responses (0 = 4).
+	// Responses for the same channel and recipient always go through the
+	// same worker, so they arrive in order.
+	OutboxWorkers int `json:"outboxWorkers,omitempty"`
+	// ShutdownTimeoutSec bounds how long Stop waits for in-flight messages
+	// to finish and their responses to be delivered (0 = 20).
+	ShutdownTimeoutSec int `json:"shutdownTimeoutSec,omitempty"`
+	// DurableInbox journals incoming messages to dataDir/inbox.wal before
+	// they are queued. Messages not yet handled when the process stops are
+	// replayed on the next start; redelivered messages are deduplicated
+	// by channel and message ID.
+	DurableInbox bool `json:"durableInbox,omitempty"`
+	// WarmupOnStart sends a one-token prompt to each local agent's model
+	// at startup, so the first real message doesn't wait for the model to
+	// load. Failures are logged and otherwise ignored.
+	WarmupOnStart bool `json:"warmupOnStart,omitempty"`
+	// SafeMode starts the server with evolution, on-chain reporting and
+	// cloud sync writes frozen; chat and reads keep working. SIGUSR2
+	// toggles it at runtime.
+	SafeMode bool `json:"safeMode,omitempty"`
+	// MaxChatBodyBytes caps the size of a POST /api/chat request body
+	// (0 = 1 MiB). Larger bodies are rejected before they are read.
+	MaxChatBodyBytes int64 `json:"maxChatBodyBytes,omitempty"`
+	// Offline is for air-gapped deployments: it forces off on-chain
+	// reporting, every chain, ClawChain discovery, cloud sync, the
+	// Turso-backed memory system, tracing, E2B sandboxes and update checks
+	// at load time, whatever their own settings.
+	Offline bool `json:"offline,omitempty"`
+	// AgentDataDirs keeps everything persisted for an agent (its record,
+	// strategy, genomes, conversation memory and action log) under
+	// dataDir/agents/<id>/, so deleting the agent removes all of it.
+	AgentDataDirs bool `json:"agentDataDirs,omitempty"`
+	// TLSCert and TLSKey, when both set, serve the API and dashboard over
+	// HTTPS using these PEM files. SIGHUP reloads them from disk.
+	TLSCert string `json:"tlsCert,omitempty"`
+	TLSKey  string `json:"tlsKey,omitempty"`
+	// HTTPRedirectPort, with TLS on, also listens for plain HTTP on this
+	// port and redirects every request to HTTPS (0 = off).
+	HTTPRedirectPort int `json:"httpRedirectPort,omitempty"`
 }
 
 type MQTTConfig struct {
@@ -74,11 +174,33 @@ type MQTTConfig struct {
 	Host     string `json:"host"`
 	Username string `json:"username,omitempty"`
 	Password string `json:"password,omitempty"`
+	// TopicPrefix namespaces all topics, so deployments sharing a broker
+	// don't collide (default "evoclaw"). Edge agents must use the same one.
+	TopicPrefix string `json:"topicPrefix,omitempty"`
+	// EdgeAgentTTLHours is how long an edge agent may go unseen before it
+	// is forgotten entirely (default 24). It goes offline much earlier.
+	EdgeAgentTTLHours int `json:"edgeAgentTtlHours,omitempty"`
+	// TLS, when set, connects to the broker over TLS (tls://) instead of
+	// plain TCP.
+	TLS *MQTTTLSConfig `json:"tls,omitempty"`
+}
+
+// MQTTTLSConfig holds the certificates for a TLS broker connection. All
+// paths are PEM files.
+type MQTTTLSConfig struct {
+	// CACert verifies the broker; the system roots are used when empty.
+	CACert string `json:"caCert,omitempty"`
+	// ClientCert and ClientKey enable mutual TLS; set both or neither.
+	ClientCert string `json:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty"`
+	// InsecureSkipVerify accepts any broker certificate. Development only.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 }
 
 type ChannelConfig struct {
 	Telegram *TelegramConfig `json:"telegram,omitempty"`
 	TUI      *TUIConfig      `json:"tui,omitempty"`
+	Webhook  *WebhookConfig  `json:"webhook,omitempty"`
 }
 
 // OnChainConfig holds BSC/opBNB blockchain settings
@@ -141,6 +263,12 @@ type CloudSyncConfig struct {
 	FullSyncIntervalHours    int    `json:"fullSyncIntervalHours"`
 	FullSyncRequireWiFi      bool   `json:"fullSyncRequireWifi"`
 	MaxOfflineQueueSize      int    `json:"maxOfflineQueueSize"`
+	// SnapshotIntervalSeconds syncs every agent's state on this interval,
+	// so idle agents still report in (0 = 900, negative = off).
+	SnapshotIntervalSeconds int `json:"snapshotIntervalSeconds,omitempty"`
+	// SyncDebounceMs coalesces an agent's conversations within this window
+	// into one critical sync (0 = 2000).
+	SyncDebounceMs int `json:"syncDebounceMs,omitempty"`
 }
 
 // MemoryConfigSettings holds tiered memory system configuration
@@ -152,6 +280,17 @@ type MemoryConfigSettings struct {
 	Cold       ColdConfig         `json:"cold"`
 	Distillation DistillationConfig `json:"distillation"`
 	Scoring    ScoringConfig      `json:"scoring"`
+
+	// Conversation bounds the per-agent conversation memory kept on disk.
+	Conversation ConversationMemoryConfig `json:"conversation,omitempty"`
+}
+
+// ConversationMemoryConfig mirrors the warm tier's retention and size
+// settings for per-agent conversation memory. Zero values disable eviction.
+type ConversationMemoryConfig struct {
+	RetentionDays           int `json:"retentionDays,omitempty"`
+	MaxSizeKb               int `json:"maxSizeKb,omitempty"`
+	EvictionIntervalMinutes int `json:"evictionIntervalMinutes,omitempty"` // default 10
 }
 
 type TreeConfig struct {
@@ -172,6 +311,11 @@ type WarmConfig struct {
 	RetentionDays      int     `json:"retentionDays"`
 	EvictionThreshold  float64 `json:"evictionThreshold"`
 	Backend            string  `json:"backend"` // "memory" or "sqlite"
+	// ForceConsolidateKb forces a synchronous consolidation (warm → cold)
+	// when a burst of memories pushes an agent's share of the warm tier
+	// past it, instead of waiting for the hourly cycle. Only that agent's
+	// entries are consolidated. 0 disables; keep it below MaxSizeKb.
+	ForceConsolidateKb int `json:"forceConsolidateKb,omitempty"`
 }
 
 type ColdConfig struct {
@@ -185,6 +329,15 @@ type DistillationConfig struct {
 	Aggression        float64 `json:"aggression"` // 0-1
 	Model             string  `json:"model"` // "local" or model name
 	MaxDistilledBytes int     `json:"maxDistilledBytes"`
+
+	// Sampling and retry settings for the LLM distillation call. Zero
+	// values use the defaults: 512 tokens, temperature 0.3, a 30s timeout
+	// per attempt, 2 retries and 500ms backoff (doubling each retry).
+	MaxTokens      int     `json:"maxTokens,omitempty"`
+	Temperature    float64 `json:"temperature,omitempty"`
+	TimeoutSec     int     `json:"timeoutSec,omitempty"`
+	MaxRetries     int     `json:"maxRetries,omitempty"`
+	RetryBackoffMs int     `json:"retryBackoffMs,omitempty"`
 }
 
 type ScoringConfig struct {
@@ -236,12 +389,29 @@ type ActionConfig struct {
 	URL     string            `json:"url,omitempty"`
 	Method  string            `json:"method,omitempty"`
 	Headers map[string]string `json:"headers,omitempty"`
+	// HTTP only: request timeout and accepted status codes (default any 2xx)
+	TimeoutMs    int64 `json:"timeoutMs,omitempty"`
+	ExpectStatus []int `json:"expectStatus,omitempty"`
 }
 
 type TUIConfig struct {
 	Enabled bool `json:"enabled"`
 }
 
+// WebhookConfig configures the generic webhook channel. Inbound payloads are
+// mapped to messages with dotted JSON paths; responses are POSTed to
+// OutboundURL, signed with Secret when set.
+type WebhookConfig struct {
+	Enabled       bool   `json:"enabled"`
+	Listen        string `json:"listen,omitempty"`        // inbound address, e.g. ":8421"; empty = outbound only
+	Path          string `json:"path,omitempty"`          // default "/webhook"
+	ContentPath   string `json:"contentPath,omitempty"`   // default "content"
+	SenderPath    string `json:"senderPath,omitempty"`    // default "from"
+	InboundSecret string `json:"inboundSecret,omitempty"` // require HMAC-SHA256 signatures on inbound requests
+	OutboundURL   string `json:"outboundUrl,omitempty"`
+	Secret        string `json:"secret,omitempty"` // HMAC-SHA256 key for outbound signatures
+}
+
 type TelegramConfig struct {
 	Enabled      bool    `json:"enabled"`
 	BotToken     string  `json:"botToken"`
@@ -255,7 +425,120 @@ type ModelsConfig struct {
 	Routing ModelRouting `json:"routing"`
 	// Health registry configuration
 	Health ModelHealthConfig `json:"health"`
-}
+	// Defaults are the sampling parameters for agents that do not set
+	// their own.
+	Defaults ModelParams `json:"defaults,omitempty"`
+	// ResponseCache reuses answers to exact repeats of low-temperature
+	// requests instead of calling the provider again.
+	ResponseCache ResponseCacheConfig `json:"responseCache,omitempty"`
+	// Reasoning controls what happens to reasoning models' thinking, which
+	// is always kept out of the answer: "hide" (default) drops it, "log"
+	// logs it, and "store" also returns it alongside the answer.
+	Reasoning string `json:"reasoning,omitempty"`
+}
+
+// ResponseCacheConfig configures the provider response cache. Requests are
+// cached by model, system prompt, messages and sampling parameters; those
+// with a temperature above MaxTemperature, or with tools, always go to the
+// provider. Zero values use the defaults (5 minute TTL, 1000 entries,
+// temperature 0.2).
+type ResponseCacheConfig struct {
+	Enabled        bool    `json:"enabled"`
+	TTLSec         int     `json:"ttlSec,omitempty"`
+	MaxEntries     int     `json:"maxEntries,omitempty"`
+	MaxTemperature float64 `json:"maxTemperature,omitempty"`
+}
+
+// ModerationConfig screens message content against a policy. Inbound
+// messages that are flagged are rejected before an agent sees them;
+// flagged outbound responses are replaced before they are sent. Content is
+// flagged if it matches a Blocklist pattern or, when ClassifierModel is
+// set, if that model judges it to break Policy.
+type ModerationConfig struct {
+	Enabled  bool `json:"enabled"`
+	Inbound  bool `json:"inbound"`
+	Outbound bool `json:"outbound"`
+	// Channels limits moderation to the named channels (empty = all).
+	Channels []string `json:"channels,omitempty"`
+	// Blocklist holds regular expressions, matched case-insensitively.
+	Blocklist []string `json:"blocklist,omitempty"`
+	// ClassifierModel is a "provider/model" asked to classify content
+	// against Policy. Classifier errors let the content through.
+	ClassifierModel string `json:"classifierModel,omitempty"`
+	Policy          string `json:"policy,omitempty"`
+	// RejectMessage is sent back for a rejected inbound message, and
+	// Replacement replaces a flagged response. Both have defaults.
+	RejectMessage string `json:"rejectMessage,omitempty"`
+	Replacement   string `json:"replacement,omitempty"`
+}
+
+// Post-processor step types.
+const (
+	PostProcessTruncate     = "truncate"
+	PostProcessAppend       = "append"
+	PostProcessRegexReplace = "regexReplace"
+)
+
+// PostProcessStep is one step of the response post-processing pipeline.
+// Steps run in order, each on the previous step's output.
+type PostProcessStep struct {
+	// Type is "truncate", "append" or "regexReplace".
+	Type string `json:"type"`
+	// MaxChars (truncate) caps the response length in characters,
+	// including Suffix, which marks a cut.
+	MaxChars int    `json:"maxChars,omitempty"`
+	Suffix   string `json:"suffix,omitempty"`
+	// Text (append) is added to the end of the response.
+	Text string `json:"text,omitempty"`
+	// Pattern (regexReplace) is a regular expression whose matches are
+	// replaced with Replacement, which may refer to groups as $1.
+	Pattern     string `json:"pattern,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	// Agents and Channels limit the step to the named agents and
+	// channels (empty = all).
+	Agents   []string `json:"agents,omitempty"`
+	Channels []string `json:"channels,omitempty"`
+}
+
+// NotificationsConfig posts orchestrator events (evolution, model health,
+// budget thresholds) to webhooks. A delivery that still fails after
+// MaxAttempts goes to the dead-letter log.
+type NotificationsConfig struct {
+	Webhooks []NotifyWebhookConfig `json:"webhooks,omitempty"`
+	// MaxAttempts per delivery (0 = 3). Retries back off exponentially
+	// from RetryBackoffMs (0 = 1000).
+	MaxAttempts    int `json:"maxAttempts,omitempty"`
+	RetryBackoffMs int `json:"retryBackoffMs,omitempty"`
+}
+
+// NotifyWebhookConfig is one notification target.
+type NotifyWebhookConfig struct {
+	// Name identifies the webhook in logs and dead letters, so the URL,
+	// which often embeds a token, is never recorded.
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Secret, when set, signs each body with HMAC-SHA256 in the
+	// X-EvoClaw-Signature header, as the webhook channel does.
+	Secret string `json:"secret,omitempty"`
+	// Events lists the event types to send, e.g. "agent.evolved"; a
+	// trailing ".*" matches a prefix, as in "model.*" (empty = all).
+	Events []string `json:"events,omitempty"`
+	// Format is "json" (default), the event as is, or "slack", a
+	// {"text": ...} message for Slack-compatible incoming webhooks.
+	Format string `json:"format,omitempty"`
+}
+
+// ModelParams are the sampling parameters sent with each chat request. Unset
+// values fall back to the next level: agent, then models.defaults, then the
+// built-in defaults (4096 max tokens, temperature 0.7, provider top_p).
+type ModelParams struct {
+	MaxTokens   int      `json:"maxTokens,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"topP,omitempty"`
+}
+
+// Float returns a pointer to v, for setting optional ModelParams fields.
+func Float(v float64) *float64 { return &v }
 
 type ModelHealthConfig struct {
 	PersistPath      string `json:"persistPath"`
@@ -267,15 +550,32 @@ type ProviderConfig struct {
 	BaseURL string  `json:"baseUrl"`
 	APIKey  string  `json:"apiKey"`
 	Models  []Model `json:"models"`
+
+	// TimeoutMs bounds each call to the provider; 0 leaves it to the
+	// provider's HTTP client. A model's own TimeoutMs takes precedence.
+	TimeoutMs int `json:"timeoutMs,omitempty"`
+	// MaxConcurrent caps simultaneous calls to the provider (0 = no cap).
+	// Calls over the cap wait up to QueueTimeoutMs for a slot, then fail.
+	MaxConcurrent  int `json:"maxConcurrent,omitempty"`
+	QueueTimeoutMs int `json:"queueTimeoutMs,omitempty"`
+	// DiscoverModels lists the provider's models from its API (/models for
+	// OpenAI-compatible providers, /api/tags for Ollama) at startup and
+	// every ModelRefreshSec (default 3600). Models configured above are
+	// kept and override discovered ones of the same ID, so pricing and
+	// context windows still come from config. If discovery fails, the
+	// previous list stays in use.
+	DiscoverModels  bool `json:"discoverModels,omitempty"`
+	ModelRefreshSec int  `json:"modelRefreshSec,omitempty"`
 }
 
 type Model struct {
 	ID            string   `json:"id"`
 	Name          string   `json:"name"`
 	ContextWindow int      `json:"contextWindow"`
-	CostInput     float64  `json:"costInput"`    // per million tokens
-	CostOutput    float64  `json:"costOutput"`   // per million tokens
-	Capabilities  []string `json:"capabilities"` // "reasoning", "code", "vision"
+	CostInput     float64  `json:"costInput"`           // per million tokens
+	CostOutput    float64  `json:"costOutput"`          // per million tokens
+	Capabilities  []string `json:"capabilities"`        // "reasoning", "code", "vision"
+	TimeoutMs     int      `json:"timeoutMs,omitempty"` // overrides the provider's timeoutMs
 }
 
 type ModelRouting struct {
@@ -285,33 +585,112 @@ type ModelRouting struct {
 	Complex string `json:"complex"`
 	// Critical tasks (trading, money) use best available
 	Critical string `json:"critical"`
+	// EmergencyLocal is a local Ollama model ("ollama/<model>") used as a
+	// last resort when every routed cloud model is degraded
+	EmergencyLocal string `json:"emergencyLocal,omitempty"`
 }
 
 type EvolutionConfig struct {
 	Enabled bool `json:"enabled"`
 	// How often to evaluate agent performance (seconds)
 	EvalIntervalSec int `json:"evalIntervalSec"`
+	// EvalJitterSec spreads evaluations out: the first round starts after
+	// a random delay of up to this many seconds, and the agents in each
+	// round are evaluated spread over the same range. It is capped at
+	// EvalIntervalSec. 0 evaluates every agent at once on the interval.
+	EvalJitterSec int `json:"evalJitterSec,omitempty"`
 	// Minimum trades/actions before evaluation
 	MinSamplesForEval int `json:"minSamplesForEval"`
 	// Maximum strategy mutation rate (0.0 - 1.0)
 	MaxMutationRate float64 `json:"maxMutationRate"`
+	// Reject genomes without a valid constraint signature and refuse to
+	// mutate them. When false, unsigned genomes are allowed with a warning.
+	RequireSignedGenomes bool `json:"requireSignedGenomes,omitempty"`
+	// OwnerPublicKey is the owner's Ed25519 public key (base64 in JSON).
+	// Constraint signatures are only trusted if made with this key; the
+	// key a genome carries must match it. Without it signed genomes are
+	// treated as unsigned. Required with RequireSignedGenomes.
+	OwnerPublicKey []byte `json:"ownerPublicKey,omitempty"`
+	// Thrash detection: an agent reverted ThrashMaxReverts times within
+	// ThrashWindowSec stops mutating for ThrashCoolOffSec. Zero uses the
+	// defaults (3 reverts, 1 hour window, 6 hour cool-off).
+	ThrashMaxReverts int `json:"thrashMaxReverts,omitempty"`
+	ThrashWindowSec  int `json:"thrashWindowSec,omitempty"`
+	ThrashCoolOffSec int `json:"thrashCoolOffSec,omitempty"`
+	// Seed, when non-zero, seeds the evolution engine's random source so
+	// that mutations are reproducible given the same inputs.
+	Seed int64 `json:"seed,omitempty"`
+	// MinFitness is the fitness below which an agent or skill evolves.
+	// MinFitnessByType and MinFitnessBySkill override it for agent types
+	// and skill names; a skill's genome min_fitness overrides them all.
+	// Zero means unset and falls back a level (0.6 when nothing is set).
+	MinFitness        float64            `json:"minFitness,omitempty"`
+	MinFitnessByType  map[string]float64 `json:"minFitnessByType,omitempty"`
+	MinFitnessBySkill map[string]float64 `json:"minFitnessBySkill,omitempty"`
+
+	// SkillLearning distills failed agent interactions into skillbank
+	// skills and mistakes that are added to later prompts.
+	SkillLearning SkillLearningConfig `json:"skillLearning,omitempty"`
+}
+
+// SkillLearningConfig controls learning from failures at runtime.
+type SkillLearningConfig struct {
+	Enabled bool `json:"enabled"`
+	// Model distills the failures, as "provider/model". Required when
+	// enabled.
+	Model string `json:"model,omitempty"`
+	// MinFailures failures trigger a distillation (0 = 5).
+	MinFailures int `json:"minFailures,omitempty"`
+	// IntervalSec also distills whatever failures have accumulated on this
+	// interval, so a few failures are not held forever (0 = 3600).
+	IntervalSec int `json:"intervalSec,omitempty"`
 }
 
 type AgentDef struct {
 	ID           string          `json:"id"`
 	Name         string          `json:"name"`
-	Type         string          `json:"type"` // "orchestrator", "trader", "monitor", "governance"
+	Type         string          `json:"type"` // e.g. "trader", "assistant", "sensor", "researcher"; see orchestrator.RegisterAgentType
 	Model        string          `json:"model"`
 	SystemPrompt string          `json:"systemPrompt"`
 	Skills       []string        `json:"skills"`
-	Capabilities []string        `json:"capabilities,omitempty"`
+	Capabilities Capabilities    `json:"capabilities,omitempty"`
 	Genome       *Genome         `json:"genome,omitempty"`
 	Config       map[string]string `json:"config,omitempty"`
 	Remote       bool            `json:"remote,omitempty"` // true if agent runs remotely via MQTT
+	// AllowedTools, when set, limits the agent's tool loop to these tools.
+	// DeniedTools are never offered to or executed for the agent.
+	AllowedTools []string `json:"allowedTools,omitempty"`
+	DeniedTools  []string `json:"deniedTools,omitempty"`
+	// CostBudgetUSD caps the agent's LLM spend per server.budgetPeriod
+	// (0 = unlimited).
+	CostBudgetUSD float64 `json:"costBudgetUsd,omitempty"`
+	// NoResponseCache sends every request to the provider even when
+	// models.responseCache is enabled.
+	NoResponseCache bool `json:"noResponseCache,omitempty"`
+	// PinModel always uses the agent's preferred model, skipping health
+	// routing. Requests fail while that model is unhealthy.
+	PinModel bool `json:"pinModel,omitempty"`
+	// IdleTimeoutSec marks the agent cold after this long without a
+	// message, releasing its model's cached responses and, for Ollama,
+	// unloading the model (0 = never).
+	IdleTimeoutSec int `json:"idleTimeoutSec,omitempty"`
+	// Shadow runs a candidate model alongside the agent's model for
+	// comparison before a swap.
+	Shadow ShadowConfig `json:"shadow,omitempty"`
+	// Sampling parameters (maxTokens, temperature, topP) for this agent.
+	ModelParams
 	// Container isolation settings
 	Container ContainerConfig `json:"container"`
 }
 
+// ShadowConfig sends each chat request also to Model ("provider/model") in
+// the background. The shadow answer is never returned; its latency, tokens
+// and similarity to the real answer are logged to <dataDir>/shadow.jsonl.
+type ShadowConfig struct {
+	Enabled bool   `json:"enabled"`
+	Model   string `json:"model,omitempty"`
+}
+
 // Genome defines the complete genetic makeup of an agent
 // This is re-exported from internal/genome for convenience
 type Genome struct {
@@ -342,6 +721,8 @@ type SkillGenome struct {
 	EvalCount    int                    `json:"eval_count,omitempty"`
 	Verified     bool                   `json:"verified,omitempty"`
 	VFMScore     float64                `json:"vfm_score,omitempty"`
+	// MinFitness, when set, is this skill's evolution threshold.
+	MinFitness float64 `json:"min_fitness,omitempty"`
 }
 
 // GenomeBehavior defines behavioral traits
@@ -361,6 +742,12 @@ type GenomeConstraints struct {
 	BlockedActions []string `json:"blocked_actions,omitempty"`
 	MaxDivergence  float64  `json:"max_divergence,omitempty"`
 	MinVFMScore    float64  `json:"min_vfm_score,omitempty"`
+	// Rules are free-text guardrails ("always ask before irreversible
+	// actions") rendered into the agent's system prompt.
+	Rules []string `json:"rules,omitempty"`
+	// ForbiddenPatterns are regular expressions a response must not match;
+	// matching responses are flagged.
+	ForbiddenPatterns []string `json:"forbidden_patterns,omitempty"`
 }
 
 type ContainerConfig struct {
@@ -413,17 +800,24 @@ func DefaultConfig() *Config {
 	}
 }
 
-// Load reads config from a JSON file
+// Load reads config from a JSON, YAML or TOML file. The format is detected
+// from the file extension.
 func Load(path string) (*Config, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read config: %w", err)
 	}
 
 	cfg := DefaultConfig()
-	if err := json.Unmarshal(data, cfg); err != nil {
+	if err := decode(data, format, cfg); err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
+	cfg.applyOffline()
 
 	// Ensure data directory exists
 	if err := os.MkdirAll(cfg.Server.DataDir, 0750); err != nil {
@@ -433,14 +827,20 @@ func Load(path string) (*Config, error) {
 	return cfg, nil
 }
 
-// Save writes config to a JSON file
+// Save writes config to path in the format implied by its extension
 func (c *Config) Save(path string) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0750); err != nil {
 		return fmt.Errorf("create config dir: %w", err)
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	// Settings forced off by server.offline are saved as configured.
+	data, err := encode(c.withoutOffline(), format)
 	if err != nil {
 		return fmt.Errorf("marshal config: %w", err)
 	}
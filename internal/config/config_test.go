@@ -441,7 +441,7 @@ func TestSave_WriteFileError(t *testing.T) {
 	
 	// Try to write to a path that is a directory
 	tmpDir := t.TempDir()
-	dirPath := filepath.Join(tmpDir, "testdir")
+	dirPath := filepath.Join(tmpDir, "testdir.json")
 	_ = os.Mkdir(dirPath, 0755)
 	
 	// Try to write to the directory itself (not a file in it)
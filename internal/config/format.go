@@ -0,0 +1,159 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies the on-disk encoding of a config file.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// FormatFromPath detects the config format from the file extension.
+// Supported extensions are .json, .yaml, .yml and .toml.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported config format %q (want .json, .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+}
+
+// decode parses data in the given format into cfg. YAML and TOML documents
+// are converted to JSON first so every format shares the struct's json tags
+// and fields absent from the file keep their current (default) values.
+func decode(data []byte, format Format, cfg *Config) error {
+	if format == FormatJSON {
+		return json.Unmarshal(data, cfg)
+	}
+	doc, err := decodeDoc(data, format)
+	if err != nil {
+		return err
+	}
+	return applyDoc(doc, cfg)
+}
+
+// decodeDoc parses data in the given format into a generic document keyed
+// by the config's json tag names.
+func decodeDoc(data []byte, format Format) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	case FormatTOML:
+		if _, err := toml.Decode(string(data), &doc); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+	return doc, nil
+}
+
+// applyDoc overlays a generic document onto cfg.
+func applyDoc(doc map[string]interface{}, cfg *Config) error {
+	if doc == nil {
+		return nil
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, cfg)
+}
+
+// encode serialises cfg in the given format, using the json tag names as keys.
+func encode(cfg *Config, format Format) ([]byte, error) {
+	raw, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatJSON {
+		return raw, nil
+	}
+
+	var doc map[string]interface{}
+	if err := decodeJSONNumbers(raw, &doc); err != nil {
+		return nil, err
+	}
+	return encodeDoc(doc, format)
+}
+
+// encodeDoc serialises a generic document in the given format.
+func encodeDoc(doc map[string]interface{}, format Format) ([]byte, error) {
+	doc = normalizeDoc(doc).(map[string]interface{})
+
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(doc, "", "  ")
+	case FormatYAML:
+		return yaml.Marshal(doc)
+	case FormatTOML:
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(doc); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+}
+
+// decodeJSONNumbers unmarshals JSON into v keeping numbers as json.Number,
+// so integers survive a round trip through a generic document.
+func decodeJSONNumbers(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// normalizeDoc converts json.Number values to int64/float64 and drops null
+// map entries, which TOML cannot represent.
+func normalizeDoc(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if child == nil {
+				delete(val, k)
+				continue
+			}
+			val[k] = normalizeDoc(child)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = normalizeDoc(child)
+		}
+		return val
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	default:
+		return v
+	}
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func roundTripConfig(dataDir string) *Config {
+	cfg := DefaultConfig()
+	cfg.Server.Port = 9100
+	cfg.Server.DataDir = dataDir
+	cfg.Server.LogLevel = "debug"
+	cfg.MQTT.Username = "edge"
+	cfg.Channels.Telegram = &TelegramConfig{
+		Enabled:      true,
+		BotToken:     "tg-token",
+		AllowedUsers: []int64{42, 99},
+	}
+	cfg.Models.Providers = map[string]ProviderConfig{
+		"ollama": {
+			BaseURL: "http://localhost:11434",
+			Models: []Model{
+				{ID: "llama3", Name: "Llama 3", ContextWindow: 8192, CostInput: 0.5, Capabilities: []string{"code"}},
+			},
+		},
+	}
+	cfg.Chains = map[string]ChainConfig{
+		"bsc-testnet": {Enabled: true, Type: "evm", RPCURL: "https://rpc.example", ChainID: 97},
+	}
+	cfg.Scheduler = SchedulerConfig{
+		Enabled: true,
+		Jobs: []SchedulerJobConfig{
+			{
+				ID:       "ping",
+				Schedule: ScheduleConfig{Kind: "interval", IntervalMs: 60000},
+				Action:   ActionConfig{Kind: "http", URL: "https://example.com", Headers: map[string]string{"X-Test": "1"}},
+				Enabled:  true,
+			},
+		},
+	}
+	cfg.Agents = []AgentDef{
+		{
+			ID:     "trader-1",
+			Name:   "Trader",
+			Type:   "trader",
+			Model:  "ollama/llama3",
+			Skills: []string{"trading"},
+			Genome: &Genome{
+				Identity: GenomeIdentity{Name: "T", Persona: "careful"},
+				Skills: map[string]SkillGenome{
+					"trading": {Enabled: true, Weight: 0.7, Params: map[string]interface{}{"threshold": 0.25}, Version: 2},
+				},
+				Behavior: GenomeBehavior{RiskTolerance: 0.3, Verbosity: 0.5, Autonomy: 0.8},
+			},
+			Config:    map[string]string{"region": "eu"},
+			Container: ContainerConfig{MemoryMB: 256, CPUShares: 512},
+		},
+	}
+	return cfg
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    Format
+		wantErr bool
+	}{
+		{"evoclaw.json", FormatJSON, false},
+		{"evoclaw.yaml", FormatYAML, false},
+		{"evoclaw.YML", FormatYAML, false},
+		{"config.toml", FormatTOML, false},
+		{"config.ini", "", true},
+		{"config", "", true},
+	}
+	for _, tt := range tests {
+		got, err := FormatFromPath(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("FormatFromPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("FormatFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestConfigRoundTripFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+	dataDir := filepath.Join(tmpDir, "data")
+
+	// JSON is the reference format; every other format must load to the same Config.
+	refPath := filepath.Join(tmpDir, "ref.json")
+	if err := roundTripConfig(dataDir).Save(refPath); err != nil {
+		t.Fatalf("save reference: %v", err)
+	}
+	want, err := Load(refPath)
+	if err != nil {
+		t.Fatalf("load reference: %v", err)
+	}
+
+	for _, ext := range []string{".json", ".yaml", ".yml", ".toml"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(tmpDir, "config"+ext)
+			if err := roundTripConfig(dataDir).Save(path); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			got, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round-trip mismatch for %s:\n got  %+v\n want %+v", ext, got, want)
+			}
+		})
+	}
+}
+
+func TestSaveWritesSourceFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := DefaultConfig()
+
+	yamlPath := filepath.Join(tmpDir, "config.yaml")
+	if err := cfg.Save(yamlPath); err != nil {
+		t.Fatalf("Save yaml: %v", err)
+	}
+	data, _ := os.ReadFile(yamlPath)
+	if !strings.Contains(string(data), "port: 8420") {
+		t.Errorf("expected YAML output, got:\n%s", data)
+	}
+
+	tomlPath := filepath.Join(tmpDir, "config.toml")
+	if err := cfg.Save(tomlPath); err != nil {
+		t.Fatalf("Save toml: %v", err)
+	}
+	data, _ = os.ReadFile(tomlPath)
+	if !strings.Contains(string(data), "[server]") || !strings.Contains(string(data), "port = 8420") {
+		t.Errorf("expected TOML output, got:\n%s", data)
+	}
+}
+
+func TestLoadPartialYAMLAndTOMLKeepDefaults(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"partial.yaml": "server:\n  port: 5555\n  dataDir: " + filepath.Join(tmpDir, "y") + "\n",
+		"partial.toml": "[server]\nport = 5555\ndataDir = \"" + filepath.Join(tmpDir, "t") + "\"\n",
+	}
+	for name, body := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(body), 0640); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load %s: %v", name, err)
+		}
+		if cfg.Server.Port != 5555 {
+			t.Errorf("%s: expected port 5555, got %d", name, cfg.Server.Port)
+		}
+		if cfg.MQTT.Port != 1883 || cfg.Server.LogLevel != "info" {
+			t.Errorf("%s: expected defaults to be preserved, got %+v", name, cfg.Server)
+		}
+	}
+}
+
+func TestLoadUnknownExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(path, []byte("port=1"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "unsupported config format") {
+		t.Fatalf("expected unsupported format error, got %v", err)
+	}
+	if err := DefaultConfig().Save(path); err == nil {
+		t.Error("expected Save to reject unknown extension")
+	}
+}
+
+func TestLoadInvalidYAMLAndTOML(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, body := range map[string]string{
+		"bad.yaml": "server: [unclosed",
+		"bad.toml": "[server\nport = ",
+	} {
+		path := filepath.Join(tmpDir, name)
+		_ = os.WriteFile(path, []byte(body), 0640)
+		if _, err := Load(path); err == nil {
+			t.Errorf("expected parse error for %s", name)
+		}
+	}
+}
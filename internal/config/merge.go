@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LoadMerged loads and deep-merges several config files in order. Later
+// files override scalar fields of earlier ones, map entries (providers,
+// chains, …) are merged key by key, and lists whose entries carry an "id"
+// (agents, scheduler jobs, provider models) are merged entry by entry on
+// that ID. Any other list is replaced wholesale.
+//
+// A path that names a directory expands to the supported config files it
+// contains, in lexical order, so "conf.d"-style layouts work out of the box.
+func LoadMerged(paths ...string) (*Config, error) {
+	files, err := expandConfigPaths(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no config files to load")
+	}
+
+	merged := map[string]interface{}{}
+	for _, path := range files {
+		format, err := FormatFromPath(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config %s: %w", path, err)
+		}
+		doc, err := decodeDoc(data, format)
+		if err != nil {
+			return nil, fmt.Errorf("parse config %s: %w", path, err)
+		}
+		merged = mergeDocs(merged, doc)
+	}
+
+	cfg := DefaultConfig()
+	if err := applyDoc(merged, cfg); err != nil {
+		return nil, fmt.Errorf("apply merged config: %w", err)
+	}
+	cfg.applyOffline()
+
+	if err := os.MkdirAll(cfg.Server.DataDir, 0750); err != nil {
+		return nil, fmt.Errorf("create data dir: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// expandConfigPaths replaces directories with the config files they contain.
+func expandConfigPaths(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config dir %s: %w", path, err)
+		}
+		var dirFiles []string
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			if _, err := FormatFromPath(e.Name()); err != nil {
+				continue
+			}
+			dirFiles = append(dirFiles, filepath.Join(path, e.Name()))
+		}
+		sort.Strings(dirFiles)
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
+
+// mergeDocs deep-merges src into dst and returns dst.
+func mergeDocs(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = map[string]interface{}{}
+	}
+	for k, sv := range src {
+		dv, exists := dst[k]
+		if !exists {
+			dst[k] = sv
+			continue
+		}
+		dst[k] = mergeValues(dv, sv)
+	}
+	return dst
+}
+
+func mergeValues(dst, src interface{}) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		if d, ok := dst.(map[string]interface{}); ok {
+			return mergeDocs(d, s)
+		}
+	case []interface{}:
+		if d, ok := dst.([]interface{}); ok && isIDList(d) && isIDList(s) {
+			return mergeByID(d, s)
+		}
+	}
+	return src
+}
+
+// isIDList reports whether every element of list is an object with a string "id".
+func isIDList(list []interface{}) bool {
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := m["id"].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeByID merges entries of src into dst by their "id" field. Matching
+// entries are deep-merged in place; new entries are appended in src order.
+func mergeByID(dst, src []interface{}) []interface{} {
+	index := make(map[string]int, len(dst))
+	for i, item := range dst {
+		index[item.(map[string]interface{})["id"].(string)] = i
+	}
+	for _, item := range src {
+		m := item.(map[string]interface{})
+		id := m["id"].(string)
+		if i, ok := index[id]; ok {
+			dst[i] = mergeDocs(dst[i].(map[string]interface{}), m)
+			continue
+		}
+		index[id] = len(dst)
+		dst = append(dst, m)
+	}
+	return dst
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(body), 0640); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadMergedScalarOverride(t *testing.T) {
+	dir := t.TempDir()
+	base := writeConfigFile(t, dir, "base.json", `{
+		"server": {"port": 8000, "dataDir": "`+filepath.Join(dir, "data")+`", "logLevel": "info"},
+		"mqtt": {"host": "broker.local", "port": 1883}
+	}`)
+	override := writeConfigFile(t, dir, "device.yaml", "server:\n  port: 9000\n")
+
+	cfg, err := LoadMerged(base, override)
+	if err != nil {
+		t.Fatalf("LoadMerged: %v", err)
+	}
+	if cfg.Server.Port != 9000 {
+		t.Errorf("expected overridden port 9000, got %d", cfg.Server.Port)
+	}
+	if cfg.Server.LogLevel != "info" || cfg.MQTT.Host != "broker.local" {
+		t.Errorf("expected untouched base fields, got server=%+v mqtt=%+v", cfg.Server, cfg.MQTT)
+	}
+}
+
+func TestLoadMergedProviderMapMerge(t *testing.T) {
+	dir := t.TempDir()
+	base := writeConfigFile(t, dir, "base.json", `{
+		"server": {"dataDir": "`+filepath.Join(dir, "data")+`"},
+		"models": {"providers": {
+			"anthropic": {"baseUrl": "https://api.anthropic.com", "apiKey": "base-key"},
+			"ollama": {"baseUrl": "http://localhost:11434"}
+		}},
+		"chains": {"bsc": {"enabled": true, "type": "evm", "chainId": 56}}
+	}`)
+	override := writeConfigFile(t, dir, "device.toml", `
+[models.providers.anthropic]
+apiKey = "device-key"
+
+[models.providers.openai]
+baseUrl = "https://api.openai.com/v1"
+
+[chains.opbnb]
+enabled = true
+type = "evm"
+chainId = 204
+`)
+
+	cfg, err := LoadMerged(base, override)
+	if err != nil {
+		t.Fatalf("LoadMerged: %v", err)
+	}
+	providers := cfg.Models.Providers
+	if len(providers) != 3 {
+		t.Fatalf("expected 3 providers, got %d: %+v", len(providers), providers)
+	}
+	if providers["anthropic"].APIKey != "device-key" {
+		t.Errorf("expected anthropic key override, got %q", providers["anthropic"].APIKey)
+	}
+	if providers["anthropic"].BaseURL != "https://api.anthropic.com" {
+		t.Errorf("expected anthropic baseUrl kept, got %q", providers["anthropic"].BaseURL)
+	}
+	if providers["ollama"].BaseURL != "http://localhost:11434" {
+		t.Errorf("expected ollama provider kept, got %+v", providers["ollama"])
+	}
+	if len(cfg.Chains) != 2 || cfg.Chains["opbnb"].ChainID != 204 || cfg.Chains["bsc"].ChainID != 56 {
+		t.Errorf("expected both chains, got %+v", cfg.Chains)
+	}
+}
+
+func TestLoadMergedAgentsByID(t *testing.T) {
+	dir := t.TempDir()
+	base := writeConfigFile(t, dir, "base.json", `{
+		"server": {"dataDir": "`+filepath.Join(dir, "data")+`"},
+		"agents": [
+			{"id": "a1", "name": "Alpha", "type": "trader", "model": "anthropic/claude-sonnet", "skills": ["trading"]},
+			{"id": "a2", "name": "Beta", "type": "monitor", "model": "ollama/llama3"}
+		]
+	}`)
+	override := writeConfigFile(t, dir, "device.json", `{
+		"agents": [
+			{"id": "a2", "model": "ollama/qwen"},
+			{"id": "a3", "name": "Gamma", "type": "governance"}
+		]
+	}`)
+
+	cfg, err := LoadMerged(base, override)
+	if err != nil {
+		t.Fatalf("LoadMerged: %v", err)
+	}
+	if len(cfg.Agents) != 3 {
+		t.Fatalf("expected 3 agents, got %d", len(cfg.Agents))
+	}
+	byID := map[string]AgentDef{}
+	for _, a := range cfg.Agents {
+		byID[a.ID] = a
+	}
+	if byID["a1"].Model != "anthropic/claude-sonnet" || len(byID["a1"].Skills) != 1 {
+		t.Errorf("expected a1 untouched, got %+v", byID["a1"])
+	}
+	if byID["a2"].Model != "ollama/qwen" || byID["a2"].Name != "Beta" {
+		t.Errorf("expected a2 model overridden and name kept, got %+v", byID["a2"])
+	}
+	if byID["a3"].Name != "Gamma" {
+		t.Errorf("expected a3 appended, got %+v", byID["a3"])
+	}
+	if cfg.Agents[0].ID != "a1" || cfg.Agents[2].ID != "a3" {
+		t.Errorf("expected base order preserved with new agents appended, got %v", []string{cfg.Agents[0].ID, cfg.Agents[1].ID, cfg.Agents[2].ID})
+	}
+}
+
+func TestLoadMergedDirectory(t *testing.T) {
+	dir := t.TempDir()
+	confDir := filepath.Join(dir, "conf.d")
+	if err := os.Mkdir(confDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	writeConfigFile(t, confDir, "10-base.json", `{"server": {"port": 1000, "dataDir": "`+filepath.Join(dir, "data")+`"}}`)
+	writeConfigFile(t, confDir, "20-device.yaml", "server:\n  port: 2000\n")
+	writeConfigFile(t, confDir, "README.md", "ignored")
+
+	cfg, err := LoadMerged(confDir)
+	if err != nil {
+		t.Fatalf("LoadMerged: %v", err)
+	}
+	if cfg.Server.Port != 2000 {
+		t.Errorf("expected later file to win, got port %d", cfg.Server.Port)
+	}
+}
+
+func TestLoadMergedErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadMerged(); err == nil {
+		t.Error("expected error for no paths")
+	}
+	if _, err := LoadMerged(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+	bad := writeConfigFile(t, dir, "bad.json", "{not json")
+	if _, err := LoadMerged(bad); err == nil {
+		t.Error("expected parse error")
+	}
+}
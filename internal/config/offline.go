@@ -0,0 +1,89 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// offlineSwitches returns the settings server.offline forces off, keyed by
+// config path. Chains are handled separately as their map values are not
+// addressable. LLM providers, channels and the MQTT broker are left alone:
+// an offline deployment points those at local services.
+func (c *Config) offlineSwitches() map[string]*bool {
+	s := map[string]*bool{
+		"onchain.enabled":        &c.OnChain.Enabled,
+		"clawchain.autoDiscover": &c.ClawChain.AutoDiscover,
+		"cloudSync.enabled":      &c.CloudSync.Enabled,
+		// The memory system keeps its cold tier in Turso and cannot run
+		// without it.
+		"memory.enabled":  &c.Memory.Enabled,
+		"tracing.enabled": &c.Tracing.Enabled,
+		"cloud.enabled":   &c.Cloud.Enabled,
+	}
+	if c.Updates != nil {
+		s["updates.enabled"] = &c.Updates.Enabled
+		s["updates.autoInstall"] = &c.Updates.AutoInstall
+	}
+	return s
+}
+
+// applyOffline forces off every feature that makes outbound network calls
+// when Server.Offline is set, whatever its own setting, and records what it
+// turned off for OfflineDisabled.
+func (c *Config) applyOffline() {
+	c.offlineDisabled = nil
+	if !c.Server.Offline {
+		return
+	}
+	for field, enabled := range c.offlineSwitches() {
+		if *enabled {
+			*enabled = false
+			c.offlineDisabled = append(c.offlineDisabled, field)
+		}
+	}
+	for name, chain := range c.Chains {
+		if chain.Enabled {
+			chain.Enabled = false
+			c.Chains[name] = chain
+			c.offlineDisabled = append(c.offlineDisabled, "chains."+name+".enabled")
+		}
+	}
+	sort.Strings(c.offlineDisabled)
+}
+
+// withoutOffline returns a copy of c with the settings applyOffline forced
+// off switched back on, so saving a config never persists them.
+func (c *Config) withoutOffline() *Config {
+	if len(c.offlineDisabled) == 0 {
+		return c
+	}
+	cp := *c
+	if c.Updates != nil {
+		u := *c.Updates
+		cp.Updates = &u
+	}
+	cp.Chains = make(map[string]ChainConfig, len(c.Chains))
+	for name, chain := range c.Chains {
+		cp.Chains[name] = chain
+	}
+
+	switches := cp.offlineSwitches()
+	for _, field := range c.offlineDisabled {
+		if enabled, ok := switches[field]; ok {
+			*enabled = true
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(field, "chains."), ".enabled")
+		if chain, ok := cp.Chains[name]; ok {
+			chain.Enabled = true
+			cp.Chains[name] = chain
+		}
+	}
+	return &cp
+}
+
+// OfflineDisabled returns the settings server.offline forced off when the
+// config was loaded, for logging at startup.
+func (c *Config) OfflineDisabled() []string {
+	return c.offlineDisabled
+}
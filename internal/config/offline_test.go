@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func offlineTestConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.Server.Offline = true
+	cfg.OnChain.Enabled = true
+	cfg.Chains = map[string]ChainConfig{
+		"bsc":      {Enabled: true, Type: "evm", RPCURL: "https://bsc.example"},
+		"disabled": {Type: "evm"},
+	}
+	cfg.ClawChain.AutoDiscover = true
+	cfg.CloudSync = CloudSyncConfig{Enabled: true, DatabaseURL: "libsql://db.example", AuthToken: "t"}
+	cfg.Memory.Enabled = true
+	cfg.Tracing.Enabled = true
+	cfg.Updates = &UpdatesConfig{Enabled: true}
+	return cfg
+}
+
+func TestLoad_OfflineForcesNetworkFeaturesOff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evoclaw.json")
+	cfg := offlineTestConfig()
+	cfg.Server.DataDir = filepath.Join(t.TempDir(), "data")
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.OnChain.Enabled || loaded.Chains["bsc"].Enabled || loaded.ClawChain.AutoDiscover ||
+		loaded.CloudSync.Enabled || loaded.Memory.Enabled || loaded.Tracing.Enabled || loaded.Updates.Enabled {
+		t.Errorf("network feature left on in offline mode: %+v", loaded)
+	}
+	want := []string{
+		"chains.bsc.enabled", "clawchain.autoDiscover", "cloudSync.enabled", "memory.enabled",
+		"onchain.enabled", "tracing.enabled", "updates.enabled",
+	}
+	if got := loaded.OfflineDisabled(); !reflect.DeepEqual(got, want) {
+		t.Errorf("OfflineDisabled() = %v, want %v", got, want)
+	}
+
+	// Saving writes the individual settings as configured.
+	if err := loaded.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := DefaultConfig()
+	if err := decode(data, FormatJSON, saved); err != nil {
+		t.Fatal(err)
+	}
+	if !saved.CloudSync.Enabled || !saved.Chains["bsc"].Enabled || saved.Chains["disabled"].Enabled {
+		t.Errorf("saved config lost individual settings: cloudSync=%v chains=%+v", saved.CloudSync.Enabled, saved.Chains)
+	}
+	if loaded.CloudSync.Enabled || loaded.Chains["bsc"].Enabled {
+		t.Error("saving re-enabled features in the loaded config")
+	}
+}
+
+func TestLoad_OnlineLeavesSettingsAlone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evoclaw.json")
+	cfg := offlineTestConfig()
+	cfg.Server.Offline = false
+	cfg.Server.DataDir = filepath.Join(t.TempDir(), "data")
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.CloudSync.Enabled || !loaded.OnChain.Enabled || len(loaded.OfflineDisabled()) != 0 {
+		t.Errorf("online config changed: cloudSync=%v onchain=%v disabled=%v",
+			loaded.CloudSync.Enabled, loaded.OnChain.Enabled, loaded.OfflineDisabled())
+	}
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotPatchable is returned by Patch for settings that cannot be changed
+// at runtime through it.
+var ErrNotPatchable = errors.New("setting cannot be changed at runtime")
+
+// patchableKeys maps each setting Patch accepts, by its JSON path, to the
+// function that sets it on a config.
+var patchableKeys = map[string]func(c *Config, v json.RawMessage) error{
+	"server.logLevel":               func(c *Config, v json.RawMessage) error { return json.Unmarshal(v, &c.Server.LogLevel) },
+	"evolution.enabled":             func(c *Config, v json.RawMessage) error { return json.Unmarshal(v, &c.Evolution.Enabled) },
+	"models.defaults.temperature":   func(c *Config, v json.RawMessage) error { return json.Unmarshal(v, &c.Models.Defaults.Temperature) },
+	"models.routing.simple":         func(c *Config, v json.RawMessage) error { return json.Unmarshal(v, &c.Models.Routing.Simple) },
+	"models.routing.complex":        func(c *Config, v json.RawMessage) error { return json.Unmarshal(v, &c.Models.Routing.Complex) },
+	"models.routing.critical":       func(c *Config, v json.RawMessage) error { return json.Unmarshal(v, &c.Models.Routing.Critical) },
+	"models.routing.emergencyLocal": func(c *Config, v json.RawMessage) error { return json.Unmarshal(v, &c.Models.Routing.EmergencyLocal) },
+}
+
+// PatchableKeys returns the settings Patch accepts, sorted.
+func PatchableKeys() []string {
+	keys := make([]string, 0, len(patchableKeys))
+	for k := range patchableKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// patchMu serialises Patch's read-modify-write of the config file.
+var patchMu sync.Mutex
+
+// Patch sets the given settings, keyed by JSON path (e.g.
+// "models.routing.simple"), in the config file at path and then reloads it
+// into c as SIGHUP does. Only the keys listed by PatchableKeys are
+// accepted; if any other key is present nothing is changed. Only the
+// patched keys are rewritten: settings the file leaves unset stay unset
+// rather than being filled in with defaults. The patched file must pass
+// Validate before it is written.
+func (c *Config) Patch(path string, updates map[string]json.RawMessage) (*ReloadResult, error) {
+	var rejected []string
+	for key := range updates {
+		if _, ok := patchableKeys[key]; !ok {
+			rejected = append(rejected, key)
+		}
+	}
+	if len(rejected) > 0 {
+		sort.Strings(rejected)
+		return nil, fmt.Errorf("%w: %s", ErrNotPatchable, strings.Join(rejected, ", "))
+	}
+
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	patchMu.Lock()
+	defer patchMu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config for patch: %w", err)
+	}
+	fileCfg := DefaultConfig()
+	if err := decode(data, format, fileCfg); err != nil {
+		return nil, fmt.Errorf("parse config for patch: %w", err)
+	}
+	var doc map[string]interface{}
+	if format == FormatJSON {
+		err = decodeJSONNumbers(data, &doc)
+	} else {
+		doc, err = decodeDoc(data, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config for patch: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+
+	for key, value := range updates {
+		if err := patchableKeys[key](fileCfg, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		var v interface{}
+		if err := decodeJSONNumbers(value, &v); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if err := setDocPath(doc, key, v); err != nil {
+			return nil, err
+		}
+	}
+	if err := fileCfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	out, err := encodeDoc(doc, format)
+	if err != nil {
+		return nil, fmt.Errorf("marshal patched config: %w", err)
+	}
+	if err := os.WriteFile(path, out, 0640); err != nil {
+		return nil, fmt.Errorf("save patched config: %w", err)
+	}
+
+	return c.Reload(path)
+}
+
+// setDocPath sets the dotted JSON path key in doc to v, creating any
+// missing parent objects.
+func setDocPath(doc map[string]interface{}, key string, v interface{}) error {
+	parts := strings.Split(key, ".")
+	node := doc
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := node[part]
+		if !ok || child == nil {
+			next := map[string]interface{}{}
+			node[part] = next
+			node = next
+			continue
+		}
+		next, ok := child.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %q in the config file is not an object", key, part)
+		}
+		node = next
+	}
+	node[parts[len(parts)-1]] = v
+	return nil
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestPatchAppliesAndPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := DefaultConfig()
+	saveJSON(t, path, cfg)
+
+	result, err := cfg.Patch(path, map[string]json.RawMessage{
+		"models.routing.simple":       json.RawMessage(`"ollama/llama3"`),
+		"models.defaults.temperature": json.RawMessage(`0.4`),
+		"server.logLevel":             json.RawMessage(`"debug"`),
+	})
+	if err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if cfg.Models.Routing.Simple != "ollama/llama3" || *cfg.Models.Defaults.Temperature != 0.4 || cfg.Server.LogLevel != "debug" {
+		t.Errorf("running config not updated: routing=%+v defaults=%+v logLevel=%q",
+			cfg.Models.Routing, cfg.Models.Defaults, cfg.Server.LogLevel)
+	}
+	if len(result.Applied) != 2 {
+		t.Errorf("applied = %v, want Server.LogLevel and Models", result.Applied)
+	}
+
+	saved, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Models.Routing.Simple != "ollama/llama3" || saved.Server.LogLevel != "debug" {
+		t.Errorf("patch not persisted: routing=%+v logLevel=%q", saved.Models.Routing, saved.Server.LogLevel)
+	}
+}
+
+func TestPatchKeepsUnpatchedSettings(t *testing.T) {
+	for _, tc := range []struct {
+		name, file, contents string
+	}{
+		{"json", "config.json", `{"server": {"port": 9000}, "models": {"routing": {"complex": "anthropic/claude"}}}`},
+		{"yaml", "config.yaml", "server:\n  port: 9000\nmodels:\n  routing:\n    complex: anthropic/claude\n"},
+		{"toml", "config.toml", "[server]\nport = 9000\n\n[models.routing]\ncomplex = \"anthropic/claude\"\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			if err := os.WriteFile(path, []byte(tc.contents), 0640); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := cfg.Patch(path, map[string]json.RawMessage{
+				"models.routing.simple": json.RawMessage(`"ollama/llama3"`),
+				"evolution.enabled":     json.RawMessage(`true`),
+			}); err != nil {
+				t.Fatalf("Patch: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, err := decodeDoc(data, Format(tc.name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys := make([]string, 0, len(doc))
+			for k := range doc {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			if strings.Join(keys, ",") != "evolution,models,server" {
+				t.Errorf("top-level keys = %v, want only the file's own and the patched ones", keys)
+			}
+
+			saved, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.Server.Port != 9000 || saved.Models.Routing.Complex != "anthropic/claude" {
+				t.Errorf("unpatched settings changed: port=%d routing=%+v", saved.Server.Port, saved.Models.Routing)
+			}
+			if saved.Models.Routing.Simple != "ollama/llama3" || !saved.Evolution.Enabled {
+				t.Errorf("patch not persisted: routing=%+v evolution=%v", saved.Models.Routing, saved.Evolution.Enabled)
+			}
+		})
+	}
+}
+
+func TestPatchRejectsUnlistedKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := DefaultConfig()
+	saveJSON(t, path, cfg)
+
+	_, err := cfg.Patch(path, map[string]json.RawMessage{
+		"evolution.enabled": json.RawMessage(`false`),
+		"server.port":       json.RawMessage(`9000`),
+	})
+	if !errors.Is(err, ErrNotPatchable) {
+		t.Fatalf("err = %v, want ErrNotPatchable", err)
+	}
+	if !cfg.Evolution.Enabled || cfg.Server.Port != 8420 {
+		t.Error("a rejected patch must not change anything")
+	}
+}
+
+func TestPatchValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := DefaultConfig()
+	saveJSON(t, path, cfg)
+
+	_, err := cfg.Patch(path, map[string]json.RawMessage{"server.logLevel": json.RawMessage(`"loud"`)})
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("err = %v, want ValidationErrors", err)
+	}
+	if _, err := cfg.Patch(path, map[string]json.RawMessage{"evolution.enabled": json.RawMessage(`"no"`)}); err == nil {
+		t.Error("expected error for a value of the wrong type")
+	}
+
+	saved, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Server.LogLevel != "info" || !saved.Evolution.Enabled {
+		t.Error("an invalid patch must not be written")
+	}
+}
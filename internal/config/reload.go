@@ -1,7 +1,6 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
@@ -52,13 +51,18 @@ func RUnlock() { mu.RUnlock() }
 // and applies hot-reloadable changes in place. Fields that require a
 // restart are logged as skipped.
 func (c *Config) Reload(path string) (*ReloadResult, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read config for reload: %w", err)
 	}
 
 	newCfg := DefaultConfig()
-	if err := json.Unmarshal(data, newCfg); err != nil {
+	if err := decode(data, format, newCfg); err != nil {
 		return nil, fmt.Errorf("parse config for reload: %w", err)
 	}
 
@@ -67,6 +71,10 @@ func (c *Config) Reload(path string) (*ReloadResult, error) {
 	mu.Lock()
 	defer mu.Unlock()
 
+	// Offline mode is fixed at startup; keep reloaded sections within it.
+	newCfg.Server.Offline = c.Server.Offline
+	newCfg.applyOffline()
+
 	// Compare and apply each section
 	diffAndApply(c, newCfg, result)
 
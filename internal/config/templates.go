@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// agentTemplates are the built-in agent presets, keyed by template name.
+// Model is left empty so agents use the configured routing unless the
+// caller overrides it.
+var agentTemplates = map[string]AgentDef{
+	"trader": {
+		ID:           "trader",
+		Name:         "Trader",
+		Type:         "trader",
+		SystemPrompt: "You are a disciplined trading agent. Follow your risk limits strictly, explain the reasoning behind every trade, and never act on unverified data.",
+		Skills:       []string{"trading", "analysis", "monitoring"},
+		Capabilities: NewCapabilities("network", "trading"),
+		ModelParams:  ModelParams{Temperature: Float(0.2)},
+		Genome: &Genome{
+			Identity: GenomeIdentity{Name: "trader", Persona: "cautious, analytical", Voice: "concise"},
+			Skills: map[string]SkillGenome{
+				"trading": {Enabled: true, Weight: 1.0, Params: map[string]interface{}{"position_size_usd": 100.0, "max_positions": 3.0}},
+			},
+			Behavior:    GenomeBehavior{RiskTolerance: 0.2, Verbosity: 0.3, Autonomy: 0.4, PromptStyle: "precise and data-driven"},
+			Constraints: GenomeConstraints{MaxLossUSD: 500, MaxDivergence: 0.3},
+		},
+	},
+	"support": {
+		ID:           "support",
+		Name:         "Support",
+		Type:         "orchestrator",
+		SystemPrompt: "You are a friendly customer support agent. Answer clearly, ask for details when a request is ambiguous, and escalate anything you cannot resolve.",
+		Skills:       []string{"chat", "search"},
+		Genome: &Genome{
+			Identity: GenomeIdentity{Name: "support", Persona: "patient, empathetic", Voice: "balanced"},
+			Skills: map[string]SkillGenome{
+				"chat": {Enabled: true, Weight: 1.0, Params: map[string]interface{}{}},
+			},
+			Behavior:    GenomeBehavior{RiskTolerance: 0.1, Verbosity: 0.5, Autonomy: 0.3, PromptStyle: "warm and clear"},
+			Constraints: GenomeConstraints{BlockedActions: []string{"refund", "delete_account"}},
+		},
+	},
+	"researcher": {
+		ID:           "researcher",
+		Name:         "Researcher",
+		Type:         "orchestrator",
+		SystemPrompt: "You are a research assistant. Gather information from multiple sources, cite them, distinguish facts from speculation, and summarize findings.",
+		Skills:       []string{"search", "analysis", "summarize"},
+		Capabilities: NewCapabilities("network", "filesystem"),
+		ModelParams:  ModelParams{MaxTokens: 8192, Temperature: Float(0.5)},
+		Genome: &Genome{
+			Identity: GenomeIdentity{Name: "researcher", Persona: "curious, rigorous", Voice: "verbose"},
+			Skills: map[string]SkillGenome{
+				"search": {Enabled: true, Weight: 1.0, Params: map[string]interface{}{"max_sources": 5.0}},
+			},
+			Behavior: GenomeBehavior{RiskTolerance: 0.3, Verbosity: 0.8, Autonomy: 0.6, PromptStyle: "thorough with citations"},
+		},
+	},
+	"home-assistant": {
+		ID:           "home-assistant",
+		Name:         "Home Assistant",
+		Type:         "monitor",
+		SystemPrompt: "You are a home automation assistant. Control devices only when asked, confirm actions that affect security or safety, and keep replies short.",
+		Skills:       []string{"monitoring", "chat"},
+		Capabilities: NewCapabilities("home", "network"),
+		Genome: &Genome{
+			Identity: GenomeIdentity{Name: "home-assistant", Persona: "helpful, careful", Voice: "concise"},
+			Skills: map[string]SkillGenome{
+				"monitoring": {Enabled: true, Weight: 1.0, Params: map[string]interface{}{"check_interval_sec": 300.0}},
+			},
+			Behavior:    GenomeBehavior{RiskTolerance: 0.1, Verbosity: 0.2, Autonomy: 0.3, PromptStyle: "short and friendly"},
+			Constraints: GenomeConstraints{BlockedActions: []string{"unlock_door", "disarm_alarm"}},
+		},
+	},
+}
+
+// AgentTemplateNames returns the names of the built-in agent templates in
+// lexical order.
+func AgentTemplateNames() []string {
+	names := make([]string, 0, len(agentTemplates))
+	for name := range agentTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AgentFromTemplate returns a new AgentDef built from the named template.
+// Non-zero fields of overrides replace the template's values, so callers can
+// set an ID, name or model and keep the rest of the preset. The returned
+// definition is a deep copy and safe to modify.
+func AgentFromTemplate(name string, overrides AgentDef) (AgentDef, error) {
+	tmpl, ok := agentTemplates[name]
+	if !ok {
+		return AgentDef{}, fmt.Errorf("unknown agent template %q (available: %v)", name, AgentTemplateNames())
+	}
+
+	// Deep copy so callers never mutate the shared preset.
+	data, err := json.Marshal(tmpl)
+	if err != nil {
+		return AgentDef{}, fmt.Errorf("copy template %s: %w", name, err)
+	}
+	var def AgentDef
+	if err := json.Unmarshal(data, &def); err != nil {
+		return AgentDef{}, fmt.Errorf("copy template %s: %w", name, err)
+	}
+
+	overrideFields(reflect.ValueOf(&def).Elem(), reflect.ValueOf(overrides))
+	// A renamed agent keeps the preset genome under its new name.
+	if overrides.Name != "" && overrides.Genome == nil && def.Genome != nil {
+		def.Genome.Identity.Name = overrides.Name
+	}
+	return def, nil
+}
+
+// overrideFields copies every non-zero field of src over dst. Embedded
+// structs such as ModelParams are merged field by field so that setting one
+// sampling parameter keeps the template's others; other struct fields
+// (Shadow, Container) are replaced whole when set.
+func overrideFields(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Type().Field(i)
+		v := src.Field(i)
+		if field.Anonymous && v.Kind() == reflect.Struct {
+			overrideFields(dst.Field(i), v)
+			continue
+		}
+		if !v.IsZero() {
+			dst.Field(i).Set(v)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestAgentTemplatesPassValidation(t *testing.T) {
+	names := AgentTemplateNames()
+	for _, want := range []string{"home-assistant", "researcher", "support", "trader"} {
+		found := false
+		for _, n := range names {
+			found = found || n == want
+		}
+		if !found {
+			t.Errorf("missing template %q in %v", want, names)
+		}
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			def, err := AgentFromTemplate(name, AgentDef{})
+			if err != nil {
+				t.Fatalf("AgentFromTemplate: %v", err)
+			}
+			if def.ID == "" || def.SystemPrompt == "" || def.Genome == nil {
+				t.Errorf("incomplete template: %+v", def)
+			}
+
+			cfg := DefaultConfig()
+			cfg.Agents = []AgentDef{def}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("template %s fails validation: %v", name, err)
+			}
+		})
+	}
+}
+
+func TestAgentFromTemplateOverrides(t *testing.T) {
+	def, err := AgentFromTemplate("trader", AgentDef{ID: "t1", Name: "Desk", Model: "ollama/llama3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.ID != "t1" || def.Name != "Desk" || def.Model != "ollama/llama3" {
+		t.Errorf("overrides not applied: %+v", def)
+	}
+	if def.Type != "trader" || len(def.Skills) == 0 {
+		t.Errorf("template fields lost: %+v", def)
+	}
+	if def.Genome.Identity.Name != "Desk" {
+		t.Errorf("genome name = %q, want Desk", def.Genome.Identity.Name)
+	}
+
+	// The shared preset is not modified.
+	def.Genome.Behavior.RiskTolerance = 0.9
+	again, _ := AgentFromTemplate("trader", AgentDef{})
+	if again.Genome.Behavior.RiskTolerance == 0.9 || again.Genome.Identity.Name != "trader" {
+		t.Error("template was mutated through a returned copy")
+	}
+}
+
+func TestAgentFromTemplateUnknown(t *testing.T) {
+	if _, err := AgentFromTemplate("pirate", AgentDef{}); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+func TestValidateGenomeBehaviorBounds(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents = []AgentDef{{ID: "a", Genome: &Genome{Behavior: GenomeBehavior{Verbosity: 1.5}}}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for verbosity out of range")
+	}
+}
+
+func TestAgentFromTemplateCopiesEveryOverride(t *testing.T) {
+	overrides := AgentDef{
+		NoResponseCache: true,
+		PinModel:        true,
+		IdleTimeoutSec:  600,
+		Shadow:          ShadowConfig{Enabled: true, Model: "ollama/qwen"},
+		ModelParams:     ModelParams{Temperature: Float(0.3)},
+		Container:       ContainerConfig{Enabled: true, MemoryMB: 256},
+	}
+	def, err := AgentFromTemplate("trader", overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !def.NoResponseCache || !def.PinModel || def.IdleTimeoutSec != 600 {
+		t.Errorf("flags not applied: %+v", def)
+	}
+	if def.Shadow != overrides.Shadow {
+		t.Errorf("shadow = %+v, want %+v", def.Shadow, overrides.Shadow)
+	}
+	if *def.Temperature != 0.3 {
+		t.Errorf("temperature = %v, want 0.3", *def.Temperature)
+	}
+	if !def.Container.Enabled || def.Container.MemoryMB != 256 {
+		t.Errorf("container = %+v", def.Container)
+	}
+}
@@ -0,0 +1,448 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ValidationError describes a single invalid config field.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error: %s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors collects every problem found by Config.Validate.
+type ValidationErrors []*ValidationError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+var validLogLevels = map[string]bool{"": true, "debug": true, "info": true, "warn": true, "error": true}
+
+var validStorageBackends = map[string]bool{"": true, "file": true, "sqlite": true}
+
+var validLogFormats = map[string]bool{"": true, "text": true, "json": true}
+
+var validBudgetPeriods = map[string]bool{"": true, "daily": true, "monthly": true}
+
+var validQueuePolicies = map[string]bool{"": true, "block": true, "drop-new": true, "drop-oldest": true}
+
+var validChainTypes = map[string]bool{"evm": true, "solana": true, "substrate": true, "hyperliquid": true}
+
+var validScheduleKinds = map[string]bool{"interval": true, "cron": true, "at": true}
+
+var validActionKinds = map[string]bool{"shell": true, "agent": true, "mqtt": true, "http": true}
+
+// Validate checks the config for values that would make the orchestrator
+// fail or misbehave at startup. It returns nil or a ValidationErrors value
+// listing every problem found, not just the first.
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+	add := func(field, format string, args ...any) {
+		errs = append(errs, &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Server
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("server.port", "must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	if c.Server.DataDir == "" {
+		add("server.dataDir", "must not be empty")
+	}
+	if !validLogLevels[c.Server.LogLevel] {
+		add("server.logLevel", "unknown level %q (want debug, info, warn or error)", c.Server.LogLevel)
+	}
+	if !validStorageBackends[c.Server.Storage] {
+		add("server.storage", "unknown backend %q (want file or sqlite)", c.Server.Storage)
+	}
+	if !validLogFormats[c.Server.LogFormat] {
+		add("server.logFormat", "unknown format %q (want text or json)", c.Server.LogFormat)
+	}
+	if c.Server.LogMaxSizeMB < 0 {
+		add("server.logMaxSizeMb", "must not be negative, got %d", c.Server.LogMaxSizeMB)
+	}
+	if c.Server.LogMaxBackups < 0 {
+		add("server.logMaxBackups", "must not be negative, got %d", c.Server.LogMaxBackups)
+	}
+	if el := c.Models.Routing.EmergencyLocal; el != "" {
+		if !strings.HasPrefix(el, "ollama/") || len(el) == len("ollama/") {
+			add("models.routing.emergencyLocal", "must be an ollama model (ollama/<model>), got %q", el)
+		} else if _, ok := c.Models.Providers["ollama"]; !ok {
+			add("models.routing.emergencyLocal", "requires an \"ollama\" provider under models.providers")
+		}
+	}
+	if c.Server.GlobalBudgetUSD < 0 {
+		add("server.globalBudgetUsd", "must not be negative, got %g", c.Server.GlobalBudgetUSD)
+	}
+	if !validBudgetPeriods[c.Server.BudgetPeriod] {
+		add("server.budgetPeriod", "unknown period %q (want daily or monthly)", c.Server.BudgetPeriod)
+	}
+	if !validQueuePolicies[c.Server.QueuePolicy] {
+		add("server.queuePolicy", "unknown policy %q (want block, drop-new or drop-oldest)", c.Server.QueuePolicy)
+	}
+	if c.Server.QueueTimeoutMs < 0 {
+		add("server.queueTimeoutMs", "must not be negative, got %d", c.Server.QueueTimeoutMs)
+	}
+	if c.Server.OutboxWorkers < 0 {
+		add("server.outboxWorkers", "must not be negative, got %d", c.Server.OutboxWorkers)
+	}
+	if c.Server.ShutdownTimeoutSec < 0 {
+		add("server.shutdownTimeoutSec", "must not be negative, got %d", c.Server.ShutdownTimeoutSec)
+	}
+	if c.Server.MaxChatBodyBytes < 0 {
+		add("server.maxChatBodyBytes", "must not be negative, got %d", c.Server.MaxChatBodyBytes)
+	}
+	if (c.Server.TLSCert == "") != (c.Server.TLSKey == "") {
+		add("server.tlsCert", "tlsCert and tlsKey must be set together")
+	}
+	if p := c.Server.HTTPRedirectPort; p != 0 {
+		switch {
+		case p < 0 || p > 65535:
+			add("server.httpRedirectPort", "must be between 0 and 65535, got %d", p)
+		case c.Server.TLSCert == "":
+			add("server.httpRedirectPort", "requires tlsCert and tlsKey")
+		case p == c.Server.Port:
+			add("server.httpRedirectPort", "must differ from server.port")
+		}
+	}
+
+	// MQTT (port 0 disables the channel)
+	if c.MQTT.Port < 0 || c.MQTT.Port > 65535 {
+		add("mqtt.port", "must be between 0 and 65535, got %d", c.MQTT.Port)
+	}
+	if c.MQTT.Port > 0 && c.MQTT.Host == "" {
+		add("mqtt.host", "must be set when mqtt.port is non-zero")
+	}
+	if strings.ContainsAny(c.MQTT.TopicPrefix, "+#") {
+		add("mqtt.topicPrefix", "must not contain MQTT wildcards, got %q", c.MQTT.TopicPrefix)
+	}
+	if c.MQTT.EdgeAgentTTLHours < 0 {
+		add("mqtt.edgeAgentTtlHours", "must not be negative, got %d", c.MQTT.EdgeAgentTTLHours)
+	}
+	if t := c.MQTT.TLS; t != nil && (t.ClientCert == "") != (t.ClientKey == "") {
+		add("mqtt.tls", "clientCert and clientKey must be set together")
+	}
+
+	// Channels
+	if tg := c.Channels.Telegram; tg != nil && tg.Enabled && tg.BotToken == "" {
+		add("channels.telegram.botToken", "must be set when telegram is enabled")
+	}
+	if wh := c.Channels.Webhook; wh != nil && wh.Enabled && wh.Listen == "" && wh.OutboundURL == "" {
+		add("channels.webhook", "listen or outboundUrl must be set when webhook is enabled")
+	}
+
+	// Providers
+	for _, name := range sortedKeys(c.Models.Providers) {
+		prov := c.Models.Providers[name]
+		field := "models.providers." + name
+		if prov.TimeoutMs < 0 {
+			add(field+".timeoutMs", "must not be negative, got %d", prov.TimeoutMs)
+		}
+		if prov.MaxConcurrent < 0 {
+			add(field+".maxConcurrent", "must not be negative, got %d", prov.MaxConcurrent)
+		}
+		if prov.QueueTimeoutMs < 0 {
+			add(field+".queueTimeoutMs", "must not be negative, got %d", prov.QueueTimeoutMs)
+		}
+		if prov.ModelRefreshSec < 0 {
+			add(field+".modelRefreshSec", "must not be negative, got %d", prov.ModelRefreshSec)
+		}
+		for i, m := range prov.Models {
+			if m.ID == "" {
+				add(fmt.Sprintf("%s.models[%d].id", field, i), "must not be empty")
+			}
+			if m.TimeoutMs < 0 {
+				add(fmt.Sprintf("%s.models[%d].timeoutMs", field, i), "must not be negative, got %d", m.TimeoutMs)
+			}
+		}
+	}
+
+	// Evolution
+	if c.Evolution.Enabled && c.Evolution.EvalIntervalSec <= 0 {
+		add("evolution.evalIntervalSec", "must be positive when evolution is enabled, got %d", c.Evolution.EvalIntervalSec)
+	}
+	if c.Evolution.EvalJitterSec < 0 {
+		add("evolution.evalJitterSec", "must not be negative, got %d", c.Evolution.EvalJitterSec)
+	}
+	if c.Evolution.MaxMutationRate < 0 || c.Evolution.MaxMutationRate > 1 {
+		add("evolution.maxMutationRate", "must be between 0 and 1, got %g", c.Evolution.MaxMutationRate)
+	}
+	if c.Evolution.ThrashMaxReverts < 0 {
+		add("evolution.thrashMaxReverts", "must not be negative, got %d", c.Evolution.ThrashMaxReverts)
+	}
+	if c.Evolution.ThrashWindowSec < 0 {
+		add("evolution.thrashWindowSec", "must not be negative, got %d", c.Evolution.ThrashWindowSec)
+	}
+	if c.Evolution.ThrashCoolOffSec < 0 {
+		add("evolution.thrashCoolOffSec", "must not be negative, got %d", c.Evolution.ThrashCoolOffSec)
+	}
+	if c.Evolution.MinFitness < 0 || c.Evolution.MinFitness > 1 {
+		add("evolution.minFitness", "must be between 0 and 1, got %g", c.Evolution.MinFitness)
+	}
+	for _, t := range sortedKeys(c.Evolution.MinFitnessByType) {
+		if v := c.Evolution.MinFitnessByType[t]; v < 0 || v > 1 {
+			add("evolution.minFitnessByType."+t, "must be between 0 and 1, got %g", v)
+		}
+	}
+	for _, skill := range sortedKeys(c.Evolution.MinFitnessBySkill) {
+		if v := c.Evolution.MinFitnessBySkill[skill]; v < 0 || v > 1 {
+			add("evolution.minFitnessBySkill."+skill, "must be between 0 and 1, got %g", v)
+		}
+	}
+	if n := len(c.Evolution.OwnerPublicKey); n != 0 && n != ed25519.PublicKeySize {
+		add("evolution.ownerPublicKey", "must be a %d-byte Ed25519 public key, got %d bytes", ed25519.PublicKeySize, n)
+	}
+	if c.Evolution.RequireSignedGenomes && len(c.Evolution.OwnerPublicKey) == 0 {
+		add("evolution.ownerPublicKey", "is required when requireSignedGenomes is set")
+	}
+	if sl := c.Evolution.SkillLearning; sl.Enabled && sl.Model == "" {
+		add("evolution.skillLearning.model", "is required when skill learning is enabled")
+	}
+	if c.Evolution.SkillLearning.MinFailures < 0 {
+		add("evolution.skillLearning.minFailures", "must not be negative, got %d", c.Evolution.SkillLearning.MinFailures)
+	}
+	if c.Evolution.SkillLearning.IntervalSec < 0 {
+		add("evolution.skillLearning.intervalSec", "must not be negative, got %d", c.Evolution.SkillLearning.IntervalSec)
+	}
+
+	// Chains
+	for _, id := range sortedKeys(c.Chains) {
+		chain := c.Chains[id]
+		if !chain.Enabled {
+			continue
+		}
+		if !validChainTypes[chain.Type] {
+			add("chains."+id+".type", "unknown chain type %q", chain.Type)
+		}
+		if chain.RPCURL == "" {
+			add("chains."+id+".rpcUrl", "must be set for enabled chains")
+		}
+	}
+
+	// Tool loop (zero keeps the defaults)
+	if c.ToolLoop.MaxIterations < 0 {
+		add("toolLoop.maxIterations", "must not be negative, got %d", c.ToolLoop.MaxIterations)
+	}
+	if c.ToolLoop.BudgetSec < 0 {
+		add("toolLoop.budgetSec", "must not be negative, got %d", c.ToolLoop.BudgetSec)
+	}
+
+	// Tracing
+	if ep := c.Tracing.Endpoint; ep != "" && !strings.HasPrefix(ep, "http://") && !strings.HasPrefix(ep, "https://") {
+		add("tracing.endpoint", "must be an http(s) URL, got %q", ep)
+	}
+
+	// Cloud sync and memory
+	if c.CloudSync.Enabled && c.CloudSync.DatabaseURL == "" {
+		add("cloudSync.databaseUrl", "must be set when cloud sync is enabled")
+	}
+	if c.Memory.Enabled && c.Memory.Cold.DatabaseUrl == "" && c.CloudSync.DatabaseURL == "" {
+		add("memory.cold.databaseUrl", "must be set (or cloudSync.databaseUrl) when memory is enabled")
+	}
+	if warm := c.Memory.Warm; warm.ForceConsolidateKb < 0 {
+		add("memory.warm.forceConsolidateKb", "must not be negative, got %d", warm.ForceConsolidateKb)
+	} else if warm.MaxSizeKb > 0 && warm.ForceConsolidateKb > warm.MaxSizeKb {
+		add("memory.warm.forceConsolidateKb", "must not exceed memory.warm.maxSizeKb (%d), got %d", warm.MaxSizeKb, warm.ForceConsolidateKb)
+	}
+	distill := c.Memory.Distillation
+	if distill.MaxTokens < 0 {
+		add("memory.distillation.maxTokens", "must not be negative, got %d", distill.MaxTokens)
+	}
+	if distill.Temperature < 0 || distill.Temperature > 2 {
+		add("memory.distillation.temperature", "must be between 0 and 2, got %g", distill.Temperature)
+	}
+	if distill.TimeoutSec < 0 {
+		add("memory.distillation.timeoutSec", "must not be negative, got %d", distill.TimeoutSec)
+	}
+	if distill.MaxRetries < 0 {
+		add("memory.distillation.maxRetries", "must not be negative, got %d", distill.MaxRetries)
+	}
+	if distill.RetryBackoffMs < 0 {
+		add("memory.distillation.retryBackoffMs", "must not be negative, got %d", distill.RetryBackoffMs)
+	}
+
+	// Scheduler
+	jobIDs := make(map[string]bool)
+	for i, job := range c.Scheduler.Jobs {
+		field := fmt.Sprintf("scheduler.jobs[%d]", i)
+		if job.ID == "" {
+			add(field+".id", "must not be empty")
+		} else if jobIDs[job.ID] {
+			add(field+".id", "duplicate job id %q", job.ID)
+		}
+		jobIDs[job.ID] = true
+		if !validScheduleKinds[job.Schedule.Kind] {
+			add(field+".schedule.kind", "unknown schedule kind %q", job.Schedule.Kind)
+		}
+		if job.Schedule.Kind == "interval" && job.Schedule.IntervalMs <= 0 {
+			add(field+".schedule.intervalMs", "must be positive for interval schedules")
+		}
+		if !validActionKinds[job.Action.Kind] {
+			add(field+".action.kind", "unknown action kind %q", job.Action.Kind)
+		}
+	}
+
+	validateModelParams("models.defaults", c.Models.Defaults, add)
+	if rc := c.Models.ResponseCache; rc.TTLSec < 0 {
+		add("models.responseCache.ttlSec", "must not be negative, got %d", rc.TTLSec)
+	}
+	if rc := c.Models.ResponseCache; rc.MaxEntries < 0 {
+		add("models.responseCache.maxEntries", "must not be negative, got %d", rc.MaxEntries)
+	}
+	switch c.Models.Reasoning {
+	case "", "hide", "log", "store":
+	default:
+		add("models.reasoning", "must be hide, log or store, got %q", c.Models.Reasoning)
+	}
+
+	// Moderation
+	if m := c.Moderation; m.Enabled {
+		if !m.Inbound && !m.Outbound {
+			add("moderation", "enable inbound, outbound or both")
+		}
+		if len(m.Blocklist) == 0 && m.ClassifierModel == "" {
+			add("moderation", "needs a blocklist or a classifierModel")
+		}
+		for i, pattern := range m.Blocklist {
+			if _, err := regexp.Compile(pattern); err != nil {
+				add(fmt.Sprintf("moderation.blocklist[%d]", i), "invalid pattern: %v", err)
+			}
+		}
+	}
+
+	// Post-processing
+	for i, step := range c.PostProcess {
+		field := fmt.Sprintf("postProcess[%d]", i)
+		switch step.Type {
+		case PostProcessTruncate:
+			if step.MaxChars <= len([]rune(step.Suffix)) {
+				add(field+".maxChars", "must be longer than the suffix, got %d", step.MaxChars)
+			}
+		case PostProcessAppend:
+			if step.Text == "" {
+				add(field+".text", "is required for append")
+			}
+		case PostProcessRegexReplace:
+			if _, err := regexp.Compile(step.Pattern); err != nil || step.Pattern == "" {
+				add(field+".pattern", "must be a non-empty regular expression")
+			}
+		default:
+			add(field+".type", "unknown post-processor %q", step.Type)
+		}
+	}
+
+	// Notifications
+	n := c.Notifications
+	if n.MaxAttempts < 0 {
+		add("notifications.maxAttempts", "must not be negative, got %d", n.MaxAttempts)
+	}
+	if n.RetryBackoffMs < 0 {
+		add("notifications.retryBackoffMs", "must not be negative, got %d", n.RetryBackoffMs)
+	}
+	webhookNames := make(map[string]bool)
+	for i, wh := range n.Webhooks {
+		field := fmt.Sprintf("notifications.webhooks[%d]", i)
+		if wh.Name == "" {
+			add(field+".name", "must not be empty")
+		} else if webhookNames[wh.Name] {
+			add(field+".name", "duplicate webhook name %q", wh.Name)
+		}
+		webhookNames[wh.Name] = true
+		if u, err := url.Parse(wh.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add(field+".url", "must be an http or https URL")
+		}
+		switch wh.Format {
+		case "", "json", "slack":
+		default:
+			add(field+".format", "must be json or slack, got %q", wh.Format)
+		}
+	}
+
+	// Agents
+	agentIDs := make(map[string]bool)
+	for i, agent := range c.Agents {
+		field := fmt.Sprintf("agents[%d]", i)
+		if agent.ID == "" {
+			add(field+".id", "must not be empty")
+			continue
+		}
+		if agentIDs[agent.ID] {
+			add(field+".id", "duplicate agent id %q", agent.ID)
+		}
+		agentIDs[agent.ID] = true
+		if agent.CostBudgetUSD < 0 {
+			add(field+".costBudgetUsd", "must not be negative, got %g", agent.CostBudgetUSD)
+		}
+		if agent.IdleTimeoutSec < 0 {
+			add(field+".idleTimeoutSec", "must not be negative, got %d", agent.IdleTimeoutSec)
+		}
+		validateModelParams(field, agent.ModelParams, add)
+		for j, c := range agent.Capabilities {
+			capField := fmt.Sprintf("%s.capabilities[%d]", field, j)
+			if c.Name == "" {
+				add(capField+".name", "must not be empty")
+			}
+			if _, _, ok := parseVersion(c.Version); c.Version != "" && !ok {
+				add(capField+".version", "must be MAJOR or MAJOR.MINOR, got %q", c.Version)
+			}
+		}
+		if agent.Shadow.Enabled && !strings.Contains(agent.Shadow.Model, "/") {
+			add(field+".shadow.model", "must be \"provider/model\" when shadow is enabled, got %q", agent.Shadow.Model)
+		}
+		if g := agent.Genome; g != nil {
+			for _, trait := range []struct {
+				name  string
+				value float64
+			}{
+				{"riskTolerance", g.Behavior.RiskTolerance},
+				{"verbosity", g.Behavior.Verbosity},
+				{"autonomy", g.Behavior.Autonomy},
+			} {
+				if trait.value < 0 || trait.value > 1 {
+					add(field+".genome.behavior."+trait.name, "must be between 0 and 1, got %g", trait.value)
+				}
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validateModelParams checks sampling parameters under the given field prefix.
+func validateModelParams(field string, p ModelParams, add func(field, format string, args ...any)) {
+	if p.MaxTokens < 0 {
+		add(field+".maxTokens", "must not be negative, got %d", p.MaxTokens)
+	}
+	if t := p.Temperature; t != nil && (*t < 0 || *t > 2) {
+		add(field+".temperature", "must be between 0 and 2, got %g", *t)
+	}
+	if t := p.TopP; t != nil && (*t < 0 || *t > 1) {
+		add(field+".topP", "must be between 0 and 1, got %g", *t)
+	}
+}
+
+// sortedKeys returns the keys of m in lexical order so reports are stable.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateDefaultConfig(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("expected default config to be valid, got %v", err)
+	}
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.Port = 0
+	cfg.Server.LogLevel = "verbose"
+	cfg.Server.Storage = "postgres"
+	cfg.Server.QueuePolicy = "drop-all"
+	cfg.Server.OutboxWorkers = -2
+	cfg.Server.ShutdownTimeoutSec = -1
+	cfg.Notifications.Webhooks = []NotifyWebhookConfig{{Name: "ops", URL: "hooks.example.com", Format: "teams"}}
+	cfg.Server.MaxChatBodyBytes = -1
+	cfg.Server.TLSKey = "server-key.pem"
+	cfg.Server.HTTPRedirectPort = 8080
+	cfg.MQTT.TopicPrefix = "tenant/#"
+	cfg.MQTT.EdgeAgentTTLHours = -1
+	cfg.MQTT.TLS = &MQTTTLSConfig{ClientCert: "client.pem"}
+	cfg.Memory.Distillation.MaxRetries = -1
+	cfg.Memory.Distillation.Temperature = 3
+	cfg.Memory.Warm.MaxSizeKb = 64
+	cfg.Memory.Warm.ForceConsolidateKb = 128
+	cfg.Channels.Telegram = &TelegramConfig{Enabled: true}
+	cfg.Models.Providers = map[string]ProviderConfig{
+		"slow": {MaxConcurrent: -1, ModelRefreshSec: -1, Models: []Model{{ID: "m", TimeoutMs: -5}}},
+	}
+	cfg.Models.ResponseCache.TTLSec = -1
+	cfg.Models.Reasoning = "show"
+	cfg.Evolution.EvalJitterSec = -10
+	cfg.Evolution.MaxMutationRate = 1.5
+	cfg.Evolution.ThrashCoolOffSec = -1
+	cfg.Evolution.MinFitnessBySkill = map[string]float64{"trading": 1.2}
+	cfg.Evolution.OwnerPublicKey = []byte("short")
+	cfg.Evolution.SkillLearning = SkillLearningConfig{Enabled: true, MinFailures: -1}
+	cfg.Chains = map[string]ChainConfig{"bsc": {Enabled: true, Type: "cosmos"}}
+	cfg.PostProcess = []PostProcessStep{{Type: "truncate", MaxChars: 1, Suffix: "..."}, {Type: "translate"}}
+	cfg.Agents = []AgentDef{{ID: "a", IdleTimeoutSec: -1, Shadow: ShadowConfig{Enabled: true, Model: "gpt-4o"}, Capabilities: NewCapabilities("@1", "fs@v2")}, {ID: "a"}, {}}
+	cfg.Scheduler.Jobs = []SchedulerJobConfig{
+		{ID: "j", Schedule: ScheduleConfig{Kind: "interval"}, Action: ActionConfig{Kind: "shell"}},
+		{ID: "j", Schedule: ScheduleConfig{Kind: "weekly"}, Action: ActionConfig{Kind: "email"}},
+	}
+
+	err := cfg.Validate()
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("expected ValidationErrors, got %T: %v", err, err)
+	}
+
+	want := []string{
+		"server.port",
+		"server.logLevel",
+		"server.storage",
+		"server.queuePolicy",
+		"server.outboxWorkers",
+		"server.shutdownTimeoutSec",
+		"notifications.webhooks[0].url",
+		"notifications.webhooks[0].format",
+		"server.maxChatBodyBytes",
+		"server.tlsCert",
+		"server.httpRedirectPort",
+		"mqtt.topicPrefix",
+		"mqtt.edgeAgentTtlHours",
+		"mqtt.tls",
+		"memory.distillation.temperature",
+		"memory.distillation.maxRetries",
+		"memory.warm.forceConsolidateKb",
+		"channels.telegram.botToken",
+		"models.providers.slow.maxConcurrent",
+		"models.providers.slow.modelRefreshSec",
+		"models.providers.slow.models[0].timeoutMs",
+		"models.responseCache.ttlSec",
+		"models.reasoning",
+		"evolution.evalJitterSec",
+		"evolution.maxMutationRate",
+		"evolution.thrashCoolOffSec",
+		"evolution.minFitnessBySkill.trading",
+		"evolution.ownerPublicKey",
+		"evolution.skillLearning.model",
+		"evolution.skillLearning.minFailures",
+		"chains.bsc.type",
+		"chains.bsc.rpcUrl",
+		"postProcess[0].maxChars",
+		"postProcess[1].type",
+		"scheduler.jobs[0].schedule.intervalMs",
+		"scheduler.jobs[1].id",
+		"scheduler.jobs[1].schedule.kind",
+		"scheduler.jobs[1].action.kind",
+		"agents[0].idleTimeoutSec",
+		"agents[0].shadow.model",
+		"agents[0].capabilities[0].name",
+		"agents[0].capabilities[1].version",
+		"agents[1].id",
+		"agents[2].id",
+	}
+	got := make(map[string]bool)
+	for _, e := range verrs {
+		got[e.Field] = true
+	}
+	for _, field := range want {
+		if !got[field] {
+			t.Errorf("expected error for %s, got %v", field, verrs)
+		}
+	}
+	if len(verrs) != len(want) {
+		t.Errorf("expected %d errors, got %d: %v", len(want), len(verrs), verrs)
+	}
+}
+
+func TestValidateMemoryRequiresDatabase(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Memory.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error when memory has no database")
+	}
+	cfg.CloudSync.DatabaseURL = "libsql://example.turso.io"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected cloudSync database to satisfy memory, got %v", err)
+	}
+}
+
+func TestValidateEmergencyLocal(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Models.Routing.EmergencyLocal = "openai/gpt-4o-mini"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a non-ollama emergency model")
+	}
+
+	cfg.Models.Routing.EmergencyLocal = "ollama/llama3.2"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error when no ollama provider is configured")
+	}
+
+	cfg.Models.Providers = map[string]ProviderConfig{"ollama": {BaseURL: "http://localhost:11434"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+}
+
+func TestValidateModeration(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Moderation = ModerationConfig{Enabled: true, Blocklist: []string{"(unclosed"}}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected errors for moderation without a direction and with a bad pattern")
+	}
+	for _, field := range []string{"moderation:", "moderation.blocklist[0]"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("error %q does not mention %s", err, field)
+		}
+	}
+
+	cfg.Moderation = ModerationConfig{Enabled: true, Outbound: true, Blocklist: []string{`\bpassword\b`}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package evolution
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+// maxGenomeBackups is how many backups are kept per agent; the oldest are
+// removed first.
+const maxGenomeBackups = 20
+
+// genomeBackupTimeFormat names backups so they sort by the time taken.
+const genomeBackupTimeFormat = "20060102T150405.000000000Z"
+
+// GenomeBackup is a copy of an agent's genome taken just before it was
+// overwritten.
+type GenomeBackup struct {
+	Key       string         `json:"key"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Genome    *config.Genome `json:"genome"`
+}
+
+// genomeBackupNamespace returns the storage namespace holding an agent's
+// genome backups (evolution/backups/<agent> under the data directory).
+func (e *Engine) genomeBackupNamespace(agentID string) string {
+	if e.agentDirs {
+		return storage.AgentNamespace(agentID, evolutionNamespace, "backups")
+	}
+	return evolutionNamespace + "/backups/" + agentID
+}
+
+// backupGenomeLocked copies the agent's stored genome, byte for byte, into
+// its backups and drops backups beyond maxGenomeBackups. An agent with no
+// genome yet has nothing to back up. Caller must hold e.mu for writing.
+func (e *Engine) backupGenomeLocked(agentID string) error {
+	data, err := e.store.Get(e.genomeLocation(agentID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read genome: %w", err)
+	}
+
+	ns := e.genomeBackupNamespace(agentID)
+	keys, err := e.store.List(ns)
+	if err != nil {
+		return fmt.Errorf("list genome backups: %w", err)
+	}
+
+	key := e.clock.Now().UTC().Format(genomeBackupTimeFormat)
+	if n := len(keys); n > 0 && keys[n-1] >= key {
+		key = keys[n-1] + "-1" // same instant as the last one; keep the order
+	}
+	if err := e.store.Put(ns, key, data); err != nil {
+		return fmt.Errorf("write genome backup: %w", err)
+	}
+
+	keys = append(keys, key)
+	for len(keys) > maxGenomeBackups {
+		if err := e.store.Delete(ns, keys[0]); err != nil {
+			return fmt.Errorf("rotate genome backups: %w", err)
+		}
+		keys = keys[1:]
+	}
+	return nil
+}
+
+// GenomeBackups returns an agent's genome backups, oldest first.
+func (e *Engine) GenomeBackups(agentID string) ([]GenomeBackup, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	ns := e.genomeBackupNamespace(agentID)
+	keys, err := e.store.List(ns)
+	if err != nil {
+		return nil, fmt.Errorf("list genome backups: %w", err)
+	}
+
+	backups := make([]GenomeBackup, 0, len(keys))
+	for _, key := range keys {
+		data, err := e.store.Get(ns, key)
+		if err != nil {
+			return nil, fmt.Errorf("read genome backup %s: %w", key, err)
+		}
+		var genome config.Genome
+		if err := json.Unmarshal(data, &genome); err != nil {
+			return nil, fmt.Errorf("unmarshal genome backup %s: %w", key, err)
+		}
+		b := GenomeBackup{Key: key, Genome: &genome}
+		if len(key) >= len(genomeBackupTimeFormat) {
+			b.CreatedAt, _ = time.Parse(genomeBackupTimeFormat, key[:len(genomeBackupTimeFormat)])
+		}
+		backups = append(backups, b)
+	}
+	return backups, nil
+}
@@ -0,0 +1,98 @@
+package evolution
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGenomeBackups_BeforeEachMutation(t *testing.T) {
+	dataDir := t.TempDir()
+	e := NewEngine(dataDir, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})))
+	if err := e.UpdateGenome("agent-1", newVersionedGenome()); err != nil {
+		t.Fatalf("UpdateGenome: %v", err)
+	}
+
+	var before []float64
+	for i := 0; i < 2; i++ {
+		g, _ := e.GetGenome("agent-1")
+		before = append(before, g.Skills["trading"].Params["threshold"].(float64))
+		if err := e.MutateSkill("agent-1", "trading", 1.0); err != nil {
+			t.Fatalf("MutateSkill: %v", err)
+		}
+	}
+
+	backups, err := e.GenomeBackups("agent-1")
+	if err != nil {
+		t.Fatalf("GenomeBackups: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("got %d backups, want 2 (the first save had nothing to back up)", len(backups))
+	}
+	for i, b := range backups {
+		if got := b.Genome.Skills["trading"].Params["threshold"]; got != before[i] {
+			t.Errorf("backup %d threshold = %v, want %v", i, got, before[i])
+		}
+		if b.CreatedAt.IsZero() {
+			t.Errorf("backup %d has no timestamp (key %q)", i, b.Key)
+		}
+	}
+
+	// The backups are plain files a human can copy back.
+	files, _ := os.ReadDir(filepath.Join(dataDir, "evolution", "backups", "agent-1"))
+	if len(files) != 2 {
+		t.Errorf("got %d backup files on disk, want 2", len(files))
+	}
+
+	// Restoring a backup brings the genome back.
+	if err := e.UpdateGenome("agent-1", backups[0].Genome); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	g, _ := e.GetGenome("agent-1")
+	if !reflect.DeepEqual(g, backups[0].Genome) {
+		t.Error("restored genome differs from the backup")
+	}
+}
+
+func TestGenomeBackups_Rollback(t *testing.T) {
+	e := newTestEngine(t)
+	_ = e.UpdateGenome("agent-1", newVersionedGenome())
+	_ = e.MutateSkill("agent-1", "trading", 1.0)
+	mutated, _ := e.GetGenome("agent-1")
+
+	if _, err := e.RollbackGenome("agent-1", 1); err != nil {
+		t.Fatalf("RollbackGenome: %v", err)
+	}
+	backups, _ := e.GenomeBackups("agent-1")
+	if len(backups) != 2 || !reflect.DeepEqual(backups[1].Genome, mutated) {
+		t.Errorf("the genome replaced by the rollback was not backed up: %d backups", len(backups))
+	}
+}
+
+func TestGenomeBackups_Rotate(t *testing.T) {
+	e := newTestEngine(t)
+	for i := 0; i < maxGenomeBackups+5; i++ {
+		g := newVersionedGenome()
+		g.Behavior.RiskTolerance = float64(i) / 100
+		if err := e.UpdateGenome("agent-1", g); err != nil {
+			t.Fatalf("UpdateGenome %d: %v", i, err)
+		}
+	}
+
+	backups, err := e.GenomeBackups("agent-1")
+	if err != nil {
+		t.Fatalf("GenomeBackups: %v", err)
+	}
+	if len(backups) != maxGenomeBackups {
+		t.Fatalf("got %d backups, want %d", len(backups), maxGenomeBackups)
+	}
+	// Saves 0..24 back up genomes 0..23; the oldest four are gone.
+	if got := backups[0].Genome.Behavior.RiskTolerance; got != 0.04 {
+		t.Errorf("oldest backup risk tolerance = %v, want 0.04", got)
+	}
+	if got := backups[len(backups)-1].Genome.Behavior.RiskTolerance; got != 0.23 {
+		t.Errorf("newest backup risk tolerance = %v, want 0.23", got)
+	}
+}
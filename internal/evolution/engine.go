@@ -1,20 +1,33 @@
 package evolution
 
 import (
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/clawinfra/evoclaw/internal/clock"
 	"github.com/clawinfra/evoclaw/internal/config"
 	"github.com/clawinfra/evoclaw/internal/genome"
 	"github.com/clawinfra/evoclaw/internal/security"
+	"github.com/clawinfra/evoclaw/internal/storage"
 )
 
+// evolutionNamespace is the storage namespace holding strategies and
+// current genomes. Genome versions live in nested namespaces below it.
+const evolutionNamespace = "evolution"
+
+// genomeKeySuffix distinguishes an agent's genome from its strategy.
+const genomeKeySuffix = "-genome"
+
 // Strategy represents an agent's current strategy that can be mutated
 type Strategy struct {
 	ID        string    `json:"id"`
@@ -80,38 +93,266 @@ type TradeMetrics struct {
 type Engine struct {
 	strategies map[string]*Strategy   // agentID -> current strategy
 	history    map[string][]*Strategy // agentID -> past strategies
-	dataDir    string
+	store      storage.Store
 	logger     *slog.Logger
 	mu         sync.RWMutex
 	feedbackMu sync.RWMutex
 	feedback   map[string][]genome.BehaviorFeedback // agentID -> feedback list
 	Firewall   *EvolutionFirewall                   // Security Layer 3
+
+	// fitnessHistory holds the most recent fitness samples per agent,
+	// capped at maxFitnessSamples. It is not persisted.
+	fitnessHistory map[string][]FitnessSample
+
+	// lineage records which strategies each agent's strategies were derived
+	// from. It is saved next to the strategy, capped at maxLineageNodes.
+	lineage map[string]*agentLineage
+
+	// requireSigned rejects unsigned genomes instead of allowing them in
+	// backward-compat mode.
+	requireSigned bool
+	// ownerKey is the trusted key constraint signatures are checked
+	// against; nil leaves signed genomes unchecked, like unsigned ones.
+	ownerKey ed25519.PublicKey
+
+	// thrash detects mutate/revert oscillation and enforces cool-offs.
+	thrash *thrashDetector
+
+	// agentDirs stores each agent's strategy, genome, versions and backups
+	// in its own namespace (agents/<id>/evolution).
+	agentDirs bool
+
+	// fitnessFuncs maps agent types to their fitness functions, and
+	// agentTypes agents to their types. Agents whose type has no function
+	// use DefaultFitness.
+	fitnessMu    sync.RWMutex
+	fitnessFuncs map[string]FitnessFunc
+	agentTypes   map[string]string
+
+	// rng is the source of all mutation randomness. It is randomly seeded
+	// unless SetSeed is called, so that seeded runs are reproducible.
+	rngMu sync.Mutex
+	rng   *rand.Rand
+
+	// clock times strategies, feedback, versions and the thrash and
+	// firewall windows.
+	clock clock.Clock
 }
 
-// NewEngine creates a new evolution engine
+// NewEngine creates a new evolution engine persisted to files under dataDir
 func NewEngine(dataDir string, logger *slog.Logger) *Engine {
-	dir := filepath.Join(dataDir, "evolution")
+	dir := filepath.Join(dataDir, evolutionNamespace)
 	_ = os.MkdirAll(dir, 0750)
 
-	fw := NewEvolutionFirewall(DefaultFirewallConfig())
+	var store storage.Store
+	if fs, err := storage.NewFileStore(dataDir); err == nil {
+		store = fs
+	} else {
+		logger.Error("file store unavailable, evolution state will not persist", "error", err)
+		store = storage.NewMemoryStore()
+	}
+
+	e := newEngine(store, logger)
 	// Attempt to load persisted snapshots
-	_ = fw.Snapshots.Load(dir)
+	_ = e.Firewall.Snapshots.Load(dir)
+	return e
+}
 
+// NewEngineWithStore creates a new evolution engine persisted to store
+func NewEngineWithStore(store storage.Store, logger *slog.Logger) *Engine {
+	return newEngine(store, logger)
+}
+
+func newEngine(store storage.Store, logger *slog.Logger) *Engine {
 	e := &Engine{
 		strategies: make(map[string]*Strategy),
 		history:    make(map[string][]*Strategy),
-		dataDir:    dir,
+		store:      store,
 		logger:     logger,
 		feedback:   make(map[string][]genome.BehaviorFeedback),
-		Firewall:   fw,
+		Firewall:   NewEvolutionFirewall(DefaultFirewallConfig()),
+
+		fitnessHistory: make(map[string][]FitnessSample),
+		lineage:        make(map[string]*agentLineage),
+		thrash:         newThrashDetector(DefaultThrashPolicy()),
+		fitnessFuncs:   make(map[string]FitnessFunc),
+		agentTypes:     make(map[string]string),
+		rng:            rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		clock:          clock.System,
 	}
 
-	// Load existing strategies from disk
+	// Load existing strategies from storage
 	e.loadStrategies()
 
 	return e
 }
 
+// SetRequireSignedGenomes toggles enforcement of constraint signatures.
+// When enabled, unsigned genomes are rejected and cannot be saved or mutated.
+func (e *Engine) SetRequireSignedGenomes(require bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requireSigned = require
+}
+
+// SetOwnerPublicKey pins the owner key that genome constraint signatures
+// must be made with. Genomes carrying any other key are rejected.
+func (e *Engine) SetOwnerPublicKey(key ed25519.PublicKey) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ownerKey = key
+}
+
+// SetSeed reseeds the engine's random source. Given the same seed and the
+// same sequence of calls, mutations produce the same strategies and
+// genomes, which makes evolution runs reproducible.
+func (e *Engine) SetSeed(seed int64) {
+	e.rngMu.Lock()
+	defer e.rngMu.Unlock()
+	e.rng = rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+}
+
+// randFloat returns a random number in [0, 1) from the engine's source.
+func (e *Engine) randFloat() float64 {
+	e.rngMu.Lock()
+	defer e.rngMu.Unlock()
+	return e.rng.Float64()
+}
+
+// randIntN returns a random number in [0, n) from the engine's source.
+func (e *Engine) randIntN(n int) int {
+	e.rngMu.Lock()
+	defer e.rngMu.Unlock()
+	return e.rng.IntN(n)
+}
+
+// SetClock replaces the engine's clock, including the one its thrash
+// detector and firewall use. Call it before the engine is in use.
+func (e *Engine) SetClock(c clock.Clock) {
+	e.mu.Lock()
+	e.clock = c
+	e.mu.Unlock()
+	e.thrash.setClock(c)
+	e.Firewall.SetClock(c)
+}
+
+// SetThrashPolicy configures mutate/revert oscillation detection.
+func (e *Engine) SetThrashPolicy(policy ThrashPolicy) {
+	e.thrash.setPolicy(policy)
+}
+
+// SetAgentDataDirs switches the engine to per-agent data directories and
+// reloads strategies from the new layout. Enabling them moves strategies,
+// genomes, genome versions and backups out of the shared evolution
+// namespace into each agent's directory.
+func (e *Engine) SetAgentDataDirs(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.agentDirs == enabled {
+		return
+	}
+	e.agentDirs = enabled
+	if enabled {
+		e.migrateToAgentDirsLocked()
+	}
+	e.strategies = make(map[string]*Strategy)
+	e.lineage = make(map[string]*agentLineage)
+	e.loadStrategies()
+}
+
+// migrateToAgentDirsLocked moves evolution state from the shared layout
+// into per-agent namespaces. Entries that can't be moved, or whose
+// destination is already taken, are left in place and logged. Caller must
+// hold e.mu for writing, with agentDirs set.
+func (e *Engine) migrateToAgentDirsLocked() {
+	keys, err := e.store.List(evolutionNamespace)
+	if err != nil {
+		e.logger.Error("failed to list evolution state to move", "error", err)
+		return
+	}
+	for _, key := range keys {
+		if agentID, ok := strings.CutSuffix(key, genomeKeySuffix); ok {
+			namespace, newKey := e.genomeLocation(agentID)
+			e.moveEntry(evolutionNamespace, key, namespace, newKey)
+			continue
+		}
+		if agentID, ok := strings.CutSuffix(key, lineageKeySuffix); ok {
+			namespace, newKey := e.lineageLocation(agentID)
+			e.moveEntry(evolutionNamespace, key, namespace, newKey)
+			continue
+		}
+		namespace, newKey := e.strategyLocation(key)
+		e.moveEntry(evolutionNamespace, key, namespace, newKey)
+	}
+
+	for _, nested := range []struct {
+		sub      string
+		location func(agentID string) string
+	}{
+		{"genomes", e.genomeVersionNamespace},
+		{"backups", e.genomeBackupNamespace},
+	} {
+		parent := evolutionNamespace + "/" + nested.sub
+		ids, err := e.store.Namespaces(parent)
+		if err != nil {
+			e.logger.Error("failed to list evolution state to move", "namespace", parent, "error", err)
+			continue
+		}
+		for _, id := range ids {
+			from := parent + "/" + id
+			keys, err := e.store.List(from)
+			if err != nil {
+				e.logger.Error("failed to list evolution state to move", "namespace", from, "error", err)
+				continue
+			}
+			for _, key := range keys {
+				e.moveEntry(from, key, nested.location(id), key)
+			}
+		}
+	}
+}
+
+// moveEntry copies one stored value to a new namespace and key and deletes
+// the original.
+func (e *Engine) moveEntry(fromNS, fromKey, toNS, toKey string) {
+	if _, err := e.store.Get(toNS, toKey); err == nil {
+		e.logger.Warn("evolution state already moved, leaving shared copy", "namespace", fromNS, "key", fromKey)
+		return
+	}
+	data, err := e.store.Get(fromNS, fromKey)
+	if err == nil {
+		err = e.store.Put(toNS, toKey, data)
+	}
+	if err == nil {
+		err = e.store.Delete(fromNS, fromKey)
+	}
+	if err != nil {
+		e.logger.Error("failed to move evolution state", "namespace", fromNS, "key", fromKey, "error", err)
+	}
+}
+
+// strategyLocation returns the namespace and key of an agent's strategy.
+func (e *Engine) strategyLocation(agentID string) (string, string) {
+	if e.agentDirs {
+		return storage.AgentNamespace(agentID, evolutionNamespace), "strategy"
+	}
+	return evolutionNamespace, agentID
+}
+
+// genomeLocation returns the namespace and key of an agent's current genome.
+func (e *Engine) genomeLocation(agentID string) (string, string) {
+	if e.agentDirs {
+		return storage.AgentNamespace(agentID, evolutionNamespace), "genome"
+	}
+	return evolutionNamespace, agentID + genomeKeySuffix
+}
+
+// ThrashStatus returns the agent's recent mutation/revert activity and any
+// active cool-off.
+func (e *Engine) ThrashStatus(agentID string) ThrashStatus {
+	return e.thrash.status(agentID)
+}
+
 // GetStrategy returns the current strategy for an agent
 func (e *Engine) GetStrategy(agentID string) interface{} {
 	e.mu.RLock()
@@ -124,8 +365,9 @@ func (e *Engine) SetStrategy(agentID string, s *Strategy) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	s.AgentID = agentID
-	s.CreatedAt = time.Now()
+	s.CreatedAt = e.clock.Now()
 	e.strategies[agentID] = s
+	e.startLineageLocked(agentID, s)
 	e.saveStrategy(s)
 }
 
@@ -140,7 +382,7 @@ func (e *Engine) Evaluate(agentID string, metrics map[string]float64) float64 {
 	}
 
 	// Compute fitness score (higher is better)
-	fitness := computeFitness(metrics)
+	fitness := e.computeAgentFitness(agentID, metrics)
 
 	// Exponential moving average of fitness
 	alpha := 0.3 // Weight of new observation
@@ -150,6 +392,8 @@ func (e *Engine) Evaluate(agentID string, metrics map[string]float64) float64 {
 		s.Fitness = alpha*fitness + (1-alpha)*s.Fitness
 	}
 	s.EvalCount++
+	e.recordFitnessLocked(agentID, s.Fitness)
+	e.updateLineageFitnessLocked(agentID, s.Fitness)
 
 	e.saveStrategy(s)
 	e.logger.Info("strategy evaluated",
@@ -164,6 +408,11 @@ func (e *Engine) Evaluate(agentID string, metrics map[string]float64) float64 {
 
 // Mutate creates a new strategy variant based on the current one
 func (e *Engine) Mutate(agentID string, mutationRate float64) (interface{}, error) {
+	if until, ok := e.thrash.coolingOff(agentID); ok {
+		return nil, fmt.Errorf("mutation suppressed: agent %s cooling off after repeated reverts until %s",
+			agentID, until.Format(time.RFC3339))
+	}
+
 	// Firewall pre-mutation check
 	if allowed, reason, err := e.Firewall.PreMutationCheck(agentID); err != nil {
 		return nil, fmt.Errorf("firewall error: %w", err)
@@ -180,11 +429,23 @@ func (e *Engine) Mutate(agentID string, mutationRate float64) (interface{}, erro
 	}
 
 	// Take snapshot before mutation (snapshot the genome if available)
-	if g, err := e.getGenomeLocked(agentID); err == nil {
+	g, err := e.getGenomeLocked(agentID)
+	if err == nil {
 		_ = e.Firewall.Snapshots.TakeSnapshot(agentID, g, current.Fitness)
 	}
 
+	// With enforcement on, strategies may only evolve under a signed genome
+	if e.requireSigned {
+		if err != nil {
+			return nil, fmt.Errorf("signed genome required for mutation: %w", err)
+		}
+		if err := e.verifyGenomeConstraints(g); err != nil {
+			return nil, fmt.Errorf("constraint verification before mutation: %w", err)
+		}
+	}
+
 	oldFitness := current.Fitness
+	parent := e.lineageCurrentLocked(agentID)
 
 	// Archive current strategy
 	e.history[agentID] = append(e.history[agentID], current)
@@ -194,24 +455,26 @@ func (e *Engine) Mutate(agentID string, mutationRate float64) (interface{}, erro
 		ID:             fmt.Sprintf("%s-v%d", agentID, current.Version+1),
 		AgentID:        agentID,
 		Version:        current.Version + 1,
-		CreatedAt:      time.Now(),
+		CreatedAt:      e.clock.Now(),
 		SystemPrompt:   current.SystemPrompt, // Prompt mutation handled separately
 		PreferredModel: current.PreferredModel,
 		FallbackModel:  current.FallbackModel,
-		Temperature:    mutateFloat(current.Temperature, mutationRate, 0.0, 2.0),
+		Temperature:    e.mutateFloat(current.Temperature, mutationRate, 0.0, 2.0),
 		MaxTokens:      current.MaxTokens,
 		Params:         make(map[string]float64),
 		Fitness:        0,
 		EvalCount:      0,
 	}
 
-	// Mutate custom parameters
-	for k, v := range current.Params {
-		mutated.Params[k] = mutateFloat(v, mutationRate, -1000, 1000)
+	// Mutate custom parameters, in key order so seeded runs repeat
+	for _, k := range sortedKeys(current.Params) {
+		mutated.Params[k] = e.mutateFloat(current.Params[k], mutationRate, -1000, 1000)
 	}
 
 	e.strategies[agentID] = mutated
+	e.recordLineageLocked(agentID, mutated, LineageMutation, parent)
 	e.saveStrategy(mutated)
+	e.thrash.recordMutation(agentID)
 
 	e.logger.Info("strategy mutated",
 		"agent", agentID,
@@ -236,9 +499,11 @@ func (e *Engine) Revert(agentID string) error {
 	}
 
 	// Pop the last strategy from history
+	abandoned := e.lineageCurrentLocked(agentID)
 	prev := history[len(history)-1]
 	e.history[agentID] = history[:len(history)-1]
 	e.strategies[agentID] = prev
+	e.recordLineageLocked(agentID, prev, LineageRevert, abandoned)
 	e.saveStrategy(prev)
 
 	e.logger.Info("strategy reverted",
@@ -246,6 +511,15 @@ func (e *Engine) Revert(agentID string) error {
 		"version", prev.Version,
 	)
 
+	if coolOff, reverts := e.thrash.recordRevert(agentID); coolOff {
+		until, _ := e.thrash.coolingOff(agentID)
+		e.logger.Warn("evolution thrashing detected, pausing mutations",
+			"agent", agentID,
+			"reverts", reverts,
+			"coolOffUntil", until,
+		)
+	}
+
 	return nil
 }
 
@@ -264,6 +538,10 @@ func (e *Engine) ShouldEvolve(agentID string, minFitness float64) bool {
 		return false
 	}
 
+	if _, ok := e.thrash.coolingOff(agentID); ok {
+		return false
+	}
+
 	return s.Fitness < minFitness
 }
 
@@ -285,11 +563,12 @@ func computeFitness(metrics map[string]float64) float64 {
 }
 
 // mutateFloat applies gaussian-like mutation to a float parameter
-func mutateFloat(value, rate, min, max float64) float64 {
-	// Simple mutation: add/subtract a percentage
-	delta := value * rate * 0.1 // 10% of value * mutation rate
-	// Alternate direction randomly based on current nanosecond
-	if time.Now().UnixNano()%2 == 0 {
+func (e *Engine) mutateFloat(value, rate, min, max float64) float64 {
+	// Simple mutation: add/subtract around 10% of value * mutation rate,
+	// scaled by a random factor in [0.5, 1.5)
+	delta := value * rate * 0.1 * (0.5 + e.randFloat())
+	// Random direction
+	if e.randIntN(2) == 0 {
 		delta = -delta
 	}
 	result := value + delta
@@ -303,51 +582,89 @@ func mutateFloat(value, rate, min, max float64) float64 {
 	return result
 }
 
+// sortedKeys returns m's keys in order, so that mutations consume random
+// numbers in the same order on every run.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (e *Engine) saveStrategy(s *Strategy) {
-	path := filepath.Join(e.dataDir, fmt.Sprintf("%s.json", s.AgentID))
 	data, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
 		e.logger.Error("failed to marshal strategy", "error", err)
 		return
 	}
-	_ = os.WriteFile(path, data, 0640)
+	namespace, key := e.strategyLocation(s.AgentID)
+	if err := e.store.Put(namespace, key, data); err != nil {
+		e.logger.Error("failed to save strategy", "agent", s.AgentID, "error", err)
+	}
 }
 
 func (e *Engine) loadStrategies() {
-	entries, err := os.ReadDir(e.dataDir)
+	if e.agentDirs {
+		ids, err := e.store.Namespaces(storage.AgentsNamespace)
+		if err != nil {
+			return
+		}
+		for _, id := range ids {
+			e.loadStrategy(e.strategyLocation(id))
+		}
+		return
+	}
+
+	keys, err := e.store.List(evolutionNamespace)
 	if err != nil {
 		return
 	}
-	for _, entry := range entries {
-		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+	for _, key := range keys {
+		if strings.HasSuffix(key, genomeKeySuffix) || strings.HasSuffix(key, lineageKeySuffix) {
 			continue
 		}
-		data, err := os.ReadFile(filepath.Join(e.dataDir, entry.Name()))
-		if err != nil {
-			continue
-		}
-		var s Strategy
-		if err := json.Unmarshal(data, &s); err != nil {
-			continue
-		}
-		e.strategies[s.AgentID] = &s
-		e.logger.Info("loaded strategy", "agent", s.AgentID, "version", s.Version)
+		e.loadStrategy(evolutionNamespace, key)
+	}
+}
+
+func (e *Engine) loadStrategy(namespace, key string) {
+	data, err := e.store.Get(namespace, key)
+	if err != nil {
+		return
+	}
+	var s Strategy
+	if err := json.Unmarshal(data, &s); err != nil || s.AgentID == "" {
+		return
 	}
+	e.strategies[s.AgentID] = &s
+	e.loadLineageLocked(s.AgentID)
+	e.logger.Info("loaded strategy", "agent", s.AgentID, "version", s.Version)
 }
 
-// verifyGenomeConstraints checks that the genome's constraints are validly signed.
-// Unsigned genomes (no key, no sig) are allowed with a warning for backward compat.
+// verifyGenomeConstraints checks that the genome's constraints are signed
+// with the pinned owner key. Unsigned genomes (no key, no sig) are allowed
+// with a warning for backward compat unless signatures are required, in
+// which case they are rejected. Without a pinned key a signature proves
+// nothing, so signed genomes are then treated the same way.
 func (e *Engine) verifyGenomeConstraints(g *config.Genome) error {
 	if len(g.OwnerPublicKey) == 0 && len(g.ConstraintSignature) == 0 {
+		if e.requireSigned {
+			return fmt.Errorf("unsigned genome rejected: %w", security.ErrMissingSignature)
+		}
 		e.logger.Warn("genome has unsigned constraints — backward-compat mode")
 		return nil
 	}
-	ok, err := security.VerifyConstraints(g.Constraints, g.ConstraintSignature, g.OwnerPublicKey)
-	if err != nil {
-		return fmt.Errorf("constraint verification failed: %w", err)
+	if len(e.ownerKey) == 0 {
+		if e.requireSigned {
+			return fmt.Errorf("no owner key pinned: %w", security.ErrMissingPublicKey)
+		}
+		e.logger.Warn("genome constraint signature not checked: no owner key pinned")
+		return nil
 	}
-	if !ok {
-		return security.ErrInvalidSignature
+	if err := security.VerifyGenome(g, e.ownerKey); err != nil {
+		return fmt.Errorf("constraint verification failed: %w", err)
 	}
 	return nil
 }
@@ -360,13 +677,12 @@ func (e *Engine) GetGenome(agentID string) (*config.Genome, error) {
 	return e.getGenomeLocked(agentID)
 }
 
-// getGenomeLocked reads a genome from disk without acquiring locks.
+// getGenomeLocked reads a genome from storage without acquiring locks.
 // Caller must hold e.mu (read or write).
 func (e *Engine) getGenomeLocked(agentID string) (*config.Genome, error) {
-	path := filepath.Join(e.dataDir, fmt.Sprintf("%s-genome.json", agentID))
-	data, err := os.ReadFile(path)
+	data, err := e.store.Get(e.genomeLocation(agentID))
 	if err != nil {
-		return nil, fmt.Errorf("read genome file: %w", err)
+		return nil, fmt.Errorf("read genome: %w", err)
 	}
 
 	var genome config.Genome
@@ -377,7 +693,7 @@ func (e *Engine) getGenomeLocked(agentID string) (*config.Genome, error) {
 	return &genome, nil
 }
 
-// UpdateGenome saves a genome to disk
+// UpdateGenome saves a genome to storage and records it as a new version
 func (e *Engine) UpdateGenome(agentID string, genome *config.Genome) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -385,20 +701,36 @@ func (e *Engine) UpdateGenome(agentID string, genome *config.Genome) error {
 	return e.updateGenomeLocked(agentID, genome)
 }
 
-// updateGenomeLocked saves a genome to disk without acquiring locks.
-// Caller must hold e.mu for writing.
+// updateGenomeLocked saves a genome to storage without acquiring locks.
+// The genome being replaced is backed up first; if that fails nothing is
+// written. Caller must hold e.mu for writing.
 func (e *Engine) updateGenomeLocked(agentID string, genome *config.Genome) error {
-	path := filepath.Join(e.dataDir, fmt.Sprintf("%s-genome.json", agentID))
+	if e.requireSigned {
+		if err := e.verifyGenomeConstraints(genome); err != nil {
+			return err
+		}
+	}
+
 	data, err := json.MarshalIndent(genome, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal genome: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0640); err != nil {
-		return fmt.Errorf("write genome file: %w", err)
+	if err := e.backupGenomeLocked(agentID); err != nil {
+		return fmt.Errorf("back up genome: %w", err)
+	}
+
+	namespace, key := e.genomeLocation(agentID)
+	if err := e.store.Put(namespace, key, data); err != nil {
+		return fmt.Errorf("write genome: %w", err)
 	}
 
-	e.logger.Info("genome updated", "agent", agentID)
+	version, err := e.saveGenomeVersionLocked(agentID, genome)
+	if err != nil {
+		return fmt.Errorf("save genome version: %w", err)
+	}
+
+	e.logger.Info("genome updated", "agent", agentID, "version", version)
 	return nil
 }
 
@@ -415,7 +747,7 @@ func (e *Engine) EvaluateSkill(agentID, skillName string, metrics map[string]flo
 	}
 
 	// Compute fitness for this skill
-	fitness := computeFitness(metrics)
+	fitness := e.computeAgentFitness(agentID, metrics)
 
 	// Update skill fitness (exponential moving average)
 	alpha := 0.3
@@ -471,21 +803,21 @@ func (e *Engine) MutateSkill(agentID, skillName string, mutationRate float64) er
 
 	// Mutate skill parameters
 	mutatedParams := make(map[string]interface{})
-	for k, v := range skill.Params {
-		switch val := v.(type) {
+	for _, k := range sortedKeys(skill.Params) {
+		switch val := skill.Params[k].(type) {
 		case float64:
-			mutatedParams[k] = mutateFloat(val, mutationRate, -10000, 10000)
+			mutatedParams[k] = e.mutateFloat(val, mutationRate, -10000, 10000)
 		case int:
-			mutatedParams[k] = int(mutateFloat(float64(val), mutationRate, -10000, 10000))
+			mutatedParams[k] = int(e.mutateFloat(float64(val), mutationRate, -10000, 10000))
 		case bool:
 			// Boolean mutation: flip with probability = mutationRate
-			if time.Now().UnixNano()%100 < int64(mutationRate*100) {
+			if e.randFloat() < mutationRate {
 				mutatedParams[k] = !val
 			} else {
 				mutatedParams[k] = val
 			}
 		default:
-			mutatedParams[k] = v // Keep unchanged
+			mutatedParams[k] = val // Keep unchanged
 		}
 	}
 
@@ -736,7 +1068,7 @@ func (e *Engine) CompositionFitness(agentID string, metrics map[string]float64)
 	avgIndividualFitness := individualFitnessSum / float64(enabledCount)
 
 	// Overall agent performance from metrics
-	overallFitness := computeFitness(metrics)
+	overallFitness := e.computeAgentFitness(agentID, metrics)
 
 	// Composition bonus: if overall > sum of parts, skills synergize well
 	// Composition penalty: if overall < sum of parts, skills conflict
@@ -764,11 +1096,32 @@ type BehaviorMetrics struct {
 	EngagementScore   float64 // 0.0-1.0
 }
 
+// feedbackTypes are the feedback types SubmitFeedback accepts.
+var feedbackTypes = map[string]bool{
+	"approval":   true,
+	"correction": true,
+	"engagement": true,
+	"dismissal":  true,
+	"completion": true,
+}
+
+// ValidFeedbackType reports whether t is a known behavioral feedback type.
+func ValidFeedbackType(t string) bool {
+	return feedbackTypes[t]
+}
+
 // SubmitFeedback records user feedback on agent behavior
 func (e *Engine) SubmitFeedback(agentID string, feedbackType string, score float64, context string) error {
+	if !ValidFeedbackType(feedbackType) {
+		return fmt.Errorf("invalid feedback type %q", feedbackType)
+	}
+	if score < -1.0 || score > 1.0 {
+		return fmt.Errorf("feedback score %v out of range [-1, 1]", score)
+	}
+
 	feedback := genome.BehaviorFeedback{
 		AgentID:   agentID,
-		Timestamp: time.Now(),
+		Timestamp: e.clock.Now(),
 		Type:      feedbackType,
 		Score:     score,
 		Context:   context,
@@ -896,7 +1249,7 @@ func (e *Engine) MutateBehavior(agentID string, feedbackScores map[string]float6
 	if score, ok := feedbackScores["risk"]; ok {
 		if score > 0 {
 			// Positive feedback: slightly increase risk tolerance
-			genome.Behavior.RiskTolerance = mutateFloat(genome.Behavior.RiskTolerance, behaviorMutationRate, 0.0, 1.0)
+			genome.Behavior.RiskTolerance = e.mutateFloat(genome.Behavior.RiskTolerance, behaviorMutationRate, 0.0, 1.0)
 		} else {
 			// Negative feedback: reduce risk tolerance
 			genome.Behavior.RiskTolerance *= (1 - behaviorMutationRate)
@@ -909,7 +1262,7 @@ func (e *Engine) MutateBehavior(agentID string, feedbackScores map[string]float6
 	// Mutate verbosity based on engagement feedback
 	if score, ok := feedbackScores["verbosity"]; ok {
 		if score > 0 {
-			genome.Behavior.Verbosity = mutateFloat(genome.Behavior.Verbosity, behaviorMutationRate, 0.0, 1.0)
+			genome.Behavior.Verbosity = e.mutateFloat(genome.Behavior.Verbosity, behaviorMutationRate, 0.0, 1.0)
 		} else {
 			genome.Behavior.Verbosity *= (1 - behaviorMutationRate)
 			if genome.Behavior.Verbosity < 0.0 {
@@ -921,7 +1274,7 @@ func (e *Engine) MutateBehavior(agentID string, feedbackScores map[string]float6
 	// Mutate autonomy based on user corrections
 	if score, ok := feedbackScores["autonomy"]; ok {
 		if score > 0 {
-			genome.Behavior.Autonomy = mutateFloat(genome.Behavior.Autonomy, behaviorMutationRate, 0.0, 1.0)
+			genome.Behavior.Autonomy = e.mutateFloat(genome.Behavior.Autonomy, behaviorMutationRate, 0.0, 1.0)
 		} else {
 			genome.Behavior.Autonomy *= (1 - behaviorMutationRate)
 			if genome.Behavior.Autonomy < 0.0 {
@@ -937,18 +1290,20 @@ func (e *Engine) MutateBehavior(agentID string, feedbackScores map[string]float6
 
 	if behaviorFitness < 50.0 {
 		// Low fitness: try a different prompt style
+		var others []string
 		for _, style := range styles {
 			if style != currentStyle {
-				genome.Behavior.PromptStyle = style
-				e.logger.Info("mutated prompt style",
-					"agent", agentID,
-					"from", currentStyle,
-					"to", style,
-					"reason", "low behavioral fitness",
-				)
-				break
+				others = append(others, style)
 			}
 		}
+		style := others[e.randIntN(len(others))]
+		genome.Behavior.PromptStyle = style
+		e.logger.Info("mutated prompt style",
+			"agent", agentID,
+			"from", currentStyle,
+			"to", style,
+			"reason", "low behavioral fitness",
+		)
 	}
 
 	if err := e.updateGenomeLocked(agentID, genome); err != nil {
@@ -984,7 +1339,7 @@ func (e *Engine) VerifyMutation(agentID, skillName string, metrics map[string]fl
 	}
 
 	preFitness := skill.Fitness
-	postFitness := computeFitness(metrics)
+	postFitness := e.computeAgentFitness(agentID, metrics)
 
 	verified := postFitness >= preFitness
 	skill.Verified = verified
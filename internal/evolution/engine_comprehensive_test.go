@@ -5,7 +5,9 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/clawinfra/evoclaw/internal/config"
 )
@@ -482,7 +484,8 @@ func TestComputeFitnessComprehensiveV2(t *testing.T) {
 }
 
 func TestMutateFloatComprehensiveV2(t *testing.T) {
-	val := mutateFloat(0.5, 0.3, 0.0, 1.0)
+	e := newTestEngine(t)
+	val := e.mutateFloat(0.5, 0.3, 0.0, 1.0)
 	if val < 0.0 || val > 1.0 {
 		t.Errorf("mutateFloat out of bounds: %f", val)
 	}
@@ -520,3 +523,51 @@ func TestGenomeSerializationV2(t *testing.T) {
 		t.Error("expected non-empty JSON")
 	}
 }
+
+func TestSeededEnginesAreReproducible(t *testing.T) {
+	run := func() (*Strategy, *config.Genome) {
+		eng := setupTestEngineWithGenome(t, "agent1")
+		eng.SetSeed(42)
+		eng.SetStrategy("agent1", &Strategy{
+			ID: "agent1-v1", Version: 1,
+			Temperature: 0.7, MaxTokens: 1024,
+			Params: map[string]float64{"alpha": 10, "beta": -3, "gamma": 250},
+		})
+
+		for i := 0; i < 3; i++ {
+			if _, err := eng.Mutate("agent1", 0.3); err != nil {
+				t.Fatalf("Mutate() error: %v", err)
+			}
+		}
+		if err := eng.MutateSkill("agent1", "trading", 0.5); err != nil {
+			t.Fatalf("MutateSkill() error: %v", err)
+		}
+		if err := eng.MutateBehavior("agent1", map[string]float64{"risk": 1, "verbosity": 1, "autonomy": 1}); err != nil {
+			t.Fatalf("MutateBehavior() error: %v", err)
+		}
+
+		s := *eng.GetStrategy("agent1").(*Strategy)
+		s.CreatedAt = time.Time{}
+		g, err := eng.GetGenome("agent1")
+		if err != nil {
+			t.Fatalf("GetGenome() error: %v", err)
+		}
+		return &s, g
+	}
+
+	s1, g1 := run()
+	s2, g2 := run()
+
+	if s1.Temperature == 0.7 {
+		t.Error("expected temperature to be mutated")
+	}
+	if !reflect.DeepEqual(s1, s2) {
+		t.Errorf("seeded strategies differ:\n%+v\n%+v", s1, s2)
+	}
+	if !reflect.DeepEqual(g1.Skills, g2.Skills) {
+		t.Errorf("seeded skills differ:\n%+v\n%+v", g1.Skills, g2.Skills)
+	}
+	if !reflect.DeepEqual(g1.Behavior, g2.Behavior) {
+		t.Errorf("seeded behavior differs:\n%+v\n%+v", g1.Behavior, g2.Behavior)
+	}
+}
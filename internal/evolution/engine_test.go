@@ -323,6 +323,7 @@ func TestMutateNonExistentAgent(t *testing.T) {
 }
 
 func TestMutateFloat(t *testing.T) {
+	e := newTestEngine(t)
 	tests := []struct {
 		name  string
 		value float64
@@ -364,7 +365,7 @@ func TestMutateFloat(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Run multiple times to check bounds
 			for i := 0; i < 100; i++ {
-				result := mutateFloat(tt.value, tt.rate, tt.min, tt.max)
+				result := e.mutateFloat(tt.value, tt.rate, tt.min, tt.max)
 
 				if result < tt.min {
 					t.Errorf("result %f below minimum %f", result, tt.min)
@@ -733,6 +734,20 @@ func TestSubmitFeedback(t *testing.T) {
 	}
 }
 
+func TestSubmitFeedback_RejectsInvalid(t *testing.T) {
+	e := newTestEngine(t)
+
+	if err := e.SubmitFeedback("agent-1", "like", 0.5, ""); err == nil {
+		t.Error("expected error for unknown feedback type")
+	}
+	if err := e.SubmitFeedback("agent-1", "approval", 1.5, ""); err == nil {
+		t.Error("expected error for score out of range")
+	}
+	if history, _ := e.GetBehaviorHistory("agent-1"); len(history) != 0 {
+		t.Errorf("invalid feedback was recorded: %+v", history)
+	}
+}
+
 func TestGetBehaviorMetrics(t *testing.T) {
 	e := newTestEngine(t)
 
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/clawinfra/evoclaw/internal/clock"
 	"github.com/clawinfra/evoclaw/internal/config"
 )
 
@@ -51,6 +52,7 @@ type MutationRateLimiter struct {
 	mu         sync.Mutex
 	records    map[string]*mutationRecord
 	maxPerHour int
+	clock      clock.Clock
 }
 
 // NewMutationRateLimiter creates a rate limiter.
@@ -58,15 +60,23 @@ func NewMutationRateLimiter(maxPerHour int) *MutationRateLimiter {
 	return &MutationRateLimiter{
 		records:    make(map[string]*mutationRecord),
 		maxPerHour: maxPerHour,
+		clock:      clock.System,
 	}
 }
 
+// SetClock replaces the clock that times the rate limit window.
+func (rl *MutationRateLimiter) SetClock(c clock.Clock) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.clock = c
+}
+
 // AllowMutation returns true if the agent hasn't exceeded the rate limit.
 func (rl *MutationRateLimiter) AllowMutation(agentID string) bool {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	now := time.Now()
+	now := rl.clock.Now()
 	cutoff := now.Add(-1 * time.Hour)
 
 	rec, ok := rl.records[agentID]
@@ -97,7 +107,7 @@ func (rl *MutationRateLimiter) Remaining(agentID string) int {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	cutoff := time.Now().Add(-1 * time.Hour)
+	cutoff := rl.clock.Now().Add(-1 * time.Hour)
 	rec := rl.records[agentID]
 	if rec == nil {
 		return rl.maxPerHour
@@ -130,6 +140,7 @@ type CircuitBreaker struct {
 	agents           map[string]*agentCircuit
 	fitnessThreshold float64 // fractional drop, e.g. 0.30
 	cooldown         time.Duration
+	clock            clock.Clock
 }
 
 // NewCircuitBreaker creates a circuit breaker.
@@ -138,9 +149,17 @@ func NewCircuitBreaker(fitnessDropThreshold float64, cooldown time.Duration) *Ci
 		agents:           make(map[string]*agentCircuit),
 		fitnessThreshold: fitnessDropThreshold,
 		cooldown:         cooldown,
+		clock:            clock.System,
 	}
 }
 
+// SetClock replaces the clock that times the cooldown.
+func (cb *CircuitBreaker) SetClock(c clock.Clock) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.clock = c
+}
+
 // ShouldAllowMutation checks if mutations are allowed for the agent.
 func (cb *CircuitBreaker) ShouldAllowMutation(agentID string) (bool, string) {
 	cb.mu.Lock()
@@ -155,7 +174,7 @@ func (cb *CircuitBreaker) ShouldAllowMutation(agentID string) (bool, string) {
 	case CircuitClosed:
 		return true, "circuit closed"
 	case CircuitOpen:
-		if time.Since(ac.OpenedAt) >= cb.cooldown {
+		if clock.Since(cb.clock, ac.OpenedAt) >= cb.cooldown {
 			ac.State = CircuitHalfOpen
 			return true, "circuit half-open (cooldown elapsed)"
 		}
@@ -186,7 +205,7 @@ func (cb *CircuitBreaker) RecordResult(agentID string, oldFitness, newFitness fl
 	drop := (oldFitness - newFitness) / oldFitness
 	if drop > cb.fitnessThreshold {
 		ac.State = CircuitOpen
-		ac.OpenedAt = time.Now()
+		ac.OpenedAt = cb.clock.Now()
 		return true
 	}
 
@@ -196,7 +215,7 @@ func (cb *CircuitBreaker) RecordResult(agentID string, oldFitness, newFitness fl
 			ac.State = CircuitClosed
 		} else {
 			ac.State = CircuitOpen
-			ac.OpenedAt = time.Now()
+			ac.OpenedAt = cb.clock.Now()
 			return true
 		}
 	}
@@ -213,7 +232,7 @@ func (cb *CircuitBreaker) GetState(agentID string) CircuitBreakerState {
 		return CircuitClosed
 	}
 	// Check for auto-transition
-	if ac.State == CircuitOpen && time.Since(ac.OpenedAt) >= cb.cooldown {
+	if ac.State == CircuitOpen && clock.Since(cb.clock, ac.OpenedAt) >= cb.cooldown {
 		ac.State = CircuitHalfOpen
 	}
 	return ac.State
@@ -413,6 +432,12 @@ func NewEvolutionFirewall(cfg FirewallConfig) *EvolutionFirewall {
 	}
 }
 
+// SetClock replaces the clock of the rate limiter and circuit breaker.
+func (fw *EvolutionFirewall) SetClock(c clock.Clock) {
+	fw.Limiter.SetClock(c)
+	fw.Breaker.SetClock(c)
+}
+
 // PreMutationCheck performs rate limit and circuit breaker checks.
 // Returns (allowed, reason, error).
 func (fw *EvolutionFirewall) PreMutationCheck(agentID string) (bool, string, error) {
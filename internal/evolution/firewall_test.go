@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/clawinfra/evoclaw/internal/clock"
 	"github.com/clawinfra/evoclaw/internal/config"
 )
 
@@ -22,6 +23,28 @@ func TestRateLimiter_AllowUpToLimit(t *testing.T) {
 	}
 }
 
+func TestRateLimiter_WindowSlides(t *testing.T) {
+	rl := NewMutationRateLimiter(2)
+	fake := clock.NewFake(time.Now())
+	rl.SetClock(fake)
+
+	rl.AllowMutation("agent-1")
+	fake.Advance(30 * time.Minute)
+	rl.AllowMutation("agent-1")
+	if rl.AllowMutation("agent-1") {
+		t.Fatal("third mutation within the hour should be denied")
+	}
+
+	// The first mutation leaves the window; the second is still in it.
+	fake.Advance(31 * time.Minute)
+	if got := rl.Remaining("agent-1"); got != 1 {
+		t.Errorf("Remaining = %d, want 1", got)
+	}
+	if !rl.AllowMutation("agent-1") {
+		t.Error("mutation should be allowed once the window slides")
+	}
+}
+
 func TestRateLimiter_DifferentAgents(t *testing.T) {
 	rl := NewMutationRateLimiter(1)
 
@@ -83,11 +106,13 @@ func TestCircuitBreaker_OpensOnFitnessDrop(t *testing.T) {
 }
 
 func TestCircuitBreaker_HalfOpenTransition(t *testing.T) {
-	cb := NewCircuitBreaker(0.30, 1*time.Millisecond) // tiny cooldown for testing
+	cb := NewCircuitBreaker(0.30, time.Hour)
+	fake := clock.NewFake(time.Now())
+	cb.SetClock(fake)
 
 	cb.RecordResult("agent-1", 1.0, 0.5) // trip
 
-	time.Sleep(5 * time.Millisecond)
+	fake.Advance(time.Hour)
 
 	allowed, reason := cb.ShouldAllowMutation("agent-1")
 	if !allowed {
@@ -108,10 +133,12 @@ func TestCircuitBreaker_HalfOpenTransition(t *testing.T) {
 }
 
 func TestCircuitBreaker_HalfOpenReopens(t *testing.T) {
-	cb := NewCircuitBreaker(0.30, 1*time.Millisecond)
+	cb := NewCircuitBreaker(0.30, time.Hour)
+	fake := clock.NewFake(time.Now())
+	cb.SetClock(fake)
 
 	cb.RecordResult("agent-1", 1.0, 0.5) // trip
-	time.Sleep(5 * time.Millisecond)
+	fake.Advance(time.Hour)
 
 	cb.ShouldAllowMutation("agent-1") // transition to half-open
 
@@ -0,0 +1,96 @@
+package evolution
+
+import "math"
+
+// FitnessFunc scores an agent's performance metrics for evolution; higher
+// is better. Scores are smoothed and compared against each other, so a
+// function only needs to be consistent for the agents it's used for.
+type FitnessFunc interface {
+	Fitness(metrics map[string]float64) float64
+}
+
+// FitnessFuncOf adapts an ordinary function to FitnessFunc.
+type FitnessFuncOf func(metrics map[string]float64) float64
+
+// Fitness calls f(metrics).
+func (f FitnessFuncOf) Fitness(metrics map[string]float64) float64 {
+	return f(metrics)
+}
+
+// DefaultFitness is used for agent types without a registered FitnessFunc.
+// It weighs success rate, cost, latency and profit.
+var DefaultFitness FitnessFunc = FitnessFuncOf(computeFitness)
+
+// RegisterFitnessFunc makes f the fitness function for agents of
+// agentType (AgentDef.Type). A nil f restores the default.
+func (e *Engine) RegisterFitnessFunc(agentType string, f FitnessFunc) {
+	e.fitnessMu.Lock()
+	defer e.fitnessMu.Unlock()
+	if f == nil {
+		delete(e.fitnessFuncs, agentType)
+		return
+	}
+	e.fitnessFuncs[agentType] = f
+}
+
+// SetAgentType records an agent's type, which selects its fitness
+// function. Agents without a type use DefaultFitness.
+func (e *Engine) SetAgentType(agentID, agentType string) {
+	e.fitnessMu.Lock()
+	defer e.fitnessMu.Unlock()
+	e.agentTypes[agentID] = agentType
+}
+
+// fitnessFunc returns the fitness function for an agent's type.
+func (e *Engine) fitnessFunc(agentID string) FitnessFunc {
+	e.fitnessMu.RLock()
+	defer e.fitnessMu.RUnlock()
+	if f, ok := e.fitnessFuncs[e.agentTypes[agentID]]; ok {
+		return f
+	}
+	return DefaultFitness
+}
+
+// computeAgentFitness scores metrics with the agent's fitness function.
+func (e *Engine) computeAgentFitness(agentID string, metrics map[string]float64) float64 {
+	return e.fitnessFunc(agentID).Fitness(metrics)
+}
+
+// WeightedFitness returns a FitnessFunc that averages the metrics named in
+// weights, each counted in proportion to its weight. As in DefaultFitness,
+// costUSD and avgResponseMs are inverted so that lower values score higher
+// and profitLoss is shifted by one; any other metric is used as reported
+// and should lie in [0, 1]. Metrics an agent hasn't reported count as zero.
+func WeightedFitness(weights map[string]float64) FitnessFunc {
+	keys := sortedKeys(weights) // fixed order keeps scores reproducible
+	w := make([]float64, len(keys))
+	var total float64
+	for i, k := range keys {
+		w[i] = weights[k]
+		total += w[i]
+	}
+	return FitnessFuncOf(func(metrics map[string]float64) float64 {
+		if total <= 0 {
+			return 0
+		}
+		var sum float64
+		for i, k := range keys {
+			sum += w[i] * normalizeMetric(k, metrics[k])
+		}
+		return sum / total
+	})
+}
+
+// normalizeMetric maps a standard metric onto a higher-is-better scale the
+// way computeFitness does.
+func normalizeMetric(name string, v float64) float64 {
+	switch name {
+	case "costUSD":
+		return 1.0 / (1.0 + v)
+	case "avgResponseMs":
+		return 1.0 / (1.0 + v/1000.0)
+	case "profitLoss":
+		return math.Max(0, v+1.0)
+	}
+	return v
+}
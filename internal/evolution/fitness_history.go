@@ -0,0 +1,34 @@
+package evolution
+
+import "time"
+
+// maxFitnessSamples bounds the fitness history kept per agent.
+const maxFitnessSamples = 200
+
+// FitnessSample is an agent's smoothed fitness after one evaluation.
+type FitnessSample struct {
+	Time    time.Time `json:"time"`
+	Fitness float64   `json:"fitness"`
+}
+
+// FitnessHistory returns the agent's recorded fitness samples, oldest first.
+// Only the most recent maxFitnessSamples evaluations since startup are kept.
+func (e *Engine) FitnessHistory(agentID string) []FitnessSample {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	samples := e.fitnessHistory[agentID]
+	out := make([]FitnessSample, len(samples))
+	copy(out, samples)
+	return out
+}
+
+// recordFitnessLocked appends a fitness sample, dropping the oldest once the
+// cap is reached. Caller must hold e.mu for writing.
+func (e *Engine) recordFitnessLocked(agentID string, fitness float64) {
+	samples := append(e.fitnessHistory[agentID], FitnessSample{Time: e.clock.Now(), Fitness: fitness})
+	if len(samples) > maxFitnessSamples {
+		samples = samples[len(samples)-maxFitnessSamples:]
+	}
+	e.fitnessHistory[agentID] = samples
+}
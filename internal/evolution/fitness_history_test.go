@@ -0,0 +1,45 @@
+package evolution
+
+import "testing"
+
+func TestFitnessHistory_RecordsEvaluations(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetStrategy("agent-1", &Strategy{ID: "s1"})
+
+	if got := e.FitnessHistory("agent-1"); len(got) != 0 {
+		t.Fatalf("expected empty history, got %d samples", len(got))
+	}
+
+	first := e.Evaluate("agent-1", map[string]float64{"successRate": 1.0})
+	second := e.Evaluate("agent-1", map[string]float64{"successRate": 0.0})
+
+	history := e.FitnessHistory("agent-1")
+	if len(history) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(history))
+	}
+	if history[0].Fitness != first || history[1].Fitness != second {
+		t.Errorf("samples = %+v, want fitness %v then %v", history, first, second)
+	}
+	if history[1].Time.Before(history[0].Time) {
+		t.Error("samples not in chronological order")
+	}
+
+	// Unknown agents are not recorded.
+	e.Evaluate("missing", map[string]float64{"successRate": 1.0})
+	if got := e.FitnessHistory("missing"); len(got) != 0 {
+		t.Errorf("expected no samples for unknown agent, got %d", len(got))
+	}
+}
+
+func TestFitnessHistory_Capped(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetStrategy("agent-1", &Strategy{ID: "s1"})
+
+	for i := 0; i < maxFitnessSamples+25; i++ {
+		e.Evaluate("agent-1", map[string]float64{"successRate": 0.5})
+	}
+
+	if got := len(e.FitnessHistory("agent-1")); got != maxFitnessSamples {
+		t.Errorf("history length = %d, want %d", got, maxFitnessSamples)
+	}
+}
@@ -0,0 +1,94 @@
+package evolution
+
+import (
+	"math"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// approvalFitness scores support agents on user approval alone.
+var approvalFitness = FitnessFuncOf(func(metrics map[string]float64) float64 {
+	return metrics["approvalRate"]
+})
+
+func TestEvaluateUsesFitnessFuncForAgentType(t *testing.T) {
+	e := newTestEngine(t)
+	e.RegisterFitnessFunc("support", approvalFitness)
+	e.SetAgentType("helpdesk", "support")
+	e.SetAgentType("trader-1", "trader")
+	e.SetStrategy("helpdesk", &Strategy{ID: "s1"})
+	e.SetStrategy("trader-1", &Strategy{ID: "s2"})
+	e.SetStrategy("untyped", &Strategy{ID: "s3"})
+
+	metrics := map[string]float64{"successRate": 0.9, "approvalRate": 0.25, "profitLoss": 0.5}
+	want := computeFitness(metrics)
+
+	if got := e.Evaluate("helpdesk", metrics); got != 0.25 {
+		t.Errorf("support agent fitness = %v, want approval rate 0.25", got)
+	}
+	if got := e.Evaluate("trader-1", metrics); got != want {
+		t.Errorf("trader fitness = %v, want default %v", got, want)
+	}
+	if got := e.Evaluate("untyped", metrics); got != want {
+		t.Errorf("untyped agent fitness = %v, want default %v", got, want)
+	}
+
+	// Unregistering restores the default
+	e.RegisterFitnessFunc("support", nil)
+	if got := e.computeAgentFitness("helpdesk", metrics); got != want {
+		t.Errorf("after unregistering, fitness = %v, want default %v", got, want)
+	}
+}
+
+func TestEvaluateSkillUsesFitnessFuncForAgentType(t *testing.T) {
+	e := newTestEngine(t)
+	e.RegisterFitnessFunc("support", approvalFitness)
+	e.SetAgentType("helpdesk", "support")
+
+	genome := &config.Genome{Skills: map[string]config.SkillGenome{"triage": {Enabled: true}}}
+	for _, id := range []string{"helpdesk", "other"} {
+		if err := e.UpdateGenome(id, genome); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	metrics := map[string]float64{"successRate": 0.8, "approvalRate": 0.6}
+	got, err := e.EvaluateSkill("helpdesk", "triage", metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 0.6 {
+		t.Errorf("support skill fitness = %v, want 0.6", got)
+	}
+
+	got, err = e.EvaluateSkill("other", "triage", metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := computeFitness(metrics); got != want {
+		t.Errorf("default skill fitness = %v, want %v", got, want)
+	}
+}
+
+func TestWeightedFitness(t *testing.T) {
+	metrics := map[string]float64{"successRate": 0.9, "costUSD": 0.5, "avgResponseMs": 800, "profitLoss": 0.2, "uptime": 0.5}
+
+	// DefaultFitness's own weights reproduce it.
+	def := WeightedFitness(map[string]float64{"successRate": 0.4, "costUSD": 0.2, "avgResponseMs": 0.1, "profitLoss": 0.3})
+	if got, want := def.Fitness(metrics), computeFitness(metrics); math.Abs(got-want) > 1e-9 {
+		t.Errorf("default weights = %v, want %v", got, want)
+	}
+
+	// Weights are relative and custom metrics are used as reported.
+	f := WeightedFitness(map[string]float64{"successRate": 1, "uptime": 3})
+	if got, want := f.Fitness(metrics), (0.9+3*0.5)/4; math.Abs(got-want) > 1e-9 {
+		t.Errorf("fitness = %v, want %v", got, want)
+	}
+	if got := f.Fitness(map[string]float64{"successRate": 1}); got != 0.25 {
+		t.Errorf("missing metric should count as zero, got %v", got)
+	}
+	if got := WeightedFitness(nil).Fitness(metrics); got != 0 {
+		t.Errorf("no weights = %v, want 0", got)
+	}
+}
@@ -0,0 +1,260 @@
+package evolution
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+// Lineage edge types.
+const (
+	LineageMutation = "mutation"
+	LineageRevert   = "revert"
+)
+
+// maxLineageNodes caps the nodes kept per agent. Past it the oldest nodes,
+// and the edges to and from them, are dropped.
+const maxLineageNodes = 256
+
+// lineageKeySuffix distinguishes an agent's lineage from its strategy.
+const lineageKeySuffix = "-lineage"
+
+// LineageNode is one strategy an agent has run. Nodes are created for every
+// mutation and revert, so a revert back to version 1 is a new node (with
+// RestoredFrom pointing at the original) rather than a cycle.
+type LineageNode struct {
+	// ID is "<agentID>#<n>", n counting from 1 in creation order.
+	ID         string    `json:"id"`
+	AgentID    string    `json:"agentId"`
+	StrategyID string    `json:"strategyId,omitempty"`
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"createdAt"`
+	// Fitness is the strategy's latest fitness while it was current.
+	Fitness float64 `json:"fitness"`
+	// RestoredFrom is the node a revert brought back.
+	RestoredFrom string `json:"restoredFrom,omitempty"`
+}
+
+// LineageEdge links a parent strategy to a child derived from it.
+type LineageEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+	// Fitness is the parent's fitness when the child was created.
+	Fitness float64   `json:"fitness"`
+	At      time.Time `json:"at"`
+}
+
+// Lineage is the tree of how an agent's current strategy came to be, as
+// nodes (oldest first) and the edges between them.
+type Lineage struct {
+	AgentID string        `json:"agentId"`
+	Current string        `json:"current,omitempty"`
+	Nodes   []LineageNode `json:"nodes"`
+	Edges   []LineageEdge `json:"edges"`
+}
+
+// agentLineage is the lineage recorded for one agent. trail parallels
+// e.history: the node of each archived strategy, for reverts. seq numbers
+// nodes, so IDs stay unique once old nodes are dropped.
+type agentLineage struct {
+	nodes   []*LineageNode
+	edges   []LineageEdge
+	current *LineageNode
+	trail   []*LineageNode
+	seq     int
+}
+
+// lineageRecord is an agentLineage as stored. The revert trail is not
+// kept, as the strategy history it parallels isn't either.
+type lineageRecord struct {
+	Seq     int           `json:"seq"`
+	Current string        `json:"current,omitempty"`
+	Nodes   []LineageNode `json:"nodes"`
+	Edges   []LineageEdge `json:"edges"`
+}
+
+// Lineage returns the agent's strategy lineage.
+func (e *Engine) Lineage(agentID string) Lineage {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	out := Lineage{AgentID: agentID, Nodes: []LineageNode{}, Edges: []LineageEdge{}}
+	l := e.lineage[agentID]
+	if l == nil {
+		return out
+	}
+	if l.current != nil {
+		out.Current = l.current.ID
+	}
+	for _, n := range l.nodes {
+		out.Nodes = append(out.Nodes, *n)
+	}
+	out.Edges = append(out.Edges, l.edges...)
+	return out
+}
+
+// lineageLocation returns the namespace and key of an agent's lineage.
+func (e *Engine) lineageLocation(agentID string) (string, string) {
+	if e.agentDirs {
+		return storage.AgentNamespace(agentID, evolutionNamespace), "lineage"
+	}
+	return evolutionNamespace, agentID + lineageKeySuffix
+}
+
+// saveLineageLocked writes the agent's lineage to storage. Caller must
+// hold e.mu.
+func (e *Engine) saveLineageLocked(agentID string) {
+	l := e.lineage[agentID]
+	if l == nil {
+		return
+	}
+	rec := lineageRecord{Seq: l.seq, Nodes: make([]LineageNode, 0, len(l.nodes)), Edges: l.edges}
+	if l.current != nil {
+		rec.Current = l.current.ID
+	}
+	for _, n := range l.nodes {
+		rec.Nodes = append(rec.Nodes, *n)
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		e.logger.Error("failed to marshal lineage", "error", err)
+		return
+	}
+	namespace, key := e.lineageLocation(agentID)
+	if err := e.store.Put(namespace, key, data); err != nil {
+		e.logger.Error("failed to save lineage", "agent", agentID, "error", err)
+	}
+}
+
+// loadLineageLocked reads the agent's stored lineage, if any. Caller must
+// hold e.mu for writing.
+func (e *Engine) loadLineageLocked(agentID string) {
+	data, err := e.store.Get(e.lineageLocation(agentID))
+	if err != nil {
+		return
+	}
+	var rec lineageRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		e.logger.Warn("ignoring unreadable lineage", "agent", agentID, "error", err)
+		return
+	}
+	l := &agentLineage{edges: rec.Edges, seq: rec.Seq}
+	for i := range rec.Nodes {
+		n := &rec.Nodes[i]
+		l.nodes = append(l.nodes, n)
+		if n.ID == rec.Current {
+			l.current = n
+		}
+	}
+	e.lineage[agentID] = l
+}
+
+// lineageCurrentLocked returns the node of the agent's current strategy,
+// creating a root node for a strategy that predates the lineage (set
+// before startup or with SetStrategy). Caller must hold e.mu for writing.
+func (e *Engine) lineageCurrentLocked(agentID string) *LineageNode {
+	l := e.lineage[agentID]
+	if l == nil {
+		l = &agentLineage{}
+		e.lineage[agentID] = l
+	}
+	if l.current == nil {
+		if s := e.strategies[agentID]; s != nil {
+			l.current = l.add(agentID, s, "")
+			e.saveLineageLocked(agentID)
+		}
+	}
+	return l.current
+}
+
+// startLineageLocked records s as a new root: a strategy set directly
+// rather than derived. Caller must hold e.mu for writing.
+func (e *Engine) startLineageLocked(agentID string, s *Strategy) {
+	l := e.lineage[agentID]
+	if l == nil {
+		l = &agentLineage{}
+		e.lineage[agentID] = l
+	}
+	l.current = l.add(agentID, s, "")
+	e.saveLineageLocked(agentID)
+}
+
+// add appends a node for s to the lineage, dropping the oldest nodes past
+// maxLineageNodes.
+func (l *agentLineage) add(agentID string, s *Strategy, restoredFrom string) *LineageNode {
+	l.seq++
+	n := &LineageNode{
+		ID:           fmt.Sprintf("%s#%d", agentID, l.seq),
+		AgentID:      agentID,
+		StrategyID:   s.ID,
+		Version:      s.Version,
+		CreatedAt:    time.Now(),
+		Fitness:      s.Fitness,
+		RestoredFrom: restoredFrom,
+	}
+	l.nodes = append(l.nodes, n)
+	if len(l.nodes) > maxLineageNodes {
+		l.trim(len(l.nodes) - maxLineageNodes)
+	}
+	return n
+}
+
+// trim drops the n oldest nodes and any edge touching them.
+func (l *agentLineage) trim(n int) {
+	dropped := make(map[string]bool, n)
+	for _, node := range l.nodes[:n] {
+		dropped[node.ID] = true
+	}
+	l.nodes = append([]*LineageNode(nil), l.nodes[n:]...)
+
+	edges := l.edges[:0]
+	for _, edge := range l.edges {
+		if !dropped[edge.From] && !dropped[edge.To] {
+			edges = append(edges, edge)
+		}
+	}
+	l.edges = edges
+}
+
+// recordLineageLocked makes child the agent's current strategy in the
+// lineage, derived from parent by edgeType. A mutation archives the
+// previous node for a later revert. Caller must hold e.mu for writing.
+func (e *Engine) recordLineageLocked(agentID string, child *Strategy, edgeType string, parent *LineageNode) {
+	l := e.lineage[agentID]
+	if l == nil {
+		l = &agentLineage{}
+		e.lineage[agentID] = l
+	}
+
+	var restoredFrom string
+	switch edgeType {
+	case LineageRevert:
+		if len(l.trail) > 0 {
+			restoredFrom = l.trail[len(l.trail)-1].ID
+			l.trail = l.trail[:len(l.trail)-1]
+		}
+	default:
+		if l.current != nil {
+			l.trail = append(l.trail, l.current)
+		}
+	}
+
+	node := l.add(agentID, child, restoredFrom)
+	if parent != nil {
+		l.edges = append(l.edges, LineageEdge{From: parent.ID, To: node.ID, Type: edgeType, Fitness: parent.Fitness, At: node.CreatedAt})
+	}
+	l.current = node
+	e.saveLineageLocked(agentID)
+}
+
+// updateLineageFitnessLocked records the current strategy's fitness on its
+// lineage node. Caller must hold e.mu for writing.
+func (e *Engine) updateLineageFitnessLocked(agentID string, fitness float64) {
+	if n := e.lineageCurrentLocked(agentID); n != nil {
+		n.Fitness = fitness
+		e.saveLineageLocked(agentID)
+	}
+}
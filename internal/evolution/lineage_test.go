@@ -0,0 +1,132 @@
+package evolution
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+func TestLineage_MutateMutate(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetStrategy("agent-1", &Strategy{ID: "a1", Version: 1, Temperature: 0.4, Params: map[string]float64{"x": 1}})
+	e.Evaluate("agent-1", map[string]float64{"successRate": 0.5})
+
+	if _, err := e.Mutate("agent-1", 0.1); err != nil {
+		t.Fatalf("first mutate: %v", err)
+	}
+	e.Evaluate("agent-1", map[string]float64{"successRate": 0.6})
+	if _, err := e.Mutate("agent-1", 0.1); err != nil {
+		t.Fatalf("second mutate: %v", err)
+	}
+
+	lin := e.Lineage("agent-1")
+	if lin.Current != "agent-1#3" {
+		t.Errorf("current = %q, want agent-1#3", lin.Current)
+	}
+	want := []LineageEdge{
+		{From: "agent-1#1", To: "agent-1#2", Type: LineageMutation},
+		{From: "agent-1#2", To: "agent-1#3", Type: LineageMutation},
+	}
+	if len(lin.Edges) != len(want) {
+		t.Fatalf("edges = %+v, want %d", lin.Edges, len(want))
+	}
+	for i, w := range want {
+		got := lin.Edges[i]
+		if got.From != w.From || got.To != w.To || got.Type != w.Type {
+			t.Errorf("edge %d = %s -%s-> %s, want %s -%s-> %s", i, got.From, got.Type, got.To, w.From, w.Type, w.To)
+		}
+	}
+
+	// Edge fitness is the parent's fitness when the child was made.
+	if lin.Edges[0].Fitness == 0 || lin.Edges[1].Fitness == 0 {
+		t.Errorf("edge fitness not recorded: %+v", lin.Edges)
+	}
+	if len(lin.Nodes) != 3 {
+		t.Fatalf("nodes = %d, want 3", len(lin.Nodes))
+	}
+}
+
+func TestLineage_Revert(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetStrategy("agent-1", &Strategy{ID: "a1", Version: 1})
+	if _, err := e.Mutate("agent-1", 0.1); err != nil {
+		t.Fatalf("mutate: %v", err)
+	}
+	if err := e.Revert("agent-1"); err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+
+	lin := e.Lineage("agent-1")
+	if len(lin.Edges) != 2 {
+		t.Fatalf("edges = %+v, want mutation then revert", lin.Edges)
+	}
+	rev := lin.Edges[1]
+	if rev.Type != LineageRevert || rev.From != "agent-1#2" || rev.To != "agent-1#3" {
+		t.Errorf("revert edge = %+v", rev)
+	}
+	restored := lin.Nodes[2]
+	if restored.Version != 1 || restored.RestoredFrom != "agent-1#1" {
+		t.Errorf("restored node = %+v, want version 1 restored from agent-1#1", restored)
+	}
+}
+
+func TestLineage_UnknownAgent(t *testing.T) {
+	e := newTestEngine(t)
+	if got := e.Lineage("missing"); len(got.Nodes) != 0 || len(got.Edges) != 0 {
+		t.Errorf("unknown agent lineage = %+v, want empty", got)
+	}
+}
+
+func TestLineage_Persisted(t *testing.T) {
+	store := storage.NewMemoryStore()
+	e := NewEngineWithStore(store, slog.Default())
+	e.SetStrategy("agent-1", &Strategy{ID: "a1", Version: 1})
+	if _, err := e.Mutate("agent-1", 0.1); err != nil {
+		t.Fatalf("mutate: %v", err)
+	}
+	e.Evaluate("agent-1", map[string]float64{"successRate": 0.7})
+	want := e.Lineage("agent-1")
+
+	reloaded := NewEngineWithStore(store, slog.Default())
+	got := reloaded.Lineage("agent-1")
+	if got.Current != want.Current || len(got.Nodes) != len(want.Nodes) || len(got.Edges) != len(want.Edges) {
+		t.Fatalf("reloaded lineage = %+v, want %+v", got, want)
+	}
+	if got.Nodes[1].Fitness != want.Nodes[1].Fitness {
+		t.Errorf("reloaded fitness = %v, want %v", got.Nodes[1].Fitness, want.Nodes[1].Fitness)
+	}
+
+	// Numbering carries on from the stored lineage
+	if _, err := reloaded.Mutate("agent-1", 0.1); err != nil {
+		t.Fatalf("mutate after reload: %v", err)
+	}
+	if cur := reloaded.Lineage("agent-1").Current; cur != "agent-1#3" {
+		t.Errorf("current after reload = %q, want agent-1#3", cur)
+	}
+}
+
+func TestLineage_Capped(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetStrategy("agent-1", &Strategy{ID: "a1", Version: 1})
+	e.mu.Lock()
+	for i := 0; i < maxLineageNodes+10; i++ {
+		parent := e.lineageCurrentLocked("agent-1")
+		e.recordLineageLocked("agent-1", &Strategy{Version: i + 2}, LineageMutation, parent)
+	}
+	e.mu.Unlock()
+
+	lin := e.Lineage("agent-1")
+	if len(lin.Nodes) != maxLineageNodes {
+		t.Fatalf("nodes = %d, want %d", len(lin.Nodes), maxLineageNodes)
+	}
+	if first := lin.Nodes[0].ID; first != "agent-1#12" {
+		t.Errorf("oldest node = %s, want agent-1#12", first)
+	}
+	if len(lin.Edges) != maxLineageNodes-1 {
+		t.Errorf("edges = %d, want %d", len(lin.Edges), maxLineageNodes-1)
+	}
+	if lin.Edges[0].From != lin.Nodes[0].ID {
+		t.Errorf("edge from dropped node kept: %+v", lin.Edges[0])
+	}
+}
@@ -0,0 +1,142 @@
+package evolution
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/security"
+)
+
+// newSignedGenome returns a genome signed with a fresh owner key pinned on e.
+func newSignedGenome(t *testing.T, e *Engine) *config.Genome {
+	t.Helper()
+	pub, priv, err := security.GenerateOwnerKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.SetOwnerPublicKey(pub)
+	g := &config.Genome{
+		Skills: map[string]config.SkillGenome{
+			"trading": {Enabled: true, Params: map[string]interface{}{"threshold": 10.0}},
+		},
+		Constraints: config.GenomeConstraints{MaxLossUSD: 500},
+	}
+	if err := security.SignGenome(g, priv); err != nil {
+		t.Fatalf("SignGenome: %v", err)
+	}
+	return g
+}
+
+func TestRequireSignedGenomes_SignedGenomeMutates(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetRequireSignedGenomes(true)
+
+	if err := e.UpdateGenome("agent-1", newSignedGenome(t, e)); err != nil {
+		t.Fatalf("UpdateGenome: %v", err)
+	}
+	if err := e.MutateSkill("agent-1", "trading", 0.5); err != nil {
+		t.Fatalf("MutateSkill on signed genome: %v", err)
+	}
+}
+
+func TestRequireSignedGenomes_TamperedConstraintBlocksMutation(t *testing.T) {
+	e := newTestEngine(t)
+
+	g := newSignedGenome(t, e)
+	if err := e.UpdateGenome("agent-1", g); err != nil {
+		t.Fatalf("UpdateGenome: %v", err)
+	}
+
+	// Simulate an attacker loosening the loss limit on disk
+	g.Constraints.MaxLossUSD = 1_000_000
+	if err := e.UpdateGenome("agent-1", g); err != nil {
+		t.Fatalf("UpdateGenome without enforcement: %v", err)
+	}
+
+	e.SetRequireSignedGenomes(true)
+
+	err := e.MutateSkill("agent-1", "trading", 0.5)
+	if !errors.Is(err, security.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+	if err := e.UpdateGenome("agent-1", g); !errors.Is(err, security.ErrInvalidSignature) {
+		t.Fatalf("expected tampered genome to be rejected on save, got %v", err)
+	}
+}
+
+func TestRequireSignedGenomes_RejectsGenomeResignedWithOtherKey(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetRequireSignedGenomes(true)
+	g := newSignedGenome(t, e)
+
+	// An attacker loosens the loss limit and re-signs with their own key
+	_, attacker, err := security.GenerateOwnerKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Constraints.MaxLossUSD = 1_000_000
+	if err := security.SignGenome(g, attacker); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.UpdateGenome("agent-1", g); !errors.Is(err, security.ErrUntrustedKey) {
+		t.Fatalf("expected ErrUntrustedKey, got %v", err)
+	}
+}
+
+func TestRequireSignedGenomes_NeedsPinnedKey(t *testing.T) {
+	e := newTestEngine(t)
+	g := newSignedGenome(t, e)
+	e.SetOwnerPublicKey(nil)
+	e.SetRequireSignedGenomes(true)
+
+	if err := e.UpdateGenome("agent-1", g); !errors.Is(err, security.ErrMissingPublicKey) {
+		t.Fatalf("expected ErrMissingPublicKey without a pinned key, got %v", err)
+	}
+}
+
+func TestRequireSignedGenomes_RejectsUnsigned(t *testing.T) {
+	e := newTestEngine(t)
+
+	unsigned := &config.Genome{
+		Skills: map[string]config.SkillGenome{
+			"trading": {Enabled: true, Params: map[string]interface{}{"threshold": 10.0}},
+		},
+	}
+	// Default: unsigned genomes are allowed in backward-compat mode
+	if err := e.UpdateGenome("agent-1", unsigned); err != nil {
+		t.Fatalf("UpdateGenome without enforcement: %v", err)
+	}
+	if err := e.MutateSkill("agent-1", "trading", 0.5); err != nil {
+		t.Fatalf("MutateSkill without enforcement: %v", err)
+	}
+
+	e.SetRequireSignedGenomes(true)
+
+	if err := e.MutateSkill("agent-1", "trading", 0.5); !errors.Is(err, security.ErrMissingSignature) {
+		t.Errorf("MutateSkill: expected ErrMissingSignature, got %v", err)
+	}
+	if err := e.MutateBehavior("agent-1", map[string]float64{"risk": 1}); !errors.Is(err, security.ErrMissingSignature) {
+		t.Errorf("MutateBehavior: expected ErrMissingSignature, got %v", err)
+	}
+	if err := e.UpdateGenome("agent-2", unsigned); !errors.Is(err, security.ErrMissingSignature) {
+		t.Errorf("UpdateGenome: expected ErrMissingSignature, got %v", err)
+	}
+}
+
+func TestRequireSignedGenomes_StrategyMutation(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetRequireSignedGenomes(true)
+	e.SetStrategy("agent-1", &Strategy{Temperature: 0.7, Params: map[string]float64{}})
+
+	if _, err := e.Mutate("agent-1", 0.2); err == nil {
+		t.Fatal("expected strategy mutation without a genome to be refused")
+	}
+
+	if err := e.UpdateGenome("agent-1", newSignedGenome(t, e)); err != nil {
+		t.Fatalf("UpdateGenome: %v", err)
+	}
+	if _, err := e.Mutate("agent-1", 0.2); err != nil {
+		t.Fatalf("Mutate with signed genome: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package evolution
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+func TestEngineWithStore_PersistsStrategiesAndGenomes(t *testing.T) {
+	store := storage.NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	e := NewEngineWithStore(store, logger)
+	e.SetStrategy("agent-1", &Strategy{Temperature: 0.5, Params: map[string]float64{}})
+	if err := e.UpdateGenome("agent-1", newVersionedGenome()); err != nil {
+		t.Fatalf("UpdateGenome: %v", err)
+	}
+
+	keys, _ := store.List(evolutionNamespace)
+	if len(keys) != 3 || keys[0] != "agent-1" || keys[1] != "agent-1"+genomeKeySuffix || keys[2] != "agent-1"+lineageKeySuffix {
+		t.Fatalf("stored keys = %v", keys)
+	}
+	versions, _ := store.List(e.genomeVersionNamespace("agent-1"))
+	if len(versions) != 1 || versions[0] != "v1" {
+		t.Fatalf("stored versions = %v, want [v1]", versions)
+	}
+
+	// A fresh engine over the same store sees the same state, and does not
+	// mistake the genome or lineage record for a strategy.
+	e2 := NewEngineWithStore(store, logger)
+	s, ok := e2.GetStrategy("agent-1").(*Strategy)
+	if !ok || s == nil || s.Temperature != 0.5 {
+		t.Fatalf("strategy not reloaded: %+v", e2.GetStrategy("agent-1"))
+	}
+	if len(e2.strategies) != 1 {
+		t.Errorf("expected 1 strategy, got %d", len(e2.strategies))
+	}
+	g, err := e2.GetGenome("agent-1")
+	if err != nil {
+		t.Fatalf("GetGenome: %v", err)
+	}
+	if g.Identity.Name != "trader" {
+		t.Errorf("genome name = %q, want trader", g.Identity.Name)
+	}
+	history, err := e2.GenomeHistory("agent-1")
+	if err != nil || len(history) != 1 {
+		t.Errorf("GenomeHistory = %v, %v; want 1 version", history, err)
+	}
+}
+
+func TestEngineWithStore_MissingGenome(t *testing.T) {
+	e := NewEngineWithStore(storage.NewMemoryStore(), slog.Default())
+	if _, err := e.GetGenome("nobody"); err == nil {
+		t.Error("expected error for missing genome")
+	}
+}
+
+func TestEngineWithStore_AgentDataDirs(t *testing.T) {
+	store := storage.NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	e := NewEngineWithStore(store, logger)
+	e.SetAgentDataDirs(true)
+	for _, id := range []string{"agent-1", "agent-2"} {
+		e.SetStrategy(id, &Strategy{Temperature: 0.5, Params: map[string]float64{}})
+		if err := e.UpdateGenome(id, newVersionedGenome()); err != nil {
+			t.Fatalf("UpdateGenome: %v", err)
+		}
+	}
+
+	if keys, _ := store.List(evolutionNamespace); len(keys) != 0 {
+		t.Errorf("shared evolution namespace = %v, want empty", keys)
+	}
+	for _, id := range []string{"agent-1", "agent-2"} {
+		keys, _ := store.List(storage.AgentNamespace(id, evolutionNamespace))
+		if len(keys) != 3 || keys[0] != "genome" || keys[1] != "lineage" || keys[2] != "strategy" {
+			t.Errorf("%s keys = %v, want [genome lineage strategy]", id, keys)
+		}
+		if versions, _ := store.List(storage.AgentNamespace(id, evolutionNamespace, "genomes")); len(versions) != 1 {
+			t.Errorf("%s versions = %v, want 1", id, versions)
+		}
+	}
+
+	// Removing one agent's namespace leaves the other's state intact.
+	if err := store.DeleteNamespace(storage.AgentNamespace("agent-1")); err != nil {
+		t.Fatal(err)
+	}
+	e2 := NewEngineWithStore(store, logger)
+	e2.SetAgentDataDirs(true)
+	if e2.GetStrategy("agent-1").(*Strategy) != nil {
+		t.Error("deleted agent's strategy reloaded")
+	}
+	if s, ok := e2.GetStrategy("agent-2").(*Strategy); !ok || s == nil {
+		t.Error("agent-2 strategy not reloaded")
+	}
+	if _, err := e2.GetGenome("agent-2"); err != nil {
+		t.Errorf("GetGenome agent-2: %v", err)
+	}
+}
+
+func TestEngineWithStore_AgentDataDirsMovesSharedState(t *testing.T) {
+	store := storage.NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	// State written with the shared layout: strategy, genome, two genome
+	// versions and the backup taken by the second update.
+	e := NewEngineWithStore(store, logger)
+	e.SetStrategy("agent-1", &Strategy{Temperature: 0.5, Params: map[string]float64{}})
+	for i := 0; i < 2; i++ {
+		if err := e.UpdateGenome("agent-1", newVersionedGenome()); err != nil {
+			t.Fatalf("UpdateGenome: %v", err)
+		}
+	}
+
+	e2 := NewEngineWithStore(store, logger)
+	e2.SetAgentDataDirs(true)
+
+	for _, ns := range []string{evolutionNamespace, evolutionNamespace + "/genomes/agent-1", evolutionNamespace + "/backups/agent-1"} {
+		if keys, _ := store.List(ns); len(keys) != 0 {
+			t.Errorf("%s still holds %v", ns, keys)
+		}
+	}
+	if s, ok := e2.GetStrategy("agent-1").(*Strategy); !ok || s == nil {
+		t.Error("strategy not carried over")
+	}
+	if _, err := e2.GetGenome("agent-1"); err != nil {
+		t.Errorf("genome not carried over: %v", err)
+	}
+	if versions, err := e2.GenomeHistory("agent-1"); err != nil || len(versions) != 2 {
+		t.Errorf("versions = %v (%v), want 2", versions, err)
+	}
+	if backups, _ := store.List(storage.AgentNamespace("agent-1", evolutionNamespace, "backups")); len(backups) != 1 {
+		t.Errorf("backups = %v, want 1", backups)
+	}
+}
@@ -0,0 +1,174 @@
+package evolution
+
+import (
+	"sync"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/clock"
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// Defaults for thrash detection.
+const (
+	DefaultThrashMaxReverts = 3
+	DefaultThrashWindow     = time.Hour
+	DefaultThrashCoolOff    = 6 * time.Hour
+)
+
+// ThrashPolicy configures detection of mutate/revert oscillation. An agent
+// that is reverted MaxReverts times within Window enters a cool-off during
+// which it is not mutated. A MaxReverts of zero disables detection.
+type ThrashPolicy struct {
+	MaxReverts int
+	Window     time.Duration
+	CoolOff    time.Duration
+}
+
+// DefaultThrashPolicy returns the default thrash policy.
+func DefaultThrashPolicy() ThrashPolicy {
+	return ThrashPolicy{
+		MaxReverts: DefaultThrashMaxReverts,
+		Window:     DefaultThrashWindow,
+		CoolOff:    DefaultThrashCoolOff,
+	}
+}
+
+// ThrashPolicyFromConfig builds a policy from the evolution config, using
+// defaults for unset values.
+func ThrashPolicyFromConfig(cfg config.EvolutionConfig) ThrashPolicy {
+	p := DefaultThrashPolicy()
+	if cfg.ThrashMaxReverts > 0 {
+		p.MaxReverts = cfg.ThrashMaxReverts
+	}
+	if cfg.ThrashWindowSec > 0 {
+		p.Window = time.Duration(cfg.ThrashWindowSec) * time.Second
+	}
+	if cfg.ThrashCoolOffSec > 0 {
+		p.CoolOff = time.Duration(cfg.ThrashCoolOffSec) * time.Second
+	}
+	return p
+}
+
+// thrashRecord is the per-agent mutation/revert bookkeeping.
+type thrashRecord struct {
+	mutations    []time.Time
+	reverts      []time.Time
+	coolOffUntil time.Time
+}
+
+// thrashDetector tracks mutations and reverts per agent and decides when an
+// agent is oscillating.
+type thrashDetector struct {
+	mu     sync.Mutex
+	policy ThrashPolicy
+	agents map[string]*thrashRecord
+	now    func() time.Time
+}
+
+func newThrashDetector(policy ThrashPolicy) *thrashDetector {
+	return &thrashDetector{
+		policy: policy,
+		agents: make(map[string]*thrashRecord),
+		now:    time.Now,
+	}
+}
+
+func (d *thrashDetector) setClock(c clock.Clock) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.now = c.Now
+}
+
+func (d *thrashDetector) setPolicy(policy ThrashPolicy) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.policy = policy
+}
+
+func (d *thrashDetector) recordLocked(agentID string) *thrashRecord {
+	rec, ok := d.agents[agentID]
+	if !ok {
+		rec = &thrashRecord{}
+		d.agents[agentID] = rec
+	}
+	return rec
+}
+
+// pruneLocked drops events older than the detection window.
+func (d *thrashDetector) pruneLocked(rec *thrashRecord, now time.Time) {
+	cutoff := now.Add(-d.policy.Window)
+	keep := func(ts []time.Time) []time.Time {
+		out := ts[:0]
+		for _, t := range ts {
+			if t.After(cutoff) {
+				out = append(out, t)
+			}
+		}
+		return out
+	}
+	rec.mutations = keep(rec.mutations)
+	rec.reverts = keep(rec.reverts)
+}
+
+// recordMutation notes a mutation of the agent's strategy.
+func (d *thrashDetector) recordMutation(agentID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	rec := d.recordLocked(agentID)
+	d.pruneLocked(rec, now)
+	rec.mutations = append(rec.mutations, now)
+}
+
+// recordRevert notes a revert and reports whether it started a cool-off.
+func (d *thrashDetector) recordRevert(agentID string) (coolOff bool, reverts int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	rec := d.recordLocked(agentID)
+	d.pruneLocked(rec, now)
+	rec.reverts = append(rec.reverts, now)
+	reverts = len(rec.reverts)
+
+	if d.policy.MaxReverts <= 0 || reverts < d.policy.MaxReverts || now.Before(rec.coolOffUntil) {
+		return false, reverts
+	}
+	rec.coolOffUntil = now.Add(d.policy.CoolOff)
+	rec.reverts = nil
+	return true, reverts
+}
+
+// coolingOff returns the end of the agent's cool-off, if one is active.
+func (d *thrashDetector) coolingOff(agentID string) (time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	rec, ok := d.agents[agentID]
+	if !ok || !d.now().Before(rec.coolOffUntil) {
+		return time.Time{}, false
+	}
+	return rec.coolOffUntil, true
+}
+
+// ThrashStatus is an agent's recent mutation/revert activity.
+type ThrashStatus struct {
+	Mutations    int        `json:"mutations"`
+	Reverts      int        `json:"reverts"`
+	CoolOffUntil *time.Time `json:"cool_off_until,omitempty"`
+}
+
+func (d *thrashDetector) status(agentID string) ThrashStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	rec, ok := d.agents[agentID]
+	if !ok {
+		return ThrashStatus{}
+	}
+	now := d.now()
+	d.pruneLocked(rec, now)
+	st := ThrashStatus{Mutations: len(rec.mutations), Reverts: len(rec.reverts)}
+	if now.Before(rec.coolOffUntil) {
+		until := rec.coolOffUntil
+		st.CoolOffUntil = &until
+	}
+	return st
+}
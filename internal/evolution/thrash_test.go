@@ -0,0 +1,83 @@
+package evolution
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/clock"
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func TestThrashCoolOffSuppressesMutation(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetThrashPolicy(ThrashPolicy{MaxReverts: 3, Window: time.Hour, CoolOff: time.Hour})
+	fake := clock.NewFake(time.Now())
+	e.SetClock(fake)
+
+	e.SetStrategy("agent-1", &Strategy{ID: "s1", Temperature: 0.7})
+	for i := 0; i < 10; i++ {
+		e.Evaluate("agent-1", map[string]float64{"successRate": 0.1})
+	}
+
+	// Oscillate: mutate, then revert, three times.
+	for i := 0; i < 3; i++ {
+		if _, err := e.Mutate("agent-1", 0.1); err != nil {
+			t.Fatalf("cycle %d: Mutate: %v", i, err)
+		}
+		fake.Advance(time.Minute)
+		if err := e.Revert("agent-1"); err != nil {
+			t.Fatalf("cycle %d: Revert: %v", i, err)
+		}
+	}
+
+	st := e.ThrashStatus("agent-1")
+	if st.CoolOffUntil == nil {
+		t.Fatalf("expected cool-off after 3 reverts, status = %+v", st)
+	}
+	if st.Mutations != 3 {
+		t.Errorf("mutations = %d, want 3", st.Mutations)
+	}
+
+	if _, err := e.Mutate("agent-1", 0.1); err == nil || !strings.Contains(err.Error(), "cooling off") {
+		t.Errorf("Mutate during cool-off err = %v, want cooling off", err)
+	}
+	if e.ShouldEvolve("agent-1", 0.99) {
+		t.Error("ShouldEvolve should be false during cool-off")
+	}
+
+	// Once the cool-off elapses, mutation resumes.
+	fake.Advance(time.Hour + time.Second)
+	if !e.ShouldEvolve("agent-1", 0.99) {
+		t.Error("ShouldEvolve should be true after cool-off")
+	}
+	if _, err := e.Mutate("agent-1", 0.1); err != nil {
+		t.Errorf("Mutate after cool-off: %v", err)
+	}
+}
+
+func TestThrashRevertsOutsideWindowDoNotTrip(t *testing.T) {
+	d := newThrashDetector(ThrashPolicy{MaxReverts: 2, Window: time.Hour, CoolOff: time.Hour})
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	d.recordRevert("a")
+	now = now.Add(2 * time.Hour)
+	if coolOff, reverts := d.recordRevert("a"); coolOff || reverts != 1 {
+		t.Errorf("recordRevert = (%v, %d), want no cool-off with 1 revert in window", coolOff, reverts)
+	}
+	if coolOff, _ := d.recordRevert("a"); !coolOff {
+		t.Error("second revert within window should start cool-off")
+	}
+}
+
+func TestThrashPolicyFromConfig(t *testing.T) {
+	if got := ThrashPolicyFromConfig(config.EvolutionConfig{}); got != DefaultThrashPolicy() {
+		t.Errorf("zero config = %+v, want defaults", got)
+	}
+	got := ThrashPolicyFromConfig(config.EvolutionConfig{ThrashMaxReverts: 5, ThrashWindowSec: 60, ThrashCoolOffSec: 120})
+	want := ThrashPolicy{MaxReverts: 5, Window: time.Minute, CoolOff: 2 * time.Minute}
+	if got != want {
+		t.Errorf("ThrashPolicyFromConfig = %+v, want %+v", got, want)
+	}
+}
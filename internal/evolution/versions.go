@@ -0,0 +1,267 @@
+package evolution
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+// maxGenomeVersions is how many genome versions are kept per agent; the
+// oldest are removed first. Version numbers keep counting up.
+const maxGenomeVersions = 100
+
+// GenomeVersion is an immutable snapshot of an agent's genome, written every
+// time the genome is saved.
+type GenomeVersion struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Genome    *config.Genome `json:"genome"`
+}
+
+// GenomeVersionInfo summarises a stored genome version without its body.
+type GenomeVersionInfo struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GenomeChange describes a single field that differs between two genome
+// versions. Path uses dotted notation, e.g. "skills.trading.params.threshold".
+// Old or New is nil when the field is absent from that version.
+type GenomeChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// GenomeHistory returns the stored genome versions for an agent, oldest first.
+func (e *Engine) GenomeHistory(agentID string) ([]GenomeVersionInfo, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	versions, err := e.genomeVersionsLocked(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]GenomeVersionInfo, 0, len(versions))
+	for _, v := range versions {
+		gv, err := e.loadGenomeVersionLocked(agentID, v)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, GenomeVersionInfo{Version: gv.Version, CreatedAt: gv.CreatedAt})
+	}
+	return history, nil
+}
+
+// GetGenomeVersion returns a specific stored genome version for an agent.
+func (e *Engine) GetGenomeVersion(agentID string, version int) (*GenomeVersion, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.loadGenomeVersionLocked(agentID, version)
+}
+
+// GenomeDiff lists the fields that changed between two genome versions,
+// sorted by path.
+func (e *Engine) GenomeDiff(agentID string, v1, v2 int) ([]GenomeChange, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	a, err := e.loadGenomeVersionLocked(agentID, v1)
+	if err != nil {
+		return nil, err
+	}
+	b, err := e.loadGenomeVersionLocked(agentID, v2)
+	if err != nil {
+		return nil, err
+	}
+
+	flatA, err := flattenGenome(a.Genome)
+	if err != nil {
+		return nil, err
+	}
+	flatB, err := flattenGenome(b.Genome)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool, len(flatA)+len(flatB))
+	for p := range flatA {
+		paths[p] = true
+	}
+	for p := range flatB {
+		paths[p] = true
+	}
+
+	changes := []GenomeChange{}
+	for _, p := range sortedPaths(paths) {
+		oldVal, newVal := flatA[p], flatB[p]
+		if reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+		changes = append(changes, GenomeChange{Path: p, Old: oldVal, New: newVal})
+	}
+	return changes, nil
+}
+
+// RollbackGenome restores an agent's genome to a previously stored version.
+// The restored genome's constraints are re-verified before it is applied, the
+// genome it replaces is backed up, and the rollback itself is recorded as a
+// new version.
+func (e *Engine) RollbackGenome(agentID string, version int) (*config.Genome, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	gv, err := e.loadGenomeVersionLocked(agentID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.verifyGenomeConstraints(gv.Genome); err != nil {
+		return nil, fmt.Errorf("constraint verification before rollback: %w", err)
+	}
+
+	if err := e.updateGenomeLocked(agentID, gv.Genome); err != nil {
+		return nil, fmt.Errorf("save genome: %w", err)
+	}
+
+	e.logger.Info("genome rolled back", "agent", agentID, "version", version)
+	return gv.Genome, nil
+}
+
+// genomeVersionNamespace returns the storage namespace holding an agent's
+// genome versions.
+func (e *Engine) genomeVersionNamespace(agentID string) string {
+	if e.agentDirs {
+		return storage.AgentNamespace(agentID, evolutionNamespace, "genomes")
+	}
+	return evolutionNamespace + "/genomes/" + agentID
+}
+
+// genomeVersionsLocked returns the stored version numbers for an agent in
+// ascending order. Caller must hold e.mu (read or write).
+func (e *Engine) genomeVersionsLocked(agentID string) ([]int, error) {
+	keys, err := e.store.List(e.genomeVersionNamespace(agentID))
+	if err != nil {
+		return nil, fmt.Errorf("read genome versions: %w", err)
+	}
+
+	var versions []int
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "v") {
+			continue
+		}
+		v, err := strconv.Atoi(strings.TrimPrefix(key, "v"))
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// loadGenomeVersionLocked reads a stored genome version from storage.
+// Caller must hold e.mu (read or write).
+func (e *Engine) loadGenomeVersionLocked(agentID string, version int) (*GenomeVersion, error) {
+	data, err := e.store.Get(e.genomeVersionNamespace(agentID), fmt.Sprintf("v%d", version))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("genome version %d not found for agent %s", version, agentID)
+		}
+		return nil, fmt.Errorf("read genome version: %w", err)
+	}
+
+	var gv GenomeVersion
+	if err := json.Unmarshal(data, &gv); err != nil {
+		return nil, fmt.Errorf("unmarshal genome version: %w", err)
+	}
+	return &gv, nil
+}
+
+// saveGenomeVersionLocked records genome as the next version for the agent
+// and drops versions beyond maxGenomeVersions. Caller must hold e.mu for writing.
+func (e *Engine) saveGenomeVersionLocked(agentID string, genome *config.Genome) (int, error) {
+	versions, err := e.genomeVersionsLocked(agentID)
+	if err != nil {
+		return 0, err
+	}
+	next := 1
+	if len(versions) > 0 {
+		next = versions[len(versions)-1] + 1
+	}
+
+	data, err := json.MarshalIndent(GenomeVersion{
+		Version:   next,
+		CreatedAt: e.clock.Now(),
+		Genome:    genome,
+	}, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("marshal genome version: %w", err)
+	}
+
+	ns := e.genomeVersionNamespace(agentID)
+	if err := e.store.Put(ns, fmt.Sprintf("v%d", next), data); err != nil {
+		return 0, fmt.Errorf("write genome version: %w", err)
+	}
+
+	versions = append(versions, next)
+	for len(versions) > maxGenomeVersions {
+		if err := e.store.Delete(ns, fmt.Sprintf("v%d", versions[0])); err != nil {
+			return 0, fmt.Errorf("rotate genome versions: %w", err)
+		}
+		versions = versions[1:]
+	}
+	return next, nil
+}
+
+// flattenGenome converts a genome into a map of dotted JSON paths to leaf values.
+func flattenGenome(g *config.Genome) (map[string]interface{}, error) {
+	raw, err := json.Marshal(g)
+	if err != nil {
+		return nil, fmt.Errorf("marshal genome: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal genome: %w", err)
+	}
+
+	flat := make(map[string]interface{})
+	flattenValue("", doc, flat)
+	return flat, nil
+}
+
+func flattenValue(prefix string, v interface{}, out map[string]interface{}) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		if prefix != "" {
+			out[prefix] = v
+		}
+		return
+	}
+	for k, child := range m {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		flattenValue(path, child, out)
+	}
+}
+
+func sortedPaths(set map[string]bool) []string {
+	paths := make([]string, 0, len(set))
+	for p := range set {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,170 @@
+package evolution
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/security"
+)
+
+func newVersionedGenome() *config.Genome {
+	return &config.Genome{
+		Identity: config.GenomeIdentity{Name: "trader"},
+		Skills: map[string]config.SkillGenome{
+			"trading": {
+				Enabled: true,
+				Weight:  0.5,
+				Params:  map[string]interface{}{"threshold": 100.0, "label": "x"},
+				Version: 1,
+			},
+		},
+		Behavior: config.GenomeBehavior{RiskTolerance: 0.3},
+	}
+}
+
+func TestGenomeVersioning_MutateDiffRollback(t *testing.T) {
+	e := newTestEngine(t)
+
+	if err := e.UpdateGenome("agent-1", newVersionedGenome()); err != nil {
+		t.Fatalf("UpdateGenome: %v", err)
+	}
+	if err := e.MutateSkill("agent-1", "trading", 1.0); err != nil {
+		t.Fatalf("MutateSkill: %v", err)
+	}
+
+	history, err := e.GenomeHistory("agent-1")
+	if err != nil {
+		t.Fatalf("GenomeHistory: %v", err)
+	}
+	if len(history) != 2 || history[0].Version != 1 || history[1].Version != 2 {
+		t.Fatalf("expected versions [1 2], got %+v", history)
+	}
+
+	changes, err := e.GenomeDiff("agent-1", 1, 2)
+	if err != nil {
+		t.Fatalf("GenomeDiff: %v", err)
+	}
+	byPath := make(map[string]GenomeChange)
+	for _, c := range changes {
+		byPath[c.Path] = c
+	}
+	param, ok := byPath["skills.trading.params.threshold"]
+	if !ok {
+		t.Fatalf("expected threshold change in diff, got %+v", changes)
+	}
+	if param.Old != 100.0 || param.New == 100.0 {
+		t.Errorf("unexpected threshold change %+v", param)
+	}
+	if _, ok := byPath["skills.trading.version"]; !ok {
+		t.Error("expected skill version change in diff")
+	}
+	if _, ok := byPath["skills.trading.params.label"]; ok {
+		t.Error("unchanged param should not appear in diff")
+	}
+
+	restored, err := e.RollbackGenome("agent-1", 1)
+	if err != nil {
+		t.Fatalf("RollbackGenome: %v", err)
+	}
+	if got := restored.Skills["trading"].Params["threshold"]; got != 100.0 {
+		t.Errorf("expected restored threshold 100, got %v", got)
+	}
+
+	current, err := e.GetGenome("agent-1")
+	if err != nil {
+		t.Fatalf("GetGenome: %v", err)
+	}
+	if got := current.Skills["trading"].Params["threshold"]; got != 100.0 {
+		t.Errorf("expected persisted threshold 100, got %v", got)
+	}
+
+	// The rollback is itself recorded and matches version 1
+	history, _ = e.GenomeHistory("agent-1")
+	if len(history) != 3 {
+		t.Fatalf("expected 3 versions after rollback, got %d", len(history))
+	}
+	if changes, _ := e.GenomeDiff("agent-1", 1, 3); len(changes) != 0 {
+		t.Errorf("expected no diff between v1 and rollback, got %+v", changes)
+	}
+}
+
+func TestGenomeHistory_Empty(t *testing.T) {
+	e := newTestEngine(t)
+	history, err := e.GenomeHistory("nobody")
+	if err != nil {
+		t.Fatalf("GenomeHistory: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("expected empty history, got %+v", history)
+	}
+}
+
+func TestGenomeDiff_UnknownVersion(t *testing.T) {
+	e := newTestEngine(t)
+	_ = e.UpdateGenome("agent-1", newVersionedGenome())
+	if _, err := e.GenomeDiff("agent-1", 1, 9); err == nil {
+		t.Error("expected error for unknown version")
+	}
+}
+
+func TestRollbackGenome_UnknownVersion(t *testing.T) {
+	e := newTestEngine(t)
+	if _, err := e.RollbackGenome("agent-1", 1); err == nil {
+		t.Error("expected error for unknown version")
+	}
+}
+
+func TestRollbackGenome_RejectsTamperedConstraints(t *testing.T) {
+	e := newTestEngine(t)
+
+	pub, priv, err := security.GenerateOwnerKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateOwnerKeyPair: %v", err)
+	}
+	g := newVersionedGenome()
+	g.Constraints = config.GenomeConstraints{MaxLossUSD: 100}
+	sig, err := security.SignConstraints(g.Constraints, priv)
+	if err != nil {
+		t.Fatalf("SignConstraints: %v", err)
+	}
+	g.ConstraintSignature = sig
+	g.OwnerPublicKey = pub
+	e.SetOwnerPublicKey(pub)
+
+	// Version 1 carries constraints that no longer match their signature
+	g.Constraints.MaxLossUSD = 1_000_000
+	if err := e.UpdateGenome("agent-1", g); err != nil {
+		t.Fatalf("UpdateGenome: %v", err)
+	}
+
+	_, err = e.RollbackGenome("agent-1", 1)
+	if !errors.Is(err, security.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestGenomeHistory_Capped(t *testing.T) {
+	e := newTestEngine(t)
+	for i := 0; i < maxGenomeVersions+3; i++ {
+		g := newVersionedGenome()
+		g.Behavior.RiskTolerance = float64(i) / 1000
+		if err := e.UpdateGenome("agent-1", g); err != nil {
+			t.Fatalf("UpdateGenome %d: %v", i, err)
+		}
+	}
+
+	history, err := e.GenomeHistory("agent-1")
+	if err != nil {
+		t.Fatalf("GenomeHistory: %v", err)
+	}
+	if len(history) != maxGenomeVersions {
+		t.Fatalf("got %d versions, want %d", len(history), maxGenomeVersions)
+	}
+	if history[0].Version != 4 || history[len(history)-1].Version != maxGenomeVersions+3 {
+		t.Errorf("versions %d..%d, want 4..%d", history[0].Version, history[len(history)-1].Version, maxGenomeVersions+3)
+	}
+	if _, err := e.GetGenomeVersion("agent-1", 3); err == nil {
+		t.Error("expected the oldest versions to be removed")
+	}
+}
@@ -53,11 +53,13 @@ type GenomeBehavior struct {
 
 // GenomeConstraints defines hard boundaries (non-evolvable)
 type GenomeConstraints struct {
-	MaxLossUSD     float64  `json:"max_loss_usd,omitempty"`
-	AllowedAssets  []string `json:"allowed_assets,omitempty"`
-	BlockedActions []string `json:"blocked_actions,omitempty"`
-	MaxDivergence  float64  `json:"max_divergence,omitempty"`  // ADL: max mutation distance from original
-	MinVFMScore    float64  `json:"min_vfm_score,omitempty"`   // VFM: minimum value-for-money threshold
+	MaxLossUSD        float64  `json:"max_loss_usd,omitempty"`
+	AllowedAssets     []string `json:"allowed_assets,omitempty"`
+	BlockedActions    []string `json:"blocked_actions,omitempty"`
+	MaxDivergence     float64  `json:"max_divergence,omitempty"`     // ADL: max mutation distance from original
+	MinVFMScore       float64  `json:"min_vfm_score,omitempty"`      // VFM: minimum value-for-money threshold
+	Rules             []string `json:"rules,omitempty"`              // guardrails rendered into the system prompt
+	ForbiddenPatterns []string `json:"forbidden_patterns,omitempty"` // regexps a response must not match
 }
 
 // BehaviorFeedback represents user feedback on agent behavior (Layer 3)
@@ -138,11 +140,13 @@ func (g *Genome) VerifyConstraints() error {
 // toConfig converts the genome-local GenomeConstraints to config.GenomeConstraints.
 func (c GenomeConstraints) toConfig() config.GenomeConstraints {
 	return config.GenomeConstraints{
-		MaxLossUSD:     c.MaxLossUSD,
-		AllowedAssets:  c.AllowedAssets,
-		BlockedActions: c.BlockedActions,
-		MaxDivergence:  c.MaxDivergence,
-		MinVFMScore:    c.MinVFMScore,
+		MaxLossUSD:        c.MaxLossUSD,
+		AllowedAssets:     c.AllowedAssets,
+		BlockedActions:    c.BlockedActions,
+		MaxDivergence:     c.MaxDivergence,
+		MinVFMScore:       c.MinVFMScore,
+		Rules:             c.Rules,
+		ForbiddenPatterns: c.ForbiddenPatterns,
 	}
 }
 
@@ -93,6 +93,10 @@ func (c *ColdMemory) Add(ctx context.Context, entry *WarmEntry) error {
 
 	now := time.Now().Unix()
 	lastAccessed := entry.LastAccessed.Unix()
+	agentID := c.agentID
+	if entry.AgentID != "" {
+		agentID = entry.AgentID
+	}
 
 	sql := `
 INSERT INTO cold_memory (
@@ -104,7 +108,7 @@ INSERT INTO cold_memory (
 
 	err = c.client.Execute(ctx, sql,
 		entry.ID,
-		c.agentID,
+		agentID,
 		entry.Timestamp.Unix(),
 		entry.EventType,
 		entry.Category,
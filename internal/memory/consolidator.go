@@ -116,7 +116,40 @@ func (c *Consolidator) doWarmEviction(ctx context.Context) {
 		"count", len(evicted),
 		"duration", time.Since(start))
 
-	// Archive evicted entries to cold storage
+	archived := c.archive(ctx, evicted)
+
+	c.logger.Info("warm eviction complete",
+		"evicted", len(evicted),
+		"archived", archived,
+		"duration", time.Since(start))
+}
+
+// ConsolidateWarm synchronously shrinks an agent's share of the warm
+// tier to at most targetBytes: expired entries go first, then the
+// lowest-scored ones. Evicted entries are archived to cold storage.
+// Returns the number of entries evicted.
+func (c *Consolidator) ConsolidateWarm(ctx context.Context, agentID string, targetBytes int) int {
+	start := time.Now()
+	evicted := c.warm.EvictAgentToSize(agentID, targetBytes)
+	if len(evicted) == 0 {
+		return 0
+	}
+
+	archived := c.archive(ctx, evicted)
+
+	c.logger.Info("warm consolidation complete",
+		"evicted", len(evicted),
+		"archived", archived,
+		"agent_id", agentID,
+		"warm_bytes", c.warm.AgentSize(agentID),
+		"target_bytes", targetBytes,
+		"duration", time.Since(start))
+	return len(evicted)
+}
+
+// archive moves evicted warm entries to cold storage and returns how many
+// were written. Entries that fail to archive are dropped.
+func (c *Consolidator) archive(ctx context.Context, evicted []*WarmEntry) int {
 	archived := 0
 	for _, entry := range evicted {
 		if err := c.cold.Add(ctx, entry); err != nil {
@@ -134,11 +167,7 @@ func (c *Consolidator) doWarmEviction(ctx context.Context) {
 				"error", err)
 		}
 	}
-
-	c.logger.Info("warm eviction complete",
-		"evicted", len(evicted),
-		"archived", archived,
-		"duration", time.Since(start))
+	return archived
 }
 
 // runTreePrune periodically prunes dead tree nodes
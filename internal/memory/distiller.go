@@ -31,6 +31,9 @@ type CoreSummary struct {
 
 // RawConversation represents the input to distillation
 type RawConversation struct {
+	// AgentID is the agent that had the conversation; empty means the
+	// manager's own agent.
+	AgentID   string    `json:"agent_id,omitempty"`
 	Messages  []Message `json:"messages"`
 	Timestamp time.Time `json:"timestamp"`
 }
@@ -13,13 +13,22 @@ import (
 // Allows decoupling from specific LLM providers
 type LLMCallFunc func(ctx context.Context, systemPrompt, userPrompt string) (string, error)
 
+// LLM distillation defaults, used unless MemoryConfig overrides them.
+const (
+	DefaultDistillationTimeout = 30 * time.Second
+	DefaultDistillationRetries = 2
+	DefaultDistillationBackoff = 500 * time.Millisecond
+)
+
 // LLMDistiller uses an LLM to distill conversations with better accuracy
 type LLMDistiller struct {
 	fallback *Distiller   // rule-based fallback
 	llmFunc  LLMCallFunc  // function to call LLM
 	model    string       // model to use (informational)
 	logger   *slog.Logger
-	timeout  time.Duration
+	timeout  time.Duration // per attempt
+	retries  int           // attempts after the first
+	backoff  time.Duration // before the first retry, doubling after
 }
 
 // NewLLMDistiller creates a new LLM-powered distiller
@@ -37,24 +46,29 @@ func NewLLMDistiller(fallback *Distiller, llmFunc LLMCallFunc, model string, log
 		llmFunc:  llmFunc,
 		model:    model,
 		logger:   logger,
-		timeout:  30 * time.Second, // Default 30s timeout
+		timeout:  DefaultDistillationTimeout,
+		retries:  DefaultDistillationRetries,
+		backoff:  DefaultDistillationBackoff,
 	}
 }
 
 // DistillConversation converts raw conversation to distilled fact using LLM
 // Falls back to rule-based distillation if LLM fails
 func (d *LLMDistiller) DistillConversation(conv RawConversation) (*DistilledFact, error) {
+	return d.distill(context.Background(), conv)
+}
+
+// distill is DistillConversation bounded by ctx. Each LLM attempt gets its
+// own timeout; a failed attempt is retried with backoff up to d.retries
+// times before falling back to rule-based distillation.
+func (d *LLMDistiller) distill(ctx context.Context, conv RawConversation) (*DistilledFact, error) {
 	// If no LLM function provided, use fallback immediately
 	if d.llmFunc == nil {
 		d.logger.Debug("no LLM function, using fallback distiller")
 		return d.fallback.DistillConversation(conv)
 	}
 
-	// Try LLM distillation with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
-	defer cancel()
-
-	distilled, err := d.distillWithLLM(ctx, conv)
+	distilled, err := d.distillWithRetry(ctx, conv)
 	if err != nil {
 		d.logger.Warn("LLM distillation failed, using fallback",
 			"error", err,
@@ -70,6 +84,36 @@ func (d *LLMDistiller) DistillConversation(conv RawConversation) (*DistilledFact
 	return distilled, nil
 }
 
+// distillWithRetry runs distillWithLLM until it succeeds, the retries are
+// used up or ctx is done, and returns the last error.
+func (d *LLMDistiller) distillWithRetry(ctx context.Context, conv RawConversation) (*DistilledFact, error) {
+	backoff := d.backoff
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, d.timeout)
+		distilled, err := d.distillWithLLM(attemptCtx, conv)
+		cancel()
+		if err == nil {
+			return distilled, nil
+		}
+		if attempt >= d.retries {
+			return nil, err
+		}
+		d.logger.Debug("LLM distillation attempt failed, retrying",
+			"attempt", attempt+1,
+			"error", err,
+			"backoff", backoff)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w (after %d attempts: %v)", ctx.Err(), attempt+1, err)
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
 // distillWithLLM performs LLM-powered distillation
 func (d *LLMDistiller) distillWithLLM(ctx context.Context, conv RawConversation) (*DistilledFact, error) {
 	systemPrompt := buildDistillationSystemPrompt()
@@ -189,3 +233,10 @@ func (d *LLMDistiller) GenerateCoreSummary(fact *DistilledFact) (*CoreSummary, e
 func (d *LLMDistiller) SetTimeout(timeout time.Duration) {
 	d.timeout = timeout
 }
+
+// SetRetries sets how many times a failed LLM distillation is retried and
+// the backoff before the first retry, which doubles after each one.
+func (d *LLMDistiller) SetRetries(retries int, backoff time.Duration) {
+	d.retries = retries
+	d.backoff = backoff
+}
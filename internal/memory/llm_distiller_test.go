@@ -179,3 +179,83 @@ func TestParseDistillationResponse_PlainJSON(t *testing.T) {
 		t.Errorf("unexpected people: %v", distilled.People)
 	}
 }
+
+func TestLLMDistiller_RetriesThenSucceeds(t *testing.T) {
+	calls := 0
+	flaky := func(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", context.DeadlineExceeded
+		}
+		return mockLLMFunc(ctx, systemPrompt, userPrompt)
+	}
+	distiller := NewLLMDistiller(NewDistiller(0.7), flaky, "test-model", nil)
+	distiller.SetRetries(2, time.Millisecond)
+
+	conv := RawConversation{
+		Messages:  []Message{{Role: "user", Content: "Alice and I are replanting the garden"}},
+		Timestamp: time.Now(),
+	}
+	distilled, err := distiller.DistillConversation(conv)
+	if err != nil {
+		t.Fatalf("DistillConversation failed: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("LLM calls = %d, want 3", calls)
+	}
+	if distilled.Fact != "User discussed garden replanting plans" {
+		t.Errorf("expected the LLM result after retrying, got fact %q", distilled.Fact)
+	}
+}
+
+func TestLLMDistiller_RetriesBounded(t *testing.T) {
+	calls := 0
+	failing := func(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+		calls++
+		return "", context.DeadlineExceeded
+	}
+	distiller := NewLLMDistiller(NewDistiller(0.7), failing, "test-model", nil)
+	distiller.SetRetries(1, time.Millisecond)
+
+	conv := RawConversation{
+		Messages:  []Message{{Role: "user", Content: "This is a test conversation"}},
+		Timestamp: time.Now(),
+	}
+	distilled, err := distiller.DistillConversation(conv)
+	if err != nil {
+		t.Fatalf("DistillConversation failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("LLM calls = %d, want 2 (one retry)", calls)
+	}
+	if distilled == nil || distilled.Fact == "" {
+		t.Error("expected rule-based fallback after retries ran out")
+	}
+}
+
+func TestLLMDistiller_AttemptTimeout(t *testing.T) {
+	var deadlines []time.Duration
+	slow := func(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+		if dl, ok := ctx.Deadline(); ok {
+			deadlines = append(deadlines, time.Until(dl))
+		}
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	distiller := NewLLMDistiller(NewDistiller(0.7), slow, "test-model", nil)
+	distiller.SetTimeout(20 * time.Millisecond)
+	distiller.SetRetries(1, time.Millisecond)
+
+	conv := RawConversation{Messages: []Message{{Role: "user", Content: "hi"}}, Timestamp: time.Now()}
+	if _, err := distiller.DistillConversation(conv); err != nil {
+		t.Fatalf("DistillConversation failed: %v", err)
+	}
+	if len(deadlines) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(deadlines))
+	}
+	for i, d := range deadlines {
+		if d > 20*time.Millisecond {
+			t.Errorf("attempt %d deadline %v, want at most the 20ms attempt timeout", i+1, d)
+		}
+	}
+}
@@ -5,53 +5,67 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
+	"github.com/clawinfra/evoclaw/internal/clock"
 	"github.com/clawinfra/evoclaw/internal/cloudsync"
 	"github.com/google/uuid"
 )
 
 // Manager is the main memory system coordinator
 type Manager struct {
-	hot           *HotMemory
-	warm          *WarmMemory
-	cold          *ColdMemory
-	tree          *MemoryTree
-	distiller     *Distiller
-	llmDistiller  *LLMDistiller     // LLM-powered distiller
-	searcher      *TreeSearcher
-	llmSearcher   *LLMTreeSearcher  // LLM-powered tree search
-	rebuilder     *TreeRebuilder    // LLM-powered tree rebuilding
-	consolidator  *Consolidator
-	cfg           MemoryConfig
-	llmFunc       LLMCallFunc       // LLM call function
-	logger        *slog.Logger
+	hot          *HotMemory
+	warm         *WarmMemory
+	cold         *ColdMemory
+	tree         *MemoryTree
+	distiller    *Distiller
+	llmDistiller *LLMDistiller // LLM-powered distiller
+	searcher     *TreeSearcher
+	llmSearcher  *LLMTreeSearcher // LLM-powered tree search
+	rebuilder    *TreeRebuilder   // LLM-powered tree rebuilding
+	consolidator *Consolidator
+	cfg          MemoryConfig
+	llmFunc      LLMCallFunc // LLM call function
+	logger       *slog.Logger
+
+	// forceMu serializes forced consolidations; forced counts them.
+	forceMu sync.Mutex
+	forced  int
+
+	clock clock.Clock
 }
 
 // MemoryConfig holds all memory system configuration
 type MemoryConfig struct {
-	Enabled         bool
-	AgentID         string
-	AgentName       string
-	OwnerName       string
+	Enabled   bool
+	AgentID   string
+	AgentName string
+	OwnerName string
 
 	// Turso connection
 	DatabaseURL string
 	AuthToken   string
 
 	// Tree settings
-	TreeMaxNodes      int
-	TreeMaxDepth      int
-	TreeRebuildDays   int
+	TreeMaxNodes    int
+	TreeMaxDepth    int
+	TreeRebuildDays int
 
 	// Hot tier
-	HotMaxBytes    int
-	HotMaxLessons  int
+	HotMaxBytes   int
+	HotMaxLessons int
 
 	// Warm tier
-	WarmMaxKB           int
-	WarmRetentionDays   int
+	WarmMaxKB             int
+	WarmRetentionDays     int
 	WarmEvictionThreshold float64
+	// WarmForceConsolidateKB is a hard cap on the warm tier. When new
+	// memories push it past the cap, consolidation runs synchronously
+	// instead of waiting for the next cycle. Zero disables it; it should
+	// be below WarmMaxKB, where new entries start dropping old ones
+	// without archiving them.
+	WarmForceConsolidateKB int
 
 	// Cold tier
 	ColdRetentionYears int
@@ -59,6 +73,11 @@ type MemoryConfig struct {
 	// Distillation
 	DistillationAggression float64
 	MaxDistilledBytes      int
+	// LLM distillation: timeout per attempt, and retries with doubling
+	// backoff before falling back to rule-based distillation.
+	DistillationTimeout      time.Duration
+	DistillationRetries      int
+	DistillationRetryBackoff time.Duration
 
 	// Scoring
 	HalfLifeDays       float64
@@ -71,21 +90,24 @@ type MemoryConfig struct {
 // DefaultMemoryConfig returns default memory configuration
 func DefaultMemoryConfig() MemoryConfig {
 	return MemoryConfig{
-		Enabled:               true,
-		TreeMaxNodes:          MaxTreeNodes,
-		TreeMaxDepth:          MaxTreeDepth,
-		TreeRebuildDays:       30,
-		HotMaxBytes:           MaxHotSizeBytes,
-		HotMaxLessons:         MaxCriticalLessons,
-		WarmMaxKB:             MaxWarmSizeKB,
-		WarmRetentionDays:     WarmRetentionDays,
-		WarmEvictionThreshold: WarmEvictionThreshold,
-		ColdRetentionYears:    ColdRetentionYears,
-		DistillationAggression: 0.7,
-		MaxDistilledBytes:     MaxDistilledBytes,
-		HalfLifeDays:          30.0,
-		ReinforcementBoost:    0.1,
-		Consolidation:         DefaultConsolidationConfig(),
+		Enabled:                  true,
+		TreeMaxNodes:             MaxTreeNodes,
+		TreeMaxDepth:             MaxTreeDepth,
+		TreeRebuildDays:          30,
+		HotMaxBytes:              MaxHotSizeBytes,
+		HotMaxLessons:            MaxCriticalLessons,
+		WarmMaxKB:                MaxWarmSizeKB,
+		WarmRetentionDays:        WarmRetentionDays,
+		WarmEvictionThreshold:    WarmEvictionThreshold,
+		ColdRetentionYears:       ColdRetentionYears,
+		DistillationAggression:   0.7,
+		MaxDistilledBytes:        MaxDistilledBytes,
+		DistillationTimeout:      DefaultDistillationTimeout,
+		DistillationRetries:      DefaultDistillationRetries,
+		DistillationRetryBackoff: DefaultDistillationBackoff,
+		HalfLifeDays:             30.0,
+		ReinforcementBoost:       0.1,
+		Consolidation:            DefaultConsolidationConfig(),
 	}
 }
 
@@ -119,7 +141,7 @@ func NewManager(cfg MemoryConfig, logger *slog.Logger) (*Manager, error) {
 
 	// Initialize components
 	hot := NewHotMemory(cfg.AgentName, cfg.OwnerName)
-	
+
 	warmConfig := WarmConfig{
 		MaxSizeBytes:      cfg.WarmMaxKB * 1024,
 		RetentionDays:     cfg.WarmRetentionDays,
@@ -152,14 +174,15 @@ func NewManager(cfg MemoryConfig, logger *slog.Logger) (*Manager, error) {
 		cold:         cold,
 		tree:         tree,
 		distiller:    distiller,
-		llmDistiller: nil,  // Set via SetLLMFunc
+		llmDistiller: nil, // Set via SetLLMFunc
 		searcher:     searcher,
-		llmSearcher:  nil,  // Set via SetLLMFunc
-		rebuilder:    nil,  // Set via SetLLMFunc
+		llmSearcher:  nil, // Set via SetLLMFunc
+		rebuilder:    nil, // Set via SetLLMFunc
 		consolidator: consolidator,
 		cfg:          cfg,
 		llmFunc:      nil,
 		logger:       logger,
+		clock:        clock.System,
 	}
 
 	logger.Info("memory manager created",
@@ -197,27 +220,32 @@ func (m *Manager) ProcessConversation(ctx context.Context, conv RawConversation,
 	// Stage 1 → 2: Distill conversation (use LLM if available)
 	var distilled *DistilledFact
 	var err error
-	
+
 	if m.llmDistiller != nil {
-		distilled, err = m.llmDistiller.DistillConversation(conv)
+		distilled, err = m.llmDistiller.distill(ctx, conv)
 	} else {
 		distilled, err = m.distiller.DistillConversation(conv)
 	}
-	
+
 	if err != nil {
 		return fmt.Errorf("distill conversation: %w", err)
 	}
 
 	// Create warm entry
+	agentID := conv.AgentID
+	if agentID == "" {
+		agentID = m.cfg.AgentID
+	}
 	entry := &WarmEntry{
 		ID:          uuid.New().String(),
+		AgentID:     agentID,
 		Timestamp:   conv.Timestamp,
 		EventType:   "conversation",
 		Category:    category,
 		Content:     distilled,
 		Importance:  importance,
 		AccessCount: 0,
-		CreatedAt:   time.Now(),
+		CreatedAt:   m.clock.Now(),
 	}
 
 	// Add to warm tier
@@ -242,6 +270,8 @@ func (m *Manager) ProcessConversation(ctx context.Context, conv RawConversation,
 		m.logger.Warn("failed to update tree counts", "category", category, "error", err)
 	}
 
+	m.enforceWarmLimit(ctx, agentID)
+
 	m.logger.Debug("processed conversation",
 		"category", category,
 		"importance", importance,
@@ -250,6 +280,37 @@ func (m *Manager) ProcessConversation(ctx context.Context, conv RawConversation,
 	return nil
 }
 
+// forcedConsolidationTarget is the fraction of WarmForceConsolidateKB a
+// forced consolidation shrinks the warm tier to, leaving headroom so a
+// burst doesn't force another on the very next memory.
+const forcedConsolidationTarget = 0.75
+
+// enforceWarmLimit forces a synchronous consolidation when an agent's
+// entries in the warm tier have grown past WarmForceConsolidateKB. Only
+// that agent's entries are consolidated, so one chatty agent can't push
+// out another's memories.
+func (m *Manager) enforceWarmLimit(ctx context.Context, agentID string) {
+	limit := m.cfg.WarmForceConsolidateKB * 1024
+	if limit <= 0 {
+		return
+	}
+
+	m.forceMu.Lock()
+	defer m.forceMu.Unlock()
+
+	size := m.warm.AgentSize(agentID)
+	if size <= limit {
+		return
+	}
+
+	m.logger.Warn("warm memory over limit, forcing consolidation",
+		"agent_id", agentID,
+		"warm_bytes", size,
+		"limit_bytes", limit)
+	m.consolidator.ConsolidateWarm(ctx, agentID, int(float64(limit)*forcedConsolidationTarget))
+	m.forced++
+}
+
 // Retrieve finds relevant memories for a query
 func (m *Manager) Retrieve(ctx context.Context, query string, maxResults int) ([]*WarmEntry, error) {
 	// Search tree index (use LLM searcher if available)
@@ -302,14 +363,14 @@ func (m *Manager) Retrieve(ctx context.Context, query string, maxResults int) ([
 				}
 
 				warmEntry := &WarmEntry{
-					ID:           coldEntry.ID,
-					Timestamp:    time.Unix(coldEntry.Timestamp, 0),
-					EventType:    coldEntry.EventType,
-					Category:     coldEntry.Category,
-					Content:      &distilled,
-					Importance:   coldEntry.Importance,
-					AccessCount:  coldEntry.AccessCount,
-					CreatedAt:    time.Unix(coldEntry.CreatedAt, 0),
+					ID:          coldEntry.ID,
+					Timestamp:   time.Unix(coldEntry.Timestamp, 0),
+					EventType:   coldEntry.EventType,
+					Category:    coldEntry.Category,
+					Content:     &distilled,
+					Importance:  coldEntry.Importance,
+					AccessCount: coldEntry.AccessCount,
+					CreatedAt:   time.Unix(coldEntry.CreatedAt, 0),
 				}
 				if coldEntry.LastAccessed != nil {
 					warmEntry.LastAccessed = time.Unix(*coldEntry.LastAccessed, 0)
@@ -355,32 +416,40 @@ func (m *Manager) GetStats(ctx context.Context) (MemoryStats, error) {
 
 	treeData, _ := m.tree.Serialize()
 
+	m.forceMu.Lock()
+	forced := m.forced
+	m.forceMu.Unlock()
+
 	return MemoryStats{
-		HotSizeBytes:   hotSize,
-		HotCapacity:    m.cfg.HotMaxBytes,
-		WarmCount:      warmStats.TotalEntries,
-		WarmSizeBytes:  warmStats.TotalSizeBytes,
-		WarmCapacity:   warmStats.CapacityBytes,
-		ColdCount:      coldCount,
-		TreeNodes:      m.tree.NodeCount,
-		TreeDepth:      m.tree.GetDepth(),
-		TreeSizeBytes:  len(treeData),
-		TreeCapacity:   MaxTreeSizeBytes,
+		HotSizeBytes:         hotSize,
+		HotCapacity:          m.cfg.HotMaxBytes,
+		WarmCount:            warmStats.TotalEntries,
+		WarmSizeBytes:        warmStats.TotalSizeBytes,
+		WarmCapacity:         warmStats.CapacityBytes,
+		ColdCount:            coldCount,
+		TreeNodes:            m.tree.NodeCount,
+		TreeDepth:            m.tree.GetDepth(),
+		TreeSizeBytes:        len(treeData),
+		TreeCapacity:         MaxTreeSizeBytes,
+		ForcedConsolidations: forced,
 	}, nil
 }
 
 // MemoryStats holds statistics about the memory system
 type MemoryStats struct {
-	HotSizeBytes   int
-	HotCapacity    int
-	WarmCount      int
-	WarmSizeBytes  int
-	WarmCapacity   int
-	ColdCount      int
-	TreeNodes      int
-	TreeDepth      int
-	TreeSizeBytes  int
-	TreeCapacity   int
+	HotSizeBytes  int
+	HotCapacity   int
+	WarmCount     int
+	WarmSizeBytes int
+	WarmCapacity  int
+	ColdCount     int
+	TreeNodes     int
+	TreeDepth     int
+	TreeSizeBytes int
+	TreeCapacity  int
+	// ForcedConsolidations counts consolidations forced by
+	// WarmForceConsolidateKB since the manager started.
+	ForcedConsolidations int
 }
 
 // AddLesson adds a critical lesson to hot memory
@@ -388,7 +457,7 @@ func (m *Manager) AddLesson(text, category string, importance float64) error {
 	lesson := Lesson{
 		Text:       text,
 		Importance: importance,
-		LearnedAt:  time.Now(),
+		LearnedAt:  m.clock.Now(),
 		Category:   category,
 	}
 
@@ -405,13 +474,20 @@ func (m *Manager) AddProject(name, description string) error {
 	project := Project{
 		Name:        name,
 		Description: description,
-		StartDate:   time.Now(),
+		StartDate:   m.clock.Now(),
 		Status:      "active",
 	}
 
 	return m.hot.AddProject(project)
 }
 
+// SetClock replaces the clock used to timestamp and age memories. Call it
+// before the manager is in use.
+func (m *Manager) SetClock(c clock.Clock) {
+	m.clock = c
+	m.warm.SetClock(c)
+}
+
 // SetLLMFunc sets the LLM call function and initializes LLM-powered components
 func (m *Manager) SetLLMFunc(llmFunc LLMCallFunc, model string) {
 	m.llmFunc = llmFunc
@@ -428,6 +504,10 @@ func (m *Manager) SetLLMFunc(llmFunc LLMCallFunc, model string) {
 
 	// Create LLM-powered distiller
 	m.llmDistiller = NewLLMDistiller(m.distiller, llmFunc, model, m.logger)
+	if m.cfg.DistillationTimeout > 0 {
+		m.llmDistiller.SetTimeout(m.cfg.DistillationTimeout)
+	}
+	m.llmDistiller.SetRetries(m.cfg.DistillationRetries, m.cfg.DistillationRetryBackoff)
 
 	// Create LLM-powered tree searcher
 	m.llmSearcher = NewLLMTreeSearcher(m.tree, m.searcher, llmFunc, m.logger)
@@ -456,10 +536,11 @@ func (m *Manager) GetConsolidator() *Consolidator {
 func (m *Manager) Store(ctx context.Context, entry *MemoryEntry) error {
 	// Add to warm tier
 	warmEntry := &WarmEntry{
-		ID:          entry.ID,
-		Timestamp:   entry.CreatedAt,
-		EventType:   "manual",
-		Category:    entry.Category,
+		ID:        entry.ID,
+		AgentID:   m.cfg.AgentID,
+		Timestamp: entry.CreatedAt,
+		EventType: "manual",
+		Category:  entry.Category,
 		Content: &DistilledFact{
 			Fact:   entry.Text,
 			Topics: []string{},
@@ -480,6 +561,8 @@ func (m *Manager) Store(ctx context.Context, entry *MemoryEntry) error {
 		m.logger.Warn("failed to update tree counts", "category", entry.Category, "error", err)
 	}
 
+	m.enforceWarmLimit(ctx, m.cfg.AgentID)
+
 	return nil
 }
 
@@ -491,16 +574,16 @@ func (m *Manager) Search(ctx context.Context, query string, maxResults int) ([]*
 		if len(results) > 0 && results[0].Score > 0 {
 			// Use top result category
 			category := results[0].Path
-			
+
 			// Retrieve from category
 			warmResults := m.warm.GetByCategory(category)
-			
+
 			// Limit results
 			limit := maxResults
 			if len(warmResults) < limit {
 				limit = len(warmResults)
 			}
-			
+
 			entries := make([]*MemoryEntry, limit)
 			for i := 0; i < limit; i++ {
 				w := warmResults[i]
@@ -546,15 +629,16 @@ func (m *Manager) GetStatus(ctx context.Context) (map[string]interface{}, error)
 	}
 
 	return map[string]interface{}{
-		"agent_id":     m.cfg.AgentID,
-		"agent_name":   m.cfg.AgentName,
-		"hot_size":     stats.HotSizeBytes,
-		"warm_count":   stats.WarmCount,
-		"warm_size":    stats.WarmSizeBytes,
-		"cold_count":   stats.ColdCount,
-		"tree_nodes":   stats.TreeNodes,
-		"tree_depth":   stats.TreeDepth,
-		"tree_size":    stats.TreeSizeBytes,
+		"agent_id":              m.cfg.AgentID,
+		"agent_name":            m.cfg.AgentName,
+		"hot_size":              stats.HotSizeBytes,
+		"warm_count":            stats.WarmCount,
+		"warm_size":             stats.WarmSizeBytes,
+		"cold_count":            stats.ColdCount,
+		"tree_nodes":            stats.TreeNodes,
+		"tree_depth":            stats.TreeDepth,
+		"tree_size":             stats.TreeSizeBytes,
+		"forced_consolidations": stats.ForcedConsolidations,
 	}, nil
 }
 
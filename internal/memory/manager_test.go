@@ -2,9 +2,17 @@ package memory
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/clawinfra/evoclaw/internal/cloudsync"
 )
 
 func init() {
@@ -346,3 +354,153 @@ func TestDefaultMemoryConfig(t *testing.T) {
 		t.Errorf("half life: got %.1f, want 30.0", cfg.HalfLifeDays)
 	}
 }
+
+func TestWarmLimitForcesConsolidation(t *testing.T) {
+	var archived atomic.Int64
+	turso := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cloudsync.PipelineRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		for _, br := range req.Requests {
+			if strings.Contains(br.Statement.SQL, "INSERT INTO cold_memory") {
+				archived.Add(1)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(cloudsync.PipelineResponse{
+			Results: []cloudsync.BatchResult{{Type: "ok", RowsAffected: 1}},
+		})
+	}))
+	defer turso.Close()
+
+	cfg := DefaultMemoryConfig()
+	cfg.AgentID = "test-agent"
+	cfg.AgentName = "TestBot"
+	cfg.OwnerName = "TestOwner"
+	cfg.DatabaseURL = turso.URL
+	cfg.AuthToken = "test-token"
+	cfg.WarmMaxKB = 50
+	cfg.WarmForceConsolidateKB = 8
+
+	mgr, err := NewManager(cfg, nil)
+	if err != nil {
+		t.Fatalf("create manager: %v", err)
+	}
+	ctx := context.Background()
+	_ = mgr.tree.AddNode("chat", "Conversations")
+
+	// Flood warm memory well past the cap without any consolidation cycle
+	// running: each conversation is a few hundred bytes once distilled.
+	limit := cfg.WarmForceConsolidateKB * 1024
+	for i := 0; i < 100; i++ {
+		conv := RawConversation{
+			Timestamp: time.Now(),
+			Messages: []Message{
+				{Role: "user", Content: fmt.Sprintf("Burst message %d: %s", i, strings.Repeat("lorem ipsum ", 20))},
+				{Role: "agent", Content: "Noted."},
+			},
+		}
+		if err := mgr.ProcessConversation(ctx, conv, "chat", 0.5); err != nil {
+			t.Fatalf("process conversation %d: %v", i, err)
+		}
+		if size := mgr.warm.GetSize(); size > limit {
+			t.Fatalf("after conversation %d warm size = %d bytes, over the %d byte limit", i, size, limit)
+		}
+	}
+
+	stats, err := mgr.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("get stats: %v", err)
+	}
+	if stats.ForcedConsolidations == 0 {
+		t.Fatal("no forced consolidation ran")
+	}
+	if archived.Load() == 0 {
+		t.Error("forced consolidation archived nothing to cold storage")
+	}
+	if got := 100 - mgr.warm.Count(); int64(got) != archived.Load() {
+		t.Errorf("%d entries left warm but %d were archived", got, archived.Load())
+	}
+	t.Logf("%d forced consolidations, %d entries archived, %d left warm",
+		stats.ForcedConsolidations, archived.Load(), mgr.warm.Count())
+}
+
+func TestWarmLimitDisabled(t *testing.T) {
+	cfg := DefaultMemoryConfig()
+	cfg.AgentID = "test-agent"
+	cfg.AgentName = "TestBot"
+	cfg.OwnerName = "TestOwner"
+	cfg.DatabaseURL = "libsql://test.turso.io"
+	cfg.AuthToken = "test-token"
+
+	mgr, _ := NewManager(cfg, nil)
+	_ = mgr.tree.AddNode("notes", "Notes")
+	for i := 0; i < 20; i++ {
+		if err := mgr.Store(context.Background(), &MemoryEntry{
+			ID: fmt.Sprintf("m-%d", i), Text: strings.Repeat("x", 400), Category: "notes", CreatedAt: time.Now(),
+		}); err != nil {
+			t.Fatalf("store: %v", err)
+		}
+	}
+	if mgr.forced != 0 || mgr.warm.Count() != 20 {
+		t.Errorf("forced = %d, warm count = %d; want no consolidation", mgr.forced, mgr.warm.Count())
+	}
+}
+
+func TestWarmLimitIsPerAgent(t *testing.T) {
+	turso := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(cloudsync.PipelineResponse{
+			Results: []cloudsync.BatchResult{{Type: "ok", RowsAffected: 1}},
+		})
+	}))
+	defer turso.Close()
+
+	cfg := DefaultMemoryConfig()
+	cfg.AgentID = "quiet"
+	cfg.AgentName = "TestBot"
+	cfg.OwnerName = "TestOwner"
+	cfg.DatabaseURL = turso.URL
+	cfg.AuthToken = "test-token"
+	cfg.WarmMaxKB = 50
+	cfg.WarmForceConsolidateKB = 8
+
+	mgr, err := NewManager(cfg, nil)
+	if err != nil {
+		t.Fatalf("create manager: %v", err)
+	}
+	ctx := context.Background()
+	_ = mgr.tree.AddNode("chat", "Conversations")
+
+	say := func(agentID string, i int) {
+		conv := RawConversation{
+			AgentID:   agentID,
+			Timestamp: time.Now(),
+			Messages: []Message{
+				{Role: "user", Content: fmt.Sprintf("Message %d from %s: %s", i, agentID, strings.Repeat("lorem ipsum ", 20))},
+				{Role: "agent", Content: "Noted."},
+			},
+		}
+		if err := mgr.ProcessConversation(ctx, conv, "chat", 0.5); err != nil {
+			t.Fatalf("process conversation: %v", err)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		say("quiet", i)
+	}
+	quietSize := mgr.warm.AgentSize("quiet")
+
+	// A chatty agent blows well past the cap on its own
+	limit := cfg.WarmForceConsolidateKB * 1024
+	for i := 0; i < 60; i++ {
+		say("chatty", i)
+		if size := mgr.warm.AgentSize("chatty"); size > limit {
+			t.Fatalf("chatty agent holds %d bytes, over the %d byte limit", size, limit)
+		}
+	}
+
+	if mgr.forced == 0 {
+		t.Fatal("no forced consolidation ran")
+	}
+	if got := mgr.warm.AgentSize("quiet"); got != quietSize {
+		t.Errorf("quiet agent's warm bytes = %d, want %d untouched", got, quietSize)
+	}
+}
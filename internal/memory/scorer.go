@@ -40,6 +40,11 @@ func DefaultScoreConfig() ScoreConfig {
 //   - recency_decay: exp(-age_days / half_life)
 //   - reinforcement: 1 + (boost × access_count)
 func CalculateScore(importance float64, createdAt time.Time, accessCount int, cfg ScoreConfig) float64 {
+	return CalculateScoreAt(importance, createdAt, accessCount, cfg, time.Now())
+}
+
+// CalculateScoreAt is CalculateScore with the entry's age measured at now.
+func CalculateScoreAt(importance float64, createdAt time.Time, accessCount int, cfg ScoreConfig, now time.Time) float64 {
 	if cfg.HalfLifeDays <= 0 {
 		cfg.HalfLifeDays = 30.0
 	}
@@ -48,7 +53,7 @@ func CalculateScore(importance float64, createdAt time.Time, accessCount int, cf
 	}
 
 	// Calculate age in days
-	age := time.Since(createdAt).Hours() / 24.0
+	age := now.Sub(createdAt).Hours() / 24.0
 
 	// Recency decay: exp(-age / half_life)
 	recency := math.Exp(-age / cfg.HalfLifeDays)
@@ -6,6 +6,8 @@ import (
 	"sort"
 	"sync"
 	"time"
+
+	"github.com/clawinfra/evoclaw/internal/clock"
 )
 
 const (
@@ -20,11 +22,13 @@ type WarmMemory struct {
 	entries map[string]*WarmEntry // keyed by ID
 	mu      sync.RWMutex
 	cfg     WarmConfig
+	clock   clock.Clock
 }
 
 // WarmEntry represents a single warm memory entry
 type WarmEntry struct {
 	ID           string         `json:"id"`
+	AgentID      string         `json:"agent_id,omitempty"` // owning agent
 	Timestamp    time.Time      `json:"timestamp"`
 	EventType    string         `json:"event_type"` // "conversation", "decision", "lesson"
 	Category     string         `json:"category"`   // tree node path
@@ -58,9 +62,17 @@ func NewWarmMemory(cfg WarmConfig) *WarmMemory {
 	return &WarmMemory{
 		entries: make(map[string]*WarmEntry),
 		cfg:     cfg,
+		clock:   clock.System,
 	}
 }
 
+// SetClock replaces the clock used to age and score entries.
+func (w *WarmMemory) SetClock(c clock.Clock) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.clock = c
+}
+
 // Add adds a new warm memory entry
 func (w *WarmMemory) Add(entry *WarmEntry) error {
 	w.mu.Lock()
@@ -77,8 +89,9 @@ func (w *WarmMemory) Add(entry *WarmEntry) error {
 		}
 	}
 
-	entry.CreatedAt = time.Now()
-	entry.LastAccessed = time.Now()
+	now := w.clock.Now()
+	entry.CreatedAt = now
+	entry.LastAccessed = now
 	w.entries[entry.ID] = entry
 
 	return nil
@@ -96,7 +109,7 @@ func (w *WarmMemory) Get(id string) (*WarmEntry, error) {
 
 	// Increment access count (reinforcement)
 	entry.AccessCount++
-	entry.LastAccessed = time.Now()
+	entry.LastAccessed = w.clock.Now()
 
 	return entry, nil
 }
@@ -169,12 +182,40 @@ func (w *WarmMemory) GetAll() []*WarmEntry {
 func (w *WarmMemory) EvictExpired() []*WarmEntry {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	return w.evictExpiredUnlocked(func(*WarmEntry) bool { return true })
+}
+
+// EvictToSize removes the lowest-scored entries until the tier is no
+// larger than maxBytes. Returns the removed entries for archival.
+func (w *WarmMemory) EvictToSize(maxBytes int) []*WarmEntry {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.evictToSizeUnlocked(maxBytes, func(*WarmEntry) bool { return true })
+}
+
+// EvictAgentToSize is EvictToSize for one agent's entries: expired ones
+// go first, then the lowest-scored, until the agent holds no more than
+// maxBytes. Other agents' entries are untouched.
+func (w *WarmMemory) EvictAgentToSize(agentID string, maxBytes int) []*WarmEntry {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	owned := func(e *WarmEntry) bool { return e.AgentID == agentID }
+	evicted := w.evictExpiredUnlocked(owned)
+	return append(evicted, w.evictToSizeUnlocked(maxBytes, owned)...)
+}
 
+// evictExpiredUnlocked removes the matching entries due for eviction
+// (must hold lock).
+func (w *WarmMemory) evictExpiredUnlocked(match func(*WarmEntry) bool) []*WarmEntry {
 	evicted := make([]*WarmEntry, 0)
 
 	for id, entry := range w.entries {
+		if !match(entry) {
+			continue
+		}
 		score := w.calculateScore(entry)
-		age := time.Since(entry.Timestamp)
+		age := clock.Since(w.clock, entry.Timestamp)
 
 		if ShouldEvictFromWarm(score, age, w.cfg.RetentionDays, w.cfg.EvictionThreshold) {
 			evicted = append(evicted, entry)
@@ -185,6 +226,38 @@ func (w *WarmMemory) EvictExpired() []*WarmEntry {
 	return evicted
 }
 
+// evictToSizeUnlocked removes the lowest-scored matching entries until
+// the matching entries total no more than maxBytes (must hold lock).
+func (w *WarmMemory) evictToSizeUnlocked(maxBytes int, match func(*WarmEntry) bool) []*WarmEntry {
+	size := 0
+	entries := make([]*WarmEntry, 0, len(w.entries))
+	for _, entry := range w.entries {
+		if match(entry) {
+			size += w.estimateEntrySize(entry)
+			entries = append(entries, entry)
+		}
+	}
+	if size <= maxBytes {
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return w.calculateScore(entries[i]) < w.calculateScore(entries[j])
+	})
+
+	evicted := make([]*WarmEntry, 0)
+	for _, entry := range entries {
+		if size <= maxBytes {
+			break
+		}
+		size -= w.estimateEntrySize(entry)
+		delete(w.entries, entry.ID)
+		evicted = append(evicted, entry)
+	}
+
+	return evicted
+}
+
 // evictLowScoreEntriesUnlocked evicts entries to free up space (must hold lock)
 func (w *WarmMemory) evictLowScoreEntriesUnlocked(neededBytes int) error {
 	// Calculate scores for all entries
@@ -225,11 +298,12 @@ func (w *WarmMemory) evictLowScoreEntriesUnlocked(neededBytes int) error {
 
 // calculateScore computes the relevance score for an entry
 func (w *WarmMemory) calculateScore(entry *WarmEntry) float64 {
-	return CalculateScore(
+	return CalculateScoreAt(
 		entry.Importance,
 		entry.Timestamp,
 		entry.AccessCount,
 		w.cfg.ScoreConfig,
+		w.clock.Now(),
 	)
 }
 
@@ -259,6 +333,20 @@ func (w *WarmMemory) GetSize() int {
 	return w.calculateSizeUnlocked()
 }
 
+// AgentSize returns the size in bytes of one agent's entries
+func (w *WarmMemory) AgentSize(agentID string) int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	total := 0
+	for _, entry := range w.entries {
+		if entry.AgentID == agentID {
+			total += w.estimateEntrySize(entry)
+		}
+	}
+	return total
+}
+
 // Count returns the number of entries
 func (w *WarmMemory) Count() int {
 	w.mu.RLock()
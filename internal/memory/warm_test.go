@@ -1,8 +1,11 @@
 package memory
 
 import (
+	"math"
 	"testing"
 	"time"
+
+	"github.com/clawinfra/evoclaw/internal/clock"
 )
 
 func TestNewWarmMemory(t *testing.T) {
@@ -210,6 +213,39 @@ func TestWarmEvictExpired(t *testing.T) {
 	}
 }
 
+func TestWarmDecaysOnClock(t *testing.T) {
+	cfg := DefaultWarmConfig()
+	warm := NewWarmMemory(cfg)
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	warm.SetClock(fake)
+
+	entry := &WarmEntry{
+		ID:         "fact",
+		Timestamp:  fake.Now(),
+		Category:   "test",
+		Content:    &DistilledFact{Fact: "Fact", Date: fake.Now()},
+		Importance: 0.9,
+	}
+	_ = warm.Add(entry)
+
+	// One half-life later the score has decayed by a factor of e
+	days := cfg.ScoreConfig.HalfLifeDays
+	fake.Advance(time.Duration(days*24) * time.Hour)
+	want := 0.9 * math.Exp(-1)
+	if got := warm.calculateScore(entry); math.Abs(got-want) > 1e-9 {
+		t.Errorf("score after one half-life = %v, want %v", got, want)
+	}
+	if evicted := warm.EvictExpired(); len(evicted) != 0 {
+		t.Fatalf("entry above threshold and within retention evicted")
+	}
+
+	// Past the retention period it goes, without anything sleeping
+	fake.Advance(48 * time.Hour)
+	if evicted := warm.EvictExpired(); len(evicted) != 1 {
+		t.Errorf("evicted %d entries past retention, want 1", len(evicted))
+	}
+}
+
 func TestWarmSizeLimit(t *testing.T) {
 	cfg := DefaultWarmConfig()
 	cfg.MaxSizeBytes = 1024 // Small limit for testing
@@ -11,6 +11,7 @@ import (
 
 	"github.com/clawinfra/evoclaw/internal/config"
 	"github.com/clawinfra/evoclaw/internal/orchestrator"
+	"github.com/clawinfra/evoclaw/internal/tracing"
 )
 
 // AnthropicProvider implements ModelProvider for Anthropic's API
@@ -23,10 +24,12 @@ type AnthropicProvider struct {
 }
 
 type anthropicRequest struct {
-	Model     string             `json:"model"`
-	MaxTokens int                `json:"max_tokens"`
-	System    string             `json:"system,omitempty"`
-	Messages  []anthropicMessage `json:"messages"`
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float64            `json:"temperature,omitempty"`
+	TopP        float64            `json:"top_p,omitempty"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
 }
 
 type anthropicMessage struct {
@@ -35,21 +38,26 @@ type anthropicMessage struct {
 }
 
 type anthropicResponse struct {
-	ID      string `json:"id"`
-	Type    string `json:"type"`
-	Role    string `json:"role"`
-	Content []struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
-	} `json:"content"`
-	Model      string `json:"model"`
-	StopReason string `json:"stop_reason"`
+	ID         string                  `json:"id"`
+	Type       string                  `json:"type"`
+	Role       string                  `json:"role"`
+	Content    []anthropicContentBlock `json:"content"`
+	Model      string                  `json:"model"`
+	StopReason string                  `json:"stop_reason"`
 	Usage      struct {
 		InputTokens  int `json:"input_tokens"`
 		OutputTokens int `json:"output_tokens"`
 	} `json:"usage"`
 }
 
+// anthropicContentBlock is one block of a response: "text", or "thinking"
+// when extended thinking is on.
+type anthropicContentBlock struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Thinking string `json:"thinking,omitempty"`
+}
+
 type anthropicError struct {
 	Type  string `json:"type"`
 	Error struct {
@@ -88,7 +96,17 @@ func (p *AnthropicProvider) SetName(name string) {
 
 func (p *AnthropicProvider) Models() []config.Model { return p.models }
 
+// Chat sends a chat request. Errors are scrubbed of credentials so they
+// can be logged safely.
 func (p *AnthropicProvider) Chat(ctx context.Context, req orchestrator.ChatRequest) (*orchestrator.ChatResponse, error) {
+	resp, err := p.chat(ctx, req)
+	if err != nil {
+		return nil, config.RedactError(err, p.apiKey)
+	}
+	return resp, nil
+}
+
+func (p *AnthropicProvider) chat(ctx context.Context, req orchestrator.ChatRequest) (*orchestrator.ChatResponse, error) {
 	// Convert messages
 	msgs := make([]anthropicMessage, len(req.Messages))
 	for i, m := range req.Messages {
@@ -104,10 +122,12 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req orchestrator.ChatReque
 	}
 
 	body := anthropicRequest{
-		Model:     req.Model,
-		MaxTokens: maxTokens,
-		System:    req.SystemPrompt,
-		Messages:  msgs,
+		Model:       req.Model,
+		MaxTokens:   maxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		System:      req.SystemPrompt,
+		Messages:    msgs,
 	}
 
 	jsonBody, err := json.Marshal(body)
@@ -122,6 +142,7 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req orchestrator.ChatReque
 
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("x-api-key", p.apiKey)
+	tracing.Inject(ctx, httpReq.Header)
 	httpReq.Header.Set("anthropic-version", "2023-06-01")
 
 	resp, err := p.client.Do(httpReq)
@@ -150,17 +171,22 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req orchestrator.ChatReque
 	}
 
 	content := ""
+	var thinking []string
 	for _, c := range apiResp.Content {
-		if c.Type == "text" {
+		switch c.Type {
+		case "text":
 			content += c.Text
+		case "thinking":
+			thinking = append(thinking, c.Thinking)
 		}
 	}
 
 	return &orchestrator.ChatResponse{
-		Content:      content,
-		Model:        apiResp.Model,
-		TokensInput:  apiResp.Usage.InputTokens,
-		TokensOutput: apiResp.Usage.OutputTokens,
-		FinishReason: apiResp.StopReason,
+		Content:          content,
+		Model:            apiResp.Model,
+		TokensInput:      apiResp.Usage.InputTokens,
+		TokensOutput:     apiResp.Usage.OutputTokens,
+		FinishReason:     apiResp.StopReason,
+		ReasoningContent: joinReasoning(thinking...),
 	}, nil
 }
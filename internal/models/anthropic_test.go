@@ -73,10 +73,7 @@ func TestAnthropicChatSuccess(t *testing.T) {
 			ID:   "msg_123",
 			Type: "message",
 			Role: "assistant",
-			Content: []struct {
-				Type string `json:"type"`
-				Text string `json:"text"`
-			}{
+			Content: []anthropicContentBlock{
 				{Type: "text", Text: "Hello! How can I assist you today?"},
 			},
 			Model:      "claude-sonnet-4",
@@ -185,13 +182,10 @@ func TestAnthropicChatWithSystemPrompt(t *testing.T) {
 		}
 
 		resp := anthropicResponse{
-			ID:   "msg_123",
-			Type: "message",
-			Role: "assistant",
-			Content: []struct {
-				Type string `json:"type"`
-				Text string `json:"text"`
-			}{{Type: "text", Text: "OK"}},
+			ID:         "msg_123",
+			Type:       "message",
+			Role:       "assistant",
+			Content:    []anthropicContentBlock{{Type: "text", Text: "OK"}},
 			Model:      "claude-sonnet-4",
 			StopReason: "end_turn",
 		}
@@ -225,10 +219,7 @@ func TestAnthropicChatMultipleContentBlocks(t *testing.T) {
 			ID:   "msg_123",
 			Type: "message",
 			Role: "assistant",
-			Content: []struct {
-				Type string `json:"type"`
-				Text string `json:"text"`
-			}{
+			Content: []anthropicContentBlock{
 				{Type: "text", Text: "First part. "},
 				{Type: "text", Text: "Second part."},
 			},
@@ -263,3 +254,28 @@ func TestAnthropicChatMultipleContentBlocks(t *testing.T) {
 		t.Errorf("expected '%s', got '%s'", expected, resp.Content)
 	}
 }
+
+func TestAnthropicChatSendsSamplingParams(t *testing.T) {
+	var got anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"ok"}],"model":"claude-sonnet-4","stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer server.Close()
+
+	p := NewAnthropicProvider(config.ProviderConfig{BaseURL: server.URL, APIKey: "test-key"})
+	_, err := p.Chat(context.Background(), orchestrator.ChatRequest{
+		Model:       "claude-sonnet-4",
+		Messages:    []orchestrator.ChatMessage{{Role: "user", Content: "hi"}},
+		MaxTokens:   256,
+		Temperature: 0.2,
+		TopP:        0.9,
+	})
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	if got.MaxTokens != 256 || got.Temperature != 0.2 || got.TopP != 0.9 {
+		t.Errorf("request params = max_tokens %d, temperature %g, top_p %g", got.MaxTokens, got.Temperature, got.TopP)
+	}
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// DefaultModelRefreshInterval is how often discovered model lists are
+// refreshed when the provider config does not set modelRefreshSec.
+const DefaultModelRefreshInterval = time.Hour
+
+// ModelRefresher is implemented by providers that can list their models
+// from the provider's API. RefreshModels replaces the list Models returns;
+// on error the previous list is kept.
+type ModelRefresher interface {
+	RefreshModels(ctx context.Context) error
+}
+
+// modelCatalog holds a provider's configured models and the list merged
+// with its most recent discovery.
+type modelCatalog struct {
+	mu         sync.RWMutex
+	configured []config.Model
+	current    []config.Model
+}
+
+func newModelCatalog(configured []config.Model) *modelCatalog {
+	return &modelCatalog{configured: configured, current: configured}
+}
+
+func (c *modelCatalog) list() []config.Model {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.current
+}
+
+// update merges discovered model IDs with the configured models.
+func (c *modelCatalog) update(discovered []string) {
+	merged := mergeModels(c.configured, discovered)
+	c.mu.Lock()
+	c.current = merged
+	c.mu.Unlock()
+}
+
+// mergeModels returns the configured models followed by discovered models
+// that aren't configured. Configured entries win, so their pricing,
+// context window and display name apply, and configured models stay
+// available even if the provider stops listing them.
+func mergeModels(configured []config.Model, discovered []string) []config.Model {
+	merged := make([]config.Model, 0, len(configured)+len(discovered))
+	seen := make(map[string]bool, len(configured))
+	for _, m := range configured {
+		merged = append(merged, m)
+		seen[m.ID] = true
+	}
+	for _, id := range discovered {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		merged = append(merged, config.Model{ID: id, Name: id})
+	}
+	return merged
+}
+
+// fetchModelList GETs url and decodes the JSON body into v.
+func fetchModelList(ctx context.Context, client *http.Client, url, apiKey string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("list models: HTTP %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unmarshal model list: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func findModel(r *Router, id string) *ModelInfo {
+	for _, m := range r.ListModels() {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestRefreshModelsAddsDiscoveredOpenAIModels(t *testing.T) {
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"gpt-4o"},{"id":"gpt-4o-mini"}]}`)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider("openai", config.ProviderConfig{
+		BaseURL: server.URL,
+		APIKey:  "sk-test",
+		Models: []config.Model{
+			{ID: "gpt-4o", Name: "GPT-4o", CostInput: 2.5, CostOutput: 10},
+		},
+	})
+	r := newTestRouter()
+	r.RegisterProvider(p)
+
+	if err := r.RefreshModels(context.Background(), "openai"); err != nil {
+		t.Fatalf("RefreshModels: %v", err)
+	}
+
+	if got := len(r.ListModels()); got != 2 {
+		t.Fatalf("ListModels() = %d models, want 2", got)
+	}
+	configured := findModel(r, "openai/gpt-4o")
+	if configured == nil || configured.Config.CostInput != 2.5 || configured.Config.Name != "GPT-4o" {
+		t.Errorf("configured override lost: %+v", configured)
+	}
+	if findModel(r, "openai/gpt-4o-mini") == nil {
+		t.Error("discovered model gpt-4o-mini not listed")
+	}
+
+	// A failed refresh keeps the last good list
+	fail.Store(true)
+	err := r.RefreshModels(context.Background(), "openai")
+	if err == nil {
+		t.Fatal("expected error from failing /models endpoint")
+	}
+	if strings.Contains(err.Error(), "sk-test") {
+		t.Errorf("error leaks API key: %v", err)
+	}
+	if findModel(r, "openai/gpt-4o-mini") == nil {
+		t.Error("discovered model dropped after failed refresh")
+	}
+}
+
+func TestRefreshModelsFallsBackToConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`not json`)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider("local", config.ProviderConfig{
+		BaseURL: server.URL,
+		Models:  []config.Model{{ID: "llama3", Name: "Llama 3"}},
+	})
+	r := newTestRouter()
+	r.RegisterProvider(WithLimits(p, config.ProviderConfig{MaxConcurrent: 1}))
+
+	if err := r.RefreshModels(context.Background(), "local"); err == nil {
+		t.Fatal("expected error for malformed model list")
+	}
+	models := r.ListModels()
+	if len(models) != 1 || models[0].ID != "local/llama3" {
+		t.Errorf("ListModels() = %v, want only configured local/llama3", models)
+	}
+}
+
+func TestRefreshModelsOllamaTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"models":[{"name":"qwen2.5:7b"},{"name":"llama3.2:3b"}]}`)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	p := NewOllamaProvider(config.ProviderConfig{BaseURL: server.URL})
+	r := newTestRouter()
+	r.RegisterProvider(p)
+	if len(r.ListModels()) != 0 {
+		t.Fatal("expected no models before discovery")
+	}
+
+	if err := r.RefreshModels(context.Background(), "ollama"); err != nil {
+		t.Fatalf("RefreshModels: %v", err)
+	}
+	for _, id := range []string{"ollama/qwen2.5:7b", "ollama/llama3.2:3b"} {
+		if findModel(r, id) == nil {
+			t.Errorf("discovered model %s not listed", id)
+		}
+	}
+}
+
+func TestRefreshModelsWithoutDiscoveryIsNoop(t *testing.T) {
+	r := newTestRouter()
+	r.RegisterProvider(&mockProvider{name: "mock", models: []config.Model{{ID: "m1"}}})
+
+	if err := r.RefreshModels(context.Background(), "mock"); err != nil {
+		t.Errorf("RefreshModels on non-discovering provider: %v", err)
+	}
+	if err := r.RefreshModels(context.Background(), "missing"); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+	if len(r.ListModels()) != 1 {
+		t.Error("models changed for provider without discovery")
+	}
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// ErrProviderBusy is returned when a call waits longer than the queue
+// timeout for one of a provider's concurrency slots.
+var ErrProviderBusy = errors.New("provider busy")
+
+// defaultProviderQueueTimeout bounds the wait for a concurrency slot when
+// the provider config does not set queueTimeoutMs.
+const defaultProviderQueueTimeout = 5 * time.Second
+
+// LimitedProvider wraps a ModelProvider with a per-call timeout and a cap on
+// concurrent calls, so one slow provider cannot be hammered by every agent
+// at once. Calls over the cap queue briefly, then fail with ErrProviderBusy.
+type LimitedProvider struct {
+	orchestrator.ModelProvider
+
+	timeout       time.Duration
+	modelTimeouts map[string]time.Duration // model ID -> timeout
+	slots         chan struct{}            // nil = unlimited
+	queueTimeout  time.Duration
+}
+
+// WithLimits applies the timeout and concurrency settings in cfg to p. It
+// returns p unchanged when cfg sets no limits.
+func WithLimits(p orchestrator.ModelProvider, cfg config.ProviderConfig) orchestrator.ModelProvider {
+	lp := &LimitedProvider{
+		ModelProvider: p,
+		timeout:       time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		modelTimeouts: make(map[string]time.Duration),
+		queueTimeout:  time.Duration(cfg.QueueTimeoutMs) * time.Millisecond,
+	}
+	for _, m := range cfg.Models {
+		if m.TimeoutMs > 0 {
+			lp.modelTimeouts[m.ID] = time.Duration(m.TimeoutMs) * time.Millisecond
+		}
+	}
+	if cfg.MaxConcurrent > 0 {
+		lp.slots = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	if lp.queueTimeout <= 0 {
+		lp.queueTimeout = defaultProviderQueueTimeout
+	}
+
+	if lp.timeout <= 0 && len(lp.modelTimeouts) == 0 && lp.slots == nil {
+		return p
+	}
+	return lp
+}
+
+// Chat waits for a concurrency slot, then calls the wrapped provider under
+// the model's timeout.
+func (p *LimitedProvider) Chat(ctx context.Context, req orchestrator.ChatRequest) (*orchestrator.ChatResponse, error) {
+	if p.slots != nil {
+		timer := time.NewTimer(p.queueTimeout)
+		select {
+		case p.slots <- struct{}{}:
+			timer.Stop()
+		case <-timer.C:
+			return nil, fmt.Errorf("%s: %w: %d calls in flight", p.Name(), ErrProviderBusy, cap(p.slots))
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+		defer func() { <-p.slots }()
+	}
+
+	timeout := p.timeout
+	if d, ok := p.modelTimeouts[req.Model]; ok {
+		timeout = d
+	}
+	if timeout <= 0 {
+		return p.ModelProvider.Chat(ctx, req)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := p.ModelProvider.Chat(callCtx, req)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s: call timed out after %s: %w", p.Name(), timeout, err)
+	}
+	return resp, err
+}
+
+// RefreshModels forwards to the wrapped provider when it supports model
+// discovery.
+func (p *LimitedProvider) RefreshModels(ctx context.Context) error {
+	if r, ok := p.ModelProvider.(ModelRefresher); ok {
+		return r.RefreshModels(ctx)
+	}
+	return nil
+}
+
+// ReleaseModel forwards to the wrapped provider when it can unload models,
+// like Ollama. Otherwise it returns errors.ErrUnsupported.
+func (p *LimitedProvider) ReleaseModel(ctx context.Context, model string) error {
+	if r, ok := p.ModelProvider.(interface {
+		ReleaseModel(ctx context.Context, model string) error
+	}); ok {
+		return r.ReleaseModel(ctx, model)
+	}
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+// slowProvider returns a mock provider that takes delay per call and
+// records the peak number of concurrent calls.
+func slowProvider(delay time.Duration, peak *atomic.Int32) *mockProvider {
+	var active atomic.Int32
+	return &mockProvider{
+		name: "slow",
+		chatFunc: func(ctx context.Context, req orchestrator.ChatRequest) (*orchestrator.ChatResponse, error) {
+			n := active.Add(1)
+			defer active.Add(-1)
+			for {
+				old := peak.Load()
+				if n <= old || peak.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			select {
+			case <-time.After(delay):
+				return &orchestrator.ChatResponse{Content: "ok"}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	}
+}
+
+func TestWithLimits_CapsConcurrentCalls(t *testing.T) {
+	var peak atomic.Int32
+	p := WithLimits(slowProvider(20*time.Millisecond, &peak), config.ProviderConfig{
+		MaxConcurrent:  2,
+		QueueTimeoutMs: 5000,
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.Chat(context.Background(), orchestrator.ChatRequest{Model: "m"}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Chat: %v", err)
+	}
+	if got := peak.Load(); got != 2 {
+		t.Errorf("peak concurrent calls = %d, want 2", got)
+	}
+}
+
+func TestWithLimits_BusyAfterQueueTimeout(t *testing.T) {
+	var peak atomic.Int32
+	p := WithLimits(slowProvider(200*time.Millisecond, &peak), config.ProviderConfig{
+		MaxConcurrent:  1,
+		QueueTimeoutMs: 20,
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = p.Chat(context.Background(), orchestrator.ChatRequest{})
+	}()
+	time.Sleep(10 * time.Millisecond) // let the first call take the slot
+
+	start := time.Now()
+	_, err := p.Chat(context.Background(), orchestrator.ChatRequest{})
+	if !errors.Is(err, ErrProviderBusy) {
+		t.Fatalf("err = %v, want ErrProviderBusy", err)
+	}
+	if waited := time.Since(start); waited > 150*time.Millisecond {
+		t.Errorf("waited %s for a slot, want about the queue timeout", waited)
+	}
+	<-done
+	if got := peak.Load(); got != 1 {
+		t.Errorf("peak concurrent calls = %d, want 1", got)
+	}
+}
+
+func TestWithLimits_Timeouts(t *testing.T) {
+	var peak atomic.Int32
+	p := WithLimits(slowProvider(100*time.Millisecond, &peak), config.ProviderConfig{
+		TimeoutMs: 10,
+		Models: []config.Model{
+			{ID: "patient", TimeoutMs: 1000},
+		},
+	})
+
+	_, err := p.Chat(context.Background(), orchestrator.ChatRequest{Model: "fast"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("provider timeout: err = %v, want deadline exceeded", err)
+	}
+
+	if _, err := p.Chat(context.Background(), orchestrator.ChatRequest{Model: "patient"}); err != nil {
+		t.Errorf("model timeout should override the provider's: %v", err)
+	}
+}
+
+func TestWithLimits_NoLimitsReturnsProvider(t *testing.T) {
+	mp := &mockProvider{name: "plain"}
+	if got := WithLimits(mp, config.ProviderConfig{}); got != orchestrator.ModelProvider(mp) {
+		t.Errorf("WithLimits with no limits = %T, want the provider itself", got)
+	}
+
+	lp := WithLimits(mp, config.ProviderConfig{MaxConcurrent: 1})
+	if lp.Name() != "plain" {
+		t.Errorf("Name() = %q, want the wrapped provider's", lp.Name())
+	}
+}
+
+// releasingProvider records the models it is asked to release.
+type releasingProvider struct {
+	mockProvider
+	released []string
+}
+
+func (p *releasingProvider) ReleaseModel(ctx context.Context, model string) error {
+	p.released = append(p.released, model)
+	return nil
+}
+
+func TestWithLimits_ForwardsReleaseModel(t *testing.T) {
+	rp := &releasingProvider{mockProvider: mockProvider{name: "ollama"}}
+	lp := WithLimits(rp, config.ProviderConfig{MaxConcurrent: 1}).(*LimitedProvider)
+	if err := lp.ReleaseModel(context.Background(), "llama3"); err != nil {
+		t.Fatalf("ReleaseModel: %v", err)
+	}
+	if len(rp.released) != 1 || rp.released[0] != "llama3" {
+		t.Errorf("released = %v, want [llama3]", rp.released)
+	}
+
+	plain := WithLimits(&mockProvider{name: "plain"}, config.ProviderConfig{MaxConcurrent: 1}).(*LimitedProvider)
+	if err := plain.ReleaseModel(context.Background(), "m"); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("err = %v, want errors.ErrUnsupported", err)
+	}
+}
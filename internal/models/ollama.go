@@ -11,12 +11,13 @@ import (
 
 	"github.com/clawinfra/evoclaw/internal/config"
 	"github.com/clawinfra/evoclaw/internal/orchestrator"
+	"github.com/clawinfra/evoclaw/internal/tracing"
 )
 
 // OllamaProvider implements ModelProvider for local Ollama inference
 type OllamaProvider struct {
 	baseURL string
-	models  []config.Model
+	models  *modelCatalog
 	client  *http.Client
 }
 
@@ -30,11 +31,15 @@ type ollamaChatRequest struct {
 type ollamaMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	// Thinking is set by thinking-capable models; older versions inline
+	// it in Content as <think>...</think> instead.
+	Thinking string `json:"thinking,omitempty"`
 }
 
 type ollamaOptions struct {
 	Temperature float64 `json:"temperature,omitempty"`
 	NumPredict  int     `json:"num_predict,omitempty"`
+	TopP        float64 `json:"top_p,omitempty"`
 }
 
 type ollamaChatResponse struct {
@@ -55,7 +60,7 @@ func NewOllamaProvider(cfg config.ProviderConfig) *OllamaProvider {
 	}
 	return &OllamaProvider{
 		baseURL: baseURL,
-		models:  cfg.Models,
+		models:  newModelCatalog(cfg.Models),
 		client: &http.Client{
 			Timeout: 300 * time.Second, // Local inference can be slow
 		},
@@ -64,9 +69,38 @@ func NewOllamaProvider(cfg config.ProviderConfig) *OllamaProvider {
 
 func (p *OllamaProvider) Name() string { return "ollama" }
 
-func (p *OllamaProvider) Models() []config.Model { return p.models }
+func (p *OllamaProvider) Models() []config.Model { return p.models.list() }
 
+// RefreshModels lists the locally installed models from /api/tags and
+// merges them with the configured ones.
+func (p *OllamaProvider) RefreshModels(ctx context.Context) error {
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := fetchModelList(ctx, p.client, p.baseURL+"/api/tags", "", &tags); err != nil {
+		return err
+	}
+	ids := make([]string, len(tags.Models))
+	for i, m := range tags.Models {
+		ids[i] = m.Name
+	}
+	p.models.update(ids)
+	return nil
+}
+
+// Chat sends a chat request. Errors are scrubbed of credentials so they
+// can be logged safely.
 func (p *OllamaProvider) Chat(ctx context.Context, req orchestrator.ChatRequest) (*orchestrator.ChatResponse, error) {
+	resp, err := p.chat(ctx, req)
+	if err != nil {
+		return nil, config.RedactError(err)
+	}
+	return resp, nil
+}
+
+func (p *OllamaProvider) chat(ctx context.Context, req orchestrator.ChatRequest) (*orchestrator.ChatResponse, error) {
 	msgs := make([]ollamaMessage, 0, len(req.Messages)+1)
 
 	// Add system prompt as first message
@@ -91,6 +125,7 @@ func (p *OllamaProvider) Chat(ctx context.Context, req orchestrator.ChatRequest)
 		Options: &ollamaOptions{
 			Temperature: req.Temperature,
 			NumPredict:  req.MaxTokens,
+			TopP:        req.TopP,
 		},
 	}
 
@@ -104,6 +139,7 @@ func (p *OllamaProvider) Chat(ctx context.Context, req orchestrator.ChatRequest)
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	tracing.Inject(ctx, httpReq.Header)
 
 	resp, err := p.client.Do(httpReq)
 	if err != nil {
@@ -125,11 +161,40 @@ func (p *OllamaProvider) Chat(ctx context.Context, req orchestrator.ChatRequest)
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
 
+	content, inline := splitThinkTags(apiResp.Message.Content)
+
 	return &orchestrator.ChatResponse{
-		Content:      apiResp.Message.Content,
-		Model:        apiResp.Model,
-		TokensInput:  apiResp.PromptEvalCount,
-		TokensOutput: apiResp.EvalCount,
-		FinishReason: "stop",
+		Content:          content,
+		Model:            apiResp.Model,
+		TokensInput:      apiResp.PromptEvalCount,
+		TokensOutput:     apiResp.EvalCount,
+		FinishReason:     "stop",
+		ReasoningContent: joinReasoning(apiResp.Message.Thinking, inline),
 	}, nil
 }
+
+// ReleaseModel unloads model from memory by setting its keep-alive to
+// zero. The next chat loads it again.
+func (p *OllamaProvider) ReleaseModel(ctx context.Context, model string) error {
+	jsonBody, err := json.Marshal(map[string]interface{}{"model": model, "keep_alive": 0})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/generate", bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return config.RedactError(fmt.Errorf("http request: %w", err))
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ollama error %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
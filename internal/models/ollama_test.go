@@ -249,3 +249,23 @@ func TestOllamaChatMultipleMessages(t *testing.T) {
 		t.Fatalf("chat failed: %v", err)
 	}
 }
+
+func TestOllamaReleaseModel(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			t.Errorf("expected path /api/generate, got %s", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"model":"llama3.2","done":true,"done_reason":"unload"}`))
+	}))
+	defer server.Close()
+
+	p := NewOllamaProvider(config.ProviderConfig{BaseURL: server.URL})
+	if err := p.ReleaseModel(context.Background(), "llama3.2"); err != nil {
+		t.Fatalf("ReleaseModel: %v", err)
+	}
+	if got["model"] != "llama3.2" || got["keep_alive"] != float64(0) {
+		t.Errorf("request = %v, want model llama3.2 with keep_alive 0", got)
+	}
+}
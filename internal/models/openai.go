@@ -11,6 +11,7 @@ import (
 
 	"github.com/clawinfra/evoclaw/internal/config"
 	"github.com/clawinfra/evoclaw/internal/orchestrator"
+	"github.com/clawinfra/evoclaw/internal/tracing"
 )
 
 // OpenAIProvider implements ModelProvider for OpenAI-compatible APIs
@@ -19,7 +20,7 @@ type OpenAIProvider struct {
 	name    string
 	baseURL string
 	apiKey  string
-	models  []config.Model
+	models  *modelCatalog
 	client  *http.Client
 }
 
@@ -28,12 +29,18 @@ type openAIRequest struct {
 	Messages    []openAIMessage `json:"messages"`
 	MaxTokens   int             `json:"max_tokens,omitempty"`
 	Temperature float64         `json:"temperature,omitempty"`
+	TopP        float64         `json:"top_p,omitempty"`
 	Stream      bool            `json:"stream"`
 }
 
 type openAIMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	// Reasoning models return their thinking in one of these, depending
+	// on the server: reasoning_content (DeepSeek, vLLM) or reasoning
+	// (OpenRouter).
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+	Reasoning        string `json:"reasoning,omitempty"`
 }
 
 type openAIResponse struct {
@@ -50,6 +57,10 @@ type openAIResponse struct {
 		PromptTokens     int `json:"prompt_tokens"`
 		CompletionTokens int `json:"completion_tokens"`
 		TotalTokens      int `json:"total_tokens"`
+
+		CompletionTokensDetails struct {
+			ReasoningTokens int `json:"reasoning_tokens"`
+		} `json:"completion_tokens_details"`
 	} `json:"usage"`
 }
 
@@ -71,7 +82,7 @@ func NewOpenAIProvider(name string, cfg config.ProviderConfig) *OpenAIProvider {
 		name:    name,
 		baseURL: baseURL,
 		apiKey:  cfg.APIKey,
-		models:  cfg.Models,
+		models:  newModelCatalog(cfg.Models),
 		client: &http.Client{
 			Timeout: 120 * time.Second,
 		},
@@ -80,9 +91,38 @@ func NewOpenAIProvider(name string, cfg config.ProviderConfig) *OpenAIProvider {
 
 func (p *OpenAIProvider) Name() string { return p.name }
 
-func (p *OpenAIProvider) Models() []config.Model { return p.models }
+func (p *OpenAIProvider) Models() []config.Model { return p.models.list() }
+
+// RefreshModels lists the provider's models from its /models endpoint and
+// merges them with the configured ones.
+func (p *OpenAIProvider) RefreshModels(ctx context.Context) error {
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := fetchModelList(ctx, p.client, p.baseURL+"/models", p.apiKey, &list); err != nil {
+		return config.RedactError(err, p.apiKey)
+	}
+	ids := make([]string, len(list.Data))
+	for i, m := range list.Data {
+		ids[i] = m.ID
+	}
+	p.models.update(ids)
+	return nil
+}
 
+// Chat sends a chat request. Errors are scrubbed of credentials so they
+// can be logged safely.
 func (p *OpenAIProvider) Chat(ctx context.Context, req orchestrator.ChatRequest) (*orchestrator.ChatResponse, error) {
+	resp, err := p.chat(ctx, req)
+	if err != nil {
+		return nil, config.RedactError(err, p.apiKey)
+	}
+	return resp, nil
+}
+
+func (p *OpenAIProvider) chat(ctx context.Context, req orchestrator.ChatRequest) (*orchestrator.ChatResponse, error) {
 	// Convert messages
 	msgs := make([]openAIMessage, 0, len(req.Messages)+1)
 
@@ -106,6 +146,7 @@ func (p *OpenAIProvider) Chat(ctx context.Context, req orchestrator.ChatRequest)
 		Messages:    msgs,
 		MaxTokens:   req.MaxTokens,
 		Temperature: req.Temperature,
+		TopP:        req.TopP,
 		Stream:      false,
 	}
 
@@ -121,6 +162,7 @@ func (p *OpenAIProvider) Chat(ctx context.Context, req orchestrator.ChatRequest)
 
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	tracing.Inject(ctx, httpReq.Header)
 
 	resp, err := p.client.Do(httpReq)
 	if err != nil {
@@ -152,12 +194,15 @@ func (p *OpenAIProvider) Chat(ctx context.Context, req orchestrator.ChatRequest)
 	}
 
 	choice := apiResp.Choices[0]
+	content, inline := splitThinkTags(choice.Message.Content)
 
 	return &orchestrator.ChatResponse{
-		Content:      choice.Message.Content,
-		Model:        apiResp.Model,
-		TokensInput:  apiResp.Usage.PromptTokens,
-		TokensOutput: apiResp.Usage.CompletionTokens,
-		FinishReason: choice.FinishReason,
+		Content:          content,
+		Model:            apiResp.Model,
+		TokensInput:      apiResp.Usage.PromptTokens,
+		TokensOutput:     apiResp.Usage.CompletionTokens,
+		FinishReason:     choice.FinishReason,
+		ReasoningContent: joinReasoning(choice.Message.ReasoningContent, choice.Message.Reasoning, inline),
+		ReasoningTokens:  apiResp.Usage.CompletionTokensDetails.ReasoningTokens,
 	}, nil
 }
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/clawinfra/evoclaw/internal/config"
 	"github.com/clawinfra/evoclaw/internal/orchestrator"
+	"github.com/clawinfra/evoclaw/internal/tracing"
 )
 
 func TestNewOpenAIProvider(t *testing.T) {
@@ -145,6 +147,30 @@ func TestOpenAIChatError(t *testing.T) {
 	}
 }
 
+func TestOpenAIChatErrorRedactsAPIKey(t *testing.T) {
+	const key = "sk-live-abcdef123456"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error": {"message": "Incorrect API key provided: ` + key + `", "type": "invalid_request_error"}}`))
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider("openai", config.ProviderConfig{BaseURL: server.URL, APIKey: key})
+	_, err := p.Chat(context.Background(), orchestrator.ChatRequest{
+		Model:    "gpt-4",
+		Messages: []orchestrator.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(err.Error(), key) {
+		t.Errorf("error leaked the API key: %v", err)
+	}
+	if !strings.Contains(err.Error(), config.RedactedValue) {
+		t.Errorf("expected masked key in %q", err.Error())
+	}
+}
+
 func TestOpenAIChatWithSystemPrompt(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var reqBody openAIRequest
@@ -183,3 +209,21 @@ func TestOpenAIChatWithSystemPrompt(t *testing.T) {
 		t.Fatalf("chat failed: %v", err)
 	}
 }
+
+func TestOpenAIChatPropagatesTraceContext(t *testing.T) {
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("traceparent"); got != tp {
+			t.Errorf("traceparent = %q, want %q", got, tp)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider("openai", config.ProviderConfig{BaseURL: server.URL, APIKey: "k"})
+	ctx := tracing.Extract(context.Background(), tp)
+	if _, err := p.Chat(ctx, orchestrator.ChatRequest{Model: "gpt-4", Messages: []orchestrator.ChatMessage{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+}
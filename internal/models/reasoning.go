@@ -0,0 +1,47 @@
+package models
+
+import "strings"
+
+const (
+	thinkOpen  = "<think>"
+	thinkClose = "</think>"
+)
+
+// splitThinkTags separates inline <think>...</think> reasoning, as emitted
+// by DeepSeek-R1 and Qwen style models, from the final answer. A block left
+// open by a truncated response is all reasoning.
+func splitThinkTags(content string) (final, reasoning string) {
+	var answer, thoughts []string
+	rest := content
+	for {
+		start := strings.Index(rest, thinkOpen)
+		if start < 0 {
+			answer = append(answer, rest)
+			break
+		}
+		answer = append(answer, rest[:start])
+		rest = rest[start+len(thinkOpen):]
+		end := strings.Index(rest, thinkClose)
+		if end < 0 {
+			thoughts = append(thoughts, rest)
+			break
+		}
+		thoughts = append(thoughts, rest[:end])
+		rest = rest[end+len(thinkClose):]
+	}
+	if len(thoughts) == 0 {
+		return content, ""
+	}
+	return strings.TrimSpace(strings.Join(answer, "")), joinReasoning(thoughts...)
+}
+
+// joinReasoning joins non-empty reasoning parts with blank lines.
+func joinReasoning(parts ...string) string {
+	kept := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, "\n\n")
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/orchestrator"
+)
+
+func TestSplitThinkTags(t *testing.T) {
+	tests := []struct {
+		in, final, reasoning string
+	}{
+		{"plain answer", "plain answer", ""},
+		{"<think>weigh options</think>\n\nThe answer is 4.", "The answer is 4.", "weigh options"},
+		{"<think>a</think>x<think>b</think>y", "xy", "a\n\nb"},
+		{"<think>cut off mid-thou", "", "cut off mid-thou"},
+	}
+	for _, tt := range tests {
+		final, reasoning := splitThinkTags(tt.in)
+		if final != tt.final || reasoning != tt.reasoning {
+			t.Errorf("splitThinkTags(%q) = %q, %q; want %q, %q", tt.in, final, reasoning, tt.final, tt.reasoning)
+		}
+	}
+}
+
+func chatAgainst(t *testing.T, body string, newProvider func(baseURL string) orchestrator.ModelProvider) *orchestrator.ChatResponse {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	resp, err := newProvider(server.URL).Chat(context.Background(), orchestrator.ChatRequest{
+		Model:    "m",
+		Messages: []orchestrator.ChatMessage{{Role: "user", Content: "2+2?"}},
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	return resp
+}
+
+func TestProvidersSeparateReasoning(t *testing.T) {
+	openAI := func(baseURL string) orchestrator.ModelProvider {
+		return NewOpenAIProvider("openai", config.ProviderConfig{BaseURL: baseURL})
+	}
+	tests := []struct {
+		name          string
+		body          string
+		provider      func(baseURL string) orchestrator.ModelProvider
+		reasoning     string
+		reasoningToks int
+	}{
+		{
+			name: "openai reasoning_content",
+			body: `{"model":"m","choices":[{"message":{"role":"assistant","content":"4","reasoning_content":"2 plus 2"},"finish_reason":"stop"}],
+				"usage":{"prompt_tokens":5,"completion_tokens":20,"completion_tokens_details":{"reasoning_tokens":15}}}`,
+			provider:      openAI,
+			reasoning:     "2 plus 2",
+			reasoningToks: 15,
+		},
+		{
+			name:      "openai inline think tags",
+			body:      `{"model":"m","choices":[{"message":{"role":"assistant","content":"<think>2 plus 2</think>4"},"finish_reason":"stop"}]}`,
+			provider:  openAI,
+			reasoning: "2 plus 2",
+		},
+		{
+			name: "anthropic thinking block",
+			body: `{"model":"m","content":[{"type":"thinking","thinking":"2 plus 2"},{"type":"text","text":"4"}],"stop_reason":"end_turn"}`,
+			provider: func(baseURL string) orchestrator.ModelProvider {
+				return NewAnthropicProvider(config.ProviderConfig{BaseURL: baseURL})
+			},
+			reasoning: "2 plus 2",
+		},
+		{
+			name: "ollama thinking field",
+			body: `{"model":"m","message":{"role":"assistant","content":"4","thinking":"2 plus 2"},"done":true}`,
+			provider: func(baseURL string) orchestrator.ModelProvider {
+				return NewOllamaProvider(config.ProviderConfig{BaseURL: baseURL})
+			},
+			reasoning: "2 plus 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := chatAgainst(t, tt.body, tt.provider)
+			if resp.Content != "4" {
+				t.Errorf("Content = %q, want only the final answer", resp.Content)
+			}
+			if resp.ReasoningContent != tt.reasoning {
+				t.Errorf("ReasoningContent = %q, want %q", resp.ReasoningContent, tt.reasoning)
+			}
+			if resp.ReasoningTokens != tt.reasoningToks {
+				t.Errorf("ReasoningTokens = %d, want %d", resp.ReasoningTokens, tt.reasoningToks)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/clawinfra/evoclaw/internal/config"
 	"github.com/clawinfra/evoclaw/internal/orchestrator"
@@ -85,6 +86,68 @@ func (r *Router) RegisterProvider(p orchestrator.ModelProvider) {
 	)
 }
 
+// RefreshModels re-lists the named provider's models from its API and
+// re-indexes them. Providers without discovery are left as they are; on a
+// discovery error the existing models stay registered.
+func (r *Router) RefreshModels(ctx context.Context, providerName string) error {
+	r.mu.RLock()
+	p, ok := r.providers[providerName]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("provider not found: %s", providerName)
+	}
+	refresher, ok := p.(ModelRefresher)
+	if !ok {
+		return nil
+	}
+	if err := refresher.RefreshModels(ctx); err != nil {
+		return fmt.Errorf("refresh %s models: %w", providerName, err)
+	}
+
+	models := p.Models()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, info := range r.models {
+		if info.Provider == providerName {
+			delete(r.models, id)
+		}
+	}
+	for _, model := range models {
+		fullID := fmt.Sprintf("%s/%s", providerName, model.ID)
+		r.models[fullID] = &ModelInfo{
+			ID:           fullID,
+			Provider:     providerName,
+			Config:       model,
+			ProviderImpl: p,
+		}
+	}
+	r.logger.Debug("provider models refreshed", "name", providerName, "models", len(models))
+	return nil
+}
+
+// StartModelRefresh refreshes the named provider's models every interval
+// until ctx is cancelled. Failures are logged and the previous list kept.
+func (r *Router) StartModelRefresh(ctx context.Context, providerName string, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultModelRefreshInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.RefreshModels(ctx, providerName); err != nil {
+					r.logger.Warn("model discovery failed, keeping previous list",
+						"provider", providerName, "error", err)
+				}
+			}
+		}
+	}()
+}
+
 // Chat routes a chat request to the appropriate model with fallback
 func (r *Router) Chat(ctx context.Context, modelID string, req orchestrator.ChatRequest, fallback []string) (*orchestrator.ChatResponse, error) {
 	// Try primary model
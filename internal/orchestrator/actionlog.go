@@ -0,0 +1,240 @@
+package orchestrator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+// maxActionDescription bounds the description stored per action so a long
+// error message cannot bloat the log.
+const maxActionDescription = 500
+
+// ActionRecord is one entry in an agent's action log.
+type ActionRecord struct {
+	AgentID     string    `json:"agent_id"`
+	Type        string    `json:"type"` // "chat", "edge", ...
+	Description string    `json:"description"`
+	Success     bool      `json:"success"`
+	LatencyMs   int64     `json:"latency_ms"`
+	Model       string    `json:"model,omitempty"`
+	Channel     string    `json:"channel,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// ActionLog is a local, append-only log of agent actions, one JSONL file per
+// agent under dir, or under each agent's data directory once
+// SetAgentDataDirs is called. It records every action whether or not it is
+// also reported on-chain.
+type ActionLog struct {
+	dir       string
+	agentsDir string // dataDir/agents with per-agent data directories
+	mu        sync.Mutex
+}
+
+// NewActionLog creates an action log rooted at dir, creating it if needed.
+func NewActionLog(dir string) (*ActionLog, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create action log directory: %w", err)
+	}
+	return &ActionLog{dir: dir}, nil
+}
+
+// SetAgentDataDirs stores each agent's log in its per-agent data directory,
+// dataDir/agents/<id>/actions.jsonl, where deleting the agent removes it.
+// Logs in the shared directory are moved there, unless the agent's
+// directory already has a log of its own. Call it before the log is used.
+func (l *ActionLog) SetAgentDataDirs(dataDir string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return fmt.Errorf("read action log directory: %w", err)
+	}
+	l.agentsDir = filepath.Join(dataDir, storage.AgentsNamespace)
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".jsonl")
+		if e.IsDir() || !ok {
+			continue
+		}
+		to, err := l.path(id)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(to); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(to), 0750); err != nil {
+			return fmt.Errorf("move action log: %w", err)
+		}
+		if err := os.Rename(filepath.Join(l.dir, e.Name()), to); err != nil {
+			return fmt.Errorf("move action log: %w", err)
+		}
+	}
+	return nil
+}
+
+func (l *ActionLog) path(agentID string) (string, error) {
+	if agentID == "" || agentID != filepath.Base(agentID) || strings.HasPrefix(agentID, ".") {
+		return "", fmt.Errorf("invalid agent id %q", agentID)
+	}
+	if l.agentsDir != "" {
+		return filepath.Join(l.agentsDir, agentID, "actions.jsonl"), nil
+	}
+	return filepath.Join(l.dir, agentID+".jsonl"), nil
+}
+
+// Delete removes the agent's log.
+func (l *ActionLog) Delete(agentID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	p, err := l.path(agentID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("delete action log: %w", err)
+	}
+	return nil
+}
+
+// truncateDescription cuts s to at most maxActionDescription bytes on a
+// rune boundary, marking the cut with an ellipsis.
+func truncateDescription(s string) string {
+	if len(s) <= maxActionDescription {
+		return s
+	}
+	n := maxActionDescription
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
+
+// Append writes rec to its agent's log, stamping the time if unset.
+func (l *ActionLog) Append(rec ActionRecord) error {
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now()
+	}
+	rec.Description = truncateDescription(rec.Description)
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal action: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	p, err := l.path(rec.AgentID)
+	if err != nil {
+		return err
+	}
+	if l.agentsDir != "" {
+		if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+			return fmt.Errorf("create agent data directory: %w", err)
+		}
+	}
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open action log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write action: %w", err)
+	}
+	return nil
+}
+
+// Recent returns up to limit of the agent's actions, newest first. A limit
+// of zero or less returns them all.
+func (l *ActionLog) Recent(agentID string, limit int) ([]ActionRecord, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	p, err := l.path(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []ActionRecord{}, nil
+		}
+		return nil, fmt.Errorf("open action log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var all []ActionRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec ActionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue // skip a torn or corrupt line rather than fail the read
+		}
+		all = append(all, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read action log: %w", err)
+	}
+
+	n := len(all)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	out := make([]ActionRecord, 0, n)
+	for i := len(all) - 1; i >= 0 && len(out) < n; i-- {
+		out = append(out, all[i])
+	}
+	return out, nil
+}
+
+// SetActionLog sets the local action log. Actions are not recorded when nil.
+func (o *Orchestrator) SetActionLog(l *ActionLog) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.actionLog = l
+}
+
+// ActionLog returns the local action log, or nil if none is set.
+func (o *Orchestrator) ActionLog() *ActionLog {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.actionLog
+}
+
+// recordAction appends an action for agent to the local log. Failures are
+// logged and otherwise ignored so they never affect message handling.
+func (o *Orchestrator) recordAction(agentID, actionType, model string, msg Message, elapsed time.Duration, actionErr error) {
+	l := o.ActionLog()
+	if l == nil {
+		return
+	}
+
+	rec := ActionRecord{
+		AgentID:     agentID,
+		Type:        actionType,
+		Description: fmt.Sprintf("Processed message via %s (%dms)", model, elapsed.Milliseconds()),
+		Success:     actionErr == nil,
+		LatencyMs:   elapsed.Milliseconds(),
+		Model:       model,
+		Channel:     msg.Channel,
+	}
+	if actionErr != nil {
+		rec.Description = fmt.Sprintf("Failed via %s: %v", model, actionErr)
+	}
+	if err := l.Append(rec); err != nil {
+		o.logger.Warn("action log write failed", "agent", agentID, "error", err)
+	}
+}
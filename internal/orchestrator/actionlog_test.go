@@ -0,0 +1,162 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestActionLogRecentNewestFirst(t *testing.T) {
+	l, err := NewActionLog(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Now()
+	for i := 0; i < 5; i++ {
+		rec := ActionRecord{
+			AgentID:     "trader",
+			Type:        "chat",
+			Description: fmt.Sprintf("action %d", i),
+			Success:     i%2 == 0,
+			LatencyMs:   int64(100 + i),
+			Model:       "test/model",
+			Timestamp:   base.Add(time.Duration(i) * time.Second),
+		}
+		if err := l.Append(rec); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	if err := l.Append(ActionRecord{AgentID: "other", Type: "chat"}); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := l.Recent("trader", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("got %d actions, want 5", len(all))
+	}
+	for i, rec := range all {
+		if want := fmt.Sprintf("action %d", 4-i); rec.Description != want {
+			t.Errorf("all[%d] = %q, want %q", i, rec.Description, want)
+		}
+	}
+	if all[0].LatencyMs != 104 || all[0].Model != "test/model" || !all[0].Success {
+		t.Errorf("newest = %+v", all[0])
+	}
+
+	recent, err := l.Recent("trader", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[0].Description != "action 4" || recent[1].Description != "action 3" {
+		t.Errorf("Recent(2) = %+v", recent)
+	}
+}
+
+func TestActionLogEdgeCases(t *testing.T) {
+	dir := t.TempDir()
+	l, err := NewActionLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if recs, err := l.Recent("nobody", 10); err != nil || len(recs) != 0 {
+		t.Errorf("Recent(unknown) = %v, %v; want empty", recs, err)
+	}
+	for _, id := range []string{"", "../escape", "a/b", ".hidden"} {
+		if err := l.Append(ActionRecord{AgentID: id}); err == nil {
+			t.Errorf("Append(%q) should fail", id)
+		}
+	}
+
+	// A corrupt line is skipped, not fatal.
+	if err := l.Append(ActionRecord{AgentID: "a", Description: "ok"}); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := os.OpenFile(filepath.Join(dir, "a.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString("{not json\n")
+	_ = f.Close()
+	if recs, err := l.Recent("a", 0); err != nil || len(recs) != 1 {
+		t.Errorf("Recent after corrupt line = %v, %v", recs, err)
+	}
+}
+
+func TestRecordAction(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.recordAction("a", "chat", "m", Message{}, time.Millisecond, nil) // no log set: no-op
+
+	l, _ := NewActionLog(t.TempDir())
+	o.SetActionLog(l)
+	o.recordAction("a", "chat", "test/model", Message{Channel: "tui"}, 42*time.Millisecond, nil)
+	o.recordAction("a", "chat", "test/model", Message{Channel: "tui"}, 7*time.Millisecond, errors.New("rate limited"))
+
+	recs, err := o.ActionLog().Recent("a", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("got %d actions, want 2", len(recs))
+	}
+	if recs[0].Success || recs[0].Description != "Failed via test/model: rate limited" {
+		t.Errorf("failure = %+v", recs[0])
+	}
+	if !recs[1].Success || recs[1].LatencyMs != 42 || recs[1].Channel != "tui" {
+		t.Errorf("success = %+v", recs[1])
+	}
+}
+
+func TestActionLogTruncatesOnRuneBoundary(t *testing.T) {
+	l, _ := NewActionLog(t.TempDir())
+	desc := "x" + strings.Repeat("é", maxActionDescription)
+	if err := l.Append(ActionRecord{AgentID: "a", Description: desc}); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := l.Recent("a", 0)
+	if err != nil || len(recs) != 1 {
+		t.Fatalf("Recent = %v, %v", recs, err)
+	}
+	got := recs[0].Description
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "é…") || len(got) > maxActionDescription+len("…") {
+		t.Errorf("description = %q (%d bytes)", got, len(got))
+	}
+}
+
+func TestActionLogAgentDataDirs(t *testing.T) {
+	dataDir := t.TempDir()
+	l, _ := NewActionLog(filepath.Join(dataDir, "actions"))
+	if err := l.Append(ActionRecord{AgentID: "a1", Description: "before"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.SetAgentDataDirs(dataDir); err != nil {
+		t.Fatalf("SetAgentDataDirs: %v", err)
+	}
+	if err := l.Append(ActionRecord{AgentID: "a1", Description: "after"}); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dataDir, "agents", "a1", "actions.jsonl")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("log not in agent data dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "actions", "a1.jsonl")); !os.IsNotExist(err) {
+		t.Error("shared log left behind")
+	}
+	if recs, _ := l.Recent("a1", 0); len(recs) != 2 || recs[1].Description != "before" {
+		t.Errorf("Recent = %+v, want moved and new actions", recs)
+	}
+
+	if err := l.Delete("a1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("log still present after Delete")
+	}
+}
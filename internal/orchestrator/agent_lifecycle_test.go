@@ -0,0 +1,103 @@
+package orchestrator
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func TestAddAgent(t *testing.T) {
+	o := New(testConfig(), testLogger())
+
+	if err := o.AddAgent(config.AgentDef{ID: "runtime-agent", Type: "monitor"}); err != nil {
+		t.Fatalf("AddAgent() error: %v", err)
+	}
+	if info := o.GetAgentInfo("runtime-agent"); info == nil || info.Status != "idle" {
+		t.Fatalf("expected idle runtime-agent, got %+v", info)
+	}
+
+	if err := o.AddAgent(config.AgentDef{ID: "runtime-agent"}); err == nil {
+		t.Error("expected duplicate AddAgent to fail")
+	}
+	if err := o.AddAgent(config.AgentDef{}); err == nil {
+		t.Error("expected AddAgent without ID to fail")
+	}
+}
+
+func TestRemoveAgent(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	_ = o.AddAgent(config.AgentDef{ID: "a1"})
+
+	if err := o.RemoveAgent("a1"); err != nil {
+		t.Fatalf("RemoveAgent() error: %v", err)
+	}
+	if o.GetAgentInfo("a1") != nil {
+		t.Error("expected agent to be removed")
+	}
+	if err := o.RemoveAgent("a1"); err == nil {
+		t.Error("expected error removing unknown agent")
+	}
+}
+
+func TestRemoveAgentWhileProcessing(t *testing.T) {
+	cfg := testConfig()
+	cfg.Agents = nil
+	o := New(cfg, testLogger())
+	o.RegisterProvider(newMockProvider("mock"))
+	ch := newMockChannel("test")
+	o.RegisterChannel(ch)
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer func() { _ = o.Stop() }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = o.AddAgent(config.AgentDef{ID: "churn", Model: "mock/mock-model-1"})
+		}()
+		go func() {
+			defer wg.Done()
+			o.handleMessage(Message{ID: "m", From: "user", Content: "hi", Channel: "test", To: "churn"})
+			_ = o.RemoveAgent("churn")
+		}()
+	}
+	wg.Wait()
+
+	// Any processing that was in flight must finish without panicking.
+	time.Sleep(50 * time.Millisecond)
+}
+
+// Adding the first agent with capabilities builds the shared tool loop
+// while other goroutines read it; run with -race.
+func TestAddAgentWithCapabilitiesWhileReadingTools(t *testing.T) {
+	o := New(testConfig(), testLogger())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_ = o.ToolStats()
+				o.mu.RLock()
+				_ = o.toolLoop
+				o.mu.RUnlock()
+			}
+		}()
+	}
+	if err := o.AddAgent(config.AgentDef{ID: "tooled", Capabilities: config.NewCapabilities("network")}); err != nil {
+		t.Fatalf("AddAgent() error: %v", err)
+	}
+	wg.Wait()
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if o.toolLoop == nil || o.agents["tooled"] == nil {
+		t.Error("expected the tool loop and agent to be initialized")
+	}
+}
@@ -0,0 +1,108 @@
+package orchestrator
+
+import (
+	"slices"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
+)
+
+// AgentTypeBehavior is what sets agents of one AgentDef.Type apart: the
+// metrics they report, what they may do by default and how their fitness
+// is judged.
+type AgentTypeBehavior struct {
+	// Metrics are the custom metrics agents of this type report with
+	// RecordAgentMetric. Each starts at zero, so it is part of every
+	// evaluation and shows in the agent's metrics from the start.
+	Metrics []string
+	// Capabilities are given to agents of this type that declare none.
+	// Registering them is an explicit grant: no type has any by default.
+	Capabilities config.Capabilities
+	// FitnessWeights weigh evaluation metrics into the type's fitness (see
+	// evolution.WeightedFitness). Nil leaves the evolution engine's
+	// fitness function for the type alone.
+	FitnessWeights map[string]float64
+}
+
+// fitnessRegistrar is implemented by evolution engines that accept
+// per-type fitness functions.
+type fitnessRegistrar interface {
+	RegisterFitnessFunc(agentType string, f evolution.FitnessFunc)
+}
+
+// RegisterAgentType makes b the behavior of agents whose AgentDef.Type is
+// agentType, replacing any registered before. No type has a behavior
+// until one is registered. Capabilities and seeded metrics
+// apply to agents initialized afterwards; fitness weights apply at once.
+func (o *Orchestrator) RegisterAgentType(agentType string, b AgentTypeBehavior) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.agentTypes == nil {
+		o.agentTypes = make(map[string]AgentTypeBehavior)
+	}
+	o.agentTypes[agentType] = b
+	if r, ok := o.evolution.(fitnessRegistrar); ok && b.FitnessWeights != nil {
+		r.RegisterFitnessFunc(agentType, evolution.WeightedFitness(b.FitnessWeights))
+	}
+}
+
+// AgentType returns the behavior registered for agentType.
+func (o *Orchestrator) AgentType(agentType string) (AgentTypeBehavior, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	b, ok := o.agentTypes[agentType]
+	return b, ok
+}
+
+// registerTypeFitnessLocked gives the evolution engine a fitness function
+// for every registered type with fitness weights.
+func (o *Orchestrator) registerTypeFitnessLocked() {
+	r, ok := o.evolution.(fitnessRegistrar)
+	if !ok {
+		return
+	}
+	for t, b := range o.agentTypes {
+		if b.FitnessWeights != nil {
+			r.RegisterFitnessFunc(t, evolution.WeightedFitness(b.FitnessWeights))
+		}
+	}
+}
+
+// withTypeDefaultsLocked fills in def's capabilities from its type when it
+// declares none.
+func (o *Orchestrator) withTypeDefaultsLocked(def config.AgentDef) config.AgentDef {
+	if b, ok := o.agentTypes[def.Type]; ok && len(def.Capabilities) == 0 {
+		def.Capabilities = slices.Clone(b.Capabilities)
+	}
+	return def
+}
+
+// newAgentMetricsLocked returns empty metrics with the custom metrics of
+// agentType seeded at zero.
+func (o *Orchestrator) newAgentMetricsLocked(agentType string) AgentMetrics {
+	metrics := AgentMetrics{Custom: make(map[string]float64)}
+	for _, k := range o.agentTypes[agentType].Metrics {
+		metrics.Custom[k] = 0
+	}
+	return metrics
+}
+
+// RecordAgentMetric sets one of the agent's custom metrics, e.g. a
+// trader's profitLoss. Custom metrics feed the agent's fitness and are
+// reset with the rest of its metrics when its strategy mutates.
+func (o *Orchestrator) RecordAgentMetric(agentID, name string, value float64) error {
+	o.mu.RLock()
+	agent, ok := o.agents[agentID]
+	o.mu.RUnlock()
+	if !ok {
+		return agentNotFound(agentID)
+	}
+
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+	if agent.Metrics.Custom == nil {
+		agent.Metrics.Custom = make(map[string]float64)
+	}
+	agent.Metrics.Custom[name] = value
+	return nil
+}
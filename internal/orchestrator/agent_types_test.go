@@ -0,0 +1,105 @@
+package orchestrator
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
+)
+
+func TestRegisterAgentType_MetricsCapabilitiesAndFitness(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	e := evolution.NewEngine(t.TempDir(), testLogger())
+	o.SetEvolutionEngine(e)
+	o.RegisterAgentType("curator", AgentTypeBehavior{
+		Metrics:        []string{"approvalRate"},
+		Capabilities:   config.NewCapabilities("filesystem"),
+		FitnessWeights: map[string]float64{"approvalRate": 3, "successRate": 1},
+	})
+
+	o.mu.Lock()
+	agent := o.initAgentLocked(config.AgentDef{ID: "librarian", Type: "curator"})
+	o.mu.Unlock()
+
+	if v, ok := agent.Metrics.Custom["approvalRate"]; !ok || v != 0 {
+		t.Errorf("approvalRate should be seeded at 0, got %v (present %v)", v, ok)
+	}
+	if got := agent.Def.Capabilities.Strings(); !slices.Equal(got, []string{"filesystem"}) {
+		t.Errorf("capabilities = %v, want the type's default", got)
+	}
+
+	if err := o.RecordAgentMetric("librarian", "approvalRate", 0.8); err != nil {
+		t.Fatalf("RecordAgentMetric: %v", err)
+	}
+	agent.mu.Lock()
+	agent.Metrics.TotalActions = 2
+	agent.Metrics.SuccessfulActions = 1
+	agent.mu.Unlock()
+
+	e.SetStrategy("librarian", &evolution.Strategy{ID: "librarian"})
+	got := e.Evaluate("librarian", o.agentEvalMetrics(agent))
+	if want := (3*0.8 + 0.5) / 4; math.Abs(got-want) > 1e-9 {
+		t.Errorf("fitness = %v, want %v from the curator weights", got, want)
+	}
+}
+
+func TestAgentTypesHaveNoDefaults(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.mu.Lock()
+	trader := o.initAgentLocked(config.AgentDef{ID: "t", Type: "trader"})
+	researcher := o.initAgentLocked(config.AgentDef{ID: "r", Type: "researcher"})
+	o.mu.Unlock()
+
+	// An unregistered type must not widen what its agents may do.
+	for _, agent := range []*AgentState{trader, researcher} {
+		if len(agent.Def.Capabilities) != 0 || len(agent.Metrics.Custom) != 0 {
+			t.Errorf("%s got type defaults: %v %v", agent.ID, agent.Def.Capabilities, agent.Metrics.Custom)
+		}
+	}
+}
+
+func TestRegisterAgentType_KeepsDeclaredCapabilities(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.RegisterAgentType("researcher", AgentTypeBehavior{Capabilities: config.NewCapabilities("network", "filesystem")})
+	o.mu.Lock()
+	pinned := o.initAgentLocked(config.AgentDef{ID: "p", Type: "researcher", Capabilities: config.NewCapabilities("network")})
+	o.mu.Unlock()
+
+	if got := pinned.Def.Capabilities.Strings(); !slices.Equal(got, []string{"network"}) {
+		t.Errorf("declared capabilities were replaced: %v", got)
+	}
+}
+
+func TestSetEvolutionEngineAppliesTypeFitness(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	weights := map[string]float64{"uptime": 0.5, "successRate": 0.4, "costUSD": 0.1}
+	o.RegisterAgentType("sensor", AgentTypeBehavior{Metrics: []string{"uptime"}, FitnessWeights: weights})
+	o.mu.Lock()
+	agent := o.initAgentLocked(config.AgentDef{ID: "sensor-1", Type: "sensor"})
+	o.mu.Unlock()
+	if err := o.RecordAgentMetric("sensor-1", "uptime", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	e := evolution.NewEngine(t.TempDir(), testLogger())
+	o.SetEvolutionEngine(e)
+	e.SetStrategy("sensor-1", &evolution.Strategy{ID: "sensor-1"})
+
+	metrics := o.agentEvalMetrics(agent)
+	want := evolution.WeightedFitness(weights).Fitness(metrics)
+	if got := e.Evaluate("sensor-1", metrics); got != want {
+		t.Errorf("sensor fitness = %v, want %v", got, want)
+	}
+	if want == evolution.DefaultFitness.Fitness(metrics) {
+		t.Error("sensor weights should differ from the trader-oriented default")
+	}
+}
+
+func TestRecordAgentMetricUnknownAgent(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	if err := o.RecordAgentMetric("ghost", "uptime", 1); err == nil {
+		t.Error("expected an error for an unknown agent")
+	}
+}
@@ -0,0 +1,144 @@
+package orchestrator
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Policies applied when the inbox or outbox is full (server.queuePolicy).
+const (
+	QueueBlock      = "block"
+	QueueDropNew    = "drop-new"
+	QueueDropOldest = "drop-oldest"
+)
+
+// defaultQueueTimeout bounds how long the block policy waits for space.
+const defaultQueueTimeout = 5 * time.Second
+
+// DeadLetterQueueFull marks items dropped by the queue policy.
+const DeadLetterQueueFull = "queue_full"
+
+// queueCounters tracks backpressure on one queue.
+type queueCounters struct {
+	full    atomic.Int64 // sends that found the queue full
+	dropped atomic.Int64 // items dropped as a result
+}
+
+// QueueStats reports queue depth and backpressure since startup.
+type QueueStats struct {
+	Policy         string `json:"policy"`
+	InboxDepth     int    `json:"inbox_depth"`
+	InboxCapacity  int    `json:"inbox_capacity"`
+	InboxFull      int64  `json:"inbox_full"`
+	InboxDropped   int64  `json:"inbox_dropped"`
+	OutboxDepth    int    `json:"outbox_depth"`
+	OutboxCapacity int    `json:"outbox_capacity"`
+	OutboxFull     int64  `json:"outbox_full"`
+	OutboxDropped  int64  `json:"outbox_dropped"`
+}
+
+// QueueStats returns the current inbox/outbox depth and backpressure counts.
+func (o *Orchestrator) QueueStats() QueueStats {
+	return QueueStats{
+		Policy:         o.queuePolicy(),
+		InboxDepth:     len(o.inbox),
+		InboxCapacity:  cap(o.inbox),
+		InboxFull:      o.inboxStats.full.Load(),
+		InboxDropped:   o.inboxStats.dropped.Load(),
+		OutboxDepth:    len(o.outbox),
+		OutboxCapacity: cap(o.outbox),
+		OutboxFull:     o.outboxStats.full.Load(),
+		OutboxDropped:  o.outboxStats.dropped.Load(),
+	}
+}
+
+func (o *Orchestrator) queuePolicy() string {
+	if o.cfg == nil || o.cfg.Server.QueuePolicy == "" {
+		return QueueBlock
+	}
+	return o.cfg.Server.QueuePolicy
+}
+
+func (o *Orchestrator) queueTimeout() time.Duration {
+	if o.cfg == nil || o.cfg.Server.QueueTimeoutMs <= 0 {
+		return defaultQueueTimeout
+	}
+	return time.Duration(o.cfg.Server.QueueTimeoutMs) * time.Millisecond
+}
+
+// enqueueMessage puts msg on the inbox, applying the queue policy if it is
+// full. It reports whether msg was queued.
+func (o *Orchestrator) enqueueMessage(msg Message) bool {
+	if o.inboxJournal != nil {
+		var err error
+		if msg, err = o.inboxJournal.add(msg); err != nil {
+			o.logger.Error("inbox journal write failed", "channel", msg.Channel, "id", msg.ID, "error", err)
+		}
+	}
+	return o.queueMessage(msg)
+}
+
+// queueMessage puts msg on the inbox without journaling it.
+func (o *Orchestrator) queueMessage(msg Message) bool {
+	return enqueue(o, o.inbox, msg, &o.inboxStats, func(m Message) {
+		o.logger.Warn("inbox full, message dropped", "policy", o.queuePolicy(), "channel", m.Channel, "from", m.From)
+		o.deadLetterMessage(DeadLetterQueueFull, "", m)
+		o.settleInbox(m)
+	})
+}
+
+// enqueueResponse puts resp on the outbox, applying the queue policy if it
+// is full. It reports whether resp was queued.
+func (o *Orchestrator) enqueueResponse(resp Response) bool {
+	return enqueue(o, o.outbox, resp, &o.outboxStats, func(r Response) {
+		o.logger.Warn("outbox full, response dropped", "policy", o.queuePolicy(), "channel", r.Channel, "agent", r.AgentID)
+		o.deadLetterResponse(DeadLetterQueueFull, r)
+	})
+}
+
+// enqueue sends item on q without blocking indefinitely. When q is full the
+// configured policy decides which item is dropped; drop is called for it.
+func enqueue[T any](o *Orchestrator, q chan T, item T, stats *queueCounters, drop func(T)) bool {
+	select {
+	case q <- item:
+		return true
+	default:
+	}
+	stats.full.Add(1)
+
+	switch o.queuePolicy() {
+	case QueueDropNew:
+		stats.dropped.Add(1)
+		drop(item)
+		return false
+
+	case QueueDropOldest:
+		for {
+			select {
+			case old := <-q:
+				stats.dropped.Add(1)
+				drop(old)
+			default:
+			}
+			select {
+			case q <- item:
+				return true
+			default:
+				// Another sender took the slot; evict again.
+			}
+		}
+
+	default: // QueueBlock
+		timer := time.NewTimer(o.queueTimeout())
+		defer timer.Stop()
+		select {
+		case q <- item:
+			return true
+		case <-timer.C:
+		case <-o.ctx.Done():
+		}
+		stats.dropped.Add(1)
+		drop(item)
+		return false
+	}
+}
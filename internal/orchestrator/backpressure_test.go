@@ -0,0 +1,135 @@
+package orchestrator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// newFullInboxOrchestrator returns an orchestrator whose inbox is full and
+// has no consumer running.
+func newFullInboxOrchestrator(t *testing.T, policy string) (*Orchestrator, *DeadLetterLog) {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Server.QueuePolicy = policy
+	cfg.Server.QueueTimeoutMs = 20
+	o := New(cfg, testLogger())
+	l, _ := NewDeadLetterLog("", 0)
+	o.SetDeadLetterLog(l)
+	for i := 0; i < cap(o.inbox); i++ {
+		o.inbox <- Message{ID: fmt.Sprintf("m%d", i), Content: "queued"}
+	}
+	return o, l
+}
+
+// enqueueWithin fails the test if enqueueMessage hangs.
+func enqueueWithin(t *testing.T, o *Orchestrator, msg Message) bool {
+	t.Helper()
+	done := make(chan bool, 1)
+	go func() { done <- o.enqueueMessage(msg) }()
+	select {
+	case ok := <-done:
+		return ok
+	case <-time.After(2 * time.Second):
+		t.Fatal("enqueueMessage blocked on a full inbox")
+		return false
+	}
+}
+
+func TestBackpressureDropNew(t *testing.T) {
+	o, l := newFullInboxOrchestrator(t, QueueDropNew)
+
+	if enqueueWithin(t, o, Message{ID: "new", Content: "late"}) {
+		t.Fatal("expected the new message to be dropped")
+	}
+	if first := <-o.inbox; first.ID != "m0" {
+		t.Errorf("head of inbox = %s, want m0", first.ID)
+	}
+	dl := l.Recent(DeadLetterQueueFull, 0)
+	if len(dl) != 1 || dl[0].MessageID != "new" || dl[0].Direction != "inbound" {
+		t.Errorf("dead letters = %+v", dl)
+	}
+	if st := o.QueueStats(); st.InboxFull != 1 || st.InboxDropped != 1 || st.Policy != QueueDropNew {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestBackpressureDropOldest(t *testing.T) {
+	o, l := newFullInboxOrchestrator(t, QueueDropOldest)
+
+	if !enqueueWithin(t, o, Message{ID: "new", Content: "late"}) {
+		t.Fatal("expected the new message to be queued")
+	}
+	if first := <-o.inbox; first.ID != "m1" {
+		t.Errorf("head of inbox = %s, want m1 after evicting m0", first.ID)
+	}
+	var last Message
+	for len(o.inbox) > 0 {
+		last = <-o.inbox
+	}
+	if last.ID != "new" {
+		t.Errorf("tail of inbox = %s, want new", last.ID)
+	}
+	dl := l.Recent(DeadLetterQueueFull, 0)
+	if len(dl) != 1 || dl[0].MessageID != "m0" {
+		t.Errorf("dead letters = %+v", dl)
+	}
+	if st := o.QueueStats(); st.InboxFull != 1 || st.InboxDropped != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestBackpressureBlockTimesOut(t *testing.T) {
+	o, l := newFullInboxOrchestrator(t, "")
+
+	start := time.Now()
+	if enqueueWithin(t, o, Message{ID: "new", Content: "late"}) {
+		t.Fatal("expected the message to time out")
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("returned after %v, want to wait for the 20ms timeout", elapsed)
+	}
+	if dl := l.Recent(DeadLetterQueueFull, 0); len(dl) != 1 || dl[0].MessageID != "new" {
+		t.Errorf("dead letters = %+v", dl)
+	}
+	if st := o.QueueStats(); st.Policy != QueueBlock || st.InboxDropped != 1 || st.InboxDepth != st.InboxCapacity {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestBackpressureBlockWaitsForSpace(t *testing.T) {
+	o, _ := newFullInboxOrchestrator(t, QueueBlock)
+	o.cfg.Server.QueueTimeoutMs = 2000
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-o.inbox
+	}()
+	if !enqueueWithin(t, o, Message{ID: "new", Content: "late"}) {
+		t.Fatal("expected the message to be queued once space freed up")
+	}
+	if st := o.QueueStats(); st.InboxFull != 1 || st.InboxDropped != 0 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestBackpressureOutboxDropNew(t *testing.T) {
+	cfg := testConfig()
+	cfg.Server.QueuePolicy = QueueDropNew
+	o := New(cfg, testLogger())
+	l, _ := NewDeadLetterLog("", 0)
+	o.SetDeadLetterLog(l)
+	for i := 0; i < cap(o.outbox); i++ {
+		o.outbox <- Response{Channel: "mock"}
+	}
+
+	if o.enqueueResponse(Response{AgentID: "a1", Channel: "mock", MessageID: "r1"}) {
+		t.Fatal("expected the response to be dropped")
+	}
+	if dl := l.Recent(DeadLetterQueueFull, 0); len(dl) != 1 || dl[0].Direction != "outbound" || dl[0].AgentID != "a1" {
+		t.Errorf("dead letters = %+v", dl)
+	}
+	if st := o.QueueStats(); st.OutboxDropped != 1 || st.InboxDropped != 0 {
+		t.Errorf("stats = %+v", st)
+	}
+}
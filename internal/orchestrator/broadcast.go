@@ -0,0 +1,145 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/channels"
+	"github.com/clawinfra/evoclaw/internal/tracing"
+)
+
+// minBroadcastCommandInterval is how often BroadcastCommand may publish;
+// every edge agent acts on a broadcast, so bursts are refused outright.
+const minBroadcastCommandInterval = 5 * time.Second
+
+var (
+	// ErrNoEdgeTransport is returned when no MQTT channel is running.
+	ErrNoEdgeTransport = errors.New("no mqtt channel for edge agents")
+	// ErrBroadcastRateLimited is returned when a broadcast command follows
+	// the previous one too closely.
+	ErrBroadcastRateLimited = errors.New("broadcast command rate limited")
+)
+
+// Ack is one edge agent's answer to a broadcast command. Agents that were
+// online when the command went out but did not answer before the timeout
+// have Acked false.
+type Ack struct {
+	AgentID string        `json:"agent_id"`
+	Acked   bool          `json:"acked"`
+	Status  string        `json:"status,omitempty"`
+	Error   string        `json:"error,omitempty"`
+	Latency time.Duration `json:"latency,omitempty"`
+}
+
+// BroadcastCommand publishes cmd with payload to every edge agent and
+// collects their acks until all agents online at send time have answered,
+// timeout elapses or ctx is done. Only one broadcast command is allowed per
+// minBroadcastCommandInterval. The result has an entry for each of those
+// agents plus any other agent that answered. Running out of time is not an
+// error; the map shows who did not respond.
+func (o *Orchestrator) BroadcastCommand(ctx context.Context, cmd string, payload map[string]interface{}, timeout time.Duration) (map[string]Ack, error) {
+	mqttCh := o.mqttChannel
+	if mqttCh == nil {
+		return nil, ErrNoEdgeTransport
+	}
+
+	o.broadcastMu.Lock()
+	if since := time.Since(o.lastBroadcastCommand); !o.lastBroadcastCommand.IsZero() && since < minBroadcastCommandInterval {
+		o.broadcastMu.Unlock()
+		return nil, fmt.Errorf("%w: retry in %s", ErrBroadcastRateLimited, (minBroadcastCommandInterval - since).Round(time.Millisecond))
+	}
+	o.lastBroadcastCommand = time.Now()
+	o.broadcastMu.Unlock()
+
+	expected := mqttCh.GetOnlineEdgeAgents()
+	results := make(map[string]Ack, len(expected))
+	for _, id := range expected {
+		results[id] = Ack{AgentID: id}
+	}
+
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	payload["sent_at"] = time.Now().Unix()
+	if tp := tracing.Traceparent(ctx); tp != "" {
+		payload[tracing.HeaderName] = tp
+	}
+
+	acks := make(chan channels.BroadcastAck, len(expected)+16)
+	sentAt := time.Now()
+	stop, err := mqttCh.BroadcastCommand(channels.EdgeAgentCommand{
+		Command:   cmd,
+		Payload:   payload,
+		RequestID: fmt.Sprintf("broadcast-%d", sentAt.UnixNano()),
+	}, acks)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	// record stores an ack and reports whether it came from an agent that
+	// was still expected to answer.
+	record := func(a channels.BroadcastAck) bool {
+		prev, known := results[a.AgentID]
+		if known && prev.Acked {
+			return false // duplicate
+		}
+		results[a.AgentID] = Ack{
+			AgentID: a.AgentID,
+			Acked:   true,
+			Status:  a.Status,
+			Error:   a.Error,
+			Latency: a.ReceivedAt.Sub(sentAt),
+		}
+		return known
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	// With no agent known to be online, listen for the whole timeout.
+	pending := len(expected)
+wait:
+	for pending > 0 || len(expected) == 0 {
+		select {
+		case a := <-acks:
+			if record(a) {
+				pending--
+			}
+		case <-timer.C:
+			break wait
+		case <-ctx.Done():
+			return results, ctx.Err()
+		}
+	}
+
+	// Keep acks that arrived alongside the last expected one.
+	for drained := false; !drained; {
+		select {
+		case a := <-acks:
+			record(a)
+		default:
+			drained = true
+		}
+	}
+
+	o.logBroadcastResult(cmd, results)
+	return results, nil
+}
+
+// logBroadcastResult logs how many agents answered a broadcast command.
+func (o *Orchestrator) logBroadcastResult(cmd string, results map[string]Ack) {
+	var missing []string
+	for id, a := range results {
+		if !a.Acked {
+			missing = append(missing, id)
+		}
+	}
+	o.logger.Info("broadcast command finished",
+		"command", cmd,
+		"acked", len(results)-len(missing),
+		"missing", missing,
+	)
+}
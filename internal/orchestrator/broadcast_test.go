@@ -0,0 +1,246 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/channels"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// displayChannel is a mockChannel that accepts broadcasts, like the TUI.
+type displayChannel struct {
+	*mockChannel
+}
+
+func (displayChannel) AcceptsBroadcast() bool { return true }
+
+func TestBroadcastToAllDisplayChannels(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	tui := displayChannel{newMockChannel("tui")}
+	cli := displayChannel{newMockChannel("cli")}
+	mqtt := newMockChannel("mqtt")
+	o.RegisterChannel(tui)
+	o.RegisterChannel(cli)
+	o.RegisterChannel(mqtt)
+
+	if err := o.Broadcast(context.Background(), "maintenance at 02:00"); err != nil {
+		t.Fatalf("Broadcast() error: %v", err)
+	}
+
+	for _, ch := range []*mockChannel{tui.mockChannel, cli.mockChannel} {
+		sent := ch.getSent()
+		if len(sent) != 1 {
+			t.Fatalf("%s received %d messages, want 1", ch.name, len(sent))
+		}
+		if sent[0].Content != "maintenance at 02:00" || sent[0].Channel != ch.name {
+			t.Errorf("%s received %+v", ch.name, sent[0])
+		}
+		if sent[0].Metadata["broadcast"] != "true" {
+			t.Errorf("%s: missing broadcast metadata", ch.name)
+		}
+	}
+	if n := len(mqtt.getSent()); n != 0 {
+		t.Errorf("mqtt received %d messages, want 0", n)
+	}
+}
+
+func TestBroadcastToNamedChannels(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	tui := displayChannel{newMockChannel("tui")}
+	mqtt := newMockChannel("mqtt")
+	o.RegisterChannel(tui)
+	o.RegisterChannel(mqtt)
+
+	if err := o.Broadcast(context.Background(), "hello", "mqtt"); err != nil {
+		t.Fatalf("Broadcast() error: %v", err)
+	}
+	if n := len(mqtt.getSent()); n != 1 {
+		t.Errorf("mqtt received %d messages, want 1", n)
+	}
+	if n := len(tui.getSent()); n != 0 {
+		t.Errorf("tui received %d messages, want 0", n)
+	}
+
+	err := o.Broadcast(context.Background(), "hello", "tui", "nope")
+	if err == nil || !strings.Contains(err.Error(), `unknown channel "nope"`) {
+		t.Errorf("expected unknown channel error, got %v", err)
+	}
+	if n := len(tui.getSent()); n != 1 {
+		t.Errorf("tui received %d messages, want 1 despite the unknown channel", n)
+	}
+}
+
+func TestBroadcastNoTargets(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.RegisterChannel(newMockChannel("mqtt"))
+	if err := o.Broadcast(context.Background(), "hello"); err == nil {
+		t.Error("expected an error when no channel accepts broadcasts")
+	}
+}
+
+type doneToken struct{}
+
+func (doneToken) Wait() bool                     { return true }
+func (doneToken) WaitTimeout(time.Duration) bool { return true }
+func (doneToken) Error() error                   { return nil }
+func (doneToken) Done() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+type edgeMessage struct {
+	topic   string
+	payload []byte
+}
+
+func (edgeMessage) Duplicate() bool   { return false }
+func (edgeMessage) Qos() byte         { return 1 }
+func (edgeMessage) Retained() bool    { return false }
+func (m edgeMessage) Topic() string   { return m.topic }
+func (edgeMessage) MessageID() uint16 { return 0 }
+func (m edgeMessage) Payload() []byte { return m.payload }
+func (edgeMessage) Ack()              {}
+
+// edgeFleet stands in for the broker and a set of edge agents. Agents in
+// replies answer every broadcast command with the given status; the others
+// stay silent.
+type edgeFleet struct {
+	opts     *mqtt.ClientOptions
+	mu       sync.Mutex
+	handlers map[string]mqtt.MessageHandler
+	replies  map[string]string
+	commands []channels.EdgeAgentCommand
+	topics   []string
+}
+
+func (f *edgeFleet) Connect() mqtt.Token {
+	f.opts.OnConnect(nil)
+	return doneToken{}
+}
+
+func (f *edgeFleet) Disconnect(uint)   {}
+func (f *edgeFleet) IsConnected() bool { return true }
+
+func (f *edgeFleet) Subscribe(topic string, _ byte, cb mqtt.MessageHandler) mqtt.Token {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[topic] = cb
+	return doneToken{}
+}
+
+func (f *edgeFleet) Publish(topic string, _ byte, _ bool, payload interface{}) mqtt.Token {
+	f.mu.Lock()
+	f.topics = append(f.topics, topic)
+	f.mu.Unlock()
+	if topic != "evoclaw/broadcast" {
+		return doneToken{}
+	}
+	var cmd channels.EdgeAgentCommand
+	_ = json.Unmarshal(payload.([]byte), &cmd)
+	f.mu.Lock()
+	f.commands = append(f.commands, cmd)
+	f.mu.Unlock()
+	for agent, status := range f.replies {
+		f.report(agent, "ack", map[string]interface{}{"request_id": cmd.RequestID, "status": status})
+	}
+	return doneToken{}
+}
+
+func (f *edgeFleet) report(agent, reportType string, payload map[string]interface{}) {
+	data, _ := json.Marshal(channels.AgentReport{AgentID: agent, ReportType: reportType, Payload: payload})
+	f.mu.Lock()
+	handler := f.handlers["evoclaw/agents/+/reports"]
+	f.mu.Unlock()
+	handler(nil, edgeMessage{topic: fmt.Sprintf("evoclaw/agents/%s/reports", agent), payload: data})
+}
+
+// startEdgeFleet wires an MQTT channel backed by fleet into o and brings
+// the given agents online.
+func startEdgeFleet(t *testing.T, o *Orchestrator, fleet *edgeFleet, online ...string) {
+	t.Helper()
+	fleet.handlers = make(map[string]mqtt.MessageHandler)
+	ch := channels.NewMQTTWithClient("localhost", 1883, "", "", testLogger(), func(opts *mqtt.ClientOptions) channels.MQTTClient {
+		fleet.opts = opts
+		return fleet
+	})
+	if err := ch.Start(context.Background()); err != nil {
+		t.Fatalf("start mqtt: %v", err)
+	}
+	t.Cleanup(func() { _ = ch.Stop() })
+	o.mqttChannel = ch
+	for _, agent := range online {
+		fleet.report(agent, "heartbeat", nil)
+	}
+}
+
+func TestBroadcastCommand_PartialAcks(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	fleet := &edgeFleet{replies: map[string]string{"edge-1": "ok", "edge-3": "error", "edge-9": "ok"}}
+	startEdgeFleet(t, o, fleet, "edge-1", "edge-2", "edge-3")
+
+	acks, err := o.BroadcastCommand(context.Background(), "reload", map[string]interface{}{"reason": "config"}, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("BroadcastCommand() error: %v", err)
+	}
+
+	if len(fleet.commands) != 1 || fleet.commands[0].Command != "reload" || fleet.commands[0].Payload["reason"] != "config" {
+		t.Fatalf("published commands = %+v", fleet.commands)
+	}
+	want := map[string]struct {
+		acked  bool
+		status string
+	}{
+		"edge-1": {true, "ok"},
+		"edge-2": {false, ""},
+		"edge-3": {true, "error"},
+		"edge-9": {true, "ok"}, // answered although it was not known to be online
+	}
+	if len(acks) != len(want) {
+		t.Fatalf("got %d acks, want %d: %+v", len(acks), len(want), acks)
+	}
+	for id, w := range want {
+		a, ok := acks[id]
+		if !ok {
+			t.Errorf("no entry for %s", id)
+			continue
+		}
+		if a.AgentID != id || a.Acked != w.acked || a.Status != w.status {
+			t.Errorf("%s: got %+v, want acked=%v status=%q", id, a, w.acked, w.status)
+		}
+	}
+}
+
+func TestBroadcastCommand_RateLimited(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	fleet := &edgeFleet{replies: map[string]string{"edge-1": "ok"}}
+	startEdgeFleet(t, o, fleet, "edge-1")
+
+	start := time.Now()
+	if _, err := o.BroadcastCommand(context.Background(), "ping", nil, time.Second); err != nil {
+		t.Fatalf("first broadcast: %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("broadcast waited for the timeout although every agent acked")
+	}
+	if _, err := o.BroadcastCommand(context.Background(), "ping", nil, time.Second); !errors.Is(err, ErrBroadcastRateLimited) {
+		t.Errorf("second broadcast err = %v, want ErrBroadcastRateLimited", err)
+	}
+	if len(fleet.commands) != 1 {
+		t.Errorf("published %d commands, want 1", len(fleet.commands))
+	}
+}
+
+func TestBroadcastCommand_NoMQTT(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	if _, err := o.BroadcastCommand(context.Background(), "ping", nil, time.Second); !errors.Is(err, ErrNoEdgeTransport) {
+		t.Errorf("err = %v, want ErrNoEdgeTransport", err)
+	}
+}
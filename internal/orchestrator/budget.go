@@ -0,0 +1,353 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// softBudgetRatio is the share of a cost budget after which agents are
+// downshifted to the cheaper routing.simple model.
+const softBudgetRatio = 0.8
+
+// budgetStateFile holds the current period's spend under server.dataDir so
+// a restart does not hand every agent a fresh budget.
+const budgetStateFile = "budget.json"
+
+// ErrCostBudgetExceeded is returned when an agent or the whole server has
+// spent its budget for the current period and no free model is available.
+var ErrCostBudgetExceeded = errors.New("cost budget exceeded")
+
+// costTracker accumulates LLM spend for the current budget period. The zero
+// value is ready to use; spend resets when the period rolls over.
+type costTracker struct {
+	mu          sync.Mutex
+	periodStart time.Time
+	agents      map[string]float64
+	total       float64
+	// alerted holds the highest budget level announced per agent this
+	// period, so each threshold is published once.
+	alerted map[string]int
+	// dirty is set when spend or alerts changed since the last save.
+	dirty bool
+	// now is overridable in tests.
+	now func() time.Time
+}
+
+// budgetPeriodStart returns the start (UTC) of the daily or monthly period
+// containing t.
+func budgetPeriodStart(t time.Time, period string) time.Time {
+	t = t.UTC()
+	if period == "monthly" {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// rollLocked resets spend if the current time is past the tracked period.
+func (c *costTracker) rollLocked(period string) {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	start := budgetPeriodStart(now(), period)
+	if c.agents == nil || !start.Equal(c.periodStart) {
+		c.periodStart = start
+		c.agents = make(map[string]float64)
+		c.total = 0
+		c.alerted = make(map[string]int)
+	}
+}
+
+func (c *costTracker) add(agentID, period string, usd float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rollLocked(period)
+	c.agents[agentID] += usd
+	c.total += usd
+	c.dirty = true
+}
+
+// spent returns the agent's and the server's spend in the current period.
+func (c *costTracker) spent(agentID, period string) (agent, total float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rollLocked(period)
+	return c.agents[agentID], c.total
+}
+
+// raise records that agentID reached level this period and reports whether
+// that is higher than any level already recorded.
+func (c *costTracker) raise(agentID, period string, level int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rollLocked(period)
+	if level <= c.alerted[agentID] {
+		return false
+	}
+	c.alerted[agentID] = level
+	c.dirty = true
+	return true
+}
+
+// budgetState is the on-disk form of a costTracker.
+type budgetState struct {
+	PeriodStart time.Time          `json:"period_start"`
+	Agents      map[string]float64 `json:"agents"`
+	Total       float64            `json:"total"`
+	Alerted     map[string]int     `json:"alerted,omitempty"`
+}
+
+// load restores spend saved by save. A missing file is not an error; spend
+// from an earlier period is dropped on the next roll.
+func (c *costTracker) load(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read budget state: %w", err)
+	}
+	var st budgetState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("parse budget state: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.periodStart = st.PeriodStart
+	c.agents = st.Agents
+	if c.agents == nil {
+		c.agents = make(map[string]float64)
+	}
+	c.total = st.Total
+	c.alerted = st.Alerted
+	if c.alerted == nil {
+		c.alerted = make(map[string]int)
+	}
+	return nil
+}
+
+// save writes the current spend to path if it changed since the last save.
+func (c *costTracker) save(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	data, err := json.Marshal(budgetState{PeriodStart: c.periodStart, Agents: c.agents, Total: c.total, Alerted: c.alerted})
+	if err != nil {
+		return fmt.Errorf("encode budget state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create budget dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write budget state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write budget state: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// budgetStatePath returns where period spend is saved, or "" when the
+// server has no data directory.
+func (o *Orchestrator) budgetStatePath() string {
+	if o.cfg == nil || o.cfg.Server.DataDir == "" {
+		return ""
+	}
+	return filepath.Join(o.cfg.Server.DataDir, budgetStateFile)
+}
+
+// persistBudget saves period spend next to the health state.
+func (o *Orchestrator) persistBudget() {
+	path := o.budgetStatePath()
+	if path == "" {
+		return
+	}
+	if err := o.costs.save(path); err != nil {
+		o.logger.Error("failed to persist budget state", "error", err)
+	}
+}
+
+func (o *Orchestrator) budgetPeriod() string {
+	if o.cfg == nil {
+		return ""
+	}
+	return o.cfg.Server.BudgetPeriod
+}
+
+// modelCost prices a call from the per-million-token rates configured for
+// model ("provider/model"). Unpriced models cost nothing.
+func (o *Orchestrator) modelCost(model string, tokensIn, tokensOut int) float64 {
+	if o.cfg == nil {
+		return 0
+	}
+	parts := strings.SplitN(model, "/", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+	for _, m := range o.cfg.Models.Providers[parts[0]].Models {
+		if m.ID == parts[1] {
+			return (float64(tokensIn)*m.CostInput + float64(tokensOut)*m.CostOutput) / 1e6
+		}
+	}
+	return 0
+}
+
+// isPaidModel reports whether model may cost money: it has a non-zero
+// configured price, or it has no configured price at all (see
+// isUnpricedModel).
+func (o *Orchestrator) isPaidModel(model string) bool {
+	return o.modelCost(model, 1e6, 1e6) > 0 || o.isUnpricedModel(model)
+}
+
+// isUnpricedModel reports whether model was discovered from the API of a
+// provider that bills for calls (anything but a local Ollama) rather than
+// configured, so there is no price to charge it at. Such models are paid
+// as far as the budget cap is concerned, even though their spend can't be
+// counted.
+func (o *Orchestrator) isUnpricedModel(model string) bool {
+	if o.cfg == nil {
+		return false
+	}
+	parts := strings.SplitN(model, "/", 2)
+	if len(parts) != 2 || parts[0] == "ollama" {
+		return false
+	}
+	prov, ok := o.cfg.Models.Providers[parts[0]]
+	if !ok || !prov.DiscoverModels {
+		return false
+	}
+	for _, m := range prov.Models {
+		if m.ID == parts[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// chargeCost prices a completed call, adds it to the agent's lifetime
+// CostUSD and to the current budget period, and returns the cost.
+func (o *Orchestrator) chargeCost(agent *AgentState, model string, tokensIn, tokensOut int) float64 {
+	cost := o.modelCost(model, tokensIn, tokensOut)
+	if cost == 0 {
+		return 0
+	}
+	agent.mu.Lock()
+	agent.Metrics.CostUSD += cost
+	agent.mu.Unlock()
+	o.costs.add(agent.ID, o.budgetPeriod(), cost)
+	return cost
+}
+
+// applyBudget returns the model agent should use given its and the global
+// cost budget. Past softBudgetRatio of either budget it downshifts to
+// routing.simple; at the cap it only allows free models and otherwise
+// returns ErrCostBudgetExceeded. Agents with pinModel set keep their model
+// and get ErrCostBudgetExceeded at the cap.
+func (o *Orchestrator) applyBudget(agent *AgentState, model string) (string, error) {
+	return o.routeBudget(agent, model, true)
+}
+
+// routeBudget is applyBudget; with notify false it routes the same way
+// but publishes no budget alerts and logs nothing, for previews.
+func (o *Orchestrator) routeBudget(agent *AgentState, model string, notify bool) (string, error) {
+	if o.cfg == nil {
+		return model, nil
+	}
+	agentBudget := agent.Def.CostBudgetUSD
+	globalBudget := o.cfg.Server.GlobalBudgetUSD
+	if agentBudget <= 0 && globalBudget <= 0 {
+		return model, nil
+	}
+
+	agentSpent, totalSpent := o.costs.spent(agent.ID, o.budgetPeriod())
+	var usage float64
+	if agentBudget > 0 {
+		usage = agentSpent / agentBudget
+	}
+	if globalBudget > 0 && totalSpent/globalBudget > usage {
+		usage = totalSpent / globalBudget
+	}
+	if notify {
+		o.publishBudgetThreshold(agent.ID, usage, agentSpent, agentBudget, totalSpent, globalBudget)
+	}
+
+	// A pinned agent never moves off its model: it runs at full price
+	// until the cap and is refused there.
+	cheap := o.cfg.Models.Routing.Simple
+	if agent.pinnedModel() {
+		cheap = ""
+	}
+	switch {
+	case usage >= 1:
+		if !o.isPaidModel(model) {
+			return model, nil
+		}
+		if notify && o.isUnpricedModel(model) {
+			o.logger.Warn("model has no configured price, refusing it at the budget cap",
+				"agent", agent.ID,
+				"model", model,
+			)
+		}
+		if cheap != "" && !o.isPaidModel(cheap) {
+			return cheap, nil
+		}
+		return "", fmt.Errorf("%w: agent %s spent $%.4f of $%.4f, server $%.4f of $%.4f",
+			ErrCostBudgetExceeded, agent.ID, agentSpent, agentBudget, totalSpent, globalBudget)
+	case usage >= softBudgetRatio && cheap != "" && cheap != model:
+		if notify {
+			o.logger.Info("cost budget nearly spent, downshifting model",
+				"agent", agent.ID,
+				"from", model,
+				"to", cheap,
+				"usage", usage,
+			)
+		}
+		return cheap, nil
+	}
+	return model, nil
+}
+
+// budgetRefusal is the user-facing reply sent when a budget blocks a call.
+func (o *Orchestrator) budgetRefusal() string {
+	period := "today"
+	if o.budgetPeriod() == "monthly" {
+		period = "this month"
+	}
+	return fmt.Sprintf("This agent has reached its spending limit for %s, so I can't answer right now. The budget resets automatically at the start of the next period.", period)
+}
+
+// Budget threshold levels published with EventBudgetThreshold.
+var budgetLevels = []string{"", "soft", "exceeded"}
+
+// publishBudgetThreshold publishes EventBudgetThreshold the first time in a
+// period the agent's usage passes softBudgetRatio and again at the cap.
+func (o *Orchestrator) publishBudgetThreshold(agentID string, usage, agentSpent, agentBudget, totalSpent, globalBudget float64) {
+	level := 0
+	switch {
+	case usage >= 1:
+		level = 2
+	case usage >= softBudgetRatio:
+		level = 1
+	}
+	if level == 0 || !o.costs.raise(agentID, o.budgetPeriod(), level) {
+		return
+	}
+	o.events.Publish(Event{Type: EventBudgetThreshold, AgentID: agentID, Data: map[string]interface{}{
+		"level":         budgetLevels[level],
+		"usage":         usage,
+		"agent_spent":   agentSpent,
+		"agent_budget":  agentBudget,
+		"server_spent":  totalSpent,
+		"server_budget": globalBudget,
+	}})
+}
@@ -0,0 +1,207 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// budgetConfig prices mock/premium at $0.40 and mock/cheap at $0.20 per
+// mockProvider call (100 input + 50 output tokens).
+func budgetConfig(agentBudget, globalBudget float64) *config.Config {
+	cfg := testConfig()
+	cfg.Agents[0].Model = "mock/premium"
+	cfg.Agents[0].CostBudgetUSD = agentBudget
+	cfg.Server.GlobalBudgetUSD = globalBudget
+	cfg.Models.Providers["mock"] = config.ProviderConfig{
+		Models: []config.Model{
+			{ID: "premium", CostInput: 2000, CostOutput: 4000},
+			{ID: "cheap", CostInput: 1000, CostOutput: 2000},
+			{ID: "free"},
+		},
+	}
+	cfg.Models.Routing.Simple = "mock/cheap"
+	return cfg
+}
+
+func newBudgetOrchestrator(t *testing.T, cfg *config.Config) (*Orchestrator, *AgentState) {
+	t.Helper()
+	o := New(cfg, testLogger())
+	o.RegisterProvider(newMockProvider("mock"))
+	o.mu.Lock()
+	agent := o.initAgentLocked(cfg.Agents[0])
+	o.mu.Unlock()
+	return o, agent
+}
+
+func processAndReceive(t *testing.T, o *Orchestrator, agent *AgentState) Response {
+	t.Helper()
+	o.processWithAgent(agent, Message{ID: "m", Channel: "test", From: "user", Content: "hello"}, agent.Def.Model)
+	select {
+	case resp := <-o.outbox:
+		return resp
+	case <-time.After(time.Second):
+		t.Fatal("no response")
+		return Response{}
+	}
+}
+
+func TestCostBudget_DownshiftThenRefuse(t *testing.T) {
+	o, agent := newBudgetOrchestrator(t, budgetConfig(1.0, 0))
+
+	// 0.40, then 0.80: both on the preferred model.
+	for i := 0; i < 2; i++ {
+		if resp := processAndReceive(t, o, agent); resp.Model != "mock/premium" {
+			t.Fatalf("call %d used %q, want mock/premium", i+1, resp.Model)
+		}
+	}
+
+	// 80% spent: downshift to routing.simple, reaching 1.00.
+	if resp := processAndReceive(t, o, agent); resp.Model != "mock/cheap" {
+		t.Fatalf("after soft threshold used %q, want mock/cheap", resp.Model)
+	}
+
+	// Hard cap: refused with a clear message and no provider call.
+	resp := processAndReceive(t, o, agent)
+	if resp.Metadata["budget_exceeded"] != "true" {
+		t.Fatalf("expected refusal, got %+v", resp)
+	}
+	if resp.Content != o.budgetRefusal() {
+		t.Errorf("Content = %q", resp.Content)
+	}
+
+	if got := agent.Metrics.CostUSD; got < 0.99 || got > 1.01 {
+		t.Errorf("CostUSD = %v, want 1.00", got)
+	}
+	if agent.Metrics.TotalActions != 3 {
+		t.Errorf("TotalActions = %d, want 3", agent.Metrics.TotalActions)
+	}
+}
+
+func TestCostBudget_HardCapFallsBackToFreeModel(t *testing.T) {
+	cfg := budgetConfig(0.4, 0)
+	cfg.Models.Routing.Simple = "mock/free"
+	o, agent := newBudgetOrchestrator(t, cfg)
+
+	processAndReceive(t, o, agent)
+	if resp := processAndReceive(t, o, agent); resp.Model != "mock/free" {
+		t.Errorf("past the cap used %q, want mock/free", resp.Model)
+	}
+}
+
+func TestCostBudget_PinnedAgentIsNotDownshifted(t *testing.T) {
+	cfg := budgetConfig(1.0, 0)
+	cfg.Agents[0].PinModel = true
+	cfg.Models.Routing.Simple = "mock/free"
+	o, agent := newBudgetOrchestrator(t, cfg)
+
+	// 0.40, 0.80, then past the soft threshold: always the pinned model.
+	for i := 0; i < 3; i++ {
+		if resp := processAndReceive(t, o, agent); resp.Model != "mock/premium" {
+			t.Fatalf("call %d used %q, want the pinned mock/premium", i+1, resp.Model)
+		}
+	}
+
+	// At the cap a pinned agent is refused rather than moved to the free model.
+	if _, err := o.applyBudget(agent, "mock/premium"); !errors.Is(err, ErrCostBudgetExceeded) {
+		t.Errorf("err = %v, want ErrCostBudgetExceeded", err)
+	}
+}
+
+func TestCostBudget_DiscoveredModelsCountAsPaid(t *testing.T) {
+	cfg := budgetConfig(0.4, 0)
+	prov := cfg.Models.Providers["mock"]
+	prov.DiscoverModels = true
+	cfg.Models.Providers["mock"] = prov
+	o, agent := newBudgetOrchestrator(t, cfg)
+
+	processAndReceive(t, o, agent)
+
+	// mock/gpt-discovered was listed by the provider, not configured: it
+	// has no price, so at the cap it must not pass for a free model.
+	if _, err := o.applyBudget(agent, "mock/gpt-discovered"); !errors.Is(err, ErrCostBudgetExceeded) {
+		t.Errorf("unpriced discovered model: err = %v, want ErrCostBudgetExceeded", err)
+	}
+	if model, err := o.applyBudget(agent, "mock/free"); err != nil || model != "mock/free" {
+		t.Errorf("configured free model: got (%q, %v)", model, err)
+	}
+}
+
+func TestCostBudget_GlobalBudget(t *testing.T) {
+	o, agent := newBudgetOrchestrator(t, budgetConfig(0, 0.4))
+
+	if _, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: agent.ID, Message: "hi"}); err != nil {
+		t.Fatalf("first ChatSync failed: %v", err)
+	}
+	_, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: agent.ID, Message: "hi"})
+	if !errors.Is(err, ErrCostBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrCostBudgetExceeded", err)
+	}
+}
+
+func TestCostBudget_ResetsAtPeriodBoundary(t *testing.T) {
+	o, agent := newBudgetOrchestrator(t, budgetConfig(0.4, 0))
+	now := time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC)
+	o.costs.now = func() time.Time { return now }
+
+	processAndReceive(t, o, agent)
+	if _, err := o.applyBudget(agent, "mock/premium"); !errors.Is(err, ErrCostBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrCostBudgetExceeded", err)
+	}
+
+	now = now.Add(2 * time.Hour) // next UTC day
+	model, err := o.applyBudget(agent, "mock/premium")
+	if err != nil || model != "mock/premium" {
+		t.Errorf("after reset got (%q, %v), want mock/premium", model, err)
+	}
+}
+
+func TestCostBudget_SpendSurvivesRestart(t *testing.T) {
+	cfg := budgetConfig(0.4, 0)
+	cfg.Server.DataDir = t.TempDir()
+	o, agent := newBudgetOrchestrator(t, cfg)
+	processAndReceive(t, o, agent)
+	o.persistBudget()
+
+	restarted, agent2 := newBudgetOrchestrator(t, cfg)
+	if err := restarted.costs.load(restarted.budgetStatePath()); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if spent, _ := restarted.costs.spent(agent2.ID, restarted.budgetPeriod()); spent < 0.4 {
+		t.Fatalf("restored spend = %v, want at least 0.4", spent)
+	}
+	if _, err := restarted.applyBudget(agent2, "mock/premium"); !errors.Is(err, ErrCostBudgetExceeded) {
+		t.Errorf("err = %v, want ErrCostBudgetExceeded after restart", err)
+	}
+}
+
+func TestCostBudget_StaleSpendDroppedOnLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), budgetStateFile)
+	old := costTracker{now: func() time.Time { return time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC) }}
+	old.add("a", "daily", 1)
+	if err := old.save(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	c := costTracker{now: func() time.Time { return time.Date(2026, 3, 11, 1, 0, 0, 0, time.UTC) }}
+	if err := c.load(path); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if agent, total := c.spent("a", "daily"); agent != 0 || total != 0 {
+		t.Errorf("spent = (%v, %v), want yesterday's spend dropped", agent, total)
+	}
+}
+
+func TestBudgetPeriodStart(t *testing.T) {
+	ts := time.Date(2026, 3, 10, 15, 4, 5, 0, time.UTC)
+	if got := budgetPeriodStart(ts, "daily"); !got.Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("daily = %v", got)
+	}
+	if got := budgetPeriodStart(ts, "monthly"); !got.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("monthly = %v", got)
+	}
+}
@@ -0,0 +1,109 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// CapabilityMismatch is an agent capability that the registered tools
+// don't agree with.
+type CapabilityMismatch struct {
+	AgentID    string
+	Capability config.Capability
+	// Tool is the tool requiring a different version, or "" when no tool
+	// requires the capability at all.
+	Tool   string
+	Reason string
+}
+
+func (m CapabilityMismatch) String() string {
+	if m.Tool == "" {
+		return fmt.Sprintf("agent %s: capability %s: %s", m.AgentID, m.Capability, m.Reason)
+	}
+	return fmt.Sprintf("agent %s: capability %s: tool %s %s", m.AgentID, m.Capability, m.Tool, m.Reason)
+}
+
+// CheckCapabilities compares each agent's capabilities with the
+// permissions tools require. A capability is reported when no tool
+// requires it, which usually means it was misspelled or renamed, and when
+// a tool requires it at a version the agent's capability doesn't satisfy.
+func CheckCapabilities(agents []config.AgentDef, tools []ToolSchema) []CapabilityMismatch {
+	var mismatches []CapabilityMismatch
+	for _, def := range agents {
+		for _, c := range def.Capabilities {
+			backed := false
+			for _, tool := range tools {
+				for _, perm := range tool.EvoClawMeta.Permissions {
+					if config.ParseCapability(perm).Name != c.Name {
+						continue
+					}
+					backed = true
+					if !c.Satisfies(perm) {
+						mismatches = append(mismatches, CapabilityMismatch{
+							AgentID:    def.ID,
+							Capability: c,
+							Tool:       tool.Name,
+							Reason:     fmt.Sprintf("requires %s", perm),
+						})
+					}
+				}
+			}
+			if !backed {
+				mismatches = append(mismatches, CapabilityMismatch{
+					AgentID:    def.ID,
+					Capability: c,
+					Reason:     "no tool requires it",
+				})
+			}
+		}
+	}
+	return mismatches
+}
+
+// checkCapabilities checks the configured agents' capabilities against the
+// installed skill tools and logs each mismatch. With
+// toolLoop.strictCapabilities set, any mismatch fails startup.
+func (o *Orchestrator) checkCapabilities() error {
+	o.mu.RLock()
+	agents := make([]config.AgentDef, len(o.cfg.Agents))
+	for i, def := range o.cfg.Agents {
+		agents[i] = o.withTypeDefaultsLocked(def)
+	}
+	tm := o.toolManager
+	o.mu.RUnlock()
+
+	declared := false
+	for _, def := range agents {
+		if len(def.Capabilities) > 0 {
+			declared = true
+			break
+		}
+	}
+	if !declared {
+		return nil
+	}
+
+	if tm == nil {
+		tm = NewToolManager("", nil, o.logger)
+	}
+	tools, err := tm.allSchemas()
+	if err != nil {
+		o.logger.Warn("capability check skipped: failed to load tools", "error", err)
+		return nil
+	}
+
+	mismatches := CheckCapabilities(agents, tools)
+	for _, m := range mismatches {
+		o.logger.Warn("capability mismatch",
+			"agent", m.AgentID,
+			"capability", m.Capability.String(),
+			"tool", m.Tool,
+			"reason", m.Reason,
+		)
+	}
+	if len(mismatches) > 0 && o.cfg.ToolLoop.StrictCapabilities {
+		return fmt.Errorf("%w: %d found, first: %s", ErrCapabilityMismatch, len(mismatches), mismatches[0])
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package orchestrator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func capabilityTools() []ToolSchema {
+	return []ToolSchema{
+		{Name: "read_file", EvoClawMeta: ToolMetadata{Permissions: []string{"filesystem@2.1"}}},
+		{Name: "send_payment", EvoClawMeta: ToolMetadata{Permissions: []string{"payments"}}},
+		{Name: "clock"},
+	}
+}
+
+func TestCheckCapabilities_Matching(t *testing.T) {
+	agents := []config.AgentDef{
+		{ID: "a", Capabilities: config.NewCapabilities("filesystem@2.3", "payments@1")},
+		{ID: "b", Capabilities: config.NewCapabilities("filesystem")},
+		{ID: "c"},
+	}
+	if got := CheckCapabilities(agents, capabilityTools()); len(got) != 0 {
+		t.Errorf("unexpected mismatches %v", got)
+	}
+}
+
+func TestCheckCapabilities_VersionMismatch(t *testing.T) {
+	for _, version := range []string{"1.9", "2.0", "3"} {
+		agents := []config.AgentDef{{ID: "a", Capabilities: config.NewCapabilities("filesystem@" + version)}}
+		got := CheckCapabilities(agents, capabilityTools())
+		if len(got) != 1 {
+			t.Fatalf("filesystem@%s: got %d mismatches, want 1", version, len(got))
+		}
+		if got[0].AgentID != "a" || got[0].Tool != "read_file" || got[0].Capability.Version != version {
+			t.Errorf("filesystem@%s: mismatch = %+v", version, got[0])
+		}
+	}
+}
+
+func TestCheckCapabilities_NoBackingTool(t *testing.T) {
+	agents := []config.AgentDef{{ID: "a", Capabilities: config.NewCapabilities("filesystem", "file_system")}}
+	got := CheckCapabilities(agents, capabilityTools())
+	if len(got) != 1 {
+		t.Fatalf("got %d mismatches, want 1", len(got))
+	}
+	if got[0].Capability.Name != "file_system" || got[0].Tool != "" {
+		t.Errorf("mismatch = %+v, want file_system with no tool", got[0])
+	}
+}
+
+func TestCheckCapabilitiesStrict(t *testing.T) {
+	cfg := testConfig()
+	cfg.Agents[0].Capabilities = config.NewCapabilities("filesystem", "file_system")
+	o := New(cfg, testLogger())
+	o.toolManager = newScopedToolManager(t)
+
+	if err := o.checkCapabilities(); err != nil {
+		t.Fatalf("mismatches should only warn by default: %v", err)
+	}
+	o.cfg.ToolLoop.StrictCapabilities = true
+	if err := o.checkCapabilities(); !errors.Is(err, ErrCapabilityMismatch) {
+		t.Errorf("err = %v, want ErrCapabilityMismatch", err)
+	}
+	o.cfg.Agents[0].Capabilities = config.NewCapabilities("filesystem")
+	if err := o.checkCapabilities(); err != nil {
+		t.Errorf("matching capabilities failed the check: %v", err)
+	}
+}
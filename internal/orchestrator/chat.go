@@ -17,16 +17,33 @@ type ChatSyncRequest struct {
 	ConversationID string
 	// History is prior messages to include in context
 	History []ChatMessage
+	// Model, when set, overrides model selection ("provider/model").
+	Model string
+	// Replay runs the request without side effects: agent status and
+	// metrics, the cost budget, evolution and the reporter are untouched.
+	Replay bool
 }
 
 // ChatSyncResponse represents the response from a synchronous chat
 type ChatSyncResponse struct {
-	AgentID      string `json:"agent_id"`
-	Response     string `json:"response"`
-	Model        string `json:"model"`
-	ElapsedMs    int64  `json:"elapsed_ms"`
-	TokensInput  int    `json:"tokens_input"`
-	TokensOutput int    `json:"tokens_output"`
+	AgentID      string  `json:"agent_id"`
+	Response     string  `json:"response"`
+	Model        string  `json:"model"`
+	ElapsedMs    int64   `json:"elapsed_ms"`
+	TokensInput  int     `json:"tokens_input"`
+	TokensOutput int     `json:"tokens_output"`
+	CostUSD      float64 `json:"cost_usd"`
+	// LatencyMs is the part of ElapsedMs spent waiting for the model.
+	LatencyMs int64 `json:"latency_ms"`
+	// Violations lists the genome's forbidden patterns the response
+	// matched.
+	Violations []string `json:"violations,omitempty"`
+	// Cached is set when the answer came from the response cache.
+	Cached bool `json:"cached,omitempty"`
+	// Reasoning is the model's thinking, returned only when models.reasoning
+	// is "store". ReasoningTokens counts it whenever the provider reports it.
+	Reasoning       string `json:"reasoning,omitempty"`
+	ReasoningTokens int    `json:"reasoning_tokens,omitempty"`
 }
 
 // ChatSync sends a message to an agent and waits for the LLM response.
@@ -40,21 +57,27 @@ func (o *Orchestrator) ChatSync(ctx context.Context, req ChatSyncRequest) (*Chat
 	o.mu.RUnlock()
 
 	if !ok {
-		return nil, fmt.Errorf("agent not found: %s", req.AgentID)
+		return nil, agentNotFound(req.AgentID)
+	}
+	if agent.isPaused() && !req.Replay {
+		return nil, fmt.Errorf("%w: %s", ErrAgentPaused, req.AgentID)
 	}
 
 	// 2. Select model
-	model := agent.Def.Model
-	if model == "" {
-		model = o.cfg.Models.Routing.Complex
+	model := o.preferredModel(agent)
+	if req.Model != "" {
+		model = req.Model
+	}
+	if req.Replay {
+		return o.replay(ctx, agent, req, model, start)
+	}
+	model, err := o.routeSync(agent, model, true)
+	if err != nil {
+		return nil, err
 	}
 
 	// Mark agent as running
-	agent.mu.Lock()
-	agent.Status = "running"
-	agent.LastActive = time.Now()
-	agent.MessageCount++
-	agent.mu.Unlock()
+	o.markRunning(agent)
 
 	defer func() {
 		agent.mu.Lock()
@@ -63,56 +86,53 @@ func (o *Orchestrator) ChatSync(ctx context.Context, req ChatSyncRequest) (*Chat
 	}()
 
 	// 3. Build chat request with conversation history
-	modelName := model
-	if parts := strings.SplitN(model, "/", 2); len(parts) == 2 {
-		modelName = parts[1]
-	}
-
-	messages := make([]ChatMessage, 0, len(req.History)+1)
-	messages = append(messages, req.History...)
-	messages = append(messages, ChatMessage{Role: "user", Content: req.Message})
-
-	chatReq := ChatRequest{
-		Model:        modelName,
-		SystemPrompt: agent.Def.SystemPrompt,
-		Messages:     messages,
-		MaxTokens:    4096,
-		Temperature:  0.7,
-	}
+	chatReq := o.buildSyncRequest(ctx, agent, req, model)
 
 	// 4. Find provider
-	provider := o.findProvider(model)
-	if provider == nil {
+	provider, err := o.providerFor(model)
+	if err != nil {
 		agent.mu.Lock()
 		agent.ErrorCount++
 		agent.Metrics.FailedActions++
 		agent.mu.Unlock()
-		return nil, fmt.Errorf("no provider for model: %s", model)
+		return nil, err
 	}
 
 	// 5. Call LLM provider
-	resp, err := provider.Chat(ctx, chatReq)
+	callStart := time.Now()
+	resp, cached, err := o.chatProvider(ctx, agent, provider, model, chatReq)
+	latency := time.Since(callStart)
 	if err != nil {
 		agent.mu.Lock()
 		agent.ErrorCount++
 		agent.Metrics.FailedActions++
 		agent.mu.Unlock()
-		return nil, fmt.Errorf("LLM error: %w", err)
+		return nil, &ErrProviderFailure{Provider: provider.Name(), Model: model, Err: err}
+	}
+
+	if !cached {
+		o.startShadow(agent, model, chatReq, resp, latency)
 	}
 
 	elapsed := time.Since(start)
+	var cost float64
+	if !cached {
+		cost = o.chargeCost(agent, model, resp.TokensInput, resp.TokensOutput)
+	}
 
 	// 6. Update metrics
 	agent.mu.Lock()
 	agent.Metrics.TotalActions++
 	agent.Metrics.SuccessfulActions++
-	agent.Metrics.TokensUsed += int64(resp.TokensInput + resp.TokensOutput)
+	if !cached {
+		agent.Metrics.TokensUsed += int64(resp.TokensInput + resp.TokensOutput)
+	}
 	n := float64(agent.Metrics.TotalActions)
 	agent.Metrics.AvgResponseMs = agent.Metrics.AvgResponseMs*(n-1)/n + float64(elapsed.Milliseconds())/n
 	agent.mu.Unlock()
 
 	// Report to evolution engine if available
-	if o.evolution != nil {
+	if o.evolution != nil && o.evolutionEnabled() {
 		agent.mu.RLock()
 		metrics := agent.Metrics
 		agent.mu.RUnlock()
@@ -132,7 +152,7 @@ func (o *Orchestrator) ChatSync(ctx context.Context, req ChatSyncRequest) (*Chat
 	o.mu.RUnlock()
 	if reporter != nil {
 		_ = reporter.RecordMessage(req.AgentID)
-		_ = reporter.UpdateMetrics(req.AgentID, resp.TokensInput+resp.TokensOutput, 0, elapsed.Milliseconds(), true)
+		_ = reporter.UpdateMetrics(req.AgentID, resp.TokensInput+resp.TokensOutput, cost, elapsed.Milliseconds(), true)
 	}
 
 	o.logger.Info("chat sync completed",
@@ -140,6 +160,7 @@ func (o *Orchestrator) ChatSync(ctx context.Context, req ChatSyncRequest) (*Chat
 		"model", model,
 		"elapsed", elapsed,
 		"tokens", resp.TokensInput+resp.TokensOutput,
+		"cached", cached,
 	)
 
 	return &ChatSyncResponse{
@@ -149,6 +170,92 @@ func (o *Orchestrator) ChatSync(ctx context.Context, req ChatSyncRequest) (*Chat
 		ElapsedMs:    elapsed.Milliseconds(),
 		TokensInput:  resp.TokensInput,
 		TokensOutput: resp.TokensOutput,
+		CostUSD:      cost,
+		LatencyMs:    latency.Milliseconds(),
+		Violations:   o.constraintViolations(agent, resp.Content),
+		Cached:       cached,
+
+		Reasoning:       o.keepReasoning(req.AgentID, model, resp),
+		ReasoningTokens: resp.ReasoningTokens,
+	}, nil
+}
+
+// buildSyncRequest builds the provider request for a synchronous chat.
+// routeSync checks the requested model against the agent's pin and routes
+// it through the cost budget, returning the model ChatSync calls. notify
+// is passed on to routeBudget.
+func (o *Orchestrator) routeSync(agent *AgentState, model string, notify bool) (string, error) {
+	if err := o.checkPinnedModel(agent, model); err != nil {
+		return "", err
+	}
+	return o.routeBudget(agent, model, notify)
+}
+
+func (o *Orchestrator) buildSyncRequest(ctx context.Context, agent *AgentState, req ChatSyncRequest, model string) ChatRequest {
+	modelName := model
+	if parts := strings.SplitN(model, "/", 2); len(parts) == 2 {
+		modelName = parts[1]
+	}
+
+	messages := make([]ChatMessage, 0, len(req.History)+1)
+	messages = append(messages, req.History...)
+	messages = append(messages, ChatMessage{Role: "user", Content: req.Message})
+
+	chatReq := ChatRequest{
+		Model:        modelName,
+		SystemPrompt: o.systemPrompt(ctx, agent, req.Message),
+		Messages:     messages,
+	}
+	applyModelParams(&chatReq, o.modelParams(agent))
+	return o.fitContext(chatReq, model)
+}
+
+// replay answers req with model without recording the exchange against
+// the agent. It is real spend, so it is subject to the agent's cost budget
+// and charged to it. A replay exists to try one particular model, so a call
+// the budget would downshift is refused rather than sent to another model.
+func (o *Orchestrator) replay(ctx context.Context, agent *AgentState, req ChatSyncRequest, model string, start time.Time) (*ChatSyncResponse, error) {
+	// findProvider falls back to any provider; a replay must hit the one
+	// that was asked for.
+	provider := o.findProvider(model)
+	if provider == nil || !strings.HasPrefix(model, provider.Name()) {
+		return nil, fmt.Errorf("%w: %s", ErrNoProvider, model)
+	}
+	allowed, err := o.applyBudget(agent, model)
+	if err != nil {
+		return nil, err
+	}
+	if allowed != model {
+		return nil, fmt.Errorf("%w: agent %s is limited to %s until its budget resets",
+			ErrCostBudgetExceeded, agent.ID, allowed)
+	}
+	chatReq := o.buildSyncRequest(ctx, agent, req, model)
+	callStart := time.Now()
+	resp, err := provider.Chat(ctx, chatReq)
+	if err != nil {
+		return nil, &ErrProviderFailure{Provider: provider.Name(), Model: model, Err: err}
+	}
+	latency := time.Since(callStart)
+	cost := o.chargeCost(agent, model, resp.TokensInput, resp.TokensOutput)
+
+	elapsed := time.Since(start)
+	o.logger.Info("chat replay completed",
+		"agent", req.AgentID,
+		"model", model,
+		"elapsed", elapsed,
+	)
+	return &ChatSyncResponse{
+		AgentID:      req.AgentID,
+		Response:     resp.Content,
+		Model:        model,
+		ElapsedMs:    elapsed.Milliseconds(),
+		TokensInput:  resp.TokensInput,
+		TokensOutput: resp.TokensOutput,
+		CostUSD:      cost,
+		LatencyMs:    latency.Milliseconds(),
+
+		Reasoning:       o.keepReasoning(req.AgentID, model, resp),
+		ReasoningTokens: resp.ReasoningTokens,
 	}, nil
 }
 
@@ -180,7 +287,7 @@ func (o *Orchestrator) GetAgentInfo(agentID string) *AgentInfo {
 	info := &AgentInfo{
 		ID:           agent.ID,
 		Def:          agent.Def,
-		Status:       agent.Status,
+		Status:       agent.statusLocked(),
 		StartedAt:    agent.StartedAt,
 		LastActive:   agent.LastActive,
 		MessageCount: agent.MessageCount,
@@ -0,0 +1,72 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/cloudsync"
+)
+
+// cloudAgentMemory builds an agent's cloud sync record from its current
+// state. lastConversation, when set, describes the conversation that
+// triggered the sync.
+func (o *Orchestrator) cloudAgentMemory(agent *AgentState, model string, lastConversation map[string]interface{}) *cloudsync.AgentMemory {
+	agent.mu.RLock()
+	defer agent.mu.RUnlock()
+
+	caps := agent.Def.Capabilities.Strings()
+	genome := make(map[string]interface{})
+	if agent.Def.Genome != nil {
+		genome["identity"] = agent.Def.Genome.Identity
+		genome["skills"] = agent.Def.Genome.Skills
+		genome["behavior"] = agent.Def.Genome.Behavior
+		genome["constraints"] = agent.Def.Genome.Constraints
+	}
+
+	coreMemory := map[string]interface{}{
+		"status":         agent.Status,
+		"paused":         agent.Paused,
+		"uptime_seconds": int64(time.Since(agent.StartedAt).Seconds()),
+		"last_active":    agent.LastActive.Unix(),
+		"message_count":  agent.MessageCount,
+		"error_count":    agent.ErrorCount,
+		"metrics": map[string]interface{}{
+			"total_actions":      agent.Metrics.TotalActions,
+			"successful_actions": agent.Metrics.SuccessfulActions,
+			"tokens_used":        agent.Metrics.TokensUsed,
+			"avg_response_ms":    agent.Metrics.AvgResponseMs,
+			"cost_usd":           agent.Metrics.CostUSD,
+		},
+	}
+	if lastConversation != nil {
+		coreMemory["last_conversation"] = lastConversation
+	}
+
+	return &cloudsync.AgentMemory{
+		AgentID:      agent.ID,
+		Name:         agent.Def.Name,
+		Model:        model,
+		Capabilities: caps,
+		Genome:       genome,
+		CoreMemory:   coreMemory,
+	}
+}
+
+// cloudSnapshots returns every agent's current state for the periodic
+// cloud sync snapshot.
+func (o *Orchestrator) cloudSnapshots() []*cloudsync.AgentMemory {
+	o.mu.RLock()
+	agents := make([]*AgentState, 0, len(o.agents))
+	for _, agent := range o.agents {
+		agents = append(agents, agent)
+	}
+	o.mu.RUnlock()
+
+	out := make([]*cloudsync.AgentMemory, 0, len(agents))
+	for _, agent := range agents {
+		agent.mu.RLock()
+		model := agent.Def.Model
+		agent.mu.RUnlock()
+		out = append(out, o.cloudAgentMemory(agent, model, nil))
+	}
+	return out
+}
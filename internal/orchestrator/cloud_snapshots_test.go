@@ -0,0 +1,25 @@
+package orchestrator
+
+import "testing"
+
+func TestCloudSnapshots_IncludesIdleAgents(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.mu.Lock()
+	o.initAgentLocked(o.cfg.Agents[0])
+	o.mu.Unlock()
+
+	snaps := o.cloudSnapshots()
+	if len(snaps) != 1 {
+		t.Fatalf("snapshots = %d, want 1", len(snaps))
+	}
+	s := snaps[0]
+	if s.AgentID != "test-agent" || s.Model != "mock/mock-model-1" {
+		t.Errorf("snapshot = %+v", s)
+	}
+	if _, ok := s.CoreMemory["uptime_seconds"]; !ok {
+		t.Error("snapshot has no uptime")
+	}
+	if _, ok := s.CoreMemory["last_conversation"]; ok {
+		t.Error("idle snapshot should not carry a last conversation")
+	}
+}
@@ -0,0 +1,181 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// messageOverheadTokens approximates the per-message framing (role,
+// separators) providers add on top of the content.
+const messageOverheadTokens = 4
+
+// injectedSections are the headings of context the prompt composer appends
+// to the base system prompt, lowest priority first. Each is appended after
+// the higher-priority ones, so cutting at its heading removes it and
+// anything of lower priority.
+var injectedSections = []string{
+	"## Relevant Memories",
+	"## Relevant Skills from Past Experience",
+	"## Response Style",
+}
+
+// estimateRequestTokens approximates the input tokens req will consume.
+func estimateRequestTokens(req ChatRequest) int {
+	n := estimateTokens(req.SystemPrompt)
+	for _, m := range req.Messages {
+		n += estimateMessageTokens(m)
+	}
+	if len(req.Tools) > 0 {
+		if b, err := json.Marshal(req.Tools); err == nil {
+			n += estimateTokens(string(b))
+		}
+	}
+	return n
+}
+
+func estimateMessageTokens(m ChatMessage) int {
+	n := messageOverheadTokens + estimateTokens(m.Content)
+	for _, tc := range m.ToolCalls {
+		n += estimateTokens(tc.Name)
+		if b, err := json.Marshal(tc.Arguments); err == nil {
+			n += estimateTokens(string(b))
+		}
+	}
+	return n
+}
+
+// TrimToContext returns req trimmed so its estimated input tokens fit within
+// limit. The oldest messages before the latest user message are dropped
+// first, an assistant tool call together with its results. If that is not
+// enough, context the prompt composer injected into the system prompt is
+// removed, lowest priority first. As a last resort, in a long tool loop, the
+// oldest assistant turns after the latest user message are dropped with
+// their tool results; the user message and the newest turn are always kept.
+// The base system prompt is never cut. A limit of zero or less disables
+// trimming. req itself is not modified.
+func TrimToContext(req ChatRequest, limit int) ChatRequest {
+	if limit <= 0 {
+		return req
+	}
+	total := estimateRequestTokens(req)
+	if total <= limit {
+		return req
+	}
+
+	protected := len(req.Messages)
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "user" {
+			protected = i
+			break
+		}
+	}
+
+	// Drop whole turns from the front of the unprotected history.
+	start := 0
+	for start < protected && total > limit {
+		end := start + 1
+		if len(req.Messages[start].ToolCalls) > 0 {
+			for end < protected && req.Messages[end].Role == "tool" {
+				end++
+			}
+		}
+		for _, m := range req.Messages[start:end] {
+			total -= estimateMessageTokens(m)
+		}
+		start = end
+	}
+	// Results whose call was dropped earlier would be orphaned.
+	for start < protected && req.Messages[start].Role == "tool" {
+		total -= estimateMessageTokens(req.Messages[start])
+		start++
+	}
+	if start > 0 {
+		req.Messages = append([]ChatMessage(nil), req.Messages[start:]...)
+	}
+
+	for _, heading := range injectedSections {
+		if total <= limit {
+			break
+		}
+		idx := strings.LastIndex(req.SystemPrompt, "\n\n"+heading)
+		if idx < 0 {
+			continue
+		}
+		total -= estimateTokens(req.SystemPrompt) - estimateTokens(req.SystemPrompt[:idx])
+		req.SystemPrompt = req.SystemPrompt[:idx]
+	}
+
+	if user := protected - start; total > limit && user < len(req.Messages) {
+		req.Messages = dropOldTurns(req.Messages, user, total, limit)
+	}
+	return req
+}
+
+// dropOldTurns drops the oldest assistant turns after msgs[user], each with
+// the tool results that follow it, until total fits within limit. The
+// newest turn is kept so the model still sees its latest results.
+func dropOldTurns(msgs []ChatMessage, user, total, limit int) []ChatMessage {
+	// Each turn starts at a message that is not a tool result.
+	var turns []int
+	for i := user + 1; i < len(msgs); i++ {
+		if msgs[i].Role != "tool" {
+			turns = append(turns, i)
+		}
+	}
+	if len(turns) < 2 {
+		return msgs
+	}
+
+	keep := turns[0]
+	for t := 1; t < len(turns) && total > limit; t++ {
+		for _, m := range msgs[keep:turns[t]] {
+			total -= estimateMessageTokens(m)
+		}
+		keep = turns[t]
+	}
+	if keep == turns[0] {
+		return msgs
+	}
+	out := append([]ChatMessage(nil), msgs[:user+1]...)
+	return append(out, msgs[keep:]...)
+}
+
+// contextWindow returns the context window configured for model
+// ("provider/model"), or 0 if none is declared.
+func (o *Orchestrator) contextWindow(model string) int {
+	if o.cfg == nil {
+		return 0
+	}
+	parts := strings.SplitN(model, "/", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+	for _, m := range o.cfg.Models.Providers[parts[0]].Models {
+		if m.ID == parts[1] {
+			return m.ContextWindow
+		}
+	}
+	return 0
+}
+
+// fitContext trims req to the context window of model, leaving room for
+// req.MaxTokens of output. Models without a declared window are untouched.
+func (o *Orchestrator) fitContext(req ChatRequest, model string) ChatRequest {
+	window := o.contextWindow(model)
+	if window <= 0 {
+		return req
+	}
+	limit := window - req.MaxTokens
+	if limit <= 0 {
+		limit = window
+	}
+	trimmed := TrimToContext(req, limit)
+	if dropped := len(req.Messages) - len(trimmed.Messages); dropped > 0 || trimmed.SystemPrompt != req.SystemPrompt {
+		o.logger.Debug("request trimmed to context window",
+			"model", model,
+			"limit", limit,
+			"dropped_messages", dropped,
+			"prompt_trimmed", trimmed.SystemPrompt != req.SystemPrompt)
+	}
+	return trimmed
+}
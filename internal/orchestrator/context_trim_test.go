@@ -0,0 +1,196 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// turn returns a message whose content is about n tokens.
+func turn(role, tag string, n int) ChatMessage {
+	return ChatMessage{Role: role, Content: tag + strings.Repeat("x", n*4-len(tag))}
+}
+
+func contents(msgs []ChatMessage) []string {
+	out := make([]string, len(msgs))
+	for i, m := range msgs {
+		out[i] = m.Content[:2]
+	}
+	return out
+}
+
+func TestTrimToContext_DropsOldestTurnsFirst(t *testing.T) {
+	req := ChatRequest{
+		SystemPrompt: "You are a careful assistant.",
+		Messages: []ChatMessage{
+			turn("user", "u1", 20),
+			turn("assistant", "a1", 20),
+			turn("user", "u2", 20),
+			turn("assistant", "a2", 20),
+			turn("user", "u3", 20),
+		},
+	}
+
+	got := TrimToContext(req, 80)
+
+	if got.SystemPrompt != req.SystemPrompt {
+		t.Errorf("system prompt = %q, want it preserved", got.SystemPrompt)
+	}
+	if c := contents(got.Messages); strings.Join(c, ",") != "u2,a2,u3" {
+		t.Errorf("kept %v, want the two oldest turns dropped", c)
+	}
+	if n := estimateRequestTokens(got); n > 80 {
+		t.Errorf("trimmed request is %d tokens, want <= 80", n)
+	}
+	if len(req.Messages) != 5 {
+		t.Error("TrimToContext modified its input")
+	}
+}
+
+func TestTrimToContext_KeepsLatestUserMessage(t *testing.T) {
+	req := ChatRequest{
+		SystemPrompt: "base",
+		Messages: []ChatMessage{
+			turn("user", "u1", 20),
+			turn("user", "u2", 100),
+		},
+	}
+	got := TrimToContext(req, 10)
+	if c := contents(got.Messages); len(c) != 1 || c[0] != "u2" {
+		t.Errorf("kept %v, want only the latest user message", c)
+	}
+	if got.SystemPrompt != "base" {
+		t.Errorf("system prompt = %q", got.SystemPrompt)
+	}
+}
+
+func TestTrimToContext_DropsToolCallWithResults(t *testing.T) {
+	req := ChatRequest{
+		Messages: []ChatMessage{
+			turn("user", "u1", 10),
+			{Role: "assistant", Content: "a1", ToolCalls: []ToolCall{{ID: "c1", Name: "search"}}},
+			{Role: "tool", Content: "r1" + strings.Repeat("x", 200), ToolCallID: "c1"},
+			turn("assistant", "a2", 10),
+			turn("user", "u2", 10),
+		},
+	}
+	// Enough to keep a2 and u2 but not the tool exchange.
+	got := TrimToContext(req, 35)
+	if c := contents(got.Messages); strings.Join(c, ",") != "a2,u2" {
+		t.Errorf("kept %v, want the tool call and its result dropped together", c)
+	}
+	for _, m := range got.Messages {
+		if m.Role == "tool" {
+			t.Errorf("orphaned tool result kept: %+v", m)
+		}
+	}
+}
+
+func TestTrimToContext_DropsOldToolTurnsAfterOnlyUserMessage(t *testing.T) {
+	toolTurn := func(n string) []ChatMessage {
+		return []ChatMessage{
+			{Role: "assistant", Content: "a" + n, ToolCalls: []ToolCall{{ID: "c" + n, Name: "search"}}},
+			{Role: "tool", Content: "r" + n + strings.Repeat("x", 200), ToolCallID: "c" + n},
+		}
+	}
+	base := "You are a research agent."
+	memories := "## Relevant Memories\n- " + strings.Repeat("m", 200)
+	msgs := []ChatMessage{turn("user", "u1", 10)}
+	for _, n := range []string{"1", "2", "3"} {
+		msgs = append(msgs, toolTurn(n)...)
+	}
+	req := ChatRequest{SystemPrompt: base + "\n\n" + memories, Messages: msgs}
+
+	got := TrimToContext(req, 100)
+	if c := contents(got.Messages); strings.Join(c, ",") != "u1,a3,r3" {
+		t.Errorf("kept %v, want the user message and the newest tool turn", c)
+	}
+	if got.SystemPrompt != base {
+		t.Errorf("system prompt = %q, want injected context removed first", got.SystemPrompt)
+	}
+	if n := estimateRequestTokens(got); n > 100 {
+		t.Errorf("trimmed request is %d tokens, want <= 100", n)
+	}
+	if len(req.Messages) != 7 {
+		t.Error("TrimToContext modified its input")
+	}
+
+	// The newest turn is kept even when it alone does not fit.
+	if c := contents(TrimToContext(req, 1).Messages); strings.Join(c, ",") != "u1,a3,r3" {
+		t.Errorf("kept %v, want the user message and the newest tool turn", c)
+	}
+}
+
+func TestTrimToContext_StripsInjectedContextLowestPriorityFirst(t *testing.T) {
+	base := "You are a trading agent."
+	style := "## Response Style\n- Keep answers brief and to the point."
+	skills := "## Relevant Skills from Past Experience\n1. [Risk] When: sizing → cap exposure"
+	memories := "## Relevant Memories\n- " + strings.Repeat("m", 200)
+	req := ChatRequest{
+		SystemPrompt: strings.Join([]string{base, style, skills, memories}, "\n\n"),
+		Messages:     []ChatMessage{turn("user", "u1", 5)},
+	}
+
+	limit := estimateTokens(strings.Join([]string{base, style, skills}, "\n\n")) + estimateMessageTokens(req.Messages[0])
+	got := TrimToContext(req, limit)
+	if want := strings.Join([]string{base, style, skills}, "\n\n"); got.SystemPrompt != want {
+		t.Errorf("system prompt = %q, want memories removed only", got.SystemPrompt)
+	}
+
+	got = TrimToContext(req, 1)
+	if got.SystemPrompt != base {
+		t.Errorf("system prompt = %q, want the base prompt kept", got.SystemPrompt)
+	}
+	if len(got.Messages) != 1 {
+		t.Errorf("kept %d messages, want the user message", len(got.Messages))
+	}
+}
+
+func TestTrimToContext_FitsOrDisabled(t *testing.T) {
+	req := ChatRequest{SystemPrompt: "sys", Messages: []ChatMessage{turn("user", "u1", 50), turn("user", "u2", 50)}}
+	if got := TrimToContext(req, 0); len(got.Messages) != 2 {
+		t.Error("a zero limit should disable trimming")
+	}
+	if got := TrimToContext(req, 1000); len(got.Messages) != 2 {
+		t.Error("a request within the limit should be unchanged")
+	}
+}
+
+func TestFitContext_UsesConfiguredContextWindow(t *testing.T) {
+	cfg := testConfig()
+	cfg.Models.Providers["mock"] = config.ProviderConfig{Models: []config.Model{
+		{ID: "mock-model-1", ContextWindow: 100},
+	}}
+	o := New(cfg, testLogger())
+
+	agent := &AgentState{ID: "a1", Def: config.AgentDef{
+		ID:           "a1",
+		SystemPrompt: "You are a test agent",
+		ModelParams:  config.ModelParams{MaxTokens: 40},
+	}}
+	req := ChatSyncRequest{
+		History: []ChatMessage{turn("user", "u1", 60), turn("assistant", "a1", 60)},
+		Message: "latest question",
+	}
+	got := o.buildSyncRequest(o.ctx, agent, req, "mock/mock-model-1")
+
+	// 100 token window minus 40 reserved for output.
+	if n := estimateRequestTokens(got); n > 60 {
+		t.Errorf("request is %d tokens, want <= 60", n)
+	}
+	if got.SystemPrompt != "You are a test agent" {
+		t.Errorf("system prompt = %q", got.SystemPrompt)
+	}
+	if last := got.Messages[len(got.Messages)-1]; last.Content != "latest question" {
+		t.Errorf("last message = %q, want the new user message", last.Content)
+	}
+	if len(got.Messages) != 1 {
+		t.Errorf("kept %v, want history dropped", contents(got.Messages))
+	}
+
+	// Models without a declared window are left alone.
+	if got := o.buildSyncRequest(o.ctx, agent, req, "other/model"); len(got.Messages) != 3 {
+		t.Errorf("unconfigured model kept %d messages, want 3", len(got.Messages))
+	}
+}
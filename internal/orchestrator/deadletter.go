@@ -0,0 +1,253 @@
+package orchestrator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Reasons a message or response ends up in the dead-letter log.
+const (
+	DeadLetterEmptyContent   = "empty_content"
+	DeadLetterNoAgent        = "no_agent"
+	DeadLetterAgentNotFound  = "agent_not_found"
+	DeadLetterUnknownChannel = "unknown_channel"
+	DeadLetterModerated      = "moderated"
+	DeadLetterWebhookFailed  = "webhook_failed"
+)
+
+// DefaultDeadLetterSize is the number of entries kept when none is given.
+const DefaultDeadLetterSize = 500
+
+// maxDeadLetterContent bounds the content stored per entry.
+const maxDeadLetterContent = 500
+
+// DeadLetter is a message or response that was dropped instead of delivered.
+type DeadLetter struct {
+	Reason    string    `json:"reason"`
+	Direction string    `json:"direction"` // "inbound" or "outbound"
+	Channel   string    `json:"channel,omitempty"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to,omitempty"`
+	AgentID   string    `json:"agent_id,omitempty"`
+	MessageID string    `json:"message_id,omitempty"`
+	Content   string    `json:"content,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// DeadLetterLog keeps the most recent dropped messages in memory and, when
+// backed by a file, appends them as JSONL so they survive restarts. The file
+// is compacted to the in-memory bound when the log is opened and whenever
+// it grows to twice that.
+type DeadLetterLog struct {
+	path    string
+	max     int
+	mu      sync.Mutex
+	entries []DeadLetter // oldest first, at most max
+	lines   int          // entries in the file
+}
+
+// NewDeadLetterLog opens a dead-letter log keeping the last max entries
+// (DefaultDeadLetterSize if max <= 0). An empty path keeps entries in memory
+// only; otherwise existing entries are loaded from path.
+func NewDeadLetterLog(path string, max int) (*DeadLetterLog, error) {
+	if max <= 0 {
+		max = DefaultDeadLetterSize
+	}
+	l := &DeadLetterLog{path: path, max: max}
+	if path == "" {
+		return l, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create dead-letter directory: %w", err)
+	}
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// load reads existing entries and rewrites the file if it held more than max.
+func (l *DeadLetterLog) load() error {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read dead-letter log: %w", err)
+	}
+
+	var all []DeadLetter
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // skip a torn or corrupt line rather than fail the load
+		}
+		all = append(all, e)
+	}
+	if len(all) <= l.max {
+		l.entries = all
+		l.lines = len(all)
+		return nil
+	}
+
+	l.entries = append([]DeadLetter(nil), all[len(all)-l.max:]...)
+	return l.compactLocked()
+}
+
+// compactLocked rewrites the file with just the in-memory entries. Caller
+// must hold l.mu, or be opening the log.
+func (l *DeadLetterLog) compactLocked() error {
+	var buf bytes.Buffer
+	for _, e := range l.entries {
+		line, _ := json.Marshal(e)
+		buf.Write(append(line, '\n'))
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("compact dead-letter log: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("compact dead-letter log: %w", err)
+	}
+	l.lines = len(l.entries)
+	return nil
+}
+
+// Add records e, stamping the time if unset. The entry is always kept in
+// memory; an error is returned only if the on-disk append failed.
+func (l *DeadLetterLog) Add(e DeadLetter) error {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	if len(e.Content) > maxDeadLetterContent {
+		e.Content = e.Content[:maxDeadLetterContent] + "…"
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, e)
+	if len(l.entries) > l.max {
+		l.entries = append(l.entries[:0], l.entries[len(l.entries)-l.max:]...)
+	}
+	if l.path == "" {
+		return nil
+	}
+	if l.lines >= 2*l.max {
+		// The entry is already in memory, so compacting writes it too
+		return l.compactLocked()
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal dead letter: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open dead-letter log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write dead letter: %w", err)
+	}
+	l.lines++
+	return nil
+}
+
+// Recent returns up to limit entries, newest first, optionally only those
+// with the given reason. A limit of zero or less returns them all.
+func (l *DeadLetterLog) Recent(reason string, limit int) []DeadLetter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := []DeadLetter{}
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		if reason != "" && l.entries[i].Reason != reason {
+			continue
+		}
+		out = append(out, l.entries[i])
+	}
+	return out
+}
+
+// SetDeadLetterLog sets the dead-letter log. Dropped messages are only
+// logged, not recorded, when nil.
+func (o *Orchestrator) SetDeadLetterLog(l *DeadLetterLog) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.deadLetters = l
+}
+
+// DeadLetterLog returns the dead-letter log, or nil if none is set.
+func (o *Orchestrator) DeadLetterLog() *DeadLetterLog {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.deadLetters
+}
+
+// deadLetterMessage records an inbound message dropped for reason.
+func (o *Orchestrator) deadLetterMessage(reason, agentID string, msg Message) {
+	o.addDeadLetter(DeadLetter{
+		Reason:    reason,
+		Direction: "inbound",
+		Channel:   msg.Channel,
+		From:      msg.From,
+		To:        msg.To,
+		AgentID:   agentID,
+		MessageID: msg.ID,
+		Content:   msg.Content,
+	})
+}
+
+// deadLetterResponse records an outbound response dropped for reason.
+// Control commands to edge agents (e.g. cancel) are not recorded.
+func (o *Orchestrator) deadLetterResponse(reason string, resp Response) {
+	if isControlCommand(resp) {
+		return
+	}
+	o.addDeadLetter(DeadLetter{
+		Reason:    reason,
+		Direction: "outbound",
+		Channel:   resp.Channel,
+		To:        resp.To,
+		AgentID:   resp.AgentID,
+		MessageID: resp.MessageID,
+		Content:   resp.Content,
+	})
+}
+
+// controlCommands are edge agent commands that manage the agent rather
+// than carry work; losing one is routine.
+var controlCommands = map[string]bool{
+	"cancel":      true,
+	"ping":        true,
+	"get_metrics": true,
+	"tools_list":  true,
+}
+
+// isControlCommand reports whether resp is a control command to an edge
+// agent.
+func isControlCommand(resp Response) bool {
+	return controlCommands[resp.Metadata["command"]]
+}
+
+func (o *Orchestrator) addDeadLetter(e DeadLetter) {
+	l := o.DeadLetterLog()
+	if l == nil {
+		return
+	}
+	if err := l.Add(e); err != nil {
+		o.logger.Warn("dead-letter log write failed", "reason", e.Reason, "error", err)
+	}
+}
@@ -0,0 +1,180 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func newDeadLetterOrchestrator(t *testing.T, cfg *config.Config) (*Orchestrator, *DeadLetterLog) {
+	t.Helper()
+	o := New(cfg, testLogger())
+	l, err := NewDeadLetterLog(filepath.Join(t.TempDir(), "deadletter.jsonl"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.SetDeadLetterLog(l)
+	return o, l
+}
+
+func TestDeadLetterEmptyContent(t *testing.T) {
+	o, l := newDeadLetterOrchestrator(t, testConfig())
+	o.handleMessage(Message{ID: "hb-1", Channel: "mqtt", From: "edge-1", Content: "  \n"})
+
+	got := l.Recent("", 0)
+	if len(got) != 1 {
+		t.Fatalf("got %d entries, want 1", len(got))
+	}
+	e := got[0]
+	if e.Reason != DeadLetterEmptyContent || e.Direction != "inbound" || e.Channel != "mqtt" || e.From != "edge-1" || e.MessageID != "hb-1" {
+		t.Errorf("entry = %+v", e)
+	}
+	if e.Timestamp.IsZero() {
+		t.Error("timestamp not set")
+	}
+}
+
+func TestDeadLetterSkipsControlCommands(t *testing.T) {
+	o, l := newDeadLetterOrchestrator(t, testConfig())
+	o.deadLetterResponse(DeadLetterQueueFull, Response{Channel: "mqtt", To: "edge-1", Content: "x",
+		Metadata: map[string]string{"command": "cancel"}})
+
+	if got := l.Recent("", 0); len(got) != 0 {
+		t.Errorf("entries = %+v, want none", got)
+	}
+
+	o.deadLetterResponse(DeadLetterQueueFull, Response{Channel: "mqtt", To: "edge-1", Content: "count to three",
+		Metadata: map[string]string{"command": "prompt"}})
+	o.deadLetterResponse(DeadLetterQueueFull, Response{Channel: "mqtt", To: "edge-1"})
+	if got := l.Recent("", 0); len(got) != 2 {
+		t.Errorf("got %d entries, want the dropped prompt and empty response", len(got))
+	}
+}
+
+func TestDeadLetterNoAgent(t *testing.T) {
+	o, l := newDeadLetterOrchestrator(t, config.DefaultConfig())
+	o.handleMessage(Message{ID: "m1", Channel: "telegram", From: "42", Content: "hello"})
+
+	got := l.Recent(DeadLetterNoAgent, 0)
+	if len(got) != 1 || got[0].Content != "hello" || got[0].AgentID != "" {
+		t.Fatalf("entries = %+v", got)
+	}
+}
+
+func TestDeadLetterAgentNotFound(t *testing.T) {
+	// handleMessage only reaches this when an agent is removed between
+	// selection and lookup, so record it the way that branch does.
+	o, l := newDeadLetterOrchestrator(t, testConfig())
+	o.deadLetterMessage(DeadLetterAgentNotFound, "ghost", Message{ID: "m2", Channel: "http", Content: "hi"})
+
+	got := l.Recent(DeadLetterAgentNotFound, 0)
+	if len(got) != 1 || got[0].AgentID != "ghost" || got[0].Channel != "http" {
+		t.Fatalf("entries = %+v", got)
+	}
+}
+
+func TestDeadLetterUnknownChannel(t *testing.T) {
+	o, l := newDeadLetterOrchestrator(t, testConfig())
+	go o.routeOutgoing()
+	defer o.cancel()
+
+	o.outbox <- Response{AgentID: "test-agent", Channel: "nonexistent", To: "u1", MessageID: "r1", Content: "reply"}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(l.Recent("", 0)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	got := l.Recent("", 0)
+	if len(got) != 1 {
+		t.Fatalf("got %d entries, want 1", len(got))
+	}
+	e := got[0]
+	if e.Reason != DeadLetterUnknownChannel || e.Direction != "outbound" || e.AgentID != "test-agent" || e.To != "u1" || e.Content != "reply" {
+		t.Errorf("entry = %+v", e)
+	}
+}
+
+func TestDeadLetterLogBoundedAndPersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dl", "deadletter.jsonl")
+	l, err := NewDeadLetterLog(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		reason := DeadLetterNoAgent
+		if i%2 == 1 {
+			reason = DeadLetterUnknownChannel
+		}
+		if err := l.Add(DeadLetter{Reason: reason, MessageID: fmt.Sprintf("m%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := l.Recent("", 0)
+	if len(got) != 3 || got[0].MessageID != "m4" || got[2].MessageID != "m2" {
+		t.Fatalf("Recent = %+v", got)
+	}
+	if got := l.Recent(DeadLetterUnknownChannel, 0); len(got) != 1 || got[0].MessageID != "m3" {
+		t.Errorf("filtered = %+v", got)
+	}
+	if got := l.Recent("", 1); len(got) != 1 || got[0].MessageID != "m4" {
+		t.Errorf("limited = %+v", got)
+	}
+
+	// Reopening restores the last entries and compacts the file.
+	reopened, err := NewDeadLetterLog(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Recent("", 0); len(got) != 3 || got[0].MessageID != "m4" {
+		t.Errorf("reopened = %+v", got)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 3 {
+		t.Errorf("file has %d lines after compaction, want 3", lines)
+	}
+}
+
+func TestDeadLetterLogCompactsWhileOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deadletter.jsonl")
+	l, err := NewDeadLetterLog(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if err := l.Add(DeadLetter{Reason: DeadLetterNoAgent, MessageID: fmt.Sprintf("m%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Count(string(data), "\n"); lines > 6 {
+			t.Fatalf("file has %d lines after %d entries, want at most 6", lines, i+1)
+		}
+	}
+
+	reopened, err := NewDeadLetterLog(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Recent("", 0); len(got) != 3 || got[0].MessageID != "m19" || got[2].MessageID != "m17" {
+		t.Errorf("reopened = %+v", got)
+	}
+}
+
+func TestDeadLetterContentTruncated(t *testing.T) {
+	l, _ := NewDeadLetterLog("", 0)
+	_ = l.Add(DeadLetter{Reason: DeadLetterNoAgent, Content: strings.Repeat("x", 2*maxDeadLetterContent)})
+	if got := l.Recent("", 0)[0].Content; len(got) > maxDeadLetterContent+len("…") {
+		t.Errorf("content length %d not truncated", len(got))
+	}
+}
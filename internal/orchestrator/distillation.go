@@ -0,0 +1,93 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/memory"
+)
+
+// Memory distillation defaults used when memory.distillation leaves them
+// zero.
+const (
+	defaultDistillMaxTokens   = 512
+	defaultDistillTemperature = 0.3
+	// memoryStoreTimeout bounds storing a conversation once it is distilled.
+	memoryStoreTimeout = 10 * time.Second
+)
+
+// applyDistillationConfig copies memory.distillation's timeout and retry
+// settings onto the memory config.
+func (o *Orchestrator) applyDistillationConfig(memCfg *memory.MemoryConfig) {
+	d := o.cfg.Memory.Distillation
+	if d.TimeoutSec > 0 {
+		memCfg.DistillationTimeout = time.Duration(d.TimeoutSec) * time.Second
+	}
+	if d.MaxRetries > 0 {
+		memCfg.DistillationRetries = d.MaxRetries
+	}
+	if d.RetryBackoffMs > 0 {
+		memCfg.DistillationRetryBackoff = time.Duration(d.RetryBackoffMs) * time.Millisecond
+	}
+}
+
+// distillationLLMFunc returns the memory system's LLM callback, calling
+// model with memory.distillation's sampling parameters.
+func (o *Orchestrator) distillationLLMFunc(model string) memory.LLMCallFunc {
+	maxTokens := o.cfg.Memory.Distillation.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultDistillMaxTokens
+	}
+	temperature := o.cfg.Memory.Distillation.Temperature
+	if temperature <= 0 {
+		temperature = defaultDistillTemperature
+	}
+
+	return o.completeFunc(model, maxTokens, temperature)
+}
+
+// completeFunc returns a callback that sends one system and user prompt to
+// model and returns the reply, for background LLM jobs like distillation.
+func (o *Orchestrator) completeFunc(model string, maxTokens int, temperature float64) func(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	return func(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+		provider, err := o.providerFor(model)
+		if err != nil {
+			return "", err
+		}
+		// Extract just the model ID (after the /) for the API request
+		modelID := model
+		if idx := strings.Index(model, "/"); idx > 0 {
+			modelID = model[idx+1:]
+		}
+		resp, err := provider.Chat(ctx, ChatRequest{
+			Model:        modelID,
+			SystemPrompt: systemPrompt,
+			Messages:     []ChatMessage{{Role: "user", Content: userPrompt}},
+			MaxTokens:    maxTokens,
+			Temperature:  temperature,
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.Content, nil
+	}
+}
+
+// memoryProcessTimeout bounds processing one conversation into memory:
+// every distillation attempt and backoff, plus storing the result.
+func (o *Orchestrator) memoryProcessTimeout() time.Duration {
+	cfg := memory.DefaultMemoryConfig()
+	o.applyDistillationConfig(&cfg)
+
+	total := memoryStoreTimeout
+	backoff := cfg.DistillationRetryBackoff
+	for attempt := 0; attempt <= cfg.DistillationRetries; attempt++ {
+		total += cfg.DistillationTimeout
+		if attempt < cfg.DistillationRetries {
+			total += backoff
+			backoff *= 2
+		}
+	}
+	return total
+}
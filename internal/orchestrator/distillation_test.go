@@ -0,0 +1,49 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDistillationLLMFunc_UsesConfiguredParams(t *testing.T) {
+	cfg := testConfig()
+	o := New(cfg, testLogger())
+	provider := &paramsCapturingProvider{}
+	o.RegisterProvider(provider)
+
+	llm := o.distillationLLMFunc("mock/mock-model-1")
+	if _, err := llm(context.Background(), "sys", "user"); err != nil {
+		t.Fatalf("llm: %v", err)
+	}
+	if provider.req.MaxTokens != 512 || provider.req.Temperature != 0.3 || provider.req.Model != "mock-model-1" {
+		t.Errorf("default request = %+v", provider.req)
+	}
+
+	cfg.Memory.Distillation.MaxTokens = 1024
+	cfg.Memory.Distillation.Temperature = 0.1
+	llm = o.distillationLLMFunc("mock/mock-model-1")
+	if _, err := llm(context.Background(), "sys", "user"); err != nil {
+		t.Fatalf("llm: %v", err)
+	}
+	if provider.req.MaxTokens != 1024 || provider.req.Temperature != 0.1 {
+		t.Errorf("configured request = %+v", provider.req)
+	}
+}
+
+func TestMemoryProcessTimeout_CoversRetries(t *testing.T) {
+	cfg := testConfig()
+	o := New(cfg, testLogger())
+
+	// Defaults: 3 attempts of 30s, 0.5s + 1s backoff, 10s to store.
+	if got, want := o.memoryProcessTimeout(), 101500*time.Millisecond; got != want {
+		t.Errorf("default timeout = %v, want %v", got, want)
+	}
+
+	cfg.Memory.Distillation.TimeoutSec = 5
+	cfg.Memory.Distillation.MaxRetries = 1
+	cfg.Memory.Distillation.RetryBackoffMs = 2000
+	if got, want := o.memoryProcessTimeout(), 22*time.Second; got != want {
+		t.Errorf("configured timeout = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+)
+
+// edgeCancelTimeout bounds publishing a cancel command to an edge agent
+// once the request behind its prompt has gone away.
+const edgeCancelTimeout = 5 * time.Second
+
+// messageContext returns the context msg is processed under: the
+// orchestrator's context carrying msg's trace, cancelled early by
+// CancelMessage(msg.ID). release must be called when processing ends.
+func (o *Orchestrator) messageContext(msg Message) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(o.traceContext(msg))
+	if msg.ID == "" {
+		return ctx, cancel
+	}
+
+	o.msgCancelMu.Lock()
+	if o.msgCancels == nil {
+		o.msgCancels = make(map[string]context.CancelFunc)
+	}
+	o.msgCancels[msg.ID] = cancel
+	o.msgCancelMu.Unlock()
+
+	return ctx, func() {
+		o.msgCancelMu.Lock()
+		delete(o.msgCancels, msg.ID)
+		o.msgCancelMu.Unlock()
+		cancel()
+	}
+}
+
+// CancelMessage abandons in-flight processing of the message with the
+// given ID, e.g. when the user who sent it has disconnected. An edge agent
+// working on it is told to stop. It reports whether the message was in
+// flight.
+func (o *Orchestrator) CancelMessage(id string) bool {
+	o.msgCancelMu.Lock()
+	cancel, ok := o.msgCancels[id]
+	o.msgCancelMu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// cancelEdgePrompt tells agentID to stop working on requestID by publishing
+// a cancel command on its commands topic. ctx is only used for its trace;
+// it is usually already done.
+func (o *Orchestrator) cancelEdgePrompt(ctx context.Context, mqttChan Channel, agentID, requestID string) {
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), edgeCancelTimeout)
+	defer cancel()
+
+	err := mqttChan.Send(sendCtx, Response{
+		AgentID:   agentID,
+		Channel:   "mqtt",
+		To:        agentID,
+		MessageID: requestID,
+		Metadata: map[string]string{
+			"command": "cancel",
+		},
+	})
+	if err != nil {
+		o.logger.Warn("failed to cancel edge prompt", "agent", agentID, "request_id", requestID, "error", err)
+		return
+	}
+	o.logger.Info("edge prompt cancelled", "agent", agentID, "request_id", requestID)
+}
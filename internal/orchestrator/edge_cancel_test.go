@@ -0,0 +1,120 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// silentEdge plays an edge agent on the "mqtt" channel that never answers.
+// prompted receives each prompt it is sent.
+type silentEdge struct {
+	*mockChannel
+	prompted chan Response
+}
+
+func newSilentEdge() *silentEdge {
+	return &silentEdge{mockChannel: newMockChannel("mqtt"), prompted: make(chan Response, 1)}
+}
+
+func (e *silentEdge) Send(ctx context.Context, msg Response) error {
+	if msg.Metadata["command"] == "prompt" {
+		e.prompted <- msg
+	}
+	return e.mockChannel.Send(ctx, msg)
+}
+
+// cancelSent returns the cancel command sent for requestID, if any.
+func (e *silentEdge) cancelSent(requestID string) (Response, bool) {
+	for _, r := range e.getSent() {
+		if r.Metadata["command"] == "cancel" && r.MessageID == requestID {
+			return r, true
+		}
+	}
+	return Response{}, false
+}
+
+func waitPrompt(t *testing.T, edge *silentEdge) Response {
+	t.Helper()
+	select {
+	case p := <-edge.prompted:
+		return p
+	case <-time.After(time.Second):
+		t.Fatal("prompt never sent to the edge agent")
+		return Response{}
+	}
+}
+
+func TestProcessWithEdgeAgent_CancelledContextCancelsPrompt(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	edge := newSilentEdge()
+	o.RegisterChannel(edge)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.processWithEdgeAgent(ctx, &AgentState{ID: "pi"}, Message{ID: "m1", Channel: "web", Content: "slow task"}, "edge/local", time.Now())
+	}()
+
+	prompt := waitPrompt(t, edge)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("wait did not return after the context was cancelled")
+	}
+
+	c, ok := edge.cancelSent(prompt.MessageID)
+	if !ok {
+		t.Fatalf("no cancel command for %s; sent %+v", prompt.MessageID, edge.getSent())
+	}
+	if c.To != "pi" {
+		t.Errorf("cancel sent to %q, want pi", c.To)
+	}
+	if len(o.edgeResultRegistry) != 0 {
+		t.Error("expected the result handler to be unregistered")
+	}
+	select {
+	case resp := <-o.outbox:
+		t.Errorf("unexpected response after cancel: %+v", resp)
+	default:
+	}
+}
+
+func TestCancelMessage_StopsEdgeAgentWork(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	edge := newSilentEdge()
+	o.RegisterChannel(edge)
+
+	o.mu.Lock()
+	agent := o.initAgentLocked(config.AgentDef{ID: "pi", Model: "edge/local"})
+	o.mu.Unlock()
+	agent.IsEdgeAgent = true
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.processWithAgent(agent, Message{ID: "m1", Channel: "web", Content: "slow task"}, "edge/local")
+	}()
+
+	prompt := waitPrompt(t, edge)
+	if !o.CancelMessage("m1") {
+		t.Fatal("CancelMessage reported m1 not in flight")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("processing did not stop after CancelMessage")
+	}
+	if _, ok := edge.cancelSent(prompt.MessageID); !ok {
+		t.Error("expected a cancel command for the prompt")
+	}
+	if o.CancelMessage("m1") {
+		t.Error("m1 still registered after processing ended")
+	}
+}
@@ -0,0 +1,182 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/clawinfra/evoclaw/internal/channels"
+)
+
+// describeEdgeCapabilities renders an agent's structured capabilities for
+// the edge_call description, one line per action with its params schema.
+func describeEdgeCapabilities(caps []channels.EdgeCapability) string {
+	var lines []string
+	for _, c := range caps {
+		line := "      * " + c.Name
+		if c.Description != "" {
+			line += ": " + c.Description
+		}
+		if len(c.InputSchema) > 0 {
+			if schema, err := json.Marshal(c.InputSchema); err == nil {
+				line += " params=" + string(schema)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// validateEdgeAction checks a structured edge_call against the capabilities
+// the agent advertised. Agents that only sent a summary accept anything.
+func validateEdgeAction(caps []channels.EdgeCapability, action string, params map[string]interface{}) error {
+	if len(caps) == 0 {
+		return nil
+	}
+	for _, c := range caps {
+		if c.Name == action {
+			if err := validateEdgeParams(c.InputSchema, params); err != nil {
+				return fmt.Errorf("invalid params for %s: %w", action, err)
+			}
+			return nil
+		}
+	}
+	names := make([]string, len(caps))
+	for i, c := range caps {
+		names[i] = c.Name
+	}
+	return fmt.Errorf("unknown action %q, available: %s", action, strings.Join(names, ", "))
+}
+
+// validateEdgeParams checks params against the subset of JSON Schema edge
+// agents and skill tools use: required properties, property types, enums
+// and additionalProperties=false. Nested objects are checked recursively.
+func validateEdgeParams(schema map[string]interface{}, params map[string]interface{}) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	props, _ := schema["properties"].(map[string]interface{})
+
+	for _, name := range schemaStrings(schema["required"]) {
+		if _, ok := params[name]; !ok {
+			return fmt.Errorf("missing required param %q", name)
+		}
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := props[name].(map[string]interface{})
+		if !ok {
+			if extra, set := schema["additionalProperties"].(bool); set && !extra {
+				return fmt.Errorf("unexpected param %q", name)
+			}
+			continue
+		}
+		if err := validateEdgeValue(prop, params[name]); err != nil {
+			return fmt.Errorf("param %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateEdgeValue checks one value against its property schema.
+func validateEdgeValue(prop map[string]interface{}, v interface{}) error {
+	if typ, ok := prop["type"].(string); ok && !matchesSchemaType(typ, v) {
+		return fmt.Errorf("want %s, got %s", typ, jsonTypeName(v))
+	}
+	if enum, ok := prop["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%v is not one of %v", v, enum)
+		}
+	}
+	if obj, ok := v.(map[string]interface{}); ok {
+		return validateEdgeParams(prop, obj)
+	}
+	return nil
+}
+
+// matchesSchemaType reports whether a decoded JSON value has the JSON Schema
+// type typ. Unknown types match anything.
+func matchesSchemaType(typ string, v interface{}) bool {
+	switch typ {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		switch v.(type) {
+		case float64, float32, int, int64:
+			return true
+		}
+		return false
+	case "integer":
+		switch n := v.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return n == float64(int64(n))
+		}
+		return false
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "null":
+		return v == nil
+	}
+	return true
+}
+
+// jsonTypeName names the JSON type of a decoded value for error messages.
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int64:
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// schemaStrings reads a JSON Schema string list, which may be []string when
+// built in Go or []interface{} when decoded from JSON.
+func schemaStrings(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, s := range list {
+			if str, ok := s.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
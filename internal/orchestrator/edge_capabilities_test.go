@@ -0,0 +1,131 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/channels"
+)
+
+const piCapabilities = `{
+	"agent_id": "pi",
+	"capabilities": [
+		{"name": "read_gpio", "description": "Read a GPIO pin",
+		 "input_schema": {"type": "object", "properties": {"pin": {"type": "integer"}}, "required": ["pin"], "additionalProperties": false}},
+		{"name": "snapshot", "description": "Take a camera picture",
+		 "input_schema": {"type": "object", "properties": {"resolution": {"type": "string", "enum": ["640x480", "1280x720"]}}}}
+	]
+}`
+
+// advertise delivers a capabilities message from the fleet to the channel.
+func (f *edgeFleet) advertise(t *testing.T, agent, payload string) {
+	t.Helper()
+	f.mu.Lock()
+	handler := f.handlers["evoclaw/agents/+/capabilities"]
+	f.mu.Unlock()
+	handler(nil, edgeMessage{topic: "evoclaw/agents/" + agent + "/capabilities", payload: []byte(payload)})
+}
+
+func TestBuildEdgeCallSchema_StructuredCapabilities(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	fleet := &edgeFleet{}
+	startEdgeFleet(t, o, fleet, "pi", "legacy")
+	fleet.advertise(t, "pi", piCapabilities)
+	fleet.advertise(t, "legacy", `{"agent_id":"legacy","capabilities":"temperature sensor"}`)
+
+	schema, ok := o.buildEdgeCallSchema()
+	if !ok {
+		t.Fatal("expected an edge_call schema with agents online")
+	}
+	for _, want := range []string{
+		"- legacy: temperature sensor",
+		"- pi: read_gpio, snapshot",
+		`* read_gpio: Read a GPIO pin params={"additionalProperties":false,"properties":{"pin":{"type":"integer"}}`,
+		"* snapshot: Take a camera picture",
+	} {
+		if !strings.Contains(schema.Description, want) {
+			t.Errorf("description missing %q:\n%s", want, schema.Description)
+		}
+	}
+
+	props := schema.Parameters["properties"].(map[string]interface{})
+	action := props["action"].(map[string]interface{})
+	enum, _ := action["enum"].([]string)
+	if len(enum) != 2 || enum[0] != "read_gpio" || enum[1] != "snapshot" {
+		t.Errorf("action enum = %v, want [read_gpio snapshot]", action["enum"])
+	}
+}
+
+func TestValidateEdgeAction(t *testing.T) {
+	var caps []channels.EdgeCapability
+	if err := json.Unmarshal([]byte(`[
+		{"name": "read_gpio", "input_schema": {"type": "object", "properties": {"pin": {"type": "integer"}}, "required": ["pin"], "additionalProperties": false}},
+		{"name": "snapshot", "input_schema": {"type": "object", "properties": {"resolution": {"type": "string", "enum": ["640x480", "1280x720"]}}}}
+	]`), &caps); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		action  string
+		params  map[string]interface{}
+		wantErr string
+	}{
+		{"valid", "read_gpio", map[string]interface{}{"pin": float64(17)}, ""},
+		{"missing required", "read_gpio", map[string]interface{}{}, `missing required param "pin"`},
+		{"wrong type", "read_gpio", map[string]interface{}{"pin": "17"}, "want integer, got string"},
+		{"fractional integer", "read_gpio", map[string]interface{}{"pin": 1.5}, "want integer"},
+		{"unexpected param", "read_gpio", map[string]interface{}{"pin": float64(1), "mode": "pull-up"}, `unexpected param "mode"`},
+		{"enum ok", "snapshot", map[string]interface{}{"resolution": "640x480"}, ""},
+		{"enum miss", "snapshot", map[string]interface{}{"resolution": "4k"}, "is not one of"},
+		{"extra allowed", "snapshot", map[string]interface{}{"flash": true}, ""},
+		{"unknown action", "reboot", nil, `unknown action "reboot", available: read_gpio, snapshot`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEdgeAction(caps, tt.action, tt.params)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Agents without structured capabilities accept any action.
+	if err := validateEdgeAction(nil, "anything", nil); err != nil {
+		t.Errorf("summary-only agent: %v", err)
+	}
+}
+
+func TestExecuteEdgeCall_RejectsInvalidParams(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	fleet := &edgeFleet{}
+	startEdgeFleet(t, o, fleet, "pi")
+	fleet.advertise(t, "pi", piCapabilities)
+	tl := NewToolLoop(o, nil)
+
+	result, err := tl.executeEdgeCall(context.Background(), nil, ToolCall{Name: "edge_call", Arguments: map[string]interface{}{
+		"agent_id": "pi",
+		"action":   "read_gpio",
+		"params":   map[string]interface{}{"pin": "seventeen"},
+	}}, time.Now())
+	if err != nil {
+		t.Fatalf("executeEdgeCall: %v", err)
+	}
+	if result.Status != "error" || !strings.Contains(result.Error, `param "pin"`) {
+		t.Errorf("result = %+v, want a params validation error", result)
+	}
+	for _, topic := range fleet.topics {
+		if strings.HasPrefix(topic, "evoclaw/agents/") {
+			t.Errorf("published to %s for an invalid call", topic)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package orchestrator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// streamingEdge plays an edge agent on the "mqtt" channel that answers a
+// prompt in chunks followed by the reassembled result.
+type streamingEdge struct {
+	*mockChannel
+	o      *Orchestrator
+	chunks []string
+}
+
+func (e *streamingEdge) Send(ctx context.Context, msg Response) error {
+	go func() {
+		full := ""
+		for seq, c := range e.chunks {
+			e.o.DeliverEdgeChunk(msg.MessageID, seq, c)
+			full += c
+		}
+		e.o.DeliverToolResult(msg.MessageID, map[string]interface{}{"status": "success", "content": full})
+	}()
+	return e.mockChannel.Send(ctx, msg)
+}
+
+// streamingChannel records the chunks forwarded to it.
+type streamingChannel struct {
+	*mockChannel
+	mu     sync.Mutex
+	chunks []Response
+	seqs   []int
+}
+
+func (c *streamingChannel) SendChunk(_ context.Context, msg Response, seq int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.chunks = append(c.chunks, msg)
+	c.seqs = append(c.seqs, seq)
+	return nil
+}
+
+func TestProcessWithEdgeAgent_ForwardsChunks(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.RegisterChannel(&streamingEdge{mockChannel: newMockChannel("mqtt"), o: o, chunks: []string{"one ", "two ", "three"}})
+	origin := &streamingChannel{mockChannel: newMockChannel("web")}
+	o.RegisterChannel(origin)
+
+	agent := &AgentState{ID: "pi"}
+	msg := Message{ID: "m1", Channel: "web", From: "user-1", Content: "count to three"}
+	o.processWithEdgeAgent(context.Background(), agent, msg, "edge/local", time.Now())
+
+	origin.mu.Lock()
+	if len(origin.chunks) != 3 {
+		t.Fatalf("forwarded %d chunks, want 3", len(origin.chunks))
+	}
+	for i, c := range origin.chunks {
+		if origin.seqs[i] != i || c.To != "user-1" || c.ReplyTo != "m1" {
+			t.Errorf("chunk %d = %+v (seq %d)", i, c, origin.seqs[i])
+		}
+	}
+	origin.mu.Unlock()
+
+	select {
+	case resp := <-o.outbox:
+		if resp.Content != "one two three" || resp.Channel != "web" {
+			t.Errorf("final response = %+v", resp)
+		}
+	default:
+		t.Fatal("expected the complete answer to be queued")
+	}
+
+	if len(o.edgeChunkRegistry) != 0 {
+		t.Error("expected the chunk handler to be unregistered")
+	}
+}
+
+func TestEdgeChunkForwarder_NotStreamed(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.RegisterChannel(newMockChannel("plain"))
+	agent := &AgentState{ID: "pi"}
+
+	if f := o.edgeChunkForwarder(context.Background(), agent, Message{Channel: "plain"}, "m"); f != nil {
+		t.Error("expected no forwarder for a channel without SendChunk")
+	}
+
+	o.RegisterChannel(&streamingChannel{mockChannel: newMockChannel("web")})
+	if f := o.edgeChunkForwarder(context.Background(), agent, Message{Channel: "web"}, "m"); f == nil {
+		t.Error("expected a forwarder for a streaming channel")
+	}
+	pm, err := NewPatternModerator([]string{"secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.SetModerator(pm)
+	if f := o.edgeChunkForwarder(context.Background(), agent, Message{Channel: "web"}, "m"); f != nil {
+		t.Error("expected no streaming while a moderator is set")
+	}
+}
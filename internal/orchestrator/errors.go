@@ -0,0 +1,50 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/clawinfra/evoclaw/internal/channels"
+)
+
+var (
+	// ErrAgentNotFound is returned when an agent ID is not registered.
+	ErrAgentNotFound = errors.New("agent not found")
+	// ErrNoProvider is returned when no registered provider serves a model.
+	ErrNoProvider = errors.New("no provider for model")
+	// ErrEdgeTimeout is returned when an edge agent does not answer in
+	// time. It is channels.ErrEdgeTimeout, so either matches.
+	ErrEdgeTimeout = channels.ErrEdgeTimeout
+	// ErrCapabilityMismatch is returned by Start when an agent's
+	// capabilities don't match the installed tools and
+	// toolLoop.strictCapabilities is set.
+	ErrCapabilityMismatch = errors.New("capability mismatch")
+)
+
+// ErrProviderFailure is returned when a provider's Chat call fails. Err is
+// the provider's error.
+type ErrProviderFailure struct {
+	Provider string
+	Model    string
+	Err      error
+}
+
+func (e *ErrProviderFailure) Error() string {
+	return fmt.Sprintf("LLM error: %s (%s): %v", e.Model, e.Provider, e.Err)
+}
+
+func (e *ErrProviderFailure) Unwrap() error { return e.Err }
+
+// agentNotFound wraps ErrAgentNotFound with the agent ID.
+func agentNotFound(id string) error {
+	return fmt.Errorf("%w: %s", ErrAgentNotFound, id)
+}
+
+// providerFor is findProvider that reports a missing provider as
+// ErrNoProvider.
+func (o *Orchestrator) providerFor(model string) (ModelProvider, error) {
+	if p := o.findProvider(model); p != nil {
+		return p, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoProvider, model)
+}
@@ -0,0 +1,95 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/channels"
+)
+
+var errUpstream = errors.New("upstream 529 overloaded")
+
+// failingProvider is a provider whose Chat always fails with errUpstream.
+type failingProvider struct{ *mockProvider }
+
+func (f failingProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	return nil, errUpstream
+}
+
+func TestErrAgentNotFound(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.mu.Lock()
+	o.initAgentLocked(o.cfg.Agents[0])
+	o.mu.Unlock()
+
+	_, metricsErr := o.GetAgentMetrics("ghost")
+	_, chatErr := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: "ghost", Message: "hi"})
+	for name, err := range map[string]error{
+		"GetAgentMetrics": metricsErr,
+		"ExecuteAgent":    o.ExecuteAgent(context.Background(), "ghost", "hi"),
+		"ChatSync":        chatErr,
+	} {
+		if !errors.Is(err, ErrAgentNotFound) {
+			t.Errorf("%s: err = %v, want ErrAgentNotFound", name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), "ghost") {
+			t.Errorf("%s: err = %q, want the agent ID in it", name, err)
+		}
+	}
+
+	if _, err := o.GetAgentMetrics("test-agent"); err != nil {
+		t.Errorf("GetAgentMetrics(test-agent) = %v", err)
+	}
+}
+
+func TestErrNoProvider(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.mu.Lock()
+	o.initAgentLocked(o.cfg.Agents[0])
+	o.mu.Unlock()
+
+	_, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: "test-agent", Message: "hi"})
+	if !errors.Is(err, ErrNoProvider) {
+		t.Fatalf("err = %v, want ErrNoProvider", err)
+	}
+	if _, err := o.providerFor("mock/mock-model-1"); !errors.Is(err, ErrNoProvider) {
+		t.Errorf("providerFor = %v, want ErrNoProvider", err)
+	}
+}
+
+func TestErrProviderFailure(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.RegisterProvider(failingProvider{newMockProvider("mock")})
+	o.mu.Lock()
+	o.initAgentLocked(o.cfg.Agents[0])
+	o.mu.Unlock()
+
+	_, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: "test-agent", Message: "hi"})
+	var failure *ErrProviderFailure
+	if !errors.As(err, &failure) {
+		t.Fatalf("err = %v, want *ErrProviderFailure", err)
+	}
+	if failure.Provider != "mock" || failure.Model == "" {
+		t.Errorf("failure = %+v, want provider mock and the model", failure)
+	}
+	if !errors.Is(err, errUpstream) {
+		t.Errorf("err = %v does not unwrap to the provider's error", err)
+	}
+}
+
+func TestErrEdgeTimeout(t *testing.T) {
+	if ErrEdgeTimeout != channels.ErrEdgeTimeout {
+		t.Fatal("ErrEdgeTimeout must match the channel's timeout error")
+	}
+
+	o := New(testConfig(), testLogger())
+	tl := NewToolLoop(o, NewToolManager("", nil, o.logger))
+	_, err := tl.waitForToolResult(context.Background(), "req-1", 10*time.Millisecond)
+	if !errors.Is(err, ErrEdgeTimeout) {
+		t.Errorf("waitForToolResult err = %v, want ErrEdgeTimeout", err)
+	}
+}
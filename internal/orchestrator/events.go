@@ -0,0 +1,123 @@
+package orchestrator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/channels"
+	"github.com/clawinfra/evoclaw/internal/router"
+)
+
+// Event types published on the orchestrator's event bus.
+const (
+	EventAgentOnline  = "agent.online"
+	EventAgentOffline = "agent.offline"
+	EventAgentEvolved = "agent.evolved"
+	EventAgentPaused  = "agent.paused"
+	EventAgentResumed = "agent.resumed"
+	EventAgentCold    = "agent.cold"
+	EventAgentWarmed  = "agent.warmed"
+	EventSafeMode     = "server.safe_mode"
+
+	EventModelUnhealthy  = "model.unhealthy"
+	EventModelRecovered  = "model.recovered"
+	EventBudgetThreshold = "budget.threshold"
+)
+
+// Event is a notification published to EventBus subscribers, e.g. the
+// dashboard's /api/events stream.
+type Event struct {
+	Type    string                 `json:"type"`
+	AgentID string                 `json:"agent_id,omitempty"`
+	Time    time.Time              `json:"time"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+// EventBus fans events out to subscribers. Publishing never blocks: a
+// subscriber whose buffer is full misses the event.
+type EventBus struct {
+	mu     sync.RWMutex
+	subs   map[int]chan Event
+	nextID int
+}
+
+// NewEventBus creates an empty event bus.
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[int]chan Event)}
+}
+
+// Subscribe returns a channel of future events and a function that
+// unsubscribes and closes it.
+func (b *EventBus) Subscribe(buffer int) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	ch := make(chan Event, buffer)
+	b.subs[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers ev to every subscriber, stamping the time if unset.
+// Publishing to a nil bus is a no-op.
+func (b *EventBus) Publish(ev Event) {
+	if b == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Events returns the orchestrator's event bus.
+func (o *Orchestrator) Events() *EventBus {
+	return o.events
+}
+
+// publishModelHealth turns a health registry state change into a
+// model.unhealthy or model.recovered event.
+func (o *Orchestrator) publishModelHealth(c router.StateChange) {
+	if c.To == router.StateDegraded {
+		o.events.Publish(Event{Type: EventModelUnhealthy, Data: map[string]interface{}{
+			"model":                c.Model,
+			"consecutive_failures": c.ConsecutiveFailures,
+			"error_type":           c.ErrorType,
+		}})
+		return
+	}
+	o.events.Publish(Event{Type: EventModelRecovered, Data: map[string]interface{}{"model": c.Model}})
+}
+
+// publishPresence turns an MQTT presence change into an agent.online or
+// agent.offline event.
+func (o *Orchestrator) publishPresence(p channels.PresenceEvent) {
+	ev := Event{
+		Type:    EventAgentOffline,
+		AgentID: p.AgentID,
+		Data:    map[string]interface{}{"last_seen": p.LastSeen},
+	}
+	if p.Online {
+		ev.Type = EventAgentOnline
+	}
+	if p.Capabilities != "" {
+		ev.Data["capabilities"] = p.Capabilities
+	}
+	o.events.Publish(ev)
+}
@@ -0,0 +1,59 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/channels"
+)
+
+func TestEventBusPublishSubscribe(t *testing.T) {
+	bus := NewEventBus()
+	a, unsubA := bus.Subscribe(1)
+	b, unsubB := bus.Subscribe(1)
+	defer unsubB()
+
+	bus.Publish(Event{Type: EventAgentOnline, AgentID: "edge-1"})
+	for _, ch := range []<-chan Event{a, b} {
+		select {
+		case ev := <-ch:
+			if ev.Type != EventAgentOnline || ev.Time.IsZero() {
+				t.Errorf("got %+v", ev)
+			}
+		default:
+			t.Fatal("subscriber did not receive event")
+		}
+	}
+
+	unsubA()
+	unsubA() // safe to call twice
+	if _, ok := <-a; ok {
+		t.Error("channel should be closed after unsubscribe")
+	}
+
+	// A full subscriber must not block publishing.
+	bus.Publish(Event{Type: "x"})
+	bus.Publish(Event{Type: "y"})
+	if ev := <-b; ev.Type != "x" {
+		t.Errorf("got %q, want x", ev.Type)
+	}
+}
+
+func TestPublishPresence(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	events, unsubscribe := o.Events().Subscribe(2)
+	defer unsubscribe()
+
+	seen := time.Now()
+	o.publishPresence(channels.PresenceEvent{AgentID: "edge-1", Online: true, Capabilities: "gpio", LastSeen: seen})
+	o.publishPresence(channels.PresenceEvent{AgentID: "edge-1", LastSeen: seen})
+
+	ev := <-events
+	if ev.Type != EventAgentOnline || ev.AgentID != "edge-1" || ev.Data["capabilities"] != "gpio" {
+		t.Errorf("online event = %+v", ev)
+	}
+	ev = <-events
+	if ev.Type != EventAgentOffline || ev.AgentID != "edge-1" {
+		t.Errorf("offline event = %+v", ev)
+	}
+}
@@ -0,0 +1,205 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// ErrEvolutionDisabled is returned when no evolution engine is set.
+var ErrEvolutionDisabled = errors.New("evolution engine not available")
+
+// ErrInsufficientSamples is returned by a manual evolution trigger when the
+// agent has fewer actions than evolution.minSamplesForEval.
+var ErrInsufficientSamples = errors.New("not enough samples for evaluation")
+
+// Evolution event types, also used as cloud sync event types.
+const (
+	EvolutionMutation      = "mutation"
+	EvolutionSkillMutation = "skill_mutation"
+)
+
+// EvolutionEvent records a mutation of an agent's strategy or one of its
+// skills, with the fitness that prompted it.
+type EvolutionEvent struct {
+	Type      string    `json:"type"`
+	Skill     string    `json:"skill,omitempty"`
+	Fitness   float64   `json:"fitness"`
+	Manual    bool      `json:"manual,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// evolutionEvents keeps the last evolution event per agent.
+type evolutionEvents struct {
+	mu   sync.Mutex
+	last map[string]EvolutionEvent
+}
+
+func (e *evolutionEvents) set(agentID string, ev EvolutionEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.last == nil {
+		e.last = make(map[string]EvolutionEvent)
+	}
+	e.last[agentID] = ev
+}
+
+func (e *evolutionEvents) get(agentID string) (EvolutionEvent, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ev, ok := e.last[agentID]
+	return ev, ok
+}
+
+// recordEvolution stores ev as the agent's last evolution event and
+// publishes it on the event bus.
+func (o *Orchestrator) recordEvolution(agentID string, ev EvolutionEvent) {
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+	o.lastEvolution.set(agentID, ev)
+
+	data := map[string]interface{}{
+		"type":    ev.Type,
+		"fitness": ev.Fitness,
+		"manual":  ev.Manual,
+	}
+	if ev.Skill != "" {
+		data["skill"] = ev.Skill
+	}
+	if ev.Error != "" {
+		data["error"] = ev.Error
+	}
+	o.events.Publish(Event{Type: EventAgentEvolved, AgentID: agentID, Time: ev.Timestamp, Data: data})
+}
+
+// EvolutionStatus is a snapshot of an agent's evolutionary state.
+type EvolutionStatus struct {
+	AgentID      string             `json:"agent_id"`
+	Strategy     interface{}        `json:"strategy"`
+	SkillFitness map[string]float64 `json:"skill_fitness"`
+	Divergence   float64            `json:"divergence"`
+	Samples      int64              `json:"samples"`
+	MinSamples   int                `json:"min_samples"`
+	LastEvent    *EvolutionEvent    `json:"last_event,omitempty"`
+}
+
+// genomeSource is implemented by evolution engines that hold evolved
+// genomes, such as *evolution.Engine.
+type genomeSource interface {
+	GetGenome(agentID string) (*config.Genome, error)
+}
+
+// divergenceScorer is implemented by evolution engines that track how far
+// an agent has drifted from its original strategy.
+type divergenceScorer interface {
+	DivergenceScore(agentID string) float64
+}
+
+// EvolutionStatus returns the agent's current strategy, per-skill fitness,
+// divergence and last evolution event.
+func (o *Orchestrator) EvolutionStatus(agentID string) (*EvolutionStatus, error) {
+	o.mu.RLock()
+	agent, ok := o.agents[agentID]
+	engine := o.evolution
+	o.mu.RUnlock()
+	if !ok {
+		return nil, agentNotFound(agentID)
+	}
+	if engine == nil {
+		return nil, ErrEvolutionDisabled
+	}
+
+	agent.mu.RLock()
+	genome := agent.Def.Genome
+	samples := agent.Metrics.TotalActions
+	agent.mu.RUnlock()
+
+	// The engine's genome reflects skill mutations; the agent's copy is the
+	// configured starting point.
+	if gs, ok := engine.(genomeSource); ok {
+		if g, err := gs.GetGenome(agentID); err == nil && g != nil {
+			genome = g
+		}
+	}
+
+	status := &EvolutionStatus{
+		AgentID:      agentID,
+		Strategy:     engine.GetStrategy(agentID),
+		SkillFitness: make(map[string]float64),
+		Samples:      samples,
+		MinSamples:   o.cfg.Evolution.MinSamplesForEval,
+	}
+	if genome != nil {
+		for name, skill := range genome.Skills {
+			status.SkillFitness[name] = skill.Fitness
+		}
+	}
+	if ds, ok := engine.(divergenceScorer); ok {
+		status.Divergence = ds.DivergenceScore(agentID)
+	}
+	if ev, ok := o.lastEvolution.get(agentID); ok {
+		status.LastEvent = &ev
+	}
+	return status, nil
+}
+
+// TriggerEvolution runs an evaluation and mutation cycle for one agent now
+// and returns the resulting strategy. Unless force is set, the agent must
+// have at least evolution.minSamplesForEval actions. It fails with
+// ErrSafeMode while safe mode is on, even when forced.
+func (o *Orchestrator) TriggerEvolution(agentID string, force bool) (interface{}, error) {
+	o.mu.RLock()
+	agent, ok := o.agents[agentID]
+	engine := o.evolution
+	o.mu.RUnlock()
+	if !ok {
+		return nil, agentNotFound(agentID)
+	}
+	if engine == nil {
+		return nil, ErrEvolutionDisabled
+	}
+	if o.SafeMode() {
+		return nil, ErrSafeMode
+	}
+
+	agent.mu.RLock()
+	samples := agent.Metrics.TotalActions
+	agent.mu.RUnlock()
+	if minSamples := o.cfg.Evolution.MinSamplesForEval; !force && samples < int64(minSamples) {
+		return nil, fmt.Errorf("%w: agent %s has %d, needs %d", ErrInsufficientSamples, agentID, samples, minSamples)
+	}
+
+	fitness := engine.Evaluate(agentID, o.agentEvalMetrics(agent))
+	if err := o.mutateAgent(agent, fitness, true); err != nil {
+		return nil, err
+	}
+	return engine.GetStrategy(agentID), nil
+}
+
+// agentEvalMetrics converts the agent's metrics into the map the evolution
+// engine evaluates.
+func (o *Orchestrator) agentEvalMetrics(agent *AgentState) map[string]float64 {
+	agent.mu.RLock()
+	defer agent.mu.RUnlock()
+
+	metrics := agent.Metrics
+	successRate := 0.0
+	if metrics.TotalActions > 0 {
+		successRate = float64(metrics.SuccessfulActions) / float64(metrics.TotalActions)
+	}
+	evalMetrics := map[string]float64{
+		"successRate":   successRate,
+		"avgResponseMs": metrics.AvgResponseMs,
+		"costUSD":       metrics.CostUSD,
+		"totalActions":  float64(metrics.TotalActions),
+	}
+	for k, v := range metrics.Custom {
+		evalMetrics[k] = v
+	}
+	return evalMetrics
+}
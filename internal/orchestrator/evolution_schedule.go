@@ -0,0 +1,86 @@
+package orchestrator
+
+import (
+	"math/rand/v2"
+	"sort"
+	"time"
+)
+
+// defaultEvolutionInterval is the evaluation interval when
+// evolution.evalIntervalSec is unset, which config validation only allows
+// while evolution is disabled.
+const defaultEvolutionInterval = time.Hour
+
+// evolutionInterval returns how often the evolution loop evaluates agents.
+func (o *Orchestrator) evolutionInterval() time.Duration {
+	if o.cfg.Evolution.EvalIntervalSec <= 0 {
+		return defaultEvolutionInterval
+	}
+	return time.Duration(o.cfg.Evolution.EvalIntervalSec) * time.Second
+}
+
+// evolutionJitter returns evolution.evalJitterSec, capped at the evaluation
+// interval. Zero disables jitter.
+func (o *Orchestrator) evolutionJitter() time.Duration {
+	jitter := time.Duration(o.cfg.Evolution.EvalJitterSec) * time.Second
+	interval := time.Duration(o.cfg.Evolution.EvalIntervalSec) * time.Second
+	if jitter <= 0 {
+		return 0
+	}
+	if interval > 0 && jitter > interval {
+		return interval
+	}
+	return jitter
+}
+
+// evolutionStartDelay picks how long the evolution loop waits before its
+// first round: a random duration below the jitter, so devices started
+// together do not evaluate in lockstep.
+func (o *Orchestrator) evolutionStartDelay() time.Duration {
+	jitter := o.evolutionJitter()
+	if jitter <= 0 {
+		return 0
+	}
+	return rand.N(jitter)
+}
+
+// evolutionStagger returns the pause between agents within a round, which
+// spreads one device's evaluations over the jitter range.
+func (o *Orchestrator) evolutionStagger() time.Duration {
+	jitter := o.evolutionJitter()
+	o.mu.RLock()
+	n := len(o.agents)
+	o.mu.RUnlock()
+	if jitter <= 0 || n < 2 {
+		return 0
+	}
+	return jitter / time.Duration(n)
+}
+
+// evolutionAgents returns the agents sorted by ID.
+func (o *Orchestrator) evolutionAgents() []*AgentState {
+	o.mu.RLock()
+	agents := make([]*AgentState, 0, len(o.agents))
+	for _, agent := range o.agents {
+		agents = append(agents, agent)
+	}
+	o.mu.RUnlock()
+
+	sort.Slice(agents, func(i, j int) bool { return agents[i].ID < agents[j].ID })
+	return agents
+}
+
+// sleep waits for d, returning false if the orchestrator stops first.
+func (o *Orchestrator) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return o.ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-o.ctx.Done():
+		return false
+	}
+}
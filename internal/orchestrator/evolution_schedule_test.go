@@ -0,0 +1,103 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func TestEvolutionStartDelay_Jittered(t *testing.T) {
+	cfg := testConfig()
+	cfg.Evolution.EvalIntervalSec = 3600
+	cfg.Evolution.EvalJitterSec = 60
+
+	// Two engines started together should not pick the same first round.
+	// Retry a few times so a rare identical draw does not flake the test.
+	var a, b time.Duration
+	for i := 0; i < 5; i++ {
+		a = New(cfg, testLogger()).evolutionStartDelay()
+		b = New(cfg, testLogger()).evolutionStartDelay()
+		if a != b {
+			break
+		}
+	}
+	if a == b {
+		t.Errorf("both engines start after %v, want different delays", a)
+	}
+	for _, d := range []time.Duration{a, b} {
+		if d < 0 || d >= 60*time.Second {
+			t.Errorf("start delay %v outside [0, 60s)", d)
+		}
+	}
+}
+
+func TestEvolutionJitter_Disabled(t *testing.T) {
+	cfg := testConfig()
+	cfg.Agents = append(cfg.Agents, config.AgentDef{ID: "other", Model: "mock/mock-model-1"})
+	o := New(cfg, testLogger())
+	o.mu.Lock()
+	for _, def := range cfg.Agents {
+		o.initAgentLocked(def)
+	}
+	o.mu.Unlock()
+
+	if d := o.evolutionStartDelay(); d != 0 {
+		t.Errorf("start delay = %v, want 0 without jitter", d)
+	}
+	if d := o.evolutionStagger(); d != 0 {
+		t.Errorf("stagger = %v, want 0 without jitter", d)
+	}
+}
+
+func TestEvolutionJitter_CappedAtInterval(t *testing.T) {
+	cfg := testConfig()
+	cfg.Evolution.EvalIntervalSec = 30
+	cfg.Evolution.EvalJitterSec = 300
+	o := New(cfg, testLogger())
+
+	if got := o.evolutionJitter(); got != 30*time.Second {
+		t.Errorf("jitter = %v, want it capped at the 30s interval", got)
+	}
+}
+
+func TestEvolutionStagger_SpreadsAgents(t *testing.T) {
+	cfg := testConfig()
+	cfg.Evolution.EvalIntervalSec = 3600
+	cfg.Evolution.EvalJitterSec = 90
+	cfg.Agents = append(cfg.Agents,
+		config.AgentDef{ID: "b-agent", Model: "mock/mock-model-1"},
+		config.AgentDef{ID: "a-agent", Model: "mock/mock-model-1"},
+	)
+	o := New(cfg, testLogger())
+	o.mu.Lock()
+	for _, def := range cfg.Agents {
+		o.initAgentLocked(def)
+	}
+	o.mu.Unlock()
+
+	if got := o.evolutionStagger(); got != 30*time.Second {
+		t.Errorf("stagger = %v, want 30s for three agents over 90s", got)
+	}
+
+	var ids []string
+	for _, a := range o.evolutionAgents() {
+		ids = append(ids, a.ID)
+	}
+	if len(ids) != 3 || ids[0] != "a-agent" || ids[1] != "b-agent" || ids[2] != "test-agent" {
+		t.Errorf("agents = %v, want sorted by ID", ids)
+	}
+}
+
+func TestSleep_StopsOnCancel(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.cancel()
+
+	start := time.Now()
+	if o.sleep(time.Minute) {
+		t.Error("sleep returned true after the orchestrator stopped")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("sleep did not return promptly on cancel")
+	}
+}
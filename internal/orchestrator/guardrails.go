@@ -0,0 +1,109 @@
+package orchestrator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/security"
+)
+
+// verifiedConstraints returns the agent's genome constraints once their
+// signature checks out against evolution.ownerPublicKey. Unsigned
+// constraints are accepted for backward compatibility unless
+// evolution.requireSignedGenomes is set; without a pinned owner key signed
+// constraints are treated the same way, since the key a genome carries
+// proves nothing. An agent without a genome has no constraints.
+func (o *Orchestrator) verifiedConstraints(agent *AgentState) (*config.GenomeConstraints, error) {
+	agent.mu.RLock()
+	g := agent.Def.Genome
+	agent.mu.RUnlock()
+	if g == nil {
+		return nil, nil
+	}
+
+	ownerKey := o.cfg.Evolution.OwnerPublicKey
+	switch {
+	case len(g.OwnerPublicKey) == 0 && len(g.ConstraintSignature) == 0:
+		if o.cfg.Evolution.RequireSignedGenomes {
+			return nil, fmt.Errorf("unsigned constraints rejected: %w", security.ErrMissingSignature)
+		}
+	case len(ownerKey) == 0:
+		if o.cfg.Evolution.RequireSignedGenomes {
+			return nil, fmt.Errorf("no owner key pinned: %w", security.ErrMissingPublicKey)
+		}
+	default:
+		if err := security.VerifyGenome(g, ownerKey); err != nil {
+			return nil, err
+		}
+	}
+	c := g.Constraints
+	return &c, nil
+}
+
+// guardrails renders the agent's verified constraints as a system prompt
+// section. Constraints that fail verification are left out and logged: an
+// unverified constraint may have been tampered with.
+func (o *Orchestrator) guardrails(agent *AgentState) string {
+	c, err := o.verifiedConstraints(agent)
+	if err != nil {
+		o.logger.Warn("genome constraints not injected", "agent", agent.ID, "error", err)
+		return ""
+	}
+	return constraintDirectives(c)
+}
+
+// constraintDirectives turns genome constraints into prompt guidance.
+func constraintDirectives(c *config.GenomeConstraints) string {
+	if c == nil {
+		return ""
+	}
+
+	var lines []string
+	if c.MaxLossUSD > 0 {
+		lines = append(lines, fmt.Sprintf("- Never risk losing more than $%.2f.", c.MaxLossUSD))
+	}
+	if len(c.AllowedAssets) > 0 {
+		lines = append(lines, "- Only deal in these assets: "+strings.Join(c.AllowedAssets, ", ")+".")
+	}
+	if len(c.BlockedActions) > 0 {
+		lines = append(lines, "- Never perform these actions: "+strings.Join(c.BlockedActions, ", ")+".")
+	}
+	for _, r := range c.Rules {
+		if r != "" {
+			lines = append(lines, "- "+r)
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return "## Constraints\n" + strings.Join(lines, "\n")
+}
+
+// constraintViolations returns the agent's forbidden patterns that content
+// matches, matching case-insensitively. Patterns that do not compile are
+// logged and skipped.
+func (o *Orchestrator) constraintViolations(agent *AgentState, content string) []string {
+	c, err := o.verifiedConstraints(agent)
+	if err != nil || c == nil {
+		return nil
+	}
+
+	var violated []string
+	for _, p := range c.ForbiddenPatterns {
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			o.logger.Warn("invalid forbidden pattern", "agent", agent.ID, "pattern", p, "error", err)
+			continue
+		}
+		if re.MatchString(content) {
+			violated = append(violated, p)
+		}
+	}
+	if len(violated) > 0 {
+		o.logger.Warn("response violates genome constraints", "agent", agent.ID, "patterns", violated)
+	}
+	return violated
+}
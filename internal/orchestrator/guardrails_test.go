@@ -0,0 +1,136 @@
+package orchestrator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/security"
+)
+
+// testOwnerKey is the owner key the guarded orchestrators pin.
+var testOwnerKey = ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+
+func constrainedGenome(t *testing.T, sign bool) *config.Genome {
+	t.Helper()
+	g := &config.Genome{
+		Constraints: config.GenomeConstraints{
+			MaxLossUSD:        250,
+			BlockedActions:    []string{"transfer_funds"},
+			Rules:             []string{"Always ask before irreversible actions."},
+			ForbiddenPatterns: []string{`seed phrase`},
+		},
+	}
+	if sign {
+		if err := security.SignGenome(g, testOwnerKey); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func newGuardedOrchestrator(t *testing.T, cfg *config.Config, g *config.Genome) (*Orchestrator, *mockProvider) {
+	t.Helper()
+	cfg.Agents[0].Genome = g
+	cfg.Evolution.OwnerPublicKey = testOwnerKey.Public().(ed25519.PublicKey)
+	o := New(cfg, testLogger())
+	p := newMockProvider("mock")
+	o.RegisterProvider(p)
+	o.mu.Lock()
+	o.initAgentLocked(cfg.Agents[0])
+	o.mu.Unlock()
+	return o, p
+}
+
+func TestSystemPrompt_InjectsSignedConstraints(t *testing.T) {
+	cfg := testConfig()
+	cfg.Evolution.RequireSignedGenomes = true
+	o, _ := newGuardedOrchestrator(t, cfg, constrainedGenome(t, true))
+
+	prompt := o.systemPrompt(context.Background(), o.agents["test-agent"], "hi")
+	for _, want := range []string{
+		"You are a test agent",
+		"## Constraints",
+		"Never risk losing more than $250.00.",
+		"Never perform these actions: transfer_funds.",
+		"- Always ask before irreversible actions.",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestSystemPrompt_RejectsUnsignedUnderEnforcement(t *testing.T) {
+	cfg := testConfig()
+	cfg.Evolution.RequireSignedGenomes = true
+	o, _ := newGuardedOrchestrator(t, cfg, constrainedGenome(t, false))
+	agent := o.agents["test-agent"]
+
+	if _, err := o.verifiedConstraints(agent); err == nil {
+		t.Fatal("unsigned constraints accepted with requireSignedGenomes set")
+	}
+	if prompt := o.systemPrompt(context.Background(), agent, "hi"); strings.Contains(prompt, "## Constraints") {
+		t.Errorf("unsigned constraints injected:\n%s", prompt)
+	}
+}
+
+func TestSystemPrompt_RejectsTamperedConstraints(t *testing.T) {
+	g := constrainedGenome(t, true)
+	g.Constraints.MaxLossUSD = 1e6
+	o, _ := newGuardedOrchestrator(t, testConfig(), g)
+
+	if prompt := o.systemPrompt(context.Background(), o.agents["test-agent"], "hi"); strings.Contains(prompt, "## Constraints") {
+		t.Errorf("tampered constraints injected:\n%s", prompt)
+	}
+}
+
+func TestSystemPrompt_RejectsConstraintsResignedWithOtherKey(t *testing.T) {
+	g := constrainedGenome(t, false)
+	g.Constraints.MaxLossUSD = 1e6
+	_, other, err := security.GenerateOwnerKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := security.SignGenome(g, other); err != nil {
+		t.Fatal(err)
+	}
+	o, _ := newGuardedOrchestrator(t, testConfig(), g)
+
+	if _, err := o.verifiedConstraints(o.agents["test-agent"]); !errors.Is(err, security.ErrUntrustedKey) {
+		t.Fatalf("expected ErrUntrustedKey, got %v", err)
+	}
+	if prompt := o.systemPrompt(context.Background(), o.agents["test-agent"], "hi"); strings.Contains(prompt, "## Constraints") {
+		t.Errorf("re-signed constraints injected:\n%s", prompt)
+	}
+}
+
+func TestSystemPrompt_UnsignedAllowedWithoutEnforcement(t *testing.T) {
+	o, _ := newGuardedOrchestrator(t, testConfig(), constrainedGenome(t, false))
+
+	if prompt := o.systemPrompt(context.Background(), o.agents["test-agent"], "hi"); !strings.Contains(prompt, "transfer_funds") {
+		t.Errorf("unsigned constraints not injected in backward-compat mode:\n%s", prompt)
+	}
+}
+
+func TestChatSync_FlagsForbiddenPatterns(t *testing.T) {
+	o, p := newGuardedOrchestrator(t, testConfig(), constrainedGenome(t, true))
+	p.setResponse("mock-model-1", "Sure, your Seed Phrase is ...")
+
+	resp, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: "test-agent", Message: "help"})
+	if err != nil {
+		t.Fatalf("ChatSync: %v", err)
+	}
+	if len(resp.Violations) != 1 || resp.Violations[0] != "seed phrase" {
+		t.Errorf("violations = %v, want [seed phrase]", resp.Violations)
+	}
+
+	p.setResponse("mock-model-1", "All good.")
+	resp, _ = o.ChatSync(context.Background(), ChatSyncRequest{AgentID: "test-agent", Message: "help"})
+	if len(resp.Violations) != 0 {
+		t.Errorf("violations = %v, want none", resp.Violations)
+	}
+}
@@ -0,0 +1,138 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// defaultIdleCheckInterval is how often agents are checked against their
+// idle timeout.
+const defaultIdleCheckInterval = time.Minute
+
+// modelReleaser is implemented by providers that can free a loaded model,
+// such as Ollama dropping its keep-alive. model has no provider prefix.
+// Wrappers that can't tell in advance return errors.ErrUnsupported.
+type modelReleaser interface {
+	ReleaseModel(ctx context.Context, model string) error
+}
+
+// markRunning records a new message for agent, waking it if it was cold.
+func (o *Orchestrator) markRunning(agent *AgentState) {
+	agent.mu.Lock()
+	wasCold := agent.Status == "cold"
+	agent.Status = "running"
+	agent.LastActive = time.Now()
+	agent.MessageCount++
+	if wasCold {
+		agent.Metrics.ColdStarts++
+	}
+	agent.mu.Unlock()
+
+	if wasCold {
+		o.logger.Info("agent warmed from cold", "agent", agent.ID)
+		o.events.Publish(Event{Type: EventAgentWarmed, AgentID: agent.ID})
+	}
+}
+
+// idleLoop periodically moves agents past their idle timeout to cold.
+func (o *Orchestrator) idleLoop() {
+	interval := o.idleCheckInterval
+	if interval <= 0 {
+		interval = defaultIdleCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-o.ctx.Done():
+			return
+		case now := <-ticker.C:
+			o.coolIdleAgents(now)
+		}
+	}
+}
+
+// coolIdleAgents marks every idle agent that has had no message for its
+// IdleTimeoutSec as cold, then releases the models no warm agent uses any
+// more. Agents that never had a message count from when they started.
+func (o *Orchestrator) coolIdleAgents(now time.Time) {
+	o.mu.RLock()
+	agents := make([]*AgentState, 0, len(o.agents))
+	for _, a := range o.agents {
+		agents = append(agents, a)
+	}
+	o.mu.RUnlock()
+
+	var cooled []*AgentState
+	for _, a := range agents {
+		a.mu.Lock()
+		last := a.LastActive
+		if last.IsZero() {
+			last = a.StartedAt
+		}
+		timeout := time.Duration(a.Def.IdleTimeoutSec) * time.Second
+		if timeout > 0 && a.Status == "idle" && now.Sub(last) >= timeout {
+			a.Status = "cold"
+			cooled = append(cooled, a)
+		}
+		a.mu.Unlock()
+	}
+	if len(cooled) == 0 {
+		return
+	}
+
+	// A model shared with an agent that is still warm stays loaded
+	warm := make(map[string]bool)
+	for _, a := range agents {
+		a.mu.RLock()
+		cold := a.Status == "cold"
+		a.mu.RUnlock()
+		if !cold {
+			warm[o.preferredModel(a)] = true
+		}
+	}
+	released := make(map[string]bool)
+	for _, a := range cooled {
+		model := o.preferredModel(a)
+		o.logger.Info("agent idle, going cold", "agent", a.ID, "model", model)
+		o.events.Publish(Event{Type: EventAgentCold, AgentID: a.ID, Data: map[string]interface{}{"model": model}})
+		if model == "" || warm[model] || released[model] {
+			continue
+		}
+		released[model] = true
+		o.releaseModel(model)
+	}
+}
+
+// releaseModel drops model's cached responses and asks its provider to
+// unload it, if the provider supports that.
+func (o *Orchestrator) releaseModel(model string) {
+	if o.responseCache != nil {
+		if n := o.responseCache.dropModel(model); n > 0 {
+			o.logger.Debug("dropped cached responses", "model", model, "entries", n)
+		}
+	}
+
+	idx := strings.Index(model, "/")
+	if idx <= 0 {
+		return
+	}
+	provider := o.findProvider(model)
+	releaser, ok := provider.(modelReleaser)
+	if !ok || !strings.HasPrefix(model[:idx], provider.Name()) {
+		return
+	}
+	modelID := model[idx+1:]
+	ctx, cancel := context.WithTimeout(o.ctx, 10*time.Second)
+	defer cancel()
+	if err := releaser.ReleaseModel(ctx, modelID); errors.Is(err, errors.ErrUnsupported) {
+		return
+	} else if err != nil {
+		o.logger.Warn("failed to release idle model", "model", model, "error", err)
+		return
+	}
+	o.logger.Info("released idle model", "model", model)
+}
@@ -0,0 +1,128 @@
+package orchestrator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// releasingProvider records ReleaseModel calls like Ollama dropping its
+// keep-alive.
+type releasingProvider struct {
+	*mockProvider
+	mu       sync.Mutex
+	released []string
+}
+
+func (p *releasingProvider) ReleaseModel(ctx context.Context, model string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.released = append(p.released, model)
+	return nil
+}
+
+func (p *releasingProvider) getReleased() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.released...)
+}
+
+func newIdleTestOrchestrator(t *testing.T, defs ...config.AgentDef) (*Orchestrator, *releasingProvider) {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Models.ResponseCache = config.ResponseCacheConfig{Enabled: true}
+	cfg.Agents = defs
+	o := New(cfg, testLogger())
+	p := &releasingProvider{mockProvider: newMockProvider("mock")}
+	o.RegisterProvider(p)
+	o.mu.Lock()
+	for _, def := range defs {
+		o.initAgentLocked(def)
+	}
+	o.mu.Unlock()
+	return o, p
+}
+
+func agentStatus(a *AgentState) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.Status
+}
+
+func TestIdleTimeout_ColdThenRewarm(t *testing.T) {
+	o, p := newIdleTestOrchestrator(t, config.AgentDef{
+		ID:             "edge",
+		Model:          "mock/mock-model-1",
+		IdleTimeoutSec: 60,
+		ModelParams:    config.ModelParams{Temperature: config.Float(0.1)},
+	})
+	agent := o.agents["edge"]
+	events, unsubscribe := o.events.Subscribe(4)
+	defer unsubscribe()
+
+	req := ChatSyncRequest{AgentID: "edge", Message: "status?"}
+	if _, err := o.ChatSync(context.Background(), req); err != nil {
+		t.Fatalf("ChatSync: %v", err)
+	}
+	if n := o.responseCache.stats().Entries; n != 1 {
+		t.Fatalf("cache entries = %d, want 1", n)
+	}
+
+	// Still inside the timeout: nothing happens.
+	o.coolIdleAgents(time.Now().Add(30 * time.Second))
+	if got := agentStatus(agent); got != "idle" {
+		t.Fatalf("status = %s before the timeout", got)
+	}
+
+	o.coolIdleAgents(time.Now().Add(61 * time.Second))
+	if got := agentStatus(agent); got != "cold" {
+		t.Fatalf("status = %s after the timeout, want cold", got)
+	}
+	if got := p.getReleased(); len(got) != 1 || got[0] != "mock-model-1" {
+		t.Errorf("released = %v, want [mock-model-1]", got)
+	}
+	if n := o.responseCache.stats().Entries; n != 0 {
+		t.Errorf("cache entries after going cold = %d, want 0", n)
+	}
+	if ev := <-events; ev.Type != EventAgentCold || ev.AgentID != "edge" {
+		t.Errorf("event = %+v, want agent.cold", ev)
+	}
+
+	// The next message wakes the agent.
+	if _, err := o.ChatSync(context.Background(), req); err != nil {
+		t.Fatalf("ChatSync: %v", err)
+	}
+	if got := agentStatus(agent); got != "idle" {
+		t.Errorf("status after next message = %s, want idle", got)
+	}
+	if agent.Metrics.ColdStarts != 1 {
+		t.Errorf("ColdStarts = %d, want 1", agent.Metrics.ColdStarts)
+	}
+	if p.getCalls() != 2 {
+		t.Errorf("provider calls = %d, want the cache dropped and the model called again", p.getCalls())
+	}
+	if ev := <-events; ev.Type != EventAgentWarmed {
+		t.Errorf("event = %+v, want agent.warmed", ev)
+	}
+}
+
+func TestIdleTimeout_SharedModelStaysLoaded(t *testing.T) {
+	o, p := newIdleTestOrchestrator(t,
+		config.AgentDef{ID: "sleepy", Model: "mock/mock-model-1", IdleTimeoutSec: 60},
+		config.AgentDef{ID: "busy", Model: "mock/mock-model-1"},
+	)
+
+	o.coolIdleAgents(time.Now().Add(time.Hour))
+	if got := agentStatus(o.agents["sleepy"]); got != "cold" {
+		t.Errorf("sleepy status = %s, want cold", got)
+	}
+	if got := agentStatus(o.agents["busy"]); got != "idle" {
+		t.Errorf("busy status = %s; agents without a timeout never go cold", got)
+	}
+	if got := p.getReleased(); len(got) != 0 {
+		t.Errorf("released %v while another agent still uses the model", got)
+	}
+}
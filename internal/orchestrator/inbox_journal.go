@@ -0,0 +1,282 @@
+package orchestrator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// inboxJournalFile is the durable inbox's file name under server.dataDir.
+const inboxJournalFile = "inbox.wal"
+
+// inboxDedupSize is how many handled message keys are remembered, across
+// restarts, to drop a message that arrives again.
+const inboxDedupSize = 1024
+
+// inboxCompactEvery is how many records may be appended before the journal
+// is rewritten without the settled messages.
+const inboxCompactEvery = 1000
+
+// inboxRecord is one journal line. An "add" is written, and synced, before
+// a message is queued; a "done" once it has been handled or dropped.
+type inboxRecord struct {
+	Op  string   `json:"op"` // "add" or "done"
+	Key string   `json:"key"`
+	Msg *Message `json:"msg,omitempty"`
+}
+
+// inboxJournal is the write-ahead log behind server.durableInbox. Messages
+// still pending when the process dies are replayed on the next start, so
+// delivery is at least once; the consumer uses claim to drop a message it
+// has already handled or is handling.
+type inboxJournal struct {
+	path string
+
+	mu       sync.Mutex
+	f        *os.File
+	pending  map[string]Message
+	order    []string // pending keys, oldest first (may hold settled keys)
+	inFlight map[string]bool
+	done     map[string]bool
+	doneRing []string // done keys, oldest first, at most inboxDedupSize
+	appended int      // records written since the last compaction
+}
+
+// inboxKey identifies a message across channels.
+func inboxKey(msg Message) string {
+	return msg.Channel + ":" + msg.ID
+}
+
+// openInboxJournal opens the journal at path, returning the messages that
+// were pending when it was last written, oldest first. The file is
+// compacted to those messages and the remembered done keys.
+func openInboxJournal(path string) (*inboxJournal, []Message, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, nil, fmt.Errorf("create inbox journal directory: %w", err)
+	}
+	j := &inboxJournal{
+		path:     path,
+		pending:  make(map[string]Message),
+		inFlight: make(map[string]bool),
+		done:     make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("read inbox journal: %w", err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var rec inboxRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue // a torn last line from a crash mid-write
+		}
+		switch rec.Op {
+		case "add":
+			if rec.Msg == nil || j.done[rec.Key] {
+				continue
+			}
+			if _, ok := j.pending[rec.Key]; !ok {
+				j.order = append(j.order, rec.Key)
+			}
+			j.pending[rec.Key] = *rec.Msg
+		case "done":
+			delete(j.pending, rec.Key)
+			j.remember(rec.Key)
+		}
+	}
+
+	var replay []Message
+	for _, key := range j.order {
+		if msg, ok := j.pending[key]; ok {
+			replay = append(replay, msg)
+		}
+	}
+	if err := j.compactLocked(); err != nil {
+		return nil, nil, err
+	}
+	return j, replay, nil
+}
+
+// remember adds key to the bounded set of handled messages.
+func (j *inboxJournal) remember(key string) {
+	if j.done[key] {
+		return
+	}
+	j.done[key] = true
+	j.doneRing = append(j.doneRing, key)
+	if len(j.doneRing) > inboxDedupSize {
+		delete(j.done, j.doneRing[0])
+		j.doneRing = j.doneRing[1:]
+	}
+}
+
+// compactLocked rewrites the journal with only the pending messages and
+// the remembered done keys, then reopens it for appending.
+func (j *inboxJournal) compactLocked() error {
+	var buf bytes.Buffer
+	for _, key := range j.doneRing {
+		line, _ := json.Marshal(inboxRecord{Op: "done", Key: key})
+		buf.Write(append(line, '\n'))
+	}
+	var order []string
+	for _, key := range j.order {
+		msg, ok := j.pending[key]
+		if !ok {
+			continue
+		}
+		order = append(order, key)
+		line, err := json.Marshal(inboxRecord{Op: "add", Key: key, Msg: &msg})
+		if err != nil {
+			return fmt.Errorf("marshal inbox record: %w", err)
+		}
+		buf.Write(append(line, '\n'))
+	}
+	j.order = order
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("compact inbox journal: %w", err)
+	}
+	if j.f != nil {
+		_ = j.f.Close()
+		j.f = nil
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return fmt.Errorf("compact inbox journal: %w", err)
+	}
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open inbox journal: %w", err)
+	}
+	j.f = f
+	j.appended = 0
+	return nil
+}
+
+// writeLocked appends rec, syncing it to disk when sync is set.
+func (j *inboxJournal) writeLocked(rec inboxRecord, sync bool) error {
+	if j.f == nil {
+		return fmt.Errorf("inbox journal closed")
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal inbox record: %w", err)
+	}
+	if _, err := j.f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write inbox journal: %w", err)
+	}
+	j.appended++
+	if sync {
+		if err := j.f.Sync(); err != nil {
+			return fmt.Errorf("sync inbox journal: %w", err)
+		}
+	}
+	return nil
+}
+
+// add records msg before it is queued. Messages without an ID get one so
+// they can be settled and deduplicated; the updated message is returned.
+func (j *inboxJournal) add(msg Message) (Message, error) {
+	if msg.ID == "" {
+		msg.ID = fmt.Sprintf("inbox-%d", time.Now().UnixNano())
+	}
+	key := inboxKey(msg)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.done[key] {
+		return msg, nil // redelivered after handling; claim will drop it
+	}
+	if _, ok := j.pending[key]; !ok {
+		j.order = append(j.order, key)
+	}
+	j.pending[key] = msg
+	return msg, j.writeLocked(inboxRecord{Op: "add", Key: key, Msg: &msg}, true)
+}
+
+// claim marks msg as being handled. It returns false if msg was already
+// handled or is being handled, in which case the caller drops it.
+func (j *inboxJournal) claim(msg Message) bool {
+	key := inboxKey(msg)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.done[key] || j.inFlight[key] {
+		return false
+	}
+	j.inFlight[key] = true
+	return true
+}
+
+// settle records that msg has been handled or dropped, so it is not
+// replayed. Every inboxCompactEvery records the journal is rewritten.
+func (j *inboxJournal) settle(msg Message) error {
+	key := inboxKey(msg)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, ok := j.pending[key]; !ok {
+		return nil
+	}
+	if err := j.writeLocked(inboxRecord{Op: "done", Key: key}, false); err != nil {
+		return err
+	}
+	delete(j.pending, key)
+	delete(j.inFlight, key)
+	j.remember(key)
+	if j.appended >= inboxCompactEvery {
+		return j.compactLocked()
+	}
+	return nil
+}
+
+// close closes the journal file. Messages settled afterwards stay pending
+// and are replayed on the next start.
+func (j *inboxJournal) close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return nil
+	}
+	err := j.f.Close()
+	j.f = nil
+	return err
+}
+
+// openDurableInbox opens the journal under server.dataDir and returns the
+// messages it still holds from the last run.
+func (o *Orchestrator) openDurableInbox() ([]Message, error) {
+	j, replay, err := openInboxJournal(filepath.Join(o.cfg.Server.DataDir, inboxJournalFile))
+	if err != nil {
+		return nil, fmt.Errorf("open durable inbox: %w", err)
+	}
+	o.inboxJournal = j
+	return replay, nil
+}
+
+// replayInbox queues messages recovered from the journal. They are already
+// journaled, so they bypass enqueueMessage.
+func (o *Orchestrator) replayInbox(msgs []Message) {
+	if len(msgs) == 0 {
+		return
+	}
+	o.logger.Info("replaying durable inbox", "messages", len(msgs))
+	for _, msg := range msgs {
+		o.queueMessage(msg)
+	}
+}
+
+// settleInbox marks msg handled in the durable inbox, if there is one.
+func (o *Orchestrator) settleInbox(msg Message) {
+	if o.inboxJournal == nil {
+		return
+	}
+	if err := o.inboxJournal.settle(msg); err != nil {
+		o.logger.Error("inbox journal write failed", "channel", msg.Channel, "id", msg.ID, "error", err)
+	}
+}
@@ -0,0 +1,138 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func durableConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Server.DataDir = t.TempDir()
+	cfg.Server.DurableInbox = true
+	cfg.Evolution.Enabled = false
+	return cfg
+}
+
+func waitForSent(t *testing.T, ch *mockChannel, n int) {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for len(ch.getSent()) < n {
+		select {
+		case <-deadline:
+			t.Fatalf("got %d of %d responses", len(ch.getSent()), n)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestDurableInbox_ReplaysAfterCrash(t *testing.T) {
+	cfg := durableConfig(t)
+
+	// First run: messages are journaled and queued, then the process dies
+	// before routing picks them up.
+	o1 := New(cfg, testLogger())
+	if _, err := o1.openDurableInbox(); err != nil {
+		t.Fatalf("openDurableInbox: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		o1.enqueueMessage(Message{ID: fmt.Sprintf("m%d", i), Channel: "mock", From: "user", Content: fmt.Sprintf("hello %d", i)})
+	}
+
+	// Second run replays them, once each.
+	o2 := New(cfg, testLogger())
+	ch := newMockChannel("mock")
+	p := newMockProvider("mock")
+	o2.RegisterChannel(ch)
+	o2.RegisterProvider(p)
+	if err := o2.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitForSent(t, ch, 3)
+
+	// The same message redelivered by the channel is skipped.
+	ch.sendMessage(Message{ID: "m1", Channel: "mock", From: "user", Content: "hello 1"})
+	time.Sleep(100 * time.Millisecond)
+	if n := len(ch.getSent()); n != 3 {
+		t.Errorf("got %d responses, want 3", n)
+	}
+	if n := p.getCalls(); n != 3 {
+		t.Errorf("provider called %d times, want 3", n)
+	}
+	o2.Stop()
+
+	// Third run has nothing left to replay.
+	o3 := New(cfg, testLogger())
+	replay, err := o3.openDurableInbox()
+	if err != nil {
+		t.Fatalf("openDurableInbox: %v", err)
+	}
+	defer o3.inboxJournal.close()
+	if len(replay) != 0 {
+		t.Errorf("replayed %d messages after a clean run, want 0", len(replay))
+	}
+}
+
+func TestDurableInbox_Disabled(t *testing.T) {
+	cfg := durableConfig(t)
+	cfg.Server.DurableInbox = false
+	o := New(cfg, testLogger())
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer o.Stop()
+	if o.inboxJournal != nil {
+		t.Error("journal opened with durableInbox off")
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Server.DataDir, inboxJournalFile)); !os.IsNotExist(err) {
+		t.Errorf("journal file exists with durableInbox off: %v", err)
+	}
+}
+
+func TestInboxJournal_TornLineAndCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), inboxJournalFile)
+	j, _, err := openInboxJournal(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	a, _ := j.add(Message{ID: "a", Channel: "c", Content: "first"})
+	b, _ := j.add(Message{Channel: "c", Content: "no id"})
+	if b.ID == "" {
+		t.Fatal("add did not assign an ID")
+	}
+	if !j.claim(a) {
+		t.Fatal("claim(a) = false")
+	}
+	if j.claim(a) {
+		t.Error("second claim(a) = true while in flight")
+	}
+	if err := j.settle(a); err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+	j.close()
+
+	// A crash mid-write leaves a partial line at the end.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"op":"add","key":"c:x","msg":{"id":`)
+	f.Close()
+
+	j, replay, err := openInboxJournal(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer j.close()
+	if len(replay) != 1 || replay[0].ID != b.ID || replay[0].Content != "no id" {
+		t.Fatalf("replay = %+v, want only %q", replay, b.ID)
+	}
+	if j.claim(a) {
+		t.Error("claim(a) = true after restart; handled keys must survive compaction")
+	}
+	if again, _ := j.add(a); again.ID != a.ID || j.pending[inboxKey(a)].ID != "" {
+		t.Error("redelivered handled message was journaled as pending")
+	}
+}
@@ -0,0 +1,110 @@
+package orchestrator
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// thresholdEvolver is a skill evolver with fixed skill fitness that records
+// which skills were judged to need evolution.
+type thresholdEvolver struct {
+	*mockEvolution
+	mu      sync.Mutex
+	fitness map[string]float64
+	evolved map[string]bool
+}
+
+func newThresholdEvolver(fitness map[string]float64) *thresholdEvolver {
+	return &thresholdEvolver{mockEvolution: newMockEvolution(), fitness: fitness, evolved: make(map[string]bool)}
+}
+
+func (e *thresholdEvolver) EvaluateSkill(agentID, skillName string, metrics map[string]float64) (float64, error) {
+	return e.fitness[skillName], nil
+}
+
+func (e *thresholdEvolver) ShouldEvolveSkill(agentID, skillName string, minFitness float64, minSamples int) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	should := e.fitness[skillName] < minFitness
+	e.evolved[skillName] = should
+	return should, nil
+}
+
+func (e *thresholdEvolver) MutateSkill(agentID, skillName string, mutationRate float64) error {
+	return nil
+}
+
+func (e *thresholdEvolver) shouldHaveEvolved(skill string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.evolved[skill]
+}
+
+func TestMinFitnessFallsBackUpTheHierarchy(t *testing.T) {
+	cfg := testConfig()
+	o := New(cfg, testLogger())
+	genome := &config.Genome{Skills: map[string]config.SkillGenome{
+		"pinned": {Enabled: true, MinFitness: 0.9},
+		"plain":  {Enabled: true},
+	}}
+
+	if got := o.minFitness("trader", "plain", genome); got != defaultMinFitness {
+		t.Errorf("unconfigured threshold = %v, want default %v", got, defaultMinFitness)
+	}
+
+	cfg.Evolution.MinFitness = 0.5
+	cfg.Evolution.MinFitnessByType = map[string]float64{"trader": 0.7}
+	cfg.Evolution.MinFitnessBySkill = map[string]float64{"plain": 0.8, "pinned": 0.3}
+
+	tests := []struct {
+		agentType, skill string
+		want             float64
+	}{
+		{"trader", "pinned", 0.9}, // skill genome beats everything
+		{"trader", "plain", 0.8},  // then the per-skill setting
+		{"trader", "other", 0.7},  // then the agent type
+		{"monitor", "other", 0.5}, // then the global threshold
+		{"trader", "", 0.7},       // agent-level evolution skips skill settings
+	}
+	for _, tt := range tests {
+		if got := o.minFitness(tt.agentType, tt.skill, genome); got != tt.want {
+			t.Errorf("minFitness(%q, %q) = %v, want %v", tt.agentType, tt.skill, got, tt.want)
+		}
+	}
+}
+
+func TestSkillEvolvesAtItsOwnThreshold(t *testing.T) {
+	cfg := testConfig()
+	cfg.Evolution.MinSamplesForEval = 1
+	cfg.Evolution.MinFitnessBySkill = map[string]float64{"trading": 0.8}
+	o := New(cfg, testLogger())
+
+	// Both skills score 0.7: above the default threshold, below trading's
+	e := newThresholdEvolver(map[string]float64{"trading": 0.7, "chat": 0.7})
+	o.SetEvolutionEngine(e)
+
+	o.mu.Lock()
+	agent := o.initAgentLocked(config.AgentDef{
+		ID:   "trader-1",
+		Type: "trader",
+		Genome: &config.Genome{Skills: map[string]config.SkillGenome{
+			"trading": {Enabled: true},
+			"chat":    {Enabled: true},
+		}},
+	})
+	o.mu.Unlock()
+	agent.mu.Lock()
+	agent.Metrics.TotalActions = 10
+	agent.mu.Unlock()
+
+	o.evaluateAgent(agent)
+
+	if !e.shouldHaveEvolved("trading") {
+		t.Error("trading at 0.7 should evolve under its 0.8 threshold")
+	}
+	if e.shouldHaveEvolved("chat") {
+		t.Errorf("chat at 0.7 should not evolve under the default %v threshold", defaultMinFitness)
+	}
+}
@@ -0,0 +1,238 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// Moderation defaults used when the config leaves them empty.
+const (
+	defaultModerationReject      = "Sorry, I can't respond to that message."
+	defaultModerationReplacement = "[response withheld by content policy]"
+	moderationClassifierTimeout  = 10 * time.Second
+)
+
+// ModerationDirection says whether content is arriving or leaving.
+type ModerationDirection string
+
+const (
+	ModerateInbound  ModerationDirection = "inbound"
+	ModerateOutbound ModerationDirection = "outbound"
+)
+
+// ModerationInput is the content handed to a Moderator.
+type ModerationInput struct {
+	Direction ModerationDirection
+	Channel   string
+	AgentID   string
+	Content   string
+}
+
+// ModerationVerdict is a Moderator's decision. Reason is logged and
+// recorded with the rejected or replaced content.
+type ModerationVerdict struct {
+	Flagged bool
+	Reason  string
+}
+
+// Moderator screens message content against a policy. An error lets the
+// content through; moderation never blocks delivery on its own failure.
+type Moderator interface {
+	Moderate(ctx context.Context, in ModerationInput) (ModerationVerdict, error)
+}
+
+// PatternModerator flags content matching any of its regular expressions.
+type PatternModerator struct {
+	patterns []*regexp.Regexp
+}
+
+// NewPatternModerator compiles patterns, matched case-insensitively.
+func NewPatternModerator(patterns []string) (*PatternModerator, error) {
+	m := &PatternModerator{}
+	for _, p := range patterns {
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			return nil, fmt.Errorf("moderation pattern %q: %w", p, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// Moderate implements Moderator.
+func (m *PatternModerator) Moderate(_ context.Context, in ModerationInput) (ModerationVerdict, error) {
+	for _, re := range m.patterns {
+		if re.MatchString(in.Content) {
+			return ModerationVerdict{Flagged: true, Reason: "matched blocklist pattern " + re.String()[len("(?i)"):]}, nil
+		}
+	}
+	return ModerationVerdict{}, nil
+}
+
+// ClassifierModerator asks an LLM whether content breaks a policy.
+type ClassifierModerator struct {
+	provider func() (ModelProvider, error)
+	model    string // model ID without the provider prefix
+	policy   string
+}
+
+const classifierPrompt = `You are a content moderator. Decide whether the message below violates this policy:
+
+%s
+
+Reply with exactly "OK" if it does not. If it does, reply "FLAGGED: " followed by a short reason.`
+
+// Moderate implements Moderator.
+func (m *ClassifierModerator) Moderate(ctx context.Context, in ModerationInput) (ModerationVerdict, error) {
+	provider, err := m.provider()
+	if err != nil {
+		return ModerationVerdict{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, moderationClassifierTimeout)
+	defer cancel()
+
+	resp, err := provider.Chat(ctx, ChatRequest{
+		Model:        m.model,
+		SystemPrompt: fmt.Sprintf(classifierPrompt, m.policy),
+		Messages:     []ChatMessage{{Role: "user", Content: in.Content}},
+		MaxTokens:    64,
+	})
+	if err != nil {
+		return ModerationVerdict{}, fmt.Errorf("moderation classifier: %w", err)
+	}
+	answer := strings.TrimSpace(resp.Content)
+	if len(answer) >= len("FLAGGED") && strings.EqualFold(answer[:len("FLAGGED")], "FLAGGED") {
+		reason := strings.TrimSpace(strings.TrimPrefix(answer[len("FLAGGED"):], ":"))
+		if reason == "" {
+			reason = "classifier flagged content"
+		}
+		return ModerationVerdict{Flagged: true, Reason: reason}, nil
+	}
+	return ModerationVerdict{}, nil
+}
+
+// ModeratorChain runs moderators in order and returns the first flag.
+// Errors from one moderator do not stop the others.
+type ModeratorChain []Moderator
+
+// Moderate implements Moderator.
+func (c ModeratorChain) Moderate(ctx context.Context, in ModerationInput) (ModerationVerdict, error) {
+	var firstErr error
+	for _, m := range c {
+		v, err := m.Moderate(ctx, in)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if v.Flagged {
+			return v, nil
+		}
+	}
+	return ModerationVerdict{}, firstErr
+}
+
+// scopedModerator applies a moderator only to the directions and channels
+// the config selects.
+type scopedModerator struct {
+	next     Moderator
+	inbound  bool
+	outbound bool
+	channels []string
+}
+
+func (s *scopedModerator) Moderate(ctx context.Context, in ModerationInput) (ModerationVerdict, error) {
+	if in.Direction == ModerateInbound && !s.inbound || in.Direction == ModerateOutbound && !s.outbound {
+		return ModerationVerdict{}, nil
+	}
+	if len(s.channels) > 0 && !slices.Contains(s.channels, in.Channel) {
+		return ModerationVerdict{}, nil
+	}
+	return s.next.Moderate(ctx, in)
+}
+
+// newConfiguredModerator builds the moderator described by cfg.
+func (o *Orchestrator) newConfiguredModerator(cfg config.ModerationConfig) (Moderator, error) {
+	var chain ModeratorChain
+	if len(cfg.Blocklist) > 0 {
+		pm, err := NewPatternModerator(cfg.Blocklist)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, pm)
+	}
+	if model := cfg.ClassifierModel; model != "" {
+		modelID := model
+		if idx := strings.Index(model, "/"); idx > 0 {
+			modelID = model[idx+1:]
+		}
+		chain = append(chain, &ClassifierModerator{
+			provider: func() (ModelProvider, error) {
+				o.mu.RLock()
+				defer o.mu.RUnlock()
+				return o.providerFor(model)
+			},
+			model:  modelID,
+			policy: cfg.Policy,
+		})
+	}
+	return &scopedModerator{next: chain, inbound: cfg.Inbound, outbound: cfg.Outbound, channels: cfg.Channels}, nil
+}
+
+// SetModerator replaces the moderator built from the moderation config.
+// It is consulted for every inbound message and outbound response; nil
+// disables moderation.
+func (o *Orchestrator) SetModerator(m Moderator) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.moderator = m
+}
+
+// moderate runs the moderator over in. Moderator errors are logged and the
+// content allowed.
+func (o *Orchestrator) moderate(in ModerationInput) ModerationVerdict {
+	o.mu.RLock()
+	m := o.moderator
+	o.mu.RUnlock()
+	if m == nil {
+		return ModerationVerdict{}
+	}
+	v, err := m.Moderate(o.ctx, in)
+	if err != nil {
+		o.logger.Warn("moderation failed, allowing content",
+			"direction", in.Direction,
+			"channel", in.Channel,
+			"agent", in.AgentID,
+			"error", err)
+		return ModerationVerdict{}
+	}
+	if v.Flagged {
+		o.logger.Warn("content flagged by moderation",
+			"direction", in.Direction,
+			"channel", in.Channel,
+			"agent", in.AgentID,
+			"reason", v.Reason)
+	}
+	return v
+}
+
+func (o *Orchestrator) moderationRejectMessage() string {
+	if o.cfg != nil && o.cfg.Moderation.RejectMessage != "" {
+		return o.cfg.Moderation.RejectMessage
+	}
+	return defaultModerationReject
+}
+
+func (o *Orchestrator) moderationReplacement() string {
+	if o.cfg != nil && o.cfg.Moderation.Replacement != "" {
+		return o.cfg.Moderation.Replacement
+	}
+	return defaultModerationReplacement
+}
@@ -0,0 +1,158 @@
+package orchestrator
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func startModeratedOrchestrator(t *testing.T, cfg *config.Config) (*Orchestrator, *mockChannel, *mockProvider) {
+	t.Helper()
+	o := New(cfg, testLogger())
+	ch := newMockChannel("test")
+	o.RegisterChannel(ch)
+	p := newMockProvider("mock")
+	o.RegisterProvider(p)
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = o.Stop() })
+	return o, ch, p
+}
+
+func TestModeration_BlockedInboundRejected(t *testing.T) {
+	o, ch, p := startModeratedOrchestrator(t, testConfig())
+	pm, err := NewPatternModerator([]string{`\bforbidden\b`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.SetModerator(pm)
+	dl, err := NewDeadLetterLog(filepath.Join(t.TempDir(), "deadletter.jsonl"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.SetDeadLetterLog(dl)
+
+	o.handleMessage(Message{ID: "m1", Channel: "test", From: "user", To: "test-agent", Content: "tell me the FORBIDDEN thing"})
+	waitForSent(t, ch, 1)
+	sent := ch.getSent()
+
+	if n := p.getCalls(); n != 0 {
+		t.Errorf("provider called %d times for a rejected message", n)
+	}
+	if len(sent) != 1 || sent[0].Content != defaultModerationReject || sent[0].Metadata["moderation"] != "rejected" {
+		t.Fatalf("sent = %+v, want one rejection reply", sent)
+	}
+	if got := dl.Recent(DeadLetterModerated, 0); len(got) != 1 || got[0].MessageID != "m1" {
+		t.Errorf("dead letters = %+v, want m1 recorded as moderated", got)
+	}
+}
+
+// slowModerator holds every inbound check until release is closed.
+type slowModerator struct{ release chan struct{} }
+
+func (m slowModerator) Moderate(ctx context.Context, in ModerationInput) (ModerationVerdict, error) {
+	if in.Direction == ModerateInbound {
+		<-m.release
+	}
+	return ModerationVerdict{}, nil
+}
+
+func TestModeration_DoesNotBlockRouting(t *testing.T) {
+	o, ch, _ := startModeratedOrchestrator(t, testConfig())
+	mod := slowModerator{release: make(chan struct{})}
+	o.SetModerator(mod)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.handleMessage(Message{ID: "m1", Channel: "test", From: "user", To: "test-agent", Content: "hello"})
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handleMessage waited for inbound moderation")
+	}
+
+	close(mod.release)
+	waitForSent(t, ch, 1)
+}
+
+func TestModeration_FlaggedOutboundReplaced(t *testing.T) {
+	o, ch, p := startModeratedOrchestrator(t, testConfig())
+	pm, _ := NewPatternModerator([]string{"secret-token-[0-9]+"})
+	o.SetModerator(pm)
+	p.setResponse("mock-model-1", "here it is: secret-token-1234")
+
+	o.handleMessage(Message{ID: "m1", Channel: "test", From: "user", To: "test-agent", Content: "hello"})
+	waitForSent(t, ch, 1)
+	sent := ch.getSent()
+
+	if p.getCalls() != 1 {
+		t.Errorf("provider calls = %d, want 1", p.getCalls())
+	}
+	if sent[0].Content != defaultModerationReplacement || sent[0].Metadata["moderation"] != "replaced" {
+		t.Errorf("sent = %+v, want the replacement", sent[0])
+	}
+}
+
+func TestModeration_ConfiguredScopes(t *testing.T) {
+	cfg := testConfig()
+	cfg.Moderation = config.ModerationConfig{
+		Enabled:     true,
+		Inbound:     true,
+		Blocklist:   []string{"blocked"},
+		Replacement: "unused",
+	}
+	o, ch, p := startModeratedOrchestrator(t, cfg)
+	// Outbound moderation is off, so a flagged response goes out as is.
+	p.setResponse("mock-model-1", "this is blocked text")
+
+	o.handleMessage(Message{ID: "m1", Channel: "test", From: "user", To: "test-agent", Content: "hello"})
+	waitForSent(t, ch, 1)
+	sent := ch.getSent()
+	if sent[0].Content != "this is blocked text" {
+		t.Errorf("content = %q, want the unmoderated response", sent[0].Content)
+	}
+
+	// A moderator limited to another channel lets inbound content through.
+	m, err := o.newConfiguredModerator(config.ModerationConfig{Inbound: true, Blocklist: []string{"blocked"}, Channels: []string{"telegram"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, _ := m.Moderate(context.Background(), ModerationInput{Direction: ModerateInbound, Channel: "test", Content: "blocked"})
+	if v.Flagged {
+		t.Error("moderator flagged content on a channel it does not cover")
+	}
+}
+
+func TestClassifierModerator(t *testing.T) {
+	tests := []struct {
+		reply   string
+		flagged bool
+		reason  string
+	}{
+		{"OK", false, ""},
+		{"FLAGGED: harassment", true, "harassment"},
+		{"flagged", true, "classifier flagged content"},
+	}
+	for _, tt := range tests {
+		p := newMockProvider("mock")
+		p.setResponse("mock-model-2", tt.reply)
+		m := &ClassifierModerator{
+			provider: func() (ModelProvider, error) { return p, nil },
+			model:    "mock-model-2",
+			policy:   "no harassment",
+		}
+		v, err := m.Moderate(context.Background(), ModerationInput{Direction: ModerateInbound, Content: "hi"})
+		if err != nil {
+			t.Fatalf("%q: %v", tt.reply, err)
+		}
+		if v.Flagged != tt.flagged || v.Reason != tt.reason {
+			t.Errorf("%q: verdict = %+v, want flagged=%v reason=%q", tt.reply, v, tt.flagged, tt.reason)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package orchestrator
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// Notification defaults used when the notifications config leaves them zero.
+const (
+	defaultNotifyAttempts = 3
+	defaultNotifyBackoff  = time.Second
+	notifyTimeout         = 10 * time.Second
+	// notifyQueueSize is how many events each webhook holds while a
+	// delivery is being retried; further events are dead-lettered.
+	notifyQueueSize = 64
+)
+
+// notifySignatureHeader matches the webhook channel's outbound signature.
+const notifySignatureHeader = "X-EvoClaw-Signature"
+
+// webhookNotifier delivers events to one configured webhook.
+type webhookNotifier struct {
+	cfg      config.NotifyWebhookConfig
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+	queue    chan Event
+}
+
+// startNotifications subscribes every configured webhook to the event bus.
+func (o *Orchestrator) startNotifications() {
+	n := o.cfg.Notifications
+	if len(n.Webhooks) == 0 {
+		return
+	}
+	attempts := n.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultNotifyAttempts
+	}
+	backoff := time.Duration(n.RetryBackoffMs) * time.Millisecond
+	if backoff <= 0 {
+		backoff = defaultNotifyBackoff
+	}
+
+	notifiers := make([]*webhookNotifier, 0, len(n.Webhooks))
+	for _, wh := range n.Webhooks {
+		w := &webhookNotifier{
+			cfg:      wh,
+			client:   &http.Client{Timeout: notifyTimeout},
+			attempts: attempts,
+			backoff:  backoff,
+			queue:    make(chan Event, notifyQueueSize),
+		}
+		notifiers = append(notifiers, w)
+		go o.runNotifier(w)
+	}
+
+	events, unsubscribe := o.events.Subscribe(notifyQueueSize)
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-o.ctx.Done():
+				return
+			case ev := <-events:
+				for _, w := range notifiers {
+					if !eventMatches(w.cfg.Events, ev.Type) {
+						continue
+					}
+					select {
+					case w.queue <- ev:
+					default:
+						o.logger.Warn("webhook queue full, dropping event", "webhook", w.cfg.Name, "event", ev.Type)
+						o.deadLetterNotification(w, ev, "queue full")
+					}
+				}
+			}
+		}
+	}()
+	o.logger.Info("webhook notifications enabled", "webhooks", len(notifiers))
+}
+
+// runNotifier delivers w's queued events one at a time.
+func (o *Orchestrator) runNotifier(w *webhookNotifier) {
+	for {
+		select {
+		case <-o.ctx.Done():
+			return
+		case ev := <-w.queue:
+			o.notify(w, ev)
+		}
+	}
+}
+
+// notify posts ev to w, retrying network errors, 429s and 5xx responses
+// with exponential backoff. An event that cannot be delivered is recorded
+// in the dead-letter log.
+func (o *Orchestrator) notify(w *webhookNotifier, ev Event) {
+	body, err := notificationBody(w.cfg.Format, ev)
+	if err != nil {
+		o.logger.Error("webhook payload", "webhook", w.cfg.Name, "error", err)
+		return
+	}
+
+	backoff := w.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(o, body)
+		if err == nil {
+			return
+		}
+		o.logger.Warn("webhook delivery failed",
+			"webhook", w.cfg.Name,
+			"event", ev.Type,
+			"attempt", attempt,
+			"error", err,
+		)
+		if !retry || attempt >= w.attempts {
+			o.deadLetterNotification(w, ev, string(body))
+			return
+		}
+		if !o.sleep(backoff) {
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// post sends body once and reports whether a failure is worth retrying.
+func (w *webhookNotifier) post(o *Orchestrator, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(o.ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, config.RedactError(err, w.cfg.URL)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.cfg.Secret))
+		mac.Write(body)
+		req.Header.Set(notifySignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, config.RedactError(err, w.cfg.URL)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+// deadLetterNotification records an event that could not be delivered to
+// w. The webhook is identified by name, never by its URL.
+func (o *Orchestrator) deadLetterNotification(w *webhookNotifier, ev Event, content string) {
+	o.addDeadLetter(DeadLetter{
+		Reason:    DeadLetterWebhookFailed,
+		Direction: "outbound",
+		Channel:   "webhook",
+		To:        w.cfg.Name,
+		AgentID:   ev.AgentID,
+		Content:   content,
+	})
+}
+
+// eventMatches reports whether eventType is selected by patterns: exact
+// types, or "prefix.*" for a family. No patterns selects everything.
+func eventMatches(patterns []string, eventType string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if p == eventType || p == "*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && strings.HasSuffix(prefix, ".") && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationBody renders ev as JSON or as a Slack {"text": ...} message.
+func notificationBody(format string, ev Event) ([]byte, error) {
+	if format != "slack" {
+		return json.Marshal(ev)
+	}
+	return json.Marshal(map[string]string{"text": slackText(ev)})
+}
+
+// slackText summarises ev on one line, e.g.
+// "*agent.evolved* trader-1: fitness=0.52, type=mutation".
+func slackText(ev Event) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*", ev.Type)
+	if ev.AgentID != "" {
+		fmt.Fprintf(&b, " %s", ev.AgentID)
+	}
+	keys := make([]string, 0, len(ev.Data))
+	for k := range ev.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		sep := ", "
+		if i == 0 {
+			sep = ": "
+		}
+		fmt.Fprintf(&b, "%s%s=%v", sep, k, ev.Data[k])
+	}
+	return b.String()
+}
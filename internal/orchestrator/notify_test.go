@@ -0,0 +1,171 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/router"
+)
+
+// webhookRecorder is an httptest server that keeps every request body.
+type webhookRecorder struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies [][]byte
+}
+
+func newWebhookRecorder(t *testing.T, status func(n int) int) *webhookRecorder {
+	t.Helper()
+	rec := &webhookRecorder{}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		rec.bodies = append(rec.bodies, body)
+		n := len(rec.bodies)
+		rec.mu.Unlock()
+		w.WriteHeader(status(n))
+	}))
+	t.Cleanup(rec.Close)
+	return rec
+}
+
+func (r *webhookRecorder) waitFor(t *testing.T, n int) [][]byte {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		r.mu.Lock()
+		got := append([][]byte(nil), r.bodies...)
+		r.mu.Unlock()
+		if len(got) >= n {
+			return got
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("webhook got %d requests, want %d", len(got), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func startNotifyingOrchestrator(t *testing.T, n config.NotificationsConfig) *Orchestrator {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Notifications = n
+	o := New(cfg, testLogger())
+	o.RegisterProvider(newMockProvider("mock"))
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = o.Stop() })
+	return o
+}
+
+func ok200(int) int { return http.StatusOK }
+
+func TestNotifications_EvolutionEventPayload(t *testing.T) {
+	plain := newWebhookRecorder(t, ok200)
+	slack := newWebhookRecorder(t, ok200)
+	models := newWebhookRecorder(t, ok200)
+	o := startNotifyingOrchestrator(t, config.NotificationsConfig{Webhooks: []config.NotifyWebhookConfig{
+		{Name: "ops", URL: plain.URL, Events: []string{EventAgentEvolved}},
+		{Name: "slack", URL: slack.URL, Events: []string{"agent.*"}, Format: "slack"},
+		{Name: "models", URL: models.URL, Events: []string{"model.*"}},
+	}})
+
+	o.recordEvolution("test-agent", EvolutionEvent{Type: EvolutionMutation, Fitness: 0.52})
+
+	var got Event
+	if err := json.Unmarshal(plain.waitFor(t, 1)[0], &got); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if got.Type != EventAgentEvolved || got.AgentID != "test-agent" || got.Data["fitness"] != 0.52 || got.Data["type"] != EvolutionMutation {
+		t.Errorf("payload = %+v", got)
+	}
+
+	var msg map[string]string
+	_ = json.Unmarshal(slack.waitFor(t, 1)[0], &msg)
+	if want := "*agent.evolved* test-agent: fitness=0.52, manual=false, type=mutation"; msg["text"] != want {
+		t.Errorf("slack text = %q, want %q", msg["text"], want)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	models.mu.Lock()
+	defer models.mu.Unlock()
+	if n := len(models.bodies); n != 0 {
+		t.Errorf("model webhook got %d unrelated events", n)
+	}
+}
+
+func TestNotifications_RetriesThenDeadLetters(t *testing.T) {
+	// Fails twice, then accepts.
+	flaky := newWebhookRecorder(t, func(n int) int {
+		if n <= 2 {
+			return http.StatusBadGateway
+		}
+		return http.StatusOK
+	})
+	down := newWebhookRecorder(t, func(int) int { return http.StatusServiceUnavailable })
+	o := startNotifyingOrchestrator(t, config.NotificationsConfig{
+		MaxAttempts:    3,
+		RetryBackoffMs: 1,
+		Webhooks: []config.NotifyWebhookConfig{
+			{Name: "flaky", URL: flaky.URL},
+			{Name: "down", URL: down.URL},
+		},
+	})
+	dl, _ := NewDeadLetterLog("", 10)
+	o.SetDeadLetterLog(dl)
+
+	o.recordEvolution("test-agent", EvolutionEvent{Type: EvolutionMutation, Fitness: 0.4})
+
+	bodies := flaky.waitFor(t, 3)
+	if string(bodies[0]) != string(bodies[2]) {
+		t.Error("retry sent a different payload")
+	}
+	down.waitFor(t, 3)
+	deadline := time.Now().Add(2 * time.Second)
+	for len(dl.Recent(DeadLetterWebhookFailed, 0)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	got := dl.Recent(DeadLetterWebhookFailed, 0)
+	if len(got) != 1 || got[0].To != "down" || got[0].AgentID != "test-agent" {
+		t.Errorf("dead letters = %+v, want one for the down webhook", got)
+	}
+}
+
+func TestNotifications_HealthAndBudgetEvents(t *testing.T) {
+	cfg := testConfig()
+	cfg.Models.Health = config.ModelHealthConfig{PersistPath: t.TempDir() + "/health.json", FailureThreshold: 2}
+	o := New(cfg, testLogger())
+	if err := o.initHealthRegistry(); err != nil {
+		t.Fatal(err)
+	}
+	events, unsubscribe := o.events.Subscribe(8)
+	defer unsubscribe()
+
+	o.healthRegistry.RecordFailure("mock/mock-model-1", router.ErrTimeout)
+	o.healthRegistry.RecordFailure("mock/mock-model-1", router.ErrTimeout)
+	if ev := <-events; ev.Type != EventModelUnhealthy || ev.Data["model"] != "mock/mock-model-1" {
+		t.Errorf("event = %+v, want model.unhealthy", ev)
+	}
+
+	// Each threshold is announced once per period.
+	o.publishBudgetThreshold("test-agent", 0.85, 8.5, 10, 8.5, 0)
+	o.publishBudgetThreshold("test-agent", 0.9, 9, 10, 9, 0)
+	o.publishBudgetThreshold("test-agent", 1.1, 11, 10, 11, 0)
+	var levels []interface{}
+	for len(events) > 0 {
+		ev := <-events
+		if ev.Type == EventBudgetThreshold {
+			levels = append(levels, ev.Data["level"])
+		}
+	}
+	if len(levels) != 2 || levels[0] != "soft" || levels[1] != "exceeded" {
+		t.Errorf("budget levels = %v, want [soft exceeded]", levels)
+	}
+}
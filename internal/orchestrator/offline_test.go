@@ -0,0 +1,65 @@
+package orchestrator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func TestOfflineMode_NoNetworkSubsystemsStart(t *testing.T) {
+	// Every network-dependent subsystem points at this server; offline
+	// mode must keep all of them from contacting it.
+	var hits atomic.Int32
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		http.Error(w, "unexpected", http.StatusTeapot)
+	}))
+	defer remote.Close()
+
+	cfg := testConfig()
+	cfg.Server.DataDir = t.TempDir()
+	cfg.Server.Offline = true
+	cfg.OnChain = config.OnChainConfig{Enabled: true, RPCURL: remote.URL, ChainID: 97}
+	cfg.ClawChain = config.ClawChainConfig{AutoDiscover: true, NodeURL: remote.URL}
+	cfg.CloudSync = config.CloudSyncConfig{Enabled: true, DatabaseURL: remote.URL, AuthToken: "token"}
+	cfg.Memory.Enabled = true
+	cfg.Memory.Cold.DatabaseUrl = remote.URL
+	cfg.Tracing = config.TracingConfig{Enabled: true, Endpoint: remote.URL}
+
+	path := filepath.Join(t.TempDir(), "evoclaw.json")
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.OfflineDisabled()) == 0 {
+		t.Fatal("offline mode disabled nothing")
+	}
+
+	o := New(loaded, testLogger())
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = o.Stop() }()
+	time.Sleep(100 * time.Millisecond)
+
+	if o.chainRegistry != nil {
+		t.Error("on-chain integration initialized")
+	}
+	if o.cloudSync != nil {
+		t.Error("cloud sync initialized")
+	}
+	if o.memory != nil {
+		t.Error("tiered memory initialized")
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("%d outbound request(s) made in offline mode", n)
+	}
+}
@@ -2,6 +2,7 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"log/slog"
@@ -9,19 +10,23 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/clawinfra/evoclaw/internal/channels"
 	"github.com/clawinfra/evoclaw/internal/clawchain"
 	"github.com/clawinfra/evoclaw/internal/cloudsync"
 	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
 	"github.com/clawinfra/evoclaw/internal/governance"
-	"github.com/clawinfra/evoclaw/internal/rsi"
-	"github.com/clawinfra/evoclaw/internal/security"
 	"github.com/clawinfra/evoclaw/internal/memory"
 	"github.com/clawinfra/evoclaw/internal/onchain"
 	"github.com/clawinfra/evoclaw/internal/router"
+	"github.com/clawinfra/evoclaw/internal/rsi"
 	"github.com/clawinfra/evoclaw/internal/scheduler"
+	"github.com/clawinfra/evoclaw/internal/security"
+	"github.com/clawinfra/evoclaw/internal/storage"
+	"github.com/clawinfra/evoclaw/internal/tracing"
 	"github.com/clawinfra/evoclaw/internal/types"
 )
 
@@ -35,12 +40,15 @@ type Response = types.Response
 type AgentState struct {
 	ID           string
 	Def          config.AgentDef
-	Status       string // "running", "idle", "error", "evolving"
+	Status       string // "running", "idle", "cold", "error", "evolving"
 	StartedAt    time.Time
 	LastActive   time.Time
 	MessageCount int64
 	ErrorCount   int64
 	IsEdgeAgent  bool // true if agent connects via MQTT (runs remotely)
+	// Paused agents take no messages and are skipped by the scheduler and
+	// evolution; see PauseAgent.
+	Paused bool
 	// Performance metrics for evolution
 	Metrics AgentMetrics
 	mu      sync.RWMutex
@@ -54,6 +62,11 @@ type AgentMetrics struct {
 	AvgResponseMs     float64
 	TokensUsed        int64
 	CostUSD           float64
+	// CacheHits counts requests answered from the response cache
+	CacheHits int64
+	// ColdStarts counts messages that woke the agent from cold; see
+	// AgentDef.IdleTimeoutSec
+	ColdStarts int64
 	// Custom metrics per agent type
 	Custom map[string]float64
 }
@@ -85,20 +98,27 @@ type EvolutionEngine interface {
 	Mutate(agentID string, mutationRate float64) (interface{}, error)
 }
 
+// agentTyper is implemented by evolution engines that score agents by
+// type (e.g. with per-type fitness functions).
+type agentTyper interface {
+	SetAgentType(agentID, agentType string)
+}
+
 type ChatRequest struct {
 	Model        string
 	SystemPrompt string
 	Messages     []ChatMessage
 	MaxTokens    int
 	Temperature  float64
+	TopP         float64
 	Tools        []ToolSchema `json:"tools,omitempty"` // NEW: Tools for function calling
 }
 
 type ChatMessage struct {
-	Role       string      `json:"role"`
-	Content    string      `json:"content"`
-	ToolCalls  []ToolCall  `json:"tool_calls,omitempty"` // NEW: Tool calls from assistant
-	ToolCallID string      `json:"tool_call_id,omitempty"`  // NEW: Tool result message
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   // NEW: Tool calls from assistant
+	ToolCallID string     `json:"tool_call_id,omitempty"` // NEW: Tool result message
 }
 
 type ChatResponse struct {
@@ -108,6 +128,12 @@ type ChatResponse struct {
 	TokensOutput int
 	FinishReason string
 	ToolCalls    []ToolCall `json:"tool_calls,omitempty"` // NEW: Tool calls in response
+
+	// ReasoningContent is a reasoning model's thinking, kept out of
+	// Content. ReasoningTokens is the part of TokensOutput it used, when
+	// the provider reports it.
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+	ReasoningTokens  int    `json:"reasoning_tokens,omitempty"`
 }
 
 // Orchestrator is the core of EvoClaw
@@ -119,16 +145,18 @@ type AgentReporter interface {
 }
 
 type Orchestrator struct {
-	cfg       *config.Config
-	channels  map[string]Channel
-	providers map[string]ModelProvider
-	agents    map[string]*AgentState
-	inbox     chan Message
-	outbox    chan Response
-	logger    *slog.Logger
-	mu        sync.RWMutex
-	ctx       context.Context
-	cancel    context.CancelFunc
+	cfg      *config.Config
+	channels map[string]Channel
+	// startedChannels records which channels started successfully (readiness)
+	startedChannels map[string]bool
+	providers       map[string]ModelProvider
+	agents          map[string]*AgentState
+	inbox           chan Message
+	outbox          chan Response
+	logger          *slog.Logger
+	mu              sync.RWMutex
+	ctx             context.Context
+	cancel          context.CancelFunc
 	// Evolution engine (optional, set via SetEvolutionEngine)
 	evolution EvolutionEngine
 	// On-chain integration (BSC/opBNB)
@@ -147,25 +175,89 @@ type Orchestrator struct {
 	toolManager        *ToolManager
 	toolLoop           *ToolLoop
 	resultRegistry     map[string]chan *ToolResult
-	edgeResultRegistry map[string]chan map[string]interface{} // For edge agent prompt results
+	edgeResultRegistry map[string]chan map[string]interface{}   // For edge agent prompt results
+	edgeChunkRegistry  map[string]func(seq int, content string) // For streamed edge agent answers
 	resultMu           sync.RWMutex
+	// msgCancels cancels in-flight messages by ID (see CancelMessage)
+	msgCancelMu sync.Mutex
+	msgCancels  map[string]context.CancelFunc
+	// shadowMu serialises appends to the shadow comparison log
+	shadowMu sync.Mutex
 	// RSI loop for recursive self-improvement
 	rsiLoop *rsi.Loop
+	// Distills failed interactions into the skillbank (optional)
+	skillLearner *skillLearner
+	// Per-type metrics, default capabilities and fitness, keyed by AgentDef.Type
+	agentTypes map[string]AgentTypeBehavior
 	// MQTT channel for edge agent dispatch
 	mqttChannel *channels.MQTTChannel
+	// Rate limit for BroadcastCommand
+	broadcastMu          sync.Mutex
+	lastBroadcastCommand time.Time
+	// Write-ahead journal behind server.durableInbox (optional)
+	inboxJournal *inboxJournal
 	// Security policy for workspace sandboxing
 	securityPolicy *security.SecurityPolicy
 	reporter       AgentReporter
+	// Persistence backend selected by server.storage (optional)
+	storage storage.Store
+	// Event bus for presence and other dashboard notifications
+	events *EventBus
+	// Local append-only action log (optional)
+	actionLog *ActionLog
+	// Bounded log of dropped messages and responses (optional)
+	deadLetters *DeadLetterLog
+	// OpenTelemetry tracer (optional)
+	tracer *tracing.Tracer
+	// Last mutation per agent
+	lastEvolution evolutionEvents
+	// Safe mode freezes evolution, on-chain and cloud sync writes
+	safeMode atomic.Bool
+	// Backpressure counters for the inbox and outbox
+	inboxStats  queueCounters
+	outboxStats queueCounters
+	// Panics recovered from background goroutines, by goroutine kind
+	panicMu sync.Mutex
+	panics  map[string]int64
+	// Per-period LLM spend for cost budgets
+	costs costTracker
+	// Builds system prompts from genome, skills and memory (optional)
+	promptComposer *PromptComposer
+	// Answers exact repeats of low-temperature requests (optional)
+	responseCache *responseCache
+	// Screens inbound and outbound content (optional)
+	moderator Moderator
+	// Rewrites agent responses before delivery (optional)
+	postProcessor ResponseProcessor
+
+	// Shutdown ordering: intakeCtx stops the channel receivers and the
+	// router, outboxCtx the delivery pool. Both are cancelled by Stop
+	// before ctx, once in-flight processing has finished.
+	intakeCtx    context.Context
+	intakeCancel context.CancelFunc
+	outboxCtx    context.Context
+	outboxCancel context.CancelFunc
+	routing      sync.WaitGroup
+	outboxDone   chan struct{}
+	// In-flight processing goroutines started with goTracked
+	inflight   sync.WaitGroup
+	inflightMu sync.Mutex
+	inflightN  int
+	draining   bool
+	// How often agents are checked against their idle timeout; tests
+	// shorten it
+	idleCheckInterval time.Duration
 }
 
 // New creates a new Orchestrator
 func New(cfg *config.Config, logger *slog.Logger) *Orchestrator {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &Orchestrator{
+	o := &Orchestrator{
 		cfg:                cfg,
 		channels:           make(map[string]Channel),
 		providers:          make(map[string]ModelProvider),
 		agents:             make(map[string]*AgentState),
+		agentTypes:         make(map[string]AgentTypeBehavior),
 		inbox:              make(chan Message, 1000),
 		outbox:             make(chan Response, 1000),
 		logger:             logger,
@@ -173,7 +265,32 @@ func New(cfg *config.Config, logger *slog.Logger) *Orchestrator {
 		cancel:             cancel,
 		resultRegistry:     make(map[string]chan *ToolResult),
 		edgeResultRegistry: make(map[string]chan map[string]interface{}),
+		edgeChunkRegistry:  make(map[string]func(seq int, content string)),
+		events:             NewEventBus(),
+	}
+	o.intakeCtx, o.intakeCancel = context.WithCancel(ctx)
+	o.outboxCtx, o.outboxCancel = context.WithCancel(ctx)
+	if cfg != nil {
+		o.safeMode.Store(cfg.Server.SafeMode)
+		if cfg.Models.ResponseCache.Enabled {
+			o.responseCache = newResponseCache(cfg.Models.ResponseCache)
+		}
+		if cfg.Moderation.Enabled {
+			if m, err := o.newConfiguredModerator(cfg.Moderation); err != nil {
+				logger.Error("moderation disabled", "error", err)
+			} else {
+				o.moderator = m
+			}
+		}
+		if len(cfg.PostProcess) > 0 {
+			if p, err := newConfiguredPostProcessor(cfg.PostProcess); err != nil {
+				logger.Error("response post-processing disabled", "error", err)
+			} else {
+				o.postProcessor = p
+			}
+		}
 	}
+	return o
 }
 
 // RegisterChannel adds a messaging channel
@@ -209,9 +326,31 @@ func (o *Orchestrator) SetEvolutionEngine(e EvolutionEngine) {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 	o.evolution = e
+	if t, ok := e.(agentTyper); ok {
+		for _, agent := range o.agents {
+			t.SetAgentType(agent.ID, agent.Def.Type)
+		}
+	}
+	o.registerTypeFitnessLocked()
 	o.logger.Info("evolution engine registered")
 }
 
+// SetStorage sets the persistence backend. When server.storage is "sqlite"
+// the RSI skillbank is kept in it as well; the file backend keeps the
+// skillbank's own JSONL file.
+func (o *Orchestrator) SetStorage(s storage.Store) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.storage = s
+}
+
+// GetStorage returns the persistence backend, or nil if none was set.
+func (o *Orchestrator) GetStorage() storage.Store {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.storage
+}
+
 // SetSecurityPolicy sets the workspace sandboxing and security policy.
 func (o *Orchestrator) SetSecurityPolicy(p *security.SecurityPolicy) {
 	o.mu.Lock()
@@ -228,6 +367,26 @@ func (o *Orchestrator) Start() error {
 		"providers", len(o.providers),
 	)
 
+	if err := o.checkCapabilities(); err != nil {
+		return err
+	}
+
+	// Open the durable inbox before any channel can deliver
+	var replay []Message
+	if o.cfg.Server.DurableInbox {
+		var err error
+		if replay, err = o.openDurableInbox(); err != nil {
+			return err
+		}
+	}
+
+	// Restore this period's spend before any message is charged
+	if path := o.budgetStatePath(); path != "" {
+		if err := o.costs.load(path); err != nil {
+			o.logger.Warn("budget state not restored; spend starts at zero", "error", err)
+		}
+	}
+
 	// Start all channels
 	for name, ch := range o.channels {
 		o.logger.Info("starting channel", "name", name)
@@ -235,55 +394,69 @@ func (o *Orchestrator) Start() error {
 		// Wire up MQTT result callback and store reference
 		if mqttCh, ok := ch.(*channels.MQTTChannel); ok {
 			mqttCh.SetResultCallback(o.DeliverToolResult)
+			mqttCh.SetChunkCallback(o.DeliverEdgeChunk)
+			mqttCh.SetPresenceCallback(o.publishPresence)
 			o.mqttChannel = mqttCh // Store reference for edge dispatch
 			o.logger.Info("mqtt result callback wired", "channel", name)
 		}
 
+		if fr, ok := ch.(feedbackReporter); ok {
+			fr.SetFeedbackCallback(o.submitFeedback)
+		}
+
 		if err := ch.Start(o.ctx); err != nil {
 			return fmt.Errorf("start channel %s: %w", name, err)
 		}
+		o.mu.Lock()
+		if o.startedChannels == nil {
+			o.startedChannels = make(map[string]bool)
+		}
+		o.startedChannels[name] = true
+		o.mu.Unlock()
 	}
 
 	// Initialize agents from config
+	o.mu.Lock()
 	for _, def := range o.cfg.Agents {
-		o.agents[def.ID] = &AgentState{
-			ID:          def.ID,
-			Def:         def,
-			Status:      "idle",
-			StartedAt:   time.Now(),
-			IsEdgeAgent: def.Remote, // Mark as edge agent if configured as remote
-			Metrics: AgentMetrics{
-				Custom: make(map[string]float64),
-			},
-		}
-		if def.Remote {
-			o.logger.Info("agent initialized", "id", def.ID, "type", def.Type, "mode", "edge")
-		} else {
-			o.logger.Info("agent initialized", "id", def.ID, "type", def.Type, "mode", "local")
-		}
-
-		// Initialize tool manager with first agent's capabilities
-		if o.toolManager == nil && len(def.Capabilities) > 0 {
-			o.toolManager = NewToolManager("", def.Capabilities, o.logger)
-			o.toolLoop = NewToolLoop(o, o.toolManager, WithRSILogger(NewDefaultRSILogger()))
-			o.logger.Info("tool manager initialized", "capabilities", def.Capabilities)
-		}
+		o.initAgentLocked(def)
 	}
+	o.mu.Unlock()
 
 	// Start message routing
-	go o.routeIncoming()
-	go o.routeOutgoing()
+	o.routing.Add(1 + len(o.channels))
+	go func() {
+		defer o.routing.Done()
+		o.routeIncoming()
+	}()
+	o.outboxDone = make(chan struct{})
+	go func() {
+		defer close(o.outboxDone)
+		o.routeOutgoing()
+	}()
 
 	// Start channel receivers
 	for _, ch := range o.channels {
-		go o.receiveFrom(ch)
+		go func(ch Channel) {
+			defer o.routing.Done()
+			o.receiveFrom(ch)
+		}(ch)
 	}
 
-	// Start evolution engine if enabled
-	if o.cfg.Evolution.Enabled {
-		go o.evolutionLoop()
+	// Run the evolution loop even while evolution is disabled: it checks
+	// evolution.enabled on every tick, so enabling it at runtime works
+	go o.evolutionLoop()
+
+	// Load each local agent's model before its first message
+	if o.cfg.Server.WarmupOnStart {
+		o.warmupAgents()
 	}
 
+	// Move agents past their idle timeout to cold
+	go o.idleLoop()
+
+	// Post events to the configured webhooks
+	o.startNotifications()
+
 	// Initialize on-chain integration if enabled
 	if o.cfg.OnChain.Enabled {
 		if err := o.initOnChain(); err != nil {
@@ -316,6 +489,12 @@ func (o *Orchestrator) Start() error {
 	// Initialize RSI loop
 	o.initRSI()
 
+	// Compose system prompts from genome style, skills and memories
+	o.initPromptComposer()
+
+	// Learn skills from repeated failures
+	o.initSkillLearning()
+
 	// Initialize scheduler if enabled
 	if o.cfg.Scheduler.Enabled {
 		if err := o.initScheduler(); err != nil {
@@ -328,6 +507,12 @@ func (o *Orchestrator) Start() error {
 		o.logger.Warn("health registry failed to initialize (non-fatal)", "error", err)
 	}
 
+	// Save spend with the health state
+	go o.persistStateLoop()
+
+	// Replay messages left in the durable inbox by the last run
+	o.replayInbox(replay)
+
 	o.logger.Info("EvoClaw orchestrator running")
 	return nil
 }
@@ -339,11 +524,15 @@ func (o *Orchestrator) initCloudSync() error {
 		return fmt.Errorf("init cloud sync: %w", err)
 	}
 
-	// Initialize schema (creates tables if not exist)
+	// Initialize schema (creates tables if not exist). An unreachable
+	// Turso is not fatal: the engine retries on reconnect and queues writes.
 	if err := mgr.InitSchema(o.ctx); err != nil {
-		return fmt.Errorf("init cloud sync schema: %w", err)
+		o.logger.Warn("cloud sync schema not created, will retry on reconnect", "error", err)
 	}
 
+	mgr.SetPaused(o.SafeMode())
+	mgr.SetSnapshotSource(o.cloudSnapshots)
+
 	// Start background sync (heartbeat + periodic warm/full sync)
 	if err := mgr.Start(o.ctx); err != nil {
 		return fmt.Errorf("start cloud sync: %w", err)
@@ -363,6 +552,40 @@ func (o *Orchestrator) GetCloudSync() *cloudsync.Manager {
 	return o.cloudSync
 }
 
+// CloudSyncStatus reports the cloud sync connection, or nil when cloud sync
+// is not configured.
+func (o *Orchestrator) CloudSyncStatus() *cloudsync.Status {
+	if o.cloudSync == nil {
+		return nil
+	}
+	st := o.cloudSync.Status()
+	return &st
+}
+
+// cloudSyncActive reports whether cloud sync writes should be made.
+func (o *Orchestrator) cloudSyncActive() bool {
+	return o.cloudSync != nil && o.cloudSync.IsEnabled() && !o.SafeMode()
+}
+
+// reportOnChain logs action on-chain when a chain is connected. It is
+// skipped in safe mode.
+func (o *Orchestrator) reportOnChain(msg Message, action onchain.Action) {
+	if o.chainRegistry == nil {
+		return
+	}
+	if o.SafeMode() {
+		o.logger.Debug("on-chain report skipped in safe mode", "agent", action.AgentDID, "action", action.ActionType)
+		return
+	}
+	reporter := onchain.NewActionReporter(o.chainRegistry, o.logger)
+	ctx, span := o.Tracer().Start(o.traceContext(msg), "chain.report")
+	defer span.End()
+	if err := reporter.ExecuteAndReport(ctx, action); err != nil {
+		span.RecordError(err)
+		o.logger.Debug("on-chain action log failed (non-fatal)", "error", err)
+	}
+}
+
 // GetMemory returns the tiered memory manager for external access
 func (o *Orchestrator) GetMemory() *memory.Manager {
 	return o.memory
@@ -395,9 +618,7 @@ func (o *Orchestrator) initHealthRegistry() error {
 	}
 
 	o.healthRegistry = hr
-
-	// Start periodic persistence (every 5 minutes)
-	go o.persistHealthLoop()
+	hr.OnStateChange(o.publishModelHealth)
 
 	o.logger.Info("health registry initialized",
 		"persist_path", healthCfg.PersistPath,
@@ -408,8 +629,8 @@ func (o *Orchestrator) initHealthRegistry() error {
 	return nil
 }
 
-// persistHealthLoop periodically persists health state
-func (o *Orchestrator) persistHealthLoop() {
+// persistStateLoop periodically persists health state and budget spend
+func (o *Orchestrator) persistStateLoop() {
 	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
 
@@ -417,14 +638,19 @@ func (o *Orchestrator) persistHealthLoop() {
 		select {
 		case <-o.ctx.Done():
 			// Final persist on shutdown
-			if err := o.healthRegistry.Persist(); err != nil {
-				o.logger.Error("failed to persist health state on shutdown", "error", err)
+			if o.healthRegistry != nil {
+				if err := o.healthRegistry.Persist(); err != nil {
+					o.logger.Error("failed to persist health state on shutdown", "error", err)
+				}
 			}
 			return
 		case <-ticker.C:
-			if err := o.healthRegistry.Persist(); err != nil {
-				o.logger.Error("failed to persist health state", "error", err)
+			if o.healthRegistry != nil {
+				if err := o.healthRegistry.Persist(); err != nil {
+					o.logger.Error("failed to persist health state", "error", err)
+				}
 			}
+			o.persistBudget()
 		}
 	}
 }
@@ -469,6 +695,7 @@ func (o *Orchestrator) initMemory() error {
 	if o.cfg.Memory.Warm.MaxSizeKb > 0 {
 		memCfg.WarmMaxKB = o.cfg.Memory.Warm.MaxSizeKb
 	}
+	memCfg.WarmForceConsolidateKB = o.cfg.Memory.Warm.ForceConsolidateKb
 	if o.cfg.Memory.Warm.RetentionDays > 0 {
 		memCfg.WarmRetentionDays = o.cfg.Memory.Warm.RetentionDays
 	}
@@ -478,6 +705,7 @@ func (o *Orchestrator) initMemory() error {
 	if o.cfg.Memory.Distillation.Aggression > 0 {
 		memCfg.DistillationAggression = o.cfg.Memory.Distillation.Aggression
 	}
+	o.applyDistillationConfig(&memCfg)
 
 	mgr, err := memory.NewManager(memCfg, o.logger)
 	if err != nil {
@@ -496,32 +724,12 @@ func (o *Orchestrator) initMemory() error {
 		llmModel = o.cfg.Memory.Distillation.Model
 	}
 
-	mgr.SetLLMFunc(func(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
-		provider := o.findProvider(llmModel)
-		if provider == nil {
-			return "", fmt.Errorf("no LLM provider available for model %s", llmModel)
-		}
-		// Extract just the model ID (after the /) for the API request
-		modelID := llmModel
-		if idx := strings.Index(llmModel, "/"); idx > 0 {
-			modelID = llmModel[idx+1:]
-		}
-		resp, err := provider.Chat(ctx, ChatRequest{
-			Model:        modelID,
-			SystemPrompt: systemPrompt,
-			Messages:     []ChatMessage{{Role: "user", Content: userPrompt}},
-			MaxTokens:    512,
-			Temperature:  0.3,
-		})
-		if err != nil {
-			return "", err
-		}
-		return resp.Content, nil
-	}, llmModel)
+	mgr.SetLLMFunc(o.distillationLLMFunc(llmModel), llmModel)
 
 	o.logger.Info("tiered memory system initialized",
 		"agent", memCfg.AgentID,
 		"warm_max_kb", memCfg.WarmMaxKB,
+		"warm_force_consolidate_kb", memCfg.WarmForceConsolidateKB,
 		"half_life_days", memCfg.HalfLifeDays,
 		"llm_model", llmModel,
 	)
@@ -552,6 +760,9 @@ func (o *Orchestrator) initGovernance() error {
 func (o *Orchestrator) initRSI() {
 	cfg := rsi.DefaultConfig()
 	cfg.DataDir = filepath.Join(o.cfg.Server.DataDir, "rsi")
+	if o.cfg.Server.Storage == storage.BackendSQLite {
+		cfg.Storage = o.storage
+	}
 
 	o.rsiLoop = rsi.NewLoop(cfg, o.logger)
 	go o.rsiLoop.Start(o.ctx)
@@ -593,6 +804,9 @@ func (o *Orchestrator) initScheduler() error {
 				URL:     jobCfg.Action.URL,
 				Method:  jobCfg.Action.Method,
 				Headers: jobCfg.Action.Headers,
+
+				TimeoutMs:    jobCfg.Action.TimeoutMs,
+				ExpectStatus: jobCfg.Action.ExpectStatus,
 			},
 			Enabled: jobCfg.Enabled,
 		}
@@ -614,9 +828,15 @@ func (o *Orchestrator) initScheduler() error {
 // ExecuteAgent implements scheduler.Executor interface
 func (o *Orchestrator) ExecuteAgent(ctx context.Context, agentID, message string) error {
 	// Find agent
-	_, exists := o.agents[agentID]
+	o.mu.RLock()
+	agent, exists := o.agents[agentID]
+	o.mu.RUnlock()
 	if !exists {
-		return fmt.Errorf("agent not found: %s", agentID)
+		return agentNotFound(agentID)
+	}
+	if agent.isPaused() {
+		o.logger.Info("agent paused, skipping scheduled message", "agent", agentID)
+		return nil
 	}
 
 	// Create message
@@ -744,14 +964,19 @@ func (o *Orchestrator) initClawChainDiscovery() {
 // Stop gracefully shuts down the orchestrator
 func (o *Orchestrator) Stop() error {
 	o.logger.Info("stopping EvoClaw orchestrator")
+
+	// Let in-flight messages finish and their responses go out before
+	// cancelling the subsystems they use
+	o.drain()
 	o.cancel()
 
-	// Persist health state before shutdown
+	// Persist health state and budget spend before shutdown
 	if o.healthRegistry != nil {
 		if err := o.healthRegistry.Persist(); err != nil {
 			o.logger.Error("error persisting health state", "error", err)
 		}
 	}
+	o.persistBudget()
 
 	// Stop tiered memory (flushes consolidation)
 	if o.memory != nil {
@@ -775,7 +1000,101 @@ func (o *Orchestrator) Stop() error {
 			o.logger.Error("error stopping channel", "name", name, "error", err)
 		}
 	}
+	o.mu.Lock()
+	o.startedChannels = nil
+	o.mu.Unlock()
+
+	// Close the durable inbox; anything still in flight is replayed on
+	// the next start
+	if o.inboxJournal != nil {
+		if err := o.inboxJournal.close(); err != nil {
+			o.logger.Error("error closing inbox journal", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// initAgentLocked creates the runtime state for an agent definition.
+// Caller must hold o.mu.
+func (o *Orchestrator) initAgentLocked(def config.AgentDef) *AgentState {
+	if o.agents == nil {
+		o.agents = make(map[string]*AgentState)
+	}
+	def = o.withTypeDefaultsLocked(def)
+	agent := &AgentState{
+		ID:          def.ID,
+		Def:         def,
+		Status:      "idle",
+		StartedAt:   time.Now(),
+		IsEdgeAgent: def.Remote, // Mark as edge agent if configured as remote
+		Metrics:     o.newAgentMetricsLocked(def.Type),
+	}
+
+	// Initialize the shared tool manager once an agent declares capabilities,
+	// before the agent is published so its first message sees the tool loop.
+	// The tool loop scopes tools to each agent's own capabilities. Readers
+	// outside o.mu take o.mu.RLock to load toolManager and toolLoop.
+	if o.logger != nil && o.toolManager == nil && len(def.Capabilities) > 0 {
+		o.toolManager = NewToolManager("", nil, o.logger)
+		o.toolLoop = NewToolLoop(o, o.toolManager,
+			WithRSILogger(NewDefaultRSILogger()),
+			WithMaxIterations(o.cfg.ToolLoop.MaxIterations),
+			WithBudget(time.Duration(o.cfg.ToolLoop.BudgetSec)*time.Second))
+		o.logger.Info("tool manager initialized")
+	}
+
+	o.agents[def.ID] = agent
+	if t, ok := o.evolution.(agentTyper); ok {
+		t.SetAgentType(def.ID, def.Type)
+	}
+
+	if o.logger == nil {
+		return agent
+	}
+	if def.Remote {
+		o.logger.Info("agent initialized", "id", def.ID, "type", def.Type, "mode", "edge")
+	} else {
+		o.logger.Info("agent initialized", "id", def.ID, "type", def.Type, "mode", "local")
+	}
+	return agent
+}
+
+// AddAgent registers a new agent at runtime. It fails if an agent with
+// the same ID is already running.
+func (o *Orchestrator) AddAgent(def config.AgentDef) error {
+	if def.ID == "" {
+		return fmt.Errorf("agent id is required")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, exists := o.agents[def.ID]; exists {
+		return fmt.Errorf("agent already exists: %s", def.ID)
+	}
+	o.initAgentLocked(def)
+	return nil
+}
+
+// RemoveAgent unregisters an agent at runtime. New messages stop routing to
+// it immediately; a message already being processed keeps its own reference
+// to the agent state and completes normally.
+func (o *Orchestrator) RemoveAgent(id string) error {
+	o.mu.Lock()
+	agent, ok := o.agents[id]
+	if ok {
+		delete(o.agents, id)
+	}
+	o.mu.Unlock()
+
+	if !ok {
+		return agentNotFound(id)
+	}
 
+	if o.logger != nil {
+		o.logger.Info("agent removed", "id", id, "type", agent.Def.Type)
+	}
 	return nil
 }
 
@@ -783,11 +1102,11 @@ func (o *Orchestrator) Stop() error {
 func (o *Orchestrator) receiveFrom(ch Channel) {
 	for {
 		select {
-		case <-o.ctx.Done():
+		case <-o.intakeCtx.Done():
 			return
 		case msg := <-ch.Receive():
 			msg.Channel = ch.Name()
-			o.inbox <- msg
+			o.enqueueMessage(msg)
 		}
 	}
 }
@@ -796,42 +1115,83 @@ func (o *Orchestrator) receiveFrom(ch Channel) {
 func (o *Orchestrator) routeIncoming() {
 	for {
 		select {
-		case <-o.ctx.Done():
+		case <-o.intakeCtx.Done():
 			return
 		case msg := <-o.inbox:
+			if o.inboxJournal != nil && !o.inboxJournal.claim(msg) {
+				o.logger.Debug("duplicate message skipped", "channel", msg.Channel, "id", msg.ID)
+				continue
+			}
 			o.handleMessage(msg)
 		}
 	}
 }
 
-// routeOutgoing sends responses back through channels
-func (o *Orchestrator) routeOutgoing() {
-	for {
-		select {
-		case <-o.ctx.Done():
-			return
-		case resp := <-o.outbox:
-			o.mu.RLock()
-			ch, ok := o.channels[resp.Channel]
-			o.mu.RUnlock()
+// broadcastTarget is implemented by channels that can display a message
+// nobody asked for, such as the TUI. Request/response channels (HTTP,
+// WebSocket) and agent transports (MQTT) do not implement it.
+type broadcastTarget interface {
+	AcceptsBroadcast() bool
+}
 
+// Broadcast sends content to every channel that accepts broadcasts, or to
+// the named channels when any are given. It is meant for system
+// notifications and scheduled nudges that have no originating message.
+// Delivery continues past failures; the returned error joins them all.
+func (o *Orchestrator) Broadcast(ctx context.Context, content string, channels ...string) error {
+	o.mu.RLock()
+	var targets []Channel
+	var errs []error
+	if len(channels) == 0 {
+		for _, ch := range o.channels {
+			if bt, ok := ch.(broadcastTarget); ok && bt.AcceptsBroadcast() {
+				targets = append(targets, ch)
+			}
+		}
+	} else {
+		for _, name := range channels {
+			ch, ok := o.channels[name]
 			if !ok {
-				o.logger.Error("unknown channel for response", "channel", resp.Channel)
+				errs = append(errs, fmt.Errorf("unknown channel %q", name))
 				continue
 			}
+			targets = append(targets, ch)
+		}
+	}
+	o.mu.RUnlock()
 
-			if err := ch.Send(o.ctx, resp); err != nil {
-				o.logger.Error("error sending response",
-					"channel", resp.Channel,
-					"error", err,
-				)
-			}
+	if len(targets) == 0 && len(errs) == 0 {
+		return fmt.Errorf("no channel accepts broadcasts")
+	}
+
+	id := fmt.Sprintf("broadcast-%d", time.Now().UnixNano())
+	for _, ch := range targets {
+		resp := Response{
+			AgentID:   "system",
+			Content:   content,
+			Channel:   ch.Name(),
+			MessageID: id,
+			Metadata:  map[string]string{"broadcast": "true"},
+		}
+		if err := ch.Send(ctx, resp); err != nil {
+			o.logger.Error("broadcast failed", "channel", ch.Name(), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name(), err))
 		}
 	}
+	return errors.Join(errs...)
 }
 
 // handleMessage routes a message to the appropriate agent
 func (o *Orchestrator) handleMessage(msg Message) {
+	// Settle the message in the durable inbox once it has been handled or
+	// dropped; processing settles it itself when it finishes.
+	async := false
+	defer func(orig Message) {
+		if !async {
+			o.settleInbox(orig)
+		}
+	}(msg)
+
 	o.logger.Info("incoming message",
 		"channel", msg.Channel,
 		"from", msg.From,
@@ -841,13 +1201,26 @@ func (o *Orchestrator) handleMessage(msg Message) {
 	// Skip empty messages (e.g., heartbeats, status updates)
 	if strings.TrimSpace(msg.Content) == "" {
 		o.logger.Debug("skipping empty message", "from", msg.From, "channel", msg.Channel)
+		o.deadLetterMessage(DeadLetterEmptyContent, "", msg)
 		return
 	}
 
+	// The message span covers routing and processing; it continues an
+	// upstream trace if the channel passed one in the metadata.
+	ctx, span := o.Tracer().Start(o.traceContext(msg), "message")
+	span.SetAttr("messaging.channel", msg.Channel)
+	span.SetAttr("messaging.from", msg.From)
+
 	// Determine which agent should handle this
+	_, selSpan := o.Tracer().Start(ctx, "select_agent")
 	agentID := o.selectAgent(msg)
+	selSpan.SetAttr("agent.id", agentID)
+	selSpan.End()
 	if agentID == "" {
 		o.logger.Warn("no agent selected for message", "from", msg.From)
+		o.deadLetterMessage(DeadLetterNoAgent, "", msg)
+		span.SetAttr("dropped", DeadLetterNoAgent)
+		span.End()
 		return
 	}
 
@@ -857,20 +1230,88 @@ func (o *Orchestrator) handleMessage(msg Message) {
 
 	if !ok {
 		o.logger.Error("agent not found", "id", agentID)
+		o.deadLetterMessage(DeadLetterAgentNotFound, agentID, msg)
+		span.SetAttr("dropped", DeadLetterAgentNotFound)
+		span.End()
 		return
 	}
 
-	// Select the right model based on task complexity and health
-	model := o.selectModel(msg, agent)
+	if agent.isPaused() {
+		o.logger.Info("agent paused, refusing message", "agent", agentID, "from", msg.From)
+		o.enqueueResponse(Response{
+			AgentID:   agentID,
+			Content:   pausedReply(agentID),
+			Channel:   msg.Channel,
+			To:        msg.From,
+			ReplyTo:   msg.ID,
+			MessageID: msg.ID,
+			Metadata:  map[string]string{"agent_paused": "true"},
+		})
+		span.SetAttr("dropped", "agent_paused")
+		span.End()
+		return
+	}
+
+	// Moderation can call out to a classifier, so it runs with the rest of
+	// the processing instead of holding up routing of the next message.
+	orig := msg
+	async = true
+	o.goTracked("process", func() {
+		defer o.settleInbox(orig)
+		defer span.End()
+
+		if v := o.moderate(ModerationInput{
+			Direction: ModerateInbound,
+			Channel:   msg.Channel,
+			AgentID:   agentID,
+			Content:   msg.Content,
+		}); v.Flagged {
+			o.deadLetterMessage(DeadLetterModerated, agentID, msg)
+			o.enqueueResponse(Response{
+				AgentID:   agentID,
+				Content:   o.moderationRejectMessage(),
+				Channel:   msg.Channel,
+				To:        msg.From,
+				ReplyTo:   msg.ID,
+				MessageID: msg.ID,
+				Metadata:  map[string]string{"moderation": "rejected", "moderation_reason": v.Reason},
+			})
+			span.SetAttr("dropped", DeadLetterModerated)
+			return
+		}
+
+		// Select the right model based on task complexity and health
+		model := o.selectModel(msg, agent)
+		span.SetAttr("agent.id", agentID)
+		span.SetAttr("llm.model", model)
+
+		if err := o.checkPinnedModel(agent, model); err != nil {
+			o.logger.Warn("pinned model unhealthy, refusing message", "agent", agentID, "model", model)
+			o.enqueueResponse(Response{
+				AgentID:   agentID,
+				Content:   pinnedModelReply(agentID),
+				Channel:   msg.Channel,
+				To:        msg.From,
+				ReplyTo:   msg.ID,
+				MessageID: msg.ID,
+				Metadata:  map[string]string{"model_unavailable": model},
+			})
+			span.SetAttr("dropped", "pinned_model_unhealthy")
+			return
+		}
 
-	// Process with LLM
-	go o.processWithAgent(agent, msg, model)
+		// Process with LLM
+		o.processWithAgent(agent, withTraceparent(ctx, msg), model)
+	})
 }
 
 // selectAgent picks the best agent for a message using hash-based routing
 // for session affinity (same sender → same agent) and natural load balancing.
 // If msg.To is set and matches a known agent, that agent is used directly.
 func (o *Orchestrator) selectAgent(msg Message) string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
 	if len(o.agents) == 0 {
 		return ""
 	}
@@ -905,21 +1346,38 @@ func (o *Orchestrator) selectAgent(msg Message) string {
 
 // selectModel picks the right model based on task complexity and health
 func (o *Orchestrator) selectModel(msg Message, agent *AgentState) string {
-	// Start with agent's preferred model
-	preferred := agent.Def.Model
-	if preferred == "" {
-		preferred = o.cfg.Models.Routing.Complex
+	// Start with the agent's (or its evolved strategy's) preferred model
+	preferred := o.preferredModel(agent)
+	if agent.pinnedModel() {
+		return preferred
 	}
 
-	// Build fallback list from config
-	fallbacks := []string{
-		o.cfg.Models.Routing.Simple,
-		o.cfg.Models.Routing.Complex,
+	// Build fallback list from the strategy and config, skipping unset routes
+	var fallbacks []string
+	var strategyFallback string
+	if s := o.activeStrategy(agent.ID); s != nil {
+		strategyFallback = s.FallbackModel
+	}
+	for _, m := range []string{strategyFallback, o.cfg.Models.Routing.Simple, o.cfg.Models.Routing.Complex} {
+		if m != "" {
+			fallbacks = append(fallbacks, m)
+		}
 	}
 
 	// Use health registry to select best model if available
 	if o.healthRegistry != nil {
 		selected := o.healthRegistry.GetHealthyModel(preferred, fallbacks)
+
+		// No healthy cloud model left: fall back to the emergency local model
+		emergency := o.cfg.Models.Routing.EmergencyLocal
+		if emergency != "" && !o.healthRegistry.IsHealthy(selected) {
+			o.logger.Warn("all routed models degraded, using emergency local model",
+				"preferred", preferred,
+				"model", emergency,
+			)
+			return emergency
+		}
+
 		if selected != preferred {
 			o.logger.Debug("model selection adjusted by health registry",
 				"preferred", preferred,
@@ -937,12 +1395,10 @@ func (o *Orchestrator) selectModel(msg Message, agent *AgentState) string {
 func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model string) {
 	start := time.Now()
 
-	agent.mu.Lock()
-	agent.Status = "running"
-	agent.LastActive = time.Now()
-	agent.MessageCount++
+	o.markRunning(agent)
+	agent.mu.RLock()
 	isEdge := agent.IsEdgeAgent
-	agent.mu.Unlock()
+	agent.mu.RUnlock()
 
 	defer func() {
 		agent.mu.Lock()
@@ -952,25 +1408,34 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 
 	// If this is an edge agent, forward to MQTT instead of processing locally
 	if isEdge {
-		o.processWithEdgeAgent(agent, msg, model, start)
+		ctx, release := o.messageContext(msg)
+		defer release()
+		o.processWithEdgeAgent(ctx, agent, msg, model, start)
 		return
 	}
 
 	var resp *Response
 	var err error
 	var llmResp *ChatResponse
+	// cached is set when the response cache answered instead of the
+	// provider: nothing was spent.
+	var cached bool
 
 	// Check if this is an edge agent (connected via MQTT)
 	if o.mqttChannel != nil && o.mqttChannel.IsEdgeAgentOnline(agent.ID) {
 		// Forward prompt to edge agent instead of processing locally
 		o.logger.Info("forwarding to edge agent", "agent", agent.ID)
+		edgeCtx, edgeSpan := o.Tracer().Start(o.traceContext(msg), "edge.dispatch")
+		edgeSpan.SetAttr("agent.id", agent.ID)
 		edgeResp, edgeErr := o.mqttChannel.SendPromptAndWait(
-			o.ctx,
+			edgeCtx,
 			agent.ID,
 			msg.Content,
 			agent.Def.SystemPrompt,
 			60*time.Second, // 60s timeout for edge agent response
 		)
+		edgeSpan.RecordError(edgeErr)
+		edgeSpan.End()
 
 		if edgeErr != nil {
 			o.logger.Error("edge agent error", "agent", agent.ID, "error", edgeErr)
@@ -978,6 +1443,7 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 			agent.ErrorCount++
 			agent.Metrics.FailedActions++
 			agent.mu.Unlock()
+			o.recordAction(agent.ID, "edge", model, msg, time.Since(start), edgeErr)
 			return
 		}
 
@@ -1001,7 +1467,8 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 		)
 
 		// Send response back through channel
-		o.outbox <- *resp
+		o.postProcess(resp)
+		o.enqueueResponse(*resp)
 
 		// Update metrics
 		agent.mu.Lock()
@@ -1009,12 +1476,35 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 		agent.Metrics.SuccessfulActions++
 		agent.Metrics.AvgResponseMs = (agent.Metrics.AvgResponseMs + float64(elapsed.Milliseconds())) / 2
 		agent.mu.Unlock()
+		o.recordAction(agent.ID, "edge", edgeResp.Model, msg, elapsed, nil)
 		return
 	}
 
+	// Enforce cost budgets: downshift near the cap, refuse paid calls past it
+	budgeted, budgetErr := o.applyBudget(agent, model)
+	if budgetErr != nil {
+		o.logger.Warn("cost budget exceeded", "agent", agent.ID, "error", budgetErr)
+		o.enqueueResponse(Response{
+			AgentID:   agent.ID,
+			Content:   o.budgetRefusal(),
+			Channel:   msg.Channel,
+			To:        msg.From,
+			ReplyTo:   msg.ID,
+			MessageID: msg.ID,
+			Metadata:  map[string]string{"budget_exceeded": "true"},
+		})
+		o.recordAction(agent.ID, "chat", model, msg, time.Since(start), budgetErr)
+		return
+	}
+	model = budgeted
+
 	// Use tool loop if enabled and agent has capabilities
-	if o.toolLoop != nil && len(agent.Def.Capabilities) > 0 {
-		tlResp, tlMetrics, tlErr := o.toolLoop.Execute(agent, msg, model)
+	callStart := time.Now()
+	o.mu.RLock()
+	toolLoop := o.toolLoop
+	o.mu.RUnlock()
+	if toolLoop != nil && len(agent.Def.Capabilities) > 0 {
+		tlResp, tlMetrics, tlErr := toolLoop.Execute(agent, msg, model)
 		if tlErr != nil {
 			o.logger.Error("tool loop error", "error", tlErr)
 			agent.mu.Lock()
@@ -1026,6 +1516,8 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 				errType := router.ClassifyError(tlErr)
 				o.healthRegistry.RecordFailure(model, errType)
 			}
+			o.recordAction(agent.ID, "chat", model, msg, time.Since(start), tlErr)
+			o.recordErrorFailure(agent, msg, tlErr)
 			return
 		}
 
@@ -1036,7 +1528,11 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 			"errors", tlMetrics.ErrorCount,
 		)
 
-		llmResp = &ChatResponse{Content: tlResp.Content}
+		llmResp = &ChatResponse{
+			Content:      tlResp.Content,
+			TokensInput:  tlMetrics.TokensInput,
+			TokensOutput: tlMetrics.TokensOutput,
+		}
 		resp = &Response{
 			AgentID:   agent.ID,
 			Content:   tlResp.Content,
@@ -1045,10 +1541,11 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 			ReplyTo:   msg.ID,
 			MessageID: msg.ID,
 			Model:     model,
+			Metadata:  tlResp.Metadata,
 		}
 	} else {
 		// Legacy: direct LLM call without tools
-		llmResp, err = o.processDirect(agent, msg, model)
+		llmResp, cached, err = o.processDirect(agent, msg, model)
 		if err != nil {
 			o.logger.Error("LLM error", "model", model, "error", err)
 			agent.mu.Lock()
@@ -1065,6 +1562,8 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 					"error_type", errType,
 				)
 			}
+			o.recordAction(agent.ID, "chat", model, msg, time.Since(start), err)
+			o.recordErrorFailure(agent, msg, err)
 
 			return
 		}
@@ -1078,6 +1577,16 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 			MessageID: msg.ID,
 			Model:     model,
 		}
+		setReasoning(resp, o.keepReasoning(agent.ID, model, llmResp))
+	}
+	latency := time.Since(callStart)
+
+	if violated := o.constraintViolations(agent, resp.Content); len(violated) > 0 {
+		if resp.Metadata == nil {
+			resp.Metadata = make(map[string]string)
+		}
+		resp.Metadata["constraint_violation"] = strings.Join(violated, ",")
+		o.recordFailure(agent, msg, fmt.Sprintf("response violated constraints %s: %s", strings.Join(violated, ", "), resp.Content))
 	}
 
 	// Record success in health registry
@@ -1086,12 +1595,24 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 	}
 
 	elapsed := time.Since(start)
+	var cost float64
+	if !cached {
+		cost = o.chargeCost(agent, model, llmResp.TokensInput, llmResp.TokensOutput)
+	}
+	resp.Usage = &types.Usage{
+		TokensInput:  llmResp.TokensInput,
+		TokensOutput: llmResp.TokensOutput,
+		CostUSD:      cost,
+		LatencyMs:    latency.Milliseconds(),
+	}
 
 	// Update metrics
 	agent.mu.Lock()
 	agent.Metrics.TotalActions++
 	agent.Metrics.SuccessfulActions++
-	agent.Metrics.TokensUsed += int64(llmResp.TokensInput + llmResp.TokensOutput)
+	if !cached {
+		agent.Metrics.TokensUsed += int64(llmResp.TokensInput + llmResp.TokensOutput)
+	}
 	// Running average response time
 	n := float64(agent.Metrics.TotalActions)
 	agent.Metrics.AvgResponseMs = agent.Metrics.AvgResponseMs*(n-1)/n + float64(elapsed.Milliseconds())/n
@@ -1106,7 +1627,7 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 	}
 
 	// Report to evolution engine if available
-	if o.evolution != nil {
+	if o.evolution != nil && o.evolutionEnabled() && !o.SafeMode() {
 		successRate := float64(metrics.SuccessfulActions) / float64(metrics.TotalActions)
 		evalMetrics := map[string]float64{
 			"successRate":   successRate,
@@ -1123,7 +1644,8 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 	}
 
 	// Send response back
-	o.outbox <- *resp
+	o.postProcess(resp)
+	o.enqueueResponse(*resp)
 
 	o.logger.Info("agent responded",
 		"agent", agent.ID,
@@ -1132,82 +1654,39 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 		"tokens", llmResp.TokensInput+llmResp.TokensOutput,
 	)
 
-	// Log action on-chain if enabled
-	if o.chainRegistry != nil {
-		go func() {
-			reporter := onchain.NewActionReporter(o.chainRegistry, o.logger)
-			action := onchain.Action{
-				AgentDID:    agent.ID,
-				Chain:       "bsc",
-				ActionType:  "chat",
-				Description: fmt.Sprintf("Processed message via %s (%dms)", model, elapsed.Milliseconds()),
-				Success:     true,
-				Timestamp:   time.Now(),
-			}
-			if err := reporter.ExecuteAndReport(o.ctx, action); err != nil {
-				o.logger.Debug("on-chain action log failed (non-fatal)", "error", err)
-			}
-		}()
-	}
-
-	// Cloud sync — critical sync after every conversation
-	if o.cloudSync != nil && o.cloudSync.IsEnabled() {
-		go func() {
-			agent.mu.RLock()
-			caps := make([]string, len(agent.Def.Capabilities))
-			copy(caps, agent.Def.Capabilities)
-			genome := make(map[string]interface{})
-			if agent.Def.Genome != nil {
-				genome["identity"] = agent.Def.Genome.Identity
-				genome["skills"] = agent.Def.Genome.Skills
-				genome["behavior"] = agent.Def.Genome.Behavior
-				genome["constraints"] = agent.Def.Genome.Constraints
-			}
-			agentMetrics := agent.Metrics
-			agent.mu.RUnlock()
-
-			// Build core memory from agent state
-			coreMemory := map[string]interface{}{
-				"last_conversation": map[string]interface{}{
-					"timestamp": time.Now().Unix(),
-					"channel":   msg.Channel,
-					"from":      msg.From,
-					"model":     model,
-					"elapsed":   elapsed.Milliseconds(),
-				},
-				"metrics": map[string]interface{}{
-					"total_actions":      agentMetrics.TotalActions,
-					"successful_actions": agentMetrics.SuccessfulActions,
-					"tokens_used":        agentMetrics.TokensUsed,
-					"avg_response_ms":    agentMetrics.AvgResponseMs,
-					"cost_usd":           agentMetrics.CostUSD,
-				},
-			}
-
-			agentMemory := &cloudsync.AgentMemory{
-				AgentID:      agent.ID,
-				Name:         agent.Def.Name,
-				Model:        model,
-				Capabilities: caps,
-				Genome:       genome,
-				CoreMemory:   coreMemory,
-			}
-
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
+	o.recordAction(agent.ID, "chat", model, msg, elapsed, nil)
 
-			if err := o.cloudSync.SyncCritical(ctx, agentMemory); err != nil {
-				o.logger.Debug("cloud sync critical failed (non-fatal)", "error", err)
-			} else {
-				o.logger.Debug("cloud synced after conversation", "agent", agent.ID)
-			}
-		}()
+	// Log action on-chain if enabled
+	action := onchain.Action{
+		AgentDID:    agent.ID,
+		Chain:       "bsc",
+		ActionType:  "chat",
+		Description: fmt.Sprintf("Processed message via %s (%dms)", model, elapsed.Milliseconds()),
+		Success:     true,
+		Timestamp:   time.Now(),
+	}
+	o.goTracked("onchain_report", func() { o.reportOnChain(msg, action) })
+
+	// Cloud sync — critical sync after every conversation, debounced so a
+	// burst of conversations is written once
+	if o.cloudSyncActive() {
+		agentMemory := o.cloudAgentMemory(agent, model, map[string]interface{}{
+			"timestamp": time.Now().Unix(),
+			"channel":   msg.Channel,
+			"from":      msg.From,
+			"model":     model,
+			"elapsed":   elapsed.Milliseconds(),
+		})
+		if err := o.cloudSync.QueueCritical(agentMemory); err != nil {
+			o.logger.Debug("cloud sync critical failed (non-fatal)", "error", err)
+		}
 	}
 
 	// Tiered memory — distill and store conversation
 	if o.memory != nil {
-		go func() {
+		o.goTracked("memory_store", func() {
 			conv := memory.RawConversation{
+				AgentID: agent.ID,
 				Messages: []memory.Message{
 					{Role: "user", Content: msg.Content},
 					{Role: "agent", Content: llmResp.Content},
@@ -1219,7 +1698,7 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 			category := fmt.Sprintf("conversations/%s", msg.Channel)
 			importance := 0.5 // Default; could be tuned by message content analysis
 
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), o.memoryProcessTimeout())
 			defer cancel()
 
 			if err := o.memory.ProcessConversation(ctx, conv, category, importance); err != nil {
@@ -1230,7 +1709,7 @@ func (o *Orchestrator) processWithAgent(agent *AgentState, msg Message, model st
 					"category", category,
 				)
 			}
-		}()
+		})
 	}
 }
 
@@ -1240,7 +1719,7 @@ func (o *Orchestrator) findProvider(model string) ModelProvider {
 	// Extract provider name from model string
 	if idx := strings.Index(model, "/"); idx > 0 {
 		providerName := model[:idx]
-		
+
 		// Match against provider names (ollama, nvidia, zhipu-1, zhipu-2, etc.)
 		for _, p := range o.providers {
 			pName := p.Name()
@@ -1250,7 +1729,7 @@ func (o *Orchestrator) findProvider(model string) ModelProvider {
 			}
 		}
 	}
-	
+
 	// Fallback: return first provider
 	for _, p := range o.providers {
 		return p
@@ -1260,7 +1739,13 @@ func (o *Orchestrator) findProvider(model string) ModelProvider {
 
 // evolutionLoop periodically evaluates and improves agents
 func (o *Orchestrator) evolutionLoop() {
-	ticker := time.NewTicker(time.Duration(o.cfg.Evolution.EvalIntervalSec) * time.Second)
+	// A random start offset keeps a fleet restarted together from
+	// evaluating, mutating and syncing at the same instants.
+	if !o.sleep(o.evolutionStartDelay()) {
+		return
+	}
+
+	ticker := time.NewTicker(o.evolutionInterval())
 	defer ticker.Stop()
 
 	for {
@@ -1268,116 +1753,155 @@ func (o *Orchestrator) evolutionLoop() {
 		case <-o.ctx.Done():
 			return
 		case <-ticker.C:
-			o.evaluateAgents()
+			// evolution.enabled can be switched off by a config reload;
+			// the loop keeps running so switching it back on resumes it.
+			if !o.evolutionEnabled() {
+				continue
+			}
+			o.evaluateAgentsStaggered(o.evolutionStagger())
 		}
 	}
 }
 
+// evolutionEnabled reports whether evolution.enabled is currently set.
+func (o *Orchestrator) evolutionEnabled() bool {
+	config.RLock()
+	defer config.RUnlock()
+	return o.cfg.Evolution.Enabled
+}
+
 // evaluateAgents runs the evolution engine on all agents (per-skill evaluation)
 func (o *Orchestrator) evaluateAgents() {
-	if o.evolution == nil {
+	o.evaluateAgentsStaggered(0)
+}
+
+// evaluateAgentsStaggered evaluates the agents in ID order, waiting step
+// between one agent and the next.
+func (o *Orchestrator) evaluateAgentsStaggered(step time.Duration) {
+	for i, agent := range o.evolutionAgents() {
+		if i > 0 && !o.sleep(step) {
+			return
+		}
+		o.evaluateAgent(agent)
+	}
+}
+
+// defaultMinFitness is the evolution threshold when none is configured.
+const defaultMinFitness = 0.6
+
+// minFitness returns the fitness below which an agent of agentType, or its
+// skill when skill is non-empty, evolves. The most specific setting wins:
+// the skill's genome, then evolution.minFitnessBySkill, minFitnessByType
+// and minFitness.
+func (o *Orchestrator) minFitness(agentType, skill string, genome *config.Genome) float64 {
+	evo := o.cfg.Evolution
+	if skill != "" {
+		if genome != nil && genome.Skills[skill].MinFitness > 0 {
+			return genome.Skills[skill].MinFitness
+		}
+		if v := evo.MinFitnessBySkill[skill]; v > 0 {
+			return v
+		}
+	}
+	if v := evo.MinFitnessByType[agentType]; v > 0 {
+		return v
+	}
+	if evo.MinFitness > 0 {
+		return evo.MinFitness
+	}
+	return defaultMinFitness
+}
+
+// evaluateAgent runs the evolution engine on one agent's enabled skills.
+func (o *Orchestrator) evaluateAgent(agent *AgentState) {
+	if o.evolution == nil || o.SafeMode() {
 		return
 	}
 
-	o.mu.RLock()
-	defer o.mu.RUnlock()
+	agent.mu.RLock()
+	metrics := agent.Metrics
+	agentID := agent.ID
+	genome := agent.Def.Genome
+	agentType := agent.Def.Type
+	paused := agent.Paused
+	agent.mu.RUnlock()
 
-	for _, agent := range o.agents {
-		agent.mu.RLock()
-		metrics := agent.Metrics
-		agentID := agent.ID
-		genome := agent.Def.Genome
-		agent.mu.RUnlock()
+	if paused || metrics.TotalActions < int64(o.cfg.Evolution.MinSamplesForEval) {
+		return
+	}
 
-		if metrics.TotalActions < int64(o.cfg.Evolution.MinSamplesForEval) {
+	if genome == nil {
+		return
+	}
+
+	// Evaluate each enabled skill separately
+	for skillName, skill := range genome.Skills {
+		if !skill.Enabled {
 			continue
 		}
 
-		if genome == nil {
-			continue
+		// Prepare skill-specific metrics
+		evalMetrics := o.getSkillMetrics(agent, skillName)
+
+		// Try to cast evolution engine to the extended interface
+		type SkillEvolver interface {
+			EvaluateSkill(agentID, skillName string, metrics map[string]float64) (float64, error)
+			ShouldEvolveSkill(agentID, skillName string, minFitness float64, minSamples int) (bool, error)
+			MutateSkill(agentID, skillName string, mutationRate float64) error
 		}
 
-		// Evaluate each enabled skill separately
-		for skillName, skill := range genome.Skills {
-			if !skill.Enabled {
+		if skillEvo, ok := o.evolution.(SkillEvolver); ok {
+			fitness, err := skillEvo.EvaluateSkill(agentID, skillName, evalMetrics)
+			if err != nil {
+				o.logger.Error("skill evaluation failed",
+					"agent", agentID,
+					"skill", skillName,
+					"error", err,
+				)
 				continue
 			}
 
-			// Prepare skill-specific metrics
-			evalMetrics := o.getSkillMetrics(agent, skillName)
+			o.logger.Info("skill evaluation",
+				"agent", agentID,
+				"skill", skillName,
+				"fitness", fitness,
+				"version", skill.Version,
+			)
 
-			// Try to cast evolution engine to the extended interface
-			type SkillEvolver interface {
-				EvaluateSkill(agentID, skillName string, metrics map[string]float64) (float64, error)
-				ShouldEvolveSkill(agentID, skillName string, minFitness float64, minSamples int) (bool, error)
-				MutateSkill(agentID, skillName string, mutationRate float64) error
+			// Check if this skill needs evolution
+			minFitness := o.minFitness(agentType, skillName, genome)
+			shouldEvolve, err := skillEvo.ShouldEvolveSkill(agentID, skillName, minFitness, o.cfg.Evolution.MinSamplesForEval)
+			if err != nil {
+				o.logger.Error("evolution check failed",
+					"agent", agentID,
+					"skill", skillName,
+					"error", err,
+				)
+				continue
 			}
 
-			if skillEvo, ok := o.evolution.(SkillEvolver); ok {
-				fitness, err := skillEvo.EvaluateSkill(agentID, skillName, evalMetrics)
-				if err != nil {
-					o.logger.Error("skill evaluation failed",
-						"agent", agentID,
-						"skill", skillName,
-						"error", err,
-					)
-					continue
-				}
-
-				o.logger.Info("skill evaluation",
+			if shouldEvolve {
+				o.logger.Warn("skill fitness below threshold, triggering evolution",
 					"agent", agentID,
 					"skill", skillName,
 					"fitness", fitness,
-					"version", skill.Version,
+					"threshold", minFitness,
 				)
 
-				// Check if this skill needs evolution
-				minFitness := 0.6
-				shouldEvolve, err := skillEvo.ShouldEvolveSkill(agentID, skillName, minFitness, o.cfg.Evolution.MinSamplesForEval)
-				if err != nil {
-					o.logger.Error("evolution check failed",
-						"agent", agentID,
-						"skill", skillName,
-						"error", err,
-					)
-					continue
-				}
-
-				if shouldEvolve {
-					o.logger.Warn("skill fitness below threshold, triggering evolution",
-						"agent", agentID,
-						"skill", skillName,
-						"fitness", fitness,
-						"threshold", minFitness,
-					)
-
-					agent.mu.Lock()
-					agent.Status = "evolving"
-					agent.mu.Unlock()
+				agent.mu.Lock()
+				agent.Status = "evolving"
+				agent.mu.Unlock()
 
-					go o.evolveSkill(agent, skillName, fitness)
-				}
-			} else {
-				// Fallback to legacy agent-level evolution
-				successRate := float64(metrics.SuccessfulActions) / float64(metrics.TotalActions)
-				evalMetrics := map[string]float64{
-					"successRate":   successRate,
-					"avgResponseMs": metrics.AvgResponseMs,
-					"costUSD":       metrics.CostUSD,
-					"totalActions":  float64(metrics.TotalActions),
-				}
-				for k, v := range metrics.Custom {
-					evalMetrics[k] = v
-				}
-
-				fitness := o.evolution.Evaluate(agentID, evalMetrics)
-				minFitness := 0.6
-				if o.evolution.ShouldEvolve(agentID, minFitness) {
-					agent.mu.Lock()
-					agent.Status = "evolving"
-					agent.mu.Unlock()
-					go o.evolveAgent(agent, fitness)
-				}
+				o.safeGo("evolve_skill", func() { o.evolveSkill(agent, skillName, fitness) })
+			}
+		} else {
+			// Fallback to legacy agent-level evolution
+			fitness := o.evolution.Evaluate(agentID, o.agentEvalMetrics(agent))
+			if o.evolution.ShouldEvolve(agentID, o.minFitness(agentType, "", nil)) {
+				agent.mu.Lock()
+				agent.Status = "evolving"
+				agent.mu.Unlock()
+				o.safeGo("evolve_agent", func() { o.evolveAgent(agent, fitness) })
 			}
 		}
 	}
@@ -1432,6 +1956,11 @@ func (o *Orchestrator) evolveSkill(agent *AgentState, skillName string, currentF
 		MutateSkill(agentID, skillName string, mutationRate float64) error
 	}
 
+	if o.SafeMode() {
+		o.logger.Info("skill mutation skipped in safe mode", "agent", agent.ID, "skill", skillName)
+		return
+	}
+
 	if skillEvo, ok := o.evolution.(SkillEvolver); ok {
 		if err := skillEvo.MutateSkill(agent.ID, skillName, o.cfg.Evolution.MaxMutationRate); err != nil {
 			o.logger.Error("skill mutation failed",
@@ -1439,8 +1968,10 @@ func (o *Orchestrator) evolveSkill(agent *AgentState, skillName string, currentF
 				"skill", skillName,
 				"error", err,
 			)
+			o.recordEvolution(agent.ID, EvolutionEvent{Type: EvolutionSkillMutation, Skill: skillName, Fitness: currentFitness, Error: err.Error()})
 			return
 		}
+		o.recordEvolution(agent.ID, EvolutionEvent{Type: EvolutionSkillMutation, Skill: skillName, Fitness: currentFitness})
 
 		o.logger.Info("skill evolved successfully",
 			"agent", agent.ID,
@@ -1448,8 +1979,8 @@ func (o *Orchestrator) evolveSkill(agent *AgentState, skillName string, currentF
 		)
 
 		// Sync evolution event to cloud
-		if o.cloudSync != nil && o.cloudSync.IsEnabled() {
-			go func() {
+		if o.cloudSyncActive() {
+			o.goTracked("cloud_sync", func() {
 				snapshot := &cloudsync.MemorySnapshot{
 					AgentID:   agent.ID,
 					Timestamp: time.Now().Unix(),
@@ -1469,7 +2000,7 @@ func (o *Orchestrator) evolveSkill(agent *AgentState, skillName string, currentF
 				if err := o.cloudSync.SyncWarm(ctx, snapshot); err != nil {
 					o.logger.Debug("cloud sync evolution event failed (non-fatal)", "error", err)
 				}
-			}()
+			})
 		}
 	}
 }
@@ -1484,31 +2015,48 @@ func (o *Orchestrator) evolveAgent(agent *AgentState, currentFitness float64) {
 
 	o.logger.Info("starting agent evolution", "agent", agent.ID, "fitness", currentFitness)
 
+	if err := o.mutateAgent(agent, currentFitness, false); err != nil {
+		o.logger.Error("evolution failed", "agent", agent.ID, "error", err)
+	}
+}
+
+// mutateAgent mutates the agent's strategy and resets its metrics so the new
+// strategy is evaluated from scratch. manual marks API-triggered cycles.
+func (o *Orchestrator) mutateAgent(agent *AgentState, currentFitness float64, manual bool) error {
+	if o.SafeMode() {
+		return ErrSafeMode
+	}
+
 	// Mutate strategy
 	_, err := o.evolution.Mutate(agent.ID, o.cfg.Evolution.MaxMutationRate)
 	if err != nil {
-		o.logger.Error("evolution failed", "agent", agent.ID, "error", err)
-		return
+		o.recordEvolution(agent.ID, EvolutionEvent{Type: EvolutionMutation, Fitness: currentFitness, Manual: manual, Error: err.Error()})
+		return err
 	}
 
 	// Reset metrics for new strategy evaluation
+	agent.mu.RLock()
+	agentType := agent.Def.Type
+	agent.mu.RUnlock()
+	o.mu.RLock()
+	fresh := o.newAgentMetricsLocked(agentType)
+	o.mu.RUnlock()
 	agent.mu.Lock()
-	agent.Metrics = AgentMetrics{
-		Custom: make(map[string]float64),
-	}
+	agent.Metrics = fresh
 	agent.mu.Unlock()
 
-	o.logger.Info("agent evolved successfully", "agent", agent.ID)
+	o.logger.Info("agent evolved successfully", "agent", agent.ID, "manual", manual)
+	o.recordEvolution(agent.ID, EvolutionEvent{Type: EvolutionMutation, Fitness: currentFitness, Manual: manual})
 
 	// Sync evolution event to cloud
-	if o.cloudSync != nil && o.cloudSync.IsEnabled() {
-		go func() {
+	if o.cloudSyncActive() {
+		o.goTracked("cloud_sync", func() {
 			snapshot := &cloudsync.MemorySnapshot{
 				AgentID:   agent.ID,
 				Timestamp: time.Now().Unix(),
 				Evolution: []cloudsync.EvolutionEntry{
 					{
-						EventType:    "mutation",
+						EventType:    EvolutionMutation,
 						FitnessScore: currentFitness,
 						Metrics: map[string]float64{
 							"mutation_rate": o.cfg.Evolution.MaxMutationRate,
@@ -1522,8 +2070,9 @@ func (o *Orchestrator) evolveAgent(agent *AgentState, currentFitness float64) {
 			if err := o.cloudSync.SyncWarm(ctx, snapshot); err != nil {
 				o.logger.Debug("cloud sync evolution event failed (non-fatal)", "error", err)
 			}
-		}()
+		})
 	}
+	return nil
 }
 
 // GetConfig returns the orchestrator's configuration
@@ -1538,7 +2087,7 @@ func (o *Orchestrator) GetAgentMetrics(agentID string) (*AgentMetrics, error) {
 	o.mu.RUnlock()
 
 	if !ok {
-		return nil, fmt.Errorf("agent not found: %s", agentID)
+		return nil, agentNotFound(agentID)
 	}
 
 	agent.mu.RLock()
@@ -1572,17 +2121,74 @@ func (o *Orchestrator) ListAgents() []types.AgentInfo {
 			ID:           a.ID,
 			Name:         a.Def.Name,
 			Model:        a.Def.Model,
-			Status:       a.Status,
+			Status:       a.statusLocked(),
 			StartedAt:    a.StartedAt,
 			LastActive:   a.LastActive,
 			MessageCount: a.MessageCount,
 			ErrorCount:   a.ErrorCount,
+			Metrics: types.AgentMetrics{
+				TotalActions:      a.Metrics.TotalActions,
+				SuccessfulActions: a.Metrics.SuccessfulActions,
+				FailedActions:     a.Metrics.FailedActions,
+				TokensUsed:        a.Metrics.TokensUsed,
+				AvgResponseMs:     a.Metrics.AvgResponseMs,
+				CostUSD:           a.Metrics.CostUSD,
+			},
 		})
 		a.mu.RUnlock()
 	}
 	return agents
 }
 
+// fitnessHistorian is implemented by evolution engines that record fitness
+// over time, such as *evolution.Engine.
+type fitnessHistorian interface {
+	FitnessHistory(agentID string) []evolution.FitnessSample
+}
+
+// FitnessHistory returns the agent's recorded fitness values, oldest first.
+// It returns nil when no evolution engine is set or the engine does not keep
+// a history.
+func (o *Orchestrator) FitnessHistory(agentID string) []float64 {
+	o.mu.RLock()
+	h, ok := o.evolution.(fitnessHistorian)
+	o.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	samples := h.FitnessHistory(agentID)
+	values := make([]float64, len(samples))
+	for i, sample := range samples {
+		values[i] = sample.Fitness
+	}
+	return values
+}
+
+// feedbackSubmitter is implemented by evolution engines that accept user
+// feedback for behavioral fitness, such as *evolution.Engine.
+type feedbackSubmitter interface {
+	SubmitFeedback(agentID, feedbackType string, score float64, context string) error
+}
+
+// feedbackReporter is implemented by channels that report user reactions.
+type feedbackReporter interface {
+	SetFeedbackCallback(cb func(channels.Feedback))
+}
+
+// submitFeedback forwards channel feedback to the evolution engine.
+func (o *Orchestrator) submitFeedback(fb channels.Feedback) {
+	o.mu.RLock()
+	s, ok := o.evolution.(feedbackSubmitter)
+	o.mu.RUnlock()
+	if !ok {
+		return
+	}
+	if err := s.SubmitFeedback(fb.AgentID, fb.Type, fb.Score, fb.Context); err != nil {
+		o.logger.Warn("submit feedback failed", "agent", fb.AgentID, "error", err)
+	}
+}
+
 // RegisterResultHandler registers a handler for a tool result
 func (o *Orchestrator) RegisterResultHandler(requestID string, handler func(*ToolResult)) {
 	o.resultMu.Lock()
@@ -1590,11 +2196,11 @@ func (o *Orchestrator) RegisterResultHandler(requestID string, handler func(*Too
 
 	o.resultRegistry[requestID] = make(chan *ToolResult, 1)
 
-	go func() {
+	o.safeGo("result_handler", func() {
 		result := <-o.resultRegistry[requestID]
 		handler(result)
 		delete(o.resultRegistry, requestID)
-	}()
+	})
 
 	o.logger.Debug("result handler registered", "request_id", requestID)
 }
@@ -1608,14 +2214,14 @@ func (o *Orchestrator) DeliverToolResult(requestID string, result map[string]int
 		// Fall back to tool result registry
 		toolCh, isTool := o.resultRegistry[requestID]
 		o.resultMu.RUnlock()
-		
+
 		if !isTool {
 			o.logger.Warn("no handler registered for result",
 				"request_id", requestID,
 			)
 			return
 		}
-		
+
 		// Deliver tool result
 		toolResult := &ToolResult{
 			Tool:   getString(result, "tool"),
@@ -1650,7 +2256,7 @@ func (o *Orchestrator) DeliverToolResult(requestID string, result map[string]int
 		return
 	}
 	o.resultMu.RUnlock()
-	
+
 	// Deliver edge agent prompt result
 	select {
 	case edgeCh <- result:
@@ -1667,6 +2273,59 @@ func (o *Orchestrator) DeliverToolResult(requestID string, result map[string]int
 	}
 }
 
+// chunkSender is implemented by channels that can show an answer while it
+// is still being produced, e.g. by editing a message as text arrives.
+type chunkSender interface {
+	// SendChunk sends the next part of the answer to msg.ReplyTo; seq
+	// starts at 0 and increases by one per chunk.
+	SendChunk(ctx context.Context, msg Response, seq int) error
+}
+
+// DeliverEdgeChunk passes one streamed chunk of an edge agent's answer to
+// the request waiting for it, if that request forwards chunks.
+func (o *Orchestrator) DeliverEdgeChunk(requestID string, seq int, content string) {
+	o.resultMu.RLock()
+	forward, ok := o.edgeChunkRegistry[requestID]
+	o.resultMu.RUnlock()
+	if ok {
+		forward(seq, content)
+	}
+}
+
+// edgeChunkForwarder returns a function that sends streamed chunks of an
+// edge agent's answer to msg's channel, or nil if that channel can't show
+// partial answers. Nothing is streamed while a moderator or post-processor
+// is set: only the complete answer is moderated and rewritten.
+func (o *Orchestrator) edgeChunkForwarder(ctx context.Context, agent *AgentState, msg Message, model string) func(seq int, content string) {
+	o.mu.RLock()
+	ch := o.channels[msg.Channel]
+	moderated := o.moderator != nil || o.postProcessor != nil
+	o.mu.RUnlock()
+
+	cs, ok := ch.(chunkSender)
+	if !ok || moderated {
+		return nil
+	}
+	return func(seq int, content string) {
+		chunk := Response{
+			AgentID:   agent.ID,
+			Content:   content,
+			Channel:   msg.Channel,
+			To:        msg.From,
+			ReplyTo:   msg.ID,
+			MessageID: msg.ID,
+			Model:     model,
+		}
+		if err := cs.SendChunk(ctx, chunk, seq); err != nil {
+			o.logger.Warn("failed to forward edge agent chunk",
+				"channel", msg.Channel,
+				"seq", seq,
+				"error", err,
+			)
+		}
+	}
+}
+
 // getString safely extracts a string from a map[string]interface{}
 func getString(m map[string]interface{}, key string) string {
 	if val, ok := m[key]; ok {
@@ -1677,27 +2336,41 @@ func getString(m map[string]interface{}, key string) string {
 	return ""
 }
 
-// processWithEdgeAgent forwards a message to an MQTT edge agent and waits for response
-func (o *Orchestrator) processWithEdgeAgent(agent *AgentState, msg Message, model string, start time.Time) {
+// processWithEdgeAgent forwards a message to an MQTT edge agent and waits for response.
+// If ctx is done first, the edge agent is sent a cancel command and the wait ends.
+func (o *Orchestrator) processWithEdgeAgent(ctx context.Context, agent *AgentState, msg Message, model string, start time.Time) {
 	requestID := fmt.Sprintf("prompt-%d", time.Now().UnixNano())
-	
+
 	o.logger.Info("forwarding to edge agent", "agent", agent.ID)
-	
+
+	ctx, span := o.Tracer().Start(ctx, "edge.dispatch")
+	defer span.End()
+	span.SetAttr("agent.id", agent.ID)
+	span.SetAttr("edge.request_id", requestID)
+
 	// Create response channel for this request
 	respChan := make(chan map[string]interface{}, 1)
-	
+
 	// Register result handler
 	o.resultMu.Lock()
 	o.edgeResultRegistry[requestID] = respChan
 	o.resultMu.Unlock()
-	
-	// Clean up handler on exit
+
+	// Stream partial answers to the originating channel if it can show them
+	if forward := o.edgeChunkForwarder(ctx, agent, msg, model); forward != nil {
+		o.resultMu.Lock()
+		o.edgeChunkRegistry[requestID] = forward
+		o.resultMu.Unlock()
+	}
+
+	// Clean up handlers on exit
 	defer func() {
 		o.resultMu.Lock()
 		delete(o.edgeResultRegistry, requestID)
+		delete(o.edgeChunkRegistry, requestID)
 		o.resultMu.Unlock()
 	}()
-	
+
 	// Send prompt to edge agent via MQTT
 	// The MQTT channel will detect command=prompt in metadata and use it
 	// But we need to ensure Content goes into the payload as "prompt" field
@@ -1713,11 +2386,11 @@ func (o *Orchestrator) processWithEdgeAgent(agent *AgentState, msg Message, mode
 			"command": "prompt",
 		},
 	}
-	
+
 	o.mu.RLock()
 	mqttChan, ok := o.channels["mqtt"]
 	o.mu.RUnlock()
-	
+
 	if !ok {
 		o.logger.Error("mqtt channel not found")
 		agent.mu.Lock()
@@ -1726,8 +2399,9 @@ func (o *Orchestrator) processWithEdgeAgent(agent *AgentState, msg Message, mode
 		agent.mu.Unlock()
 		return
 	}
-	
-	if err := mqttChan.Send(o.ctx, mqttMsg); err != nil {
+
+	if err := mqttChan.Send(ctx, mqttMsg); err != nil {
+		span.RecordError(err)
 		o.logger.Error("failed to send to edge agent", "error", err)
 		agent.mu.Lock()
 		agent.ErrorCount++
@@ -1735,9 +2409,9 @@ func (o *Orchestrator) processWithEdgeAgent(agent *AgentState, msg Message, mode
 		agent.mu.Unlock()
 		return
 	}
-	
+
 	o.logger.Info("prompt sent to edge agent", "channel", "mqtt", "agent", agent.ID, "request_id", requestID, "prompt_length", len(msg.Content))
-	
+
 	// Wait for response with timeout
 	timeout := time.After(60 * time.Second)
 	select {
@@ -1745,19 +2419,22 @@ func (o *Orchestrator) processWithEdgeAgent(agent *AgentState, msg Message, mode
 		// Extract response content from edge agent result
 		content, _ := result["content"].(string)
 		status, _ := result["status"].(string)
-		
+
 		if status == "error" {
 			errorMsg, _ := result["error"].(string)
+			span.RecordError(errors.New(errorMsg))
 			o.logger.Error("edge agent error", "agent", agent.ID, "error", errorMsg)
 			agent.mu.Lock()
 			agent.ErrorCount++
 			agent.Metrics.FailedActions++
 			agent.mu.Unlock()
+			o.recordAction(agent.ID, "edge", model, msg, time.Since(start), errors.New(errorMsg))
+			o.recordErrorFailure(agent, msg, errors.New(errorMsg))
 			return
 		}
-		
+
 		elapsed := time.Since(start)
-		
+
 		// Extract metadata if available
 		var inputTokens, outputTokens int64
 		if metadata, ok := result["metadata"].(map[string]interface{}); ok {
@@ -1768,7 +2445,7 @@ func (o *Orchestrator) processWithEdgeAgent(agent *AgentState, msg Message, mode
 				outputTokens = int64(ot)
 			}
 		}
-		
+
 		// Update metrics
 		agent.mu.Lock()
 		agent.Metrics.TotalActions++
@@ -1777,7 +2454,8 @@ func (o *Orchestrator) processWithEdgeAgent(agent *AgentState, msg Message, mode
 		n := float64(agent.Metrics.TotalActions)
 		agent.Metrics.AvgResponseMs = agent.Metrics.AvgResponseMs*(n-1)/n + float64(elapsed.Milliseconds())/n
 		agent.mu.Unlock()
-		
+		o.recordAction(agent.ID, "edge", model, msg, elapsed, nil)
+
 		// Send response back to user
 		resp := &Response{
 			AgentID:   agent.ID,
@@ -1787,29 +2465,37 @@ func (o *Orchestrator) processWithEdgeAgent(agent *AgentState, msg Message, mode
 			ReplyTo:   msg.ID,
 			MessageID: msg.ID,
 			Model:     model,
+			Usage: &types.Usage{
+				TokensInput:  int(inputTokens),
+				TokensOutput: int(outputTokens),
+				LatencyMs:    elapsed.Milliseconds(),
+			},
 		}
-		
-		select {
-		case o.outbox <- *resp:
+
+		o.postProcess(resp)
+		if o.enqueueResponse(*resp) {
 			o.logger.Info("edge agent response delivered", "agent", agent.ID, "elapsed", elapsed)
-		case <-time.After(5 * time.Second):
-			o.logger.Warn("timeout sending edge agent response to outbox")
 		}
-		
+
 	case <-timeout:
-		o.logger.Error("edge agent error", "agent", agent.ID, "error", "timeout waiting for response from "+agent.ID)
+		err := fmt.Errorf("%w from %s", ErrEdgeTimeout, agent.ID)
+		span.RecordError(err)
+		o.logger.Error("edge agent error", "agent", agent.ID, "error", err)
 		agent.mu.Lock()
 		agent.ErrorCount++
 		agent.Metrics.FailedActions++
 		agent.mu.Unlock()
-		
-	case <-o.ctx.Done():
-		return
+		o.recordAction(agent.ID, "edge", model, msg, time.Since(start), err)
+
+	case <-ctx.Done():
+		span.RecordError(ctx.Err())
+		o.cancelEdgePrompt(ctx, mqttChan, agent.ID, requestID)
 	}
 }
 
-// processDirect processes a message without tools (legacy mode)
-func (o *Orchestrator) processDirect(agent *AgentState, msg Message, model string) (*ChatResponse, error) {
+// processDirect processes a message without tools (legacy mode). It
+// reports whether the response came from the response cache.
+func (o *Orchestrator) processDirect(agent *AgentState, msg Message, model string) (*ChatResponse, bool, error) {
 	// Extract just the model ID (after the /) for the API request
 	modelID := model
 	if idx := strings.Index(model, "/"); idx > 0 {
@@ -1818,25 +2504,38 @@ func (o *Orchestrator) processDirect(agent *AgentState, msg Message, model strin
 
 	req := ChatRequest{
 		Model:        modelID,
-		SystemPrompt: agent.Def.SystemPrompt,
+		SystemPrompt: o.systemPrompt(o.ctx, agent, msg.Content),
 		Messages: []ChatMessage{
 			{Role: "user", Content: msg.Content},
 		},
-		MaxTokens:   4096,
-		Temperature: 0.7,
 	}
+	applyModelParams(&req, o.modelParams(agent))
+	req = o.fitContext(req, model)
 
-	provider := o.findProvider(model)
-	if provider == nil {
-		return nil, fmt.Errorf("no provider for model: %s", model)
+	provider, err := o.providerFor(model)
+	if err != nil {
+		return nil, false, err
 	}
 
-	resp, err := provider.Chat(o.ctx, req)
+	ctx, span := o.Tracer().Start(o.traceContext(msg), "llm.call")
+	defer span.End()
+	span.SetAttr("llm.model", model)
+
+	callStart := time.Now()
+	resp, cached, err := o.chatProvider(ctx, agent, provider, model, req)
 	if err != nil {
-		return nil, err
+		err = &ErrProviderFailure{Provider: provider.Name(), Model: model, Err: err}
+		span.RecordError(err)
+		return nil, false, err
 	}
+	if !cached {
+		o.startShadow(agent, model, req, resp, time.Since(callStart))
+	}
+	span.SetAttr("llm.cached", cached)
+	span.SetAttr("llm.tokens_input", resp.TokensInput)
+	span.SetAttr("llm.tokens_output", resp.TokensOutput)
 
-	return resp, nil
+	return resp, cached, nil
 }
 
 // buildEdgeCallSchema dynamically constructs the edge_call tool schema based on
@@ -1852,17 +2551,47 @@ func (o *Orchestrator) buildEdgeCallSchema() (ToolSchema, bool) {
 		return ToolSchema{}, false
 	}
 
-	// Build agent list for description
+	agentSpecs := o.mqttChannel.GetOnlineAgentCapabilitySpecs()
+
+	// Build agent list for description, with each structured action and
+	// its params schema so the model can make precise calls.
+	ids := make([]string, 0, len(agentCaps))
+	for id := range agentCaps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
 	var agentLines []string
-	for id, caps := range agentCaps {
-		if caps != "" {
+	actionSet := make(map[string]bool)
+	for _, id := range ids {
+		if caps := agentCaps[id]; caps != "" {
 			agentLines = append(agentLines, fmt.Sprintf("  - %s: %s", id, caps))
 		} else {
 			agentLines = append(agentLines, fmt.Sprintf("  - %s", id))
 		}
+		if specs := agentSpecs[id]; len(specs) > 0 {
+			agentLines = append(agentLines, "    actions:", describeEdgeCapabilities(specs))
+			for _, c := range specs {
+				actionSet[c.Name] = true
+			}
+		}
 	}
 	agentDesc := strings.Join(agentLines, "\n")
 
+	actionParam := map[string]interface{}{
+		"type":        "string",
+		"description": "Optional: specific action name for structured calls",
+	}
+	if len(actionSet) > 0 {
+		actions := make([]string, 0, len(actionSet))
+		for name := range actionSet {
+			actions = append(actions, name)
+		}
+		sort.Strings(actions)
+		actionParam["enum"] = actions
+		actionParam["description"] = "Optional: action for structured calls; params must match the action's params schema listed above"
+	}
+
 	schema := ToolSchema{
 		Name: "edge_call",
 		Description: fmt.Sprintf(
@@ -1881,10 +2610,7 @@ func (o *Orchestrator) buildEdgeCallSchema() (ToolSchema, bool) {
 					"type":        "string",
 					"description": "Natural language query for the edge agent to handle",
 				},
-				"action": map[string]interface{}{
-					"type":        "string",
-					"description": "Optional: specific action name for structured calls",
-				},
+				"action": actionParam,
 				"params": map[string]interface{}{
 					"type":        "object",
 					"description": "Optional: parameters for the structured action",
@@ -764,7 +764,7 @@ func TestProcessWithAgentFullIntegration(t *testing.T) {
 			ID: "agent-1",
 			Name: "Test Agent",
 			Model: "test-model",
-			Capabilities: []string{"test"},
+			Capabilities: config.NewCapabilities("test"),
 			Genome: &config.Genome{
 				Skills: map[string]config.SkillGenome{
 					"chat": {Enabled: true},
@@ -227,6 +227,57 @@ func TestSelectModelUsesHealthRegistry(t *testing.T) {
 	}
 }
 
+// TestSelectModelEmergencyLocal tests that the emergency local model is only
+// used once every routed cloud model is degraded
+func TestSelectModelEmergencyLocal(t *testing.T) {
+	cfg := &config.Config{
+		Memory: config.MemoryConfigSettings{Enabled: false},
+		Models: config.ModelsConfig{
+			Routing: config.ModelRouting{
+				Simple:         "test/simple",
+				Complex:        "test/complex",
+				EmergencyLocal: "ollama/llama3.2",
+			},
+			Health: config.ModelHealthConfig{
+				PersistPath:      t.TempDir() + "/health.json",
+				FailureThreshold: 3,
+			},
+		},
+		Agents: []config.AgentDef{{ID: "test-agent", Model: "test/complex"}},
+	}
+
+	orc := New(cfg, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	if err := orc.initHealthRegistry(); err != nil {
+		t.Fatalf("failed to initialize health registry: %v", err)
+	}
+	agent := &AgentState{ID: "test-agent", Def: cfg.Agents[0]}
+	msg := Message{ID: "m", Content: "hello"}
+
+	degrade := func(model string) {
+		for i := 0; i < 3; i++ {
+			orc.healthRegistry.RecordFailure(model, router.ErrRateLimited)
+		}
+	}
+
+	// One cloud model still healthy: no emergency fallback
+	degrade("test/complex")
+	if model := orc.selectModel(msg, agent); model != "test/simple" {
+		t.Errorf("expected test/simple, got %s", model)
+	}
+
+	// All cloud models cooling down: use the local model
+	degrade("test/simple")
+	if model := orc.selectModel(msg, agent); model != "ollama/llama3.2" {
+		t.Errorf("expected emergency local model, got %s", model)
+	}
+
+	// Recovery takes priority over the emergency model again
+	orc.healthRegistry.ResetModel("test/complex")
+	if model := orc.selectModel(msg, agent); model != "test/complex" {
+		t.Errorf("expected test/complex after recovery, got %s", model)
+	}
+}
+
 // TestHealthRegistryPersistence tests that health state persists correctly
 func TestHealthRegistryPersistence(t *testing.T) {
 	persistPath := t.TempDir() + "/health.json"
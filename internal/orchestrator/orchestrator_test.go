@@ -9,7 +9,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/clawinfra/evoclaw/internal/channels"
 	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
 )
 
 // Mock Channel
@@ -281,6 +283,90 @@ func TestSetEvolutionEngine(t *testing.T) {
 	}
 }
 
+func TestEvolutionEngineLearnsAgentTypes(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.mu.Lock()
+	o.initAgentLocked(config.AgentDef{ID: "early", Type: "support"})
+	o.mu.Unlock()
+
+	e := evolution.NewEngine(t.TempDir(), testLogger())
+	e.RegisterFitnessFunc("support", evolution.FitnessFuncOf(func(map[string]float64) float64 { return 0.42 }))
+	e.RegisterFitnessFunc("trader", evolution.FitnessFuncOf(func(map[string]float64) float64 { return 0.84 }))
+	o.SetEvolutionEngine(e)
+
+	o.mu.Lock()
+	o.initAgentLocked(config.AgentDef{ID: "late", Type: "trader"})
+	o.mu.Unlock()
+
+	for id, want := range map[string]float64{"early": 0.42, "late": 0.84} {
+		e.SetStrategy(id, &evolution.Strategy{ID: id})
+		if got := e.Evaluate(id, map[string]float64{}); got != want {
+			t.Errorf("%s fitness = %v, want %v from its type's function", id, got, want)
+		}
+	}
+}
+
+func TestFitnessHistory(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	if got := o.FitnessHistory("test-agent"); got != nil {
+		t.Errorf("expected nil without engine, got %v", got)
+	}
+
+	// Engines without a history yield nothing.
+	o.SetEvolutionEngine(newMockEvolution())
+	if got := o.FitnessHistory("test-agent"); got != nil {
+		t.Errorf("expected nil for engine without history, got %v", got)
+	}
+
+	e := evolution.NewEngine(t.TempDir(), testLogger())
+	e.SetStrategy("test-agent", &evolution.Strategy{ID: "s1"})
+	want := []float64{
+		e.Evaluate("test-agent", map[string]float64{"successRate": 1.0}),
+		e.Evaluate("test-agent", map[string]float64{"successRate": 0.2}),
+	}
+	o.SetEvolutionEngine(e)
+
+	got := o.FitnessHistory("test-agent")
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("FitnessHistory = %v, want %v", got, want)
+	}
+}
+
+// reactingChannel is a mockChannel that reports user reactions.
+type reactingChannel struct {
+	*mockChannel
+	feedback func(channels.Feedback)
+}
+
+func (c *reactingChannel) SetFeedbackCallback(cb func(channels.Feedback)) { c.feedback = cb }
+
+func TestChannelFeedbackReachesEvolution(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	e := evolution.NewEngine(t.TempDir(), testLogger())
+	o.SetEvolutionEngine(e)
+	ch := &reactingChannel{mockChannel: newMockChannel("reacting")}
+	o.RegisterChannel(ch)
+
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer o.Stop() //nolint:errcheck
+
+	if ch.feedback == nil {
+		t.Fatal("feedback callback not wired on Start")
+	}
+	ch.feedback(channels.Feedback{AgentID: "test-agent", Type: "approval", Score: -1, Context: "👎"})
+	ch.feedback(channels.Feedback{AgentID: "test-agent", Type: "bogus", Score: 1})
+
+	history, err := e.GetBehaviorHistory("test-agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Score != -1 {
+		t.Errorf("behavior history = %+v, want one approval of -1", history)
+	}
+}
+
 func TestStartAndStop(t *testing.T) {
 	o := New(testConfig(), testLogger())
 	ch := newMockChannel("mock-channel")
@@ -0,0 +1,135 @@
+package orchestrator
+
+import (
+	"hash/fnv"
+	"maps"
+	"sync"
+)
+
+// defaultOutboxWorkers is the delivery pool size when server.outboxWorkers
+// is unset.
+const defaultOutboxWorkers = 4
+
+// outboxWorkerQueue is how many responses each worker holds before the
+// dispatcher waits for it. Only destinations that hash to a backed-up
+// worker are held up until then.
+const outboxWorkerQueue = 64
+
+// outboxWorkers returns the configured delivery pool size.
+func (o *Orchestrator) outboxWorkers() int {
+	if o.cfg != nil && o.cfg.Server.OutboxWorkers > 0 {
+		return o.cfg.Server.OutboxWorkers
+	}
+	return defaultOutboxWorkers
+}
+
+// outboxWorkerFor picks the worker for resp's destination, so responses to
+// the same channel and recipient are delivered in order.
+func outboxWorkerFor(resp Response, workers int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(resp.Channel))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(resp.To))
+	return int(h.Sum32() % uint32(workers))
+}
+
+// routeOutgoing sends responses back through channels. It hands each
+// response to one of a pool of delivery workers so a slow channel Send
+// does not hold up responses bound elsewhere. When Stop drains the outbox
+// it hands over whatever is still queued and returns once every worker has
+// delivered its share.
+func (o *Orchestrator) routeOutgoing() {
+	n := o.outboxWorkers()
+	queues := make([]chan Response, n)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan Response, outboxWorkerQueue)
+		wg.Add(1)
+		go func(q <-chan Response) {
+			defer wg.Done()
+			o.deliverResponses(q)
+		}(queues[i])
+	}
+	defer func() {
+		for _, q := range queues {
+			close(q)
+		}
+		wg.Wait()
+	}()
+
+	dispatch := func(resp Response) bool {
+		select {
+		case queues[outboxWorkerFor(resp, n)] <- resp:
+			return true
+		case <-o.ctx.Done():
+			return false
+		}
+	}
+	for {
+		select {
+		case <-o.outboxCtx.Done():
+			for {
+				select {
+				case resp := <-o.outbox:
+					if !dispatch(resp) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		case resp := <-o.outbox:
+			if !dispatch(resp) {
+				return
+			}
+		}
+	}
+}
+
+// deliverResponses is one outbox worker. It delivers until its queue is
+// closed, dropping what is left once the orchestrator is cancelled.
+func (o *Orchestrator) deliverResponses(q <-chan Response) {
+	for resp := range q {
+		if o.ctx.Err() != nil {
+			continue
+		}
+		o.deliverResponse(resp)
+	}
+}
+
+// deliverResponse sends resp through its channel.
+func (o *Orchestrator) deliverResponse(resp Response) {
+	o.mu.RLock()
+	ch, ok := o.channels[resp.Channel]
+	o.mu.RUnlock()
+
+	if !ok {
+		o.logger.Error("unknown channel for response", "channel", resp.Channel)
+		o.deadLetterResponse(DeadLetterUnknownChannel, resp)
+		return
+	}
+
+	if resp.Metadata["moderation"] == "" {
+		if v := o.moderate(ModerationInput{
+			Direction: ModerateOutbound,
+			Channel:   resp.Channel,
+			AgentID:   resp.AgentID,
+			Content:   resp.Content,
+		}); v.Flagged {
+			resp.Content = o.moderationReplacement()
+			resp.Metadata = maps.Clone(resp.Metadata)
+			if resp.Metadata == nil {
+				resp.Metadata = make(map[string]string)
+			}
+			resp.Metadata["moderation"] = "replaced"
+			resp.Metadata["moderation_reason"] = v.Reason
+		}
+	}
+
+	if err := ch.Send(o.ctx, resp); err != nil {
+		o.logger.Error("error sending response",
+			"channel", resp.Channel,
+			"error", err,
+		)
+	}
+}
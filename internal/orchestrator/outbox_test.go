@@ -0,0 +1,138 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// blockingChannel is a mockChannel whose Send waits for release, like a
+// rate-limited Telegram bot.
+type blockingChannel struct {
+	*mockChannel
+	release chan struct{}
+}
+
+func (b blockingChannel) Send(ctx context.Context, msg Response) error {
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return b.mockChannel.Send(ctx, msg)
+}
+
+func TestRouteOutgoing_SlowChannelDoesNotBlockFast(t *testing.T) {
+	cfg := testConfig()
+	cfg.Server.OutboxWorkers = 4
+	o := New(cfg, testLogger())
+	slow := blockingChannel{newMockChannel("slow"), make(chan struct{})}
+	fast := newMockChannel("fast")
+	o.RegisterChannel(slow)
+	o.RegisterChannel(fast)
+
+	// Pick a fast recipient that does not share the slow one's worker.
+	slowResp := Response{Channel: "slow", To: "chat-1"}
+	fastTo := ""
+	for i := 0; fastTo == ""; i++ {
+		to := fmt.Sprintf("user-%d", i)
+		if outboxWorkerFor(Response{Channel: "fast", To: to}, 4) != outboxWorkerFor(slowResp, 4) {
+			fastTo = to
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		o.routeOutgoing()
+		close(done)
+	}()
+	defer func() {
+		o.cancel()
+		<-done
+	}()
+
+	for i := 0; i < 3; i++ {
+		o.outbox <- Response{Channel: "slow", To: "chat-1", Content: fmt.Sprintf("slow-%d", i)}
+	}
+	for i := 0; i < 5; i++ {
+		o.outbox <- Response{Channel: "fast", To: fastTo, Content: fmt.Sprintf("fast-%d", i)}
+	}
+
+	deadline := time.After(2 * time.Second)
+	for len(fast.getSent()) < 5 {
+		select {
+		case <-deadline:
+			t.Fatalf("fast channel got %d of 5 responses while the slow one was stuck", len(fast.getSent()))
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	if n := len(slow.getSent()); n != 0 {
+		t.Fatalf("slow channel delivered %d responses before release", n)
+	}
+
+	close(slow.release)
+	deadline = time.After(2 * time.Second)
+	for len(slow.getSent()) < 3 {
+		select {
+		case <-deadline:
+			t.Fatalf("slow channel got %d of 3 responses after release", len(slow.getSent()))
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	for i, r := range slow.getSent() {
+		if want := fmt.Sprintf("slow-%d", i); r.Content != want {
+			t.Errorf("slow response %d = %q, want %q", i, r.Content, want)
+		}
+	}
+}
+
+func TestRouteOutgoing_PreservesPerDestinationOrder(t *testing.T) {
+	cfg := testConfig()
+	cfg.Server.OutboxWorkers = 8
+	o := New(cfg, testLogger())
+	ch := newMockChannel("chat")
+	o.RegisterChannel(ch)
+
+	done := make(chan struct{})
+	go func() {
+		o.routeOutgoing()
+		close(done)
+	}()
+	defer func() {
+		o.cancel()
+		<-done
+	}()
+
+	const perUser = 50
+	users := []string{"alice", "bob", "carol"}
+	for i := 0; i < perUser; i++ {
+		for _, u := range users {
+			o.outbox <- Response{Channel: "chat", To: u, Content: fmt.Sprintf("%s-%d", u, i)}
+		}
+	}
+
+	deadline := time.After(2 * time.Second)
+	for len(ch.getSent()) < perUser*len(users) {
+		select {
+		case <-deadline:
+			t.Fatalf("delivered %d of %d responses", len(ch.getSent()), perUser*len(users))
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	next := make(map[string]int)
+	for _, r := range ch.getSent() {
+		if want := fmt.Sprintf("%s-%d", r.To, next[r.To]); r.Content != want {
+			t.Fatalf("got %q for %s, want %q", r.Content, r.To, want)
+		}
+		next[r.To]++
+	}
+}
+
+func TestOutboxWorkersDefault(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	if n := o.outboxWorkers(); n != defaultOutboxWorkers {
+		t.Errorf("outboxWorkers() = %d, want %d", n, defaultOutboxWorkers)
+	}
+}
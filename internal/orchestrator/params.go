@@ -0,0 +1,57 @@
+package orchestrator
+
+import (
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// Built-in sampling defaults used when neither the agent nor
+// models.defaults set a value.
+const (
+	defaultMaxTokens   = 4096
+	defaultTemperature = 0.7
+)
+
+// modelParams resolves the sampling parameters for agent. Later sources win:
+// built-in defaults, models.defaults, the agent definition, then the agent's
+// evolved strategy (so mutated values take effect on the next request).
+func (o *Orchestrator) modelParams(agent *AgentState) config.ModelParams {
+	p := config.ModelParams{MaxTokens: defaultMaxTokens, Temperature: config.Float(defaultTemperature)}
+	merge := func(src config.ModelParams) {
+		if src.MaxTokens > 0 {
+			p.MaxTokens = src.MaxTokens
+		}
+		if src.Temperature != nil {
+			p.Temperature = src.Temperature
+		}
+		if src.TopP != nil {
+			p.TopP = src.TopP
+		}
+	}
+
+	if o.cfg != nil {
+		merge(o.cfg.Models.Defaults)
+	}
+	agent.mu.RLock()
+	merge(agent.Def.ModelParams)
+	agent.mu.RUnlock()
+
+	if s := o.activeStrategy(agent.ID); s != nil {
+		strategy := config.ModelParams{MaxTokens: s.MaxTokens}
+		if s.Temperature > 0 {
+			strategy.Temperature = config.Float(s.Temperature)
+		}
+		merge(strategy)
+	}
+	return p
+}
+
+// applyModelParams copies the resolved parameters onto a chat request.
+func applyModelParams(req *ChatRequest, p config.ModelParams) {
+	req.MaxTokens = p.MaxTokens
+	if p.Temperature != nil {
+		req.Temperature = *p.Temperature
+	}
+	if p.TopP != nil {
+		req.TopP = *p.TopP
+	}
+}
@@ -0,0 +1,104 @@
+package orchestrator
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
+)
+
+// paramsCapturingProvider records the last chat request it received.
+type paramsCapturingProvider struct {
+	mu  sync.Mutex
+	req ChatRequest
+}
+
+func (p *paramsCapturingProvider) Name() string { return "mock" }
+
+func (p *paramsCapturingProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.req = req
+	return &ChatResponse{Content: "ok"}, nil
+}
+
+func (p *paramsCapturingProvider) Models() []config.Model { return nil }
+
+func TestModelParams_Resolution(t *testing.T) {
+	cfg := testConfig()
+	o := New(cfg, testLogger())
+	agent := &AgentState{ID: "a1", Def: config.AgentDef{ID: "a1"}}
+
+	if got := o.modelParams(agent); got.MaxTokens != 4096 || *got.Temperature != 0.7 || got.TopP != nil {
+		t.Errorf("built-in defaults = %+v", got)
+	}
+
+	cfg.Models.Defaults = config.ModelParams{MaxTokens: 2048, TopP: config.Float(0.95)}
+	agent.Def.Temperature = config.Float(0.2)
+	got := o.modelParams(agent)
+	if got.MaxTokens != 2048 || *got.Temperature != 0.2 || *got.TopP != 0.95 {
+		t.Errorf("modelParams = {MaxTokens:%d Temperature:%g TopP:%g}, want {2048 0.2 0.95}",
+			got.MaxTokens, *got.Temperature, *got.TopP)
+	}
+}
+
+func TestProcessDirect_SendsZeroTemperature(t *testing.T) {
+	cfg := testConfig()
+	cfg.Models.Defaults = config.ModelParams{Temperature: config.Float(0.5), TopP: config.Float(0.9)}
+	o := New(cfg, testLogger())
+	provider := &paramsCapturingProvider{}
+	o.RegisterProvider(provider)
+
+	agent := &AgentState{ID: "a1", Def: config.AgentDef{
+		ID:          "a1",
+		ModelParams: config.ModelParams{Temperature: config.Float(0), TopP: config.Float(0)},
+	}}
+	if _, _, err := o.processDirect(agent, Message{Content: "hi"}, "mock/mock-model-1"); err != nil {
+		t.Fatalf("processDirect: %v", err)
+	}
+	if provider.req.Temperature != 0 || provider.req.TopP != 0 {
+		t.Errorf("temperature = %g, top_p = %g; want the configured 0s", provider.req.Temperature, provider.req.TopP)
+	}
+}
+
+func TestProcessDirect_SendsAgentTemperature(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	provider := &paramsCapturingProvider{}
+	o.RegisterProvider(provider)
+
+	agent := &AgentState{ID: "a1", Def: config.AgentDef{
+		ID:          "a1",
+		ModelParams: config.ModelParams{Temperature: config.Float(0.15), MaxTokens: 300, TopP: config.Float(0.8)},
+	}}
+	if _, _, err := o.processDirect(agent, Message{Content: "hi"}, "mock/mock-model-1"); err != nil {
+		t.Fatalf("processDirect: %v", err)
+	}
+	if provider.req.Temperature != 0.15 || provider.req.MaxTokens != 300 || provider.req.TopP != 0.8 {
+		t.Errorf("provider got %+v", provider.req)
+	}
+}
+
+func TestProcessDirect_MutatedStrategyTemperatureApplies(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	provider := &paramsCapturingProvider{}
+	o.RegisterProvider(provider)
+
+	e := evolution.NewEngine(t.TempDir(), testLogger())
+	e.SetStrategy("a1", &evolution.Strategy{ID: "s1", Temperature: 1.0})
+	mutated, err := e.Mutate("a1", 1.0)
+	if err != nil {
+		t.Fatalf("Mutate: %v", err)
+	}
+	wantTemp := mutated.(*evolution.Strategy).Temperature
+	o.SetEvolutionEngine(e)
+
+	agent := &AgentState{ID: "a1", Def: config.AgentDef{ID: "a1"}}
+	if _, _, err := o.processDirect(agent, Message{Content: "hi"}, "mock/mock-model-1"); err != nil {
+		t.Fatalf("processDirect: %v", err)
+	}
+	if provider.req.Temperature != wantTemp || wantTemp == defaultTemperature {
+		t.Errorf("temperature = %g, want mutated %g", provider.req.Temperature, wantTemp)
+	}
+}
@@ -0,0 +1,70 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAgentPaused is returned for chats with an agent that has been paused.
+var ErrAgentPaused = errors.New("agent is paused")
+
+// PauseAgent stops an agent from processing messages until ResumeAgent is
+// called. Incoming channel messages get a short reply saying so, chats fail
+// with ErrAgentPaused, and the scheduler and evolution loop skip the agent.
+// Messages already being processed finish normally. Pausing is not
+// persisted: agents start unpaused.
+func (o *Orchestrator) PauseAgent(id string) error {
+	return o.setPaused(id, true)
+}
+
+// ResumeAgent lets a paused agent process messages again.
+func (o *Orchestrator) ResumeAgent(id string) error {
+	return o.setPaused(id, false)
+}
+
+func (o *Orchestrator) setPaused(id string, paused bool) error {
+	o.mu.RLock()
+	agent, ok := o.agents[id]
+	o.mu.RUnlock()
+	if !ok {
+		return agentNotFound(id)
+	}
+
+	agent.mu.Lock()
+	changed := agent.Paused != paused
+	agent.Paused = paused
+	agent.mu.Unlock()
+	if !changed {
+		return nil
+	}
+
+	if paused {
+		o.logger.Info("agent paused", "agent", id)
+		o.events.Publish(Event{Type: EventAgentPaused, AgentID: id})
+	} else {
+		o.logger.Info("agent resumed", "agent", id)
+		o.events.Publish(Event{Type: EventAgentResumed, AgentID: id})
+	}
+	return nil
+}
+
+// isPaused reports whether the agent is paused.
+func (a *AgentState) isPaused() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.Paused
+}
+
+// statusLocked returns the status to report for the agent: "paused" while
+// it is paused, otherwise its current activity. Caller must hold a.mu.
+func (a *AgentState) statusLocked() string {
+	if a.Paused {
+		return "paused"
+	}
+	return a.Status
+}
+
+// pausedReply is the user-facing reply sent to messages for a paused agent.
+func pausedReply(agentID string) string {
+	return fmt.Sprintf("Agent %s is paused and not taking messages right now. Please try again later.", agentID)
+}
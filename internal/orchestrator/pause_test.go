@@ -0,0 +1,94 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func TestPauseAgent_RefusesMessagesAndResumes(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	ch := newMockChannel("test")
+	o.RegisterChannel(ch)
+	p := newMockProvider("mock")
+	o.RegisterProvider(p)
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = o.Stop() }()
+
+	if err := o.PauseAgent("test-agent"); err != nil {
+		t.Fatalf("PauseAgent: %v", err)
+	}
+	if got := o.GetAgentInfo("test-agent").Status; got != "paused" {
+		t.Errorf("status = %q, want paused", got)
+	}
+
+	o.handleMessage(Message{ID: "m1", Channel: "test", From: "user", To: "test-agent", Content: "hello"})
+	time.Sleep(50 * time.Millisecond)
+	if n := p.getCalls(); n != 0 {
+		t.Fatalf("provider called %d times for a paused agent", n)
+	}
+	sent := ch.getSent()
+	if len(sent) != 1 || sent[0].Metadata["agent_paused"] != "true" {
+		t.Fatalf("sent = %+v, want one paused reply", sent)
+	}
+
+	if _, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: "test-agent", Message: "hi"}); !errors.Is(err, ErrAgentPaused) {
+		t.Errorf("ChatSync err = %v, want ErrAgentPaused", err)
+	}
+
+	if err := o.ResumeAgent("test-agent"); err != nil {
+		t.Fatalf("ResumeAgent: %v", err)
+	}
+	if got := o.GetAgentInfo("test-agent").Status; got == "paused" {
+		t.Error("status still paused after resume")
+	}
+	if _, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: "test-agent", Message: "hi"}); err != nil {
+		t.Fatalf("ChatSync after resume: %v", err)
+	}
+	if n := p.getCalls(); n != 1 {
+		t.Errorf("provider called %d times after resume, want 1", n)
+	}
+}
+
+func TestPauseAgent_SkipsEvolution(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	e := newMockEvolution()
+	o.SetEvolutionEngine(e)
+	o.mu.Lock()
+	o.initAgentLocked(o.cfg.Agents[0])
+	o.mu.Unlock()
+
+	agent := o.agents["test-agent"]
+	agent.Def.Genome = &config.Genome{Skills: map[string]config.SkillGenome{"chat": {Enabled: true}}}
+	agent.Metrics.TotalActions = 10
+	_ = o.PauseAgent("test-agent")
+
+	evaluated := func() bool {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		_, ok := e.fitness["test-agent"]
+		return ok
+	}
+	o.evaluateAgents()
+	if evaluated() {
+		t.Error("paused agent was evaluated")
+	}
+
+	_ = o.ResumeAgent("test-agent")
+	o.evaluateAgents()
+	if !evaluated() {
+		t.Error("resumed agent was not evaluated")
+	}
+}
+
+func TestPauseAgent_UnknownAgent(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	if err := o.PauseAgent("ghost"); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("err = %v, want ErrAgentNotFound", err)
+	}
+}
@@ -0,0 +1,34 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPinnedModelUnhealthy is returned for requests to an agent with
+// pinModel set while its model is marked unhealthy.
+var ErrPinnedModelUnhealthy = errors.New("pinned model is unhealthy")
+
+// pinnedModel reports whether the agent must always use its preferred model.
+func (a *AgentState) pinnedModel() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.Def.PinModel
+}
+
+// checkPinnedModel fails when agent is pinned to model and the health
+// registry has marked it unhealthy. Unpinned agents are rerouted by
+// selectModel instead.
+func (o *Orchestrator) checkPinnedModel(agent *AgentState, model string) error {
+	if o.healthRegistry == nil || !agent.pinnedModel() {
+		return nil
+	}
+	if o.healthRegistry.IsHealthy(model) {
+		return nil
+	}
+	return fmt.Errorf("%w: agent %s, model %s", ErrPinnedModelUnhealthy, agent.ID, model)
+}
+
+func pinnedModelReply(agentID string) string {
+	return fmt.Sprintf("Agent %s's model is unavailable right now. Please try again later.", agentID)
+}
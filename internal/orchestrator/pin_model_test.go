@@ -0,0 +1,72 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/router"
+)
+
+func newPinTestOrchestrator(t *testing.T, pin bool) (*Orchestrator, *mockChannel, *mockProvider) {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Models.Routing.Simple = "mock/mock-model-2"
+	cfg.Models.Health = config.ModelHealthConfig{
+		PersistPath:      t.TempDir() + "/health.json",
+		FailureThreshold: 3,
+	}
+	cfg.Agents[0].PinModel = pin
+	o := New(cfg, testLogger())
+	ch := newMockChannel("test")
+	o.RegisterChannel(ch)
+	p := newMockProvider("mock")
+	o.RegisterProvider(p)
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = o.Stop() })
+
+	for i := 0; i < 3; i++ {
+		o.healthRegistry.RecordFailure("mock/mock-model-1", router.ErrRateLimited)
+	}
+	return o, ch, p
+}
+
+func TestPinModel_UnhealthyModelFails(t *testing.T) {
+	o, ch, p := newPinTestOrchestrator(t, true)
+	agent := o.agents["test-agent"]
+
+	if got := o.selectModel(Message{Content: "hi"}, agent); got != "mock/mock-model-1" {
+		t.Errorf("selectModel = %s, want the pinned model", got)
+	}
+	_, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: "test-agent", Message: "hi"})
+	if !errors.Is(err, ErrPinnedModelUnhealthy) {
+		t.Fatalf("ChatSync err = %v, want ErrPinnedModelUnhealthy", err)
+	}
+	if n := p.getCalls(); n != 0 {
+		t.Errorf("provider called %d times", n)
+	}
+
+	o.handleMessage(Message{ID: "m1", Channel: "test", From: "user", To: "test-agent", Content: "hi"})
+	waitForSent(t, ch, 1)
+	if sent := ch.getSent(); sent[0].Metadata["model_unavailable"] != "mock/mock-model-1" {
+		t.Errorf("sent = %+v, want a model unavailable reply", sent[0])
+	}
+	if n := p.getCalls(); n != 0 {
+		t.Errorf("provider called %d times", n)
+	}
+}
+
+func TestPinModel_UnpinnedAgentReroutes(t *testing.T) {
+	o, _, _ := newPinTestOrchestrator(t, false)
+	agent := o.agents["test-agent"]
+
+	if got := o.selectModel(Message{Content: "hi"}, agent); got != "mock/mock-model-2" {
+		t.Errorf("selectModel = %s, want the healthy fallback", got)
+	}
+	if err := o.checkPinnedModel(agent, "mock/mock-model-1"); err != nil {
+		t.Errorf("checkPinnedModel = %v for an unpinned agent", err)
+	}
+}
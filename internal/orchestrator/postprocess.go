@@ -0,0 +1,139 @@
+package orchestrator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// ResponseProcessor rewrites an agent response's content before it is
+// sent, e.g. to add a disclaimer or enforce a length limit.
+type ResponseProcessor interface {
+	Process(resp Response) string
+}
+
+// ProcessorChain runs processors in order, each on the previous one's
+// output.
+type ProcessorChain []ResponseProcessor
+
+// Process implements ResponseProcessor.
+func (c ProcessorChain) Process(resp Response) string {
+	for _, p := range c {
+		resp.Content = p.Process(resp)
+	}
+	return resp.Content
+}
+
+// TruncateProcessor caps content at MaxChars characters, ending a cut
+// response with Suffix.
+type TruncateProcessor struct {
+	MaxChars int
+	Suffix   string
+}
+
+// Process implements ResponseProcessor.
+func (p TruncateProcessor) Process(resp Response) string {
+	runes := []rune(resp.Content)
+	if p.MaxChars <= 0 || len(runes) <= p.MaxChars {
+		return resp.Content
+	}
+	keep := max(p.MaxChars-len([]rune(p.Suffix)), 0)
+	return string(runes[:keep]) + p.Suffix
+}
+
+// AppendProcessor adds Text to the end of the content.
+type AppendProcessor struct {
+	Text string
+}
+
+// Process implements ResponseProcessor.
+func (p AppendProcessor) Process(resp Response) string {
+	return resp.Content + p.Text
+}
+
+// RegexReplaceProcessor replaces every match of a regular expression.
+type RegexReplaceProcessor struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// NewRegexReplaceProcessor compiles pattern. replacement may refer to
+// capture groups as $1 or ${name}.
+func NewRegexReplaceProcessor(pattern, replacement string) (*RegexReplaceProcessor, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("post-process pattern %q: %w", pattern, err)
+	}
+	return &RegexReplaceProcessor{re: re, replacement: replacement}, nil
+}
+
+// Process implements ResponseProcessor.
+func (p *RegexReplaceProcessor) Process(resp Response) string {
+	return p.re.ReplaceAllString(resp.Content, p.replacement)
+}
+
+// scopedProcessor applies a processor only to the agents and channels the
+// config selects.
+type scopedProcessor struct {
+	next     ResponseProcessor
+	agents   []string
+	channels []string
+}
+
+func (s *scopedProcessor) Process(resp Response) string {
+	if len(s.agents) > 0 && !slices.Contains(s.agents, resp.AgentID) {
+		return resp.Content
+	}
+	if len(s.channels) > 0 && !slices.Contains(s.channels, resp.Channel) {
+		return resp.Content
+	}
+	return s.next.Process(resp)
+}
+
+// newConfiguredPostProcessor builds the pipeline described by steps.
+func newConfiguredPostProcessor(steps []config.PostProcessStep) (ResponseProcessor, error) {
+	chain := make(ProcessorChain, 0, len(steps))
+	for i, step := range steps {
+		var p ResponseProcessor
+		switch step.Type {
+		case config.PostProcessTruncate:
+			p = TruncateProcessor{MaxChars: step.MaxChars, Suffix: step.Suffix}
+		case config.PostProcessAppend:
+			p = AppendProcessor{Text: step.Text}
+		case config.PostProcessRegexReplace:
+			rp, err := NewRegexReplaceProcessor(step.Pattern, step.Replacement)
+			if err != nil {
+				return nil, err
+			}
+			p = rp
+		default:
+			return nil, fmt.Errorf("post-process step %d: unknown type %q", i, step.Type)
+		}
+		if len(step.Agents) > 0 || len(step.Channels) > 0 {
+			p = &scopedProcessor{next: p, agents: step.Agents, channels: step.Channels}
+		}
+		chain = append(chain, p)
+	}
+	return chain, nil
+}
+
+// SetPostProcessor replaces the pipeline built from the postProcess
+// config. It rewrites every agent response before it is queued for
+// delivery; nil disables post-processing.
+func (o *Orchestrator) SetPostProcessor(p ResponseProcessor) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.postProcessor = p
+}
+
+// postProcess rewrites resp's content with the post-processor, if any.
+func (o *Orchestrator) postProcess(resp *Response) {
+	o.mu.RLock()
+	p := o.postProcessor
+	o.mu.RUnlock()
+	if p != nil {
+		resp.Content = p.Process(*resp)
+	}
+}
@@ -0,0 +1,61 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func TestPostProcessChainAppliesStepsInOrder(t *testing.T) {
+	// The appended text contains what the replace step removes, so it only
+	// survives if the steps run in order.
+	p, err := newConfiguredPostProcessor([]config.PostProcessStep{
+		{Type: config.PostProcessRegexReplace, Pattern: `\[thought:[^\]]*\]\s*`},
+		{Type: config.PostProcessAppend, Text: "\n[thought: kept]"},
+	})
+	if err != nil {
+		t.Fatalf("newConfiguredPostProcessor: %v", err)
+	}
+
+	got := p.Process(Response{Content: "[thought: check prices] BTC is up."})
+	if want := "BTC is up.\n[thought: kept]"; got != want {
+		t.Errorf("Process = %q, want %q", got, want)
+	}
+}
+
+func TestPostProcessAppliedBeforeDelivery(t *testing.T) {
+	cfg := testConfig()
+	cfg.PostProcess = []config.PostProcessStep{
+		{Type: config.PostProcessTruncate, MaxChars: 8, Suffix: "..."},
+		{Type: config.PostProcessAppend, Text: " -- bot"},
+		{Type: config.PostProcessAppend, Text: " (other agent)", Agents: []string{"someone-else"}},
+	}
+	o := New(cfg, testLogger())
+	provider := newMockProvider("mock")
+	provider.responses["mock-model-1"] = "a long answer"
+	o.RegisterProvider(provider)
+	o.mu.Lock()
+	agent := o.initAgentLocked(cfg.Agents[0])
+	o.mu.Unlock()
+
+	resp := processAndReceive(t, o, agent)
+	if want := "a lon... -- bot"; resp.Content != want {
+		t.Errorf("delivered %q, want %q", resp.Content, want)
+	}
+}
+
+func TestTruncateProcessorCountsCharacters(t *testing.T) {
+	p := TruncateProcessor{MaxChars: 3}
+	if got := p.Process(Response{Content: "héllo"}); got != "hél" {
+		t.Errorf("Process = %q, want hél", got)
+	}
+	if got := p.Process(Response{Content: "hé"}); got != "hé" {
+		t.Errorf("short content changed to %q", got)
+	}
+}
+
+func TestNewConfiguredPostProcessorRejectsBadPattern(t *testing.T) {
+	if _, err := newConfiguredPostProcessor([]config.PostProcessStep{{Type: config.PostProcessRegexReplace, Pattern: "("}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,54 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+)
+
+// ChatPreview is the provider request a chat would send, assembled without
+// calling the provider.
+type ChatPreview struct {
+	AgentID string
+	// Model is the full "provider/model" the request would go to, after
+	// strategy and budget routing.
+	Model string
+	// Provider is the provider ChatSync would call, or empty if none.
+	Provider string
+	// Request is exactly what the provider's Chat would receive.
+	Request ChatRequest
+}
+
+// PreviewChat runs the same agent lookup, model selection, system prompt
+// composition and context trimming as ChatSync and returns the request it
+// would send. Nothing is recorded against the agent, no budget alert is
+// raised and no provider is called.
+func (o *Orchestrator) PreviewChat(ctx context.Context, req ChatSyncRequest) (*ChatPreview, error) {
+	o.mu.RLock()
+	agent, ok := o.agents[req.AgentID]
+	o.mu.RUnlock()
+	if !ok {
+		return nil, agentNotFound(req.AgentID)
+	}
+	if agent.isPaused() {
+		return nil, fmt.Errorf("%w: %s", ErrAgentPaused, req.AgentID)
+	}
+
+	model := o.preferredModel(agent)
+	if req.Model != "" {
+		model = req.Model
+	}
+	model, err := o.routeSync(agent, model, false)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ChatPreview{
+		AgentID: agent.ID,
+		Model:   model,
+		Request: o.buildSyncRequest(ctx, agent, req, model),
+	}
+	if p := o.findProvider(model); p != nil {
+		preview.Provider = p.Name()
+	}
+	return preview, nil
+}
@@ -0,0 +1,105 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func TestPreviewChat(t *testing.T) {
+	cfg := testConfig()
+	cfg.Agents[0].Capabilities = config.NewCapabilities("filesystem")
+	o := New(cfg, testLogger())
+	p := newMockProvider("mock")
+	o.RegisterProvider(p)
+	o.mu.Lock()
+	o.initAgentLocked(cfg.Agents[0])
+	o.mu.Unlock()
+
+	preview, err := o.PreviewChat(context.Background(), ChatSyncRequest{
+		AgentID: "test-agent",
+		Message: "what time is it?",
+		History: []ChatMessage{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("PreviewChat: %v", err)
+	}
+
+	if preview.Model != "mock/mock-model-1" || preview.Provider != "mock" {
+		t.Errorf("model = %q, provider = %q", preview.Model, preview.Provider)
+	}
+	if preview.Request.Model != "mock-model-1" {
+		t.Errorf("request model = %q, want the ID without provider prefix", preview.Request.Model)
+	}
+	if !strings.Contains(preview.Request.SystemPrompt, "You are a test agent") {
+		t.Errorf("system prompt = %q, want the agent's prompt in it", preview.Request.SystemPrompt)
+	}
+	msgs := preview.Request.Messages
+	if len(msgs) != 3 || msgs[2].Content != "what time is it?" {
+		t.Errorf("messages = %+v, want history then the message", msgs)
+	}
+	// ChatSync sends no tools, so neither does its preview
+	if len(preview.Request.Tools) != 0 {
+		t.Errorf("tools = %v, want none", schemaNames(preview.Request.Tools))
+	}
+
+	if n := p.getCalls(); n != 0 {
+		t.Errorf("provider called %d times, want 0", n)
+	}
+	m, _ := o.GetAgentMetrics("test-agent")
+	if m.TotalActions != 0 || m.TokensUsed != 0 {
+		t.Errorf("metrics changed by a preview: %+v", m)
+	}
+}
+
+func TestPreviewChat_UnknownAgent(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	if _, err := o.PreviewChat(context.Background(), ChatSyncRequest{AgentID: "ghost"}); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("err = %v, want ErrAgentNotFound", err)
+	}
+}
+
+func TestPreviewChat_RoutesBudgetWithoutAlerts(t *testing.T) {
+	o, agent := newBudgetOrchestrator(t, budgetConfig(1.0, 0))
+	o.costs.add(agent.ID, o.budgetPeriod(), 0.85)
+	events, unsubscribe := o.events.Subscribe(8)
+	defer unsubscribe()
+
+	preview, err := o.PreviewChat(context.Background(), ChatSyncRequest{AgentID: agent.ID, Message: "hi"})
+	if err != nil {
+		t.Fatalf("PreviewChat: %v", err)
+	}
+	if preview.Model != "mock/cheap" {
+		t.Errorf("model = %q, want the downshifted mock/cheap", preview.Model)
+	}
+	for len(events) > 0 {
+		if ev := <-events; ev.Type == EventBudgetThreshold {
+			t.Errorf("preview published %+v", ev)
+		}
+	}
+
+	// The alert is still raised by the first real call
+	if _, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: agent.ID, Message: "hi"}); err != nil {
+		t.Fatalf("ChatSync: %v", err)
+	}
+	var alerted bool
+	for len(events) > 0 {
+		if ev := <-events; ev.Type == EventBudgetThreshold {
+			alerted = true
+		}
+	}
+	if !alerted {
+		t.Error("no budget alert after the preview")
+	}
+}
+
+func TestPreviewChat_RefusesUnhealthyPinnedModel(t *testing.T) {
+	o, _, _ := newPinTestOrchestrator(t, true)
+	_, err := o.PreviewChat(context.Background(), ChatSyncRequest{AgentID: "test-agent", Message: "hi"})
+	if !errors.Is(err, ErrPinnedModelUnhealthy) {
+		t.Errorf("err = %v, want ErrPinnedModelUnhealthy", err)
+	}
+}
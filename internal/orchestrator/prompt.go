@@ -0,0 +1,265 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/memory"
+	"github.com/clawinfra/evoclaw/internal/skillbank"
+)
+
+const (
+	// defaultPromptTokenBudget bounds the composed system prompt.
+	defaultPromptTokenBudget = 2000
+	defaultPromptSkills      = 3
+	defaultPromptMemories    = 5
+	defaultPromptMistakes    = 3
+)
+
+// memoryRetriever is the part of memory.Manager the composer needs.
+type memoryRetriever interface {
+	Retrieve(ctx context.Context, query string, maxResults int) ([]*memory.WarmEntry, error)
+}
+
+// mistakeLister is the part of skillbank.Store the composer needs.
+type mistakeLister interface {
+	ListMistakes(taskType string) ([]skillbank.CommonMistake, error)
+}
+
+// PromptComposer assembles an agent's system prompt from its base prompt,
+// the style directives in its genome, relevant skills and common mistakes
+// from the skillbank and relevant memories, keeping the result within a
+// token budget.
+//
+// Sections are added in priority order — base prompt, style, skills,
+// memories — and a section that would exceed the budget is dropped (memories
+// are trimmed one at a time). The base prompt is always kept.
+type PromptComposer struct {
+	injector    skillbank.Injector
+	skills      skillbank.Retriever
+	memories    memoryRetriever
+	tokenBudget int
+	maxSkills   int
+	maxMemories int
+
+	mistakes    mistakeLister
+	maxMistakes int
+}
+
+// NewPromptComposer creates a composer. skills and memories may be nil to
+// skip those sections; a tokenBudget of zero or less uses the default.
+func NewPromptComposer(skills skillbank.Retriever, memories memoryRetriever, tokenBudget int) *PromptComposer {
+	if tokenBudget <= 0 {
+		tokenBudget = defaultPromptTokenBudget
+	}
+	return &PromptComposer{
+		injector:    skillbank.NewInjector(),
+		skills:      skills,
+		memories:    memories,
+		tokenBudget: tokenBudget,
+		maxSkills:   defaultPromptSkills,
+		maxMemories: defaultPromptMemories,
+		maxMistakes: defaultPromptMistakes,
+	}
+}
+
+// WithMistakes adds the most recent common mistakes for the agent's type
+// (and general ones) alongside its skills.
+func (pc *PromptComposer) WithMistakes(m mistakeLister) *PromptComposer {
+	pc.mistakes = m
+	return pc
+}
+
+// estimateTokens approximates token count at ~4 characters per token.
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// Compose returns the system prompt for agent answering query.
+func (pc *PromptComposer) Compose(ctx context.Context, agent *AgentState, query string) string {
+	agent.mu.RLock()
+	base := agent.Def.SystemPrompt
+	genome := agent.Def.Genome
+	taskType := agentTaskType(agent.Def)
+	agent.mu.RUnlock()
+	return pc.compose(ctx, base, genome, taskType, query)
+}
+
+// compose builds the prompt from an explicit base prompt, so callers can
+// substitute an evolved one for the agent's configured prompt.
+func (pc *PromptComposer) compose(ctx context.Context, base string, genome *config.Genome, taskType, query string) string {
+	sections := []string{}
+	if base != "" {
+		sections = append(sections, base)
+	}
+	used := estimateTokens(base)
+
+	add := func(section string) bool {
+		if section == "" {
+			return false
+		}
+		cost := estimateTokens(section) + 1
+		if used+cost > pc.tokenBudget {
+			return false
+		}
+		sections = append(sections, section)
+		used += cost
+		return true
+	}
+
+	add(styleDirectives(genome))
+
+	var found []skillbank.Skill
+	if pc.skills != nil && query != "" {
+		if s, err := pc.skills.Retrieve(ctx, query, pc.maxSkills); err == nil {
+			found = s
+		}
+	}
+	add(strings.TrimRight(pc.injector.FormatForPrompt(found, pc.recentMistakes(taskType)), "\n"))
+
+	if pc.memories != nil && query != "" {
+		entries, err := pc.memories.Retrieve(ctx, query, pc.maxMemories)
+		if err == nil {
+			// Trim from the end until the block fits the remaining budget.
+			for n := min(len(entries), pc.maxMemories); n > 0; n-- {
+				if add(formatMemories(entries[:n])) {
+					break
+				}
+			}
+		}
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+// recentMistakes returns the newest mistakes recorded for taskType or for
+// no task type in particular.
+func (pc *PromptComposer) recentMistakes(taskType string) []skillbank.CommonMistake {
+	if pc.mistakes == nil {
+		return nil
+	}
+	all, err := pc.mistakes.ListMistakes("")
+	if err != nil {
+		return nil
+	}
+	var out []skillbank.CommonMistake
+	for _, m := range all {
+		if m.TaskType == "" || m.TaskType == taskType {
+			out = append(out, m)
+		}
+	}
+	// IDs start with their creation time, so the newest sort last.
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	if len(out) > pc.maxMistakes {
+		out = out[len(out)-pc.maxMistakes:]
+	}
+	return out
+}
+
+// styleDirectives turns the genome's behavioral traits into prompt guidance.
+func styleDirectives(g *config.Genome) string {
+	if g == nil {
+		return ""
+	}
+
+	var lines []string
+	if g.Identity.Voice != "" {
+		lines = append(lines, fmt.Sprintf("- Voice: %s", g.Identity.Voice))
+	}
+	if g.Behavior.PromptStyle != "" {
+		lines = append(lines, fmt.Sprintf("- Style: %s", g.Behavior.PromptStyle))
+	}
+	switch v := g.Behavior.Verbosity; {
+	case v > 0 && v < 0.34:
+		lines = append(lines, "- Keep answers brief and to the point.")
+	case v > 0.66:
+		lines = append(lines, "- Give thorough, detailed answers.")
+	}
+	for _, p := range g.Behavior.ResponsePatterns {
+		if p != "" {
+			lines = append(lines, "- "+p)
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return "## Response Style\n" + strings.Join(lines, "\n")
+}
+
+// formatMemories renders memory entries as a markdown block.
+func formatMemories(entries []*memory.WarmEntry) string {
+	var sb strings.Builder
+	for _, e := range entries {
+		if e == nil || e.Content == nil || e.Content.Fact == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "- %s\n", e.Content.Fact)
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return "## Relevant Memories\n" + strings.TrimRight(sb.String(), "\n")
+}
+
+// SetPromptComposer sets the composer used to build system prompts. With no
+// composer the agent's configured prompt is sent verbatim.
+func (o *Orchestrator) SetPromptComposer(pc *PromptComposer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.promptComposer = pc
+}
+
+// systemPrompt returns the system prompt to send for agent answering query.
+// An evolved strategy prompt replaces the configured one as the base, and
+// the genome's verified constraints follow it; both are always kept.
+func (o *Orchestrator) systemPrompt(ctx context.Context, agent *AgentState, query string) string {
+	base := o.baseSystemPrompt(agent)
+	if g := o.guardrails(agent); g != "" {
+		if base != "" {
+			base += "\n\n"
+		}
+		base += g
+	}
+
+	o.mu.RLock()
+	pc := o.promptComposer
+	o.mu.RUnlock()
+	if pc == nil {
+		return base
+	}
+
+	agent.mu.RLock()
+	genome := agent.Def.Genome
+	taskType := agentTaskType(agent.Def)
+	agent.mu.RUnlock()
+	return pc.compose(ctx, base, genome, taskType, query)
+}
+
+// initPromptComposer installs a default composer backed by the RSI
+// skillbank and tiered memory, unless one was set explicitly.
+func (o *Orchestrator) initPromptComposer() {
+	o.mu.RLock()
+	set := o.promptComposer != nil
+	o.mu.RUnlock()
+	if set {
+		return
+	}
+
+	var skills skillbank.Retriever
+	var mistakes mistakeLister
+	if o.rsiLoop != nil {
+		if store := o.rsiLoop.Observer().SkillStore(); store != nil {
+			skills = skillbank.NewRetriever(store, "")
+			mistakes = store
+		}
+	}
+	var mem memoryRetriever
+	if o.memory != nil {
+		mem = o.memory
+	}
+	o.SetPromptComposer(NewPromptComposer(skills, mem, 0).WithMistakes(mistakes))
+}
@@ -0,0 +1,155 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/memory"
+	"github.com/clawinfra/evoclaw/internal/skillbank"
+)
+
+type stubSkillRetriever struct{ skills []skillbank.Skill }
+
+func (r *stubSkillRetriever) Retrieve(ctx context.Context, task string, k int) ([]skillbank.Skill, error) {
+	if k < len(r.skills) {
+		return r.skills[:k], nil
+	}
+	return r.skills, nil
+}
+
+type stubMemoryRetriever struct{ facts []string }
+
+func (r *stubMemoryRetriever) Retrieve(ctx context.Context, query string, maxResults int) ([]*memory.WarmEntry, error) {
+	var out []*memory.WarmEntry
+	for _, f := range r.facts {
+		out = append(out, &memory.WarmEntry{Content: &memory.DistilledFact{Fact: f}})
+	}
+	return out, nil
+}
+
+func composerAgent() *AgentState {
+	return &AgentState{
+		ID: "composer",
+		Def: config.AgentDef{
+			ID:           "composer",
+			SystemPrompt: "You are a helpful assistant.",
+			Genome: &config.Genome{
+				Behavior: config.GenomeBehavior{
+					Verbosity:   0.1,
+					PromptStyle: "friendly and direct",
+				},
+			},
+		},
+	}
+}
+
+var testSkills = []skillbank.Skill{
+	{Title: "Check Errors", WhenToApply: "calling APIs", Principle: "Always check returned errors"},
+}
+
+func TestPromptComposer_IncludesStyleSkillsAndMemories(t *testing.T) {
+	pc := NewPromptComposer(&stubSkillRetriever{skills: testSkills}, &stubMemoryRetriever{facts: []string{"User prefers Go"}}, 0)
+
+	prompt := pc.Compose(context.Background(), composerAgent(), "how do I call the API?")
+
+	if !strings.HasPrefix(prompt, "You are a helpful assistant.") {
+		t.Errorf("base prompt should come first:\n%s", prompt)
+	}
+	for _, want := range []string{
+		"## Response Style",
+		"- Style: friendly and direct",
+		"Keep answers brief",
+		"## Relevant Skills from Past Experience",
+		"[Check Errors] When: calling APIs → Always check returned errors",
+		"## Relevant Memories\n- User prefers Go",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("composed prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestPromptComposer_VerboseStyle(t *testing.T) {
+	agent := composerAgent()
+	agent.Def.Genome.Behavior.Verbosity = 0.9
+
+	prompt := NewPromptComposer(nil, nil, 0).Compose(context.Background(), agent, "hi")
+	if !strings.Contains(prompt, "thorough, detailed answers") || strings.Contains(prompt, "brief") {
+		t.Errorf("expected verbose directive:\n%s", prompt)
+	}
+}
+
+func TestPromptComposer_NoGenomeLeavesPromptUnchanged(t *testing.T) {
+	agent := composerAgent()
+	agent.Def.Genome = nil
+
+	if got := NewPromptComposer(nil, nil, 0).Compose(context.Background(), agent, "hi"); got != agent.Def.SystemPrompt {
+		t.Errorf("Compose() = %q, want base prompt", got)
+	}
+}
+
+func TestPromptComposer_RespectsTokenBudget(t *testing.T) {
+	facts := make([]string, 5)
+	for i := range facts {
+		facts[i] = strings.Repeat("memory fact ", 10)
+	}
+	agent := composerAgent()
+	budget := 60
+	pc := NewPromptComposer(&stubSkillRetriever{skills: testSkills}, &stubMemoryRetriever{facts: facts}, budget)
+
+	prompt := pc.Compose(context.Background(), agent, "query")
+	if estimateTokens(prompt) > budget+5 {
+		t.Errorf("prompt uses ~%d tokens, budget %d:\n%s", estimateTokens(prompt), budget, prompt)
+	}
+	// Higher-priority sections survive; memories are trimmed or dropped.
+	if !strings.Contains(prompt, "## Response Style") {
+		t.Errorf("style directive should fit the budget:\n%s", prompt)
+	}
+	if strings.Count(prompt, "memory fact") >= 50 {
+		t.Error("expected memories to be trimmed")
+	}
+}
+
+// promptCapturingProvider records the system prompt it was sent.
+type promptCapturingProvider struct {
+	mu     sync.Mutex
+	prompt string
+}
+
+func (p *promptCapturingProvider) Name() string { return "mock" }
+
+func (p *promptCapturingProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prompt = req.SystemPrompt
+	return &ChatResponse{Content: "ok"}, nil
+}
+
+func (p *promptCapturingProvider) Models() []config.Model { return nil }
+
+func TestProcessDirect_UsesComposedPrompt(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	provider := &promptCapturingProvider{}
+	o.RegisterProvider(provider)
+	o.SetPromptComposer(NewPromptComposer(&stubSkillRetriever{skills: testSkills}, nil, 0))
+
+	agent := composerAgent()
+	if _, _, err := o.processDirect(agent, Message{Content: "call the API"}, "mock/mock-model-1"); err != nil {
+		t.Fatalf("processDirect() error: %v", err)
+	}
+	if !strings.Contains(provider.prompt, "## Relevant Skills from Past Experience") {
+		t.Errorf("provider got prompt without skills:\n%s", provider.prompt)
+	}
+
+	// Without a composer the configured prompt is sent verbatim.
+	o.SetPromptComposer(nil)
+	if _, _, err := o.processDirect(agent, Message{Content: "hi"}, "mock/mock-model-1"); err != nil {
+		t.Fatal(err)
+	}
+	if provider.prompt != agent.Def.SystemPrompt {
+		t.Errorf("prompt = %q, want verbatim base prompt", provider.prompt)
+	}
+}
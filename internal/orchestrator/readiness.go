@@ -0,0 +1,110 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SubsystemStatus reports whether one orchestrator subsystem is ready to
+// serve traffic.
+type SubsystemStatus struct {
+	Ready  bool   `json:"ready"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// connectionReporter is implemented by channels that hold a connection to an
+// external broker, such as MQTT.
+type connectionReporter interface {
+	IsConnected() bool
+}
+
+// Readiness reports the state of each subsystem needed to serve requests:
+// model providers, messaging channels and, when on-chain integration is
+// enabled, the connected chains. The orchestrator is ready only if every
+// entry is ready.
+func (o *Orchestrator) Readiness() map[string]SubsystemStatus {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	status := map[string]SubsystemStatus{
+		"providers": o.providerReadinessLocked(),
+		"channels":  o.channelReadinessLocked(),
+	}
+	if o.cfg != nil && o.cfg.OnChain.Enabled {
+		status["chains"] = o.chainReadiness()
+	}
+	return status
+}
+
+// providerReadinessLocked checks that at least one provider is registered and
+// that the health registry has not marked every one of its models degraded.
+// Caller must hold o.mu.
+func (o *Orchestrator) providerReadinessLocked() SubsystemStatus {
+	if len(o.providers) == 0 {
+		return SubsystemStatus{Detail: "no providers registered"}
+	}
+	if o.healthRegistry == nil {
+		return SubsystemStatus{Ready: true, Detail: fmt.Sprintf("%d provider(s) registered", len(o.providers))}
+	}
+
+	total, healthy := 0, 0
+	for name, p := range o.providers {
+		for _, m := range p.Models() {
+			total++
+			if o.healthRegistry.IsHealthy(name + "/" + m.ID) {
+				healthy++
+			}
+		}
+	}
+	if total > 0 && healthy == 0 {
+		return SubsystemStatus{Detail: fmt.Sprintf("all %d model(s) degraded", total)}
+	}
+	return SubsystemStatus{Ready: true, Detail: fmt.Sprintf("%d/%d model(s) healthy", healthy, total)}
+}
+
+// channelReadinessLocked checks that every registered channel has been
+// started and, for connection-oriented channels, is still connected.
+// Caller must hold o.mu.
+func (o *Orchestrator) channelReadinessLocked() SubsystemStatus {
+	if len(o.channels) == 0 {
+		return SubsystemStatus{Detail: "no channels registered"}
+	}
+
+	var notReady []string
+	for name, ch := range o.channels {
+		if !o.startedChannels[name] {
+			notReady = append(notReady, name+" not started")
+			continue
+		}
+		if cr, ok := ch.(connectionReporter); ok && !cr.IsConnected() {
+			notReady = append(notReady, name+" disconnected")
+		}
+	}
+	if len(notReady) > 0 {
+		sort.Strings(notReady)
+		return SubsystemStatus{Detail: strings.Join(notReady, ", ")}
+	}
+	return SubsystemStatus{Ready: true, Detail: fmt.Sprintf("%d channel(s) started", len(o.channels))}
+}
+
+// chainReadiness checks that every registered chain adapter is connected.
+func (o *Orchestrator) chainReadiness() SubsystemStatus {
+	if o.chainRegistry == nil {
+		return SubsystemStatus{Detail: "on-chain integration not initialised"}
+	}
+
+	ids := o.chainRegistry.ListChains()
+	sort.Strings(ids)
+	var disconnected []string
+	for _, id := range ids {
+		adapter, err := o.chainRegistry.Get(id)
+		if err != nil || !adapter.IsConnected() {
+			disconnected = append(disconnected, id)
+		}
+	}
+	if len(disconnected) > 0 {
+		return SubsystemStatus{Detail: "disconnected: " + strings.Join(disconnected, ", ")}
+	}
+	return SubsystemStatus{Ready: true, Detail: fmt.Sprintf("%d chain(s) connected", len(ids))}
+}
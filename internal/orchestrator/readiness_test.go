@@ -0,0 +1,104 @@
+package orchestrator
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func newReadinessTestOrchestrator(t *testing.T) *Orchestrator {
+	t.Helper()
+	cfg := &config.Config{
+		Models: config.ModelsConfig{
+			Health: config.ModelHealthConfig{
+				PersistPath:      t.TempDir() + "/health.json",
+				FailureThreshold: 1,
+			},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	return New(cfg, logger)
+}
+
+func TestReadinessBeforeStart(t *testing.T) {
+	o := newReadinessTestOrchestrator(t)
+
+	status := o.Readiness()
+	if status["providers"].Ready {
+		t.Error("providers should not be ready with none registered")
+	}
+	if status["channels"].Ready {
+		t.Error("channels should not be ready with none registered")
+	}
+
+	o.RegisterChannel(newMockChannel("test"))
+	o.RegisterProvider(newMockProvider("mock"))
+
+	status = o.Readiness()
+	if !status["providers"].Ready {
+		t.Errorf("providers should be ready once registered: %+v", status["providers"])
+	}
+	if status["channels"].Ready {
+		t.Errorf("channels should not be ready before Start: %+v", status["channels"])
+	}
+	if _, ok := status["chains"]; ok {
+		t.Error("chains should not be reported when on-chain is disabled")
+	}
+}
+
+func TestReadinessAfterStartAndStop(t *testing.T) {
+	o := newReadinessTestOrchestrator(t)
+	o.RegisterChannel(newMockChannel("test"))
+	o.RegisterProvider(newMockProvider("mock"))
+
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	for name, s := range o.Readiness() {
+		if !s.Ready {
+			t.Errorf("%s should be ready after Start: %s", name, s.Detail)
+		}
+	}
+
+	if err := o.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if o.Readiness()["channels"].Ready {
+		t.Error("channels should not be ready after Stop")
+	}
+}
+
+func TestReadinessAllModelsDegraded(t *testing.T) {
+	o := newReadinessTestOrchestrator(t)
+	o.RegisterChannel(newMockChannel("test"))
+	o.RegisterProvider(newMockProvider("mock"))
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer o.Stop()
+
+	o.healthRegistry.RecordFailure("mock/mock-model-1", "server_error")
+	if !o.Readiness()["providers"].Ready {
+		t.Error("providers should stay ready while one model is healthy")
+	}
+
+	o.healthRegistry.RecordFailure("mock/mock-model-2", "server_error")
+	if s := o.Readiness()["providers"]; s.Ready {
+		t.Errorf("providers should not be ready when every model is degraded: %+v", s)
+	}
+}
+
+func TestReadinessChainsRequired(t *testing.T) {
+	o := newReadinessTestOrchestrator(t)
+	o.cfg.OnChain.Enabled = true
+
+	s, ok := o.Readiness()["chains"]
+	if !ok {
+		t.Fatal("chains should be reported when on-chain is enabled")
+	}
+	if s.Ready {
+		t.Error("chains should not be ready before the registry is initialised")
+	}
+}
@@ -0,0 +1,46 @@
+package orchestrator
+
+// Reasoning modes for config.ModelsConfig.Reasoning. Providers always keep
+// a reasoning model's thinking out of ChatResponse.Content, so the answer,
+// conversation memory and distillation only ever see the final text; the
+// mode decides what else happens to the thinking.
+const (
+	ReasoningHide  = "hide"
+	ReasoningLog   = "log"
+	ReasoningStore = "store"
+)
+
+// reasoningMetadataKey carries kept reasoning in Response.Metadata.
+const reasoningMetadataKey = "reasoning"
+
+// keepReasoning applies the configured reasoning mode to resp and returns
+// the reasoning to attach to the reply, which is empty unless the mode is
+// "store".
+func (o *Orchestrator) keepReasoning(agentID, model string, resp *ChatResponse) string {
+	if resp == nil || resp.ReasoningContent == "" {
+		return ""
+	}
+	switch o.cfg.Models.Reasoning {
+	case ReasoningLog:
+		o.logger.Info("model reasoning",
+			"agent", agentID,
+			"model", model,
+			"tokens", resp.ReasoningTokens,
+			"reasoning", resp.ReasoningContent,
+		)
+	case ReasoningStore:
+		return resp.ReasoningContent
+	}
+	return ""
+}
+
+// setReasoning records kept reasoning on resp's metadata.
+func setReasoning(resp *Response, reasoning string) {
+	if reasoning == "" {
+		return
+	}
+	if resp.Metadata == nil {
+		resp.Metadata = make(map[string]string)
+	}
+	resp.Metadata[reasoningMetadataKey] = reasoning
+}
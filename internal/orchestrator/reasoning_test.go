@@ -0,0 +1,95 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// reasoningProvider answers like a reasoning model: thinking separated
+// from the final answer.
+type reasoningProvider struct{}
+
+func (p *reasoningProvider) Name() string { return "mock" }
+
+func (p *reasoningProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	return &ChatResponse{
+		Content:          "final answer",
+		Model:            req.Model,
+		TokensInput:      10,
+		TokensOutput:     40,
+		ReasoningContent: "let me think step by step",
+		ReasoningTokens:  30,
+	}, nil
+}
+
+func (p *reasoningProvider) Models() []config.Model { return nil }
+
+func newReasoningOrchestrator(mode string) (*Orchestrator, *AgentState) {
+	cfg := testConfig()
+	cfg.Models.Reasoning = mode
+	o := New(cfg, testLogger())
+	o.RegisterProvider(&reasoningProvider{})
+	o.mu.Lock()
+	agent := o.initAgentLocked(cfg.Agents[0])
+	o.mu.Unlock()
+	return o, agent
+}
+
+func TestReasoningHiddenByDefault(t *testing.T) {
+	for _, mode := range []string{"", ReasoningHide, ReasoningLog} {
+		o, agent := newReasoningOrchestrator(mode)
+
+		resp, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: agent.ID, Message: "hi"})
+		if err != nil {
+			t.Fatalf("%q: ChatSync: %v", mode, err)
+		}
+		if resp.Response != "final answer" || resp.Reasoning != "" {
+			t.Errorf("%q: response = %q, reasoning = %q", mode, resp.Response, resp.Reasoning)
+		}
+		if resp.ReasoningTokens != 30 {
+			t.Errorf("%q: reasoning tokens = %d, want 30", mode, resp.ReasoningTokens)
+		}
+
+		reply := processAndReceive(t, o, agent)
+		if reply.Content != "final answer" {
+			t.Errorf("%q: reply content = %q", mode, reply.Content)
+		}
+		if _, ok := reply.Metadata[reasoningMetadataKey]; ok {
+			t.Errorf("%q: reasoning leaked into reply metadata", mode)
+		}
+	}
+}
+
+func TestReasoningStoredWhenConfigured(t *testing.T) {
+	o, agent := newReasoningOrchestrator(ReasoningStore)
+
+	resp, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: agent.ID, Message: "hi"})
+	if err != nil {
+		t.Fatalf("ChatSync: %v", err)
+	}
+	if resp.Response != "final answer" || resp.Reasoning != "let me think step by step" {
+		t.Errorf("response = %q, reasoning = %q", resp.Response, resp.Reasoning)
+	}
+
+	reply := processAndReceive(t, o, agent)
+	if reply.Content != "final answer" {
+		t.Errorf("reply content = %q", reply.Content)
+	}
+	if got := reply.Metadata[reasoningMetadataKey]; got != "let me think step by step" {
+		t.Errorf("reply reasoning = %q", got)
+	}
+}
+
+func TestDistillationUsesFinalContentOnly(t *testing.T) {
+	o, _ := newReasoningOrchestrator(ReasoningStore)
+
+	out, err := o.distillationLLMFunc("mock/mock-model-1")(context.Background(), "sys", "user")
+	if err != nil {
+		t.Fatalf("llm: %v", err)
+	}
+	if out != "final answer" {
+		t.Errorf("distillation got %q, want only the final answer", out)
+	}
+}
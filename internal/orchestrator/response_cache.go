@@ -0,0 +1,194 @@
+package orchestrator
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// Response cache defaults used when models.responseCache leaves them zero.
+const (
+	defaultResponseCacheTTL            = 5 * time.Minute
+	defaultResponseCacheEntries        = 1000
+	defaultResponseCacheMaxTemperature = 0.2
+)
+
+// ResponseCacheStats reports the provider response cache for /api/status.
+type ResponseCacheStats struct {
+	Entries int   `json:"entries"`
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+}
+
+// responseCache is a bounded LRU of provider responses keyed by the exact
+// request. Entries expire after ttl.
+type responseCache struct {
+	ttl            time.Duration
+	maxEntries     int
+	maxTemperature float64
+
+	mu      sync.Mutex
+	order   *list.List // front = most recently used
+	entries map[string]*list.Element
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type cachedResponse struct {
+	key     string
+	model   string
+	resp    ChatResponse
+	expires time.Time
+}
+
+func newResponseCache(cfg config.ResponseCacheConfig) *responseCache {
+	c := &responseCache{
+		ttl:            time.Duration(cfg.TTLSec) * time.Second,
+		maxEntries:     cfg.MaxEntries,
+		maxTemperature: cfg.MaxTemperature,
+		order:          list.New(),
+		entries:        make(map[string]*list.Element),
+	}
+	if c.ttl <= 0 {
+		c.ttl = defaultResponseCacheTTL
+	}
+	if c.maxEntries <= 0 {
+		c.maxEntries = defaultResponseCacheEntries
+	}
+	if c.maxTemperature <= 0 {
+		c.maxTemperature = defaultResponseCacheMaxTemperature
+	}
+	return c
+}
+
+// cacheable reports whether req may be answered from the cache: sampling
+// must be near-deterministic (temperature 0 included), and tool use is
+// never replayed.
+func (c *responseCache) cacheable(req ChatRequest) bool {
+	return req.Temperature <= c.maxTemperature && len(req.Tools) == 0
+}
+
+// responseCacheKey hashes everything that shapes the answer: the full
+// "provider/model", system prompt, messages and sampling parameters.
+func responseCacheKey(model string, req ChatRequest) string {
+	data, _ := json.Marshal(struct {
+		Model        string
+		SystemPrompt string
+		Messages     []ChatMessage
+		MaxTokens    int
+		Temperature  float64
+		TopP         float64
+	}{model, req.SystemPrompt, req.Messages, req.MaxTokens, req.Temperature, req.TopP})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *responseCache) get(key string) (ChatResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses.Add(1)
+		return ChatResponse{}, false
+	}
+	entry := el.Value.(*cachedResponse)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		c.misses.Add(1)
+		return ChatResponse{}, false
+	}
+	c.order.MoveToFront(el)
+	c.hits.Add(1)
+	return entry.resp, true
+}
+
+func (c *responseCache) put(key, model string, resp ChatResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cachedResponse{key: key, model: model, resp: resp, expires: time.Now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+// dropModel removes every cached response from model and returns how many
+// were removed.
+func (c *responseCache) dropModel(model string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for key, el := range c.entries {
+		if el.Value.(*cachedResponse).model == model {
+			c.order.Remove(el)
+			delete(c.entries, key)
+			n++
+		}
+	}
+	return n
+}
+
+func (c *responseCache) stats() ResponseCacheStats {
+	c.mu.Lock()
+	n := c.order.Len()
+	c.mu.Unlock()
+	return ResponseCacheStats{Entries: n, Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// ResponseCacheStats returns the response cache counters, or nil when
+// models.responseCache is disabled.
+func (o *Orchestrator) ResponseCacheStats() *ResponseCacheStats {
+	if o.responseCache == nil {
+		return nil
+	}
+	s := o.responseCache.stats()
+	return &s
+}
+
+// chatProvider sends req to provider, answering exact repeats of cacheable
+// requests from the response cache. A cached answer costs nothing, so it
+// reports zero tokens; the hit is counted in the agent's metrics.
+func (o *Orchestrator) chatProvider(ctx context.Context, agent *AgentState, provider ModelProvider, model string, req ChatRequest) (*ChatResponse, bool, error) {
+	c := o.responseCache
+	agent.mu.RLock()
+	optOut := agent.Def.NoResponseCache
+	agent.mu.RUnlock()
+	if c == nil || optOut || !c.cacheable(req) {
+		resp, err := provider.Chat(ctx, req)
+		return resp, false, err
+	}
+
+	key := responseCacheKey(model, req)
+	if cached, ok := c.get(key); ok {
+		agent.mu.Lock()
+		agent.Metrics.CacheHits++
+		agent.mu.Unlock()
+		cached.TokensInput, cached.TokensOutput = 0, 0
+		return &cached, true, nil
+	}
+
+	resp, err := provider.Chat(ctx, req)
+	if err == nil && len(resp.ToolCalls) == 0 {
+		c.put(key, model, *resp)
+	}
+	return resp, false, err
+}
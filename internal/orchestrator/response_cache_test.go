@@ -0,0 +1,152 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+func newCachingOrchestrator(t *testing.T, temperature float64) (*Orchestrator, *mockProvider) {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Models.ResponseCache = config.ResponseCacheConfig{Enabled: true}
+	cfg.Agents[0].Temperature = config.Float(temperature)
+	o := New(cfg, testLogger())
+	p := newMockProvider("mock")
+	o.RegisterProvider(p)
+	o.mu.Lock()
+	o.initAgentLocked(cfg.Agents[0])
+	o.mu.Unlock()
+	return o, p
+}
+
+func TestResponseCache_LowTemperatureRepeatHits(t *testing.T) {
+	o, p := newCachingOrchestrator(t, 0.1)
+	req := ChatSyncRequest{AgentID: "test-agent", Message: "what is 2+2?"}
+
+	first, err := o.ChatSync(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ChatSync: %v", err)
+	}
+	second, err := o.ChatSync(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ChatSync: %v", err)
+	}
+
+	if n := p.getCalls(); n != 1 {
+		t.Errorf("provider called %d times, want 1", n)
+	}
+	if first.Cached || !second.Cached {
+		t.Errorf("cached = %v, %v; want false, true", first.Cached, second.Cached)
+	}
+	if second.Response != first.Response || second.TokensInput != 0 || second.CostUSD != 0 {
+		t.Errorf("cached response = %+v", second)
+	}
+	if m, _ := o.GetAgentMetrics("test-agent"); m.CacheHits != 1 {
+		t.Errorf("agent cache hits = %d, want 1", m.CacheHits)
+	} else if want := int64(first.TokensInput + first.TokensOutput); m.TokensUsed != want {
+		t.Errorf("tokens used = %d, want only the first call's %d", m.TokensUsed, want)
+	}
+	if s := o.ResponseCacheStats(); s == nil || s.Hits != 1 || s.Misses != 1 || s.Entries != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+
+	// A different message is a different request.
+	if _, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: "test-agent", Message: "and 3+3?"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := p.getCalls(); n != 2 {
+		t.Errorf("provider called %d times, want 2", n)
+	}
+}
+
+func TestResponseCache_ZeroTemperatureRepeatHits(t *testing.T) {
+	o, p := newCachingOrchestrator(t, 0)
+	req := ChatSyncRequest{AgentID: "test-agent", Message: "what is 2+2?"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := o.ChatSync(context.Background(), req); err != nil {
+			t.Fatalf("ChatSync: %v", err)
+		}
+	}
+	if n := p.getCalls(); n != 1 {
+		t.Errorf("provider called %d times, want 1", n)
+	}
+	if s := o.ResponseCacheStats(); s == nil || s.Hits != 1 {
+		t.Errorf("stats = %+v, want one hit", s)
+	}
+}
+
+func TestResponseCache_Cacheable(t *testing.T) {
+	c := newResponseCache(config.ResponseCacheConfig{Enabled: true})
+	if !c.cacheable(ChatRequest{}) {
+		t.Error("temperature 0 request is not cacheable")
+	}
+	if c.cacheable(ChatRequest{Temperature: 0.5}) {
+		t.Error("request above maxTemperature is cacheable")
+	}
+	if c.cacheable(ChatRequest{Tools: []ToolSchema{{}}}) {
+		t.Error("request with tools is cacheable")
+	}
+}
+
+func TestResponseCache_HighTemperatureCallsThrough(t *testing.T) {
+	o, p := newCachingOrchestrator(t, 0.9)
+	req := ChatSyncRequest{AgentID: "test-agent", Message: "write a poem"}
+
+	for i := 0; i < 3; i++ {
+		resp, err := o.ChatSync(context.Background(), req)
+		if err != nil {
+			t.Fatalf("ChatSync: %v", err)
+		}
+		if resp.Cached {
+			t.Error("high-temperature response served from cache")
+		}
+	}
+	if n := p.getCalls(); n != 3 {
+		t.Errorf("provider called %d times, want 3", n)
+	}
+}
+
+func TestResponseCache_AgentOptOut(t *testing.T) {
+	o, p := newCachingOrchestrator(t, 0.1)
+	o.agents["test-agent"].Def.NoResponseCache = true
+	req := ChatSyncRequest{AgentID: "test-agent", Message: "what is 2+2?"}
+
+	_, _ = o.ChatSync(context.Background(), req)
+	_, _ = o.ChatSync(context.Background(), req)
+	if n := p.getCalls(); n != 2 {
+		t.Errorf("provider called %d times, want 2", n)
+	}
+}
+
+func TestResponseCache_ExpiryAndEviction(t *testing.T) {
+	c := newResponseCache(config.ResponseCacheConfig{MaxEntries: 2})
+
+	c.put("a", "mock/m", ChatResponse{Content: "A"})
+	c.put("b", "mock/m", ChatResponse{Content: "B"})
+	c.get("a") // a is now the most recently used
+	c.put("c", "mock/m", ChatResponse{Content: "C"})
+	if _, ok := c.get("b"); ok {
+		t.Error("least recently used entry not evicted")
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Error("recently used entry evicted")
+	}
+
+	c.entries["a"].Value.(*cachedResponse).expires = time.Now().Add(-time.Second)
+	if _, ok := c.get("a"); ok {
+		t.Error("expired entry returned")
+	}
+	if s := c.stats(); s.Entries != 1 {
+		t.Errorf("entries = %d, want 1 after expiry", s.Entries)
+	}
+}
+
+func TestResponseCache_Disabled(t *testing.T) {
+	if s := New(testConfig(), testLogger()).ResponseCacheStats(); s != nil {
+		t.Errorf("stats = %+v, want nil when disabled", s)
+	}
+}
@@ -0,0 +1,51 @@
+package orchestrator
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// safeGo runs fn in a new goroutine. A panic in fn is recovered, logged
+// with its stack and counted under name instead of crashing the process.
+// Use it for fire-and-forget work; long-running loops should not swallow
+// their own panics.
+func (o *Orchestrator) safeGo(name string, fn func()) {
+	go o.runSafe(name, fn)
+}
+
+// runSafe calls fn, recovering and recording any panic.
+func (o *Orchestrator) runSafe(name string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			o.recordPanic(name, r, debug.Stack())
+		}
+	}()
+	fn()
+}
+
+func (o *Orchestrator) recordPanic(name string, r interface{}, stack []byte) {
+	o.panicMu.Lock()
+	if o.panics == nil {
+		o.panics = make(map[string]int64)
+	}
+	o.panics[name]++
+	o.panicMu.Unlock()
+
+	o.logger.Error("recovered panic in background goroutine",
+		"goroutine", name,
+		"panic", fmt.Sprint(r),
+		"stack", string(stack),
+	)
+}
+
+// PanicStats returns the number of panics recovered since startup, by
+// goroutine kind (e.g. "process", "cloud_sync").
+func (o *Orchestrator) PanicStats() map[string]int64 {
+	o.panicMu.Lock()
+	defer o.panicMu.Unlock()
+	stats := make(map[string]int64, len(o.panics))
+	for name, n := range o.panics {
+		stats[name] = n
+	}
+	return stats
+}
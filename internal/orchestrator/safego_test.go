@@ -0,0 +1,69 @@
+package orchestrator
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockPanicProvider panics on its first call and answers normally after.
+type mockPanicProvider struct {
+	*mockProvider
+	panicked atomic.Bool
+}
+
+func (p *mockPanicProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if p.panicked.CompareAndSwap(false, true) {
+		panic("provider exploded")
+	}
+	return p.mockProvider.Chat(ctx, req)
+}
+
+func TestSafeGo_RecoversAndRecordsPanic(t *testing.T) {
+	o := New(testConfig(), testLogger())
+
+	done := make(chan struct{})
+	o.safeGo("test", func() {
+		defer close(done)
+		panic("boom")
+	})
+	<-done
+
+	deadline := time.Now().Add(time.Second)
+	for o.PanicStats()["test"] != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("panic not recorded: %v", o.PanicStats())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestProcessingPanicDoesNotStopOrchestrator(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	ch := newMockChannel("test")
+	o.RegisterChannel(ch)
+	o.RegisterProvider(&mockPanicProvider{mockProvider: newMockProvider("mock")})
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = o.Stop() }()
+
+	o.handleMessage(Message{ID: "m1", Channel: "test", From: "user", To: "test-agent", Content: "hello"})
+	if !o.waitInflight(time.Now().Add(2 * time.Second)) {
+		t.Fatal("panicking message never finished")
+	}
+	if n := o.PanicStats()["process"]; n != 1 {
+		t.Fatalf("recorded process panics = %d, want 1", n)
+	}
+
+	// The orchestrator keeps serving messages after the panic.
+	o.handleMessage(Message{ID: "m2", Channel: "test", From: "user", To: "test-agent", Content: "hello again"})
+	deadline := time.Now().Add(2 * time.Second)
+	for len(ch.getSent()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no response after a recovered panic")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,31 @@
+package orchestrator
+
+import "errors"
+
+// ErrSafeMode is returned for state changes refused while safe mode is on.
+var ErrSafeMode = errors.New("safe mode: state changes are disabled")
+
+// SetSafeMode turns safe mode on or off. In safe mode agents keep answering
+// messages, but everything that changes lasting state is frozen: strategy
+// and genome evaluation and mutation, on-chain action reports and cloud
+// sync writes. It is meant for observing a misbehaving deployment without
+// it evolving underneath you.
+func (o *Orchestrator) SetSafeMode(on bool) {
+	if o.safeMode.Swap(on) == on {
+		return
+	}
+	if o.cloudSync != nil {
+		o.cloudSync.SetPaused(on)
+	}
+	if on {
+		o.logger.Warn("safe mode enabled: evolution, on-chain and cloud sync writes frozen")
+	} else {
+		o.logger.Info("safe mode disabled")
+	}
+	o.events.Publish(Event{Type: EventSafeMode, Data: map[string]interface{}{"enabled": on}})
+}
+
+// SafeMode reports whether safe mode is on.
+func (o *Orchestrator) SafeMode() bool {
+	return o.safeMode.Load()
+}
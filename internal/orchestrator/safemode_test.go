@@ -0,0 +1,101 @@
+package orchestrator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/onchain"
+	"github.com/clawinfra/evoclaw/internal/tracing"
+)
+
+func TestSafeMode_FromConfigAndToggle(t *testing.T) {
+	cfg := testConfig()
+	cfg.Server.SafeMode = true
+	o := New(cfg, testLogger())
+	if !o.SafeMode() {
+		t.Fatal("safe mode should start on when configured")
+	}
+
+	events, unsubscribe := o.Events().Subscribe(4)
+	defer unsubscribe()
+	o.SetSafeMode(false)
+	o.SetSafeMode(false) // no change, no event
+
+	select {
+	case ev := <-events:
+		if ev.Type != EventSafeMode || ev.Data["enabled"] != false {
+			t.Errorf("event = %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no safe mode event published")
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("unexpected second event %+v", ev)
+	default:
+	}
+}
+
+func TestSafeMode_SkipsEvolution(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	evo := newMockEvolution()
+	o.SetEvolutionEngine(evo)
+	agent := &AgentState{
+		ID:  "test-agent",
+		Def: config.AgentDef{ID: "test-agent", Genome: &config.Genome{}},
+		Metrics: AgentMetrics{
+			TotalActions: 10,
+			Custom:       map[string]float64{},
+		},
+	}
+	o.agents["test-agent"] = agent
+	o.SetSafeMode(true)
+
+	if _, err := o.TriggerEvolution("test-agent", true); !errors.Is(err, ErrSafeMode) {
+		t.Errorf("forced trigger err = %v, want ErrSafeMode", err)
+	}
+	if err := o.mutateAgent(agent, 0.1, false); !errors.Is(err, ErrSafeMode) {
+		t.Errorf("mutateAgent err = %v, want ErrSafeMode", err)
+	}
+	o.evaluateAgents()
+
+	evo.mu.Lock()
+	mutations, evaluated := evo.mutations, len(evo.fitness)
+	evo.mu.Unlock()
+	if mutations != 0 || evaluated != 0 {
+		t.Errorf("engine saw %d mutations and %d evaluations in safe mode, want none", mutations, evaluated)
+	}
+	if agent.Metrics.TotalActions != 10 {
+		t.Error("metrics were reset although no mutation happened")
+	}
+
+	o.SetSafeMode(false)
+	if _, err := o.TriggerEvolution("test-agent", true); err != nil {
+		t.Fatalf("trigger after leaving safe mode: %v", err)
+	}
+	if evo.mutations != 1 {
+		t.Errorf("mutations = %d, want 1", evo.mutations)
+	}
+}
+
+func TestSafeMode_SkipsOnChainReport(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	o.chainRegistry = onchain.NewChainRegistry(testLogger())
+	exp := tracing.NewMemoryExporter()
+	o.SetTracer(tracing.New(exp))
+	action := onchain.Action{AgentDID: "test-agent", Chain: "bsc", ActionType: "chat", Success: true, Timestamp: time.Now()}
+
+	o.SetSafeMode(true)
+	o.reportOnChain(Message{}, action)
+	if spans := spansByName(exp.Spans())["chain.report"]; len(spans) != 0 {
+		t.Fatalf("on-chain report attempted in safe mode: %+v", spans)
+	}
+
+	o.SetSafeMode(false)
+	o.reportOnChain(Message{}, action)
+	if spans := spansByName(exp.Spans())["chain.report"]; len(spans) != 1 {
+		t.Errorf("got %d chain.report spans after leaving safe mode, want 1", len(spans))
+	}
+}
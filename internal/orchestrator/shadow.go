@@ -0,0 +1,213 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// shadowTimeout bounds a shadow model call.
+	shadowTimeout = 2 * time.Minute
+	// maxShadowAnswer bounds each answer stored in a comparison.
+	maxShadowAnswer = 1000
+	// maxShadowLogBytes is the size at which shadow.jsonl is rotated to
+	// shadow.jsonl.1, replacing the previous rotation.
+	maxShadowLogBytes = 10 << 20
+)
+
+// ShadowComparison compares an agent's answer to a request with its shadow
+// model's answer to the same request. It is logged, one JSON object per
+// line, to <dataDir>/shadow.jsonl, which is rotated at maxShadowLogBytes.
+type ShadowComparison struct {
+	AgentID      string `json:"agent_id"`
+	Model        string `json:"model"`
+	ShadowModel  string `json:"shadow_model"`
+	LatencyMs    int64  `json:"latency_ms"`
+	TokensInput  int    `json:"tokens_input"`
+	TokensOutput int    `json:"tokens_output"`
+	// Shadow call results; only Error is set when the call failed.
+	ShadowLatencyMs    int64   `json:"shadow_latency_ms"`
+	ShadowTokensInput  int     `json:"shadow_tokens_input"`
+	ShadowTokensOutput int     `json:"shadow_tokens_output"`
+	ShadowCostUSD      float64 `json:"shadow_cost_usd"`
+	Error              string  `json:"error,omitempty"`
+	// Similarity is the share of distinct words the answers have in
+	// common, from 0 (none) to 1 (the same words).
+	Similarity   float64   `json:"similarity"`
+	Answer       string    `json:"answer"`
+	ShadowAnswer string    `json:"shadow_answer"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// startShadow sends req to the agent's shadow model in the background, if
+// it has one enabled, and logs how its answer compares with resp. The
+// shadow answer is never returned, but its cost is charged to the agent
+// like any other call. Shadowing stops once the budget would downshift or
+// refuse the shadow model.
+func (o *Orchestrator) startShadow(agent *AgentState, model string, req ChatRequest, resp *ChatResponse, latency time.Duration) {
+	agent.mu.RLock()
+	shadow := agent.Def.Shadow
+	agent.mu.RUnlock()
+	if !shadow.Enabled || shadow.Model == "" || shadow.Model == model {
+		return
+	}
+	if budgeted, err := o.applyBudget(agent, shadow.Model); err != nil || budgeted != shadow.Model {
+		o.logger.Debug("shadow call skipped by cost budget", "agent", agent.ID, "shadow_model", shadow.Model)
+		return
+	}
+
+	cmp := ShadowComparison{
+		AgentID:      agent.ID,
+		Model:        model,
+		ShadowModel:  shadow.Model,
+		LatencyMs:    latency.Milliseconds(),
+		TokensInput:  resp.TokensInput,
+		TokensOutput: resp.TokensOutput,
+		Answer:       truncateRunes(resp.Content, maxShadowAnswer),
+	}
+	answer := resp.Content
+
+	o.goTracked("shadow", func() {
+		shadowResp, shadowLatency, err := o.callShadow(shadow.Model, req)
+		if err != nil {
+			cmp.Error = err.Error()
+		} else {
+			cmp.ShadowLatencyMs = shadowLatency.Milliseconds()
+			cmp.ShadowTokensInput = shadowResp.TokensInput
+			cmp.ShadowTokensOutput = shadowResp.TokensOutput
+			cmp.ShadowCostUSD = o.chargeCost(agent, shadow.Model, shadowResp.TokensInput, shadowResp.TokensOutput)
+			cmp.Similarity = wordSimilarity(answer, shadowResp.Content)
+			cmp.ShadowAnswer = truncateRunes(shadowResp.Content, maxShadowAnswer)
+		}
+		o.recordShadow(cmp)
+	})
+}
+
+// callShadow sends req to model. Like a replay, it must reach that model's
+// own provider rather than a fallback.
+func (o *Orchestrator) callShadow(model string, req ChatRequest) (*ChatResponse, time.Duration, error) {
+	provider := o.findProvider(model)
+	if provider == nil || !strings.HasPrefix(model, provider.Name()) {
+		return nil, 0, fmt.Errorf("%w: %s", ErrNoProvider, model)
+	}
+	if _, id, ok := strings.Cut(model, "/"); ok {
+		req.Model = id
+	}
+
+	ctx, cancel := context.WithTimeout(o.ctx, shadowTimeout)
+	defer cancel()
+	start := time.Now()
+	resp, err := provider.Chat(ctx, req)
+	if err != nil {
+		return nil, 0, &ErrProviderFailure{Provider: provider.Name(), Model: model, Err: err}
+	}
+	return resp, time.Since(start), nil
+}
+
+// recordShadow logs cmp and appends it to the shadow log in the data dir.
+func (o *Orchestrator) recordShadow(cmp ShadowComparison) {
+	cmp.Timestamp = time.Now()
+	if cmp.Error != "" {
+		o.logger.Warn("shadow model call failed",
+			"agent", cmp.AgentID,
+			"shadow_model", cmp.ShadowModel,
+			"error", cmp.Error,
+		)
+	} else {
+		o.logger.Info("shadow comparison",
+			"agent", cmp.AgentID,
+			"model", cmp.Model,
+			"shadow_model", cmp.ShadowModel,
+			"latency_ms", cmp.LatencyMs,
+			"shadow_latency_ms", cmp.ShadowLatencyMs,
+			"similarity", cmp.Similarity,
+		)
+	}
+
+	if o.cfg == nil || o.cfg.Server.DataDir == "" {
+		return
+	}
+	data, err := json.Marshal(cmp)
+	if err != nil {
+		return
+	}
+	path := filepath.Join(o.cfg.Server.DataDir, "shadow.jsonl")
+	o.shadowMu.Lock()
+	defer o.shadowMu.Unlock()
+	if err := rotateLog(path, maxShadowLogBytes); err != nil {
+		o.logger.Warn("failed to rotate shadow log", "error", err)
+	}
+	if err := appendLine(path, data); err != nil {
+		o.logger.Warn("failed to write shadow comparison", "error", err)
+	}
+}
+
+// rotateLog renames the file at path to path+".1", replacing any earlier
+// rotation, once it has grown to maxBytes.
+func rotateLog(path string, maxBytes int64) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() < maxBytes {
+		return nil
+	}
+	return os.Rename(path, path+".1")
+}
+
+// appendLine appends data and a newline to the file at path.
+func appendLine(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// wordSimilarity is the Jaccard index of the case-folded words of a and b.
+func wordSimilarity(a, b string) float64 {
+	wa, wb := wordSet(a), wordSet(b)
+	if len(wa) == 0 && len(wb) == 0 {
+		return 1
+	}
+	common := 0
+	for w := range wa {
+		if wb[w] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(wa)+len(wb)-common)
+}
+
+func wordSet(s string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// truncateRunes cuts s to at most n runes, marking the cut with "…".
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
@@ -0,0 +1,227 @@
+package orchestrator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// gatedProvider answers only once release is closed, and reports each
+// call on started.
+type gatedProvider struct {
+	*mockProvider
+	started chan ChatRequest
+	release chan struct{}
+}
+
+func (p *gatedProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	p.started <- req
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &ChatResponse{Content: "The answer is forty two", TokensInput: 80, TokensOutput: 20}, nil
+}
+
+func newShadowOrchestrator(t *testing.T) (*Orchestrator, *AgentState, *gatedProvider) {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Server.DataDir = t.TempDir()
+	cfg.Agents[0].Shadow = config.ShadowConfig{Enabled: true, Model: "candidate/big-1"}
+	cfg.Models.Providers["candidate"] = config.ProviderConfig{
+		Models: []config.Model{{ID: "big-1", CostInput: 1, CostOutput: 2}},
+	}
+
+	o := New(cfg, testLogger())
+	primary := newMockProvider("mock")
+	primary.setResponse("mock-model-1", "The answer is 42")
+	o.RegisterProvider(primary)
+	shadow := &gatedProvider{
+		mockProvider: newMockProvider("candidate"),
+		started:      make(chan ChatRequest, 1),
+		release:      make(chan struct{}),
+	}
+	o.RegisterProvider(shadow)
+
+	o.mu.Lock()
+	agent := o.initAgentLocked(cfg.Agents[0])
+	o.mu.Unlock()
+	return o, agent, shadow
+}
+
+// readShadowLog waits for and returns the comparisons in dir's shadow log.
+func readShadowLog(t *testing.T, dir string, want int) []ShadowComparison {
+	t.Helper()
+	var got []ShadowComparison
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		got = nil
+		f, err := os.Open(filepath.Join(dir, "shadow.jsonl"))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var c ShadowComparison
+			if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+				t.Fatalf("bad shadow log line %q: %v", scanner.Text(), err)
+			}
+			got = append(got, c)
+		}
+		_ = f.Close()
+		if len(got) >= want {
+			break
+		}
+	}
+	return got
+}
+
+func TestShadowModel_ChatSyncOutOfBand(t *testing.T) {
+	o, agent, shadow := newShadowOrchestrator(t)
+
+	// The shadow call is held open, so ChatSync returning proves it
+	// doesn't wait for it.
+	resp, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: agent.ID, Message: "what is the answer?"})
+	if err != nil {
+		t.Fatalf("ChatSync: %v", err)
+	}
+	if resp.Response != "The answer is 42" || resp.Model != "mock/mock-model-1" {
+		t.Errorf("user-facing response = %q from %s, want the primary model's answer", resp.Response, resp.Model)
+	}
+
+	select {
+	case req := <-shadow.started:
+		if req.Model != "big-1" {
+			t.Errorf("shadow request model = %q, want big-1", req.Model)
+		}
+		if last := req.Messages[len(req.Messages)-1]; last.Content != "what is the answer?" {
+			t.Errorf("shadow prompt = %q, want the user's message", last.Content)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the shadow model to be called")
+	}
+	if _, err := os.Stat(filepath.Join(o.cfg.Server.DataDir, "shadow.jsonl")); err == nil {
+		t.Fatal("comparison logged before the shadow model answered")
+	}
+	close(shadow.release)
+
+	entries := readShadowLog(t, o.cfg.Server.DataDir, 1)
+	if len(entries) != 1 {
+		t.Fatalf("got %d comparisons, want 1", len(entries))
+	}
+	c := entries[0]
+	if c.AgentID != agent.ID || c.Model != "mock/mock-model-1" || c.ShadowModel != "candidate/big-1" {
+		t.Errorf("comparison identifies %s: %s vs %s", c.AgentID, c.Model, c.ShadowModel)
+	}
+	if c.TokensInput != 100 || c.ShadowTokensInput != 80 || c.ShadowTokensOutput != 20 {
+		t.Errorf("tokens = %d, shadow %d/%d", c.TokensInput, c.ShadowTokensInput, c.ShadowTokensOutput)
+	}
+	if c.ShadowCostUSD <= 0 {
+		t.Error("expected the shadow call to be priced")
+	}
+	// {the, answer, is} shared out of {the, answer, is, 42, forty, two}
+	if c.Similarity != 0.5 {
+		t.Errorf("similarity = %v, want 0.5", c.Similarity)
+	}
+	if c.Answer != "The answer is 42" || c.ShadowAnswer != "The answer is forty two" || c.Error != "" {
+		t.Errorf("unexpected comparison %+v", c)
+	}
+
+	// The primary model is unpriced, so the agent's spend is the shadow's.
+	agent.mu.RLock()
+	spent := agent.Metrics.CostUSD
+	agent.mu.RUnlock()
+	if spent != c.ShadowCostUSD {
+		t.Errorf("agent cost = %v, want the shadow call's %v", spent, c.ShadowCostUSD)
+	}
+}
+
+func TestShadowModel_SkippedPastSoftBudget(t *testing.T) {
+	o, agent, shadow := newShadowOrchestrator(t)
+	close(shadow.release)
+	agent.Def.CostBudgetUSD = 1
+	o.cfg.Models.Routing.Simple = "mock/mock-model-1"
+	o.costs.add(agent.ID, o.budgetPeriod(), 0.9)
+
+	processAndReceive(t, o, agent)
+	select {
+	case <-shadow.started:
+		t.Fatal("shadow model called past the soft budget threshold")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRotateLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shadow.jsonl")
+	if err := rotateLog(path, 10); err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := rotateLog(path, 20); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal("rotated a file under the limit")
+	}
+	if err := rotateLog(path, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("file at the limit not rotated")
+	}
+	if data, err := os.ReadFile(path + ".1"); err != nil || string(data) != "0123456789" {
+		t.Errorf("rotation = %q, %v", data, err)
+	}
+}
+
+func TestShadowModel_ProcessWithAgent(t *testing.T) {
+	o, agent, shadow := newShadowOrchestrator(t)
+	close(shadow.release)
+
+	resp := processAndReceive(t, o, agent)
+	if resp.Content != "The answer is 42" {
+		t.Errorf("response = %q, want the primary model's answer", resp.Content)
+	}
+	<-shadow.started
+	if entries := readShadowLog(t, o.cfg.Server.DataDir, 1); len(entries) != 1 {
+		t.Fatalf("got %d comparisons, want 1", len(entries))
+	}
+}
+
+func TestShadowModel_Disabled(t *testing.T) {
+	o, agent, shadow := newShadowOrchestrator(t)
+	agent.Def.Shadow.Enabled = false
+
+	if _, err := o.ChatSync(context.Background(), ChatSyncRequest{AgentID: agent.ID, Message: "hi"}); err != nil {
+		t.Fatalf("ChatSync: %v", err)
+	}
+	select {
+	case <-shadow.started:
+		t.Fatal("shadow model called while disabled")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWordSimilarity(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"Hello, world!", "hello world", 1},
+		{"a b", "c d", 0},
+		{"a b c", "a b d", 0.5},
+	} {
+		if got := wordSimilarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("wordSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package orchestrator
+
+import (
+	"time"
+)
+
+// defaultShutdownTimeout bounds Stop's wait for in-flight work when
+// server.shutdownTimeoutSec is unset.
+const defaultShutdownTimeout = 20 * time.Second
+
+// shutdownTimeout returns how long Stop waits for in-flight messages and
+// their responses.
+func (o *Orchestrator) shutdownTimeout() time.Duration {
+	if o.cfg != nil && o.cfg.Server.ShutdownTimeoutSec > 0 {
+		return time.Duration(o.cfg.Server.ShutdownTimeoutSec) * time.Second
+	}
+	return defaultShutdownTimeout
+}
+
+// goTracked runs fn in a goroutine that Stop waits for before cancelling
+// the subsystems fn may use. Once Stop has started draining, new work is
+// only tracked while other tracked work is still running (work started by
+// an in-flight message); anything else runs untracked. A panic in fn is
+// recovered and recorded under name, as for safeGo.
+func (o *Orchestrator) goTracked(name string, fn func()) {
+	o.inflightMu.Lock()
+	tracked := !o.draining || o.inflightN > 0
+	if tracked {
+		o.inflightN++
+		o.inflight.Add(1)
+	}
+	o.inflightMu.Unlock()
+
+	go func() {
+		if tracked {
+			defer o.untrack()
+		}
+		o.runSafe(name, fn)
+	}()
+}
+
+func (o *Orchestrator) untrack() {
+	o.inflightMu.Lock()
+	o.inflightN--
+	o.inflightMu.Unlock()
+	o.inflight.Done()
+}
+
+// waitInflight waits until every tracked goroutine has finished or the
+// deadline passes, and reports whether they all finished.
+func (o *Orchestrator) waitInflight(deadline time.Time) bool {
+	o.inflightMu.Lock()
+	o.draining = true
+	o.inflightMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		o.inflight.Wait()
+		close(done)
+	}()
+	return waitUntil(done, deadline)
+}
+
+// waitUntil waits for done to close or the deadline to pass.
+func waitUntil(done <-chan struct{}, deadline time.Time) bool {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// drain stops taking new messages, waits for in-flight processing to
+// finish and then for the outbox to deliver what it produced, all within
+// the shutdown timeout. Subsystems those goroutines depend on are only
+// stopped after this returns.
+func (o *Orchestrator) drain() {
+	deadline := time.Now().Add(o.shutdownTimeout())
+
+	// No new messages: stop the channel receivers and the router, and wait
+	// for the router so it cannot start more work while we drain.
+	o.intakeCancel()
+	if !waitUntil(o.routingDone(), deadline) {
+		o.logger.Warn("shutdown timed out waiting for message routing")
+	}
+
+	if !o.waitInflight(deadline) {
+		o.logger.Warn("shutdown timed out waiting for in-flight messages",
+			"timeout", o.shutdownTimeout())
+	}
+
+	o.outboxCancel()
+	if o.outboxDone != nil && !waitUntil(o.outboxDone, deadline) {
+		o.logger.Warn("shutdown timed out delivering queued responses", "pending", len(o.outbox))
+	}
+}
+
+// routingDone returns a channel closed once the intake goroutines exit.
+func (o *Orchestrator) routingDone() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		o.routing.Wait()
+		close(done)
+	}()
+	return done
+}
@@ -0,0 +1,50 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStop_WaitsForInflightMessages(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	ch := newMockChannel("test")
+	o.RegisterChannel(ch)
+	p := &mockSlowProvider{mockProvider: newMockProvider("mock"), delay: 200 * time.Millisecond}
+	o.RegisterProvider(p)
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	o.handleMessage(Message{ID: "m1", Channel: "test", From: "user", To: "test-agent", Content: "hello"})
+	start := time.Now()
+	if err := o.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Stop returned after %v, before the in-flight request finished", elapsed)
+	}
+	if n := p.getCalls(); n != 1 {
+		t.Errorf("provider calls = %d, want 1", n)
+	}
+	sent := ch.getSent()
+	if len(sent) != 1 || sent[0].Content != "mock response" {
+		t.Errorf("sent = %+v, want the in-flight response delivered before Stop returned", sent)
+	}
+}
+
+func TestGoTracked_UntrackedOnceDrained(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	if !o.waitInflight(time.Now().Add(time.Second)) {
+		t.Fatal("waitInflight timed out with nothing in flight")
+	}
+
+	// Nothing is in flight, so late work is not tracked and cannot hold
+	// up a finished drain.
+	release := make(chan struct{})
+	defer close(release)
+	o.goTracked("test", func() { <-release })
+	if !o.waitInflight(time.Now().Add(50 * time.Millisecond)) {
+		t.Error("work started after draining was tracked")
+	}
+}
@@ -0,0 +1,268 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/skillbank"
+)
+
+const (
+	defaultSkillMinFailures = 5
+	defaultSkillInterval    = time.Hour
+	// maxPendingFailures bounds the failures held for the next
+	// distillation; the oldest are dropped first.
+	maxPendingFailures = 100
+	// maxFailureTextRunes bounds the task and observation text kept per
+	// failure, since all of it ends up in the distillation prompt.
+	maxFailureTextRunes = 500
+	// skillDistillMaxTokens leaves room for several skills in the JSON reply.
+	skillDistillMaxTokens = 2048
+	// trajectoryCategory marks raw trajectories the RSI observer stores in
+	// the skillbank; they are not skills yet and cover nothing.
+	trajectoryCategory = "trajectory"
+)
+
+// skillLearner collects failed interactions until there are enough to
+// distill into the skillbank.
+type skillLearner struct {
+	updater     skillbank.Updater
+	store       skillbank.Store
+	minFailures int
+
+	mu      sync.Mutex
+	pending []skillbank.Trajectory
+	running bool
+}
+
+// add queues a failure and reports whether it is the one that brings the
+// queue to minFailures. Past that point (e.g. after a failed run put its
+// failures back) distillation waits for the next interval instead of
+// retrying on every failure.
+func (l *skillLearner) add(t skillbank.Trajectory) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(l.pending, t)
+	if excess := len(l.pending) - maxPendingFailures; excess > 0 {
+		l.pending = l.pending[excess:]
+	}
+	return !l.running && len(l.pending) == l.minFailures
+}
+
+// take claims the queued failures for a distillation run. It returns nil
+// if a run is already in progress or nothing is queued.
+func (l *skillLearner) take() []skillbank.Trajectory {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running || len(l.pending) == 0 {
+		return nil
+	}
+	failures := l.pending
+	l.pending = nil
+	l.running = true
+	return failures
+}
+
+// done ends a run. Failures that could not be distilled are queued again
+// ahead of any recorded since.
+func (l *skillLearner) done(retry []skillbank.Trajectory) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.running = false
+	if len(retry) > 0 {
+		l.pending = append(retry, l.pending...)
+		if excess := len(l.pending) - maxPendingFailures; excess > 0 {
+			l.pending = l.pending[excess:]
+		}
+	}
+}
+
+// SetSkillUpdater enables learning from failures: failed interactions are
+// recorded as trajectories and, once minFailures have accumulated, passed
+// to updater, which adds the skills and mistakes it distills to store.
+// minFailures of zero or less uses the default; a nil updater disables
+// learning.
+func (o *Orchestrator) SetSkillUpdater(updater skillbank.Updater, store skillbank.Store, minFailures int) {
+	if minFailures <= 0 {
+		minFailures = defaultSkillMinFailures
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if updater == nil {
+		o.skillLearner = nil
+		return
+	}
+	o.skillLearner = &skillLearner{updater: updater, store: store, minFailures: minFailures}
+}
+
+// initSkillLearning distills failures into the RSI skillbank with the
+// model from evolution.skillLearning, unless an updater was set
+// explicitly.
+func (o *Orchestrator) initSkillLearning() {
+	cfg := o.cfg.Evolution.SkillLearning
+	if !cfg.Enabled {
+		return
+	}
+	o.mu.RLock()
+	set := o.skillLearner != nil
+	o.mu.RUnlock()
+
+	if !set {
+		var store skillbank.Store
+		if o.rsiLoop != nil {
+			store = o.rsiLoop.Observer().SkillStore()
+		}
+		if store == nil {
+			o.logger.Warn("skill learning disabled: no skillbank store")
+			return
+		}
+		distiller := skillbank.NewFuncDistiller(o.completeFunc(cfg.Model, skillDistillMaxTokens, defaultDistillTemperature))
+		archiveDir := filepath.Join(o.cfg.Server.DataDir, "rsi")
+		o.SetSkillUpdater(skillbank.NewSkillUpdater(distiller, store, archiveDir), store, cfg.MinFailures)
+	}
+
+	interval := defaultSkillInterval
+	if cfg.IntervalSec > 0 {
+		interval = time.Duration(cfg.IntervalSec) * time.Second
+	}
+	o.safeGo("skill_learning", func() { o.skillLearningLoop(interval) })
+	o.logger.Info("skill learning enabled", "model", cfg.Model, "interval", interval)
+}
+
+// skillLearningLoop distills accumulated failures on every tick.
+func (o *Orchestrator) skillLearningLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-o.ctx.Done():
+			return
+		case <-ticker.C:
+			o.learnFromFailures(o.ctx)
+		}
+	}
+}
+
+// recordFailure queues a failed interaction for skill learning, starting a
+// distillation once enough have accumulated.
+func (o *Orchestrator) recordFailure(agent *AgentState, msg Message, observation string) {
+	o.mu.RLock()
+	l := o.skillLearner
+	o.mu.RUnlock()
+	if l == nil {
+		return
+	}
+
+	agent.mu.RLock()
+	taskType := agentTaskType(agent.Def)
+	agent.mu.RUnlock()
+
+	ready := l.add(skillbank.Trajectory{
+		TaskDescription: truncateRunes(msg.Content, maxFailureTextRunes),
+		TaskType:        taskType,
+		Steps: []skillbank.TrajectoryStep{{
+			Action:      "respond",
+			Observation: truncateRunes(observation, maxFailureTextRunes),
+			Timestamp:   time.Now(),
+		}},
+	})
+	if ready {
+		o.goTracked("skill_learning", func() { o.learnFromFailures(o.ctx) })
+	}
+}
+
+// recordErrorFailure queues err for skill learning if it reflects how the
+// agent behaved. Provider and transport errors are not something a skill
+// can teach the agent to avoid.
+func (o *Orchestrator) recordErrorFailure(agent *AgentState, msg Message, err error) {
+	if behaviourFailure(err) {
+		o.recordFailure(agent, msg, err.Error())
+	}
+}
+
+// behaviourFailure reports whether err came from the agent's own behaviour,
+// e.g. a failing tool, rather than from a timeout, a rate limit, an
+// unavailable model or a spent budget.
+func behaviourFailure(err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, ErrCostBudgetExceeded), errors.Is(err, ErrPinnedModelUnhealthy),
+		errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range providerErrorMarkers {
+		if strings.Contains(msg, marker) {
+			return false
+		}
+	}
+	return true
+}
+
+// providerErrorMarkers identify provider and transport failures by their
+// message. "provider busy" is models.ErrProviderBusy; the models package
+// imports this one.
+var providerErrorMarkers = []string{
+	"provider busy", "rate limit", "too many requests", "429", "quota",
+	"timeout", "deadline exceeded", "500", "502", "503", "504", "529",
+	"internal server error", "overloaded", "401", "403", "unauthorized",
+	"forbidden", "invalid api key", "model not found", "context length",
+}
+
+// agentTaskType is the skillbank task type for def's interactions, so
+// skills and mistakes learned from one trader reach the others.
+func agentTaskType(def config.AgentDef) string {
+	if def.Type == "" {
+		return "agent_chat"
+	}
+	return def.Type
+}
+
+// learnFromFailures passes the queued failures to the skill updater. The
+// skills and mistakes it distills are picked up by the prompt composer.
+// Nothing is learned in safe mode; the failures stay queued.
+func (o *Orchestrator) learnFromFailures(ctx context.Context) {
+	if o.SafeMode() {
+		return
+	}
+	o.mu.RLock()
+	l := o.skillLearner
+	o.mu.RUnlock()
+	if l == nil {
+		return
+	}
+	failures := l.take()
+	if failures == nil {
+		return
+	}
+
+	current, err := l.store.List("")
+	if err != nil {
+		o.logger.Warn("skill learning: list skills failed", "error", err)
+		l.done(failures)
+		return
+	}
+	skills := current[:0]
+	for _, s := range current {
+		if s.Category != trajectoryCategory {
+			skills = append(skills, s)
+		}
+	}
+
+	added, err := l.updater.Update(ctx, failures, skills)
+	if err != nil {
+		o.logger.Warn("skill learning: distillation failed", "failures", len(failures), "error", err)
+		l.done(failures)
+		return
+	}
+	l.done(nil)
+	for _, s := range added {
+		o.logger.Info("skill learned from failures", "skill", skillbank.SkillSummary(s))
+	}
+}
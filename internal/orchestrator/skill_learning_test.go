@@ -0,0 +1,180 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/skillbank"
+)
+
+// mockDistiller turns every batch into one skill and one mistake for the
+// batch's task type and records what it was given.
+type mockDistiller struct {
+	mu      sync.Mutex
+	batches [][]skillbank.Trajectory
+}
+
+func (d *mockDistiller) Distill(ctx context.Context, trajectories []skillbank.Trajectory) ([]skillbank.Skill, []skillbank.CommonMistake, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.batches = append(d.batches, trajectories)
+	taskType := trajectories[0].TaskType
+	return []skillbank.Skill{{
+		ID:          "retry-upstream",
+		Title:       "Retry Upstream",
+		Principle:   "Retry overloaded upstream calls with backoff",
+		WhenToApply: "upstream overloaded",
+		TaskType:    taskType,
+		Source:      skillbank.SourceDistilled,
+	}}, []skillbank.CommonMistake{{
+		ID:          "mistake-1",
+		Description: "Giving up after one overloaded response",
+		HowToAvoid:  "retry before reporting failure",
+		TaskType:    taskType,
+	}}, nil
+}
+
+func (d *mockDistiller) calls() [][]skillbank.Trajectory {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([][]skillbank.Trajectory{}, d.batches...)
+}
+
+var errToolFailed = errors.New("tool fetch_price failed: unknown symbol")
+
+// brokenToolProvider fails every call with errToolFailed, a failure of the
+// agent's own making rather than the provider's.
+type brokenToolProvider struct{ *mockProvider }
+
+func (p brokenToolProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	return nil, errToolFailed
+}
+
+// newLearningOrchestrator returns an orchestrator whose only provider always
+// fails, learning into a fresh skillbank after minFailures failures.
+func newLearningOrchestrator(t *testing.T, minFailures int) (*Orchestrator, *AgentState, *mockDistiller, skillbank.Store) {
+	t.Helper()
+	store, err := skillbank.NewFileStore(filepath.Join(t.TempDir(), "skills.jsonl"))
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	distiller := &mockDistiller{}
+
+	o := New(testConfig(), testLogger())
+	o.RegisterProvider(brokenToolProvider{newMockProvider("mock")})
+	o.SetSkillUpdater(skillbank.NewSkillUpdater(distiller, store, t.TempDir()), store, minFailures)
+	o.mu.Lock()
+	agent := o.initAgentLocked(o.cfg.Agents[0])
+	o.mu.Unlock()
+	return o, agent, distiller, store
+}
+
+func TestSkillLearning_RepeatedFailuresTriggerDistillation(t *testing.T) {
+	o, agent, distiller, store := newLearningOrchestrator(t, 3)
+
+	for i := 0; i < 3; i++ {
+		o.processWithAgent(agent, Message{ID: "m", Channel: "web", Content: "fetch BTC price"}, "mock/mock-model-1")
+	}
+
+	// Distillation runs in the background; the mistake is stored last.
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if m, _ := store.ListMistakes(""); len(m) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := store.Get("retry-upstream"); err != nil {
+		t.Fatalf("distilled skill not stored: %v", err)
+	}
+
+	calls := distiller.calls()
+	if len(calls) != 1 || len(calls[0]) != 3 {
+		t.Fatalf("distiller calls = %d, want one batch of 3", len(calls))
+	}
+	f := calls[0][0]
+	if f.TaskDescription != "fetch BTC price" || f.TaskType != "orchestrator" || f.Success {
+		t.Errorf("unexpected trajectory %+v", f)
+	}
+	if len(f.Steps) != 1 || !strings.Contains(f.Steps[0].Observation, errToolFailed.Error()) {
+		t.Errorf("trajectory should record the error, got %+v", f.Steps)
+	}
+
+	// The learned skill and mistake reach the next prompt.
+	o.SetPromptComposer(NewPromptComposer(skillbank.NewRetriever(store, ""), nil, 0).WithMistakes(store))
+	prompt := o.systemPrompt(context.Background(), agent, "upstream overloaded again")
+	for _, want := range []string{"[Retry Upstream]", "Giving up after one overloaded response"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestSkillLearning_FewerFailuresWaitForInterval(t *testing.T) {
+	o, agent, distiller, store := newLearningOrchestrator(t, 3)
+
+	for i := 0; i < 2; i++ {
+		o.processWithAgent(agent, Message{ID: "m", Channel: "web", Content: "fetch BTC price"}, "mock/mock-model-1")
+	}
+	if n := len(distiller.calls()); n != 0 {
+		t.Fatalf("distilled after 2 of 3 failures (%d calls)", n)
+	}
+
+	// The periodic sweep distills whatever has accumulated.
+	o.learnFromFailures(context.Background())
+	if calls := distiller.calls(); len(calls) != 1 || len(calls[0]) != 2 {
+		t.Fatalf("expected one batch of 2 from the sweep, got %d calls", len(calls))
+	}
+	if store.Count() != 1 {
+		t.Errorf("store has %d skills, want 1", store.Count())
+	}
+
+	o.learnFromFailures(context.Background())
+	if n := len(distiller.calls()); n != 1 {
+		t.Errorf("sweep with nothing queued called the distiller (%d calls)", n)
+	}
+}
+
+func TestSkillLearning_IgnoresProviderErrors(t *testing.T) {
+	o, agent, _, _ := newLearningOrchestrator(t, 1)
+	o.RegisterProvider(failingProvider{newMockProvider("mock")})
+
+	o.processWithAgent(agent, Message{ID: "m", Channel: "web", Content: "fetch BTC price"}, "mock/mock-model-1")
+	for _, err := range []error{
+		fmt.Errorf("%w: agent test-agent", ErrCostBudgetExceeded),
+		errors.New("429 Too Many Requests"),
+		context.DeadlineExceeded,
+	} {
+		o.recordErrorFailure(agent, Message{Content: "fetch BTC price"}, err)
+	}
+
+	if n := len(o.skillLearner.take()); n != 0 {
+		t.Errorf("%d provider failures queued for skill learning, want 0", n)
+	}
+}
+
+func TestSkillLearning_PausedInSafeMode(t *testing.T) {
+	o, agent, distiller, _ := newLearningOrchestrator(t, 5)
+	o.SetSafeMode(true)
+
+	for i := 0; i < 3; i++ {
+		o.processWithAgent(agent, Message{ID: "m", Channel: "web", Content: "fetch BTC price"}, "mock/mock-model-1")
+	}
+	o.learnFromFailures(context.Background())
+	if n := len(distiller.calls()); n != 0 {
+		t.Fatalf("distilled in safe mode (%d calls)", n)
+	}
+
+	// The failures stay queued for when safe mode ends.
+	o.SetSafeMode(false)
+	o.learnFromFailures(context.Background())
+	if calls := distiller.calls(); len(calls) != 1 || len(calls[0]) != 3 {
+		t.Errorf("expected one batch of 3 after safe mode, got %d calls", len(calls))
+	}
+}
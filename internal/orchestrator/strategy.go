@@ -0,0 +1,46 @@
+package orchestrator
+
+import (
+	"github.com/clawinfra/evoclaw/internal/evolution"
+)
+
+// activeStrategy returns the agent's current strategy from the evolution
+// engine, or nil when there is no engine or it keeps no *evolution.Strategy
+// for the agent.
+func (o *Orchestrator) activeStrategy(agentID string) *evolution.Strategy {
+	o.mu.RLock()
+	evo := o.evolution
+	o.mu.RUnlock()
+	if evo == nil {
+		return nil
+	}
+	s, _ := evo.GetStrategy(agentID).(*evolution.Strategy)
+	return s
+}
+
+// preferredModel returns the model to try first for agent: the evolved
+// strategy's preferred model, then the agent's configured model, then the
+// complex route.
+func (o *Orchestrator) preferredModel(agent *AgentState) string {
+	if s := o.activeStrategy(agent.ID); s != nil && s.PreferredModel != "" {
+		return s.PreferredModel
+	}
+	agent.mu.RLock()
+	model := agent.Def.Model
+	agent.mu.RUnlock()
+	if model == "" {
+		model = o.cfg.Models.Routing.Complex
+	}
+	return model
+}
+
+// baseSystemPrompt returns the evolved strategy's system prompt, falling back
+// to the agent's configured prompt.
+func (o *Orchestrator) baseSystemPrompt(agent *AgentState) string {
+	if s := o.activeStrategy(agent.ID); s != nil && s.SystemPrompt != "" {
+		return s.SystemPrompt
+	}
+	agent.mu.RLock()
+	defer agent.mu.RUnlock()
+	return agent.Def.SystemPrompt
+}
@@ -0,0 +1,88 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/evolution"
+)
+
+func TestStrategyOverridesModelAndPrompt(t *testing.T) {
+	cfg := testConfig()
+	o := New(cfg, testLogger())
+	provider := &paramsCapturingProvider{}
+	o.RegisterProvider(provider)
+
+	agent := &AgentState{ID: "a1", Def: config.AgentDef{
+		ID:           "a1",
+		Model:        "other/configured-model",
+		SystemPrompt: "configured prompt",
+	}}
+
+	// Without a strategy the agent definition is used.
+	if got := o.selectModel(Message{}, agent); got != "other/configured-model" {
+		t.Errorf("selectModel without strategy = %q", got)
+	}
+
+	e := evolution.NewEngine(t.TempDir(), testLogger())
+	e.SetStrategy("a1", &evolution.Strategy{
+		ID:             "s1",
+		SystemPrompt:   "evolved prompt",
+		PreferredModel: "mock/mock-model-1",
+		Temperature:    0.4,
+	})
+	o.SetEvolutionEngine(e)
+
+	model := o.selectModel(Message{}, agent)
+	if model != "mock/mock-model-1" {
+		t.Fatalf("selectModel = %q, want strategy model", model)
+	}
+	if _, _, err := o.processDirect(agent, Message{Content: "hi"}, model); err != nil {
+		t.Fatalf("processDirect: %v", err)
+	}
+	if provider.req.Model != "mock-model-1" {
+		t.Errorf("model sent = %q, want mock-model-1", provider.req.Model)
+	}
+	if provider.req.SystemPrompt != "evolved prompt" {
+		t.Errorf("system prompt sent = %q, want evolved prompt", provider.req.SystemPrompt)
+	}
+	if provider.req.Temperature != 0.4 {
+		t.Errorf("temperature sent = %g, want 0.4", provider.req.Temperature)
+	}
+}
+
+func TestStrategyWithoutOverridesFallsBackToDef(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	e := evolution.NewEngine(t.TempDir(), testLogger())
+	e.SetStrategy("a1", &evolution.Strategy{ID: "s1"})
+	o.SetEvolutionEngine(e)
+
+	agent := &AgentState{ID: "a1", Def: config.AgentDef{ID: "a1", Model: "x/y", SystemPrompt: "base"}}
+	if got := o.preferredModel(agent); got != "x/y" {
+		t.Errorf("preferredModel = %q, want x/y", got)
+	}
+	if got := o.baseSystemPrompt(agent); got != "base" {
+		t.Errorf("baseSystemPrompt = %q, want base", got)
+	}
+}
+
+func TestChatSyncUsesStrategyModel(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	provider := &paramsCapturingProvider{}
+	o.RegisterProvider(provider)
+	o.mu.Lock()
+	o.initAgentLocked(config.AgentDef{ID: "a1", Model: "other/configured", SystemPrompt: "configured"})
+	o.mu.Unlock()
+
+	e := evolution.NewEngine(t.TempDir(), testLogger())
+	e.SetStrategy("a1", &evolution.Strategy{ID: "s1", PreferredModel: "mock/mock-model-1", SystemPrompt: "evolved"})
+	o.SetEvolutionEngine(e)
+
+	resp, err := o.ChatSync(t.Context(), ChatSyncRequest{AgentID: "a1", Message: "hi"})
+	if err != nil {
+		t.Fatalf("ChatSync: %v", err)
+	}
+	if resp.Model != "mock/mock-model-1" || provider.req.SystemPrompt != "evolved" {
+		t.Errorf("model = %q, prompt = %q", resp.Model, provider.req.SystemPrompt)
+	}
+}
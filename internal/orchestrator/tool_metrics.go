@@ -0,0 +1,120 @@
+package orchestrator
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ToolStats summarises every execution of one tool since startup, for
+// GET /api/tools/metrics and the Prometheus endpoint.
+type ToolStats struct {
+	Tool        string `json:"tool"`
+	Invocations int64  `json:"invocations"`
+	Errors      int64  `json:"errors"`
+	Timeouts    int64  `json:"timeouts"`
+	// TotalLatencyMs is the summed latency of all invocations.
+	TotalLatencyMs int64     `json:"total_latency_ms"`
+	AvgLatencyMs   float64   `json:"avg_latency_ms"`
+	ErrorRate      float64   `json:"error_rate"`
+	LastUsed       time.Time `json:"last_used"`
+}
+
+// toolMetrics accumulates ToolStats per tool name.
+type toolMetrics struct {
+	mu     sync.Mutex
+	byTool map[string]*ToolStats
+}
+
+func (m *toolMetrics) record(tool string, elapsed time.Duration, failed, timedOut bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.byTool == nil {
+		m.byTool = make(map[string]*ToolStats)
+	}
+	s, ok := m.byTool[tool]
+	if !ok {
+		s = &ToolStats{Tool: tool}
+		m.byTool[tool] = s
+	}
+	s.Invocations++
+	s.TotalLatencyMs += elapsed.Milliseconds()
+	if failed {
+		s.Errors++
+	}
+	if timedOut {
+		s.Timeouts++
+	}
+	s.LastUsed = time.Now()
+}
+
+func (m *toolMetrics) snapshot() []ToolStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]ToolStats, 0, len(m.byTool))
+	for _, s := range m.byTool {
+		st := *s
+		st.AvgLatencyMs = float64(st.TotalLatencyMs) / float64(st.Invocations)
+		st.ErrorRate = float64(st.Errors) / float64(st.Invocations)
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Tool < out[j].Tool })
+	return out
+}
+
+// RecordToolResult adds one tool execution to the per-tool metrics. A
+// non-nil err or a result with a non-success status counts as an error;
+// the latency is the result's ElapsedMs when set, otherwise elapsed.
+func (tm *ToolManager) RecordToolResult(tool string, result *ToolResult, err error, elapsed time.Duration) {
+	failed := err != nil || result == nil || result.Status != "success"
+	timedOut := result != nil && result.ErrorType == "timeout"
+	if result != nil && result.ElapsedMs > 0 {
+		elapsed = time.Duration(result.ElapsedMs) * time.Millisecond
+	}
+	tm.metrics.record(tool, elapsed, failed, timedOut)
+}
+
+// ToolStats returns the per-tool metrics, sorted by tool name.
+func (tm *ToolManager) ToolStats() []ToolStats {
+	return tm.metrics.snapshot()
+}
+
+// ToolStats returns the per-tool execution metrics, or nil if no agent has
+// tools.
+func (o *Orchestrator) ToolStats() []ToolStats {
+	o.mu.RLock()
+	tm := o.toolManager
+	o.mu.RUnlock()
+	if tm == nil {
+		return nil
+	}
+	return tm.ToolStats()
+}
+
+// recordToolExecution logs one tool execution and feeds it into the
+// per-tool metrics.
+func (tl *ToolLoop) recordToolExecution(agent *AgentState, call ToolCall, result *ToolResult, err error, elapsed time.Duration) {
+	if tl.toolManager != nil {
+		tl.toolManager.RecordToolResult(call.Name, result, err, elapsed)
+	}
+
+	elapsedMs := elapsed.Milliseconds()
+	if result != nil && result.ElapsedMs > 0 {
+		elapsedMs = result.ElapsedMs
+	}
+	attrs := []any{"agent", agent.ID, "tool", call.Name, "call_id", call.ID, "elapsed_ms", elapsedMs}
+	switch {
+	case err != nil || result == nil:
+		tl.logger.Warn("tool execution failed", append(attrs, "error", err)...)
+	case result.Status != "success":
+		tl.logger.Warn("tool execution failed", append(attrs,
+			"status", result.Status,
+			"error_type", result.ErrorType,
+			"exit_code", result.ExitCode,
+			"error", result.Error)...)
+	default:
+		tl.logger.Info("tool executed", append(attrs, "status", result.Status)...)
+	}
+}
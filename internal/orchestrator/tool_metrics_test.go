@@ -0,0 +1,73 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestToolMetrics_PerToolStats(t *testing.T) {
+	mock := newMockEdge()
+	mock.results["fast-1"] = &ToolResult{Tool: "fast", Status: "success", ElapsedMs: 10}
+	mock.results["fast-2"] = &ToolResult{Tool: "fast", Status: "success", ElapsedMs: 30}
+	mock.results["slow-1"] = &ToolResult{Tool: "slow", Status: "success", ElapsedMs: 400}
+	mock.results["slow-2"] = &ToolResult{Tool: "slow", Status: "error", ErrorType: "timeout", ElapsedMs: 1000}
+	mock.errors["slow-3"] = errors.New("edge agent offline")
+	mock.latency["slow-3"] = 20 * time.Millisecond
+
+	tl := makeToolLoop(5, mock.exec)
+	tl.toolManager = NewToolManager(t.TempDir(), nil, testLogger())
+	agent := makeAgent("agent-1")
+
+	tl.executeParallel(context.Background(), agent, []ToolCall{
+		makeCall("fast-1", "fast"), makeCall("slow-1", "slow"), makeCall("slow-2", "slow"),
+	}, nil)
+	tl.executeParallel(context.Background(), agent, []ToolCall{
+		makeCall("fast-2", "fast"), makeCall("slow-3", "slow"),
+	}, nil)
+
+	stats := tl.toolManager.ToolStats()
+	if len(stats) != 2 || stats[0].Tool != "fast" || stats[1].Tool != "slow" {
+		t.Fatalf("stats = %+v, want fast and slow sorted by name", stats)
+	}
+
+	fast := stats[0]
+	if fast.Invocations != 2 || fast.Errors != 0 || fast.ErrorRate != 0 || fast.AvgLatencyMs != 20 {
+		t.Errorf("fast = %+v, want 2 invocations, no errors, 20ms average", fast)
+	}
+
+	slow := stats[1]
+	if slow.Invocations != 3 || slow.Errors != 2 || slow.Timeouts != 1 {
+		t.Errorf("slow = %+v, want 3 invocations, 2 errors, 1 timeout", slow)
+	}
+	if slow.ErrorRate < 0.66 || slow.ErrorRate > 0.67 {
+		t.Errorf("slow error rate = %v, want 2/3", slow.ErrorRate)
+	}
+	// The failed call has no ToolResult, so its wall-clock latency is used.
+	if slow.TotalLatencyMs < 1420 || slow.AvgLatencyMs <= fast.AvgLatencyMs {
+		t.Errorf("slow latency = %dms total, %vms avg", slow.TotalLatencyMs, slow.AvgLatencyMs)
+	}
+	if slow.LastUsed.IsZero() {
+		t.Error("LastUsed not set")
+	}
+}
+
+func TestToolMetrics_DeniedCallsNotCounted(t *testing.T) {
+	mock := newMockEdge()
+	tl := makeToolLoop(5, mock.exec)
+	tl.toolManager = NewToolManager(t.TempDir(), nil, testLogger())
+	agent := makeAgent("agent-1")
+	agent.Def.DeniedTools = []string{"rm"}
+
+	tl.executeParallel(context.Background(), agent, []ToolCall{makeCall("c1", "rm")}, nil)
+	if stats := tl.toolManager.ToolStats(); len(stats) != 0 {
+		t.Errorf("stats = %+v, want denied call not recorded", stats)
+	}
+}
+
+func TestOrchestratorToolStats_NoToolManager(t *testing.T) {
+	if stats := New(testConfig(), testLogger()).ToolStats(); stats != nil {
+		t.Errorf("stats = %+v, want nil without a tool manager", stats)
+	}
+}
@@ -3,7 +3,9 @@ package orchestrator
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/clawinfra/evoclaw/internal/config"
 	rsiPkg "github.com/clawinfra/evoclaw/internal/rsi"
 	"github.com/clawinfra/evoclaw/internal/security"
 )
@@ -18,6 +21,31 @@ import (
 // ToolLoopOption is a functional option for configuring a ToolLoop.
 type ToolLoopOption func(*ToolLoop)
 
+// Finish reasons reported in ToolLoopMetrics and the response metadata.
+const (
+	// FinishReasonStop means the model produced a final answer.
+	FinishReasonStop = "stop"
+	// FinishReasonBudgetExceeded means the loop hit its iteration cap or
+	// wall-clock budget and the response is partial.
+	FinishReasonBudgetExceeded = "budget_exceeded"
+)
+
+// WithMaxIterations caps the number of LLM round-trips that may request
+// tools. Values below 1 are ignored.
+func WithMaxIterations(n int) ToolLoopOption {
+	return func(tl *ToolLoop) {
+		if n > 0 {
+			tl.maxIterations = n
+		}
+	}
+}
+
+// WithBudget limits the total wall-clock time of one Execute call. Zero
+// means no limit.
+func WithBudget(d time.Duration) ToolLoopOption {
+	return func(tl *ToolLoop) { tl.budget = d }
+}
+
 // WithRSILogger wires an RSILogger into the ToolLoop so that every Execute()
 // call automatically emits one outcome record.
 func WithRSILogger(logger RSILogger) ToolLoopOption {
@@ -32,6 +60,7 @@ type ToolLoop struct {
 
 	// Config
 	maxIterations  int
+	budget         time.Duration // wall-clock limit per Execute; 0 = none
 	errorLimit     int
 	defaultTimeout time.Duration
 	maxParallel    int
@@ -70,6 +99,20 @@ type ToolLoopMetrics struct {
 	ParallelBatches int
 	MaxConcurrency  int
 	WallTimeSavedMs int64
+	// FinishReason is FinishReasonStop or FinishReasonBudgetExceeded.
+	FinishReason string
+	// BudgetExceeded is set when the iteration cap or wall-clock budget
+	// ended the loop; BudgetLimit names which ("max_iterations" or "wall_clock").
+	BudgetExceeded bool
+	BudgetLimit    string
+	// UnavailableCount counts calls to tools that weren't offered or whose
+	// edge agent was unreachable; each is also an error.
+	UnavailableCount int
+	// TokensInput and TokensOutput sum usage across every LLM call in the loop.
+	TokensInput  int
+	TokensOutput int
+	// ReasoningTokens is the part of TokensOutput spent on reasoning.
+	ReasoningTokens int
 }
 
 // parallelToolResult holds the outcome of a single tool call executed in parallel.
@@ -86,7 +129,7 @@ func NewToolLoop(orch *Orchestrator, tm *ToolManager, opts ...ToolLoopOption) *T
 		orchestrator:   orch,
 		toolManager:    tm,
 		logger:         orch.logger.With("component", "tool_loop"),
-		maxIterations:  10, // Configurable
+		maxIterations:  10, // See WithMaxIterations
 		errorLimit:     3,  // Configurable
 		defaultTimeout: 30 * time.Second,
 		maxParallel:    5,
@@ -99,12 +142,33 @@ func NewToolLoop(orch *Orchestrator, tm *ToolManager, opts ...ToolLoopOption) *T
 }
 
 // executeParallel executes a batch of tool calls concurrently and returns
-// results in the original call order. For a single call, it takes the fast
-// path with no goroutine overhead.
-func (tl *ToolLoop) executeParallel(ctx context.Context, agent *AgentState, calls []ToolCall) []parallelToolResult {
-	fn := tl.execFunc
-	if fn == nil {
-		fn = tl.executeToolCall
+// results in the original call order. Calls whose arguments do not match
+// their tool's schema in schemas are answered with an error instead of
+// being executed. For a single call, it takes the fast path with no
+// goroutine overhead.
+func (tl *ToolLoop) executeParallel(ctx context.Context, agent *AgentState, calls []ToolCall, schemas map[string]ToolSchema) []parallelToolResult {
+	exec := tl.execFunc
+	if exec == nil {
+		exec = func(agent *AgentState, call ToolCall) (*ToolResult, error) {
+			return tl.executeToolCall(ctx, agent, call)
+		}
+	}
+	// Enforce the agent's tool scope at execution time too: the model may
+	// name a tool it was never offered.
+	fn := func(agent *AgentState, call ToolCall) (*ToolResult, error) {
+		if denied := tl.checkToolAccess(agent, call); denied != nil {
+			return denied, nil
+		}
+		if unavailable := tl.checkToolAvailable(agent, call, schemas); unavailable != nil {
+			return unavailable, nil
+		}
+		if invalid := tl.checkToolArguments(agent, call, schemas); invalid != nil {
+			return invalid, nil
+		}
+		start := time.Now()
+		res, err := exec(agent, call)
+		tl.recordToolExecution(agent, call, res, err, time.Since(start))
+		return res, err
 	}
 
 	results := make([]parallelToolResult, len(calls))
@@ -166,24 +230,49 @@ func (tl *ToolLoop) logRSIOutcome(agentID, model string, metrics *ToolLoopMetric
 	}
 }
 
+// toolSchemas returns the tools the loop offers agent: the ones it may use,
+// plus edge_call while any edge agent is online.
+func (tl *ToolLoop) toolSchemas(agent *AgentState) ([]ToolSchema, error) {
+	tools, err := tl.toolManager.SchemasForAgent(agent.Def)
+	if err != nil {
+		return nil, fmt.Errorf("generate tool schemas: %w", err)
+	}
+
+	// edge_call is a single generic tool — no per-device schema needed.
+	if schema, ok := tl.orchestrator.buildEdgeCallSchema(); ok && checkToolAccess(agent.Def, schema.Name, nil) == nil {
+		tools = append(tools, schema)
+	}
+	return tools, nil
+}
+
 // Execute runs the tool loop for a message
 func (tl *ToolLoop) Execute(agent *AgentState, msg Message, model string) (*Response, *ToolLoopMetrics, error) {
 	startTime := time.Now()
 	metrics := &ToolLoopMetrics{}
 	var allToolNames []string
 
-	// Generate tool schemas
-	tools, err := tl.toolManager.GenerateSchemas()
+	tools, err := tl.toolSchemas(agent)
 	if err != nil {
-		return nil, nil, fmt.Errorf("generate tool schemas: %w", err)
+		return nil, nil, err
 	}
+	schemas := toolSchemaIndex(tools)
 
-	// Append edge_call tool if any edge agents are online.
-	// This is a single generic tool — no per-device schema needed.
-	if schema, ok := tl.orchestrator.buildEdgeCallSchema(); ok {
-		tools = append(tools, schema)
+	// Spans for each iteration join the message's trace
+	ctx := tl.orchestrator.traceContext(msg)
+	tracer := tl.orchestrator.Tracer()
+
+	// Every LLM and tool call gets only the time left in the budget, so a
+	// slow call cannot overrun it
+	if tl.budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, startTime.Add(tl.budget))
+		defer cancel()
 	}
 
+	// Compose the system prompt and sampling parameters once for the whole loop
+	systemPrompt := tl.orchestrator.systemPrompt(tl.orchestrator.ctx, agent, msg.Content)
+	params := tl.orchestrator.modelParams(agent)
+
 	// Initialize conversation history
 	messages := []ChatMessage{
 		{Role: "user", Content: msg.Content},
@@ -191,18 +280,40 @@ func (tl *ToolLoop) Execute(agent *AgentState, msg Message, model string) (*Resp
 
 	consecutiveErrors := 0
 	var finalContent string // Tracks the final text response
+	var lastContent string  // Latest assistant text, kept for partial responses
 	needsSummary := false   // True when loop ended after tool results (needs summarisation)
+	outOfTime := false      // True when the wall-clock budget ran out
+	var reasoning string    // Latest reasoning kept under models.reasoning "store"
 
 	// Tool loop
 	for iteration := 0; iteration < tl.maxIterations; iteration++ {
+		if tl.budgetSpent(startTime) {
+			outOfTime = true
+			break
+		}
 		metrics.TotalIterations++
+		iterCtx, iterSpan := tracer.Start(ctx, "tool_loop.iteration")
+		iterSpan.SetAttr("tool_loop.iteration", iteration)
 
 		// Call LLM
-		llmResp, toolCalls, err := tl.callLLM(messages, tools, model, agent.Def.SystemPrompt)
+		llmResp, toolCalls, err := tl.callLLM(iterCtx, messages, tools, model, systemPrompt, params)
 		if err != nil {
+			iterSpan.RecordError(err)
+			iterSpan.End()
+			if tl.budgetSpent(startTime) {
+				outOfTime = true
+				break
+			}
 			tl.logRSIOutcome(agent.ID, model, metrics, allToolNames, time.Since(startTime))
 			return nil, nil, fmt.Errorf("call LLM (iteration %d): %w", iteration, err)
 		}
+		iterSpan.SetAttr("tool_loop.tool_calls", len(toolCalls))
+		metrics.TokensInput += llmResp.TokensInput
+		metrics.TokensOutput += llmResp.TokensOutput
+		metrics.ReasoningTokens += llmResp.ReasoningTokens
+		if r := tl.orchestrator.keepReasoning(agent.ID, model, llmResp); r != "" {
+			reasoning = r
+		}
 
 		// Add assistant response to history
 		assistantMsg := ChatMessage{
@@ -216,9 +327,13 @@ func (tl *ToolLoop) Execute(agent *AgentState, msg Message, model string) (*Resp
 		}
 
 		messages = append(messages, assistantMsg)
+		if llmResp.Content != "" {
+			lastContent = llmResp.Content
+		}
 
 		// If no tool calls, the LLM produced its final answer — use it directly
 		if len(toolCalls) == 0 {
+			iterSpan.End()
 			finalContent = llmResp.Content
 			tl.logger.Info("tool loop complete", "iterations", iteration+1)
 			break
@@ -231,8 +346,9 @@ func (tl *ToolLoop) Execute(agent *AgentState, msg Message, model string) (*Resp
 
 		// --- Parallel batch execution (Phase 2) ---
 		batchStart := time.Now()
-		batchResults := tl.executeParallel(tl.orchestrator.ctx, agent, toolCalls)
+		batchResults := tl.executeParallel(iterCtx, agent, toolCalls, schemas)
 		batchWall := time.Since(batchStart)
+		iterSpan.End()
 
 		// Update parallel-specific metrics for multi-call batches
 		if len(toolCalls) > 1 {
@@ -278,8 +394,11 @@ func (tl *ToolLoop) Execute(agent *AgentState, msg Message, model string) (*Resp
 				metrics.SuccessCount++
 			} else {
 				metrics.ErrorCount++
-				if pr.Result.ErrorType == "timeout" {
+				switch pr.Result.ErrorType {
+				case "timeout":
 					metrics.TimeoutCount++
+				case errTypeToolUnavailable:
+					metrics.UnavailableCount++
 				}
 			}
 
@@ -303,8 +422,30 @@ func (tl *ToolLoop) Execute(agent *AgentState, msg Message, model string) (*Resp
 		// If we've hit max iterations, we need a summary call
 		if iteration == tl.maxIterations-1 {
 			needsSummary = true
+			metrics.BudgetExceeded = true
+			metrics.BudgetLimit = "max_iterations"
+		}
+	}
+
+	metrics.FinishReason = FinishReasonStop
+	if outOfTime {
+		// No time left for a summary call: return what we have.
+		metrics.BudgetExceeded = true
+		metrics.BudgetLimit = "wall_clock"
+		needsSummary = false
+		finalContent = lastContent
+		if finalContent == "" {
+			finalContent = fmt.Sprintf("Stopped after %d tool call(s): the %s time budget was exceeded before a final answer was produced.", metrics.ToolCalls, tl.budget)
 		}
 	}
+	if metrics.BudgetExceeded {
+		metrics.FinishReason = FinishReasonBudgetExceeded
+		tl.logger.Warn("tool loop budget exceeded",
+			"agent", agent.ID,
+			"limit", metrics.BudgetLimit,
+			"iterations", metrics.TotalIterations,
+			"elapsed", time.Since(startTime))
+	}
 
 	metrics.TotalDuration = time.Since(startTime)
 
@@ -313,16 +454,29 @@ func (tl *ToolLoop) Execute(agent *AgentState, msg Message, model string) (*Resp
 	// 2. finalContent is empty (the LLM never produced a text-only response)
 	if needsSummary || finalContent == "" {
 		tl.logger.Info("making summary LLM call", "reason_max_iter", needsSummary, "empty_content", finalContent == "")
-		summaryResp, _, err := tl.callLLM(messages, tools, model, agent.Def.SystemPrompt)
+		summaryResp, _, err := tl.callLLM(ctx, messages, tools, model, systemPrompt, params)
+		if err != nil && tl.budgetSpent(startTime) && lastContent != "" {
+			// The budget ran out during the summary: return what we have.
+			metrics.BudgetExceeded = true
+			metrics.BudgetLimit = "wall_clock"
+			metrics.FinishReason = FinishReasonBudgetExceeded
+			summaryResp, err = &ChatResponse{Content: lastContent}, nil
+		}
 		if err != nil {
 			tl.logRSIOutcome(agent.ID, model, metrics, allToolNames, time.Since(startTime))
 			return nil, metrics, fmt.Errorf("summary LLM call: %w", err)
 		}
+		metrics.TokensInput += summaryResp.TokensInput
+		metrics.TokensOutput += summaryResp.TokensOutput
+		metrics.ReasoningTokens += summaryResp.ReasoningTokens
+		if r := tl.orchestrator.keepReasoning(agent.ID, model, summaryResp); r != "" {
+			reasoning = r
+		}
 		finalContent = summaryResp.Content
 	}
 
 	tl.logRSIOutcome(agent.ID, model, metrics, allToolNames, time.Since(startTime))
-	return &Response{
+	resp := &Response{
 		AgentID:   agent.ID,
 		Content:   finalContent,
 		Channel:   msg.Channel,
@@ -330,15 +484,38 @@ func (tl *ToolLoop) Execute(agent *AgentState, msg Message, model string) (*Resp
 		ReplyTo:   msg.ID,
 		MessageID: msg.ID,
 		Model:     model,
-	}, metrics, nil
+	}
+	if metrics.BudgetExceeded {
+		resp.Metadata = map[string]string{
+			"finish_reason": metrics.FinishReason,
+			"budget_limit":  metrics.BudgetLimit,
+		}
+	}
+	setReasoning(resp, reasoning)
+	return resp, metrics, nil
+}
+
+// budgetSpent reports whether the wall-clock budget, if any, has run out.
+func (tl *ToolLoop) budgetSpent(start time.Time) bool {
+	return tl.budget > 0 && time.Since(start) >= tl.budget
+}
+
+// callTimeout returns d, shortened to the time left before ctx's deadline.
+func callTimeout(ctx context.Context, d time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline); left < d {
+			return max(left, 0)
+		}
+	}
+	return d
 }
 
 // callLLM calls the LLM with conversation history and tools
-func (tl *ToolLoop) callLLM(messages []ChatMessage, tools []ToolSchema, model, systemPrompt string) (*ChatResponse, []ToolCall, error) {
+func (tl *ToolLoop) callLLM(ctx context.Context, messages []ChatMessage, tools []ToolSchema, model, systemPrompt string, params config.ModelParams) (*ChatResponse, []ToolCall, error) {
 	// Find provider
-	provider := tl.orchestrator.findProvider(model)
-	if provider == nil {
-		return nil, nil, fmt.Errorf("no provider for model: %s", model)
+	provider, err := tl.orchestrator.providerFor(model)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Extract just the model ID (after the /) for the API request
@@ -353,15 +530,22 @@ func (tl *ToolLoop) callLLM(messages []ChatMessage, tools []ToolSchema, model, s
 		SystemPrompt: systemPrompt, // Pass agent's system prompt
 		Messages:     messages,
 		Tools:        tools, // Include tool schemas for function calling
-		MaxTokens:    4096,
-		Temperature:  0.7,
 	}
+	applyModelParams(&req, params)
+	req = tl.orchestrator.fitContext(req, model)
 
 	// Call LLM
-	resp, err := provider.Chat(tl.orchestrator.ctx, req)
+	ctx, span := tl.orchestrator.Tracer().Start(ctx, "llm.call")
+	defer span.End()
+	span.SetAttr("llm.model", model)
+	resp, err := provider.Chat(ctx, req)
 	if err != nil {
+		err = &ErrProviderFailure{Provider: provider.Name(), Model: model, Err: err}
+		span.RecordError(err)
 		return nil, nil, err
 	}
+	span.SetAttr("llm.tokens_input", resp.TokensInput)
+	span.SetAttr("llm.tokens_output", resp.TokensOutput)
 
 	// Parse tool calls from response
 	var toolCalls []ToolCall
@@ -372,8 +556,92 @@ func (tl *ToolLoop) callLLM(messages []ChatMessage, tools []ToolSchema, model, s
 	return resp, toolCalls, nil
 }
 
+// checkToolAccess returns an error result if the agent may not call the
+// tool, or nil if the call may proceed.
+func (tl *ToolLoop) checkToolAccess(agent *AgentState, call ToolCall) *ToolResult {
+	if tl.toolManager == nil {
+		return nil
+	}
+	if err := tl.toolManager.CheckAgentTool(agent.Def, call.Name); err != nil {
+		tl.logger.Warn("tool call denied", "agent", agent.ID, "tool", call.Name, "reason", err)
+		return &ToolResult{
+			Tool:      call.Name,
+			Status:    "error",
+			Error:     fmt.Sprintf("tool not permitted: %v", err),
+			ErrorType: "tool_denied",
+		}
+	}
+	return nil
+}
+
+// checkToolArguments returns an error result if call's arguments do not
+// match the parameters schema the tool was offered with, or nil if the call
+// may proceed. The error goes back to the model so it can fix the
+// arguments and call again, rather than the tool running with bad input.
+func (tl *ToolLoop) checkToolArguments(agent *AgentState, call ToolCall, schemas map[string]ToolSchema) *ToolResult {
+	schema, ok := schemas[call.Name]
+	if !ok {
+		return nil
+	}
+	if err := validateEdgeParams(schema.Parameters, call.Arguments); err != nil {
+		tl.logger.Warn("tool call arguments invalid", "agent", agent.ID, "tool", call.Name, "reason", err)
+		return &ToolResult{
+			Tool:      call.Name,
+			Status:    "error",
+			Error:     fmt.Sprintf("invalid arguments: %v; fix the arguments and call %s again", err, call.Name),
+			ErrorType: "invalid_arguments",
+		}
+	}
+	return nil
+}
+
+// errTypeToolUnavailable marks results for tools that can't be reached:
+// never offered to the model, or served by an edge agent that is offline.
+// The model is told so it can answer another way; the turn carries on.
+const errTypeToolUnavailable = "tool_unavailable"
+
+// unavailableResult returns the tool_unavailable result for call.
+func unavailableResult(name, reason string) *ToolResult {
+	return &ToolResult{
+		Tool:      name,
+		Status:    "error",
+		Error:     fmt.Sprintf("tool %q is unavailable: %s", name, reason),
+		ErrorType: errTypeToolUnavailable,
+	}
+}
+
+// checkToolAvailable returns a tool_unavailable result if call names a tool
+// the loop didn't offer, or nil if the call may proceed. With no offered
+// tools to check against (direct dispatch), calls pass through unchecked.
+func (tl *ToolLoop) checkToolAvailable(agent *AgentState, call ToolCall, schemas map[string]ToolSchema) *ToolResult {
+	if len(schemas) == 0 {
+		return nil
+	}
+	if _, ok := schemas[call.Name]; ok {
+		return nil
+	}
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tl.logger.Warn("tool call unavailable", "agent", agent.ID, "tool", call.Name)
+	return unavailableResult(call.Name, fmt.Sprintf(
+		"it is not registered for this agent; available tools: %s. Use one of those or answer without it",
+		strings.Join(names, ", ")))
+}
+
+// toolSchemaIndex maps offered tool schemas by name.
+func toolSchemaIndex(tools []ToolSchema) map[string]ToolSchema {
+	index := make(map[string]ToolSchema, len(tools))
+	for _, t := range tools {
+		index[t.Name] = t
+	}
+	return index
+}
+
 // executeToolCall executes a single tool call
-func (tl *ToolLoop) executeToolCall(agent *AgentState, toolCall ToolCall) (*ToolResult, error) {
+func (tl *ToolLoop) executeToolCall(ctx context.Context, agent *AgentState, toolCall ToolCall) (*ToolResult, error) {
 	start := time.Now()
 
 	// Security policy check: validate tool call before execution
@@ -393,11 +661,12 @@ func (tl *ToolLoop) executeToolCall(agent *AgentState, toolCall ToolCall) (*Tool
 	// edge_call: NL passthrough to a named edge agent's own tool loop.
 	// No tool schema registration needed on the edge — it handles routing itself.
 	if toolCall.Name == "edge_call" {
-		return tl.executeEdgeCall(agent, toolCall, start)
+		return tl.executeEdgeCall(ctx, agent, toolCall, start)
 	}
 
 	// Generate request ID
 	requestID := fmt.Sprintf("tool-%d", time.Now().UnixNano())
+	timeout := callTimeout(ctx, tl.toolManager.GetToolTimeout(toolCall.Name))
 
 	// Build command for edge agent
 	cmd := EdgeAgentCommand{
@@ -406,7 +675,7 @@ func (tl *ToolLoop) executeToolCall(agent *AgentState, toolCall ToolCall) (*Tool
 		Payload: map[string]interface{}{
 			"tool":       toolCall.Name,
 			"parameters": toolCall.Arguments,
-			"timeout_ms": tl.toolManager.GetToolTimeout(toolCall.Name).Milliseconds(),
+			"timeout_ms": timeout.Milliseconds(),
 		},
 	}
 
@@ -419,7 +688,14 @@ func (tl *ToolLoop) executeToolCall(agent *AgentState, toolCall ToolCall) (*Tool
 	// Get MQTT channel
 	mqttChan, ok := tl.orchestrator.channels["mqtt"]
 	if !ok {
-		return nil, fmt.Errorf("MQTT channel not available")
+		res := unavailableResult(toolCall.Name, "no edge agents are connected")
+		res.ElapsedMs = time.Since(start).Milliseconds()
+		return res, nil
+	}
+	if edge := tl.orchestrator.mqttChannel; edge != nil && !edge.IsEdgeAgentOnline(agent.ID) {
+		res := unavailableResult(toolCall.Name, fmt.Sprintf("edge agent %q is offline", agent.ID))
+		res.ElapsedMs = time.Since(start).Milliseconds()
+		return res, nil
 	}
 
 	// Send command via MQTT
@@ -434,13 +710,12 @@ func (tl *ToolLoop) executeToolCall(agent *AgentState, toolCall ToolCall) (*Tool
 		},
 	}
 
-	if err := mqttChan.Send(tl.orchestrator.ctx, resp); err != nil {
+	if err := mqttChan.Send(ctx, resp); err != nil {
 		return nil, fmt.Errorf("send tool command: %w", err)
 	}
 
 	// Wait for result
-	timeout := tl.toolManager.GetToolTimeout(toolCall.Name)
-	result, err := tl.waitForToolResult(requestID, timeout)
+	result, err := tl.waitForToolResult(ctx, requestID, timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -464,8 +739,8 @@ func (tl *ToolLoop) executeToolCall(agent *AgentState, toolCall ToolCall) (*Tool
 }
 
 // waitForToolResult waits for a tool result from the edge agent
-func (tl *ToolLoop) waitForToolResult(requestID string, timeout time.Duration) (*ToolResult, error) {
-	ctx, cancel := context.WithTimeout(tl.orchestrator.ctx, timeout)
+func (tl *ToolLoop) waitForToolResult(ctx context.Context, requestID string, timeout time.Duration) (*ToolResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	// Subscribe to result channel
@@ -480,14 +755,17 @@ func (tl *ToolLoop) waitForToolResult(requestID string, timeout time.Duration) (
 	case result := <-resultChan:
 		return result, nil
 	case <-ctx.Done():
-		return nil, fmt.Errorf("timeout waiting for tool result")
+		return nil, fmt.Errorf("%w: tool result %s", ErrEdgeTimeout, requestID)
 	}
 }
 
 // formatToolResult formats a tool result as a tool message for the LLM
 func (tl *ToolLoop) formatToolResult(toolCall ToolCall, result *ToolResult) ChatMessage {
 	content := result.Result
-	if result.Status == "error" {
+	switch {
+	case result.ErrorType == errTypeToolUnavailable:
+		content = fmt.Sprintf("Tool unavailable: %s", result.Error)
+	case result.Status == "error":
 		content = fmt.Sprintf("Error: %s", result.Error)
 	}
 
@@ -508,7 +786,7 @@ type EdgeAgentCommand struct {
 // executeEdgeCall handles the generic edge_call tool.
 // It sends the query to the named edge agent via MQTT and waits for the
 // agent's own LLM+tool loop to produce a natural language answer.
-func (tl *ToolLoop) executeEdgeCall(agent *AgentState, toolCall ToolCall, start time.Time) (*ToolResult, error) {
+func (tl *ToolLoop) executeEdgeCall(ctx context.Context, agent *AgentState, toolCall ToolCall, start time.Time) (*ToolResult, error) {
 	agentID, _ := toolCall.Arguments["agent_id"].(string)
 	query, _ := toolCall.Arguments["query"].(string)
 
@@ -519,10 +797,25 @@ func (tl *ToolLoop) executeEdgeCall(agent *AgentState, toolCall ToolCall, start
 			Error:  "agent_id is required",
 		}, nil
 	}
+
+	action, _ := toolCall.Arguments["action"].(string)
+	params, _ := toolCall.Arguments["params"].(map[string]interface{})
+
+	// Structured calls are checked against the agent's advertised
+	// capabilities before anything is sent.
+	if action != "" && tl.orchestrator.mqttChannel != nil {
+		specs := tl.orchestrator.mqttChannel.GetEdgeAgentCapabilitySpecs(agentID)
+		if err := validateEdgeAction(specs, action, params); err != nil {
+			return &ToolResult{
+				Tool:   "edge_call",
+				Status: "error",
+				Error:  err.Error(),
+			}, nil
+		}
+	}
+
 	if query == "" {
 		// Fall back to action+params mode if query is empty
-		action, _ := toolCall.Arguments["action"].(string)
-		params, _ := toolCall.Arguments["params"].(map[string]interface{})
 		if action != "" {
 			paramJSON, _ := json.Marshal(params)
 			query = fmt.Sprintf("Execute action: %s with params: %s", action, string(paramJSON))
@@ -536,34 +829,31 @@ func (tl *ToolLoop) executeEdgeCall(agent *AgentState, toolCall ToolCall, start
 	}
 
 	if tl.orchestrator.mqttChannel == nil {
-		return &ToolResult{
-			Tool:   "edge_call",
-			Status: "error",
-			Error:  "MQTT channel not available",
-		}, nil
+		return unavailableResult("edge_call", "no edge agents are connected"), nil
 	}
 
 	if !tl.orchestrator.mqttChannel.IsEdgeAgentOnline(agentID) {
-		return &ToolResult{
-			Tool:   "edge_call",
-			Status: "error",
-			Error:  fmt.Sprintf("edge agent %q is not online", agentID),
-		}, nil
+		return unavailableResult("edge_call", fmt.Sprintf("edge agent %q is not online", agentID)), nil
 	}
 
 	tl.logger.Info("dispatching edge_call", "agent", agentID, "query_len", len(query))
 
-	ctx, cancel := context.WithTimeout(tl.orchestrator.ctx, 60*time.Second)
+	timeout := callTimeout(ctx, 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	resp, err := tl.orchestrator.mqttChannel.SendPromptAndWait(ctx, agentID, query, "", 60*time.Second)
+	resp, err := tl.orchestrator.mqttChannel.SendPromptAndWait(ctx, agentID, query, "", timeout)
 	if err != nil {
-		return &ToolResult{
+		result := &ToolResult{
 			Tool:      "edge_call",
 			Status:    "error",
 			Error:     err.Error(),
 			ElapsedMs: time.Since(start).Milliseconds(),
-		}, nil
+		}
+		if errors.Is(err, ErrEdgeTimeout) {
+			result.ErrorType = "timeout"
+		}
+		return result, nil
 	}
 
 	return &ToolResult{
@@ -0,0 +1,190 @@
+package orchestrator
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// loopingProvider always asks for another tool call, so the tool loop only
+// ends when a budget stops it.
+type loopingProvider struct {
+	name      string
+	mu        sync.Mutex
+	callCount int
+}
+
+func (p *loopingProvider) Name() string { return p.name }
+
+func (p *loopingProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.callCount++
+	return &ChatResponse{
+		Content:   "still working",
+		ToolCalls: []ToolCall{makeCall("tc", "tool_a")},
+	}, nil
+}
+
+func (p *loopingProvider) Models() []config.Model {
+	return []config.Model{{ID: p.name}}
+}
+
+func (p *loopingProvider) calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.callCount
+}
+
+func newBudgetToolLoop(t *testing.T, provider *loopingProvider, exec func(*AgentState, ToolCall) (*ToolResult, error), opts ...ToolLoopOption) *ToolLoop {
+	t.Helper()
+	orch := New(&config.Config{}, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+	orch.RegisterProvider(provider)
+	tl := NewToolLoop(orch, NewToolManager("", nil, orch.logger), opts...)
+	tl.logger = orch.logger
+	tl.execFunc = exec
+	return tl
+}
+
+func TestExecute_MaxIterationsBudget(t *testing.T) {
+	provider := &loopingProvider{name: "test/model"}
+	tl := newBudgetToolLoop(t, provider, func(agent *AgentState, call ToolCall) (*ToolResult, error) {
+		return successResult(call.Name), nil
+	}, WithMaxIterations(3))
+
+	resp, metrics, err := tl.Execute(makeAgent("looper"), Message{Content: "go"}, "test/model")
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+
+	// 3 loop iterations plus one summary call.
+	if got := provider.calls(); got != 4 {
+		t.Errorf("provider calls = %d, want 4", got)
+	}
+	if metrics.TotalIterations != 3 {
+		t.Errorf("TotalIterations = %d, want 3", metrics.TotalIterations)
+	}
+	if !metrics.BudgetExceeded || metrics.BudgetLimit != "max_iterations" {
+		t.Errorf("BudgetExceeded = %v, BudgetLimit = %q", metrics.BudgetExceeded, metrics.BudgetLimit)
+	}
+	if metrics.FinishReason != FinishReasonBudgetExceeded {
+		t.Errorf("FinishReason = %q, want %q", metrics.FinishReason, FinishReasonBudgetExceeded)
+	}
+	if resp.Metadata["finish_reason"] != FinishReasonBudgetExceeded {
+		t.Errorf("response metadata = %v", resp.Metadata)
+	}
+	if resp.Content == "" {
+		t.Error("expected partial content")
+	}
+}
+
+func TestExecute_WallClockBudget(t *testing.T) {
+	provider := &loopingProvider{name: "test/model"}
+	tl := newBudgetToolLoop(t, provider, func(agent *AgentState, call ToolCall) (*ToolResult, error) {
+		time.Sleep(30 * time.Millisecond)
+		return successResult(call.Name), nil
+	}, WithMaxIterations(100), WithBudget(50*time.Millisecond))
+
+	start := time.Now()
+	resp, metrics, err := tl.Execute(makeAgent("looper"), Message{Content: "go"}, "test/model")
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Execute() took %v, budget was 50ms", elapsed)
+	}
+	if metrics.TotalIterations >= 100 {
+		t.Errorf("TotalIterations = %d, want the budget to stop the loop early", metrics.TotalIterations)
+	}
+	if !metrics.BudgetExceeded || metrics.BudgetLimit != "wall_clock" {
+		t.Errorf("BudgetExceeded = %v, BudgetLimit = %q", metrics.BudgetExceeded, metrics.BudgetLimit)
+	}
+	// No summary call once the clock has run out.
+	if got := provider.calls(); got != metrics.TotalIterations {
+		t.Errorf("provider calls = %d, want %d", got, metrics.TotalIterations)
+	}
+	if resp.Content != "still working" {
+		t.Errorf("Content = %q, want last assistant text", resp.Content)
+	}
+	if resp.Metadata["budget_limit"] != "wall_clock" {
+		t.Errorf("response metadata = %v", resp.Metadata)
+	}
+}
+
+// slowProvider answers its first call at once with a tool call, then blocks
+// until the request context is done.
+type slowProvider struct {
+	loopingProvider
+}
+
+func (p *slowProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if p.calls() == 0 {
+		return p.loopingProvider.Chat(ctx, req)
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestExecute_WallClockBudgetBoundsSlowCall(t *testing.T) {
+	provider := &slowProvider{loopingProvider{name: "test/model"}}
+	orch := New(&config.Config{}, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+	orch.RegisterProvider(provider)
+	tl := NewToolLoop(orch, NewToolManager("", nil, orch.logger), WithBudget(100*time.Millisecond))
+	tl.logger = orch.logger
+	tl.execFunc = func(agent *AgentState, call ToolCall) (*ToolResult, error) {
+		return successResult(call.Name), nil
+	}
+
+	start := time.Now()
+	resp, metrics, err := tl.Execute(makeAgent("slow"), Message{Content: "go"}, "test/model")
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Execute() took %v, budget was 100ms", elapsed)
+	}
+	if metrics.BudgetLimit != "wall_clock" || resp.Content != "still working" {
+		t.Errorf("BudgetLimit = %q, Content = %q; want a wall_clock partial answer", metrics.BudgetLimit, resp.Content)
+	}
+}
+
+func TestCallTimeout(t *testing.T) {
+	if got := callTimeout(context.Background(), time.Minute); got != time.Minute {
+		t.Errorf("no deadline: %v, want 1m", got)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if got := callTimeout(ctx, time.Minute); got > time.Second {
+		t.Errorf("with deadline: %v, want at most 1s", got)
+	}
+}
+
+func TestExecute_NoBudgetHit(t *testing.T) {
+	provider := &toolLoopMockProvider{name: "test/model"}
+	orch := newTestOrchestratorForToolLoop(t, provider)
+	tl := NewToolLoop(orch, NewToolManager("", nil, orch.logger), WithBudget(time.Minute))
+
+	resp, metrics, err := tl.Execute(makeAgent("quick"), Message{Content: "hi"}, "test/model")
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+	if metrics.BudgetExceeded || metrics.FinishReason != FinishReasonStop {
+		t.Errorf("BudgetExceeded = %v, FinishReason = %q", metrics.BudgetExceeded, metrics.FinishReason)
+	}
+	if resp.Metadata != nil {
+		t.Errorf("unexpected metadata %v", resp.Metadata)
+	}
+}
+
+func TestWithMaxIterationsIgnoresNonPositive(t *testing.T) {
+	orch := newTestOrchestratorForToolLoop(t, &toolLoopMockProvider{name: "test/model"})
+	tl := NewToolLoop(orch, nil, WithMaxIterations(0))
+	if tl.maxIterations != 10 {
+		t.Errorf("maxIterations = %d, want default 10", tl.maxIterations)
+	}
+}
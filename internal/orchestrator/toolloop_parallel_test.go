@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -91,7 +93,7 @@ func TestParallel_SingleCall(t *testing.T) {
 	agent := makeAgent("agent-1")
 
 	calls := []ToolCall{makeCall("call-1", "tool-a")}
-	results := tl.executeParallel(context.Background(), agent, calls)
+	results := tl.executeParallel(context.Background(), agent, calls, nil)
 
 	if len(results) != 1 {
 		t.Fatalf("expected 1 result, got %d", len(results))
@@ -121,7 +123,7 @@ func TestParallel_TwoCalls(t *testing.T) {
 
 	calls := []ToolCall{makeCall("c1", "t"), makeCall("c2", "t")}
 	start := time.Now()
-	results := tl.executeParallel(context.Background(), agent, calls)
+	results := tl.executeParallel(context.Background(), agent, calls, nil)
 	elapsed := time.Since(start)
 
 	if len(results) != 2 {
@@ -150,7 +152,7 @@ func TestParallel_OneFailsOneSucceeds(t *testing.T) {
 	agent := makeAgent("a")
 
 	calls := []ToolCall{makeCall("fail", "tool"), makeCall("ok", "tool")}
-	results := tl.executeParallel(context.Background(), agent, calls)
+	results := tl.executeParallel(context.Background(), agent, calls, nil)
 
 	if len(results) != 2 {
 		t.Fatalf("want 2 results, got %d", len(results))
@@ -182,7 +184,7 @@ func TestParallel_AllFail(t *testing.T) {
 	agent := makeAgent("a")
 
 	calls := []ToolCall{makeCall("a", "t"), makeCall("b", "t"), makeCall("c", "t")}
-	results := tl.executeParallel(context.Background(), agent, calls)
+	results := tl.executeParallel(context.Background(), agent, calls, nil)
 
 	errorCount := 0
 	for _, r := range results {
@@ -212,7 +214,7 @@ func TestParallel_OrderPreserved(t *testing.T) {
 	agent := makeAgent("a")
 
 	calls := []ToolCall{makeCall("i0", "t"), makeCall("i1", "t"), makeCall("i2", "t")}
-	results := tl.executeParallel(context.Background(), agent, calls)
+	results := tl.executeParallel(context.Background(), agent, calls, nil)
 
 	if len(results) != 3 {
 		t.Fatalf("want 3 results, got %d", len(results))
@@ -258,7 +260,7 @@ func TestParallel_ConcurrencyLimit(t *testing.T) {
 		calls[i] = makeCall(fmt.Sprintf("c%d", i), "t")
 	}
 
-	results := tl.executeParallel(context.Background(), agent, calls)
+	results := tl.executeParallel(context.Background(), agent, calls, nil)
 	if len(results) != 8 {
 		t.Fatalf("want 8 results, got %d", len(results))
 	}
@@ -286,7 +288,7 @@ func TestParallel_ContextCancelled(t *testing.T) {
 
 	calls := []ToolCall{makeCall("c1", "t"), makeCall("c2", "t"), makeCall("c3", "t")}
 	start := time.Now()
-	results := tl.executeParallel(ctx, agent, calls)
+	results := tl.executeParallel(ctx, agent, calls, nil)
 	elapsed := time.Since(start)
 
 	if elapsed > 2*time.Second {
@@ -323,7 +325,7 @@ func TestParallel_MetricsWallTimeSaved(t *testing.T) {
 	calls := []ToolCall{makeCall("m1", "t"), makeCall("m2", "t")}
 
 	batchStart := time.Now()
-	batchResults := tl.executeParallel(context.Background(), agent, calls)
+	batchResults := tl.executeParallel(context.Background(), agent, calls, nil)
 	batchWall := time.Since(batchStart)
 
 	// Compute the same WallTimeSavedMs calculation that Execute() uses
@@ -354,6 +356,7 @@ type toolLoopMockProvider struct {
 	callCount   int
 	mu          sync.Mutex
 	responses   []mockLLMResponse // response sequence per call
+	requests    []ChatRequest
 }
 
 type mockLLMResponse struct {
@@ -368,6 +371,7 @@ func (p *toolLoopMockProvider) Chat(ctx context.Context, req ChatRequest) (*Chat
 	defer p.mu.Unlock()
 	idx := p.callCount
 	p.callCount++
+	p.requests = append(p.requests, req)
 	if idx < len(p.responses) {
 		r := p.responses[idx]
 		return &ChatResponse{Content: r.content, ToolCalls: r.toolCalls}, nil
@@ -520,3 +524,155 @@ func TestExecute_BackwardCompatSingleTool(t *testing.T) {
 		t.Errorf("SuccessCount = %d, want 1", metrics.SuccessCount)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// 11. TestExecute_InvalidArgumentsReturnedToModel — schema validation
+// ---------------------------------------------------------------------------
+
+func TestExecute_InvalidArgumentsReturnedToModel(t *testing.T) {
+	dir := t.TempDir()
+	skillDir := filepath.Join(dir, "files")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	skill := `
+[[tools]]
+name = "read_file"
+description = "Read a file"
+
+[tools.parameters]
+required = ["path"]
+
+[tools.parameters.properties.path]
+type = "string"
+description = "File to read"
+`
+	if err := os.WriteFile(filepath.Join(skillDir, "skill.toml"), []byte(skill), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := ToolCall{ID: "tc1", Name: "read_file", Arguments: map[string]interface{}{"file": "notes.txt"}}
+	good := ToolCall{ID: "tc2", Name: "read_file", Arguments: map[string]interface{}{"path": "notes.txt"}}
+	provider := &toolLoopMockProvider{
+		name: "test/model",
+		responses: []mockLLMResponse{
+			{toolCalls: []ToolCall{bad}},
+			{toolCalls: []ToolCall{good}},
+			{content: "read it"},
+		},
+	}
+	orch := newTestOrchestratorForToolLoop(t, provider)
+
+	var mu sync.Mutex
+	var executed []ToolCall
+	tl := NewToolLoop(orch, NewToolManager(dir, nil, orch.logger))
+	tl.execFunc = func(agent *AgentState, call ToolCall) (*ToolResult, error) {
+		mu.Lock()
+		executed = append(executed, call)
+		mu.Unlock()
+		return successResult(call.Name), nil
+	}
+
+	resp, metrics, err := tl.Execute(makeAgent("agent-validate"), Message{Content: "read notes"}, "test/model")
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+	if resp.Content != "read it" {
+		t.Errorf("Content = %q, want %q", resp.Content, "read it")
+	}
+
+	// Only the corrected call ran.
+	if len(executed) != 1 || executed[0].ID != "tc2" {
+		t.Fatalf("executed = %+v, want only tc2", executed)
+	}
+	if metrics.ErrorCount != 1 || metrics.SuccessCount != 1 {
+		t.Errorf("ErrorCount = %d, SuccessCount = %d, want 1 and 1", metrics.ErrorCount, metrics.SuccessCount)
+	}
+
+	// The model saw the validation error on its retry.
+	retry := provider.requests[1].Messages
+	last := retry[len(retry)-1]
+	if last.Role != "tool" || last.ToolCallID != "tc1" || !strings.Contains(last.Content, `missing required param "path"`) {
+		t.Errorf("tool message = %+v, want validation error for tc1", last)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// 12. TestExecute_UnavailableToolReturnedToModel — unknown tool, loop continues
+// ---------------------------------------------------------------------------
+
+func TestExecute_UnavailableToolReturnedToModel(t *testing.T) {
+	missing := makeCall("tc1", "launch_rocket")
+	provider := &toolLoopMockProvider{
+		name: "test/model",
+		responses: []mockLLMResponse{
+			{toolCalls: []ToolCall{missing}},
+			{toolCalls: []ToolCall{missing}},
+			{toolCalls: []ToolCall{missing}},
+			{toolCalls: []ToolCall{makeCall("tc2", "clock")}},
+			{content: "it is noon"},
+		},
+	}
+	orch := newTestOrchestratorForToolLoop(t, provider)
+
+	var mu sync.Mutex
+	var executed []string
+	tl := NewToolLoop(orch, newScopedToolManager(t))
+	tl.execFunc = func(agent *AgentState, call ToolCall) (*ToolResult, error) {
+		mu.Lock()
+		executed = append(executed, call.Name)
+		mu.Unlock()
+		return successResult(call.Name), nil
+	}
+
+	agent := &AgentState{ID: "agent-unavailable", Def: config.AgentDef{ID: "agent-unavailable"}}
+	resp, metrics, err := tl.Execute(agent, Message{Content: "what time is it?"}, "test/model")
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+	if resp.Content != "it is noon" {
+		t.Errorf("Content = %q, want %q", resp.Content, "it is noon")
+	}
+
+	// The unknown tool never ran, and repeated calls to it didn't end the turn.
+	if len(executed) != 1 || executed[0] != "clock" {
+		t.Errorf("executed = %v, want only clock", executed)
+	}
+	if metrics.UnavailableCount != 3 || metrics.ErrorCount != 3 || metrics.SuccessCount != 1 {
+		t.Errorf("UnavailableCount = %d, ErrorCount = %d, SuccessCount = %d, want 3, 3 and 1",
+			metrics.UnavailableCount, metrics.ErrorCount, metrics.SuccessCount)
+	}
+
+	// The model was told the tool is unavailable and what it can use instead.
+	retry := provider.requests[1].Messages
+	last := retry[len(retry)-1]
+	if last.Role != "tool" || last.ToolCallID != "tc1" ||
+		!strings.Contains(last.Content, "Tool unavailable") || !strings.Contains(last.Content, "clock") {
+		t.Errorf("tool message = %+v, want unavailable result listing clock", last)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// 13. TestExecuteToolCall_NoEdgeAgentIsUnavailable — no MQTT, no turn error
+// ---------------------------------------------------------------------------
+
+func TestExecuteToolCall_NoEdgeAgentIsUnavailable(t *testing.T) {
+	orch := newTestOrchestratorForToolLoop(t, &toolLoopMockProvider{name: "test/model"})
+	tl := NewToolLoop(orch, NewToolManager("", nil, orch.logger))
+
+	results := tl.executeParallel(context.Background(), makeAgent("agent-edge"), []ToolCall{makeCall("tc1", "read_sensor")}, nil)
+	if results[0].Err != nil {
+		t.Fatalf("Err = %v, want an unavailable result", results[0].Err)
+	}
+	res := results[0].Result
+	if res == nil || res.Status != "error" || res.ErrorType != errTypeToolUnavailable {
+		t.Errorf("result = %+v, want tool_unavailable", res)
+	}
+
+	edge := tl.executeParallel(context.Background(), makeAgent("agent-edge"), []ToolCall{{
+		ID: "tc2", Name: "edge_call", Arguments: map[string]interface{}{"agent_id": "pi-1", "query": "temperature?"},
+	}}, nil)
+	if res := edge[0].Result; res == nil || res.ErrorType != errTypeToolUnavailable {
+		t.Errorf("edge_call result = %+v, want tool_unavailable", res)
+	}
+}
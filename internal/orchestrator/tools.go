@@ -10,6 +10,8 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"log/slog"
+
+	"github.com/clawinfra/evoclaw/internal/config"
 )
 
 // ToolDefinition represents a tool from skill.toml
@@ -62,6 +64,7 @@ type ToolManager struct {
 	logger       *slog.Logger
 	cache        map[string][]ToolSchema
 	builtinTools map[string]*BuiltinTool // pi-style built-in tools (name → tool)
+	metrics      toolMetrics
 	mu           sync.RWMutex
 }
 
@@ -81,10 +84,23 @@ func NewToolManager(skillsPath string, capabilities []string, logger *slog.Logge
 	}
 }
 
-// GenerateSchemas generates LLM tool schemas for all available tools
+// GenerateSchemas generates LLM tool schemas for all tools permitted by the
+// manager's capabilities
 func (tm *ToolManager) GenerateSchemas() ([]ToolSchema, error) {
+	return tm.cachedSchemas("all", tm.FilterByCapabilities)
+}
+
+// allSchemas generates LLM tool schemas for every skill tool, regardless of
+// capabilities
+func (tm *ToolManager) allSchemas() ([]ToolSchema, error) {
+	return tm.cachedSchemas("unfiltered", func(tools []ToolDefinition) []ToolDefinition { return tools })
+}
+
+// cachedSchemas discovers skill tools, applies filter and caches the
+// resulting schemas under key
+func (tm *ToolManager) cachedSchemas(key string, filter func([]ToolDefinition) []ToolDefinition) ([]ToolSchema, error) {
 	tm.mu.RLock()
-	if cached, ok := tm.cache["all"]; ok {
+	if cached, ok := tm.cache[key]; ok {
 		tm.mu.RUnlock()
 		return cached, nil
 	}
@@ -98,7 +114,7 @@ func (tm *ToolManager) GenerateSchemas() ([]ToolSchema, error) {
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Skills directory doesn't exist yet — return empty schema set (not an error)
-			tm.cache["all"] = []ToolSchema{}
+			tm.cache[key] = []ToolSchema{}
 			return []ToolSchema{}, nil
 		}
 		return nil, fmt.Errorf("read skills directory: %w", err)
@@ -124,7 +140,7 @@ func (tm *ToolManager) GenerateSchemas() ([]ToolSchema, error) {
 	}
 
 	// Filter by capabilities
-	filtered := tm.FilterByCapabilities(allTools)
+	filtered := filter(allTools)
 
 	// Convert to schemas
 	schemas := make([]ToolSchema, 0, len(filtered))
@@ -138,7 +154,7 @@ func (tm *ToolManager) GenerateSchemas() ([]ToolSchema, error) {
 	}
 
 	// Cache results
-	tm.cache["all"] = schemas
+	tm.cache[key] = schemas
 
 	tm.logger.Info("generated tool schemas", "count", len(schemas))
 	return schemas, nil
@@ -225,7 +241,7 @@ func (tm *ToolManager) toolAllowed(tool ToolDefinition) bool {
 	// Check if tool requires any capability that agent has
 	for _, perm := range tool.Permissions {
 		for _, cap := range tm.capabilities {
-			if perm == cap {
+			if config.ParseCapability(cap).Satisfies(perm) {
 				return true
 			}
 		}
@@ -239,6 +255,70 @@ func (tm *ToolManager) toolAllowed(tool ToolDefinition) bool {
 	return false
 }
 
+// SchemasForAgent returns the tool schemas an agent may use: skill tools
+// whose permissions its capabilities cover, narrowed by its allowed and
+// denied tool lists.
+func (tm *ToolManager) SchemasForAgent(def config.AgentDef) ([]ToolSchema, error) {
+	all, err := tm.allSchemas()
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make([]ToolSchema, 0, len(all))
+	for _, schema := range all {
+		if checkToolAccess(def, schema.Name, schema.EvoClawMeta.Permissions) == nil {
+			schemas = append(schemas, schema)
+		}
+	}
+	return schemas, nil
+}
+
+// CheckAgentTool returns an error explaining why the agent may not call the
+// named tool, or nil if it may. Tools without a skill definition (such as
+// edge_call) are only subject to the allowed and denied lists.
+func (tm *ToolManager) CheckAgentTool(def config.AgentDef, name string) error {
+	all, err := tm.allSchemas()
+	if err != nil {
+		return err
+	}
+	for _, schema := range all {
+		if schema.Name == name {
+			return checkToolAccess(def, name, schema.EvoClawMeta.Permissions)
+		}
+	}
+	return checkToolAccess(def, name, nil)
+}
+
+// checkToolAccess applies an agent's deny list, allow list and capabilities
+// to a tool requiring any one of permissions. Unlike toolAllowed, an agent
+// without capabilities may only use tools that require none.
+func checkToolAccess(def config.AgentDef, name string, permissions []string) error {
+	if containsTool(def.DeniedTools, name) {
+		return fmt.Errorf("tool %q is denied for agent %s", name, def.ID)
+	}
+	if len(def.AllowedTools) > 0 && !containsTool(def.AllowedTools, name) {
+		return fmt.Errorf("tool %q is not in the allowed tools for agent %s", name, def.ID)
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+	for _, perm := range permissions {
+		if def.Capabilities.Satisfies(perm) {
+			return nil
+		}
+	}
+	return fmt.Errorf("tool %q requires one of %v, agent %s has capabilities %v", name, permissions, def.ID, def.Capabilities.Strings())
+}
+
+func containsTool(list []string, name string) bool {
+	for _, v := range list {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
 // GetToolTimeout returns the default timeout for a tool
 func (tm *ToolManager) GetToolTimeout(toolName string) time.Duration {
 	// Default timeouts by tool category
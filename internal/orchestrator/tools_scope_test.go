@@ -0,0 +1,150 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+const scopedSkillTOML = `
+[[tools]]
+name = "read_file"
+description = "Read a file"
+permissions = ["filesystem"]
+
+[[tools]]
+name = "send_payment"
+description = "Move funds"
+permissions = ["payments"]
+
+[[tools]]
+name = "clock"
+description = "Current time"
+`
+
+func newScopedToolManager(t *testing.T) *ToolManager {
+	t.Helper()
+	dir := t.TempDir()
+	skillDir := filepath.Join(dir, "ops")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "skill.toml"), []byte(scopedSkillTOML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return NewToolManager(dir, nil, testLogger())
+}
+
+func schemaNames(schemas []ToolSchema) []string {
+	names := make([]string, len(schemas))
+	for i, s := range schemas {
+		names[i] = s.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSchemasForAgent(t *testing.T) {
+	tm := newScopedToolManager(t)
+
+	tests := []struct {
+		name string
+		def  config.AgentDef
+		want string
+	}{
+		{"restricted", config.AgentDef{ID: "r", Capabilities: config.NewCapabilities("filesystem")}, "clock,read_file"},
+		{"privileged", config.AgentDef{ID: "p", Capabilities: config.NewCapabilities("filesystem", "payments")}, "clock,read_file,send_payment"},
+		{"no capabilities", config.AgentDef{ID: "n"}, "clock"},
+		{"allow list", config.AgentDef{ID: "a", Capabilities: config.NewCapabilities("filesystem", "payments"), AllowedTools: []string{"clock", "send_payment"}}, "clock,send_payment"},
+		{"deny list", config.AgentDef{ID: "d", Capabilities: config.NewCapabilities("filesystem", "payments"), DeniedTools: []string{"send_payment"}}, "clock,read_file"},
+		{"deny beats allow", config.AgentDef{ID: "b", Capabilities: config.NewCapabilities("filesystem"), AllowedTools: []string{"read_file"}, DeniedTools: []string{"read_file"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemas, err := tm.SchemasForAgent(tt.def)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(schemaNames(schemas), ","); got != tt.want {
+				t.Errorf("tools = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAgentTool(t *testing.T) {
+	tm := newScopedToolManager(t)
+	restricted := config.AgentDef{ID: "restricted", Capabilities: config.NewCapabilities("filesystem"), DeniedTools: []string{"edge_call"}}
+
+	if err := tm.CheckAgentTool(restricted, "read_file"); err != nil {
+		t.Errorf("read_file should be permitted: %v", err)
+	}
+	err := tm.CheckAgentTool(restricted, "send_payment")
+	if err == nil || !strings.Contains(err.Error(), "payments") {
+		t.Errorf("send_payment should be refused naming the missing capability, got %v", err)
+	}
+	if err := tm.CheckAgentTool(restricted, "edge_call"); err == nil {
+		t.Error("denied edge_call should be refused")
+	}
+	// Tools without a skill definition are left to the edge agent.
+	if err := tm.CheckAgentTool(restricted, "device_tool"); err != nil {
+		t.Errorf("undefined tool should only be subject to allow/deny lists: %v", err)
+	}
+}
+
+func TestToolLoop_RestrictedAgentCannotInvokePrivilegedTool(t *testing.T) {
+	provider := &toolLoopMockProvider{
+		name: "test/model",
+		responses: []mockLLMResponse{
+			{toolCalls: []ToolCall{makeCall("tc1", "send_payment"), makeCall("tc2", "read_file")}},
+			{content: "done"},
+		},
+	}
+	orch := newTestOrchestratorForToolLoop(t, provider)
+
+	var mu sync.Mutex
+	var executed []string
+	tl := &ToolLoop{
+		orchestrator:   orch,
+		toolManager:    newScopedToolManager(t),
+		logger:         orch.logger,
+		maxIterations:  10,
+		errorLimit:     3,
+		defaultTimeout: 30 * time.Second,
+		maxParallel:    5,
+		rsiLogger:      NoopRSILogger{},
+		execFunc: func(agent *AgentState, call ToolCall) (*ToolResult, error) {
+			mu.Lock()
+			executed = append(executed, call.Name)
+			mu.Unlock()
+			return &ToolResult{Tool: call.Name, Status: "success"}, nil
+		},
+	}
+
+	agent := &AgentState{ID: "restricted", Def: config.AgentDef{ID: "restricted", Capabilities: config.NewCapabilities("filesystem")}}
+	_, metrics, err := tl.Execute(agent, Message{Content: "pay", From: "user"}, "test/model")
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if len(executed) != 1 || executed[0] != "read_file" {
+		t.Errorf("executed = %v, want only read_file", executed)
+	}
+	if metrics.SuccessCount != 1 || metrics.ErrorCount != 1 {
+		t.Errorf("metrics = %+v, want 1 success and 1 error", metrics)
+	}
+
+	// The model receives a clear error instead of a result.
+	results := tl.executeParallel(context.Background(), agent, []ToolCall{makeCall("tc3", "send_payment")}, nil)
+	res := results[0].Result
+	if res == nil || res.Status != "error" || res.ErrorType != "tool_denied" || !strings.Contains(res.Error, "not permitted") {
+		t.Errorf("expected tool_denied error result, got %+v", res)
+	}
+}
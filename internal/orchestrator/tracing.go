@@ -0,0 +1,47 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/clawinfra/evoclaw/internal/tracing"
+)
+
+// SetTracer enables tracing of message processing. Nothing is traced when
+// nil.
+func (o *Orchestrator) SetTracer(t *tracing.Tracer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.tracer = t
+}
+
+// Tracer returns the tracer, or nil if tracing is disabled.
+func (o *Orchestrator) Tracer() *tracing.Tracer {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.tracer
+}
+
+// traceContext returns the orchestrator context carrying msg's trace
+// context, so spans for work done on behalf of msg join its trace.
+func (o *Orchestrator) traceContext(msg Message) context.Context {
+	if o.Tracer() == nil {
+		return o.ctx
+	}
+	return tracing.Extract(o.ctx, msg.Metadata[tracing.HeaderName])
+}
+
+// withTraceparent returns msg carrying the trace context of ctx in its
+// metadata. msg.Metadata is copied, not modified.
+func withTraceparent(ctx context.Context, msg Message) Message {
+	tp := tracing.Traceparent(ctx)
+	if tp == "" {
+		return msg
+	}
+	md := make(map[string]string, len(msg.Metadata)+1)
+	for k, v := range msg.Metadata {
+		md[k] = v
+	}
+	md[tracing.HeaderName] = tp
+	msg.Metadata = md
+	return msg
+}
@@ -0,0 +1,189 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+	"github.com/clawinfra/evoclaw/internal/tracing"
+)
+
+// traceCapturingProvider records the traceparent of each request context.
+type traceCapturingProvider struct {
+	mu           sync.Mutex
+	traceparents []string
+}
+
+func (p *traceCapturingProvider) Name() string { return "mock" }
+
+func (p *traceCapturingProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	p.mu.Lock()
+	p.traceparents = append(p.traceparents, tracing.Traceparent(ctx))
+	p.mu.Unlock()
+	return &ChatResponse{Content: "ok", TokensInput: 10, TokensOutput: 5}, nil
+}
+
+func (p *traceCapturingProvider) Models() []config.Model { return nil }
+
+func (p *traceCapturingProvider) seen() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.traceparents...)
+}
+
+func spansByName(spans []tracing.SpanData) map[string][]tracing.SpanData {
+	out := make(map[string][]tracing.SpanData)
+	for _, s := range spans {
+		out[s.Name] = append(out[s.Name], s)
+	}
+	return out
+}
+
+func waitForSpan(t *testing.T, exp *tracing.MemoryExporter, name string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(spansByName(exp.Spans())[name]) > 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("span %q was not exported; got %+v", name, exp.Spans())
+}
+
+func TestTracing_MessageSpanHierarchy(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	exp := tracing.NewMemoryExporter()
+	o.SetTracer(tracing.New(exp))
+	provider := &traceCapturingProvider{}
+	o.RegisterProvider(provider)
+	o.agents["test-agent"] = &AgentState{
+		ID:      "test-agent",
+		Def:     config.AgentDef{ID: "test-agent", Model: "mock/mock-model-1"},
+		Metrics: AgentMetrics{Custom: make(map[string]float64)},
+	}
+
+	o.handleMessage(Message{ID: "m1", Channel: "telegram", From: "42", Content: "hello"})
+	waitForSpan(t, exp, "message")
+
+	byName := spansByName(exp.Spans())
+	if len(byName["message"]) != 1 || len(byName["select_agent"]) != 1 || len(byName["llm.call"]) != 1 {
+		t.Fatalf("spans = %+v", exp.Spans())
+	}
+	root, sel, call := byName["message"][0], byName["select_agent"][0], byName["llm.call"][0]
+
+	if root.ParentID != "" {
+		t.Errorf("message span has parent %s, want root", root.ParentID)
+	}
+	for _, child := range []tracing.SpanData{sel, call} {
+		if child.TraceID != root.TraceID || child.ParentID != root.SpanID {
+			t.Errorf("%s: trace %s parent %s, want trace %s parent %s",
+				child.Name, child.TraceID, child.ParentID, root.TraceID, root.SpanID)
+		}
+	}
+	if root.Attributes["agent.id"] != "test-agent" || root.Attributes["messaging.channel"] != "telegram" {
+		t.Errorf("message attributes = %v", root.Attributes)
+	}
+	if call.Attributes["llm.model"] != "mock/mock-model-1" || call.Attributes["llm.tokens_output"] != 5 {
+		t.Errorf("llm.call attributes = %v", call.Attributes)
+	}
+	if root.End.Before(call.End) {
+		t.Error("message span ended before the model call")
+	}
+
+	// The provider request carried the llm.call span as its parent.
+	seen := provider.seen()
+	if len(seen) != 1 || seen[0] != "00-"+call.TraceID+"-"+call.SpanID+"-01" {
+		t.Errorf("provider traceparent = %v, want llm.call span", seen)
+	}
+}
+
+func TestTracing_ContinuesUpstreamTrace(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	exp := tracing.NewMemoryExporter()
+	o.SetTracer(tracing.New(exp))
+	o.RegisterProvider(&traceCapturingProvider{})
+	o.agents["test-agent"] = &AgentState{ID: "test-agent", Def: config.AgentDef{ID: "test-agent"}, Metrics: AgentMetrics{Custom: map[string]float64{}}}
+
+	upstream := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	o.handleMessage(Message{Content: "hi", Metadata: map[string]string{tracing.HeaderName: upstream}})
+	waitForSpan(t, exp, "message")
+
+	root := spansByName(exp.Spans())["message"][0]
+	if root.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || root.ParentID != "00f067aa0ba902b7" {
+		t.Errorf("message span = trace %s parent %s, want upstream", root.TraceID, root.ParentID)
+	}
+}
+
+func TestTracing_ToolLoopIterations(t *testing.T) {
+	provider := &loopingProvider{name: "test/model"}
+	tl := newBudgetToolLoop(t, provider, func(agent *AgentState, call ToolCall) (*ToolResult, error) {
+		return successResult(call.Name), nil
+	}, WithMaxIterations(2))
+	exp := tracing.NewMemoryExporter()
+	tracer := tracing.New(exp)
+	tl.orchestrator.SetTracer(tracer)
+
+	ctx, root := tracer.Start(context.Background(), "message")
+	msg := withTraceparent(ctx, Message{Content: "go"})
+	if _, _, err := tl.Execute(makeAgent("looper"), msg, "test/model"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	root.End()
+
+	byName := spansByName(exp.Spans())
+	iters, calls := byName["tool_loop.iteration"], byName["llm.call"]
+	if len(iters) != 2 {
+		t.Fatalf("got %d iteration spans, want 2", len(iters))
+	}
+	// One call per iteration plus the summary call.
+	if len(calls) != 3 {
+		t.Fatalf("got %d llm.call spans, want 3", len(calls))
+	}
+
+	rootID := root.SpanContext()
+	parents := map[string]bool{}
+	for i, it := range iters {
+		if it.ParentID != hexSpanID(rootID) {
+			t.Errorf("iteration %d parent = %s, want message span", i, it.ParentID)
+		}
+		if it.Attributes["tool_loop.iteration"] != i || it.Attributes["tool_loop.tool_calls"] != 1 {
+			t.Errorf("iteration %d attributes = %v", i, it.Attributes)
+		}
+		parents[it.SpanID] = true
+	}
+	var underIteration, underRoot int
+	for _, c := range calls {
+		switch {
+		case parents[c.ParentID]:
+			underIteration++
+		case c.ParentID == hexSpanID(rootID):
+			underRoot++ // the summary call
+		}
+	}
+	if underIteration != 2 || underRoot != 1 {
+		t.Errorf("llm.call parents: %d under iterations, %d under message; want 2 and 1", underIteration, underRoot)
+	}
+}
+
+func TestTracing_DisabledIsNoop(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	provider := &traceCapturingProvider{}
+	o.RegisterProvider(provider)
+
+	msg := Message{Content: "hi", Metadata: map[string]string{tracing.HeaderName: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}}
+	agent := &AgentState{ID: "a1", Def: config.AgentDef{ID: "a1"}}
+	if _, _, err := o.processDirect(agent, msg, "mock/mock-model-1"); err != nil {
+		t.Fatalf("processDirect: %v", err)
+	}
+	if seen := provider.seen(); len(seen) != 1 || seen[0] != "" {
+		t.Errorf("provider traceparent = %v, want none with tracing disabled", seen)
+	}
+}
+
+func hexSpanID(sc tracing.SpanContext) string {
+	return hex.EncodeToString(sc.SpanID[:])
+}
@@ -0,0 +1,77 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// warmupTimeout bounds a warmup call; loading a large local model can
+// take a while.
+const warmupTimeout = 2 * time.Minute
+
+// warmupAgents sends a one-token prompt to the model of every local,
+// unpaused agent whose model runs on Ollama, so loading the model happens
+// before the first real message. Hosted models have nothing to load and
+// would be billed, so they are not warmed. Agents sharing a model warm it
+// once. The calls run in the background and failures are only logged.
+func (o *Orchestrator) warmupAgents() {
+	o.mu.RLock()
+	agents := make([]*AgentState, 0, len(o.agents))
+	for _, a := range o.agents {
+		agents = append(agents, a)
+	}
+	o.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, a := range agents {
+		a.mu.RLock()
+		remote := a.Def.Remote
+		a.mu.RUnlock()
+		if remote || a.isPaused() {
+			continue
+		}
+		model := o.preferredModel(a)
+		if !isLocalModel(model) || seen[model] {
+			continue
+		}
+		seen[model] = true
+		o.safeGo("warmup", func() { o.warmupModel(model) })
+	}
+}
+
+// isLocalModel reports whether model is served by the local Ollama
+// provider.
+func isLocalModel(model string) bool {
+	provider, _, ok := strings.Cut(model, "/")
+	return ok && provider == "ollama"
+}
+
+// warmupModel sends model a minimal prompt. The answer is discarded and
+// not charged to any agent.
+func (o *Orchestrator) warmupModel(model string) {
+	// findProvider falls back to any provider; warming a different one
+	// would only spend a call on the wrong model.
+	provider := o.findProvider(model)
+	if provider == nil || !strings.HasPrefix(model, provider.Name()+"/") {
+		o.logger.Warn("model warmup skipped: no provider", "model", model)
+		return
+	}
+	req := ChatRequest{
+		Model:     model,
+		Messages:  []ChatMessage{{Role: "user", Content: "ping"}},
+		MaxTokens: 1,
+	}
+	if _, id, ok := strings.Cut(model, "/"); ok {
+		req.Model = id
+	}
+
+	ctx, cancel := context.WithTimeout(o.ctx, warmupTimeout)
+	defer cancel()
+	start := time.Now()
+	if _, err := provider.Chat(ctx, req); err != nil {
+		o.logger.Warn("model warmup failed", "model", model, "provider", provider.Name(), "error", err)
+		return
+	}
+	o.logger.Info("model warmed up", "model", model, "duration", time.Since(start))
+}
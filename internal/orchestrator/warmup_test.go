@@ -0,0 +1,121 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/config"
+)
+
+// warmupProvider reports the model of each call on calls.
+type warmupProvider struct {
+	*mockProvider
+	calls chan ChatRequest
+	err   error
+}
+
+func (p *warmupProvider) Chat(_ context.Context, req ChatRequest) (*ChatResponse, error) {
+	p.calls <- req
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &ChatResponse{Content: "pong", Model: req.Model}, nil
+}
+
+func newWarmupOrchestrator(t *testing.T, providerErr error) (*Orchestrator, *warmupProvider) {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Evolution.Enabled = false
+	cfg.Server.WarmupOnStart = true
+	cfg.Agents = []config.AgentDef{
+		{ID: "a", Model: "ollama/llama3"},
+		{ID: "b", Model: "ollama/qwen2"},
+		{ID: "c", Model: "ollama/llama3"}, // shares a's model
+		{ID: "pi", Model: "ollama/tiny", Remote: true},
+		{ID: "hosted", Model: "openai/gpt-4o"}, // billed, nothing to load
+	}
+	o := New(cfg, testLogger())
+	p := &warmupProvider{mockProvider: newMockProvider("ollama"), calls: make(chan ChatRequest, 10), err: providerErr}
+	o.RegisterProvider(p)
+	o.RegisterChannel(newMockChannel("test"))
+	return o, p
+}
+
+// collectWarmups waits for n warmup calls, then for stray extra ones.
+func collectWarmups(t *testing.T, p *warmupProvider, n int) []string {
+	t.Helper()
+	var models []string
+	timeout := time.After(2 * time.Second)
+	for len(models) < n {
+		select {
+		case req := <-p.calls:
+			models = append(models, req.Model)
+		case <-timeout:
+			t.Fatalf("got %d warmup calls, want %d", len(models), n)
+		}
+	}
+	select {
+	case req := <-p.calls:
+		t.Errorf("unexpected extra warmup call for %s", req.Model)
+	case <-time.After(50 * time.Millisecond):
+	}
+	sort.Strings(models)
+	return models
+}
+
+func TestWarmupOnStart(t *testing.T) {
+	o, p := newWarmupOrchestrator(t, nil)
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = o.Stop() }()
+
+	models := collectWarmups(t, p, 2)
+	if models[0] != "llama3" || models[1] != "qwen2" {
+		t.Errorf("warmed %v, want each local agent's Ollama model once", models)
+	}
+
+	// Warmup is not a message: nothing is charged or counted
+	for _, id := range []string{"a", "b", "c"} {
+		o.mu.RLock()
+		agent := o.agents[id]
+		o.mu.RUnlock()
+		agent.mu.RLock()
+		if agent.MessageCount != 0 || agent.Metrics.CostUSD != 0 {
+			t.Errorf("agent %s: messages %d, cost %v after warmup", id, agent.MessageCount, agent.Metrics.CostUSD)
+		}
+		agent.mu.RUnlock()
+	}
+}
+
+func TestWarmupOnStart_FailureNonFatal(t *testing.T) {
+	o, p := newWarmupOrchestrator(t, errors.New("model not found"))
+	if err := o.Start(); err != nil {
+		t.Fatalf("a failed warmup must not fail Start: %v", err)
+	}
+	defer func() { _ = o.Stop() }()
+	collectWarmups(t, p, 2)
+}
+
+func TestWarmupOnStart_Disabled(t *testing.T) {
+	o, p := newWarmupOrchestrator(t, nil)
+	o.cfg.Server.WarmupOnStart = false
+	if err := o.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = o.Stop() }()
+	collectWarmups(t, p, 0)
+}
+
+func TestWarmupModel_NeedsItsOwnProvider(t *testing.T) {
+	o := New(testConfig(), testLogger())
+	hosted := &warmupProvider{mockProvider: newMockProvider("openai"), calls: make(chan ChatRequest, 1)}
+	o.RegisterProvider(hosted)
+
+	// findProvider falls back to openai; warming it is pointless.
+	o.warmupModel("ollama/llama3")
+	collectWarmups(t, hosted, 0)
+}
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/clawinfra/evoclaw/internal/clock"
 )
 
 // ModelState represents the health state of a model.
@@ -71,6 +73,33 @@ type HealthRegistry struct {
 	cfg     HealthConfig
 	logger  *slog.Logger
 	dirty   bool // Track if state needs persisting
+	// onChange is called, outside the lock, when a model is degraded or
+	// recovers
+	onChange func(StateChange)
+	clock    clock.Clock
+}
+
+// StateChange describes a model moving to or from the degraded state.
+type StateChange struct {
+	Model               string
+	From, To            ModelState
+	ConsecutiveFailures int
+	ErrorType           string
+}
+
+// OnStateChange registers fn to be called whenever a model is degraded or
+// recovers. It replaces any earlier callback.
+func (hr *HealthRegistry) OnStateChange(fn func(StateChange)) {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	hr.onChange = fn
+}
+
+// SetClock replaces the clock used for failure times and cooldowns.
+func (hr *HealthRegistry) SetClock(c clock.Clock) {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	hr.clock = c
 }
 
 // HealthSnapshot is the persisted state format.
@@ -90,6 +119,7 @@ func NewHealthRegistry(cfg HealthConfig, logger *slog.Logger) (*HealthRegistry,
 		models: make(map[string]*ModelHealth),
 		cfg:    cfg,
 		logger: logger.With("component", "health-registry"),
+		clock:  clock.System,
 	}
 
 	// Try to load existing state
@@ -117,10 +147,17 @@ func (hr *HealthRegistry) getOrCreate(modelID string) *ModelHealth {
 // RecordSuccess records a successful model call.
 func (hr *HealthRegistry) RecordSuccess(modelID string) {
 	hr.mu.Lock()
-	defer hr.mu.Unlock()
+	var change *StateChange
+	defer func() {
+		notify := hr.onChange
+		hr.mu.Unlock()
+		if change != nil && notify != nil {
+			notify(*change)
+		}
+	}()
 
 	h := hr.getOrCreate(modelID)
-	now := time.Now()
+	now := hr.clock.Now()
 
 	h.LastSuccess = &now
 	h.ConsecutiveFailures = 0
@@ -132,6 +169,7 @@ func (hr *HealthRegistry) RecordSuccess(modelID string) {
 		h.State = StateHealthy
 		h.DegradedAt = nil
 		hr.logger.Info("model recovered", "model", modelID)
+		change = &StateChange{Model: modelID, From: StateDegraded, To: StateHealthy}
 	case StateUnknown:
 		h.State = StateHealthy
 	}
@@ -147,10 +185,17 @@ func (hr *HealthRegistry) RecordSuccess(modelID string) {
 // RecordFailure records a failed model call.
 func (hr *HealthRegistry) RecordFailure(modelID string, errType string) {
 	hr.mu.Lock()
-	defer hr.mu.Unlock()
+	var change *StateChange
+	defer func() {
+		notify := hr.onChange
+		hr.mu.Unlock()
+		if change != nil && notify != nil {
+			notify(*change)
+		}
+	}()
 
 	h := hr.getOrCreate(modelID)
-	now := time.Now()
+	now := hr.clock.Now()
 
 	h.LastFailure = &now
 	h.LastErrorType = errType
@@ -171,6 +216,13 @@ func (hr *HealthRegistry) RecordFailure(modelID string, errType string) {
 
 	// Check if should degrade
 	if h.ConsecutiveFailures >= hr.cfg.FailureThreshold && h.State != StateDegraded {
+		change = &StateChange{
+			Model:               modelID,
+			From:                h.State,
+			To:                  StateDegraded,
+			ConsecutiveFailures: h.ConsecutiveFailures,
+			ErrorType:           errType,
+		}
 		h.State = StateDegraded
 		h.DegradedAt = &now
 		hr.logger.Warn("model degraded",
@@ -195,7 +247,7 @@ func (hr *HealthRegistry) IsHealthy(modelID string) bool {
 
 	// Check if degraded but cooldown expired (auto-recovery)
 	if h.State == StateDegraded && hr.cfg.AutoRecover && h.DegradedAt != nil {
-		if time.Since(*h.DegradedAt) > hr.cfg.CooldownPeriod {
+		if clock.Since(hr.clock, *h.DegradedAt) > hr.cfg.CooldownPeriod {
 			return true // Allow retry
 		}
 		return false
@@ -239,7 +291,7 @@ func (hr *HealthRegistry) isHealthyLocked(modelID string) bool {
 	if h.State == StateDegraded {
 		// Check cooldown
 		if hr.cfg.AutoRecover && h.DegradedAt != nil {
-			if time.Since(*h.DegradedAt) > hr.cfg.CooldownPeriod {
+			if clock.Since(hr.clock, *h.DegradedAt) > hr.cfg.CooldownPeriod {
 				return true // Allow retry after cooldown
 			}
 		}
@@ -338,7 +390,7 @@ func (hr *HealthRegistry) Persist() error {
 
 	snapshot := HealthSnapshot{
 		Models:      hr.models,
-		LastUpdated: time.Now(),
+		LastUpdated: hr.clock.Now(),
 		Version:     "1.0",
 	}
 
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/clawinfra/evoclaw/internal/clock"
 )
 
 func TestHealthRegistry_RecordSuccess(t *testing.T) {
@@ -101,17 +103,52 @@ func TestHealthRegistry_RecoveryAfterSuccess(t *testing.T) {
 	}
 }
 
+func TestHealthRegistry_OnStateChange(t *testing.T) {
+	cfg := DefaultHealthConfig()
+	cfg.PersistPath = filepath.Join(t.TempDir(), "health.json")
+	cfg.FailureThreshold = 2
+
+	hr, err := NewHealthRegistry(cfg, slog.Default())
+	if err != nil {
+		t.Fatalf("NewHealthRegistry: %v", err)
+	}
+	var changes []StateChange
+	hr.OnStateChange(func(c StateChange) {
+		// The registry is unlocked while the callback runs.
+		hr.IsHealthy(c.Model)
+		changes = append(changes, c)
+	})
+
+	hr.RecordFailure("model-a", ErrRateLimited)
+	hr.RecordFailure("model-a", ErrTimeout)
+	hr.RecordFailure("model-a", ErrTimeout) // already degraded
+	hr.RecordSuccess("model-a")
+	hr.RecordSuccess("model-a") // already healthy
+
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v, want degrade then recover", changes)
+	}
+	if c := changes[0]; c.To != StateDegraded || c.ConsecutiveFailures != 2 || c.ErrorType != ErrTimeout {
+		t.Errorf("degrade change = %+v", c)
+	}
+	if c := changes[1]; c.From != StateDegraded || c.To != StateHealthy {
+		t.Errorf("recover change = %+v", c)
+	}
+}
+
 func TestHealthRegistry_IsHealthy(t *testing.T) {
 	cfg := DefaultHealthConfig()
 	cfg.PersistPath = filepath.Join(t.TempDir(), "health.json")
 	cfg.FailureThreshold = 2
-	cfg.CooldownPeriod = 100 * time.Millisecond
+	cfg.CooldownPeriod = 5 * time.Minute
 	cfg.AutoRecover = true
 
 	hr, err := NewHealthRegistry(cfg, slog.Default())
 	if err != nil {
 		t.Fatalf("NewHealthRegistry: %v", err)
 	}
+	fake := clock.NewFake(time.Now())
+	hr.SetClock(fake)
 
 	// Unknown model is healthy
 	if !hr.IsHealthy("unknown-model") {
@@ -126,10 +163,14 @@ func TestHealthRegistry_IsHealthy(t *testing.T) {
 		t.Error("degraded model should not be healthy")
 	}
 
-	// Wait for cooldown
-	time.Sleep(150 * time.Millisecond)
+	// Still degraded just inside the cooldown
+	fake.Advance(cfg.CooldownPeriod - time.Second)
+	if hr.IsHealthy("model-a") {
+		t.Error("model should stay degraded until the cooldown ends")
+	}
 
 	// Should be healthy again (auto-recover)
+	fake.Advance(2 * time.Second)
 	if !hr.IsHealthy("model-a") {
 		t.Error("model should be healthy after cooldown")
 	}
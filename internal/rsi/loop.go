@@ -25,14 +25,17 @@ type Loop struct {
 }
 
 // NewLoop creates a new RSI Loop.
-// It automatically initialises a skillbank FileStore under cfg.DataDir/skillbank.jsonl
-// so that every recorded outcome is persisted as a raw trajectory for later LLM distillation.
+// It automatically initialises a skillbank store — backed by cfg.Storage when set,
+// otherwise a FileStore under cfg.DataDir/skillbank.jsonl — so that every recorded
+// outcome is persisted as a raw trajectory for later LLM distillation.
 func NewLoop(cfg Config, logger *slog.Logger) *Loop {
 	observer := NewObserver(cfg, logger)
 
 	// Attach skillbank store — best-effort; log and continue on failure.
 	sbPath := filepath.Join(cfg.DataDir, "skillbank.jsonl")
-	if store, err := skillbank.NewFileStore(sbPath); err != nil {
+	if cfg.Storage != nil {
+		observer.WithSkillStore(skillbank.NewBackendStore(cfg.Storage))
+	} else if store, err := skillbank.NewFileStore(sbPath); err != nil {
 		logger.Warn("skillbank: failed to init file store, trajectory recording disabled",
 			"path", sbPath, "error", err)
 	} else {
@@ -43,6 +43,11 @@ func (o *Observer) WithSkillStore(store skillbank.Store) *Observer {
 	return o
 }
 
+// SkillStore returns the attached skillbank store, or nil if none.
+func (o *Observer) SkillStore() skillbank.Store {
+	return o.skillStore
+}
+
 // RecordTrajectory stores a raw trajectory in the skillbank for later distillation.
 // It is a best-effort call: errors are logged but do not affect normal operation.
 func (o *Observer) RecordTrajectory(t skillbank.Trajectory) {
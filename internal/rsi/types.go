@@ -5,6 +5,8 @@ package rsi
 
 import (
 	"time"
+
+	"github.com/clawinfra/evoclaw/internal/storage"
 )
 
 // Source identifies where an outcome originated.
@@ -120,6 +122,9 @@ type Config struct {
 
 	// DataDir is the directory for storing outcomes and proposals.
 	DataDir string `json:"data_dir"`
+
+	// Storage, if set, persists the skillbank instead of DataDir/skillbank.jsonl.
+	Storage storage.Store `json:"-"`
 }
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser accepts standard 5-field expressions, 6-field expressions with
+// a leading seconds field, and descriptors such as @daily or @every 5m.
+var cronParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// parseCron parses a cron expression evaluated in the given timezone.
+//
+// The timezone may also be given as a trailing IANA name in the expression
+// itself, e.g. "0 9 * * MON-FRI America/New_York", or with robfig's
+// "CRON_TZ=" / "TZ=" prefix. An empty timezone means the local zone.
+func parseCron(expr, timezone string) (cron.Schedule, error) {
+	spec, exprTZ := splitCronTimezone(expr)
+	if exprTZ != "" {
+		if timezone != "" && timezone != exprTZ {
+			return nil, fmt.Errorf("cron expression timezone %q conflicts with timezone %q", exprTZ, timezone)
+		}
+		timezone = exprTZ
+	}
+	if spec == "" {
+		return nil, fmt.Errorf("cron expression required")
+	}
+
+	if timezone != "" {
+		if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+			return nil, fmt.Errorf("cron expression has both a TZ prefix and timezone %q", timezone)
+		}
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+		spec = "CRON_TZ=" + timezone + " " + spec
+	}
+
+	schedule, err := cronParser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s, ok := schedule.(*cron.SpecSchedule); ok {
+		return fallBackSchedule{s}, nil
+	}
+	return schedule, nil
+}
+
+// allHours has a bit set for every hour of the day.
+const allHours = 1<<24 - 1
+
+// fallBackSchedule fires fixed-time jobs once when a DST fall-back repeats
+// their wall-clock time, matching traditional cron. Jobs that run every hour
+// keep firing through the repeated hour.
+type fallBackSchedule struct {
+	*cron.SpecSchedule
+}
+
+// Next returns the next activation time after t.
+func (s fallBackSchedule) Next(t time.Time) time.Time {
+	next := s.SpecSchedule.Next(t)
+	if s.Hour&allHours == allHours {
+		return next
+	}
+	loc := s.Location
+	for !next.IsZero() && !wallClock(next.In(loc)).After(wallClock(t.In(loc))) {
+		next = s.SpecSchedule.Next(next)
+	}
+	return next
+}
+
+// wallClock returns t's local date and time reinterpreted as UTC, so two
+// instants can be compared by the clock reading rather than absolute time.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// splitCronTimezone separates a trailing IANA timezone name from a cron
+// expression. Cron fields never contain '/' followed by letters or equal
+// "UTC", so any final field that does is treated as a timezone.
+func splitCronTimezone(expr string) (spec, timezone string) {
+	fields := strings.Fields(expr)
+	if len(fields) < 2 {
+		return strings.Join(fields, " "), ""
+	}
+	last := fields[len(fields)-1]
+	if isTimezoneName(last) {
+		return strings.Join(fields[:len(fields)-1], " "), last
+	}
+	return strings.Join(fields, " "), ""
+}
+
+// isTimezoneName reports whether a cron field looks like a timezone name
+// rather than a schedule field.
+func isTimezoneName(field string) bool {
+	if field == "UTC" || field == "Local" {
+		return true
+	}
+	slash := strings.Index(field, "/")
+	if slash <= 0 || slash == len(field)-1 {
+		return false
+	}
+	// Step values ("*/5", "1-10/2") have digits after the slash
+	c := field[slash+1]
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
@@ -0,0 +1,197 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	return loc
+}
+
+func cronJob(expr, tz string) *Job {
+	return &Job{
+		ID:       "cron",
+		Name:     "Cron",
+		Schedule: ScheduleConfig{Kind: "cron", Expr: expr, Timezone: tz},
+		Action:   ActionConfig{Kind: "shell", Command: "true"},
+	}
+}
+
+func TestNextRun_CronWeekdaysWithInlineTimezone(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	job := cronJob("0 9 * * MON-FRI America/New_York", "")
+	if err := job.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	// Friday 2024-03-08 10:00 NY -> next is Monday 09:00 NY
+	from := time.Date(2024, 3, 8, 10, 0, 0, 0, ny)
+	next, err := job.NextRun(from)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	want := time.Date(2024, 3, 11, 9, 0, 0, 0, ny)
+	if !next.Equal(want) {
+		t.Errorf("next = %v, want %v", next, want)
+	}
+}
+
+func TestNextRun_CronAcrossSpringForward(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	job := cronJob("0 9 * * *", "America/New_York")
+
+	// DST starts 2024-03-10 02:00 in New York; 09:00 local must still fire
+	// at 09:00 wall-clock time, which is 13:00 UTC instead of 14:00 UTC.
+	from := time.Date(2024, 3, 9, 12, 0, 0, 0, ny)
+	next, err := job.NextRun(from)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	if want := time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("next = %v, want %v", next.UTC(), want)
+	}
+}
+
+func TestNextRun_CronSkippedHourDuringSpringForward(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	job := cronJob("30 2 * * *", "America/New_York")
+
+	// 02:30 does not exist on 2024-03-10; the job must not fire twice or at a
+	// bogus time, and must resume at 02:30 the following day.
+	from := time.Date(2024, 3, 9, 3, 0, 0, 0, ny)
+	next, err := job.NextRun(from)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	if next.In(ny).Day() == 10 && next.In(ny).Hour() == 2 {
+		t.Fatalf("fired at nonexistent local time %v", next)
+	}
+
+	after, err := job.NextRun(next)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	if want := time.Date(2024, 3, 11, 2, 30, 0, 0, ny); !after.Equal(want) && !next.Equal(want) {
+		t.Errorf("expected a run at %v, got %v then %v", want, next, after)
+	}
+}
+
+func TestNextRun_CronAcrossFallBack(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	job := cronJob("30 1 * * *", "America/New_York")
+
+	// 01:30 occurs twice on 2024-11-03; the job must fire only once that day.
+	from := time.Date(2024, 11, 3, 0, 0, 0, 0, ny)
+	first, err := job.NextRun(from)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	second, err := job.NextRun(first)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	if first.In(ny).Day() != 3 || second.In(ny).Day() != 4 {
+		t.Errorf("expected runs on Nov 3 and Nov 4, got %v and %v", first.In(ny), second.In(ny))
+	}
+}
+
+func TestNextRun_CronWithSeconds(t *testing.T) {
+	job := cronJob("*/15 * * * * *", "UTC")
+	if err := job.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 7, 0, time.UTC)
+	next, err := job.NextRun(from)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	if want := time.Date(2024, 1, 1, 0, 0, 15, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("next = %v, want %v", next, want)
+	}
+}
+
+func TestNextRun_AtAcrossSpringForward(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	job := &Job{
+		ID:       "at",
+		Name:     "At",
+		Schedule: ScheduleConfig{Kind: "at", Time: "09:00", Timezone: "America/New_York"},
+		Action:   ActionConfig{Kind: "shell", Command: "true"},
+	}
+
+	from := time.Date(2024, 3, 9, 10, 0, 0, 0, ny)
+	next, err := job.NextRun(from)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	if want := time.Date(2024, 3, 10, 9, 0, 0, 0, ny); !next.Equal(want) {
+		t.Errorf("next = %v, want %v", next, want)
+	}
+}
+
+func TestValidate_InvalidCron(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		tz   string
+		want string
+	}{
+		{"garbage", "not a cron", "", "invalid cron expression"},
+		{"out of range", "61 * * * *", "", "invalid cron expression"},
+		{"too many fields", "0 0 0 0 * * * *", "", "invalid cron expression"},
+		{"unknown timezone", "0 9 * * *", "Mars/Olympus_Mons", "invalid timezone"},
+		{"conflicting timezone", "0 9 * * * Europe/London", "America/New_York", "conflicts"},
+		{"empty", "", "", "cron expression required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cronJob(tt.expr, tt.tz).Validate()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadJobs_InvalidCronDisablesJob(t *testing.T) {
+	sched := NewScheduler(&MockExecutor{}, nil)
+	job := cronJob("99 * * * *", "")
+	job.Enabled = true
+
+	if err := sched.LoadJobs([]*Job{job}); err != nil {
+		t.Fatalf("LoadJobs: %v", err)
+	}
+
+	got, err := sched.GetJob("cron")
+	if err != nil {
+		t.Fatalf("expected invalid job to be kept: %v", err)
+	}
+	if got.Enabled {
+		t.Error("expected invalid job to be disabled")
+	}
+	if !strings.Contains(got.State.LastError, "invalid cron expression") {
+		t.Errorf("expected LastError to explain the failure, got %q", got.State.LastError)
+	}
+}
+
+func TestNextRun_HourlyCronFiresThroughRepeatedHour(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	job := cronJob("30 * * * *", "America/New_York")
+
+	// Hourly jobs keep firing through both 01:30s on the fall-back day.
+	first, _ := job.NextRun(time.Date(2024, 11, 3, 1, 0, 0, 0, ny))
+	second, _ := job.NextRun(first)
+	if second.Sub(first) != time.Hour {
+		t.Errorf("expected runs an hour apart, got %v and %v", first, second)
+	}
+}
@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func agentJob(id string, intervalMs int64) *Job {
+	return &Job{
+		ID:       id,
+		Name:     id,
+		Enabled:  true,
+		Schedule: ScheduleConfig{Kind: "interval", IntervalMs: intervalMs},
+		Action:   ActionConfig{Kind: "agent", AgentID: "a1", Message: "tick"},
+	}
+}
+
+// waitFor polls cond until it holds or the timeout elapses.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestScheduler_AddDisableEnableAtRuntime(t *testing.T) {
+	exec := &MockExecutor{}
+	sched := NewScheduler(exec, nil)
+	if err := sched.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer sched.Stop()
+
+	if err := sched.AddJob(agentJob("tick", 20)); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+	if !waitFor(t, 2*time.Second, func() bool { return len(exec.GetAgentCalls()) >= 2 }) {
+		t.Fatal("added job never fired")
+	}
+
+	if err := sched.DisableJob("tick"); err != nil {
+		t.Fatalf("DisableJob: %v", err)
+	}
+	stopped := len(exec.GetAgentCalls())
+	time.Sleep(100 * time.Millisecond)
+	if got := len(exec.GetAgentCalls()); got != stopped {
+		t.Fatalf("disabled job kept firing: %d -> %d calls", stopped, got)
+	}
+	if job, _ := sched.GetJob("tick"); job.Enabled {
+		t.Error("expected job to report disabled")
+	}
+	if st := sched.GetStats(); st["running_jobs"] != 0 {
+		t.Errorf("expected no running jobs, got %v", st["running_jobs"])
+	}
+
+	if err := sched.EnableJob("tick"); err != nil {
+		t.Fatalf("EnableJob: %v", err)
+	}
+	if !waitFor(t, 2*time.Second, func() bool { return len(exec.GetAgentCalls()) > stopped }) {
+		t.Fatal("re-enabled job never fired")
+	}
+}
+
+func TestScheduler_RemoveJobStopsFiring(t *testing.T) {
+	exec := &MockExecutor{}
+	sched := NewScheduler(exec, nil)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	_ = sched.AddJob(agentJob("tick", 20))
+	if !waitFor(t, 2*time.Second, func() bool { return len(exec.GetAgentCalls()) >= 1 }) {
+		t.Fatal("job never fired")
+	}
+	if err := sched.RemoveJob("tick"); err != nil {
+		t.Fatalf("RemoveJob: %v", err)
+	}
+	removed := len(exec.GetAgentCalls())
+	time.Sleep(100 * time.Millisecond)
+	if got := len(exec.GetAgentCalls()); got != removed {
+		t.Errorf("removed job kept firing: %d -> %d calls", removed, got)
+	}
+}
+
+func TestScheduler_RemoveJobCancelsInFlightAction(t *testing.T) {
+	sched := NewScheduler(&MockExecutor{}, nil)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	job := shellJob("slow", "sleep 30")
+	job.Schedule.IntervalMs = 10
+	_ = sched.AddJob(job)
+	time.Sleep(50 * time.Millisecond) // let the command start
+
+	done := make(chan struct{})
+	go func() {
+		_ = sched.RemoveJob("slow")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RemoveJob blocked on an in-flight action")
+	}
+}
+
+func TestScheduler_AddJobRejectsInvalidSchedule(t *testing.T) {
+	sched := NewScheduler(&MockExecutor{}, nil)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	job := agentJob("bad", 0)
+	job.Schedule = ScheduleConfig{Kind: "cron", Expr: "0 0 30 2 *"} // Feb 30th never occurs
+	if err := sched.AddJob(job); err == nil {
+		t.Fatal("expected schedule that never fires to be rejected")
+	}
+	if _, err := sched.GetJob("bad"); err == nil {
+		t.Error("rejected job should not be registered")
+	}
+}
+
+func TestScheduler_EnableDisableUnknownJob(t *testing.T) {
+	sched := NewScheduler(&MockExecutor{}, nil)
+	if err := sched.EnableJob("missing"); err == nil {
+		t.Error("expected error enabling unknown job")
+	}
+	if err := sched.DisableJob("missing"); err == nil {
+		t.Error("expected error disabling unknown job")
+	}
+}
+
+func TestScheduler_EnableKeepsInvalidJobDisabled(t *testing.T) {
+	sched := NewScheduler(&MockExecutor{}, nil)
+	job := agentJob("broken", 0)
+	_ = sched.LoadJobs([]*Job{job})
+
+	if err := sched.EnableJob("broken"); err == nil {
+		t.Error("expected invalid job to stay disabled")
+	}
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultRunHistorySize is the number of runs kept per job.
+const DefaultRunHistorySize = 20
+
+// JobRun records the outcome of a single job execution.
+type JobRun struct {
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+	Success   bool          `json:"success"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// JobStatus summarises a job's recent executions.
+type JobStatus struct {
+	JobID      string    `json:"jobId"`
+	Enabled    bool      `json:"enabled"`
+	RunCount   int64     `json:"runCount"`
+	ErrorCount int64     `json:"errorCount"`
+	NextRunAt  time.Time `json:"nextRunAt,omitempty"`
+	LastRun    *JobRun   `json:"lastRun,omitempty"`
+	// Runs holds the most recent executions, oldest first.
+	Runs []JobRun `json:"runs"`
+}
+
+// runHistory is a bounded, concurrency-safe record of a job's executions.
+// It is shared between the scheduler and the job's runners so status can be
+// read without touching the Job while a runner is executing it.
+type runHistory struct {
+	mu         sync.Mutex
+	runs       []JobRun
+	max        int
+	runCount   int64
+	errorCount int64
+	nextRunAt  time.Time
+}
+
+func newRunHistory(max int) *runHistory {
+	if max <= 0 {
+		max = DefaultRunHistorySize
+	}
+	return &runHistory{max: max}
+}
+
+// record appends a run, dropping the oldest once the history is full.
+func (h *runHistory) record(run JobRun) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.runs = append(h.runs, run)
+	if len(h.runs) > h.max {
+		h.runs = h.runs[len(h.runs)-h.max:]
+	}
+	h.runCount++
+	if !run.Success {
+		h.errorCount++
+	}
+}
+
+// setNextRun records when the job is next due.
+func (h *runHistory) setNextRun(t time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nextRunAt = t
+}
+
+// status returns a snapshot of the history for the given job.
+func (h *runHistory) status(jobID string, enabled bool) JobStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	st := JobStatus{
+		JobID:      jobID,
+		Enabled:    enabled,
+		RunCount:   h.runCount,
+		ErrorCount: h.errorCount,
+		NextRunAt:  h.nextRunAt,
+		Runs:       make([]JobRun, len(h.runs)),
+	}
+	copy(st.Runs, h.runs)
+	if n := len(st.Runs); n > 0 {
+		last := st.Runs[n-1]
+		st.LastRun = &last
+	}
+	return st
+}
@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func shellJob(id, command string) *Job {
+	return &Job{
+		ID:       id,
+		Name:     id,
+		Enabled:  true,
+		Schedule: ScheduleConfig{Kind: "interval", IntervalMs: 60000},
+		Action:   ActionConfig{Kind: "shell", Command: command},
+	}
+}
+
+func TestJobStatus_RecordsSuccessAndFailure(t *testing.T) {
+	sched := NewScheduler(&MockExecutor{}, nil)
+	if err := sched.AddJob(shellJob("ok", "true")); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.AddJob(shellJob("bad", "exit 3")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"ok", "bad", "bad"} {
+		if err := sched.RunJobNow(id); err != nil {
+			t.Fatalf("RunJobNow(%s): %v", id, err)
+		}
+	}
+
+	ok, err := sched.JobStatus("ok")
+	if err != nil {
+		t.Fatalf("JobStatus: %v", err)
+	}
+	if ok.RunCount != 1 || ok.ErrorCount != 0 {
+		t.Errorf("ok: runs=%d errors=%d, want 1/0", ok.RunCount, ok.ErrorCount)
+	}
+	if ok.LastRun == nil || !ok.LastRun.Success || ok.LastRun.Error != "" {
+		t.Errorf("ok: unexpected last run %+v", ok.LastRun)
+	}
+
+	bad, _ := sched.JobStatus("bad")
+	if bad.RunCount != 2 || bad.ErrorCount != 2 || len(bad.Runs) != 2 {
+		t.Errorf("bad: runs=%d errors=%d history=%d, want 2/2/2", bad.RunCount, bad.ErrorCount, len(bad.Runs))
+	}
+	if bad.LastRun == nil || bad.LastRun.Success || bad.LastRun.Error == "" {
+		t.Errorf("bad: expected failed last run with error, got %+v", bad.LastRun)
+	}
+	if bad.LastRun.StartedAt.IsZero() {
+		t.Error("bad: expected start time to be recorded")
+	}
+}
+
+func TestJobStatus_NeverRun(t *testing.T) {
+	sched := NewScheduler(&MockExecutor{}, nil)
+	_ = sched.AddJob(shellJob("idle", "true"))
+
+	st, err := sched.JobStatus("idle")
+	if err != nil {
+		t.Fatalf("JobStatus: %v", err)
+	}
+	if st.LastRun != nil || len(st.Runs) != 0 || st.RunCount != 0 {
+		t.Errorf("expected empty status, got %+v", st)
+	}
+}
+
+func TestJobStatus_UnknownJob(t *testing.T) {
+	sched := NewScheduler(&MockExecutor{}, nil)
+	if _, err := sched.JobStatus("missing"); err == nil {
+		t.Error("expected error for unknown job")
+	}
+}
+
+func TestJobStatus_RemovedJobForgetsHistory(t *testing.T) {
+	sched := NewScheduler(&MockExecutor{}, nil)
+	_ = sched.AddJob(shellJob("gone", "true"))
+	_ = sched.RunJobNow("gone")
+	_ = sched.RemoveJob("gone")
+	_ = sched.AddJob(shellJob("gone", "true"))
+
+	st, _ := sched.JobStatus("gone")
+	if st.RunCount != 0 {
+		t.Errorf("expected fresh history after re-adding, got %d runs", st.RunCount)
+	}
+}
+
+func TestRunHistory_Bounded(t *testing.T) {
+	h := newRunHistory(3)
+	for i := 0; i < 5; i++ {
+		h.record(JobRun{StartedAt: time.Unix(int64(i), 0), Success: i%2 == 0})
+	}
+
+	st := h.status("j", true)
+	if len(st.Runs) != 3 {
+		t.Fatalf("expected 3 runs kept, got %d", len(st.Runs))
+	}
+	if st.Runs[0].StartedAt.Unix() != 2 || st.LastRun.StartedAt.Unix() != 4 {
+		t.Errorf("expected runs 2..4, got %v..%v", st.Runs[0].StartedAt.Unix(), st.LastRun.StartedAt.Unix())
+	}
+	if st.RunCount != 5 || st.ErrorCount != 2 {
+		t.Errorf("expected totals 5/2, got %d/%d", st.RunCount, st.ErrorCount)
+	}
+}
+
+func TestRunHistory_ConcurrentAccess(t *testing.T) {
+	h := newRunHistory(DefaultRunHistorySize)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				h.record(JobRun{Success: true})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_ = h.status("j", true)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if st := h.status("j", true); st.RunCount != 400 || len(st.Runs) != DefaultRunHistorySize {
+		t.Errorf("unexpected totals: runs=%d kept=%d", st.RunCount, len(st.Runs))
+	}
+}
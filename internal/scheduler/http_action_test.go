@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newHTTPJob(url string) *Job {
+	return &Job{
+		ID:       "http-job",
+		Name:     "HTTP Job",
+		Enabled:  true,
+		Schedule: ScheduleConfig{Kind: "interval", IntervalMs: 1000},
+		Action: ActionConfig{
+			Kind:    "http",
+			Method:  http.MethodPost,
+			URL:     url,
+			Headers: map[string]string{"X-Api-Key": "secret"},
+			Payload: map[string]any{"ping": true},
+		},
+	}
+}
+
+func TestJobRunnerHTTP_SendsRequest(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		method  string
+		apiKey  string
+		ctype   string
+		payload string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		method, apiKey, ctype, payload = r.Method, r.Header.Get("X-Api-Key"), r.Header.Get("Content-Type"), string(body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	job := newHTTPJob(srv.URL)
+	if err := job.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	NewJobRunner(job, nil, nil).executeJob(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if method != http.MethodPost {
+		t.Errorf("method = %q, want POST", method)
+	}
+	if apiKey != "secret" {
+		t.Errorf("X-Api-Key = %q, want secret", apiKey)
+	}
+	if ctype != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ctype)
+	}
+	if payload != `{"ping":true}` {
+		t.Errorf("payload = %q", payload)
+	}
+	if job.State.ErrorCount != 0 || job.State.LastError != "" {
+		t.Errorf("expected success, got error %q", job.State.LastError)
+	}
+}
+
+func TestJobRunnerHTTP_Non2xxIsFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	job := newHTTPJob(srv.URL)
+	NewJobRunner(job, nil, nil).executeJob(context.Background())
+
+	if job.State.ErrorCount != 1 {
+		t.Fatalf("ErrorCount = %d, want 1", job.State.ErrorCount)
+	}
+	if !strings.Contains(job.State.LastError, "500") {
+		t.Errorf("LastError = %q, want status 500", job.State.LastError)
+	}
+}
+
+func TestJobRunnerHTTP_ExpectStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// 200 is not in the expected set, so the run fails
+	job := newHTTPJob(srv.URL)
+	job.Action.ExpectStatus = []int{http.StatusNoContent}
+	NewJobRunner(job, nil, nil).executeJob(context.Background())
+	if job.State.ErrorCount != 1 {
+		t.Errorf("expected failure when status not in expectStatus, ErrorCount = %d", job.State.ErrorCount)
+	}
+
+	job = newHTTPJob(srv.URL)
+	job.Action.ExpectStatus = []int{http.StatusNoContent, http.StatusOK}
+	NewJobRunner(job, nil, nil).executeJob(context.Background())
+	if job.State.ErrorCount != 0 {
+		t.Errorf("expected success, got %q", job.State.LastError)
+	}
+}
+
+func TestJobRunnerHTTP_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	job := newHTTPJob(srv.URL)
+	job.Action.TimeoutMs = 50
+
+	start := time.Now()
+	NewJobRunner(job, nil, nil).executeJob(context.Background())
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("request took %v, expected timeout near 50ms", elapsed)
+	}
+	if job.State.ErrorCount != 1 {
+		t.Errorf("expected timeout to count as failure")
+	}
+}
+
+func TestJobValidate_HTTPAction(t *testing.T) {
+	job := newHTTPJob("http://example.com")
+	job.Action.ExpectStatus = []int{42}
+	if err := job.Validate(); err == nil {
+		t.Error("expected invalid status code to fail validation")
+	}
+
+	job = newHTTPJob("http://example.com")
+	job.Action.TimeoutMs = -1
+	if err := job.Validate(); err == nil {
+		t.Error("expected negative timeout to fail validation")
+	}
+}
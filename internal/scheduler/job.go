@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
-
-	"github.com/robfig/cron/v3"
 )
 
 // Job represents a scheduled task
@@ -39,6 +37,10 @@ type ActionConfig struct {
 	URL     string         `json:"url,omitempty"`
 	Method  string         `json:"method,omitempty"` // "GET", "POST", etc.
 	Headers map[string]string `json:"headers,omitempty"`
+	// HTTP only: request timeout (default 30s) and the status codes that
+	// count as success (default any 2xx)
+	TimeoutMs    int64 `json:"timeoutMs,omitempty"`
+	ExpectStatus []int `json:"expectStatus,omitempty"`
 }
 
 // JobState tracks job execution state
@@ -70,9 +72,8 @@ func (j *Job) Validate() error {
 		if j.Schedule.Expr == "" {
 			return fmt.Errorf("cron expression required")
 		}
-		// Validate cron expression
-		if _, err := cron.ParseStandard(j.Schedule.Expr); err != nil {
-			return fmt.Errorf("invalid cron expression: %w", err)
+		if _, err := parseCron(j.Schedule.Expr, j.Schedule.Timezone); err != nil {
+			return err
 		}
 	case "at":
 		if j.Schedule.Time == "" {
@@ -82,6 +83,11 @@ func (j *Job) Validate() error {
 		if _, err := time.Parse("15:04", j.Schedule.Time); err != nil {
 			return fmt.Errorf("invalid time format (use HH:MM): %w", err)
 		}
+		if j.Schedule.Timezone != "" {
+			if _, err := time.LoadLocation(j.Schedule.Timezone); err != nil {
+				return fmt.Errorf("invalid timezone %q: %w", j.Schedule.Timezone, err)
+			}
+		}
 	default:
 		return fmt.Errorf("unknown schedule kind: %s (use interval, cron, or at)", j.Schedule.Kind)
 	}
@@ -110,6 +116,14 @@ func (j *Job) Validate() error {
 		if j.Action.Method == "" {
 			j.Action.Method = "GET"
 		}
+		if j.Action.TimeoutMs < 0 {
+			return fmt.Errorf("timeoutMs must not be negative")
+		}
+		for _, code := range j.Action.ExpectStatus {
+			if code < 100 || code > 599 {
+				return fmt.Errorf("invalid expectStatus code: %d", code)
+			}
+		}
 	default:
 		return fmt.Errorf("unknown action kind: %s (use shell, agent, mqtt, or http)", j.Action.Kind)
 	}
@@ -125,11 +139,15 @@ func (j *Job) NextRun(from time.Time) (time.Time, error) {
 		return from.Add(interval), nil
 
 	case "cron":
-		schedule, err := cron.ParseStandard(j.Schedule.Expr)
+		schedule, err := parseCron(j.Schedule.Expr, j.Schedule.Timezone)
 		if err != nil {
 			return time.Time{}, fmt.Errorf("parse cron: %w", err)
 		}
-		return schedule.Next(from), nil
+		next := schedule.Next(from)
+		if next.IsZero() {
+			return time.Time{}, fmt.Errorf("cron expression %q never fires", j.Schedule.Expr)
+		}
+		return next, nil
 
 	case "at":
 		t, err := time.Parse("15:04", j.Schedule.Time)
@@ -146,13 +164,16 @@ func (j *Job) NextRun(from time.Time) (time.Time, error) {
 			}
 		}
 
-		// Build next occurrence
-		next := time.Date(from.Year(), from.Month(), from.Day(),
+		// Build next occurrence on the current day in the job's timezone
+		local := from.In(loc)
+		next := time.Date(local.Year(), local.Month(), local.Day(),
 			t.Hour(), t.Minute(), 0, 0, loc)
 
-		// If time has passed today, schedule for tomorrow
-		if next.Before(from) || next.Equal(from) {
-			next = next.Add(24 * time.Hour)
+		// If time has passed today, schedule for tomorrow. Rebuilding the date keeps the
+		// wall-clock time across DST changes, unlike adding 24h.
+		if !next.After(from) {
+			next = time.Date(local.Year(), local.Month(), local.Day()+1,
+				t.Hour(), t.Minute(), 0, 0, loc)
 		}
 
 		return next, nil
@@ -8,17 +8,29 @@ import (
 	"log/slog"
 	"net/http"
 	"os/exec"
+	"sync"
 	"time"
+
+	"github.com/clawinfra/evoclaw/internal/clock"
 )
 
+// defaultHTTPTimeout bounds HTTP actions that don't set TimeoutMs.
+const defaultHTTPTimeout = 30 * time.Second
+
+// shellWaitDelay bounds how long a cancelled shell action may take to exit.
+const shellWaitDelay = time.Second
+
 // JobRunner executes a single job on schedule
 type JobRunner struct {
 	job       *Job
 	ticker    *time.Ticker
 	logger    *slog.Logger
 	executor  Executor
+	history   *runHistory // optional; set by the scheduler
+	stateMu   *sync.Mutex // optional; guards job.State when shared
 	stopCh    chan struct{}
 	doneCh    chan struct{}
+	clock     clock.Clock
 }
 
 // Executor defines interfaces for executing actions
@@ -38,10 +50,12 @@ func NewJobRunner(job *Job, executor Executor, log *slog.Logger) *JobRunner {
 		logger:   log.With("job", job.ID),
 		stopCh:   make(chan struct{}),
 		doneCh:   make(chan struct{}),
+		clock:    clock.System,
 	}
 }
 
-// Start begins executing the job on schedule
+// Start begins executing the job on schedule. Stop cancels the context
+// passed to in-flight actions, so a hung command cannot block removal.
 func (r *JobRunner) Start(ctx context.Context) {
 	defer close(r.doneCh)
 
@@ -50,72 +64,112 @@ func (r *JobRunner) Start(ctx context.Context) {
 		return
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	// Calculate initial next run
-	nextRun, err := r.job.NextRun(time.Now())
-	if err != nil {
-		r.logger.Error("failed to calculate next run", "error", err)
+	nextRun, ok := r.scheduleNext()
+	if !ok {
 		return
 	}
-	r.job.State.NextRunAt = nextRun
 
 	r.logger.Info("job runner started", "next_run", nextRun.Format(time.RFC3339))
 
-	// Set up ticker based on schedule type
-	var tickerDuration time.Duration
-	switch r.job.Schedule.Kind {
-	case "interval":
-		tickerDuration = time.Duration(r.job.Schedule.IntervalMs) * time.Millisecond
-	case "cron", "at":
-		// Check every minute for cron/at schedules
-		tickerDuration = 1 * time.Minute
+	if r.job.Schedule.Kind == "interval" {
+		r.runInterval(ctx)
+		return
 	}
+	r.runAtNextRun(ctx, nextRun)
+}
 
-	r.ticker = time.NewTicker(tickerDuration)
+// runInterval executes the job on a fixed ticker.
+func (r *JobRunner) runInterval(ctx context.Context) {
+	r.ticker = time.NewTicker(time.Duration(r.job.Schedule.IntervalMs) * time.Millisecond)
 	defer r.ticker.Stop()
 
 	for {
 		select {
+		case <-r.stopCh:
+			r.logger.Info("job runner stopped")
+			return
 		case <-ctx.Done():
 			r.logger.Info("job runner stopped (context cancelled)")
 			return
+		case <-r.ticker.C:
+			r.executeJob(ctx)
+			r.scheduleNext()
+		}
+	}
+}
+
+// runAtNextRun sleeps until each computed fire time, so cron schedules with
+// a seconds field and "at" schedules fire on time rather than on a polling tick.
+func (r *JobRunner) runAtNextRun(ctx context.Context, nextRun time.Time) {
+	timer := time.NewTimer(nextRun.Sub(r.clock.Now()))
+	defer timer.Stop()
+
+	for {
+		select {
 		case <-r.stopCh:
 			r.logger.Info("job runner stopped")
 			return
-		case now := <-r.ticker.C:
-			// For interval schedules, always run
-			// For cron/at schedules, check if it's time
-			shouldRun := false
-			if r.job.Schedule.Kind == "interval" {
-				shouldRun = true
-			} else {
-				shouldRun = now.After(r.job.State.NextRunAt) || now.Equal(r.job.State.NextRunAt)
-			}
-
-			if shouldRun {
-				r.executeJob(ctx)
-
-				// Calculate next run
-				nextRun, err := r.job.NextRun(time.Now())
-				if err != nil {
-					r.logger.Error("failed to calculate next run", "error", err)
-				} else {
-					r.job.State.NextRunAt = nextRun
-					r.logger.Debug("next run scheduled", "next_run", nextRun.Format(time.RFC3339))
-				}
+		case <-ctx.Done():
+			r.logger.Info("job runner stopped (context cancelled)")
+			return
+		case <-timer.C:
+			r.executeJob(ctx)
+			next, ok := r.scheduleNext()
+			if !ok {
+				return
 			}
+			timer.Reset(next.Sub(r.clock.Now()))
 		}
 	}
 }
 
-// Stop stops the job runner
+// scheduleNext computes and records the job's next fire time. It returns
+// false if no further run can be scheduled.
+func (r *JobRunner) scheduleNext() (time.Time, bool) {
+	nextRun, err := r.job.NextRun(r.clock.Now())
+	if err != nil {
+		r.withState(func(st *JobState) { st.LastError = err.Error() })
+		r.logger.Error("failed to calculate next run", "error", err)
+		return time.Time{}, false
+	}
+	r.withState(func(st *JobState) { st.NextRunAt = nextRun })
+	if r.history != nil {
+		r.history.setNextRun(nextRun)
+	}
+	r.logger.Debug("next run scheduled", "next_run", nextRun.Format(time.RFC3339))
+	return nextRun, true
+}
+
+// Stop stops the job runner, cancelling any in-flight action, and waits for
+// it to exit.
 func (r *JobRunner) Stop() {
 	close(r.stopCh)
 	<-r.doneCh
 }
 
+// withState runs fn with exclusive access to the job's runtime state.
+func (r *JobRunner) withState(fn func(st *JobState)) {
+	if r.stateMu != nil {
+		r.stateMu.Lock()
+		defer r.stateMu.Unlock()
+	}
+	fn(&r.job.State)
+}
+
 // executeJob runs the job once
 func (r *JobRunner) executeJob(ctx context.Context) {
-	start := time.Now()
+	start := r.clock.Now()
 	r.logger.Info("executing job")
 
 	var err error
@@ -132,32 +186,49 @@ func (r *JobRunner) executeJob(ctx context.Context) {
 		err = fmt.Errorf("unknown action kind: %s", r.job.Action.Kind)
 	}
 
-	duration := time.Since(start)
+	duration := clock.Since(r.clock, start)
+
+	if r.history != nil {
+		run := JobRun{StartedAt: start, Duration: duration, Success: err == nil}
+		if err != nil {
+			run.Error = err.Error()
+		}
+		r.history.record(run)
+	}
 
 	// Update state
-	r.job.State.LastRunAt = time.Now()
-	r.job.State.LastDuration = duration
-	r.job.State.RunCount++
+	var state JobState
+	r.withState(func(st *JobState) {
+		st.LastRunAt = r.clock.Now()
+		st.LastDuration = duration
+		st.RunCount++
+		if err != nil {
+			st.ErrorCount++
+			st.LastError = err.Error()
+		} else {
+			st.LastError = ""
+		}
+		state = *st
+	})
 
 	if err != nil {
-		r.job.State.ErrorCount++
-		r.job.State.LastError = err.Error()
 		r.logger.Error("job failed",
 			"error", err,
 			"duration", duration,
-			"run_count", r.job.State.RunCount,
-			"error_count", r.job.State.ErrorCount)
+			"run_count", state.RunCount,
+			"error_count", state.ErrorCount)
 	} else {
-		r.job.State.LastError = ""
 		r.logger.Info("job completed",
 			"duration", duration,
-			"run_count", r.job.State.RunCount)
+			"run_count", state.RunCount)
 	}
 }
 
 // executeShell runs a shell command
 func (r *JobRunner) executeShell(ctx context.Context) error {
 	cmd := exec.CommandContext(ctx, "sh", "-c", r.job.Action.Command)
+	// Don't wait on output pipes held open by orphaned children after cancel
+	cmd.WaitDelay = shellWaitDelay
 
 	// Add args if provided
 	if len(r.job.Action.Args) > 0 {
@@ -217,17 +288,39 @@ func (r *JobRunner) executeHTTP(ctx context.Context) error {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	timeout := defaultHTTPTimeout
+	if r.job.Action.TimeoutMs > 0 {
+		timeout = time.Duration(r.job.Action.TimeoutMs) * time.Millisecond
+	}
+
+	client := &http.Client{Timeout: timeout}
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("http request failed: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode >= 400 {
+	r.logger.Info("http request completed",
+		"method", req.Method,
+		"url", r.job.Action.URL,
+		"status", resp.StatusCode)
+
+	if !r.statusOK(resp.StatusCode) {
 		return fmt.Errorf("http request failed with status: %d", resp.StatusCode)
 	}
-
-	r.logger.Debug("http request completed", "status", resp.StatusCode)
 	return nil
 }
+
+// statusOK reports whether an HTTP status satisfies the job's expectations:
+// one of ExpectStatus if set, otherwise any 2xx.
+func (r *JobRunner) statusOK(status int) bool {
+	if len(r.job.Action.ExpectStatus) == 0 {
+		return status >= 200 && status < 300
+	}
+	for _, code := range r.job.Action.ExpectStatus {
+		if status == code {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/clawinfra/evoclaw/internal/clock"
 )
 
 func TestJobRunnerShellExecution(t *testing.T) {
@@ -232,6 +234,44 @@ func TestJobRunnerStateTiming(t *testing.T) {
 	}
 }
 
+func TestJobRunnerUsesSchedulerClock(t *testing.T) {
+	job := &Job{
+		ID:      "clocked-job",
+		Name:    "Clocked Job",
+		Enabled: true,
+		Schedule: ScheduleConfig{
+			Kind: "cron",
+			Expr: "0 9 * * *",
+		},
+		Action: ActionConfig{
+			Kind:    "shell",
+			Command: "true",
+		},
+	}
+
+	s := NewScheduler(nil, nil)
+	fake := clock.NewFake(time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC))
+	s.SetClock(fake)
+	s.mu.Lock()
+	runner := s.newRunnerLocked(job)
+	s.mu.Unlock()
+
+	next, ok := runner.scheduleNext()
+	if want := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC); !ok || !next.Equal(want) {
+		t.Fatalf("next run = %v, %v; want %v", next, ok, want)
+	}
+
+	// Run it as if the clock reached the fire time, then schedule again
+	fake.Set(next)
+	runner.executeJob(context.Background())
+	if !job.State.LastRunAt.Equal(next) {
+		t.Errorf("LastRunAt = %v, want %v", job.State.LastRunAt, next)
+	}
+	if next, _ = runner.scheduleNext(); !next.Equal(time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("following run = %v, want the next day at 09:00", next)
+	}
+}
+
 func TestJobRunnerDisabledJob(t *testing.T) {
 	job := &Job{
 		ID:      "disabled-job",
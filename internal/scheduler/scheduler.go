@@ -5,17 +5,25 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
+
+	"github.com/clawinfra/evoclaw/internal/clock"
 )
 
 // Scheduler manages all scheduled jobs
 type Scheduler struct {
 	jobs     map[string]*Job
 	runners  map[string]*JobRunner
+	history  map[string]*runHistory
 	executor Executor
 	logger   *slog.Logger
 	mu       sync.RWMutex
-	ctx      context.Context
-	cancel   context.CancelFunc
+	// stateMu guards Job.State, which runners update while executing
+	stateMu sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	// clock times runs and computes next fire times
+	clock clock.Clock
 }
 
 // Config holds scheduler configuration
@@ -33,11 +41,20 @@ func NewScheduler(executor Executor, logger *slog.Logger) *Scheduler {
 	return &Scheduler{
 		jobs:     make(map[string]*Job),
 		runners:  make(map[string]*JobRunner),
+		history:  make(map[string]*runHistory),
 		executor: executor,
 		logger:   logger.With("component", "scheduler"),
+		clock:    clock.System,
 	}
 }
 
+// SetClock replaces the clock used by jobs started after the call.
+func (s *Scheduler) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
 // Start initializes and starts all enabled jobs
 func (s *Scheduler) Start(ctx context.Context) error {
 	s.mu.Lock()
@@ -53,7 +70,7 @@ func (s *Scheduler) Start(ctx context.Context) error {
 			continue
 		}
 
-		runner := NewJobRunner(job, s.executor, s.logger)
+		runner := s.newRunnerLocked(job)
 		s.runners[id] = runner
 		go runner.Start(s.ctx)
 	}
@@ -84,10 +101,12 @@ func (s *Scheduler) Stop() {
 	s.logger.Info("scheduler stopped")
 }
 
-// AddJob adds a new job to the scheduler
+// AddJob adds a new job to the scheduler. The job's schedule is validated
+// before it is activated; if the scheduler is running and the job is enabled,
+// it starts firing immediately.
 func (s *Scheduler) AddJob(job *Job) error {
-	if err := job.Validate(); err != nil {
-		return fmt.Errorf("invalid job: %w", err)
+	if err := validateSchedulable(job); err != nil {
+		return err
 	}
 
 	s.mu.Lock()
@@ -103,7 +122,7 @@ func (s *Scheduler) AddJob(job *Job) error {
 
 	// Start runner if scheduler is running and job is enabled
 	if s.ctx != nil && job.Enabled {
-		runner := NewJobRunner(job, s.executor, s.logger)
+		runner := s.newRunnerLocked(job)
 		s.runners[job.ID] = runner
 		go runner.Start(s.ctx)
 		s.logger.Info("job added and started", "job", job.ID)
@@ -132,6 +151,7 @@ func (s *Scheduler) RemoveJob(id string) error {
 
 	// Remove job
 	delete(s.jobs, id)
+	delete(s.history, id)
 	s.logger.Info("job removed", "job", id)
 
 	return nil
@@ -139,8 +159,8 @@ func (s *Scheduler) RemoveJob(id string) error {
 
 // UpdateJob updates an existing job
 func (s *Scheduler) UpdateJob(job *Job) error {
-	if err := job.Validate(); err != nil {
-		return fmt.Errorf("invalid job: %w", err)
+	if err := validateSchedulable(job); err != nil {
+		return err
 	}
 
 	s.mu.Lock()
@@ -162,7 +182,7 @@ func (s *Scheduler) UpdateJob(job *Job) error {
 
 	// Start new runner if scheduler is running and job is enabled
 	if s.ctx != nil && job.Enabled {
-		runner := NewJobRunner(job, s.executor, s.logger)
+		runner := s.newRunnerLocked(job)
 		s.runners[job.ID] = runner
 		go runner.Start(s.ctx)
 		s.logger.Info("job updated and restarted", "job", job.ID)
@@ -183,7 +203,7 @@ func (s *Scheduler) GetJob(id string) (*Job, error) {
 		return nil, fmt.Errorf("job not found: %s", id)
 	}
 
-	return job.Clone(), nil
+	return s.cloneJob(job), nil
 }
 
 // ListJobs returns all jobs
@@ -193,7 +213,7 @@ func (s *Scheduler) ListJobs() []*Job {
 
 	jobs := make([]*Job, 0, len(s.jobs))
 	for _, job := range s.jobs {
-		jobs = append(jobs, job.Clone())
+		jobs = append(jobs, s.cloneJob(job))
 	}
 
 	return jobs
@@ -201,32 +221,44 @@ func (s *Scheduler) ListJobs() []*Job {
 
 // RunJobNow triggers a job immediately (bypassing schedule)
 func (s *Scheduler) RunJobNow(id string) error {
-	s.mu.RLock()
+	s.mu.Lock()
 	job, exists := s.jobs[id]
-	s.mu.RUnlock()
+	var runner *JobRunner
+	if exists {
+		// Create temporary runner and execute once
+		runner = s.newRunnerLocked(job)
+	}
+	s.mu.Unlock()
 
 	if !exists {
 		return fmt.Errorf("job not found: %s", id)
 	}
 
-	// Create temporary runner and execute once
-	runner := NewJobRunner(job, s.executor, s.logger)
 	ctx := context.Background()
 	runner.executeJob(ctx)
 
 	return nil
 }
 
-// LoadJobs loads jobs from configuration
+// LoadJobs loads jobs from configuration. Jobs that fail validation (for
+// example an unparseable cron expression) are kept but disabled, with the
+// reason recorded in State.LastError so they show up in job listings rather
+// than silently never firing.
 func (s *Scheduler) LoadJobs(jobs []*Job) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	for _, job := range jobs {
 		if err := job.Validate(); err != nil {
-			s.logger.Warn("invalid job in config, skipping",
+			s.logger.Error("invalid job in config, disabling",
 				"job", job.ID,
 				"error", err)
+			if job.ID == "" {
+				continue
+			}
+			job.Enabled = false
+			job.State.LastError = fmt.Sprintf("invalid job: %v", err)
+			s.jobs[job.ID] = job
 			continue
 		}
 
@@ -238,6 +270,111 @@ func (s *Scheduler) LoadJobs(jobs []*Job) error {
 	return nil
 }
 
+// EnableJob activates a job. If the scheduler is running, the job starts
+// firing on its schedule immediately.
+func (s *Scheduler) EnableJob(id string) error {
+	return s.setEnabled(id, true)
+}
+
+// DisableJob deactivates a job, stopping its runner. Its definition and run
+// history are kept so it can be re-enabled later.
+func (s *Scheduler) DisableJob(id string) error {
+	return s.setEnabled(id, false)
+}
+
+func (s *Scheduler) setEnabled(id string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs[id]
+	if !exists {
+		return fmt.Errorf("job not found: %s", id)
+	}
+
+	if enabled {
+		// Jobs disabled at load time for an invalid schedule stay disabled
+		if err := validateSchedulable(job); err != nil {
+			return err
+		}
+	}
+
+	if runner, running := s.runners[id]; running {
+		runner.Stop()
+		delete(s.runners, id)
+	}
+
+	s.stateMu.Lock()
+	job.Enabled = enabled
+	if enabled {
+		job.State.LastError = ""
+	}
+	s.stateMu.Unlock()
+
+	if enabled && s.ctx != nil {
+		runner := s.newRunnerLocked(job)
+		s.runners[id] = runner
+		go runner.Start(s.ctx)
+	}
+
+	s.logger.Info("job enabled state changed", "job", id, "enabled", enabled)
+	return nil
+}
+
+// validateSchedulable checks a job's configuration and that its schedule
+// yields a next run time.
+func validateSchedulable(job *Job) error {
+	if err := job.Validate(); err != nil {
+		return fmt.Errorf("invalid job: %w", err)
+	}
+	if _, err := job.NextRun(time.Now()); err != nil {
+		return fmt.Errorf("invalid job schedule: %w", err)
+	}
+	return nil
+}
+
+// cloneJob copies a job while no runner is updating its state.
+func (s *Scheduler) cloneJob(job *Job) *Job {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	return job.Clone()
+}
+
+// JobStatus returns the recent run history and last-run outcome for a job.
+func (s *Scheduler) JobStatus(id string) (JobStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, exists := s.jobs[id]
+	if !exists {
+		return JobStatus{}, fmt.Errorf("job not found: %s", id)
+	}
+	return s.historyLocked(id).status(id, job.Enabled), nil
+}
+
+// newRunnerLocked creates a runner that records into the job's history.
+// Caller must hold s.mu for writing.
+func (s *Scheduler) newRunnerLocked(job *Job) *JobRunner {
+	runner := NewJobRunner(job, s.executor, s.logger)
+	h, ok := s.history[job.ID]
+	if !ok {
+		h = newRunHistory(DefaultRunHistorySize)
+		s.history[job.ID] = h
+	}
+	runner.history = h
+	runner.stateMu = &s.stateMu
+	runner.clock = s.clock
+	return runner
+}
+
+// historyLocked returns the job's history, or an empty one if it has never
+// had a runner. Caller must hold s.mu (read or write).
+func (s *Scheduler) historyLocked(id string) *runHistory {
+	if h, ok := s.history[id]; ok {
+		return h
+	}
+	return newRunHistory(DefaultRunHistorySize)
+}
+
 // GetStats returns scheduler statistics
 func (s *Scheduler) GetStats() map[string]interface{} {
 	s.mu.RLock()
@@ -247,6 +384,7 @@ func (s *Scheduler) GetStats() map[string]interface{} {
 	totalErrors := int64(0)
 	activeJobs := 0
 
+	s.stateMu.Lock()
 	for _, job := range s.jobs {
 		totalRuns += job.State.RunCount
 		totalErrors += job.State.ErrorCount
@@ -254,6 +392,7 @@ func (s *Scheduler) GetStats() map[string]interface{} {
 			activeJobs++
 		}
 	}
+	s.stateMu.Unlock()
 
 	return map[string]interface{}{
 		"total_jobs":   len(s.jobs),
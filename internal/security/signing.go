@@ -3,6 +3,7 @@
 package security
 
 import (
+	"bytes"
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/json"
@@ -20,6 +21,8 @@ var (
 	ErrMissingSignature = errors.New("security: missing constraint signature")
 	// ErrMissingPublicKey is returned when the owner public key is absent.
 	ErrMissingPublicKey = errors.New("security: missing owner public key")
+	// ErrUntrustedKey is returned when a genome carries a key other than the trusted owner key.
+	ErrUntrustedKey = errors.New("security: genome signed with an untrusted key")
 )
 
 // GenerateOwnerKeyPair generates a new Ed25519 key pair for signing constraints.
@@ -39,6 +42,14 @@ func SerializeConstraints(c config.GenomeConstraints) ([]byte, error) {
 		"max_loss_usd":    c.MaxLossUSD,
 		"min_vfm_score":   c.MinVFMScore,
 	}
+	// Added later, so only present when set: constraints signed before
+	// these existed still verify. Rule order is kept as written.
+	if len(c.Rules) > 0 {
+		m["rules"] = c.Rules
+	}
+	if len(c.ForbiddenPatterns) > 0 {
+		m["forbidden_patterns"] = sortedStrings(c.ForbiddenPatterns)
+	}
 	return deterministicJSON(m)
 }
 
@@ -51,6 +62,40 @@ func SignConstraints(c config.GenomeConstraints, privateKey ed25519.PrivateKey)
 	return ed25519.Sign(privateKey, msg), nil
 }
 
+// SignGenome signs the genome's constraints with the owner's private key and
+// stores the signature and matching public key on the genome.
+func SignGenome(g *config.Genome, privateKey ed25519.PrivateKey) error {
+	sig, err := SignConstraints(g.Constraints, privateKey)
+	if err != nil {
+		return err
+	}
+	g.ConstraintSignature = sig
+	g.OwnerPublicKey = privateKey.Public().(ed25519.PublicKey)
+	return nil
+}
+
+// VerifyGenome checks the genome's constraint signature against the trusted
+// owner key. The key carried by the genome is only compared with it, never
+// trusted on its own: anyone can re-sign altered constraints with a key of
+// their own. It returns ErrUntrustedKey if the keys differ and
+// ErrInvalidSignature if the constraints were altered after signing.
+func VerifyGenome(g *config.Genome, trusted ed25519.PublicKey) error {
+	if len(trusted) != ed25519.PublicKeySize {
+		return ErrMissingPublicKey
+	}
+	if !bytes.Equal(g.OwnerPublicKey, trusted) {
+		return ErrUntrustedKey
+	}
+	ok, err := VerifyConstraints(g.Constraints, g.ConstraintSignature, trusted)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
 // VerifyConstraints verifies that the signature matches the constraints and public key.
 func VerifyConstraints(c config.GenomeConstraints, signature []byte, publicKey ed25519.PublicKey) (bool, error) {
 	if len(publicKey) != ed25519.PublicKeySize {
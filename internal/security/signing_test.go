@@ -129,6 +129,23 @@ func TestDeterministicSerialization(t *testing.T) {
 	}
 }
 
+func TestSerializeConstraints_RulesOnlyWhenSet(t *testing.T) {
+	// Constraints signed before rules existed must still verify.
+	b, _ := SerializeConstraints(config.GenomeConstraints{MaxLossUSD: 100})
+	want := `{"allowed_assets":[],"blocked_actions":[],"max_divergence":0,"max_loss_usd":100,"min_vfm_score":0}`
+	if string(b) != want {
+		t.Errorf("serialized = %s, want %s", b, want)
+	}
+
+	pub, priv, _ := GenerateOwnerKeyPair()
+	c := config.GenomeConstraints{Rules: []string{"ask first"}, ForbiddenPatterns: []string{"password"}}
+	sig, _ := SignConstraints(c, priv)
+	c.Rules = []string{"never ask"}
+	if ok, _ := VerifyConstraints(c, sig, pub); ok {
+		t.Error("edited rules still verify")
+	}
+}
+
 func TestUnsignedConstraintsBackwardCompat(t *testing.T) {
 	// Unsigned constraints (empty sig + empty key) should return specific errors,
 	// allowing callers to decide whether to warn or reject.
@@ -140,3 +157,66 @@ func TestUnsignedConstraintsBackwardCompat(t *testing.T) {
 		t.Fatalf("expected ErrMissingPublicKey for nil key, got %v", err)
 	}
 }
+
+func TestSignGenome(t *testing.T) {
+	pub, priv, err := GenerateOwnerKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &config.Genome{Constraints: config.GenomeConstraints{MaxLossUSD: 250}}
+	if err := SignGenome(g, priv); err != nil {
+		t.Fatalf("SignGenome: %v", err)
+	}
+	if len(g.OwnerPublicKey) != ed25519.PublicKeySize || len(g.ConstraintSignature) == 0 {
+		t.Fatal("expected signature and public key to be set")
+	}
+	if err := VerifyGenome(g, pub); err != nil {
+		t.Fatalf("VerifyGenome: %v", err)
+	}
+
+	g.Constraints.MaxLossUSD = 1e6
+	if err := VerifyGenome(g, pub); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature for tampered genome, got %v", err)
+	}
+}
+
+func TestVerifyGenome_ResignedWithOtherKey(t *testing.T) {
+	pub, _, err := GenerateOwnerKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, attacker, err := GenerateOwnerKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Loosened constraints re-signed with the attacker's own key verify
+	// against the key they carry, but not against the owner's.
+	g := &config.Genome{Constraints: config.GenomeConstraints{MaxLossUSD: 1e6}}
+	if err := SignGenome(g, attacker); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyGenome(g, pub); err != ErrUntrustedKey {
+		t.Fatalf("expected ErrUntrustedKey, got %v", err)
+	}
+
+	// Carrying the owner's key does not help without the owner's signature.
+	g.OwnerPublicKey = pub
+	if err := VerifyGenome(g, pub); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestVerifyGenome_Unsigned(t *testing.T) {
+	pub, _, err := GenerateOwnerKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyGenome(&config.Genome{}, nil); err != ErrMissingPublicKey {
+		t.Fatalf("expected ErrMissingPublicKey without a trusted key, got %v", err)
+	}
+	if err := VerifyGenome(&config.Genome{}, pub); err != ErrUntrustedKey {
+		t.Fatalf("expected ErrUntrustedKey, got %v", err)
+	}
+}
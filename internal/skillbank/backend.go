@@ -0,0 +1,176 @@
+package skillbank
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+// Storage namespaces used by BackendStore.
+const (
+	skillsNamespace   = "skillbank/skills"
+	mistakesNamespace = "skillbank/mistakes"
+)
+
+// BackendStore persists skills and common mistakes through a pluggable
+// storage.Store, one record per ID. Use it instead of FileStore when state
+// must live outside the local data dir (e.g. a shared database).
+type BackendStore struct {
+	mu      sync.Mutex // serialises check-then-write in Add/Update/Delete
+	backend storage.Store
+}
+
+// NewBackendStore returns a skill store backed by s.
+func NewBackendStore(s storage.Store) *BackendStore {
+	return &BackendStore{backend: s}
+}
+
+// Add adds a new skill. Returns ErrDuplicateID if the ID is already present.
+func (b *BackendStore) Add(skill Skill) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.backend.Get(skillsNamespace, skill.ID); err == nil {
+		return ErrDuplicateID
+	}
+	return putJSON(b.backend, skillsNamespace, skill.ID, skill)
+}
+
+// Get returns a skill by ID. Returns ErrNotFound if absent.
+func (b *BackendStore) Get(id string) (Skill, error) {
+	var s Skill
+	if err := getJSON(b.backend, skillsNamespace, id, &s); err != nil {
+		return Skill{}, err
+	}
+	return s, nil
+}
+
+// List returns all skills. If category is non-empty, only matching skills are returned.
+func (b *BackendStore) List(category string) ([]Skill, error) {
+	ids, err := b.backend.List(skillsNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("skillbank: list skills: %w", err)
+	}
+	out := make([]Skill, 0, len(ids))
+	for _, id := range ids {
+		var s Skill
+		if err := getJSON(b.backend, skillsNamespace, id, &s); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue // deleted concurrently
+			}
+			return nil, err
+		}
+		if category == "" || s.Category == category {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// Update overwrites an existing skill. Returns ErrNotFound if absent.
+func (b *BackendStore) Update(skill Skill) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.backend.Get(skillsNamespace, skill.ID); err != nil {
+		return notFound(err)
+	}
+	return putJSON(b.backend, skillsNamespace, skill.ID, skill)
+}
+
+// Delete removes a skill by ID. Returns ErrNotFound if absent.
+func (b *BackendStore) Delete(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.backend.Get(skillsNamespace, id); err != nil {
+		return notFound(err)
+	}
+	return b.backend.Delete(skillsNamespace, id)
+}
+
+// Count returns the number of stored skills.
+func (b *BackendStore) Count() int {
+	ids, err := b.backend.List(skillsNamespace)
+	if err != nil {
+		return 0
+	}
+	return len(ids)
+}
+
+// AddMistake adds a new common mistake.
+func (b *BackendStore) AddMistake(m CommonMistake) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.backend.Get(mistakesNamespace, m.ID); err == nil {
+		return ErrDuplicateID
+	}
+	return putJSON(b.backend, mistakesNamespace, m.ID, m)
+}
+
+// ListMistakes returns common mistakes. If taskType is non-empty, only matching ones are returned.
+func (b *BackendStore) ListMistakes(taskType string) ([]CommonMistake, error) {
+	ids, err := b.backend.List(mistakesNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("skillbank: list mistakes: %w", err)
+	}
+	out := make([]CommonMistake, 0, len(ids))
+	for _, id := range ids {
+		var m CommonMistake
+		if err := getJSON(b.backend, mistakesNamespace, id, &m); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		if taskType == "" || m.TaskType == taskType {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// DeleteMistake removes a mistake by ID.
+func (b *BackendStore) DeleteMistake(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.backend.Get(mistakesNamespace, id); err != nil {
+		return notFound(err)
+	}
+	return b.backend.Delete(mistakesNamespace, id)
+}
+
+func putJSON(s storage.Store, namespace, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("skillbank: marshal %s: %w", key, err)
+	}
+	if err := s.Put(namespace, key, data); err != nil {
+		return fmt.Errorf("skillbank: %w", err)
+	}
+	return nil
+}
+
+func getJSON(s storage.Store, namespace, key string, v interface{}) error {
+	data, err := s.Get(namespace, key)
+	if err != nil {
+		return notFound(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("skillbank: unmarshal %s: %w", key, err)
+	}
+	return nil
+}
+
+// notFound maps storage.ErrNotFound to the package's ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return ErrNotFound
+	}
+	return fmt.Errorf("skillbank: %w", err)
+}
@@ -0,0 +1,103 @@
+package skillbank
+
+import (
+	"testing"
+
+	"github.com/clawinfra/evoclaw/internal/storage"
+)
+
+var _ Store = (*BackendStore)(nil)
+
+func TestBackendStore_AddGetListUpdateDelete(t *testing.T) {
+	bs := NewBackendStore(storage.NewMemoryStore())
+
+	s := makeSkill("skill-1", "general", "")
+	if err := bs.Add(s); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := bs.Add(s); err != ErrDuplicateID {
+		t.Fatalf("expected ErrDuplicateID, got %v", err)
+	}
+	if bs.Count() != 1 {
+		t.Fatalf("Count want 1, got %d", bs.Count())
+	}
+
+	got, err := bs.Get("skill-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Title != s.Title {
+		t.Errorf("Title mismatch: want %q got %q", s.Title, got.Title)
+	}
+	if _, err := bs.Get("nonexistent"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	_ = bs.Add(makeSkill("skill-2", "security", ""))
+	skills, err := bs.List("general")
+	if err != nil || len(skills) != 1 {
+		t.Fatalf("List category = %d, %v; want 1", len(skills), err)
+	}
+	skills, _ = bs.List("")
+	if len(skills) != 2 {
+		t.Fatalf("List all want 2, got %d", len(skills))
+	}
+
+	s.Title = "Updated Title"
+	if err := bs.Update(s); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, _ = bs.Get("skill-1")
+	if got.Title != "Updated Title" {
+		t.Errorf("Update not persisted: got %q", got.Title)
+	}
+	if err := bs.Update(makeSkill("missing", "general", "")); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound on Update, got %v", err)
+	}
+
+	if err := bs.Delete("skill-1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := bs.Delete("skill-1"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound on Delete, got %v", err)
+	}
+}
+
+func TestBackendStore_Mistakes(t *testing.T) {
+	bs := NewBackendStore(storage.NewMemoryStore())
+
+	if err := bs.AddMistake(makeMistake("m1", "coding")); err != nil {
+		t.Fatalf("AddMistake: %v", err)
+	}
+	if err := bs.AddMistake(makeMistake("m1", "coding")); err != ErrDuplicateID {
+		t.Fatalf("expected ErrDuplicateID, got %v", err)
+	}
+	_ = bs.AddMistake(makeMistake("m2", "trading"))
+
+	ms, err := bs.ListMistakes("coding")
+	if err != nil || len(ms) != 1 {
+		t.Fatalf("ListMistakes = %d, %v; want 1", len(ms), err)
+	}
+	if err := bs.DeleteMistake("m1"); err != nil {
+		t.Fatalf("DeleteMistake: %v", err)
+	}
+	if err := bs.DeleteMistake("m1"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestBackendStore_Persistence(t *testing.T) {
+	fs, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	_ = NewBackendStore(fs).Add(makeSkill("skill-1", "general", ""))
+
+	got, err := NewBackendStore(fs).Get("skill-1")
+	if err != nil {
+		t.Fatalf("Get after reopen: %v", err)
+	}
+	if got.ID != "skill-1" {
+		t.Errorf("ID = %q, want skill-1", got.ID)
+	}
+}
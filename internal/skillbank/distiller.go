@@ -67,6 +67,13 @@ func NewLLMDistiller(apiURL, apiKey, model string) *LLMDistiller {
 
 // Distill processes trajectories in batches of up to 10 and returns extracted skills and mistakes.
 func (d *LLMDistiller) Distill(ctx context.Context, trajectories []Trajectory) ([]Skill, []CommonMistake, error) {
+	return distillInBatches(ctx, trajectories, d.distillBatch)
+}
+
+// distillInBatches runs distill over trajectories in batches of up to
+// distillerBatchSize and concatenates the results.
+func distillInBatches(ctx context.Context, trajectories []Trajectory,
+	distill func(context.Context, []Trajectory) ([]Skill, []CommonMistake, error)) ([]Skill, []CommonMistake, error) {
 	var allSkills []Skill
 	var allMistakes []CommonMistake
 
@@ -77,7 +84,7 @@ func (d *LLMDistiller) Distill(ctx context.Context, trajectories []Trajectory) (
 		}
 		batch := trajectories[i:end]
 
-		skills, mistakes, err := d.distillBatch(ctx, batch)
+		skills, mistakes, err := distill(ctx, batch)
 		if err != nil {
 			return nil, nil, fmt.Errorf("distill batch %d-%d: %w", i, end, err)
 		}
@@ -143,6 +150,32 @@ func (d *LLMDistiller) distillBatch(ctx context.Context, batch []Trajectory) ([]
 	return parseDistillResponse(chatResp.Choices[0].Message.Content)
 }
 
+// CompleteFunc sends a system and user prompt to a model and returns its
+// reply.
+type CompleteFunc func(ctx context.Context, systemPrompt, userPrompt string) (string, error)
+
+// FuncDistiller extracts skills like LLMDistiller but sends each batch
+// through a CompleteFunc, so callers can reuse their own model providers.
+type FuncDistiller struct {
+	complete CompleteFunc
+}
+
+// NewFuncDistiller creates a distiller that calls complete once per batch.
+func NewFuncDistiller(complete CompleteFunc) *FuncDistiller {
+	return &FuncDistiller{complete: complete}
+}
+
+// Distill processes trajectories in batches of up to 10 and returns extracted skills and mistakes.
+func (d *FuncDistiller) Distill(ctx context.Context, trajectories []Trajectory) ([]Skill, []CommonMistake, error) {
+	return distillInBatches(ctx, trajectories, func(ctx context.Context, batch []Trajectory) ([]Skill, []CommonMistake, error) {
+		content, err := d.complete(ctx, distillerSystemPrompt, buildDistillPrompt(batch))
+		if err != nil {
+			return nil, nil, err
+		}
+		return parseDistillResponse(content)
+	})
+}
+
 // buildDistillPrompt serializes trajectories into a human-readable prompt.
 func buildDistillPrompt(trajectories []Trajectory) string {
 	var sb strings.Builder
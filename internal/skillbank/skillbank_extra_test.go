@@ -171,6 +171,44 @@ func TestLLMDistiller_Distill_EmptyTrajectories(t *testing.T) {
 	}
 }
 
+func TestFuncDistiller_Distill(t *testing.T) {
+	var prompts []string
+	d := NewFuncDistiller(func(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+		if systemPrompt != distillerSystemPrompt {
+			t.Errorf("unexpected system prompt %q", systemPrompt)
+		}
+		prompts = append(prompts, userPrompt)
+		return `{"skills":[{"title":"Retry","principle":"Retry timeouts once"}],"mistakes":[{"description":"gave up early"}]}`, nil
+	})
+
+	trajectories := make([]Trajectory, 12)
+	for i := range trajectories {
+		trajectories[i] = makeTrajectory("coding", false)
+	}
+	skills, mistakes, err := d.Distill(context.Background(), trajectories)
+	if err != nil {
+		t.Fatalf("Distill: %v", err)
+	}
+	if len(prompts) != 2 {
+		t.Errorf("expected 2 batches, got %d", len(prompts))
+	}
+	if len(skills) != 2 || skills[0].Source != SourceDistilled {
+		t.Errorf("skills = %+v", skills)
+	}
+	if len(mistakes) != 2 {
+		t.Errorf("mistakes = %+v", mistakes)
+	}
+}
+
+func TestFuncDistiller_Distill_CompleteError(t *testing.T) {
+	d := NewFuncDistiller(func(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+		return "", fmt.Errorf("provider down")
+	})
+	if _, _, err := d.Distill(context.Background(), []Trajectory{makeTrajectory("coding", false)}); err == nil {
+		t.Error("expected error when the model call fails")
+	}
+}
+
 func TestBuildDistillPrompt(t *testing.T) {
 	trajectories := []Trajectory{
 		{
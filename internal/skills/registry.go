@@ -34,10 +34,54 @@ func (r *Registry) Register(skill *Skill) error {
 	defer r.mu.Unlock()
 
 	name := skill.Manifest.Name
-	if _, exists := r.skills[name]; exists {
-		return fmt.Errorf("skill %q already registered", name)
+	if prev, exists := r.skills[name]; exists {
+		return fmt.Errorf("skill %q already registered from %s", name, prev.Dir)
 	}
 
+	r.addLocked(skill)
+	return nil
+}
+
+// RegisterAll validates and registers a batch of skills. Skills with an
+// invalid manifest or a name already used by a registered skill or an
+// earlier skill in the batch are not registered; every such skill is
+// reported in the returned RegistrationErrors. Valid skills are registered
+// regardless.
+func (r *Registry) RegisterAll(skills []*Skill) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	valid, errs := checkSkills(skills, r.skills)
+	for _, s := range valid {
+		r.addLocked(s)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Replace swaps the registered skills for a freshly loaded batch, as
+// RegisterAll would register them into an empty registry.
+func (r *Registry) Replace(skills []*Skill) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	valid, errs := checkSkills(skills, nil)
+	r.skills = make(map[string]*Skill, len(valid))
+	r.tools = make(map[string]*registeredTool)
+	for _, s := range valid {
+		r.addLocked(s)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// addLocked adds a skill and its tools. Caller must hold r.mu for writing.
+func (r *Registry) addLocked(skill *Skill) {
+	name := skill.Manifest.Name
 	r.skills[name] = skill
 	for toolName, tool := range skill.Tools {
 		fqn := name + "." + toolName
@@ -47,7 +91,25 @@ func (r *Registry) Register(skill *Skill) error {
 			r.tools[toolName] = &registeredTool{Skill: skill, Tool: tool}
 		}
 	}
-	return nil
+}
+
+// Unregister removes a skill and its tools. It reports whether the skill
+// was registered.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	skill, ok := r.skills[name]
+	if !ok {
+		return false
+	}
+	delete(r.skills, name)
+	for toolName, rt := range r.tools {
+		if rt.Skill == skill {
+			delete(r.tools, toolName)
+		}
+	}
+	return true
 }
 
 // GetTool looks up a tool by name (either "skill.tool" or just "tool").
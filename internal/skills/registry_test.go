@@ -85,3 +85,37 @@ func TestSetHealth(t *testing.T) {
 		t.Error("expected unhealthy")
 	}
 }
+
+func TestRegistryUnregister(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	reg := NewRegistry(logger)
+
+	skill := &Skill{
+		Manifest: SkillManifest{Name: "test-skill"},
+		Tools:    map[string]*ToolDef{"hello": {Name: "hello", Command: "echo"}},
+	}
+	if err := reg.Register(skill); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reg.Unregister("test-skill") {
+		t.Fatal("expected Unregister to report a registered skill")
+	}
+	if reg.SkillCount() != 0 {
+		t.Errorf("expected 0 skills, got %d", reg.SkillCount())
+	}
+	if _, _, err := reg.GetTool("hello"); err == nil {
+		t.Error("short tool name should be removed")
+	}
+	if _, _, err := reg.GetTool("test-skill.hello"); err == nil {
+		t.Error("qualified tool name should be removed")
+	}
+	if reg.Unregister("test-skill") {
+		t.Error("second Unregister should report false")
+	}
+
+	// The name can be registered again.
+	if err := reg.Register(skill); err != nil {
+		t.Errorf("re-register: %v", err)
+	}
+}
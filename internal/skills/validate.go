@@ -0,0 +1,164 @@
+package skills
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrDuplicateSkill is wrapped by errors for two skills sharing a name.
+var ErrDuplicateSkill = errors.New("duplicate skill name")
+
+var (
+	// skillNamePattern follows the Agent Skills naming rules: lowercase
+	// letters, digits and hyphens, starting with a letter or digit.
+	skillNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	// permissionPattern matches capability names such as "internet".
+	permissionPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+	// envNamePattern matches portable environment variable names.
+	envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ValidationError describes a single invalid manifest field.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors collects every problem found by SkillManifest.Validate.
+type ValidationErrors []*ValidationError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks the manifest for missing required fields and malformed
+// permission and env references. It returns nil or a ValidationErrors value
+// listing every problem found.
+func (m *SkillManifest) Validate() error {
+	var errs ValidationErrors
+	add := func(field, format string, args ...any) {
+		errs = append(errs, &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case m.Name == "":
+		add("name", "is required")
+	case !skillNamePattern.MatchString(m.Name):
+		add("name", "%q must contain only lowercase letters, digits and hyphens", m.Name)
+	}
+	if strings.TrimSpace(m.Description) == "" {
+		add("description", "is required")
+	}
+
+	seen := make(map[string]bool)
+	for i, perm := range m.Metadata.EvoClaw.Permissions {
+		field := fmt.Sprintf("metadata.evoclaw.permissions[%d]", i)
+		switch {
+		case !permissionPattern.MatchString(perm):
+			add(field, "invalid permission %q", perm)
+		case seen[perm]:
+			add(field, "duplicate permission %q", perm)
+		}
+		seen[perm] = true
+	}
+
+	seen = make(map[string]bool)
+	for i, env := range m.Metadata.EvoClaw.Env {
+		field := fmt.Sprintf("metadata.evoclaw.env[%d]", i)
+		switch {
+		case !envNamePattern.MatchString(env):
+			add(field, "invalid environment variable name %q", env)
+		case seen[env]:
+			add(field, "duplicate environment variable %q", env)
+		}
+		seen[env] = true
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// SkillError explains why a skill was not registered.
+type SkillError struct {
+	Name string // manifest name; empty if missing
+	Dir  string // skill directory
+	Err  error
+}
+
+func (e *SkillError) Error() string {
+	name := e.Name
+	if name == "" {
+		name = "<unnamed>"
+	}
+	if e.Dir == "" {
+		return fmt.Sprintf("skill %s: %v", name, e.Err)
+	}
+	return fmt.Sprintf("skill %s (%s): %v", name, e.Dir, e.Err)
+}
+
+func (e *SkillError) Unwrap() error { return e.Err }
+
+// RegistrationErrors lists every skill rejected by Registry.RegisterAll or
+// Registry.Replace.
+type RegistrationErrors []*SkillError
+
+func (r RegistrationErrors) Error() string {
+	msgs := make([]string, len(r))
+	for i, e := range r {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap lets errors.Is and errors.As match individual skill errors.
+func (r RegistrationErrors) Unwrap() []error {
+	errs := make([]error, len(r))
+	for i, e := range r {
+		errs[i] = e
+	}
+	return errs
+}
+
+// checkSkills validates a batch of skills against each other and against
+// already registered skills. It returns the skills that may be registered
+// and an error for each one that may not. Within the batch the first skill
+// with a given name wins.
+func checkSkills(batch []*Skill, registered map[string]*Skill) ([]*Skill, RegistrationErrors) {
+	var valid []*Skill
+	var errs RegistrationErrors
+	taken := make(map[string]*Skill, len(registered)+len(batch))
+	for name, s := range registered {
+		taken[name] = s
+	}
+
+	for _, s := range batch {
+		name := s.Manifest.Name
+		if err := s.Manifest.Validate(); err != nil {
+			errs = append(errs, &SkillError{Name: name, Dir: s.Dir, Err: err})
+			continue
+		}
+		if prev, ok := taken[name]; ok {
+			errs = append(errs, &SkillError{
+				Name: name,
+				Dir:  s.Dir,
+				Err:  fmt.Errorf("%w %q: defined in %s and %s", ErrDuplicateSkill, name, prev.Dir, s.Dir),
+			})
+			continue
+		}
+		taken[name] = s
+		valid = append(valid, s)
+	}
+	return valid, errs
+}
@@ -0,0 +1,132 @@
+package skills
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func validManifest() SkillManifest {
+	return SkillManifest{
+		Name:        "market-monitor",
+		Version:     "1.0.0",
+		Description: "Real-time market monitoring",
+		Metadata: Metadata{EvoClaw: EvoclawMeta{
+			Permissions: []string{"internet", "filesystem"},
+			Env:         []string{"MARKET_API_KEY"},
+		}},
+	}
+}
+
+func TestManifestValidate(t *testing.T) {
+	m := validManifest()
+	if err := m.Validate(); err != nil {
+		t.Fatalf("valid manifest rejected: %v", err)
+	}
+
+	m = SkillManifest{
+		Name: "Bad Name",
+		Metadata: Metadata{EvoClaw: EvoclawMeta{
+			Permissions: []string{"internet", "internet", ""},
+			Env:         []string{"1BAD"},
+		}},
+	}
+	err := m.Validate()
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	fields := make(map[string]bool)
+	for _, e := range verrs {
+		fields[e.Field] = true
+	}
+	for _, want := range []string{
+		"name",
+		"description",
+		"metadata.evoclaw.permissions[1]",
+		"metadata.evoclaw.permissions[2]",
+		"metadata.evoclaw.env[0]",
+	} {
+		if !fields[want] {
+			t.Errorf("expected error for %s, got %v", want, err)
+		}
+	}
+
+	m = SkillManifest{}
+	if err := m.Validate(); err == nil || !strings.Contains(err.Error(), "name: is required") {
+		t.Errorf("expected missing name error, got %v", err)
+	}
+}
+
+func TestRegisterAll(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	reg := NewRegistry(logger)
+
+	good := &Skill{Manifest: validManifest(), Dir: "/skills/a"}
+	dup := &Skill{Manifest: validManifest(), Dir: "/skills/b"}
+	missing := &Skill{Manifest: SkillManifest{Version: "1.0.0"}, Dir: "/skills/c"}
+
+	err := reg.RegisterAll([]*Skill{good, dup, missing})
+	var regErrs RegistrationErrors
+	if !errors.As(err, &regErrs) {
+		t.Fatalf("expected RegistrationErrors, got %v", err)
+	}
+	if len(regErrs) != 2 {
+		t.Fatalf("expected 2 rejected skills, got %d: %v", len(regErrs), err)
+	}
+
+	if !errors.Is(regErrs[0], ErrDuplicateSkill) {
+		t.Errorf("expected duplicate error, got %v", regErrs[0])
+	}
+	for _, dir := range []string{"/skills/a", "/skills/b"} {
+		if !strings.Contains(regErrs[0].Error(), dir) {
+			t.Errorf("duplicate error should name %s: %v", dir, regErrs[0])
+		}
+	}
+	if regErrs[1].Dir != "/skills/c" || !strings.Contains(regErrs[1].Error(), "name: is required") {
+		t.Errorf("expected missing name error for /skills/c, got %v", regErrs[1])
+	}
+
+	// The valid skill is registered regardless.
+	if reg.SkillCount() != 1 || reg.ListSkills()[0] != good {
+		t.Errorf("expected only the first valid skill registered, got %d", reg.SkillCount())
+	}
+
+	// Names already in the registry count as duplicates too.
+	if err := reg.RegisterAll([]*Skill{dup}); !errors.Is(err, ErrDuplicateSkill) {
+		t.Errorf("expected duplicate error against registry, got %v", err)
+	}
+
+	other := validManifest()
+	other.Name = "other"
+	if err := reg.RegisterAll([]*Skill{{Manifest: other}}); err != nil {
+		t.Errorf("RegisterAll(valid) = %v", err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	reg := NewRegistry(logger)
+
+	old := validManifest()
+	old.Name = "old"
+	if err := reg.RegisterAll([]*Skill{{Manifest: old}}); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := &Skill{
+		Manifest: validManifest(),
+		Tools:    map[string]*ToolDef{"check": {Command: "check.sh"}},
+	}
+	if err := reg.Replace([]*Skill{edited}); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	if reg.SkillCount() != 1 {
+		t.Errorf("expected old skill dropped, got %d skills", reg.SkillCount())
+	}
+	if _, _, err := reg.GetTool("market-monitor.check"); err != nil {
+		t.Errorf("replaced skill's tool missing: %v", err)
+	}
+}
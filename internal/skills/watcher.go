@@ -0,0 +1,148 @@
+package skills
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Default timings for Watcher.
+const (
+	DefaultPollInterval = time.Second
+	DefaultDebounce     = 500 * time.Millisecond
+)
+
+// Watcher reloads skills when files under the skills directory change.
+// Changes are detected by polling file modification times and sizes; a
+// burst of edits triggers a single reload once the directory has been quiet
+// for the debounce period.
+type Watcher struct {
+	loader   *Loader
+	registry *Registry
+	logger   *slog.Logger
+
+	// PollInterval is how often the directory is scanned.
+	PollInterval time.Duration
+	// Debounce is how long the directory must stay unchanged before reloading.
+	Debounce time.Duration
+
+	// last is the directory state the registry was last synced with.
+	last map[string]fileStamp
+}
+
+// fileStamp identifies one version of a file for change detection.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher creates a watcher that reloads skills found by loader into
+// registry. The directory is scanned immediately, so create it right after
+// the initial LoadAll: any change from then on triggers a reload.
+func NewWatcher(loader *Loader, registry *Registry, logger *slog.Logger) *Watcher {
+	w := &Watcher{
+		loader:       loader,
+		registry:     registry,
+		logger:       logger.With("component", "skill-watcher"),
+		PollInterval: DefaultPollInterval,
+		Debounce:     DefaultDebounce,
+	}
+	w.last = w.snapshot()
+	return w
+}
+
+// Run watches the skills directory until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	w.logger.Info("watching skills for changes", "dir", w.loader.skillsDir)
+
+	var changedAt time.Time
+	pending := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if current := w.snapshot(); !sameSnapshot(w.last, current) {
+				w.last = current
+				changedAt = now
+				pending = true
+				continue
+			}
+			if pending && now.Sub(changedAt) >= w.Debounce {
+				pending = false
+				w.Reload()
+			}
+		}
+	}
+}
+
+// Reload loads every skill from disk and replaces the registered skills
+// with them: new and edited skills are (re-)registered and skills no longer
+// on disk, or no longer valid, are unregistered. Errors are logged; the
+// registry keeps its current skills if the directory cannot be read.
+func (w *Watcher) Reload() {
+	loaded, err := w.loader.LoadAll()
+	if err != nil {
+		w.logger.Error("skill reload failed", "error", err)
+		return
+	}
+
+	before := w.registry.ListSkills()
+	if err := w.registry.Replace(loaded); err != nil {
+		w.logger.Error("skills rejected", "error", err)
+	}
+	current := make(map[string]bool)
+	for _, s := range w.registry.ListSkills() {
+		current[s.Manifest.Name] = true
+	}
+	for _, s := range before {
+		if !current[s.Manifest.Name] {
+			w.logger.Info("skill removed", "name", s.Manifest.Name)
+		}
+	}
+
+	w.logger.Info("skills reloaded", "count", w.registry.SkillCount())
+}
+
+// snapshot records every file under the skills directory. A missing
+// directory yields an empty snapshot.
+func (w *Watcher) snapshot() map[string]fileStamp {
+	snap := make(map[string]fileStamp)
+	_ = filepath.WalkDir(w.loader.skillsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		snap[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return snap
+}
+
+func sameSnapshot(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		if other, ok := b[path]; !ok || !other.modTime.Equal(stamp.modTime) || other.size != stamp.size {
+			return false
+		}
+	}
+	return true
+}
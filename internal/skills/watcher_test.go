@@ -0,0 +1,125 @@
+package skills
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestSkill(t *testing.T, dir, name, command string) {
+	t.Helper()
+	skillDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "---\nname: " + name + "\nversion: 1.0.0\ndescription: test skill\n---\n"
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tools := "[tools.run]\ncommand = \"" + command + "\"\n"
+	if err := os.WriteFile(filepath.Join(skillDir, "agent.toml"), []byte(tools), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func startTestWatcher(t *testing.T, dir string) *Registry {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	reg := NewRegistry(logger)
+	w := NewWatcher(NewLoader(dir, logger), reg, logger)
+	w.PollInterval = 10 * time.Millisecond
+	w.Debounce = 30 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return reg
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatcherRegistersNewSkill(t *testing.T) {
+	dir := t.TempDir()
+	reg := startTestWatcher(t, dir)
+
+	writeTestSkill(t, dir, "fresh", "echo")
+	waitFor(t, "new skill to register", func() bool {
+		_, _, err := reg.GetTool("fresh.run")
+		return err == nil
+	})
+
+	// Edits replace the registered skill.
+	writeTestSkill(t, dir, "fresh", "printf")
+	waitFor(t, "edited skill to reload", func() bool {
+		tool, _, err := reg.GetTool("fresh.run")
+		return err == nil && tool.Command == "printf"
+	})
+
+	// Removed skills are unregistered.
+	if err := os.RemoveAll(filepath.Join(dir, "fresh")); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "removed skill to unregister", func() bool {
+		return reg.SkillCount() == 0
+	})
+}
+
+func TestWatcherReloadKeepsSkillsOnError(t *testing.T) {
+	dir := t.TempDir()
+	writeTestSkill(t, dir, "kept", "echo")
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	reg := NewRegistry(logger)
+	w := NewWatcher(NewLoader(dir, logger), reg, logger)
+	w.Reload()
+	if reg.SkillCount() != 1 {
+		t.Fatalf("expected 1 skill, got %d", reg.SkillCount())
+	}
+
+	// A skills path that is not a directory makes LoadAll fail.
+	file := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.loader.skillsDir = file
+	w.Reload()
+	if reg.SkillCount() != 1 {
+		t.Errorf("failed reload should keep existing skills, got %d", reg.SkillCount())
+	}
+}
+
+func TestSameSnapshot(t *testing.T) {
+	now := time.Now()
+	a := map[string]fileStamp{"x": {modTime: now, size: 1}}
+	if !sameSnapshot(a, map[string]fileStamp{"x": {modTime: now, size: 1}}) {
+		t.Error("identical snapshots should match")
+	}
+	if sameSnapshot(a, map[string]fileStamp{"x": {modTime: now, size: 2}}) {
+		t.Error("size change should be detected")
+	}
+	if sameSnapshot(a, map[string]fileStamp{"y": {modTime: now, size: 1}}) {
+		t.Error("renamed file should be detected")
+	}
+	if sameSnapshot(a, map[string]fileStamp{}) {
+		t.Error("removed file should be detected")
+	}
+}
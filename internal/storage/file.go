@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileExt is appended to every key on disk. Stored values are JSON in
+// practice, and keeping the extension preserves the historical data layout.
+const fileExt = ".json"
+
+// FileStore stores each value as <root>/<namespace>/<key>.json. It is the
+// default backend and reads data directories written before the Store
+// interface existed.
+type FileStore struct {
+	root string
+}
+
+// NewFileStore returns a filesystem store rooted at dir, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("storage: create root dir: %w", err)
+	}
+	return &FileStore{root: dir}, nil
+}
+
+// Root returns the directory the store writes to.
+func (s *FileStore) Root() string {
+	return s.root
+}
+
+// Get reads the value stored under key.
+func (s *FileStore) Get(namespace, key string) ([]byte, error) {
+	if err := validate(namespace, key); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(s.path(namespace, key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("storage: read %s/%s: %w", namespace, key, err)
+	}
+	return data, nil
+}
+
+// Put writes value atomically via a temp file and rename.
+func (s *FileStore) Put(namespace, key string, value []byte) error {
+	if err := validate(namespace, key); err != nil {
+		return err
+	}
+	dir := s.dir(namespace)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("storage: create namespace dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("storage: write %s/%s: %w", namespace, key, err)
+	}
+	tmpName := tmp.Name()
+	_, writeErr := tmp.Write(value)
+	closeErr := tmp.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		writeErr = os.Chmod(tmpName, 0640)
+	}
+	if writeErr == nil {
+		writeErr = os.Rename(tmpName, s.path(namespace, key))
+	}
+	if writeErr != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("storage: write %s/%s: %w", namespace, key, writeErr)
+	}
+	return nil
+}
+
+// List returns the keys of the .json files directly inside the namespace
+// directory. Subdirectories (nested namespaces) and temp files are skipped.
+func (s *FileStore) List(namespace string) ([]string, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(s.dir(namespace))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("storage: list %s: %w", namespace, err)
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != fileExt {
+			continue
+		}
+		keys = append(keys, strings.TrimSuffix(name, fileExt))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Delete removes the file for key, if present.
+func (s *FileStore) Delete(namespace, key string) error {
+	if err := validate(namespace, key); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(namespace, key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("storage: delete %s/%s: %w", namespace, key, err)
+	}
+	return nil
+}
+
+// Namespaces returns the subdirectories of the parent namespace directory.
+func (s *FileStore) Namespaces(parent string) ([]string, error) {
+	if err := validateNamespace(parent); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(s.dir(parent))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("storage: list namespaces in %s: %w", parent, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DeleteNamespace removes the namespace directory and everything below it,
+// including files not written through the store.
+func (s *FileStore) DeleteNamespace(namespace string) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(s.dir(namespace)); err != nil {
+		return fmt.Errorf("storage: delete namespace %s: %w", namespace, err)
+	}
+	return nil
+}
+
+func (s *FileStore) dir(namespace string) string {
+	return filepath.Join(s.root, filepath.FromSlash(namespace))
+}
+
+func (s *FileStore) path(namespace, key string) string {
+	return filepath.Join(s.dir(namespace), key+fileExt)
+}
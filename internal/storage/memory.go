@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStore keeps values in memory. It is intended for tests and for
+// ephemeral deployments where nothing should touch the disk.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[string]map[string][]byte // namespace -> key -> value
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string]map[string][]byte)}
+}
+
+// Get returns a copy of the value stored under key.
+func (s *MemoryStore) Get(namespace, key string) ([]byte, error) {
+	if err := validate(namespace, key); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, ok := s.data[namespace][key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), v...), nil
+}
+
+// Put stores a copy of value under key.
+func (s *MemoryStore) Put(namespace, key string, value []byte) error {
+	if err := validate(namespace, key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ns, ok := s.data[namespace]
+	if !ok {
+		ns = make(map[string][]byte)
+		s.data[namespace] = ns
+	}
+	ns[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// List returns the sorted keys in namespace.
+func (s *MemoryStore) List(namespace string) ([]string, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ns := s.data[namespace]
+	if len(ns) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(ns))
+	for k := range ns {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Delete removes key from namespace.
+func (s *MemoryStore) Delete(namespace, key string) error {
+	if err := validate(namespace, key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data[namespace], key)
+	return nil
+}
+
+// Namespaces returns the sorted names of the non-empty namespaces nested
+// directly below parent.
+func (s *MemoryStore) Namespaces(parent string) ([]string, error) {
+	if err := validateNamespace(parent); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for ns, keys := range s.data {
+		rest, ok := strings.CutPrefix(ns, parent+"/")
+		if !ok || len(keys) == 0 {
+			continue
+		}
+		name, _, _ := strings.Cut(rest, "/")
+		seen[name] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DeleteNamespace removes namespace and every namespace nested below it.
+func (s *MemoryStore) DeleteNamespace(namespace string) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ns := range s.data {
+		if ns == namespace || strings.HasPrefix(ns, namespace+"/") {
+			delete(s.data, ns)
+		}
+	}
+	return nil
+}
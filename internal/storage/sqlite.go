@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// SQLiteFile is the database file name used under the data dir.
+const SQLiteFile = "evoclaw.db"
+
+// sqliteMigrations are applied in order; PRAGMA user_version records how
+// many have run. Append new migrations, never edit existing ones.
+var sqliteMigrations = []string{
+	`CREATE TABLE records (
+		namespace  TEXT    NOT NULL,
+		key        TEXT    NOT NULL,
+		value      BLOB    NOT NULL,
+		updated_at INTEGER NOT NULL,
+		PRIMARY KEY (namespace, key)
+	) WITHOUT ROWID`,
+}
+
+// SQLiteStore keeps every record in a single SQLite database. Writes run in
+// transactions with WAL journaling and synchronous=FULL, so a power loss
+// leaves either the old or the new value, never a half-written one.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens (or creates) the database at path and applies pending
+// schema migrations.
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("storage: create db dir: %w", err)
+	}
+
+	// Pragmas go in the DSN so every pooled connection gets them.
+	q := url.Values{}
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "synchronous(FULL)")
+	q.Set("_txlock", "immediate")
+	db, err := sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("storage: open sqlite: %w", err)
+	}
+
+	s := &SQLiteStore{db: db}
+	if err := s.migrate(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("storage: migrate sqlite: %w", err)
+	}
+	return s, nil
+}
+
+// Close closes the underlying database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// migrate applies each migration after the recorded user_version in its own
+// transaction, bumping user_version in the same transaction.
+func (s *SQLiteStore) migrate() error {
+	var version int
+	if err := s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	if version > len(sqliteMigrations) {
+		return fmt.Errorf("database schema version %d is newer than supported %d", version, len(sqliteMigrations))
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, i+1)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Get returns the value stored under key.
+func (s *SQLiteStore) Get(namespace, key string) ([]byte, error) {
+	if err := validate(namespace, key); err != nil {
+		return nil, err
+	}
+	var value []byte
+	err := s.db.QueryRow(`SELECT value FROM records WHERE namespace = ? AND key = ?`, namespace, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("storage: read %s/%s: %w", namespace, key, err)
+	}
+	return value, nil
+}
+
+// Put inserts or replaces the value under key in a single transaction.
+func (s *SQLiteStore) Put(namespace, key string, value []byte) error {
+	if err := validate(namespace, key); err != nil {
+		return err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return s.inTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO records (namespace, key, value, updated_at) VALUES (?, ?, ?, ?)
+			ON CONFLICT (namespace, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+			namespace, key, value, time.Now().UnixNano())
+		if err != nil {
+			return fmt.Errorf("storage: write %s/%s: %w", namespace, key, err)
+		}
+		return nil
+	})
+}
+
+// List returns the sorted keys in namespace. Nested namespaces are not
+// included, matching FileStore.
+func (s *SQLiteStore) List(namespace string) ([]string, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query(`SELECT key FROM records WHERE namespace = ? ORDER BY key`, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list %s: %w", namespace, err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("storage: list %s: %w", namespace, err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: list %s: %w", namespace, err)
+	}
+	return keys, nil
+}
+
+// Delete removes key in a single transaction.
+func (s *SQLiteStore) Delete(namespace, key string) error {
+	if err := validate(namespace, key); err != nil {
+		return err
+	}
+	return s.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM records WHERE namespace = ? AND key = ?`, namespace, key); err != nil {
+			return fmt.Errorf("storage: delete %s/%s: %w", namespace, key, err)
+		}
+		return nil
+	})
+}
+
+// Namespaces returns the sorted names of the namespaces nested directly
+// below parent.
+func (s *SQLiteStore) Namespaces(parent string) ([]string, error) {
+	if err := validateNamespace(parent); err != nil {
+		return nil, err
+	}
+	prefix := parent + "/"
+	rows, err := s.db.Query(`SELECT DISTINCT namespace FROM records WHERE substr(namespace, 1, ?) = ?`, len(prefix), prefix)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list namespaces in %s: %w", parent, err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var ns string
+		if err := rows.Scan(&ns); err != nil {
+			return nil, fmt.Errorf("storage: list namespaces in %s: %w", parent, err)
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(ns, prefix), "/")
+		seen[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: list namespaces in %s: %w", parent, err)
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DeleteNamespace removes namespace and every namespace nested below it in
+// a single transaction.
+func (s *SQLiteStore) DeleteNamespace(namespace string) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+	prefix := namespace + "/"
+	return s.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM records WHERE namespace = ? OR substr(namespace, 1, ?) = ?`,
+			namespace, len(prefix), prefix); err != nil {
+			return fmt.Errorf("storage: delete namespace %s: %w", namespace, err)
+		}
+		return nil
+	})
+}
+
+// inTx runs fn in a transaction, committing only if fn succeeds.
+func (s *SQLiteStore) inTx(fn func(*sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("storage: begin: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("storage: commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func newTestSQLite(t *testing.T, path string) *SQLiteStore {
+	t.Helper()
+	s, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("OpenSQLite: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestSQLiteStore(t *testing.T) {
+	testStore(t, func(t *testing.T) Store {
+		return newTestSQLite(t, filepath.Join(t.TempDir(), SQLiteFile))
+	})
+}
+
+func TestSQLiteStore_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SQLiteFile)
+	s, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("OpenSQLite: %v", err)
+	}
+	if err := s.Put("agents", "a1", []byte("v1")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	_ = s.Close()
+
+	s2 := newTestSQLite(t, path)
+	got, err := s2.Get("agents", "a1")
+	if err != nil || string(got) != "v1" {
+		t.Errorf("Get after reopen = %q, %v; want v1", got, err)
+	}
+}
+
+func TestSQLiteStore_Migrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SQLiteFile)
+	s := newTestSQLite(t, path)
+
+	var version int
+	if err := s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		t.Fatalf("user_version: %v", err)
+	}
+	if version != len(sqliteMigrations) {
+		t.Errorf("user_version = %d, want %d", version, len(sqliteMigrations))
+	}
+
+	// Reopening must not re-run migrations (CREATE TABLE would fail).
+	newTestSQLite(t, path)
+
+	// A database from a newer release is refused rather than misread.
+	if _, err := s.db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, len(sqliteMigrations)+1)); err != nil {
+		t.Fatalf("set user_version: %v", err)
+	}
+	if _, err := OpenSQLite(path); err == nil {
+		t.Error("expected error opening a database with a newer schema")
+	}
+}
+
+// A transaction that fails part-way leaves the previous value untouched.
+func TestSQLiteStore_PartialWriteRollsBack(t *testing.T) {
+	s := newTestSQLite(t, filepath.Join(t.TempDir(), SQLiteFile))
+	_ = s.Put("agents", "a1", []byte("committed"))
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`UPDATE records SET value = ? WHERE namespace = 'agents' AND key = 'a1'`, []byte("half")); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO records VALUES ('agents', 'a2', 'x', 0)`); err != nil {
+			return err
+		}
+		return errors.New("simulated power loss")
+	})
+	if err == nil {
+		t.Fatal("expected inTx to return the failure")
+	}
+
+	got, _ := s.Get("agents", "a1")
+	if string(got) != "committed" {
+		t.Errorf("a1 = %q, want committed", got)
+	}
+	if _, err := s.Get("agents", "a2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("a2 should not exist after rollback, got %v", err)
+	}
+}
+
+// A process that dies with an open transaction never commits it.
+func TestSQLiteStore_UncommittedWriteLostOnCrash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SQLiteFile)
+	s, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("OpenSQLite: %v", err)
+	}
+	_ = s.Put("agents", "a1", []byte("committed"))
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if _, err := tx.Exec(`UPDATE records SET value = 'torn' WHERE key = 'a1'`); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	// Simulate the crash: the connection goes away without a commit.
+	_ = s.db.Close()
+
+	s2 := newTestSQLite(t, path)
+	got, err := s2.Get("agents", "a1")
+	if err != nil || string(got) != "committed" {
+		t.Errorf("a1 = %q, %v; want committed", got, err)
+	}
+}
+
+// A torn temp file from an interrupted FileStore write is ignored.
+func TestFileStore_PartialWriteIgnored(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := NewFileStore(dir)
+	_ = s.Put("agents", "a1", []byte(`{"id":"a1"}`))
+
+	_ = os.WriteFile(filepath.Join(dir, "agents", ".tmp-crash"), []byte(`{"id":"a1","na`), 0640)
+
+	got, err := s.Get("agents", "a1")
+	if err != nil || string(got) != `{"id":"a1"}` {
+		t.Errorf("Get = %q, %v; want the committed value", got, err)
+	}
+	keys, _ := s.List("agents")
+	if len(keys) != 1 {
+		t.Errorf("List = %v, want [a1]", keys)
+	}
+}
+
+// Two handles on the same file (e.g. two processes) see each other's writes.
+func TestSQLiteStore_ConcurrentHandles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SQLiteFile)
+	a := newTestSQLite(t, path)
+	b := newTestSQLite(t, path)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s := a
+			if i%2 == 1 {
+				s = b
+			}
+			key := fmt.Sprintf("k%02d", i)
+			if err := s.Put("agents", key, []byte(key)); err != nil {
+				t.Errorf("Put %s: %v", key, err)
+			}
+			if _, err := s.Get("agents", key); err != nil {
+				t.Errorf("Get %s: %v", key, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, s := range []*SQLiteStore{a, b} {
+		keys, err := s.List("agents")
+		if err != nil || len(keys) != 50 {
+			t.Errorf("List = %d keys, %v; want 50", len(keys), err)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, backend := range []string{"", BackendFile} {
+		s, err := Open(backend, dir)
+		if err != nil {
+			t.Fatalf("Open(%q): %v", backend, err)
+		}
+		if _, ok := s.(*FileStore); !ok {
+			t.Errorf("Open(%q) = %T, want *FileStore", backend, s)
+		}
+	}
+
+	s, err := Open(BackendSQLite, dir)
+	if err != nil {
+		t.Fatalf("Open(sqlite): %v", err)
+	}
+	sq, ok := s.(*SQLiteStore)
+	if !ok {
+		t.Fatalf("Open(sqlite) = %T, want *SQLiteStore", s)
+	}
+	_ = sq.Close()
+	if _, err := os.Stat(filepath.Join(dir, SQLiteFile)); err != nil {
+		t.Errorf("expected database file: %v", err)
+	}
+
+	if _, err := Open("postgres", dir); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+}
@@ -0,0 +1,97 @@
+// Package storage defines the pluggable persistence backend used for agent,
+// strategy, genome and skill records. Records are opaque byte values
+// addressed by a namespace and a key, so the same data can live in plain
+// files on disk (the default), in memory for tests, or in a database.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned by Get when no value is stored under the key.
+var ErrNotFound = errors.New("storage: not found")
+
+// Store persists values by namespace and key. Namespaces may be nested with
+// "/" (e.g. "evolution/genomes/agent-1"); keys may not contain "/".
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value stored under key, or ErrNotFound.
+	Get(namespace, key string) ([]byte, error)
+	// Put stores value under key, replacing any existing value.
+	Put(namespace, key string, value []byte) error
+	// List returns the keys stored directly in namespace, sorted. A missing
+	// namespace is empty, not an error.
+	List(namespace string) ([]string, error)
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(namespace, key string) error
+	// Namespaces returns the names of the namespaces nested directly below
+	// parent, sorted. A missing parent has none, not an error.
+	Namespaces(parent string) ([]string, error)
+	// DeleteNamespace removes every key in namespace and in the namespaces
+	// nested below it. Deleting a missing namespace is not an error.
+	DeleteNamespace(namespace string) error
+}
+
+// AgentsNamespace holds agent records. With per-agent data directories
+// (server.agentDataDirs) everything persisted for an agent lives in nested
+// namespaces below AgentsNamespace/<id>.
+const AgentsNamespace = "agents"
+
+// AgentNamespace returns the namespace for an agent's own state, nested
+// below AgentsNamespace: AgentNamespace("a1", "evolution") is
+// "agents/a1/evolution". With no parts it is the agent's root namespace.
+func AgentNamespace(agentID string, parts ...string) string {
+	return strings.Join(append([]string{AgentsNamespace, agentID}, parts...), "/")
+}
+
+// validate rejects namespaces and keys that could escape the store root or
+// collide with another record.
+func validate(namespace, key string) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+	if key == "" {
+		return fmt.Errorf("storage: empty key")
+	}
+	if strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return fmt.Errorf("storage: invalid key %q", key)
+	}
+	return nil
+}
+
+func validateNamespace(namespace string) error {
+	if namespace == "" {
+		return fmt.Errorf("storage: empty namespace")
+	}
+	if strings.Contains(namespace, `\`) {
+		return fmt.Errorf("storage: invalid namespace %q", namespace)
+	}
+	for _, part := range strings.Split(namespace, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("storage: invalid namespace %q", namespace)
+		}
+	}
+	return nil
+}
+
+// Backend names accepted by Open (config: server.storage).
+const (
+	BackendFile   = "file"
+	BackendSQLite = "sqlite"
+)
+
+// Open returns the store selected by backend, rooted at dataDir. An empty
+// backend selects the file store.
+func Open(backend, dataDir string) (Store, error) {
+	switch backend {
+	case "", BackendFile:
+		return NewFileStore(dataDir)
+	case BackendSQLite:
+		return OpenSQLite(filepath.Join(dataDir, SQLiteFile))
+	default:
+		return nil, fmt.Errorf("storage: unknown backend %q (want file or sqlite)", backend)
+	}
+}
@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// testStore runs the behaviour every Store implementation must share.
+func testStore(t *testing.T, newStore func(t *testing.T) Store) {
+	t.Run("GetMissing", func(t *testing.T) {
+		s := newStore(t)
+		if _, err := s.Get("agents", "nope"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get missing = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("PutGet", func(t *testing.T) {
+		s := newStore(t)
+		if err := s.Put("agents", "a1", []byte(`{"id":"a1"}`)); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		got, err := s.Get("agents", "a1")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if string(got) != `{"id":"a1"}` {
+			t.Errorf("Get = %s", got)
+		}
+	})
+
+	t.Run("PutOverwrites", func(t *testing.T) {
+		s := newStore(t)
+		_ = s.Put("agents", "a1", []byte("old"))
+		if err := s.Put("agents", "a1", []byte("new")); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		got, _ := s.Get("agents", "a1")
+		if string(got) != "new" {
+			t.Errorf("Get = %s, want new", got)
+		}
+	})
+
+	t.Run("ValueIsCopied", func(t *testing.T) {
+		s := newStore(t)
+		v := []byte("abc")
+		_ = s.Put("agents", "a1", v)
+		v[0] = 'x'
+		got, _ := s.Get("agents", "a1")
+		got[1] = 'y'
+		again, _ := s.Get("agents", "a1")
+		if string(again) != "abc" {
+			t.Errorf("stored value was mutated: %s", again)
+		}
+	})
+
+	t.Run("ListSortedAndScoped", func(t *testing.T) {
+		s := newStore(t)
+		for _, k := range []string{"c", "a", "b"} {
+			if err := s.Put("agents", k, []byte(k)); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+		}
+		_ = s.Put("other", "z", []byte("z"))
+		_ = s.Put("agents/nested", "n", []byte("n"))
+
+		keys, err := s.List("agents")
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+			t.Errorf("List = %v, want [a b c]", keys)
+		}
+
+		nested, _ := s.List("agents/nested")
+		if !reflect.DeepEqual(nested, []string{"n"}) {
+			t.Errorf("List nested = %v, want [n]", nested)
+		}
+	})
+
+	t.Run("ListMissingNamespace", func(t *testing.T) {
+		s := newStore(t)
+		keys, err := s.List("empty")
+		if err != nil || len(keys) != 0 {
+			t.Errorf("List = %v, %v; want empty, nil", keys, err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		s := newStore(t)
+		_ = s.Put("agents", "a1", []byte("x"))
+		if err := s.Delete("agents", "a1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := s.Get("agents", "a1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+		}
+		if err := s.Delete("agents", "a1"); err != nil {
+			t.Errorf("Delete missing = %v, want nil", err)
+		}
+	})
+
+	t.Run("Namespaces", func(t *testing.T) {
+		s := newStore(t)
+		_ = s.Put("agents", "a0", []byte("x"))
+		_ = s.Put("agents/b/evolution", "k", []byte("x"))
+		_ = s.Put("agents/a", "k", []byte("x"))
+		_ = s.Put("agents/a/evolution/genomes", "v1", []byte("x"))
+		_ = s.Put("agentsx/c", "k", []byte("x"))
+		got, err := s.Namespaces("agents")
+		if err != nil {
+			t.Fatalf("Namespaces: %v", err)
+		}
+		if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Namespaces = %v, want %v", got, want)
+		}
+		if got, err := s.Namespaces("missing"); err != nil || len(got) != 0 {
+			t.Errorf("Namespaces missing = %v, %v; want empty, nil", got, err)
+		}
+	})
+
+	t.Run("DeleteNamespace", func(t *testing.T) {
+		s := newStore(t)
+		_ = s.Put("agents/a", "k", []byte("x"))
+		_ = s.Put("agents/a/evolution", "k", []byte("x"))
+		_ = s.Put("agents/ab", "k", []byte("x"))
+		if err := s.DeleteNamespace("agents/a"); err != nil {
+			t.Fatalf("DeleteNamespace: %v", err)
+		}
+		for _, ns := range []string{"agents/a", "agents/a/evolution"} {
+			if _, err := s.Get(ns, "k"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get(%s) after DeleteNamespace = %v, want ErrNotFound", ns, err)
+			}
+		}
+		if _, err := s.Get("agents/ab", "k"); err != nil {
+			t.Errorf("sibling namespace deleted: %v", err)
+		}
+		if err := s.DeleteNamespace("agents/a"); err != nil {
+			t.Errorf("DeleteNamespace missing = %v, want nil", err)
+		}
+	})
+
+	t.Run("InvalidNames", func(t *testing.T) {
+		s := newStore(t)
+		cases := []struct{ ns, key string }{
+			{"", "k"},
+			{"agents", ""},
+			{"agents", "../escape"},
+			{"agents", ".."},
+			{"../escape", "k"},
+			{"agents//x", "k"},
+			{"/abs", "k"},
+		}
+		for _, c := range cases {
+			if err := s.Put(c.ns, c.key, []byte("x")); err == nil {
+				t.Errorf("Put(%q, %q) should fail", c.ns, c.key)
+			}
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := newStore(t)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				key := fmt.Sprintf("k%02d", i)
+				if err := s.Put("agents", key, []byte(key)); err != nil {
+					t.Errorf("Put: %v", err)
+				}
+				_, _ = s.List("agents")
+			}(i)
+		}
+		wg.Wait()
+		keys, _ := s.List("agents")
+		if len(keys) != 20 {
+			t.Errorf("List = %d keys, want 20", len(keys))
+		}
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	testStore(t, func(t *testing.T) Store {
+		s, err := NewFileStore(t.TempDir())
+		if err != nil {
+			t.Fatalf("NewFileStore: %v", err)
+		}
+		return s
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, func(t *testing.T) Store {
+		return NewMemoryStore()
+	})
+}
+
+func TestFileStoreLayout(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	if err := s.Put("evolution/genomes/a1", "v1", []byte("{}")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evolution", "genomes", "a1", "v1.json")); err != nil {
+		t.Errorf("expected file at namespace path: %v", err)
+	}
+}
+
+func TestFileStoreListIgnoresForeignFiles(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := NewFileStore(dir)
+	ns := filepath.Join(dir, "agents")
+	_ = os.MkdirAll(ns, 0750)
+	_ = os.WriteFile(filepath.Join(ns, "a1.json"), []byte("{}"), 0640)
+	_ = os.WriteFile(filepath.Join(ns, "notes.txt"), []byte("x"), 0640)
+	_ = os.WriteFile(filepath.Join(ns, ".tmp-123"), []byte("x"), 0640)
+
+	keys, err := s.List("agents")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"a1"}) {
+		t.Errorf("List = %v, want [a1]", keys)
+	}
+}
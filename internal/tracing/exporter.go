@@ -0,0 +1,237 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryExporter keeps finished spans in memory. It is meant for tests.
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// NewMemoryExporter returns an empty in-memory exporter.
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+// ExportSpan implements Exporter.
+func (e *MemoryExporter) ExportSpan(s SpanData) {
+	e.mu.Lock()
+	e.spans = append(e.spans, s)
+	e.mu.Unlock()
+}
+
+// Shutdown implements Exporter.
+func (e *MemoryExporter) Shutdown(context.Context) error { return nil }
+
+// Spans returns the finished spans in the order they ended.
+func (e *MemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]SpanData, len(e.spans))
+	copy(out, e.spans)
+	return out
+}
+
+// Reset discards all recorded spans.
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	e.spans = nil
+	e.mu.Unlock()
+}
+
+// OTLP batching defaults.
+const (
+	otlpBatchSize     = 256
+	otlpQueueSize     = 2048
+	otlpFlushInterval = 5 * time.Second
+)
+
+// OTLPExporter batches spans and posts them to an OTLP/HTTP collector using
+// the JSON encoding. Spans are dropped, not blocked on, if the queue fills.
+type OTLPExporter struct {
+	url     string
+	service string
+	client  *http.Client
+	logger  *slog.Logger
+
+	queue chan SpanData
+	done  chan struct{}
+	once  sync.Once
+	wg    sync.WaitGroup
+}
+
+// NewOTLPExporter starts an exporter posting to endpoint (e.g.
+// "http://localhost:4318"; "/v1/traces" is appended if missing), tagging
+// spans with service as service.name.
+func NewOTLPExporter(endpoint, service string, logger *slog.Logger) *OTLPExporter {
+	url := strings.TrimRight(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/traces") {
+		url += "/v1/traces"
+	}
+	e := &OTLPExporter{
+		url:     url,
+		service: service,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		logger:  logger,
+		queue:   make(chan SpanData, otlpQueueSize),
+		done:    make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e
+}
+
+// ExportSpan implements Exporter.
+func (e *OTLPExporter) ExportSpan(s SpanData) {
+	select {
+	case e.queue <- s:
+	default:
+		e.logger.Debug("trace export queue full, span dropped", "span", s.Name)
+	}
+}
+
+// Shutdown flushes queued spans and stops the exporter.
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.once.Do(func() { close(e.done) })
+	finished := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *OTLPExporter) run() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+
+	var batch []SpanData
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.post(batch); err != nil {
+			e.logger.Warn("trace export failed", "spans", len(batch), "error", err)
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case s := <-e.queue:
+			batch = append(batch, s)
+			if len(batch) >= otlpBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.done:
+			for {
+				select {
+				case s := <-e.queue:
+					batch = append(batch, s)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *OTLPExporter) post(batch []SpanData) error {
+	body, err := json.Marshal(otlpRequest(e.service, batch))
+	if err != nil {
+		return fmt.Errorf("marshal spans: %w", err)
+	}
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// otlpRequest builds an OTLP ExportTraceServiceRequest in its JSON form.
+func otlpRequest(service string, batch []SpanData) map[string]any {
+	spans := make([]map[string]any, len(batch))
+	for i, s := range batch {
+		span := map[string]any{
+			"traceId":           s.TraceID,
+			"spanId":            s.SpanID,
+			"name":              s.Name,
+			"kind":              1, // SPAN_KIND_INTERNAL
+			"startTimeUnixNano": strconv.FormatInt(s.Start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(s.End.UnixNano(), 10),
+			"attributes":        otlpAttributes(s.Attributes),
+		}
+		if s.ParentID != "" {
+			span["parentSpanId"] = s.ParentID
+		}
+		if s.Error != "" {
+			span["status"] = map[string]any{"code": 2, "message": s.Error} // STATUS_CODE_ERROR
+		}
+		spans[i] = span
+	}
+	return map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource": map[string]any{
+				"attributes": otlpAttributes(map[string]any{"service.name": service}),
+			},
+			"scopeSpans": []any{map[string]any{
+				"scope": map[string]any{"name": "github.com/clawinfra/evoclaw"},
+				"spans": spans,
+			}},
+		}},
+	}
+}
+
+func otlpAttributes(attrs map[string]any) []map[string]any {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]map[string]any, 0, len(keys))
+	for _, k := range keys {
+		var v map[string]any
+		switch val := attrs[k].(type) {
+		case string:
+			v = map[string]any{"stringValue": val}
+		case bool:
+			v = map[string]any{"boolValue": val}
+		case int:
+			v = map[string]any{"intValue": strconv.Itoa(val)}
+		case int64:
+			v = map[string]any{"intValue": strconv.FormatInt(val, 10)}
+		case float64:
+			v = map[string]any{"doubleValue": val}
+		default:
+			v = map[string]any{"stringValue": fmt.Sprint(val)}
+		}
+		out = append(out, map[string]any{"key": k, "value": v})
+	}
+	return out
+}
@@ -0,0 +1,219 @@
+// Package tracing provides lightweight OpenTelemetry-compatible tracing.
+//
+// Spans carry W3C trace context (the traceparent header) so traces continue
+// across HTTP provider calls and MQTT edge agents, and are exported to any
+// OTLP/HTTP collector. A nil *Tracer and nil *Span are valid and do nothing,
+// so call sites need no checks when tracing is disabled.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HeaderName is the W3C trace context header, also used as the metadata key
+// on messages and MQTT payloads.
+const HeaderName = "traceparent"
+
+// SpanContext identifies a span within a trace.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+}
+
+// IsValid reports whether both IDs are non-zero.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// Traceparent formats sc as a W3C traceparent value (always sampled).
+func (sc SpanContext) Traceparent() string {
+	return "00-" + hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-01"
+}
+
+// ParseTraceparent parses a W3C traceparent value.
+func ParseTraceparent(s string) (SpanContext, bool) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return sc, false
+	}
+	return sc, sc.IsValid()
+}
+
+type contextKey struct{}
+
+// ContextWithSpanContext returns ctx carrying sc as the current span.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, sc)
+}
+
+// SpanContextFromContext returns the current span in ctx, if any.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(contextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// Traceparent returns the traceparent value for the span in ctx, or "".
+func Traceparent(ctx context.Context) string {
+	if sc, ok := SpanContextFromContext(ctx); ok {
+		return sc.Traceparent()
+	}
+	return ""
+}
+
+// Inject sets the traceparent header from the span in ctx, if any.
+func Inject(ctx context.Context, h http.Header) {
+	if tp := Traceparent(ctx); tp != "" {
+		h.Set(HeaderName, tp)
+	}
+}
+
+// Extract returns ctx with the remote parent described by traceparent, or
+// ctx unchanged if traceparent is empty or malformed.
+func Extract(ctx context.Context, traceparent string) context.Context {
+	if traceparent == "" {
+		return ctx
+	}
+	if sc, ok := ParseTraceparent(traceparent); ok {
+		return ContextWithSpanContext(ctx, sc)
+	}
+	return ctx
+}
+
+// SpanData is a finished span as handed to an Exporter. IDs are lowercase
+// hex; ParentID is empty for root spans.
+type SpanData struct {
+	Name       string
+	TraceID    string
+	SpanID     string
+	ParentID   string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]any
+	Error      string
+}
+
+// Exporter receives finished spans. ExportSpan must not block.
+type Exporter interface {
+	ExportSpan(SpanData)
+	Shutdown(ctx context.Context) error
+}
+
+// Tracer creates spans and hands them to its exporter when they end.
+type Tracer struct {
+	exporter Exporter
+}
+
+// New returns a tracer exporting to exp.
+func New(exp Exporter) *Tracer {
+	return &Tracer{exporter: exp}
+}
+
+// Shutdown flushes the exporter. It is a no-op on a nil tracer.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	return t.exporter.Shutdown(ctx)
+}
+
+// Start begins a span named name, a child of the span in ctx if there is
+// one, and returns a context carrying it. On a nil tracer it returns ctx and
+// a nil span.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	sc := SpanContext{}
+	var parentID string
+	if parent, ok := SpanContextFromContext(ctx); ok {
+		sc.TraceID = parent.TraceID
+		parentID = hex.EncodeToString(parent.SpanID[:])
+	} else {
+		_, _ = rand.Read(sc.TraceID[:])
+	}
+	_, _ = rand.Read(sc.SpanID[:])
+
+	s := &Span{
+		tracer: t,
+		sc:     sc,
+		data: SpanData{
+			Name:     name,
+			TraceID:  hex.EncodeToString(sc.TraceID[:]),
+			SpanID:   hex.EncodeToString(sc.SpanID[:]),
+			ParentID: parentID,
+			Start:    time.Now(),
+		},
+	}
+	return ContextWithSpanContext(ctx, sc), s
+}
+
+// Span is an in-progress operation. All methods are safe on a nil span.
+type Span struct {
+	tracer *Tracer
+	sc     SpanContext
+	mu     sync.Mutex
+	data   SpanData
+	ended  bool
+}
+
+// SpanContext returns the span's identity.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// SetAttr records an attribute. Strings, bools, ints and floats are
+// exported with their type; anything else as its string form.
+func (s *Span) SetAttr(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]any)
+	}
+	s.data.Attributes[key] = value
+}
+
+// RecordError marks the span as failed. A nil err is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.data.Error = err.Error()
+	s.mu.Unlock()
+}
+
+// End finishes the span and exports it. Later calls are ignored.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+	s.tracer.exporter.ExportSpan(data)
+}
@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, ok := ParseTraceparent(tp)
+	if !ok {
+		t.Fatal("valid traceparent rejected")
+	}
+	if got := sc.Traceparent(); got != tp {
+		t.Errorf("Traceparent() = %s, want %s", got, tp)
+	}
+
+	for _, bad := range []string{
+		"",
+		"garbage",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-zzf067aa0ba902b7-01",
+	} {
+		if _, ok := ParseTraceparent(bad); ok {
+			t.Errorf("ParseTraceparent(%q) accepted", bad)
+		}
+	}
+}
+
+func TestNilTracerIsNoop(t *testing.T) {
+	var tr *Tracer
+	ctx, span := tr.Start(context.Background(), "x")
+	if span != nil || Traceparent(ctx) != "" {
+		t.Fatal("nil tracer created a span")
+	}
+	span.SetAttr("k", "v")
+	span.RecordError(errors.New("boom"))
+	span.End()
+	if err := tr.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+}
+
+func TestSpanHierarchyAndPropagation(t *testing.T) {
+	exp := NewMemoryExporter()
+	tr := New(exp)
+
+	ctx, parent := tr.Start(context.Background(), "parent")
+	h := http.Header{}
+	Inject(ctx, h)
+	if h.Get(HeaderName) != parent.SpanContext().Traceparent() {
+		t.Fatalf("injected %q", h.Get(HeaderName))
+	}
+
+	// A remote service continues the trace from the header.
+	remote := Extract(context.Background(), h.Get(HeaderName))
+	_, child := tr.Start(remote, "child")
+	child.SetAttr("n", 1)
+	child.RecordError(errors.New("boom"))
+	child.End()
+	child.End() // ignored
+	parent.End()
+
+	spans := exp.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	c, p := spans[0], spans[1]
+	if c.TraceID != p.TraceID || c.ParentID != p.SpanID || p.ParentID != "" {
+		t.Errorf("child %+v not under parent %+v", c, p)
+	}
+	if c.Error != "boom" || c.Attributes["n"] != 1 || c.End.Before(c.Start) {
+		t.Errorf("child = %+v", c)
+	}
+
+	if Extract(context.Background(), "garbage") != context.Background() {
+		t.Error("malformed traceparent changed the context")
+	}
+}
+
+func TestOTLPExporterPostsJSON(t *testing.T) {
+	bodies := make(chan map[string]any, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(data, &body)
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	exp := NewOTLPExporter(srv.URL, "evoclaw-test", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	tr := New(exp)
+	ctx, parent := tr.Start(context.Background(), "message")
+	_, child := tr.Start(ctx, "llm.call")
+	child.SetAttr("llm.model", "mock/m")
+	child.SetAttr("llm.tokens_input", 12)
+	child.RecordError(errors.New("rate limited"))
+	child.End()
+	parent.End()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := tr.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	var body map[string]any
+	select {
+	case body = <-bodies:
+	case <-time.After(2 * time.Second):
+		t.Fatal("collector received nothing")
+	}
+
+	rs := body["resourceSpans"].([]any)[0].(map[string]any)
+	svc := rs["resource"].(map[string]any)["attributes"].([]any)[0].(map[string]any)
+	if svc["key"] != "service.name" || svc["value"].(map[string]any)["stringValue"] != "evoclaw-test" {
+		t.Errorf("resource attribute = %v", svc)
+	}
+	spans := rs["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	call := spans[0].(map[string]any)
+	if call["name"] != "llm.call" || call["parentSpanId"] != spans[1].(map[string]any)["spanId"] {
+		t.Errorf("llm.call span = %v", call)
+	}
+	if status := call["status"].(map[string]any); status["code"] != float64(2) || status["message"] != "rate limited" {
+		t.Errorf("status = %v", status)
+	}
+	attrs := call["attributes"].([]any)
+	if len(attrs) != 2 || attrs[0].(map[string]any)["key"] != "llm.model" ||
+		attrs[1].(map[string]any)["value"].(map[string]any)["intValue"] != "12" {
+		t.Errorf("attributes = %v", attrs)
+	}
+}
@@ -2,7 +2,10 @@
 // to avoid import cycles between channels and orchestrator.
 package types
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Button represents an inline keyboard button for Telegram
 type Button struct {
@@ -39,6 +42,8 @@ type Response struct {
 	MessageID string
 	Model     string
 	Metadata  map[string]string
+	// Usage reports what producing the response took; nil when unknown.
+	Usage *Usage
 
 	// Telegram-specific fields
 	Buttons       [][]Button // inline keyboard rows (nil = no keyboard)
@@ -46,6 +51,26 @@ type Response struct {
 	ReplyToID     int64      // if >0, send as a reply to this message ID
 }
 
+// Usage is the token count, cost and model latency of one response.
+type Usage struct {
+	TokensInput  int     `json:"tokens_input"`
+	TokensOutput int     `json:"tokens_output"`
+	CostUSD      float64 `json:"cost_usd"`
+	// LatencyMs is the time spent waiting for the model, including tool
+	// calls it made.
+	LatencyMs int64 `json:"latency_ms"`
+}
+
+// String renders u as a short status line, e.g.
+// "100 in · 50 out · $0.0011 · 830ms". Cost is left out for free models.
+func (u Usage) String() string {
+	s := fmt.Sprintf("%d in · %d out", u.TokensInput, u.TokensOutput)
+	if u.CostUSD > 0 {
+		s += fmt.Sprintf(" · $%.4f", u.CostUSD)
+	}
+	return s + " · " + (time.Duration(u.LatencyMs) * time.Millisecond).String()
+}
+
 // ToolResult represents the result of a tool execution from an edge agent
 type ToolResult struct {
 	Tool      string `json:"tool"`